@@ -0,0 +1,161 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Gituser143/cryptgo/pkg/display/activity"
+	"github.com/Gituser143/cryptgo/pkg/exchange"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+)
+
+// accountCmd represents the account command
+var accountCmd = &cobra.Command{
+	Use:   "account",
+	Short: "Connect exchange accounts with read only API keys",
+	Long: `The account command connects exchange accounts (` + strings.Join(exchange.Names(), ", ") + `)
+with read only API keys, so their balances are shown on the portfolio page,
+valued in the selected currency. Create keys with view or read permissions
+only, as these keys are never used to trade or withdraw. Keys used to place
+orders are connected separately with the trade command. Keys are kept
+encrypted in ~/.config/cryptgo/exchanges.json, with the encryption key in
+the OS keyring. Without a keyring, the encryption key is derived from a
+passphrase set in CRYPTGO_PASSPHRASE`,
+}
+
+// accountConnectCmd represents the account connect command
+var accountConnectCmd = &cobra.Command{
+	Use:   "connect <exchange>",
+	Short: "Connect an exchange account, reading its API key and secret from stdin",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Read credentials from stdin, so they aren't kept in shell history
+		stdin := bufio.NewReader(os.Stdin)
+		fmt.Fprintf(os.Stderr, "API key for %s: ", args[0])
+		key, _ := stdin.ReadString('\n')
+		fmt.Fprintf(os.Stderr, "API secret for %s: ", args[0])
+		secret, _ := stdin.ReadString('\n')
+
+		creds := exchange.Credentials{
+			Key:    strings.TrimSpace(key),
+			Secret: strings.TrimSpace(secret),
+		}
+		if err := exchange.Connect(args[0], creds); err != nil {
+			return err
+		}
+		fmt.Printf("Connected the %s account\n", args[0])
+		return nil
+	},
+}
+
+// accountDisconnectCmd represents the account disconnect command
+var accountDisconnectCmd = &cobra.Command{
+	Use:   "disconnect <exchange>",
+	Short: "Remove the API key of an exchange account",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exchange.Disconnect(args[0])
+	},
+}
+
+// accountActivityCmd represents the account activity command
+var accountActivityCmd = &cobra.Command{
+	Use:   "activity",
+	Short: "Track open orders and recent fills of connected exchange accounts",
+	Long: `The activity command opens a page listing open orders and recent fills of
+connected exchange accounts, fetched every minute. Fills can be added to
+trades imported with portfolio import, once confirmed, building holdings
+with cost basis. Coinbase accounts don't serve fills to read only keys`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		connected, err := exchange.Connected()
+		if err != nil {
+			return err
+		}
+		if len(connected) == 0 {
+			return fmt.Errorf("no exchange accounts are connected, connect one with: cryptgo account connect <exchange>")
+		}
+
+		// Context and errgroup used to manage routines
+		eg, ctx := errgroup.WithContext(context.Background())
+		dataChannel := make(chan exchange.Activity)
+
+		// Fetch open orders and fills of connected accounts
+		eg.Go(func() error {
+			return exchange.GetActivityData(ctx, dataChannel)
+		})
+
+		// Display UI for activity
+		eg.Go(func() error {
+			return activity.DisplayActivity(ctx, dataChannel, historicalPrice())
+		})
+
+		if err := eg.Wait(); err != nil {
+			if err.Error() != "UI Closed" {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+// accountBalancesCmd represents the account balances command
+var accountBalancesCmd = &cobra.Command{
+	Use:   "balances",
+	Short: "Print balances of connected exchange accounts",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		connected, err := exchange.Connected()
+		cobra.CheckErr(err)
+		if len(connected) == 0 {
+			fmt.Println("No exchange accounts are connected")
+			return
+		}
+
+		holdings := exchange.GetBalances(context.Background())
+		for _, name := range connected {
+			if err, ok := holdings.Errors[name]; ok {
+				fmt.Printf("%s: %v\n", name, err)
+				continue
+			}
+
+			balances := holdings.Balances[name]
+			symbols := []string{}
+			for symbol := range balances {
+				symbols = append(symbols, symbol)
+			}
+			sort.Strings(symbols)
+
+			fmt.Printf("%s:\n", name)
+			for _, symbol := range symbols {
+				fmt.Printf("  %s %f\n", symbol, balances[symbol])
+			}
+		}
+	},
+}
+
+func init() {
+	accountCmd.AddCommand(accountConnectCmd, accountDisconnectCmd, accountBalancesCmd, accountActivityCmd)
+	rootCmd.AddCommand(accountCmd)
+}
@@ -0,0 +1,100 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// apikeyCmd represents the apikey command
+var apikeyCmd = &cobra.Command{
+	Use:   "apikey",
+	Short: "Store API keys of data providers",
+	Long: `The apikey command stores API keys of data providers, Eg: a CoinGecko demo
+key or a CryptoPanic token, in the OS keyring (Keychain on macOS, Secret Service on Linux and the
+Credential Manager on Windows). Without a keyring, keys are kept in
+~/.cryptgo-keys.json, readable only by you. The store is set by
+apikeys.store in the config file`,
+}
+
+// apikeySetCmd represents the apikey set command
+var apikeySetCmd = &cobra.Command{
+	Use:   "set <provider>",
+	Short: "Store the API key of a provider, read from stdin",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Read the key from stdin, so it isn't kept in shell history
+		fmt.Fprintf(os.Stderr, "API key for %s: ", args[0])
+		key, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return fmt.Errorf("no API key given: %v", err)
+		}
+
+		store, err := utils.SetAPIKey(args[0], key)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Stored the %s API key in the %s\n", args[0], storeName(store))
+		return nil
+	},
+}
+
+// apikeyDeleteCmd represents the apikey delete command
+var apikeyDeleteCmd = &cobra.Command{
+	Use:   "delete <provider>",
+	Short: "Remove the API key of a provider",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return utils.DeleteAPIKey(args[0])
+	},
+}
+
+// apikeyStatusCmd represents the apikey status command
+var apikeyStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "List providers and where their API keys are stored",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, provider := range utils.APIKeyProviders {
+			status := "not set"
+			if _, store := utils.GetAPIKey(provider); store != "" {
+				status = "stored in the " + storeName(store)
+			}
+			fmt.Printf("%s: %s\n", provider, status)
+		}
+	},
+}
+
+// storeName describes a store of API keys
+func storeName(store string) string {
+	if store == utils.KeyStoreFile {
+		return "key file"
+	}
+	return "OS keyring"
+}
+
+func init() {
+	apikeyCmd.AddCommand(apikeySetCmd, apikeyDeleteCmd, apikeyStatusCmd)
+	rootCmd.AddCommand(apikeyCmd)
+}
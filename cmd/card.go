@@ -0,0 +1,63 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+var cardOutput string
+
+// cardCmd represents the card command
+var cardCmd = &cobra.Command{
+	Use:   "card <coin>",
+	Short: "Write a JSON card of a coin",
+	Long: `The card command writes a versioned JSON snapshot of a coin (price, changes,
+market cap, 7 day sparkline and links) for use by other tools and bots. The
+coin is given by its CoinGecko ID or symbol, Eg: bitcoin or BTC`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeCoins,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		card, err := api.GetCoinCard(args[0])
+		if err != nil {
+			return err
+		}
+
+		data, err := json.MarshalIndent(card, "", "  ")
+		if err != nil {
+			return err
+		}
+		data = append(data, '\n')
+
+		// Write to stdout if no output file is given
+		if cardOutput == "" || cardOutput == "-" {
+			_, err = os.Stdout.Write(data)
+			return err
+		}
+
+		return os.WriteFile(cardOutput, data, 0644)
+	},
+}
+
+func init() {
+	cardCmd.Flags().StringVarP(&cardOutput, "output", "o", "", "file to write the card to (default is stdout)")
+	rootCmd.AddCommand(cardCmd)
+}
@@ -0,0 +1,160 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Gituser143/cryptgo/pkg/alerts"
+	"github.com/Gituser143/cryptgo/pkg/api"
+	"github.com/spf13/cobra"
+	geckoTypes "github.com/superoo7/go-gecko/v3/types"
+)
+
+var (
+	checkJSON bool
+	checkNew  bool
+)
+
+// metAlert is an alert printed by the check-alerts command. Prices are in
+// USD.
+type metAlert struct {
+	Coin         string  `json:"coin"`
+	Symbol       string  `json:"symbol"`
+	Kind         string  `json:"kind"`
+	Value        float64 `json:"value"`
+	PriceUSD     float64 `json:"price_usd"`
+	Change24hPct float64 `json:"change_24h_pct"`
+	Message      string  `json:"message"`
+}
+
+// alertCoins returns market data of coins alerts are set on, whatever their
+// rank, along with the top coins if a template applies to every coin. Top
+// coins already fetched can be passed as top, otherwise they are fetched if
+// needed.
+func alertCoins(top geckoTypes.CoinsMarket) (geckoTypes.CoinsMarket, error) {
+	ids, all := alerts.Coins()
+
+	coins, err := api.GetCoinsByID(ids)
+	if err != nil || !all {
+		return coins, err
+	}
+
+	if top == nil {
+		top, err = api.GetSource().GetTopCoins(maxDaemonCoins)
+		if err != nil {
+			return nil, err
+		}
+	}
+	seen := map[string]bool{}
+	for _, coin := range coins {
+		seen[coin.ID] = true
+	}
+	for _, coin := range top {
+		if !seen[coin.ID] {
+			coins = append(coins, coin)
+		}
+	}
+
+	return coins, nil
+}
+
+// checkAlertsCmd represents the check-alerts command
+var checkAlertsCmd = &cobra.Command{
+	Use:   "check-alerts",
+	Short: "Check price alerts once and exit",
+	Long: `The check-alerts command checks price alerts and alert templates once
+against current prices, printing the alerts met and sending them to the
+desktop if alerts.notify is set, to Telegram if alerts.telegram.chat is
+set, and to a webhook if alerts.webhook.url is set. Nothing is printed if
+no alert is met, or an empty list with --json, so it can be run from cron
+without keeping the daemon or UI running. It exits with a non-zero status
+if alerts met can't be sent.
+
+With --new, alerts are only reported when their condition starts to hold,
+as on the main page, remembering those met between runs.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		coins, err := alertCoins(nil)
+		if err != nil {
+			return err
+		}
+
+		if checkNew {
+			alerts.LoadActive()
+		}
+
+		met := []metAlert{}
+		triggered := []alerts.Triggered{}
+		for _, coin := range coins {
+			check := alerts.Met
+			if checkNew {
+				check = alerts.Check
+			}
+			for _, t := range check(coin.ID, coin.Symbol, coin.CurrentPrice, coin.PriceChangePercentage24h) {
+				triggered = append(triggered, t)
+				met = append(met, metAlert{
+					Coin:         t.Coin,
+					Symbol:       t.Symbol,
+					Kind:         t.Kind,
+					Value:        t.Value,
+					PriceUSD:     t.Price,
+					Change24hPct: t.Change,
+					Message:      t.Message(),
+				})
+			}
+		}
+
+		if checkNew {
+			if err := alerts.SaveActive(); err != nil {
+				return err
+			}
+		}
+
+		messages := []string{}
+		for _, alert := range met {
+			messages = append(messages, alert.Message)
+		}
+		// Alerts met are printed even if they can't be sent
+		var notifyErr error
+		if len(messages) > 0 {
+			notifyErr = alerts.Notify("cryptgo", strings.Join(messages, " | "), triggered...)
+		}
+
+		if checkJSON {
+			data, err := json.MarshalIndent(met, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+		} else {
+			for _, message := range messages {
+				fmt.Println(message)
+			}
+		}
+
+		return notifyErr
+	},
+}
+
+func init() {
+	checkAlertsCmd.Flags().BoolVar(&checkJSON, "json", false, "print as JSON")
+	checkAlertsCmd.Flags().BoolVar(&checkNew, "new", false, "only report alerts which weren't met on the last check with --new")
+	rootCmd.AddCommand(checkAlertsCmd)
+}
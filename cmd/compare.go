@@ -0,0 +1,99 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+	"github.com/Gituser143/cryptgo/pkg/display/compare"
+	ui "github.com/gizak/termui/v3"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+)
+
+var compareInterval string
+
+// compareCmd represents the compare command
+var compareCmd = &cobra.Command{
+	Use:   "compare <coin> <coin>...",
+	Short: "Compare price history of 2 to 5 coins",
+	Long: `The compare command plots the change in price of 2 to 5 coins over an
+interval on a single graph, along with their 24h stats. Coins are given by
+their symbol, CoinGecko ID or CoinCap ID, Eg: cryptgo compare BTC ETH SOL`,
+	Args:              cobra.RangeArgs(api.MinCompareCoins, api.MaxCompareCoins),
+	ValidArgsFunction: completeCoins,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Check interval before fetching anything
+		interval := api.DefaultInterval
+		if compareInterval != "" {
+			if _, err := api.HistoryDays(compareInterval); err != nil {
+				return fmt.Errorf("invalid interval, expected one of 24hr, 7d, 14d, 30d, 90d, 180d, 1yr, 5yr: %v", err)
+			}
+			interval = compareInterval
+		}
+
+		// Get IDs of coins
+		coinIDMap := api.NewCoinIDMap()
+		coinIDMap.Populate()
+		ids := []api.CoinID{}
+		for _, coin := range args {
+			ids = append(ids, coinIDMap.Find(coin))
+		}
+
+		// Initialise UI
+		if err := ui.Init(); err != nil {
+			return fmt.Errorf("failed to initialise termui: %v", err)
+		}
+		defer ui.Close()
+
+		// Context and errgroup used to manage routines
+		eg, ctx := errgroup.WithContext(context.Background())
+		dataChannel := make(chan api.CompareData)
+		intervalChannel := make(chan string, 1)
+		intervalChannel <- interval
+
+		// Fetch histories and stats of coins
+		policy := api.GetRefreshPolicy(api.PriorityNormal)
+		eg.Go(func() error {
+			return api.GetCompareData(ctx, api.GetSource(), ids, policy.HistoryInterval, intervalChannel, dataChannel)
+		})
+
+		// Refresh FX rates of currencies
+		eg.Go(func() error {
+			return api.RefreshFXRates(ctx)
+		})
+
+		// Display UI for comparison
+		eg.Go(func() error {
+			return compare.DisplayCompare(ctx, interval, intervalChannel, dataChannel, ui.PollEvents())
+		})
+
+		if err := eg.Wait(); err != nil {
+			if err.Error() != "UI Closed" {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(compareCmd)
+	compareCmd.Flags().StringVarP(&compareInterval, "interval", "i", "", "interval of history, one of 24hr, 7d, 14d, 30d, 90d, 180d, 1yr, 5yr (default is coin.interval from the config file)")
+}
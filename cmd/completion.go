@@ -0,0 +1,70 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+// completionCmd represents the completion command
+var completionCmd = &cobra.Command{
+	Use:   "completion <bash|zsh|fish|powershell>",
+	Short: "Print a shell completion script",
+	Long: `The completion command prints a script completing commands, flags and coins
+for a shell. Coins are completed from the index of top coins bundled with
+cryptgo, so completion works without a network. Eg, for bash:
+
+  source <(cryptgo completion bash)`,
+	Args:      cobra.ExactValidArgs(1),
+	ValidArgs: []string{"bash", "zsh", "fish", "powershell"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch args[0] {
+		case "bash":
+			return rootCmd.GenBashCompletion(os.Stdout)
+		case "zsh":
+			return rootCmd.GenZshCompletion(os.Stdout)
+		case "fish":
+			return rootCmd.GenFishCompletion(os.Stdout, true)
+		default:
+			return rootCmd.GenPowerShellCompletion(os.Stdout)
+		}
+	},
+}
+
+// completeCoins completes coin arguments with IDs of coins of the bundled
+// index, described by their name and symbol
+func completeCoins(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	coins, _ := api.BundledIndex()
+	toComplete = strings.ToLower(toComplete)
+
+	completions := []string{}
+	for _, coin := range coins {
+		if strings.HasPrefix(coin.ID, toComplete) || strings.HasPrefix(strings.ToLower(coin.Symbol), toComplete) {
+			completions = append(completions, fmt.Sprintf("%s\t%s (%s)", coin.ID, coin.Name, coin.Symbol))
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+}
@@ -0,0 +1,571 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/alerts"
+	"github.com/Gituser143/cryptgo/pkg/api"
+	"github.com/Gituser143/cryptgo/pkg/display/layout"
+	"github.com/Gituser143/cryptgo/pkg/display/theme"
+	"github.com/Gituser143/cryptgo/pkg/exchange"
+	"github.com/Gituser143/cryptgo/pkg/keys"
+	"github.com/Gituser143/cryptgo/pkg/utils"
+	"github.com/Gituser143/cryptgo/pkg/widgets"
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/spf13/viper"
+)
+
+// config holds settings read from the config file and flags, validated so
+// they can be applied all at once
+type config struct {
+	changeStyle  widgets.ChangeStyle
+	theme        string
+	maxFPS       int
+	statsOutput  string
+	exportFormat string
+	exportPNG    bool
+
+	marketsRefresh time.Duration
+
+	spikeGuard     bool
+	spikeThreshold float64
+	spikeWindow    int
+	spikeLog       string
+
+	powerMode   string
+	powerFactor int
+	powerFPS    int
+
+	session    utils.TradingSession
+	favourites []string
+	currency   string
+
+	historyRefresh    time.Duration
+	detailsRefresh    time.Duration
+	uiRefresh         time.Duration
+	assetsRefresh     time.Duration
+	favouritesRefresh time.Duration
+	idleTimeout       time.Duration
+	confirmQuit       bool
+	coinInterval      string
+
+	granularity    string
+	historyPoints  int
+	historyWorkers int
+	staleAfter     time.Duration
+	fxRefresh      time.Duration
+
+	aliases         map[string]string
+	watchlistDir    string
+	namedWatchlists map[string][]string
+	savedQueries    []api.ScreenerQuery
+
+	keyStore     string
+	storeBackend string
+
+	cacheEnabled  bool
+	cacheDir      string
+	rateLimits    map[string]int
+	adaptiveQuota bool
+
+	smallWidth  int
+	smallHeight int
+	layouts     map[string]map[string][]string // Widgets hidden by profile and page
+	bindings    map[string]map[string][]string // Keys bound by page and action
+
+	desktopNotifications bool
+	telegramChat         string
+	webhookURL           string
+	priceAlerts          []alerts.Alert
+	templates            []alerts.Template
+
+	tradingEnabled bool
+	dryRun         bool
+
+	daemonInterval time.Duration
+	scanMovers     bool
+	moverThreshold float64
+	moverTop       int
+
+	source string
+}
+
+// contains returns true if list holds s
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// parseConfig reads and validates settings of the config file and flags,
+// without applying any of them
+func parseConfig() (*config, error) {
+	c := &config{}
+
+	// Colouring of changes
+	c.changeStyle = widgets.ChangeStyle{
+		Flat:      viper.GetFloat64("change.flat"),
+		Gradient:  viper.GetBool("change.gradient"),
+		FullScale: viper.GetFloat64("change.fullscale"),
+	}
+
+	// Colour theme
+	c.theme = viper.GetString("theme")
+	if !contains(theme.Names, c.theme) {
+		return nil, fmt.Errorf("invalid theme %q, expected one of %s", c.theme, strings.Join(theme.Names, ", "))
+	}
+
+	// Render throttle and output of quick stats
+	c.maxFPS = viper.GetInt("render.fps")
+	c.statsOutput = viper.GetString("stats.output")
+
+	// Format of exported views
+	c.exportFormat = viper.GetString("export.format")
+	if c.exportFormat != utils.ExportJSON && c.exportFormat != utils.ExportCSV {
+		return nil, fmt.Errorf("invalid export format %q, expected one of %s", c.exportFormat, strings.Join(utils.ExportFormats, ", "))
+	}
+	c.exportPNG = viper.GetBool("export.png")
+
+	// Refresh of markets
+	c.marketsRefresh = viper.GetDuration("markets.refresh")
+	if c.marketsRefresh <= 0 {
+		return nil, fmt.Errorf("invalid markets refresh, must be a positive duration")
+	}
+
+	// Guard against glitched prices
+	c.spikeGuard = viper.GetBool("guard.enabled")
+	c.spikeThreshold = viper.GetFloat64("guard.threshold")
+	if c.spikeThreshold <= 0 {
+		return nil, fmt.Errorf("invalid guard threshold, expected a positive percentage")
+	}
+	c.spikeWindow = viper.GetInt("guard.window")
+	if c.spikeWindow < utils.MinSpikeWindow {
+		return nil, fmt.Errorf("invalid guard window, expected %d or more prices", utils.MinSpikeWindow)
+	}
+	c.spikeLog = viper.GetString("guard.log")
+
+	// Low power mode
+	c.powerMode = strings.ToLower(viper.GetString("power.mode"))
+	if !contains(utils.PowerModes, c.powerMode) {
+		return nil, fmt.Errorf("invalid power mode %q, expected one of %s", c.powerMode, strings.Join(utils.PowerModes, ", "))
+	}
+	c.powerFactor = viper.GetInt("power.factor")
+	if c.powerFactor < 1 {
+		return nil, fmt.Errorf("invalid power factor, expected 1 or more")
+	}
+	c.powerFPS = viper.GetInt("power.fps")
+
+	// Trading session
+	location, err := time.LoadLocation(viper.GetString("session.timezone"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid session timezone: %v", err)
+	}
+	sessionOpen, err := utils.ParseTimeOfDay(viper.GetString("session.open"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid session open, expected HH:MM: %v", err)
+	}
+	sessionClose, err := utils.ParseTimeOfDay(viper.GetString("session.close"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid session close, expected HH:MM: %v", err)
+	}
+	c.session = utils.TradingSession{
+		Enabled:  viper.GetBool("session.enabled"),
+		Location: location,
+		Open:     sessionOpen,
+		Close:    sessionClose,
+	}
+
+	// Default favourites and currency, used until changed in the UI
+	c.favourites = viper.GetStringSlice("favourites")
+	c.currency = viper.GetString("currency")
+
+	// Refresh intervals and default coin page interval
+	c.historyRefresh, c.detailsRefresh = viper.GetDuration("refresh.history"), viper.GetDuration("refresh.details")
+	if c.historyRefresh <= 0 || c.detailsRefresh <= 0 {
+		return nil, fmt.Errorf("invalid refresh: refresh intervals must be positive")
+	}
+	c.uiRefresh = viper.GetDuration("refresh.ui")
+	if c.uiRefresh < utils.MinUIRefresh {
+		return nil, fmt.Errorf("invalid ui refresh %s, must be at least %s", c.uiRefresh, utils.MinUIRefresh)
+	}
+	c.assetsRefresh = viper.GetDuration("refresh.assets")
+	if c.assetsRefresh < api.MinPollRefresh {
+		return nil, fmt.Errorf("invalid assets refresh %s, must be at least %s", c.assetsRefresh, api.MinPollRefresh)
+	}
+	c.favouritesRefresh = viper.GetDuration("refresh.favourites")
+	if c.favouritesRefresh < api.MinPollRefresh {
+		return nil, fmt.Errorf("invalid favourites refresh %s, must be at least %s", c.favouritesRefresh, api.MinPollRefresh)
+	}
+	c.idleTimeout = viper.GetDuration("idle.timeout")
+	if c.idleTimeout != 0 && c.idleTimeout < time.Minute {
+		return nil, fmt.Errorf("invalid idle timeout %s, must be 0 to turn the screensaver off, or at least 1m", c.idleTimeout)
+	}
+	c.confirmQuit = viper.GetBool("quit.confirm")
+	c.coinInterval = viper.GetString("coin.interval")
+	if _, err := api.HistoryDays(c.coinInterval); err != nil {
+		return nil, fmt.Errorf("invalid coin interval, expected one of 24hr, 7d, 14d, 30d, 90d, 180d, 1yr, 5yr: %v", err)
+	}
+
+	// History granularity, points drawn and histories fetched at once
+	c.granularity = viper.GetString("history.granularity")
+	if c.granularity != api.AutoGranularity && !contains(api.Granularities(), c.granularity) {
+		return nil, fmt.Errorf("invalid history granularity %q, expected auto or one of %s",
+			c.granularity, strings.Join(api.Granularities(), ", "))
+	}
+	c.historyPoints = viper.GetInt("history.points")
+	if c.historyPoints < 0 {
+		return nil, fmt.Errorf("invalid history points %d, expected 0 or more", c.historyPoints)
+	}
+	c.historyWorkers = viper.GetInt("history.workers")
+	if c.historyWorkers < 1 || c.historyWorkers > api.MaxHistoryWorkers {
+		return nil, fmt.Errorf("invalid history workers %d, expected 1 to %d", c.historyWorkers, api.MaxHistoryWorkers)
+	}
+
+	// How long a live price stream may be silent before polling
+	c.staleAfter = viper.GetDuration("live.staleafter")
+	if c.staleAfter <= 0 {
+		return nil, fmt.Errorf("invalid live staleafter, must be a positive duration")
+	}
+
+	// How often FX rates of currencies are refreshed
+	c.fxRefresh = viper.GetDuration("fx.refresh")
+	if c.fxRefresh <= 0 {
+		return nil, fmt.Errorf("invalid fx refresh, must be a positive duration")
+	}
+
+	// Aliases of coins, Eg: xbt for bitcoin
+	c.aliases = map[string]string{}
+	for alias, id := range viper.GetStringMapString("aliases") {
+		alias, id = strings.ToLower(strings.TrimSpace(alias)), strings.ToLower(strings.TrimSpace(id))
+		if alias == "" || id == "" {
+			return nil, fmt.Errorf("invalid alias %q of %q, expected an alias and a CoinGecko ID", alias, id)
+		}
+		c.aliases[alias] = id
+	}
+
+	// Directory of watchlist files
+	c.watchlistDir, err = homedir.Expand(viper.GetString("watchlists.dir"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid watchlists dir: %v", err)
+	}
+
+	// Watchlists named in the config file, Eg: DeFi
+	namedWatchlists := []struct {
+		Name  string
+		Coins []string
+	}{}
+	if err := viper.UnmarshalKey("watchlists.lists", &namedWatchlists); err != nil {
+		return nil, fmt.Errorf("invalid watchlists lists: %v", err)
+	}
+	c.namedWatchlists = map[string][]string{}
+	for _, list := range namedWatchlists {
+		if list.Name == "" || strings.EqualFold(list.Name, utils.FavouritesList) {
+			return nil, fmt.Errorf("invalid watchlist name %q", list.Name)
+		}
+		if _, ok := c.namedWatchlists[list.Name]; ok {
+			return nil, fmt.Errorf("invalid watchlists lists: %q listed twice", list.Name)
+		}
+		c.namedWatchlists[list.Name] = list.Coins
+	}
+
+	// Screener queries named in the config file
+	queries := []struct {
+		Name  string
+		Query string
+	}{}
+	if err := viper.UnmarshalKey("screener.queries", &queries); err != nil {
+		return nil, fmt.Errorf("invalid screener queries: %v", err)
+	}
+	c.savedQueries = []api.ScreenerQuery{}
+	for _, saved := range queries {
+		if saved.Name == "" {
+			return nil, fmt.Errorf("invalid screener query %q, expected a name", saved.Query)
+		}
+		query, err := api.ParseScreenerQuery(saved.Query)
+		if err != nil {
+			return nil, fmt.Errorf("invalid screener query %s: %v", saved.Name, err)
+		}
+		for _, other := range c.savedQueries {
+			if strings.EqualFold(other.Name, saved.Name) {
+				return nil, fmt.Errorf("invalid screener queries: %q listed twice", saved.Name)
+			}
+		}
+		query.Name = saved.Name
+		c.savedQueries = append(c.savedQueries, query)
+	}
+
+	// Where API keys of providers are stored
+	c.keyStore = viper.GetString("apikeys.store")
+	if c.keyStore != utils.KeyStoreAuto && c.keyStore != utils.KeyStoreKeyring && c.keyStore != utils.KeyStoreFile {
+		return nil, fmt.Errorf("invalid apikeys store %q, expected auto, keyring or file", c.keyStore)
+	}
+
+	// The backend local data is kept in
+	c.storeBackend = viper.GetString("store.backend")
+	if _, err := utils.NewStore(c.storeBackend); err != nil {
+		return nil, fmt.Errorf("invalid store backend: %v", err)
+	}
+
+	// Response caching
+	c.cacheEnabled = viper.GetBool("cache.enabled")
+	if viper.GetBool("cache.disk") {
+		c.cacheDir, err = homedir.Expand(viper.GetString("cache.dir"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid cache dir: %v", err)
+		}
+	}
+
+	// Requests per minute allowed to each provider
+	c.rateLimits = map[string]int{}
+	for name := range viper.GetStringMap("ratelimit") {
+		perMinute := viper.GetInt("ratelimit." + name)
+		if !contains(api.RateLimitProviders(), name) {
+			return nil, fmt.Errorf("invalid ratelimit: unknown provider %q, expected one of: %s", name, strings.Join(api.RateLimitProviders(), ", "))
+		}
+		if perMinute < 0 {
+			return nil, fmt.Errorf("invalid ratelimit: rate limit of %s must not be negative", name)
+		}
+		c.rateLimits[name] = perMinute
+	}
+
+	// Stretching of refreshes when quotas are about to run out
+	c.adaptiveQuota = viper.GetBool("quota.adaptive")
+
+	// The terminal size of the small layout profile, and widgets hidden in
+	// each profile
+	c.smallWidth, c.smallHeight = viper.GetInt("layout.small.width"), viper.GetInt("layout.small.height")
+	if c.smallWidth <= 0 || c.smallHeight <= 0 {
+		return nil, fmt.Errorf("invalid layout small width and height, must be positive")
+	}
+	for profile := range viper.GetStringMap("layout") {
+		if profile != layout.Small && profile != layout.Large {
+			return nil, fmt.Errorf("invalid layout: unknown profile %q, expected small or large", profile)
+		}
+	}
+	c.layouts = map[string]map[string][]string{}
+	for _, profile := range layout.Profiles {
+		hide := map[string][]string{}
+		for page := range viper.GetStringMap("layout." + profile + ".hide") {
+			hide[page] = nil
+		}
+		for page := range layout.Widgets {
+			hide[page] = viper.GetStringSlice("layout." + profile + ".hide." + page)
+		}
+		if err := layout.Check(profile, hide); err != nil {
+			return nil, fmt.Errorf("invalid layout.%s: %v", profile, err)
+		}
+		c.layouts[profile] = hide
+	}
+	if err := workspaceLayout(c.layouts); err != nil {
+		return nil, err
+	}
+
+	// Keys bound to actions of each page
+	for page := range viper.GetStringMap("keys") {
+		if _, ok := keys.Keymaps[page]; !ok {
+			return nil, fmt.Errorf("invalid keys: unknown page %q, expected main or coin", page)
+		}
+	}
+	c.bindings = map[string]map[string][]string{}
+	for page, keymap := range keys.Keymaps {
+		bindings := map[string][]string{}
+		for action := range viper.GetStringMap("keys." + page) {
+			bindings[action] = viper.GetStringSlice("keys." + page + "." + action)
+		}
+		if err := keymap.Check(bindings); err != nil {
+			return nil, fmt.Errorf("invalid keys.%s: %v", page, err)
+		}
+		c.bindings[page] = bindings
+	}
+
+	// Price alerts
+	c.desktopNotifications = viper.GetBool("alerts.notify")
+	c.telegramChat = viper.GetString("alerts.telegram.chat")
+	c.webhookURL = viper.GetString("alerts.webhook.url")
+	if c.webhookURL != "" && !strings.HasPrefix(c.webhookURL, "https://") && !strings.HasPrefix(c.webhookURL, "http://") {
+		return nil, fmt.Errorf("invalid alerts.webhook.url %q, expected an http or https URL", c.webhookURL)
+	}
+	c.priceAlerts = []alerts.Alert{}
+	if err := viper.UnmarshalKey("alerts.rules", &c.priceAlerts); err != nil {
+		return nil, fmt.Errorf("invalid alert rules: %v", err)
+	}
+	for _, alert := range c.priceAlerts {
+		if alert.Kind != alerts.Above && alert.Kind != alerts.Below && alert.Kind != alerts.Change && alert.Kind != alerts.Move {
+			return nil, fmt.Errorf("invalid alert kind %q for %s, expected above, below, change or move", alert.Kind, alert.Coin)
+		}
+		if alert.Kind == alerts.Move && alert.Window <= 0 {
+			return nil, fmt.Errorf("invalid move alert for %s, expected a window, Eg: 15m", alert.Coin)
+		}
+	}
+
+	// Alert templates, applied to every coin of a watchlist
+	c.templates = []alerts.Template{}
+	if err := viper.UnmarshalKey("alerts.templates", &c.templates); err != nil {
+		return nil, fmt.Errorf("invalid alert templates: %v", err)
+	}
+	for _, template := range c.templates {
+		if template.Watchlist != alerts.Favourites && template.Watchlist != alerts.Holdings &&
+			template.Watchlist != alerts.Watchlists && template.Watchlist != alerts.All {
+			return nil, fmt.Errorf("invalid alert template watchlist %q, expected favourites, holdings, watchlists or all", template.Watchlist)
+		}
+		if template.Kind != alerts.Change {
+			return nil, fmt.Errorf("invalid alert template kind %q, templates only support change", template.Kind)
+		}
+		if template.Value <= 0 {
+			return nil, fmt.Errorf("invalid alert template value %g, expected a positive change", template.Value)
+		}
+	}
+
+	// Trading, off unless opted into
+	c.tradingEnabled = viper.GetBool("trading.enabled")
+	c.dryRun = viper.GetBool("trading.dryrun")
+
+	// How often the daemon checks prices, and its top movers scanner
+	c.daemonInterval = viper.GetDuration("daemon.interval")
+	if c.daemonInterval <= 0 {
+		return nil, fmt.Errorf("invalid daemon interval, must be a positive duration")
+	}
+	c.scanMovers = viper.GetBool("daemon.movers.enabled")
+	c.moverThreshold = viper.GetFloat64("daemon.movers.threshold")
+	if c.moverThreshold <= 0 {
+		return nil, fmt.Errorf("invalid daemon movers threshold %g, expected a positive change", c.moverThreshold)
+	}
+	c.moverTop = viper.GetInt("daemon.movers.top")
+	if c.moverTop < 1 || c.moverTop > maxDaemonCoins {
+		return nil, fmt.Errorf("invalid daemon movers top %d, expected 1 to %d", c.moverTop, maxDaemonCoins)
+	}
+
+	// Data source
+	c.source = viper.GetString("source")
+	if !contains(api.SourceNames(), c.source) {
+		return nil, fmt.Errorf("unknown source %q, available sources are: %s", c.source, strings.Join(api.SourceNames(), ", "))
+	}
+
+	return c, nil
+}
+
+// apply applies settings. Settings read by pollers are swapped in under the
+// settings lock, so pollers see them change at once, while those guarded by
+// locks of their own are set after. Settings were validated by parseConfig,
+// so setters don't fail.
+func (c *config) apply() {
+	utils.ApplySettings(func() {
+		utils.MaxFPS = c.maxFPS
+		utils.StatsOutput = c.statsOutput
+		utils.ExportFormat = c.exportFormat
+		utils.ExportPNG = c.exportPNG
+		api.MarketsRefresh = c.marketsRefresh
+
+		utils.SpikeGuard = c.spikeGuard
+		utils.SpikeThreshold = c.spikeThreshold
+		utils.SpikeWindow = c.spikeWindow
+		utils.SpikeLog = c.spikeLog
+
+		utils.LowPowerFactor = c.powerFactor
+		utils.LowPowerFPS = c.powerFPS
+
+		utils.UIRefresh = c.uiRefresh
+		utils.IdleTimeout = c.idleTimeout
+		utils.ConfirmQuit = c.confirmQuit
+		api.SetDefaultInterval(c.coinInterval)
+
+		api.SetHistoryGranularity(c.granularity)
+		utils.HistoryPoints = c.historyPoints
+		api.HistoryWorkers = c.historyWorkers
+		api.StaleAfter = c.staleAfter
+		api.FXRefresh = c.fxRefresh
+
+		api.Aliases = c.aliases
+		utils.WatchlistDir = c.watchlistDir
+		utils.NamedWatchlists = c.namedWatchlists
+		api.SavedQueries = c.savedQueries
+
+		utils.APIKeyStore = c.keyStore
+		utils.StoreBackend = c.storeBackend
+
+		api.CacheEnabled = c.cacheEnabled
+		api.CacheDir = c.cacheDir
+		api.AdaptiveQuota = c.adaptiveQuota
+
+		alerts.DesktopNotifications = c.desktopNotifications
+		alerts.TelegramChat = c.telegramChat
+		alerts.WebhookURL = c.webhookURL
+
+		exchange.TradingEnabled = c.tradingEnabled
+		exchange.DryRun = c.dryRun
+
+		daemonInterval = c.daemonInterval
+		api.ScanMovers = c.scanMovers
+		api.MoverThreshold = c.moverThreshold
+		api.MoverTop = c.moverTop
+	})
+
+	widgets.SetChangeColoring(c.changeStyle)
+	theme.Set(c.theme)
+	utils.SetPowerMode(c.powerMode)
+	utils.SetSession(c.session)
+	utils.SetDefaults(c.favourites, c.currency)
+	api.SetRefreshIntervals(c.historyRefresh, c.detailsRefresh)
+	api.SetPollRefresh(c.assetsRefresh, c.favouritesRefresh)
+	for name, perMinute := range c.rateLimits {
+		api.SetRateLimit(name, perMinute)
+	}
+	if !c.adaptiveQuota {
+		utils.SetStretch(1)
+	}
+
+	layout.SmallWidth, layout.SmallHeight = c.smallWidth, c.smallHeight
+	for profile, hide := range c.layouts {
+		layout.Set(profile, hide)
+	}
+	for page, bindings := range c.bindings {
+		keys.Keymaps[page].Set(bindings)
+	}
+
+	alerts.SetConfigAlerts(c.priceAlerts)
+	alerts.SetTemplates(c.templates)
+	api.SetSource(c.source)
+}
+
+// applyConfig applies settings read from the config file and flags. Nothing
+// is applied if any setting is invalid.
+func applyConfig() error {
+	c, err := parseConfig()
+	if err != nil {
+		return err
+	}
+	c.apply()
+	return nil
+}
+
+// reloadConfig re-reads the config file and applies it, keeping the
+// previous config if the file can't be read or holds an invalid setting.
+// Running streams are left as they are, settings they depend on apply when
+// they are next started.
+func reloadConfig() error {
+	if err := viper.ReadInConfig(); err != nil {
+		return err
+	}
+	return applyConfig()
+}
@@ -0,0 +1,168 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/alerts"
+	"github.com/Gituser143/cryptgo/pkg/api"
+	"github.com/Gituser143/cryptgo/pkg/utils"
+	"github.com/spf13/cobra"
+	geckoTypes "github.com/superoo7/go-gecko/v3/types"
+)
+
+// maxDaemonCoins is the most coins (by market cap) the daemon checks
+const maxDaemonCoins = 250
+
+// daemonInterval is how often the daemon checks prices, set from the
+// config file
+var daemonInterval = time.Minute
+
+// daemonCmd represents the daemon command
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Notify about alerts and top movers in the background",
+	Long: `The daemon command checks prices without a UI. Price alerts and alert
+templates are checked as on the main page, on coins alerts are set on
+whatever their rank, as check-alerts does, and top coins moving
+by at least daemon.movers.threshold % within an hour are notified about
+unless daemon.movers.enabled is false. Move alerts are checked continuously
+against prices streamed from CoinCap. Notifications are printed, sent to
+the desktop if alerts.notify is set, to Telegram if alerts.telegram.chat
+is set, and to a webhook if alerts.webhook.url is set.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var m sync.Mutex
+		scanner := api.NewMoverScanner(api.MoverThreshold, api.MoverTop)
+
+		// Scan coins of the main page's coin table for movers, or more
+		n := 150
+		if api.MoverTop > n {
+			n = api.MoverTop
+		}
+
+		// Check move alerts continuously against streamed prices
+		if coins := alerts.MoveCoins(); len(coins) > 0 {
+			go func() {
+				if err := watchMoves(context.Background(), coins); err != nil {
+					fmt.Fprintf(os.Stderr, "%s stopped checking move alerts: %v\n", time.Now().Format("15:04:05"), err)
+				}
+			}()
+		}
+
+		return utils.LoopTick(context.Background(), daemonInterval, func(errChan chan error) {
+			m.Lock()
+			defer m.Unlock()
+
+			// Keep running through failed requests, Eg: rate limits
+			var topCoins geckoTypes.CoinsMarket
+			if api.ScanMovers {
+				var err error
+				topCoins, err = api.GetSource().GetTopCoins(n)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "%s failed to fetch prices: %v\n", time.Now().Format("15:04:05"), err)
+					return
+				}
+			}
+
+			// Fetch coins alerts are set on as check-alerts does, as they
+			// may rank below the top coins
+			coinsData, err := alertCoins(topCoins)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s failed to fetch prices: %v\n", time.Now().Format("15:04:05"), err)
+				return
+			}
+
+			messages := []string{}
+			triggered := []alerts.Triggered{}
+			for _, val := range coinsData {
+				for _, t := range alerts.Check(val.ID, val.Symbol, val.CurrentPrice, val.PriceChangePercentage24h) {
+					messages = append(messages, t.Message())
+					triggered = append(triggered, t)
+				}
+			}
+
+			if api.ScanMovers {
+				for _, alert := range scanner.Check(topCoins) {
+					messages = append(messages, alert.Message())
+				}
+			}
+
+			for _, message := range messages {
+				fmt.Printf("%s %s\n", time.Now().Format("15:04:05"), message)
+			}
+			if len(messages) > 0 {
+				if err := alerts.Notify("cryptgo", strings.Join(messages, " | "), triggered...); err != nil {
+					fmt.Fprintf(os.Stderr, "%s %v\n", time.Now().Format("15:04:05"), err)
+				}
+			}
+		})
+	},
+}
+
+// watchMoves checks move alerts of coins against prices streamed from
+// CoinCap, printing and notifying those triggered, till the stream fails
+func watchMoves(ctx context.Context, coins []string) error {
+	coinIDs := api.NewCoinIDMap()
+	coinIDs.Populate()
+
+	ids := []api.CoinID{}
+	for _, coin := range coins {
+		ids = append(ids, coinIDs.Find(coin))
+	}
+
+	prices := make(chan api.LivePrice)
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- api.StreamPrices(ctx, ids, prices)
+	}()
+
+	for {
+		select {
+		case err := <-errChan:
+			return err
+
+		case p := <-prices:
+			symbol := p.ID.Symbol
+			if symbol == "" {
+				symbol = p.ID.CoinGeckoID
+			}
+
+			triggered := alerts.CheckMoves(p.ID.CoinGeckoID, symbol, p.Price, p.At)
+			messages := []string{}
+			for _, t := range triggered {
+				messages = append(messages, t.Message())
+				fmt.Printf("%s %s\n", p.At.Format("15:04:05"), t.Message())
+			}
+			if len(messages) > 0 {
+				if err := alerts.Notify("cryptgo", strings.Join(messages, " | "), triggered...); err != nil {
+					fmt.Fprintf(os.Stderr, "%s %v\n", p.At.Format("15:04:05"), err)
+				}
+			}
+		}
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(daemonCmd)
+}
@@ -0,0 +1,62 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+	"github.com/Gituser143/cryptgo/pkg/display/exchanges"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+)
+
+// exchangesCmd represents the exchanges command
+var exchangesCmd = &cobra.Command{
+	Use:   "exchanges",
+	Short: "Rank exchanges by volume",
+	Long: `The exchanges command ranks exchanges by 24 hour volume from CoinCap,
+along with their CoinGecko trust score and number of pairs. Selecting an
+exchange lists its markets`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPages("exchanges")
+	},
+}
+
+// runExchanges opens the exchanges page till it is closed
+func runExchanges() error {
+	// Context and errgroup used to manage routines
+	eg, ctx := errgroup.WithContext(context.Background())
+	dataChannel := make(chan api.ExchangeData)
+	exchangeChannel := make(chan api.Exchange, 1)
+
+	// Fetch exchanges and markets of the selected exchange
+	eg.Go(func() error {
+		return api.GetExchanges(ctx, exchangeChannel, dataChannel)
+	})
+
+	// Display UI for exchanges
+	eg.Go(func() error {
+		return exchanges.DisplayExchanges(ctx, exchangeChannel, dataChannel)
+	})
+
+	return eg.Wait()
+}
+
+func init() {
+	rootCmd.AddCommand(exchangesCmd)
+}
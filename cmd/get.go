@@ -0,0 +1,179 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+	"github.com/spf13/cobra"
+	geckoTypes "github.com/superoo7/go-gecko/v3/types"
+)
+
+var (
+	getJSON    bool
+	getCSV     bool
+	getHistory string
+)
+
+// coinQuote is the output of the get command. Prices are in USD.
+type coinQuote struct {
+	ID           string         `json:"id"`
+	Symbol       string         `json:"symbol"`
+	Name         string         `json:"name"`
+	Rank         int            `json:"rank"`
+	PriceUSD     float64        `json:"price_usd"`
+	Change24hPct float64        `json:"change_24h_pct"`
+	MarketCapUSD float64        `json:"market_cap_usd"`
+	Volume24hUSD float64        `json:"volume_24h_usd"`
+	History      []historyPoint `json:"history,omitempty"`
+}
+
+// historyPoint is a price of a coin at a point in time
+type historyPoint struct {
+	Time     time.Time `json:"time"`
+	PriceUSD float64   `json:"price_usd"`
+}
+
+// newCoinQuote returns the quote of a coin from its market data
+func newCoinQuote(asset geckoTypes.CoinsMarketItem) coinQuote {
+	return coinQuote{
+		ID:           asset.ID,
+		Symbol:       strings.ToUpper(asset.Symbol),
+		Name:         asset.Name,
+		Rank:         int(asset.MarketCapRank),
+		PriceUSD:     asset.CurrentPrice,
+		Change24hPct: asset.PriceChangePercentage24h,
+		MarketCapUSD: asset.MarketCap,
+		Volume24hUSD: asset.TotalVolume,
+	}
+}
+
+// newHistory returns points of a price history
+func newHistory(history []geckoTypes.ChartItem) []historyPoint {
+	points := []historyPoint{}
+	for _, item := range history {
+		points = append(points, historyPoint{
+			Time:     time.Unix(0, int64(item[0])*int64(time.Millisecond)).UTC(),
+			PriceUSD: float64(item[1]),
+		})
+	}
+	return points
+}
+
+// getCmd represents the get command
+var getCmd = &cobra.Command{
+	Use:   "get <coin>",
+	Short: "Print price and 24h change of a coin without the UI",
+	Long: `The get command prints the price, 24h change, market cap and volume of a
+coin and exits, for use in scripts and status bars (Eg: polybar or tmux). The
+coin is given by its symbol, CoinGecko ID or CoinCap ID, Eg: BTC or bitcoin.
+Data is served from the selected source and prices are in USD`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeCoins,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if getJSON && getCSV {
+			return fmt.Errorf("only one of --json and --csv can be used")
+		}
+
+		// Check interval of history before fetching anything
+		days := 0
+		if getHistory != "" {
+			var err error
+			days, err = api.HistoryDays(getHistory)
+			if err != nil {
+				return fmt.Errorf("invalid history, expected one of 24hr, 7d, 14d, 30d, 90d, 180d, 1yr, 5yr: %v", err)
+			}
+		}
+
+		src := api.GetSource()
+		id := api.FindCoinID(args[0])
+
+		asset, err := src.GetAsset(id)
+		if err != nil {
+			return err
+		}
+
+		quote := newCoinQuote(asset)
+
+		// Fetch history if asked for
+		if days > 0 {
+			history, err := src.GetHistory(id, days)
+			if err != nil {
+				return err
+			}
+			quote.History = newHistory(history)
+		}
+
+		switch {
+		case getJSON:
+			data, err := json.MarshalIndent(quote, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+
+		case getCSV:
+			w := csv.NewWriter(os.Stdout)
+
+			// History is written in place of the quote when asked for
+			if quote.History != nil {
+				w.Write([]string{"time", "price_usd"})
+				for _, point := range quote.History {
+					w.Write([]string{point.Time.Format(time.RFC3339), fmt.Sprintf("%g", point.PriceUSD)})
+				}
+			} else {
+				w.Write([]string{"id", "symbol", "name", "rank", "price_usd", "change_24h_pct", "market_cap_usd", "volume_24h_usd"})
+				w.Write([]string{
+					quote.ID,
+					quote.Symbol,
+					quote.Name,
+					fmt.Sprintf("%d", quote.Rank),
+					fmt.Sprintf("%g", quote.PriceUSD),
+					fmt.Sprintf("%.2f", quote.Change24hPct),
+					fmt.Sprintf("%g", quote.MarketCapUSD),
+					fmt.Sprintf("%g", quote.Volume24hUSD),
+				})
+			}
+
+			w.Flush()
+			return w.Error()
+
+		default:
+			// Single line for status bars, Eg: BTC 47000.12 USD ▲ 1.23%
+			change := fmt.Sprintf("%s %.2f%%", api.UP_ARROW, quote.Change24hPct)
+			if quote.Change24hPct < 0 {
+				change = fmt.Sprintf("%s %.2f%%", api.DOWN_ARROW, -quote.Change24hPct)
+			}
+			fmt.Printf("%s %.2f USD %s\n", quote.Symbol, quote.PriceUSD, change)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	getCmd.Flags().BoolVar(&getJSON, "json", false, "print as JSON")
+	getCmd.Flags().BoolVar(&getCSV, "csv", false, "print as CSV")
+	getCmd.Flags().StringVar(&getHistory, "history", "", "include price history over an interval (24hr, 7d, 14d, 30d, 90d, 180d, 1yr, 5yr)")
+	rootCmd.AddCommand(getCmd)
+}
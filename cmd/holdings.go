@@ -0,0 +1,68 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+	"github.com/Gituser143/cryptgo/pkg/display/holdings"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+)
+
+// holdingsCmd represents the holdings command
+var holdingsCmd = &cobra.Command{
+	Use:   "holdings",
+	Short: "Track holdings with cost basis and profit/loss",
+	Long: `The holdings command shows holdings read from the config file along with
+their current value, unrealised profit/loss and allocation`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Context and errgroup used to manage routines
+		eg, ctx := errgroup.WithContext(context.Background())
+		dataChannel := make(chan api.AssetData)
+
+		// Flag to determine if data must be sent
+		sendData := true
+
+		// Fetch Coin Assets
+		eg.Go(func() error {
+			return api.GetAssets(ctx, dataChannel, &sendData)
+		})
+
+		// Refresh FX rates of currencies
+		eg.Go(func() error {
+			return api.RefreshFXRates(ctx)
+		})
+
+		// Display UI for holdings
+		eg.Go(func() error {
+			return holdings.DisplayHoldings(ctx, dataChannel)
+		})
+
+		if err := eg.Wait(); err != nil {
+			if err.Error() != "UI Closed" {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(holdingsCmd)
+}
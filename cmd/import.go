@@ -0,0 +1,109 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+	"github.com/Gituser143/cryptgo/pkg/portfolio"
+	"github.com/Gituser143/cryptgo/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importFormat  string
+	importReplace bool
+)
+
+// portfolioImportCmd represents the portfolio import command
+var portfolioImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import trades from a CSV export, building holdings with cost basis",
+	Long: `The import command reads trades from a CSV export of Coinbase (transaction
+history) or Binance (spot trade history), or a generic CSV with columns date,
+type (buy or sell), coin, quantity, price and optionally fee and exchange.
+Prices and fees are in USD, and trades priced in other currencies are
+priced from the coin's price history at the time. Sells are matched
+against the oldest lots
+bought first (FIFO), and lots left open are shown on the holdings page
+along with the realised profit/loss. Trades imported before are kept, so
+overlapping exports can be imported`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		trades, skipped, err := portfolio.ParseTrades(f, importFormat)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %v", args[0], err)
+		}
+
+		imported := utils.GetTrades()
+		if importReplace {
+			imported = nil
+		}
+		merged, added := portfolio.MergeTrades(imported, trades)
+		merged, errs := portfolio.PriceTrades(merged, historicalPrice())
+		if err := utils.SaveTrades(merged); err != nil {
+			return err
+		}
+
+		fmt.Printf("Imported %d trades, %d were imported before\n", added, len(trades)-added)
+		for _, row := range skipped {
+			fmt.Printf("  skipped line %d: %s\n", row.Line, row.Reason)
+		}
+		for _, err := range errs {
+			fmt.Printf("  not priced: %v\n", err)
+		}
+
+		open, realised, warnings := portfolio.MatchFIFO(merged)
+		for _, warning := range warnings {
+			fmt.Printf("  warning: %s\n", warning)
+		}
+		fmt.Printf("%d open lots, %.2f USD realised by selling %d lots\n", len(open), portfolio.RealisedPnL(realised), len(realised))
+		return nil
+	},
+}
+
+// historicalPrice returns the USD price of a coin at a time, from the
+// history of the selected source. Coin IDs are only fetched once a price
+// is needed.
+func historicalPrice() func(coin string, t time.Time) (float64, error) {
+	var coinIDs api.CoinIDMap
+
+	return func(coin string, t time.Time) (float64, error) {
+		if coinIDs == nil {
+			coinIDs = api.NewCoinIDMap()
+			coinIDs.Populate()
+		}
+		return api.GetPriceAt(api.GetSource(), coinIDs.Find(coin), t)
+	}
+}
+
+func init() {
+	portfolioImportCmd.Flags().StringVar(&importFormat, "format", portfolio.FormatAuto, "format of the export, one of: "+strings.Join(portfolio.ImportFormats, ", "))
+	portfolioImportCmd.Flags().BoolVar(&importReplace, "replace", false, "replace trades imported before")
+
+	portfolioCmd.AddCommand(portfolioImportCmd)
+}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/Gituser143/cryptgo/pkg/api"
 	"github.com/Gituser143/cryptgo/pkg/display/portfolio"
+	"github.com/Gituser143/cryptgo/pkg/exchange"
 	"github.com/spf13/cobra"
 	"golang.org/x/sync/errgroup"
 )
@@ -31,32 +32,61 @@ var portfolioCmd = &cobra.Command{
 	Short: "Track your portfolio",
 	Long:  `The portfolio command helps track your own portfolio in real time`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// Context and errgroup used to manage routines
-		eg, ctx := errgroup.WithContext(context.Background())
-		dataChannel := make(chan api.AssetData)
-
-		// Flag to determine if data must be sent when viewing per coin prices
-		sendData := true
-
-		// Fetch Coin Assets
-		eg.Go(func() error {
-			return api.GetAssets(ctx, dataChannel, &sendData)
-		})
-
-		// Display UI for portfolio
-		eg.Go(func() error {
-			return portfolio.DisplayPortfolio(ctx, dataChannel, &sendData)
-		})
-
-		if err := eg.Wait(); err != nil {
-			if err.Error() != "UI Closed" {
-				return err
-			}
-		}
-		return nil
+		return runPages("portfolio")
 	},
 }
 
+// runPortfolio opens the portfolio page till it is closed
+func runPortfolio() error {
+	// Context and errgroup used to manage routines
+	eg, ctx := errgroup.WithContext(context.Background())
+	dataChannel := make(chan api.AssetData)
+	holdingsChannel := make(chan exchange.Holdings)
+	riskChannel := make(chan []api.PortfolioHolding, 1)
+	valueChannel := make(chan []api.PortfolioHolding, 1)
+	intervalChannel := make(chan string, 1)
+
+	// Flag to determine if data must be sent when viewing per coin prices
+	sendData := true
+
+	// Fetch Coin Assets
+	eg.Go(func() error {
+		return api.GetAssets(ctx, dataChannel, &sendData)
+	})
+
+	// Check for stale favourites and holdings
+	eg.Go(func() error {
+		return api.GetStaleCoins(ctx, dataChannel, &sendData)
+	})
+
+	// Fetch balances of connected exchange accounts
+	eg.Go(func() error {
+		return exchange.GetHoldings(ctx, holdingsChannel, &sendData)
+	})
+
+	// Compute risk metrics of holdings
+	eg.Go(func() error {
+		return api.GetPortfolioRisk(ctx, riskChannel, dataChannel, &sendData)
+	})
+
+	// Compute value history of holdings
+	eg.Go(func() error {
+		return api.GetPortfolioHistory(ctx, valueChannel, intervalChannel, dataChannel, &sendData)
+	})
+
+	// Refresh FX rates of currencies
+	eg.Go(func() error {
+		return api.RefreshFXRates(ctx)
+	})
+
+	// Display UI for portfolio
+	eg.Go(func() error {
+		return portfolio.DisplayPortfolio(ctx, dataChannel, holdingsChannel, riskChannel, valueChannel, intervalChannel, &sendData)
+	})
+
+	return eg.Wait()
+}
+
 func init() {
 	rootCmd.AddCommand(portfolioCmd)
 }
@@ -0,0 +1,109 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+	"github.com/Gituser143/cryptgo/pkg/display/ratio"
+	ui "github.com/gizak/termui/v3"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+)
+
+var (
+	ratioInterval string
+	ratioSMADays  int
+)
+
+// ratioCmd represents the ratio command
+var ratioCmd = &cobra.Command{
+	Use:   "ratio <coin> <coin>",
+	Short: "Plot the relative strength of a coin against another",
+	Long: `The ratio command plots the price of the first coin in terms of the
+second over an interval, along with its simple moving average. A ratio
+rising above its average shows the first coin outperforming the second.
+Coins are given by their symbol, CoinGecko ID or CoinCap ID,
+Eg: cryptgo ratio ETH BTC --sma 50`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeCoins,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Check interval and average before fetching anything
+		interval := api.DefaultInterval
+		if ratioInterval != "" {
+			if _, err := api.HistoryDays(ratioInterval); err != nil {
+				return fmt.Errorf("invalid interval, expected one of 24hr, 7d, 14d, 30d, 90d, 180d, 1yr, 5yr: %v", err)
+			}
+			interval = ratioInterval
+		}
+		if ratioSMADays <= 0 {
+			return fmt.Errorf("invalid sma %d, expected a number of days above 0", ratioSMADays)
+		}
+
+		// Get IDs of coins, named by the symbols given when unknown
+		coinIDMap := api.NewCoinIDMap()
+		coinIDMap.Populate()
+		ids := []api.CoinID{}
+		for _, coin := range args {
+			id := coinIDMap.Find(coin)
+			if id.Symbol == "" {
+				id.Symbol = strings.ToUpper(coin)
+			}
+			ids = append(ids, id)
+		}
+		pair := ids[0].Symbol + "/" + ids[1].Symbol
+
+		// Initialise UI
+		if err := ui.Init(); err != nil {
+			return fmt.Errorf("failed to initialise termui: %v", err)
+		}
+		defer ui.Close()
+
+		// Context and errgroup used to manage routines
+		eg, ctx := errgroup.WithContext(context.Background())
+		dataChannel := make(chan api.RatioData)
+		intervalChannel := make(chan string, 1)
+		intervalChannel <- interval
+
+		// Fetch ratio of coins
+		policy := api.GetRefreshPolicy(api.PriorityNormal)
+		eg.Go(func() error {
+			return api.GetRatioData(ctx, api.GetSource(), ids[0], ids[1], ratioSMADays, policy.HistoryInterval, intervalChannel, dataChannel)
+		})
+
+		// Display UI for ratio
+		eg.Go(func() error {
+			return ratio.DisplayRatio(ctx, pair, ratioSMADays, interval, intervalChannel, dataChannel, ui.PollEvents())
+		})
+
+		if err := eg.Wait(); err != nil {
+			if err.Error() != "UI Closed" {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(ratioCmd)
+	ratioCmd.Flags().StringVarP(&ratioInterval, "interval", "i", "", "interval of history, one of 24hr, 7d, 14d, 30d, 90d, 180d, 1yr, 5yr (default is coin.interval from the config file)")
+	ratioCmd.Flags().IntVar(&ratioSMADays, "sma", api.DefaultSMADays, "days the moving average of the ratio is taken over")
+}
@@ -20,6 +20,10 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -27,12 +31,20 @@ import (
 
 	"github.com/Gituser143/cryptgo/pkg/api"
 	"github.com/Gituser143/cryptgo/pkg/display/allcoin"
+	"github.com/Gituser143/cryptgo/pkg/display/layout"
+	"github.com/Gituser143/cryptgo/pkg/display/theme"
+	"github.com/Gituser143/cryptgo/pkg/exchange"
+	"github.com/Gituser143/cryptgo/pkg/utils"
+	"github.com/Gituser143/cryptgo/pkg/widgets"
 	homedir "github.com/mitchellh/go-homedir"
 	"github.com/spf13/viper"
 )
 
 var cfgFile string
 
+// watchlistName is the watchlist shown in the favourites table on opening
+var watchlistName string
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "cryptgo",
@@ -40,40 +52,94 @@ var rootCmd = &cobra.Command{
 	Long:  `Crytpgo is a TUI based application written purely in Go to monitor and observe cryptocurrency prices in real time!`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 
-		// Context and errgroup used to manage routines
-		eg, ctx := errgroup.WithContext(context.Background())
-		dataChannel := make(chan api.AssetData)
+		// Open the favourites table on a watchlist, matched ignoring case
+		if watchlistName != "" {
+			utils.ActiveWatchlist = ""
+			for _, name := range utils.WatchlistNames() {
+				if strings.EqualFold(name, watchlistName) {
+					utils.ActiveWatchlist = name
+				}
+			}
+			if utils.ActiveWatchlist == "" {
+				return fmt.Errorf("unknown watchlist %q, expected one of: %s", watchlistName, strings.Join(utils.WatchlistNames(), ", "))
+			}
+		}
 
-		// Flag to determine if data must be sent when viewing per coin prices
-		sendData := true
+		return runPages("main")
+	},
+}
 
-		// Fetch Coin Assets
-		eg.Go(func() error {
-			return api.GetAssets(ctx, dataChannel, &sendData)
-		})
+// runMain opens the main page till it is closed
+func runMain() error {
+	// Context and errgroup used to manage routines
+	eg, ctx := errgroup.WithContext(context.Background())
+	dataChannel := make(chan api.AssetData)
 
-		// Fetch Top 3 coin history
-		eg.Go(func() error {
-			return api.GetTopCoinData(ctx, dataChannel, &sendData, []string{"bitcoin", "ethereum", "nano"})
-		})
+	// Flag to determine if data must be sent when viewing per coin prices
+	sendData := true
 
-		// Display UI for overall coins
-		eg.Go(func() error {
-			return allcoin.DisplayAllCoins(ctx, dataChannel, &sendData)
-		})
+	// Coins of the coin table, sent by the main page to fetch sparklines of
+	sparklineChannel := make(chan []api.CoinID, 1)
 
-		if err := eg.Wait(); err != nil {
-			if err.Error() != "UI Closed" {
-				return err
-			}
-		}
-		return nil
-	},
+	// Fetch Coin Assets
+	eg.Go(func() error {
+		return api.GetAssets(ctx, dataChannel, &sendData)
+	})
+
+	// Fetch sparklines of the coin table
+	eg.Go(func() error {
+		return api.GetSparklines(ctx, sparklineChannel, dataChannel, &sendData)
+	})
+
+	// Fetch Top 3 coin history
+	eg.Go(func() error {
+		return api.GetTopCoinData(ctx, dataChannel, &sendData, []string{"bitcoin", "ethereum", "nano"})
+	})
+
+	// Fetch BTC dominance
+	eg.Go(func() error {
+		return api.GetDominanceHistory(ctx, dataChannel, &sendData)
+	})
+
+	// Fetch global market overview
+	eg.Go(func() error {
+		return api.GetGlobalOverview(ctx, dataChannel, &sendData)
+	})
+
+	// Fetch Altseason index
+	eg.Go(func() error {
+		return api.GetAltseasonIndex(ctx, dataChannel, &sendData)
+	})
+
+	// Check for stale favourites and holdings
+	eg.Go(func() error {
+		return api.GetStaleCoins(ctx, dataChannel, &sendData)
+	})
+
+	// Watch for changes to watchlist files
+	eg.Go(func() error {
+		return api.GetWatchlists(ctx, dataChannel, &sendData)
+	})
+
+	// Refresh FX rates of currencies
+	eg.Go(func() error {
+		return api.RefreshFXRates(ctx)
+	})
+
+	// Display UI for overall coins
+	eg.Go(func() error {
+		return allcoin.DisplayAllCoins(ctx, dataChannel, sparklineChannel, &sendData)
+	})
+
+	return eg.Wait()
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
+	// Save state and restore the terminal before exiting on SIGTERM
+	utils.HandleQuitSignals()
+
 	cobra.CheckErr(rootCmd.Execute())
 }
 
@@ -84,7 +150,22 @@ func init() {
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
 
-	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.cryptgo.yaml)")
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.config/cryptgo/config.yaml or $HOME/.cryptgo.yaml)")
+	rootCmd.PersistentFlags().Int("fps", utils.MaxFPS, "max redraws per second of live prices")
+	viper.BindPFlag("render.fps", rootCmd.PersistentFlags().Lookup("fps"))
+	rootCmd.PersistentFlags().String("power", utils.PowerAuto, "low power mode, one of: "+strings.Join(utils.PowerModes, ", ")+" (auto enters it on battery)")
+	viper.BindPFlag("power.mode", rootCmd.PersistentFlags().Lookup("power"))
+	rootCmd.PersistentFlags().String("source", "default", "data source, one of: "+strings.Join(api.SourceNames(), ", "))
+	viper.BindPFlag("source", rootCmd.PersistentFlags().Lookup("source"))
+	rootCmd.PersistentFlags().String("stats", "", "file to write quick stats of the open coin to as lines of JSON, Eg: a named pipe, or - for stdout")
+	viper.BindPFlag("stats.output", rootCmd.PersistentFlags().Lookup("stats"))
+	rootCmd.PersistentFlags().Duration("refresh-ui", utils.UIRefresh, "how often pages are refreshed, at least "+utils.MinUIRefresh.String())
+	viper.BindPFlag("refresh.ui", rootCmd.PersistentFlags().Lookup("refresh-ui"))
+	rootCmd.PersistentFlags().Duration("refresh-assets", api.AssetsRefresh(), "how often top coins are polled, at least "+api.MinPollRefresh.String())
+	viper.BindPFlag("refresh.assets", rootCmd.PersistentFlags().Lookup("refresh-assets"))
+	rootCmd.PersistentFlags().Duration("refresh-favourites", api.FavouritesRefresh(), "how often prices of favourites are polled, at least "+api.MinPollRefresh.String())
+	viper.BindPFlag("refresh.favourites", rootCmd.PersistentFlags().Lookup("refresh-favourites"))
+	rootCmd.Flags().StringVar(&watchlistName, "watchlist", "", "watchlist shown in the favourites table (default is favourites)")
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -97,9 +178,15 @@ func initConfig() {
 		home, err := homedir.Dir()
 		cobra.CheckErr(err)
 
-		// Search config in home directory with name ".cryptgo" (without extension).
-		viper.AddConfigPath(home)
-		viper.SetConfigName(".cryptgo")
+		// Prefer config.yaml in the user config directory, falling back to
+		// ".cryptgo" (without extension) in the home directory.
+		configFile := filepath.Join(configDir(), "cryptgo", "config.yaml")
+		if _, err := os.Stat(configFile); err == nil {
+			viper.SetConfigFile(configFile)
+		} else {
+			viper.AddConfigPath(home)
+			viper.SetConfigName(".cryptgo")
+		}
 	}
 
 	viper.AutomaticEnv() // read in environment variables that match
@@ -108,4 +195,162 @@ func initConfig() {
 	if err := viper.ReadInConfig(); err == nil {
 		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
 	}
+
+	// Set colouring of changes
+	viper.SetDefault("change.flat", widgets.ChangeColoring().Flat)
+	viper.SetDefault("change.gradient", widgets.ChangeColoring().Gradient)
+	viper.SetDefault("change.fullscale", widgets.ChangeColoring().FullScale)
+
+	// Set colour theme
+	viper.SetDefault("theme", theme.Current().Name)
+
+	// Set trading session marked on intraday charts
+	viper.SetDefault("session.enabled", false)
+	viper.SetDefault("session.timezone", "America/New_York")
+	viper.SetDefault("session.open", "09:30")
+	viper.SetDefault("session.close", "16:00")
+
+	// Set defaults of favourites, currency, refresh intervals and coin page
+	viper.SetDefault("favourites", []string{})
+	viper.SetDefault("currency", utils.DefaultCurrency)
+	normalPolicy := api.GetRefreshPolicy(api.PriorityNormal)
+	viper.SetDefault("refresh.history", normalPolicy.HistoryInterval)
+	viper.SetDefault("refresh.details", normalPolicy.DetailsInterval)
+	viper.SetDefault("coin.interval", api.DefaultInterval)
+
+	// Set history granularity and points drawn, fit to the graph by default
+	viper.SetDefault("history.granularity", api.AutoGranularity)
+	viper.SetDefault("history.points", utils.HistoryPoints)
+	viper.SetDefault("history.workers", api.HistoryWorkers)
+	viper.SetDefault("idle.timeout", utils.IdleTimeout)
+
+	// Set whether the quit key must be pressed twice to close a page
+	viper.SetDefault("quit.confirm", utils.ConfirmQuit)
+
+	// Set how long a live price stream may be silent before polling
+	viper.SetDefault("live.staleafter", api.StaleAfter)
+
+	// Set how often FX rates of currencies are refreshed
+	viper.SetDefault("fx.refresh", api.FXRefresh)
+
+	// Set the format views are exported in
+	viper.SetDefault("export.format", utils.ExportFormat)
+	viper.SetDefault("export.png", utils.ExportPNG)
+
+	// Set how often markets of a coin are refreshed while shown
+	viper.SetDefault("markets.refresh", api.MarketsRefresh)
+
+	// Set the guard against glitched prices
+	viper.SetDefault("guard.enabled", utils.SpikeGuard)
+	viper.SetDefault("guard.threshold", utils.SpikeThreshold)
+	viper.SetDefault("guard.window", utils.SpikeWindow)
+	viper.SetDefault("guard.log", "")
+
+	// Set how much refreshes and redraws are slowed down in low power mode
+	viper.SetDefault("power.mode", utils.PowerAuto)
+	viper.SetDefault("power.factor", utils.LowPowerFactor)
+	viper.SetDefault("power.fps", utils.LowPowerFPS)
+
+	// Set directory of watchlist files
+	viper.SetDefault("watchlists.dir", filepath.Join(configDir(), "cryptgo", "watchlists"))
+
+	// Set how often the daemon checks prices, and its top movers scanner
+	viper.SetDefault("daemon.interval", daemonInterval)
+	viper.SetDefault("daemon.movers.enabled", api.ScanMovers)
+	viper.SetDefault("daemon.movers.threshold", api.MoverThreshold)
+	viper.SetDefault("daemon.movers.top", api.MoverTop)
+
+	// Set the terminal size of the small layout profile, and widgets hidden
+	// in each profile
+	viper.SetDefault("layout.small.width", layout.SmallWidth)
+	viper.SetDefault("layout.small.height", layout.SmallHeight)
+	for _, profile := range layout.Profiles {
+		for page := range layout.Widgets {
+			viper.SetDefault("layout."+profile+".hide."+page, layout.HiddenNames(profile, page))
+		}
+	}
+
+	// Set where API keys of providers are stored
+	viper.SetDefault("apikeys.store", utils.APIKeyStore)
+
+	// Set the backend local data is kept in
+	viper.SetDefault("store.backend", utils.StoreBackend)
+
+	// Set response caching, on disk only if enabled
+	viper.SetDefault("cache.enabled", api.CacheEnabled)
+	viper.SetDefault("cache.disk", false)
+	viper.SetDefault("cache.dir", filepath.Join(cacheDir(), "cryptgo"))
+
+	// Set requests per minute allowed to each provider
+	for name, perMinute := range api.DefaultRateLimits {
+		viper.SetDefault("ratelimit."+name, perMinute)
+	}
+
+	// Set stretching of refreshes when quotas are about to run out
+	viper.SetDefault("quota.adaptive", api.AdaptiveQuota)
+
+	// Set trading, off unless opted into
+	viper.SetDefault("trading.enabled", exchange.TradingEnabled)
+	viper.SetDefault("trading.dryrun", exchange.DryRun)
+
+	cobra.CheckErr(applyConfig())
+
+	utils.ReloadConfig = reloadConfig
+	utils.SaveConfig = saveConfig
+}
+
+// configDir returns the user config directory, $XDG_CONFIG_HOME or
+// ~/.config
+func configDir() string {
+	dir, err := utils.ConfigDir()
+	cobra.CheckErr(err)
+	return dir
+}
+
+// cacheDir returns the user cache directory, $XDG_CACHE_HOME or ~/.cache
+func cacheDir() string {
+	if xdg := os.Getenv("XDG_CACHE_HOME"); xdg != "" {
+		return xdg
+	}
+	home, err := homedir.Dir()
+	cobra.CheckErr(err)
+	return filepath.Join(home, ".cache")
+}
+
+// saveConfig writes favourites and the currency changed in the UI to the
+// config file read, or to config.yaml in the user config directory if none
+// was. Other settings in the file are kept, and the file is left untouched if
+// neither changed.
+func saveConfig(favourites map[string]bool, currency string) error {
+	file := viper.ConfigFileUsed()
+	if file == "" {
+		file = filepath.Join(configDir(), "cryptgo", "config.yaml")
+		if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+			return err
+		}
+	}
+
+	// Read the file alone, so defaults, flags and the environment aren't
+	// written along
+	v := viper.New()
+	v.SetConfigFile(file)
+	if err := v.ReadInConfig(); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	ids := []string{}
+	for id, ok := range favourites {
+		if ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	if reflect.DeepEqual(v.GetStringSlice("favourites"), ids) && v.GetString("currency") == currency {
+		return nil
+	}
+
+	v.Set("favourites", ids)
+	v.Set("currency", currency)
+	return v.WriteConfig()
 }
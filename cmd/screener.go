@@ -0,0 +1,202 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+	"github.com/Gituser143/cryptgo/pkg/display/screener"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+)
+
+var (
+	screenerJSON bool
+	screenerCSV  bool
+)
+
+// screenedCoin is a coin printed by the screener run command. Values are
+// in USD.
+type screenedCoin struct {
+	coinQuote
+	VolumeRatio float64  `json:"volume_mcap_ratio"`
+	FromATHPct  *float64 `json:"from_ath_pct,omitempty"`
+}
+
+// findQuery returns the query named or given as criteria by args, the
+// first saved query if none is given, or else one listing all coins
+func findQuery(args []string) (api.ScreenerQuery, error) {
+	if len(args) == 0 {
+		if saved := api.GetSavedQueries(); len(saved) > 0 {
+			return saved[0], nil
+		}
+		return api.ScreenerQuery{}, nil
+	}
+	return api.FindQuery(strings.Join(args, " "))
+}
+
+// screenerCmd represents the screener command
+var screenerCmd = &cobra.Command{
+	Use:   "screener [query name | criteria]",
+	Short: "Screen top coins by market cap, change, volume and distance from ATH",
+	Long: `The screener command lists the top 250 coins meeting criteria, updated as
+market data is refreshed. Criteria are separated by spaces, all of which
+coins must meet, Eg: mcap>100m change<-5 volmcap>0.1 ath<-70. Fields are:
+
+  mcap     market cap in USD, values may end with k, m, b or t
+  change   change % over 24 hours
+  volmcap  volume over 24 hours divided by market cap
+  ath      change % from the all time high, Eg: -70 is 70% below it
+
+Queries can be named under screener.queries in the config file, and opened
+by name. The first one is opened by default`,
+	Args: cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		query, err := findQuery(args)
+		if err != nil {
+			return err
+		}
+
+		// Context and errgroup used to manage routines
+		eg, ctx := errgroup.WithContext(context.Background())
+		dataChannel := make(chan api.ScreenerData)
+		// Buffered so the page isn't held up till the next tick
+		queryChannel := make(chan api.ScreenerQuery, 1)
+
+		// Screen coins with the query last set
+		eg.Go(func() error {
+			return api.GetScreener(ctx, queryChannel, dataChannel)
+		})
+
+		// Display UI for the screener
+		eg.Go(func() error {
+			return screener.DisplayScreener(ctx, query, queryChannel, dataChannel)
+		})
+
+		if err := eg.Wait(); err != nil {
+			if err.Error() != "UI Closed" {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+// screenerRunCmd represents the screener run command
+var screenerRunCmd = &cobra.Command{
+	Use:   "run [query name | criteria]",
+	Short: "Print coins meeting a query without the UI",
+	Long: `The run command screens coins once and prints those meeting a query, by
+market cap, for use in scripts. Prices and market caps are in USD`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if screenerJSON && screenerCSV {
+			return fmt.Errorf("only one of --json and --csv can be used")
+		}
+
+		query, err := findQuery(args)
+		if err != nil {
+			return err
+		}
+
+		coins, err := api.GetScreenerCoins()
+		if err != nil {
+			return err
+		}
+
+		screened := []screenedCoin{}
+		for _, coin := range api.Screen(query, coins) {
+			c := screenedCoin{coinQuote: newCoinQuote(coin)}
+			c.VolumeRatio, _ = api.FieldValue(coin, api.FieldVolumeRatio)
+			if val, ok := api.FieldValue(coin, api.FieldATH); ok {
+				c.FromATHPct = &val
+			}
+			screened = append(screened, c)
+		}
+
+		switch {
+		case screenerJSON:
+			data, err := json.MarshalIndent(screened, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+
+		case screenerCSV:
+			w := csv.NewWriter(os.Stdout)
+			w.Write([]string{"id", "symbol", "name", "rank", "price_usd", "change_24h_pct", "market_cap_usd", "volume_24h_usd", "volume_mcap_ratio", "from_ath_pct"})
+			for _, c := range screened {
+				fromATH := ""
+				if c.FromATHPct != nil {
+					fromATH = fmt.Sprintf("%.2f", *c.FromATHPct)
+				}
+				w.Write([]string{
+					c.ID,
+					c.Symbol,
+					c.Name,
+					fmt.Sprintf("%d", c.Rank),
+					fmt.Sprintf("%g", c.PriceUSD),
+					fmt.Sprintf("%.2f", c.Change24hPct),
+					fmt.Sprintf("%g", c.MarketCapUSD),
+					fmt.Sprintf("%g", c.Volume24hUSD),
+					fmt.Sprintf("%.4f", c.VolumeRatio),
+					fromATH,
+				})
+			}
+
+			w.Flush()
+			return w.Error()
+
+		default:
+			// One coin a line, Eg: 12 DOT 6.54 USD ▼ 5.21%
+			for _, c := range screened {
+				change := fmt.Sprintf("%s %.2f%%", api.UP_ARROW, c.Change24hPct)
+				if c.Change24hPct < 0 {
+					change = fmt.Sprintf("%s %.2f%%", api.DOWN_ARROW, -c.Change24hPct)
+				}
+				fmt.Printf("%d %s %g USD %s\n", c.Rank, c.Symbol, c.PriceUSD, change)
+			}
+		}
+
+		return nil
+	},
+}
+
+// screenerListCmd represents the screener list command
+var screenerListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List screener queries saved in the config file",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, query := range api.GetSavedQueries() {
+			fmt.Printf("%s: %s\n", query.Name, query.String())
+		}
+	},
+}
+
+func init() {
+	screenerRunCmd.Flags().BoolVar(&screenerJSON, "json", false, "print as JSON")
+	screenerRunCmd.Flags().BoolVar(&screenerCSV, "csv", false, "print as CSV")
+
+	screenerCmd.AddCommand(screenerRunCmd, screenerListCmd)
+	rootCmd.AddCommand(screenerCmd)
+}
@@ -0,0 +1,321 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+	"github.com/Gituser143/cryptgo/pkg/utils"
+	"github.com/spf13/cobra"
+	geckoTypes "github.com/superoo7/go-gecko/v3/types"
+	"golang.org/x/sync/errgroup"
+)
+
+var serveAddr string
+
+// server serves data of the main page pollers and the selected source as
+// JSON
+type server struct {
+	m            sync.Mutex
+	coins        geckoTypes.CoinsMarket
+	favourites   geckoTypes.CoinsMarket
+	favouriteIDs map[string]bool
+	idsOnce      sync.Once
+	ids          api.CoinIDMap
+}
+
+// favouriteQuote is the quote of a favourite, or the error it couldn't be
+// quoted with, Eg: if it isn't listed
+type favouriteQuote struct {
+	ID string `json:"id"`
+	*coinQuote
+	Error string `json:"error,omitempty"`
+}
+
+// writeJSON writes v as the JSON response with the given status
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes err as a JSON error, with a status depending on it
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusBadGateway
+
+	var statusErr *api.StatusError
+	if errors.Is(err, api.ErrNotListed) || (errors.As(err, &statusErr) && statusErr.Code == http.StatusNotFound) {
+		status = http.StatusNotFound
+	}
+
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// find returns IDs of a coin given by its symbol, CoinGecko ID or CoinCap
+// ID. Coin IDs are fetched on the first lookup.
+func (s *server) find(coin string) api.CoinID {
+	s.idsOnce.Do(func() {
+		s.ids = api.NewCoinIDMap()
+		s.ids.Populate()
+	})
+	return s.ids.Find(coin)
+}
+
+// asset returns market data of a coin, from the latest top coins if listed
+func (s *server) asset(id api.CoinID) (geckoTypes.CoinsMarketItem, error) {
+	s.m.Lock()
+	for _, val := range s.coins {
+		if val.ID == id.CoinGeckoID {
+			s.m.Unlock()
+			return val, nil
+		}
+	}
+	s.m.Unlock()
+
+	return api.GetSource().GetAsset(id)
+}
+
+// serveCoins serves quotes of top coins
+func (s *server) serveCoins(w http.ResponseWriter, r *http.Request) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.coins == nil {
+		w.Header().Set("Retry-After", "10")
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "coins not fetched yet"})
+		return
+	}
+
+	quotes := []coinQuote{}
+	for _, val := range s.coins {
+		quotes = append(quotes, newCoinQuote(val))
+	}
+	writeJSON(w, http.StatusOK, quotes)
+}
+
+// serveCoin serves the quote of a coin, /coins/{id}, or its history,
+// /coins/{id}/history. History is over the interval query parameter, or
+// coin.interval from the config file.
+func (s *server) serveCoin(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/coins/"), "/")
+	if parts[0] == "" || len(parts) > 2 || (len(parts) == 2 && parts[1] != "history") {
+		http.NotFound(w, r)
+		return
+	}
+	id := s.find(parts[0])
+
+	if len(parts) == 1 {
+		asset, err := s.asset(id)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, newCoinQuote(asset))
+		return
+	}
+
+	interval := r.URL.Query().Get("interval")
+	if interval == "" {
+		interval = api.DefaultInterval
+	}
+	days, err := api.HistoryDays(interval)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": "invalid interval, expected one of 24hr, 7d, 14d, 30d, 90d, 180d, 1yr, 5yr",
+		})
+		return
+	}
+
+	history, err := api.GetSource().GetHistory(id, days)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newHistory(history))
+}
+
+// serveFavourites serves quotes of favourites as last polled. Favourites
+// missing from the poll are listed with an error.
+func (s *server) serveFavourites(w http.ResponseWriter, r *http.Request) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.favourites == nil {
+		w.Header().Set("Retry-After", "10")
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "favourites not fetched yet"})
+		return
+	}
+
+	quotes := []favouriteQuote{}
+	polled := map[string]bool{}
+	for _, val := range s.favourites {
+		quote := newCoinQuote(val)
+		quotes = append(quotes, favouriteQuote{ID: val.ID, coinQuote: &quote})
+		polled[val.ID] = true
+	}
+
+	// Order as the coin table, by rank
+	sort.Slice(quotes, func(i, j int) bool {
+		return quotes[i].Rank < quotes[j].Rank
+	})
+
+	missing := []string{}
+	for id := range s.favouriteIDs {
+		if !polled[id] {
+			missing = append(missing, id)
+		}
+	}
+	sort.Strings(missing)
+	for _, id := range missing {
+		quotes = append(quotes, favouriteQuote{ID: id, Error: api.ErrNotListed.Error()})
+	}
+
+	writeJSON(w, http.StatusOK, quotes)
+}
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve prices as JSON over HTTP",
+	Long: `The serve command serves data of cryptgo as JSON over HTTP, for other
+local tools. Prices of top coins and favourites are polled as on the main
+page, other data is fetched from the selected source through the response
+cache. Prices are
+in USD. Endpoints are:
+
+  /coins                 top coins by market cap
+  /coins/{id}            a coin, by symbol, CoinGecko ID or CoinCap ID
+  /coins/{id}/history    price history, over ?interval= (Eg: 7d)
+  /favourites            favourite coins`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Context and errgroup used to manage routines
+		eg, ctx := errgroup.WithContext(context.Background())
+		dataChannel := make(chan api.AssetData)
+
+		favouritesChannel := make(chan api.CoinData)
+
+		// Flag to determine if data must be sent
+		sendData := true
+
+		// Fetch Coin Assets, polling again after failed requests, Eg: rate
+		// limits
+		eg.Go(func() error {
+			for {
+				err := api.GetAssets(ctx, dataChannel, &sendData)
+				if ctx.Err() != nil {
+					return err
+				}
+				fmt.Fprintf(os.Stderr, "%s failed to fetch prices: %v\n", time.Now().Format("15:04:05"), err)
+
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(time.Duration(10) * time.Second):
+				}
+			}
+		})
+
+		s := &server{}
+
+		// Fetch prices of favourites, polling again after failed requests.
+		// There is nothing to poll without favourites.
+		favourites := utils.Watchlist{Name: utils.FavouritesList, IDs: utils.GetFavourites()}
+		s.favouriteIDs = favourites.IDs
+		if len(favourites.IDs) == 0 {
+			s.favourites = geckoTypes.CoinsMarket{}
+		}
+		eg.Go(func() error {
+			if len(favourites.IDs) == 0 {
+				return nil
+			}
+			for {
+				err := api.GetFavouritePrices(ctx, favourites, favouritesChannel)
+				if ctx.Err() != nil {
+					return err
+				}
+				fmt.Fprintf(os.Stderr, "%s failed to fetch favourites: %v\n", time.Now().Format("15:04:05"), err)
+
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(time.Duration(10) * time.Second):
+				}
+			}
+		})
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("/coins", s.serveCoins)
+		mux.HandleFunc("/coins/", s.serveCoin)
+		mux.HandleFunc("/favourites", s.serveFavourites)
+		srv := &http.Server{Addr: serveAddr, Handler: mux}
+
+		// Keep the latest coins and favourites
+		eg.Go(func() error {
+			for {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case data := <-dataChannel:
+					s.m.Lock()
+					s.coins = data.AllCoinData
+					s.m.Unlock()
+				case data := <-favouritesChannel:
+					if data.FavouriteCoins == nil {
+						// Nothing polled yet, Eg: only simple prices
+						continue
+					}
+					s.m.Lock()
+					s.favourites = data.FavouriteCoins
+					s.m.Unlock()
+				}
+			}
+		})
+
+		// Serve until the server fails
+		eg.Go(func() error {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Duration(5)*time.Second)
+			defer cancel()
+			return srv.Shutdown(shutdownCtx)
+		})
+		eg.Go(func() error {
+			fmt.Printf("Serving on http://%s\n", serveAddr)
+			if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+				return err
+			}
+			return nil
+		})
+
+		return eg.Wait()
+	},
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveAddr, "addr", "127.0.0.1:8080", "address to listen on")
+	rootCmd.AddCommand(serveCmd)
+}
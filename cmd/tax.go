@@ -0,0 +1,102 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/portfolio"
+	"github.com/Gituser143/cryptgo/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	taxYear      string
+	taxYearStart string
+	taxOutput    string
+)
+
+// portfolioTaxCmd represents the portfolio tax command
+var portfolioTaxCmd = &cobra.Command{
+	Use:   "tax",
+	Short: "Export gains realised by imported trades as CSV, per tax year",
+	Long: `The tax command reports gains realised by imported trades as CSV, with a
+row for each lot sold: its tax year, quantity, dates acquired and disposed
+of, holding period, proceeds, cost basis and gain in USD. Lots are matched
+first in first out, as on the holdings page, and held for longer than a
+year are long term. Trades missing a USD value, Eg: of pairs not quoted in
+USD, are valued at the coin's price at the time from its price history`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		yearStart, err := time.Parse("01-02", taxYearStart)
+		if err != nil {
+			return fmt.Errorf("invalid start of tax years %q, expected MM-DD, Eg: 04-06", taxYearStart)
+		}
+
+		trades := utils.GetTrades()
+		if len(trades) == 0 {
+			return fmt.Errorf("no trades were imported, import them with cryptgo portfolio import <file>")
+		}
+
+		trades, errs := portfolio.PriceTrades(trades, historicalPrice())
+		for _, err := range errs {
+			fmt.Fprintf(os.Stderr, "not priced: %v\n", err)
+		}
+
+		_, realised, warnings := portfolio.MatchFIFO(trades)
+		for _, warning := range warnings {
+			fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+		}
+		gains := portfolio.Gains(realised, yearStart, taxYear)
+
+		var out io.Writer = os.Stdout
+		if taxOutput != "" && taxOutput != "-" {
+			f, err := os.Create(taxOutput)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			out = f
+		}
+		if err := portfolio.WriteGainsCSV(out, gains); err != nil {
+			return err
+		}
+
+		// Totals per term
+		short, long := 0.0, 0.0
+		for _, g := range gains {
+			if g.LongTerm() {
+				long += g.PnL
+			} else {
+				short += g.PnL
+			}
+		}
+		fmt.Fprintf(os.Stderr, "%d lots sold, %.2f USD short term and %.2f USD long term gains\n", len(gains), short, long)
+		return nil
+	},
+}
+
+func init() {
+	portfolioTaxCmd.Flags().StringVar(&taxYear, "year", "", "tax year to report, Eg: 2023 or 2023-24 (default reports every year)")
+	portfolioTaxCmd.Flags().StringVar(&taxYearStart, "year-start", "01-01", "month and day tax years start on, Eg: 04-06")
+	portfolioTaxCmd.Flags().StringVarP(&taxOutput, "output", "o", "", "file to write the CSV to (default is standard output)")
+
+	portfolioCmd.AddCommand(portfolioTaxCmd)
+}
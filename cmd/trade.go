@@ -0,0 +1,218 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Gituser143/cryptgo/pkg/display/orders"
+	"github.com/Gituser143/cryptgo/pkg/exchange"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+)
+
+// Flags of the trade command
+var (
+	tradeExchange string
+	tradePrice    float64
+	tradeDryRun   bool
+	tradeYes      bool
+)
+
+// tradeCmd represents the trade command
+var tradeCmd = &cobra.Command{
+	Use:   "trade",
+	Short: "Place and track orders on an exchange, once trading is enabled",
+	Long: `The trade command places limit and market orders on ` + strings.Join(exchange.TradingNames(), ", ") + `, and
+tracks their status. Trading is off unless trading.enabled is set to true in
+the config file, and every order is confirmed by typing yes before it is
+placed. Orders are only validated by the exchange with --dry-run, or with
+trading.dryrun set in the config file.
+
+Trading keys are connected apart from read only keys of the account command,
+and are kept encrypted in ~/.config/cryptgo/exchanges.json. Create them with spot
+trading permissions only, never withdrawals. Orders placed are recorded in
+~/.cryptgo-orders.json`,
+}
+
+// tradeConnectCmd represents the trade connect command
+var tradeConnectCmd = &cobra.Command{
+	Use:   "connect <exchange>",
+	Short: "Connect an account to trade with, reading its API key and secret from stdin",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Read credentials from stdin, so they aren't kept in shell history
+		stdin := bufio.NewReader(os.Stdin)
+		fmt.Fprintf(os.Stderr, "Trading API key for %s: ", args[0])
+		key, _ := stdin.ReadString('\n')
+		fmt.Fprintf(os.Stderr, "Trading API secret for %s: ", args[0])
+		secret, _ := stdin.ReadString('\n')
+
+		creds := exchange.Credentials{
+			Key:    strings.TrimSpace(key),
+			Secret: strings.TrimSpace(secret),
+		}
+		if err := exchange.ConnectTrading(args[0], creds); err != nil {
+			return err
+		}
+		fmt.Printf("Connected the %s account for trading\n", args[0])
+		return nil
+	},
+}
+
+// tradeDisconnectCmd represents the trade disconnect command
+var tradeDisconnectCmd = &cobra.Command{
+	Use:   "disconnect <exchange>",
+	Short: "Remove the trading API key of an exchange account",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exchange.DisconnectTrading(args[0])
+	},
+}
+
+// placeOrder places an order of a side from arguments of the buy and sell
+// commands, once confirmed
+func placeOrder(side string, args []string) error {
+	quantity, err := strconv.ParseFloat(args[1], 64)
+	if err != nil {
+		return fmt.Errorf("invalid quantity %q", args[1])
+	}
+	order, err := exchange.ValidateOrder(exchange.Order{
+		Exchange: tradeExchange,
+		Pair:     args[0],
+		Side:     side,
+		Quantity: quantity,
+		Price:    tradePrice,
+	})
+	if err != nil {
+		return err
+	}
+
+	dryRun := tradeDryRun || exchange.DryRun
+	if !tradeYes {
+		action := order.String()
+		if dryRun {
+			action += " (dry run)"
+		}
+		fmt.Fprintf(os.Stderr, "Type yes to %s: ", action)
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if strings.TrimSpace(strings.ToLower(answer)) != "yes" {
+			return fmt.Errorf("order not placed")
+		}
+	}
+
+	order, err = exchange.PlaceOrder(context.Background(), order, dryRun)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Order %s is %s\n", order.ID, order.Status)
+	return nil
+}
+
+// tradeBuyCmd represents the trade buy command
+var tradeBuyCmd = &cobra.Command{
+	Use:   "buy <pair> <quantity>",
+	Short: "Place a buy order, at --price or at market price",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return placeOrder(exchange.Buy, args)
+	},
+}
+
+// tradeSellCmd represents the trade sell command
+var tradeSellCmd = &cobra.Command{
+	Use:   "sell <pair> <quantity>",
+	Short: "Place a sell order, at --price or at market price",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return placeOrder(exchange.Sell, args)
+	},
+}
+
+// tradeCancelCmd represents the trade cancel command
+var tradeCancelCmd = &cobra.Command{
+	Use:   "cancel <order id>",
+	Short: "Cancel an open order, once confirmed",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !tradeYes {
+			fmt.Fprintf(os.Stderr, "Type yes to cancel order %s: ", args[0])
+			answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+			if strings.TrimSpace(strings.ToLower(answer)) != "yes" {
+				return fmt.Errorf("order not cancelled")
+			}
+		}
+
+		order, err := exchange.CancelOrder(context.Background(), args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Order %s is %s\n", order.ID, order.Status)
+		return nil
+	},
+}
+
+// tradeOrdersCmd represents the trade orders command
+var tradeOrdersCmd = &cobra.Command{
+	Use:   "orders",
+	Short: "Track orders, and place or cancel them, on the orders page",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !exchange.TradingEnabled {
+			return fmt.Errorf("trading is disabled, set trading.enabled to true in the config file to enable it")
+		}
+
+		// Context and errgroup used to manage routines
+		eg, ctx := errgroup.WithContext(context.Background())
+		dataChannel := make(chan exchange.OrdersData)
+
+		// Fetch statuses of open orders
+		eg.Go(func() error {
+			return exchange.GetOrders(ctx, dataChannel)
+		})
+
+		// Display UI for orders
+		eg.Go(func() error {
+			return orders.DisplayOrders(ctx, tradeExchange, tradeDryRun, dataChannel)
+		})
+
+		if err := eg.Wait(); err != nil {
+			if err.Error() != "UI Closed" {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	tradeCmd.PersistentFlags().StringVarP(&tradeExchange, "exchange", "e", "binance", "Exchange to place orders on")
+	tradeCmd.PersistentFlags().BoolVar(&tradeDryRun, "dry-run", false, "Validate orders with the exchange without placing them")
+	for _, c := range []*cobra.Command{tradeBuyCmd, tradeSellCmd, tradeCancelCmd} {
+		c.Flags().BoolVarP(&tradeYes, "yes", "y", false, "Skip typing yes to confirm")
+	}
+	tradeBuyCmd.Flags().Float64VarP(&tradePrice, "price", "p", 0, "Limit price, leave out for a market order")
+	tradeSellCmd.Flags().Float64VarP(&tradePrice, "price", "p", 0, "Limit price, leave out for a market order")
+
+	tradeCmd.AddCommand(tradeConnectCmd, tradeDisconnectCmd, tradeBuyCmd, tradeSellCmd, tradeCancelCmd, tradeOrdersCmd)
+	rootCmd.AddCommand(tradeCmd)
+}
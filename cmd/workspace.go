@@ -0,0 +1,283 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Gituser143/cryptgo/pkg/display/layout"
+	"github.com/Gituser143/cryptgo/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	workspacePage     string
+	workspaceCoins    []string
+	workspaceCurrency string
+	workspaceHide     []string
+)
+
+// pageRunners open the pages workspaces can be opened on, till closed
+var pageRunners = map[string]func() error{
+	"main":      runMain,
+	"portfolio": runPortfolio,
+	"exchanges": runExchanges,
+}
+
+// runPages opens a page, and the page of each workspace switched to from it,
+// till one is closed. The open workspace keeps the coins and currency it
+// was left with.
+func runPages(page string) error {
+	// Keep the open workspace if cryptgo is terminated too
+	defer utils.OnExit(keepWorkspace)()
+
+	for {
+		err := pageRunners[page]()
+
+		var switched utils.SwitchWorkspace
+		if !errors.As(err, &switched) {
+			keepWorkspace()
+			if err != nil && err.Error() != "UI Closed" {
+				return err
+			}
+			return nil
+		}
+
+		w, err := openWorkspace(switched.Name)
+		if err != nil {
+			return err
+		}
+		page = w.Page
+	}
+}
+
+// keepWorkspace saves the coins and currency the open workspace was left
+// with, if it sets them
+func keepWorkspace() {
+	if utils.ActiveWorkspace == "" {
+		return
+	}
+	w, err := utils.GetWorkspace(utils.ActiveWorkspace)
+	if err != nil {
+		return
+	}
+
+	if len(w.Coins) > 0 {
+		w.Coins = []string{}
+		for id := range utils.GetFavourites() {
+			w.Coins = append(w.Coins, id)
+		}
+		sort.Strings(w.Coins)
+	}
+	if w.Currency != "" {
+		w.Currency = utils.GetCurrency()
+	}
+
+	utils.SaveWorkspace(w)
+}
+
+// openWorkspace keeps the open workspace and opens the one named, setting
+// favourites, currency and layout to its own
+func openWorkspace(name string) (utils.Workspace, error) {
+	w, err := utils.GetWorkspace(name)
+	if err != nil {
+		return w, err
+	}
+	keepWorkspace()
+
+	favourites := utils.GetFavourites()
+	if len(w.Coins) > 0 {
+		favourites = map[string]bool{}
+		for _, id := range w.Coins {
+			favourites[id] = true
+		}
+	}
+	currency := utils.GetCurrency()
+	if w.Currency != "" {
+		currency = w.Currency
+	}
+	if err := utils.SaveMetadata(favourites, currency, utils.GetPortfolio()); err != nil {
+		return w, err
+	}
+
+	// Re-apply config, so layouts of the last workspace are dropped
+	utils.ActiveWorkspace = w.Name
+	if err := applyConfig(); err != nil {
+		return w, err
+	}
+
+	return w, utils.SetLastWorkspace(w.Name)
+}
+
+// workspaceLayout hides widgets hidden by the open workspace in layouts of
+// the config file, by profile and page
+func workspaceLayout(layouts map[string]map[string][]string) error {
+	if utils.ActiveWorkspace == "" {
+		return nil
+	}
+	w, err := utils.GetWorkspace(utils.ActiveWorkspace)
+	if err != nil || len(w.Hide) == 0 {
+		return nil
+	}
+
+	for profile, hide := range layouts {
+		for page, names := range w.Hide {
+			hide[page] = names
+		}
+		if err := layout.Check(profile, hide); err != nil {
+			return fmt.Errorf("invalid layout of workspace %s: %v", w.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// parseHide reads widgets to hide, given as page.widget, Eg: coin.explorers
+func parseHide(names []string) (map[string][]string, error) {
+	hide := map[string][]string{}
+	for _, name := range names {
+		parts := strings.SplitN(name, ".", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid widget %q, expected page.widget, Eg: coin.explorers", name)
+		}
+
+		widgets, ok := layout.Widgets[parts[0]]
+		if !ok {
+			return nil, fmt.Errorf("unknown page %q, expected main or coin", parts[0])
+		}
+		known := false
+		for _, widget := range widgets {
+			known = known || widget == parts[1]
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown widget %q of the %s page, expected one of: %s", parts[1], parts[0], strings.Join(widgets, ", "))
+		}
+
+		hide[parts[0]] = append(hide[parts[0]], parts[1])
+	}
+	return hide, nil
+}
+
+// workspaceCmd represents the workspace command
+var workspaceCmd = &cobra.Command{
+	Use:   "workspace",
+	Short: "Save and open workspaces",
+	Long: `The workspace command saves workspaces, bundling a page with the favourite
+coins, currency and hidden widgets it is shown with, Eg: "BTC trading" or
+"Portfolio review". Pressing W on the main, portfolio or exchanges page
+switches to the next workspace, and each workspace keeps the coins and
+currency it was left with`,
+}
+
+// workspaceListCmd represents the workspace list command
+var workspaceListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved workspaces",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		last := utils.LastWorkspace()
+		for _, w := range utils.GetWorkspaces() {
+			details := []string{"page: " + w.Page}
+			if len(w.Coins) > 0 {
+				details = append(details, "coins: "+strings.Join(w.Coins, ", "))
+			}
+			if w.Currency != "" {
+				details = append(details, "currency: "+w.Currency)
+			}
+			pages := []string{}
+			for page := range w.Hide {
+				pages = append(pages, page)
+			}
+			sort.Strings(pages)
+			for _, page := range pages {
+				details = append(details, fmt.Sprintf("%s hides: %s", page, strings.Join(w.Hide[page], ", ")))
+			}
+
+			marker := " "
+			if w.Name == last {
+				marker = "*"
+			}
+			fmt.Printf("%s %s (%s)\n", marker, w.Name, strings.Join(details, "; "))
+		}
+	},
+}
+
+// workspaceSaveCmd represents the workspace save command
+var workspaceSaveCmd = &cobra.Command{
+	Use:   "save <name>",
+	Short: "Save a workspace, replacing one of the same name",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		hide, err := parseHide(workspaceHide)
+		if err != nil {
+			return err
+		}
+
+		return utils.SaveWorkspace(utils.Workspace{
+			Name:     args[0],
+			Page:     workspacePage,
+			Coins:    workspaceCoins,
+			Currency: workspaceCurrency,
+			Hide:     hide,
+		})
+	},
+}
+
+// workspaceDeleteCmd represents the workspace delete command
+var workspaceDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Remove a workspace",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return utils.DeleteWorkspace(args[0])
+	},
+}
+
+// workspaceOpenCmd represents the workspace open command
+var workspaceOpenCmd = &cobra.Command{
+	Use:   "open [name]",
+	Short: "Open a workspace, by default the one last opened",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := utils.LastWorkspace()
+		if len(args) > 0 {
+			name = args[0]
+		}
+		if name == "" {
+			return fmt.Errorf("no workspace was opened before, give the name of one")
+		}
+
+		w, err := openWorkspace(name)
+		if err != nil {
+			return err
+		}
+		return runPages(w.Page)
+	},
+}
+
+func init() {
+	workspaceSaveCmd.Flags().StringVar(&workspacePage, "page", "main", "page to open on, one of: "+strings.Join(utils.WorkspacePages, ", "))
+	workspaceSaveCmd.Flags().StringSliceVar(&workspaceCoins, "coins", nil, "CoinGecko IDs of favourite coins (default keeps favourites as they are)")
+	workspaceSaveCmd.Flags().StringVar(&workspaceCurrency, "currency", "", "currency ID, Eg: euro (default keeps the currency as it is)")
+	workspaceSaveCmd.Flags().StringSliceVar(&workspaceHide, "hide", nil, "widgets to hide as page.widget, Eg: coin.explorers (default is the layout of the config file)")
+
+	workspaceCmd.AddCommand(workspaceListCmd, workspaceSaveCmd, workspaceDeleteCmd, workspaceOpenCmd)
+	rootCmd.AddCommand(workspaceCmd)
+}
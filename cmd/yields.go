@@ -0,0 +1,60 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+	"github.com/Gituser143/cryptgo/pkg/display/yields"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+)
+
+// yieldsCmd represents the yields command
+var yieldsCmd = &cobra.Command{
+	Use:   "yields",
+	Short: "Compare stablecoin yields with funding rates",
+	Long: `The yields command shows stablecoin lending/earn rates aggregated from
+DefiLlama, compared with current funding rates of major perpetuals`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Context and errgroup used to manage routines
+		eg, ctx := errgroup.WithContext(context.Background())
+		dataChannel := make(chan api.YieldData)
+
+		// Fetch yields and funding rates
+		eg.Go(func() error {
+			return api.GetYields(ctx, dataChannel)
+		})
+
+		// Display UI for yields
+		eg.Go(func() error {
+			return yields.DisplayYields(ctx, dataChannel)
+		})
+
+		if err := eg.Wait(); err != nil {
+			if err.Error() != "UI Closed" {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(yieldsCmd)
+}
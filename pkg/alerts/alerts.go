@@ -0,0 +1,481 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alerts
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
+)
+
+// Kinds of alerts
+const (
+	Above  = "above"  // Price rises to or above Value (USD)
+	Below  = "below"  // Price falls to or below Value (USD)
+	Change = "change" // 24 hour change moves by at least Value % either way
+	Move   = "move"   // Price moves by at least Value % either way within Window
+)
+
+// Watchlists alert templates can be applied to
+const (
+	Favourites = "favourites" // Favourite coins
+	Holdings   = "holdings"   // Coins in the portfolio
+	Watchlists = "watchlists" // Coins in watchlist files
+	All        = "all"        // Every coin
+)
+
+// templateRefresh is how often watchlists of templates are re-read
+const templateRefresh = time.Duration(10) * time.Second
+
+// Template is an alert applied to every coin of a watchlist, Eg: a 7% daily
+// move of any favourite. Coins in Exclude (CoinGecko IDs) are left out.
+type Template struct {
+	Watchlist string   `json:"watchlist" mapstructure:"watchlist"`
+	Kind      string   `json:"kind" mapstructure:"kind"`
+	Value     float64  `json:"value" mapstructure:"value"`
+	Exclude   []string `json:"exclude" mapstructure:"exclude"`
+}
+
+// Alert is a price threshold of a coin, specified by its CoinGecko ID. Move
+// alerts also have the window moves are measured within, and are triggered
+// again once Cooldown has passed, which is Window if not set.
+type Alert struct {
+	Coin     string        `json:"coin" mapstructure:"coin"`
+	Kind     string        `json:"kind" mapstructure:"kind"`
+	Value    float64       `json:"value" mapstructure:"value"`
+	Window   time.Duration `json:"window,omitempty" mapstructure:"window"`
+	Cooldown time.Duration `json:"cooldown,omitempty" mapstructure:"cooldown"`
+}
+
+// String describes the alert, Eg: "> 70000", "< 60000", "±5%" or "±3% in
+// 15m"
+func (a Alert) String() string {
+	switch a.Kind {
+	case Above:
+		return fmt.Sprintf("> %g", a.Value)
+	case Below:
+		return fmt.Sprintf("< %g", a.Value)
+	case Move:
+		return fmt.Sprintf("±%g%% in %s", a.Value, shortDuration(a.Window))
+	default:
+		return fmt.Sprintf("±%g%%", a.Value)
+	}
+}
+
+// shortDuration formats a duration without trailing zero units, Eg: 15m
+// rather than 15m0s
+func shortDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// met returns true if the alert's condition holds for the given price and
+// 24 hour change
+func (a Alert) met(price, change24h float64) bool {
+	switch a.Kind {
+	case Above:
+		return price >= a.Value
+	case Below:
+		return price <= a.Value
+	case Change:
+		return math.Abs(change24h) >= a.Value
+	}
+	return false
+}
+
+// Parse parses an alert entered by the user: ">70000" (above), "<60000"
+// (below), "%5" (24 hour change of 5%) or "~3/15m" (move of 3% within any
+// 15 minutes)
+func Parse(coin, s string) (Alert, error) {
+	s = strings.TrimSpace(s)
+	if len(s) < 2 {
+		return Alert{}, fmt.Errorf("invalid alert %q", s)
+	}
+
+	kinds := map[byte]string{'>': Above, '<': Below, '%': Change, '~': Move}
+	kind, ok := kinds[s[0]]
+	if !ok {
+		return Alert{}, fmt.Errorf("invalid alert %q, expected >price, <price, %%change or ~move/window", s)
+	}
+
+	valueStr := s[1:]
+	window := time.Duration(0)
+	if kind == Move {
+		parts := strings.SplitN(valueStr, "/", 2)
+		if len(parts) != 2 {
+			return Alert{}, fmt.Errorf("invalid alert %q, expected ~move/window, Eg: ~3/15m", s)
+		}
+		var err error
+		window, err = time.ParseDuration(strings.TrimSpace(parts[1]))
+		if err != nil || window <= 0 {
+			return Alert{}, fmt.Errorf("invalid window of alert %q, expected a duration, Eg: 15m", s)
+		}
+		valueStr = parts[0]
+	}
+
+	value, err := strconv.ParseFloat(strings.TrimSpace(valueStr), 64)
+	if err != nil || value <= 0 {
+		return Alert{}, fmt.Errorf("invalid alert %q, expected a positive number", s)
+	}
+
+	return Alert{Coin: coin, Kind: kind, Value: value, Window: window}, nil
+}
+
+// Triggered is an alert whose condition was met
+type Triggered struct {
+	Alert
+	Symbol string
+	Price  float64
+	Change float64 // 24 hour change, or the move of move alerts
+}
+
+// Message describes the triggered alert, Eg: "BTC above 70000 (70012.5)"
+func (t Triggered) Message() string {
+	switch t.Kind {
+	case Change:
+		return fmt.Sprintf("%s moved %.2f%% in 24h", t.Symbol, t.Change)
+	case Move:
+		return fmt.Sprintf("%s moved %.2f%% in %s (%g)", t.Symbol, t.Change, shortDuration(t.Window), t.Price)
+	default:
+		return fmt.Sprintf("%s %s %g (%g)", t.Symbol, t.Kind, t.Value, t.Price)
+	}
+}
+
+// store holds alerts from the config file and those set in the UI, along
+// with templates expanded to coins of their watchlists. Alerts are edge
+// triggered, they fire when their condition starts to hold and are re-armed
+// once it stops holding.
+var store = struct {
+	sync.Mutex
+	config     []Alert
+	saved      []Alert
+	templates  []Template
+	members    map[string]map[string]bool
+	expandedAt time.Time
+	active     map[Alert]bool
+	loaded     bool
+}{active: make(map[Alert]bool)}
+
+// SetConfigAlerts sets alerts read from the config file
+func SetConfigAlerts(alerts []Alert) {
+	store.Lock()
+	defer store.Unlock()
+	store.config = alerts
+}
+
+// SetTemplates sets alert templates read from the config file
+func SetTemplates(templates []Template) {
+	store.Lock()
+	defer store.Unlock()
+	store.templates = templates
+	store.expandedAt = time.Time{}
+}
+
+// members returns coins of a watchlist, All is matched by every coin
+func members(watchlist string) map[string]bool {
+	switch watchlist {
+	case Favourites:
+		return utils.GetFavourites()
+	case Holdings:
+		coins := map[string]bool{}
+		for id := range utils.GetPortfolio() {
+			coins[id] = true
+		}
+		return coins
+	case Watchlists:
+		return utils.GetWatchlists(utils.WatchlistDir)
+	}
+	return map[string]bool{}
+}
+
+// coinAlerts returns alerts of a coin from the config file, the UI and
+// templates, store must be locked. Watchlists of templates are re-read every
+// templateRefresh, so templates follow changes to favourites and holdings.
+func coinAlerts(coin string) []Alert {
+	load()
+
+	if time.Since(store.expandedAt) > templateRefresh {
+		store.members = make(map[string]map[string]bool)
+		for _, template := range store.templates {
+			if _, ok := store.members[template.Watchlist]; !ok && template.Watchlist != All {
+				store.members[template.Watchlist] = members(template.Watchlist)
+			}
+		}
+		store.expandedAt = time.Now()
+	}
+
+	alerts := []Alert{}
+	for _, alert := range append(append([]Alert{}, store.config...), store.saved...) {
+		if alert.Coin == coin {
+			alerts = append(alerts, alert)
+		}
+	}
+
+	for _, template := range store.templates {
+		if template.Watchlist != All && !store.members[template.Watchlist][coin] {
+			continue
+		}
+		excluded := false
+		for _, id := range template.Exclude {
+			if id == coin {
+				excluded = true
+			}
+		}
+		if !excluded {
+			alerts = append(alerts, Alert{Coin: coin, Kind: template.Kind, Value: template.Value})
+		}
+	}
+
+	// Drop duplicates, Eg: a template expanding to an alert set in the UI
+	unique := []Alert{}
+	seen := map[Alert]bool{}
+	for _, alert := range alerts {
+		if !seen[alert] {
+			seen[alert] = true
+			unique = append(unique, alert)
+		}
+	}
+
+	return unique
+}
+
+// load reads saved alerts once, store must be locked
+func load() {
+	if store.loaded {
+		return
+	}
+	store.loaded = true
+	store.saved = readAlerts("cryptgo-alerts.json")
+}
+
+// Get returns alerts of a coin, from the config file, the UI and templates
+func Get(coin string) []Alert {
+	store.Lock()
+	defer store.Unlock()
+
+	return coinAlerts(coin)
+}
+
+// Add saves an alert set from the UI
+func Add(alert Alert) error {
+	store.Lock()
+	defer store.Unlock()
+	load()
+
+	for _, saved := range store.saved {
+		if saved == alert {
+			return nil
+		}
+	}
+	store.saved = append(store.saved, alert)
+	return writeAlerts("cryptgo-alerts.json", store.saved)
+}
+
+// Clear removes alerts of a coin set from the UI. Alerts from the config
+// file and templates are kept.
+func Clear(coin string) error {
+	store.Lock()
+	defer store.Unlock()
+	load()
+
+	saved := []Alert{}
+	for _, alert := range store.saved {
+		if alert.Coin != coin {
+			saved = append(saved, alert)
+		}
+	}
+	store.saved = saved
+	return writeAlerts("cryptgo-alerts.json", store.saved)
+}
+
+// Check checks alerts of a coin against its current USD price and 24 hour
+// change, returning the alerts which were triggered. Prices rejected as
+// glitches of the provider are left unchecked.
+func Check(coin, symbol string, price, change24h float64) []Triggered {
+	if !utils.AcceptPrice("alert "+coin, price) {
+		return nil
+	}
+
+	store.Lock()
+	defer store.Unlock()
+
+	triggered := []Triggered{}
+	for _, alert := range coinAlerts(coin) {
+		met := alert.met(price, change24h)
+		if met && !store.active[alert] {
+			triggered = append(triggered, Triggered{
+				Alert:  alert,
+				Symbol: strings.ToUpper(symbol),
+				Price:  price,
+				Change: change24h,
+			})
+		}
+		store.active[alert] = met
+	}
+
+	return triggered
+}
+
+// Met returns alerts of a coin whose condition holds for its current USD
+// price and 24 hour change, whether or not they were triggered before, Eg:
+// to report every alert met on a single check
+func Met(coin, symbol string, price, change24h float64) []Triggered {
+	if !utils.AcceptPrice("alert "+coin, price) {
+		return nil
+	}
+
+	store.Lock()
+	defer store.Unlock()
+
+	met := []Triggered{}
+	for _, alert := range coinAlerts(coin) {
+		if alert.met(price, change24h) {
+			met = append(met, Triggered{
+				Alert:  alert,
+				Symbol: strings.ToUpper(symbol),
+				Price:  price,
+				Change: change24h,
+			})
+		}
+	}
+
+	return met
+}
+
+// Coins returns CoinGecko IDs of coins alerts are set on, from the config
+// file, the UI and templates, sorted. all is true if a template applies to
+// every coin, which can't be listed.
+func Coins() (coins []string, all bool) {
+	store.Lock()
+	defer store.Unlock()
+	load()
+
+	set := map[string]bool{}
+	for _, alert := range append(append([]Alert{}, store.config...), store.saved...) {
+		set[alert.Coin] = true
+	}
+	for _, template := range store.templates {
+		if template.Watchlist == All {
+			all = true
+			continue
+		}
+		for coin := range members(template.Watchlist) {
+			set[coin] = true
+		}
+	}
+
+	for coin := range set {
+		coins = append(coins, coin)
+	}
+	sort.Strings(coins)
+
+	return coins, all
+}
+
+// LoadActive restores alerts whose condition held when SaveActive was last
+// called, so alerts checked by separate runs, Eg: of check-alerts from
+// cron, are only triggered when their condition starts to hold
+func LoadActive() {
+	store.Lock()
+	defer store.Unlock()
+
+	for _, alert := range readAlerts("cryptgo-alerts-state.json") {
+		store.active[alert] = true
+	}
+}
+
+// SaveActive saves alerts whose condition held when last checked, to be
+// restored by LoadActive
+func SaveActive() error {
+	store.Lock()
+	defer store.Unlock()
+
+	active := []Alert{}
+	for alert, met := range store.active {
+		if met {
+			active = append(active, alert)
+		}
+	}
+	sort.Slice(active, func(i, j int) bool {
+		return active[i].Coin+" "+active[i].String() < active[j].Coin+" "+active[j].String()
+	})
+
+	return writeAlerts("cryptgo-alerts-state.json", active)
+}
+
+// readAlerts reads alerts saved to a hidden file in the home directory, Eg:
+// those set from the UI from ~/.cryptgo-alerts.json
+func readAlerts(name string) []Alert {
+	alerts := []Alert{}
+
+	// Get home directory
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return alerts
+	}
+
+	// Open file
+	alertsFile, err := os.Open(homeDir + "/." + name)
+	if err != nil {
+		return alerts
+	}
+	defer alertsFile.Close()
+
+	// Read content
+	err = json.NewDecoder(alertsFile).Decode(&alerts)
+	if err != nil {
+		return []Alert{}
+	}
+
+	return alerts
+}
+
+// writeAlerts saves alerts to a hidden file in the home directory, Eg: those
+// set from the UI to ~/.cryptgo-alerts.json
+func writeAlerts(name string, alerts []Alert) error {
+	// Get Home directory
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+
+	// Visible and hidden paths are used for the same reason as in
+	// utils.SaveMetadata
+	filePath := homeDir + "/" + name
+	hiddenPath := homeDir + "/." + name
+
+	data, err := json.MarshalIndent(alerts, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	return utils.WriteFile(filePath, hiddenPath, data)
+}
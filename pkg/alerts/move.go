@@ -0,0 +1,144 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alerts
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
+)
+
+// priceTick is a price of a coin at a time
+type priceTick struct {
+	at    time.Time
+	price float64
+}
+
+// moves holds recent prices of coins with move alerts, and when each move
+// alert was last triggered
+var moves = struct {
+	prices map[string][]priceTick
+	fired  map[Alert]time.Time
+}{
+	prices: make(map[string][]priceTick),
+	fired:  make(map[Alert]time.Time),
+}
+
+// moveAlerts returns move alerts of a coin, store must be locked
+func moveAlerts(coin string) []Alert {
+	alerts := []Alert{}
+	for _, alert := range coinAlerts(coin) {
+		if alert.Kind == Move && alert.Window > 0 {
+			alerts = append(alerts, alert)
+		}
+	}
+	return alerts
+}
+
+// MoveCoins returns CoinGecko IDs of coins with move alerts, to stream
+// prices of
+func MoveCoins() []string {
+	coins, _ := Coins()
+
+	store.Lock()
+	defer store.Unlock()
+
+	moving := []string{}
+	for _, coin := range coins {
+		if len(moveAlerts(coin)) > 0 {
+			moving = append(moving, coin)
+		}
+	}
+	return moving
+}
+
+// CheckMoves records a USD price of a coin, Eg: from a live stream, and
+// checks its move alerts against prices recorded within their windows. An
+// alert is triggered once the price is Value % above the lowest or below
+// the highest price within its window, and not again till its cooldown has
+// passed. Prices rejected as glitches of the provider are left out.
+func CheckMoves(coin, symbol string, price float64, at time.Time) []Triggered {
+	if price <= 0 || !utils.AcceptPrice("move "+coin, price) {
+		return nil
+	}
+
+	store.Lock()
+	defer store.Unlock()
+
+	alerts := moveAlerts(coin)
+	if len(alerts) == 0 {
+		delete(moves.prices, coin)
+		return nil
+	}
+
+	// Keep prices within the longest window, at most one a second as
+	// streams can tick many times a second
+	longest := time.Duration(0)
+	for _, alert := range alerts {
+		if alert.Window > longest {
+			longest = alert.Window
+		}
+	}
+	ticks := moves.prices[coin]
+	if len(ticks) == 0 || at.Sub(ticks[len(ticks)-1].at) >= time.Second {
+		ticks = append(ticks, priceTick{at: at, price: price})
+	}
+	for len(ticks) > 0 && at.Sub(ticks[0].at) > longest {
+		ticks = ticks[1:]
+	}
+	moves.prices[coin] = ticks
+
+	triggered := []Triggered{}
+	for _, alert := range alerts {
+		cooldown := alert.Cooldown
+		if cooldown <= 0 {
+			cooldown = alert.Window
+		}
+		if fired, ok := moves.fired[alert]; ok && at.Sub(fired) < cooldown {
+			continue
+		}
+
+		low, high := price, price
+		for _, tick := range ticks {
+			if at.Sub(tick.at) <= alert.Window {
+				low = math.Min(low, tick.price)
+				high = math.Max(high, tick.price)
+			}
+		}
+
+		// The larger of the rise from the low and the fall from the high
+		move := (price - low) / low * 100
+		if fall := (price - high) / high * 100; -fall > move {
+			move = fall
+		}
+		if math.Abs(move) < alert.Value {
+			continue
+		}
+
+		moves.fired[alert] = at
+		triggered = append(triggered, Triggered{
+			Alert:  alert,
+			Symbol: strings.ToUpper(symbol),
+			Price:  price,
+			Change: move,
+		})
+	}
+
+	return triggered
+}
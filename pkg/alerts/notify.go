@@ -0,0 +1,69 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alerts
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// DesktopNotifications enables desktop notifications of triggered alerts
+var DesktopNotifications = false
+
+// Notify sends a notification of triggered alerts to the desktop, if
+// desktop notifications are enabled, to Telegram, if a chat is set, and to
+// the webhook, if its URL is set. The alerts the message describes, if any,
+// are posted to the webhook along with it. Each is tried even if others
+// fail.
+func Notify(title, message string, triggered ...Triggered) error {
+	var err error
+	if DesktopNotifications {
+		err = notifyDesktop(title, message)
+	}
+	if TelegramChat != "" {
+		if telegramErr := sendTelegram(title, message); telegramErr != nil {
+			err = telegramErr
+		}
+	}
+	if WebhookURL != "" {
+		if webhookErr := sendWebhook(title, message, triggered); webhookErr != nil {
+			err = webhookErr
+		}
+	}
+	return err
+}
+
+// notifyDesktop sends a desktop notification using notify-send on Linux or
+// osascript on macOS
+func notifyDesktop(title, message string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		// Escape quotes for AppleScript strings
+		quote := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+		script := fmt.Sprintf(`display notification "%s" with title "%s"`, quote.Replace(message), quote.Replace(title))
+		cmd = exec.Command("osascript", "-e", script)
+	case "windows":
+		return fmt.Errorf("desktop notifications are not supported on windows")
+	default:
+		cmd = exec.Command("notify-send", title, message)
+	}
+
+	return cmd.Run()
+}
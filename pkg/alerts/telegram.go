@@ -0,0 +1,78 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alerts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
+)
+
+// TelegramChat is the ID of the chat triggered alerts are sent to by the
+// Telegram bot whose token is stored as the telegram API key, Eg: 123456789
+// or @channel. Alerts aren't sent to Telegram if it is empty.
+var TelegramChat = ""
+
+// telegramURL is the base URL of the Telegram Bot API
+const telegramURL = "https://api.telegram.org"
+
+// telegramClient sends messages, giving up on Telegram after a while so
+// alerts aren't held up
+var telegramClient = &http.Client{Timeout: time.Duration(10) * time.Second}
+
+// telegramResponse holds the outcome of a Bot API request
+type telegramResponse struct {
+	OK          bool   `json:"ok"`
+	Description string `json:"description"`
+}
+
+// sendTelegram sends a message to TelegramChat. Errors leave out the URL,
+// which holds the bot token.
+func sendTelegram(title, message string) error {
+	token, _ := utils.GetAPIKey("telegram")
+	if token == "" {
+		return fmt.Errorf("no Telegram bot token, store one with: cryptgo apikey set telegram")
+	}
+
+	res, err := telegramClient.PostForm(fmt.Sprintf("%s/bot%s/sendMessage", telegramURL, token), url.Values{
+		"chat_id": {TelegramChat},
+		"text":    {title + ": " + message},
+	})
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("failed to send Telegram message: %v", err)
+	}
+	defer res.Body.Close()
+
+	data := telegramResponse{}
+	if err := json.NewDecoder(res.Body).Decode(&data); err != nil || !data.OK {
+		if data.Description == "" {
+			data.Description = res.Status
+		}
+		return fmt.Errorf("failed to send Telegram message: %s", data.Description)
+	}
+
+	return nil
+}
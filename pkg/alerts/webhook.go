@@ -0,0 +1,101 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alerts
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// WebhookURL is the URL triggered alerts are posted to as JSON, Eg: a Slack
+// or Discord incoming webhook, or an ntfy topic. Alerts aren't posted if it
+// is empty.
+var WebhookURL = ""
+
+// webhookClient posts alerts, giving up after a while so alerts aren't held
+// up
+var webhookClient = &http.Client{Timeout: time.Duration(10) * time.Second}
+
+// webhookPayload is the JSON posted to WebhookURL. The message is sent as
+// both text and content, which Slack and Discord show as is.
+type webhookPayload struct {
+	Title     string         `json:"title"`
+	Text      string         `json:"text"`
+	Content   string         `json:"content"`
+	Timestamp time.Time      `json:"timestamp"`
+	Alerts    []webhookAlert `json:"alerts"`
+}
+
+// webhookAlert describes a triggered alert in webhookPayload
+type webhookAlert struct {
+	Coin         string  `json:"coin"`
+	Symbol       string  `json:"symbol"`
+	Rule         string  `json:"rule"`
+	Kind         string  `json:"kind"`
+	Value        float64 `json:"value"`
+	PriceUSD     float64 `json:"price_usd"`
+	Change24hPct float64 `json:"change_24h_pct"`
+}
+
+// sendWebhook posts triggered alerts to WebhookURL. Errors leave out the
+// URL, which often holds a secret token.
+func sendWebhook(title, message string, triggered []Triggered) error {
+	payload := webhookPayload{
+		Title:     title,
+		Text:      message,
+		Content:   message,
+		Timestamp: time.Now().UTC(),
+		Alerts:    []webhookAlert{},
+	}
+	for _, t := range triggered {
+		payload.Alerts = append(payload.Alerts, webhookAlert{
+			Coin:         t.Coin,
+			Symbol:       t.Symbol,
+			Rule:         t.String(),
+			Kind:         t.Kind,
+			Value:        t.Value,
+			PriceUSD:     t.Price,
+			Change24hPct: t.Change,
+		})
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	res, err := webhookClient.Post(WebhookURL, "application/json", bytes.NewReader(data))
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("failed to post alerts to the webhook: %v", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("failed to post alerts to the webhook: %s", res.Status)
+	}
+
+	return nil
+}
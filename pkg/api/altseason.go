@@ -0,0 +1,207 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
+	geckoTypes "github.com/superoo7/go-gecko/v3/types"
+)
+
+const (
+	altseasonCoins  = 50
+	altseasonWindow = 90 // days over which performance is compared
+)
+
+// altseasonExcluded holds stablecoins and wrapped/staked assets which track
+// another asset and hence are not considered for the altseason index
+var altseasonExcluded = map[string]bool{
+	"bitcoin":           true,
+	"tether":            true,
+	"usd-coin":          true,
+	"dai":               true,
+	"binance-usd":       true,
+	"true-usd":          true,
+	"paxos-standard":    true,
+	"first-digital-usd": true,
+	"ethena-usde":       true,
+	"usdd":              true,
+	"frax":              true,
+	"paypal-usd":        true,
+	"wrapped-bitcoin":   true,
+	"staked-ether":      true,
+	"wrapped-steth":     true,
+	"weth":              true,
+}
+
+// dailyPrices returns daily prices of history, keyed by the day (days since
+// unix epoch)
+func dailyPrices(history []geckoTypes.ChartItem) map[int64]float64 {
+	prices := make(map[int64]float64)
+	for _, v := range history {
+		day := int64(v[0]) / (24 * 60 * 60 * 1000)
+		prices[day] = float64(v[1])
+	}
+
+	return prices
+}
+
+// windowChange returns the change in price over altseasonWindow days ending
+// on the given day, and false if either price is missing
+func windowChange(prices map[int64]float64, day int64) (float64, bool) {
+	end, ok := prices[day]
+	if !ok {
+		return 0, false
+	}
+
+	start, ok := prices[day-altseasonWindow]
+	if !ok || start == 0 {
+		return 0, false
+	}
+
+	return end/start - 1, true
+}
+
+// computeAltseasonHistory returns the altseason index for every day on which
+// bitcoin's windowed change is known. The index for a day is the percentage
+// of coins which outperformed bitcoin over the window ending on that day.
+func computeAltseasonHistory(btcPrices map[int64]float64, coinPrices []map[int64]float64) []float64 {
+	// Find range of days
+	first, last := int64(-1), int64(-1)
+	for day := range btcPrices {
+		if first == -1 || day < first {
+			first = day
+		}
+		if day > last {
+			last = day
+		}
+	}
+
+	history := []float64{}
+	for day := first + altseasonWindow; day <= last; day++ {
+		btcChange, ok := windowChange(btcPrices, day)
+		if !ok {
+			continue
+		}
+
+		total, outperformers := 0, 0
+		for _, prices := range coinPrices {
+			change, ok := windowChange(prices, day)
+			if !ok {
+				continue
+			}
+			total++
+			if change > btcChange {
+				outperformers++
+			}
+		}
+
+		if total > 0 {
+			history = append(history, float64(outperformers)*100/float64(total))
+		}
+	}
+
+	return history
+}
+
+// GetAltseasonIndex serves the altseason index for the main page, which is
+// the percentage of top 50 coins that outperformed bitcoin over 90 days.
+// Histories are fetched by a pool of HistoryWorkers, within rate limits,
+// and the result is held back while data sending is paused.
+func GetAltseasonIndex(ctx context.Context, dataChannel chan AssetData, sendData *bool) error {
+
+	return utils.LoopTick(ctx, time.Duration(1)*time.Hour, func(errChan chan error) {
+		var finalErr error = nil
+		data := AssetData{}
+
+		defer func() {
+			if finalErr != nil {
+				errChan <- finalErr
+			}
+		}()
+
+		// Fetch top coins
+		coinsData, err := geckoSource{}.GetTopCoins(altseasonCoins + len(altseasonExcluded))
+		if err != nil {
+			finalErr = err
+			return
+		}
+
+		// Pick top coins, leaving out excluded ones
+		ids := []CoinID{{CoinGeckoID: "bitcoin", Symbol: "BTC"}}
+		for _, val := range coinsData {
+			if len(ids) == altseasonCoins+1 {
+				break
+			}
+			if !altseasonExcluded[val.ID] {
+				ids = append(ids, CoinID{CoinGeckoID: val.ID, Symbol: val.Symbol})
+			}
+		}
+
+		// Fetch enough history to compute the index over the last window
+		days := 2 * altseasonWindow
+		histories, err := getHistories(ctx, geckoSource{}, ids, days)
+		if err != nil {
+			finalErr = err
+			return
+		}
+
+		btc := histories["bitcoin"]
+		if btc.Err != nil {
+			finalErr = btc.Err
+			return
+		}
+		btcPrices := dailyPrices(btc.History)
+
+		// Coins whose history can't be fetched are left out
+		coinPrices := []map[int64]float64{}
+		for _, id := range ids[1:] {
+			if result, ok := histories[id.CoinGeckoID]; ok && result.Err == nil {
+				coinPrices = append(coinPrices, dailyPrices(result.History))
+			}
+		}
+
+		history := computeAltseasonHistory(btcPrices, coinPrices)
+		if len(history) == 0 {
+			return
+		}
+
+		// Aggregate data
+		data.IsAltseasonData = true
+		data.AltseasonHistory = history
+		data.AltseasonIndex = history[len(history)-1]
+
+		for !*sendData {
+			select {
+			case <-ctx.Done():
+				finalErr = ctx.Err()
+				return
+			case <-time.After(time.Second):
+			}
+		}
+
+		// Send data
+		select {
+		case <-ctx.Done():
+			finalErr = ctx.Err()
+			return
+		case dataChannel <- data:
+		}
+	})
+}
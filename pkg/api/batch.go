@@ -0,0 +1,114 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// assetBatchWindow is how long asset requests are collected for before
+// being sent as a single request
+const assetBatchWindow = time.Duration(100) * time.Millisecond
+
+// assetResult holds the result of a batched asset request
+type assetResult struct {
+	asset CoinCapAsset
+	err   error
+}
+
+// assetBatcher coalesces CoinCap asset requests made around the same time
+// (Eg: by different widgets) into a single /assets?ids= request. Besides
+// saving requests, assets fetched together share the same timestamp.
+type assetBatcher struct {
+	sync.Mutex
+	pending map[string][]chan assetResult
+}
+
+var coincapAssets = &assetBatcher{}
+
+// get returns the asset specified by a CoinCap ID, waiting for the batch it
+// is added to
+func (b *assetBatcher) get(id string) (CoinCapAsset, error) {
+	result := make(chan assetResult, 1)
+
+	b.Lock()
+	if len(b.pending) == 0 {
+		b.pending = make(map[string][]chan assetResult)
+		time.AfterFunc(assetBatchWindow, b.flush)
+	}
+	b.pending[id] = append(b.pending[id], result)
+	b.Unlock()
+
+	r := <-result
+	return r.asset, r.err
+}
+
+// flush fetches all pending assets in a single request
+func (b *assetBatcher) flush() {
+	b.Lock()
+	pending := b.pending
+	b.pending = nil
+	b.Unlock()
+
+	ids := []string{}
+	for id := range pending {
+		ids = append(ids, id)
+	}
+
+	assets, err := getCoinCapAssets(ids)
+
+	for id, results := range pending {
+		r := assetResult{err: err}
+		if err == nil {
+			asset, ok := assets[id]
+			if ok {
+				r.asset = asset
+			} else {
+				r.err = fmt.Errorf("%w on CoinCap", ErrNotListed)
+			}
+		}
+
+		for _, result := range results {
+			result <- r
+		}
+	}
+}
+
+// getCoinCapAssets fetches assets specified by CoinCap IDs in a single
+// request, returning them by ID
+func getCoinCapAssets(ids []string) (map[string]CoinCapAsset, error) {
+	sort.Strings(ids)
+
+	url := fmt.Sprintf("%s/assets?ids=%s", coincapURL, strings.Join(ids, ","))
+
+	data := CoinCapData{}
+	err := getJSON(url, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	assets := make(map[string]CoinCapAsset)
+	for _, asset := range data.Data {
+		assets[asset.ID] = asset
+	}
+
+	return assets, nil
+}
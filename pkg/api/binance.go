@@ -0,0 +1,184 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	geckoTypes "github.com/superoo7/go-gecko/v3/types"
+)
+
+const binanceURL = "https://api.binance.com/api/v3"
+
+// binanceSource serves price history and live trades of a coin's USDT pair
+// from Binance. Binance has no market data (Eg: market cap), so the coin
+// table and summaries are served from CoinGecko.
+type binanceSource struct {
+	geckoSource
+}
+
+// binanceTrade holds a trade received over the Binance trade stream
+type binanceTrade struct {
+	Price string `json:"p"`
+}
+
+// binancePair returns the USDT pair of a coin, Eg: BTCUSDT
+func binancePair(id CoinID) (string, error) {
+	if id.Symbol == "" || id.Symbol == "USDT" {
+		return "", fmt.Errorf("%w on Binance", ErrNotListed)
+	}
+	return id.Symbol + "USDT", nil
+}
+
+// Name returns the name of the source
+func (binanceSource) Name() string {
+	return "binance"
+}
+
+// GetHistory returns the USDT price history of a coin over the given number
+// of days
+func (s binanceSource) GetHistory(id CoinID, days int) ([]geckoTypes.ChartItem, error) {
+	end := time.Now()
+	return s.GetHistoryRange(id, end.AddDate(0, 0, -days), end)
+}
+
+// GetHistoryRange returns the USDT price history of a coin between start and
+// end, built from the close of Binance klines
+func (binanceSource) GetHistoryRange(id CoinID, start, end time.Time) ([]geckoTypes.ChartItem, error) {
+	pair, err := binancePair(id)
+	if err != nil {
+		return nil, err
+	}
+
+	days := rangeDays(start, end)
+
+	interval := "1w"
+	switch {
+	case days <= 1:
+		interval = "5m"
+	case days <= 7:
+		interval = "1h"
+	case days <= 14:
+		interval = "2h"
+	case days <= 30:
+		interval = "6h"
+	case days <= 90:
+		interval = "12h"
+	case days <= 365:
+		interval = "1d"
+	}
+
+	// Binance names intervals with the unit last
+	if g := granularityFor(days); g != AutoGranularity {
+		interval = g[1:] + g[:1]
+	}
+
+	url := fmt.Sprintf("%s/klines?symbol=%s&interval=%s&startTime=%d&endTime=%d&limit=1000",
+		binanceURL, pair, interval, start.UnixNano()/1e6, end.UnixNano()/1e6)
+
+	// Klines are arrays of open time, open, high, low, close, ...
+	klines := [][]interface{}{}
+	err = getJSON(url, &klines)
+	if err != nil {
+		// Binance responds with 400 for unknown pairs
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && statusErr.Code == http.StatusBadRequest {
+			return nil, fmt.Errorf("%w on Binance as %s", ErrNotListed, pair)
+		}
+		return nil, err
+	}
+
+	history := []geckoTypes.ChartItem{}
+	for _, kline := range klines {
+		if len(kline) < 5 {
+			continue
+		}
+
+		openTime, ok := kline[0].(float64)
+		if !ok {
+			continue
+		}
+
+		closePrice, ok := kline[4].(string)
+		if !ok {
+			continue
+		}
+
+		price, err := strconv.ParseFloat(closePrice, 64)
+		if err != nil {
+			continue
+		}
+
+		history = append(history, geckoTypes.ChartItem{float32(openTime), float32(price)})
+	}
+
+	return history, nil
+}
+
+// GetLivePrice streams the price of every trade of a coin's USDT pair. The
+// socket is reconnected if it drops.
+func (binanceSource) GetLivePrice(ctx context.Context, id CoinID, dataChannel chan string) error {
+	pair, err := binancePair(id)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("wss://stream.binance.com:9443/ws/%s@trade", strings.ToLower(pair))
+
+	return reconnect(ctx, func(connected, received func()) error {
+		c, _, err := websocket.DefaultDialer.DialContext(ctx, url, nil)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+		connected()
+
+		// Unblock reads once cancelled
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			select {
+			case <-ctx.Done():
+				c.Close()
+			case <-done:
+			}
+		}()
+
+		// Trades are read in a plain loop rather than on a tick, to forward
+		// them as soon as they are made
+		trade := binanceTrade{}
+		for {
+			if err := c.ReadJSON(&trade); err != nil {
+				return err
+			}
+			received()
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case dataChannel <- trade.Price:
+			}
+		}
+	})
+}
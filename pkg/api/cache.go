@@ -0,0 +1,270 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
+)
+
+// CacheEnabled determines if responses are cached
+var CacheEnabled = true
+
+// CacheDir is the directory responses are also cached in, to survive
+// restarts. Responses are only cached in memory if empty.
+var CacheDir = ""
+
+// cacheSettings returns CacheEnabled and CacheDir, read under the settings
+// lock
+func cacheSettings() (bool, string) {
+	utils.RLockSettings()
+	defer utils.RUnlockSettings()
+	return CacheEnabled, CacheDir
+}
+
+// cacheRule sets how long responses of endpoints matching pattern are
+// cached for. History endpoints are cached for longer the longer the span
+// of history requested, as older points don't change.
+type cacheRule struct {
+	pattern *regexp.Regexp
+	ttl     time.Duration
+	history bool
+}
+
+// cacheRules list endpoints whose responses are cached, others are always
+// fetched. Live data (details, candles, order books) is never cached.
+var cacheRules = []cacheRule{
+	{pattern: regexp.MustCompile(`^api\.coingecko\.com/api/v3/coins/[^/]+/market_chart$`), history: true},
+	{pattern: regexp.MustCompile(`^api\.coincap\.io/v2/assets/[^/]+/history$`), history: true},
+	{pattern: regexp.MustCompile(`^api\.binance\.com/api/v3/klines$`), history: true},
+	{pattern: regexp.MustCompile(`^api\.coingecko\.com/api/v3/coins/list$`), ttl: time.Duration(24) * time.Hour},
+	{pattern: regexp.MustCompile(`^api\.coincap\.io/v2/rates$`), ttl: time.Duration(10) * time.Minute},
+	{pattern: regexp.MustCompile(`^api\.frankfurter\.app/`), ttl: time.Duration(1) * time.Hour},
+}
+
+// historyTTL returns how long a history spanning days is cached for
+func historyTTL(days int) time.Duration {
+	switch {
+	case days <= 1:
+		return time.Duration(1) * time.Minute
+	case days <= 90:
+		return time.Duration(15) * time.Minute
+	default:
+		return time.Duration(1) * time.Hour
+	}
+}
+
+// cacheEntry holds a cached response
+type cacheEntry struct {
+	Expires time.Time   `json:"expires"`
+	Status  int         `json:"status"`
+	Header  http.Header `json:"header"`
+	Body    []byte      `json:"body"`
+}
+
+// responseCache serves GET requests to endpoints in cacheRules from memory
+// or CacheDir until their TTL expires
+type responseCache struct {
+	sync.Mutex
+	next    http.RoundTripper
+	entries map[string]cacheEntry
+}
+
+var httpCache = &responseCache{
+	next:    httpLimiter,
+	entries: make(map[string]cacheEntry),
+}
+
+// requestKey returns the key a request to u is cached by, along with the
+// number of days of history it requests. Timestamps of history ending now
+// change on every request, so they are replaced by the span of time between
+// them. Other timestamps are kept, rounded down to the TTL of the history so
+// requests for the same period share a key while different periods don't.
+func requestKey(u *url.URL) (string, int) {
+	query := u.Query()
+
+	days, _ := strconv.Atoi(query.Get("days"))
+
+	startKey, endKey := "start", "end"
+	if query.Get(startKey) == "" {
+		startKey, endKey = "startTime", "endTime"
+	}
+	startMs, err := strconv.ParseInt(query.Get(startKey), 10, 64)
+	if err != nil {
+		return u.Host + u.Path + "?" + query.Encode(), days
+	}
+
+	nowMs := time.Now().UnixNano() / 1e6
+	endMs, err := strconv.ParseInt(query.Get(endKey), 10, 64)
+	if err != nil {
+		endMs = nowMs
+	}
+	days = int((time.Duration(endMs-startMs)*time.Millisecond + 12*time.Hour) / (24 * time.Hour))
+
+	ttlMs := int64(historyTTL(days) / time.Millisecond)
+	if nowMs-endMs < ttlMs {
+		query.Del(startKey)
+		query.Del(endKey)
+		query.Set("span", fmt.Sprintf("%dd", days))
+	} else {
+		query.Set(startKey, strconv.FormatInt(startMs-startMs%ttlMs, 10))
+		query.Set(endKey, strconv.FormatInt(endMs-endMs%ttlMs, 10))
+	}
+
+	return u.Host + u.Path + "?" + query.Encode(), days
+}
+
+// cachePath returns the path a response is cached at in dir
+func cachePath(dir, key string) string {
+	return filepath.Join(dir, fmt.Sprintf("%x.json", sha256.Sum256([]byte(key))))
+}
+
+// get returns a cached response which hasn't expired, from memory or dir
+func (c *responseCache) get(dir, key string) (cacheEntry, bool) {
+	c.Lock()
+	entry, ok := c.entries[key]
+	c.Unlock()
+
+	if !ok && dir != "" {
+		data, err := os.ReadFile(cachePath(dir, key))
+		if err == nil && json.Unmarshal(data, &entry) == nil {
+			ok = true
+			c.Lock()
+			c.entries[key] = entry
+			c.Unlock()
+		}
+	}
+
+	return entry, ok && time.Now().Before(entry.Expires)
+}
+
+// set caches a response in memory and dir, if not empty
+func (c *responseCache) set(dir, key string, entry cacheEntry) {
+	c.Lock()
+	c.entries[key] = entry
+	c.Unlock()
+
+	if dir == "" {
+		return
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return
+	}
+	os.WriteFile(cachePath(dir, key), data, 0644)
+}
+
+// RoundTrip serves a request from the cache, sending it and caching the
+// response if it isn't cached
+func (c *responseCache) RoundTrip(req *http.Request) (*http.Response, error) {
+	enabled, dir := cacheSettings()
+	if !enabled || req.Method != http.MethodGet {
+		return c.next.RoundTrip(req)
+	}
+
+	// Get TTL of endpoint
+	var ttl time.Duration
+	key, days := requestKey(req.URL)
+	for _, rule := range cacheRules {
+		if rule.pattern.MatchString(req.URL.Host + req.URL.Path) {
+			ttl = rule.ttl
+			if rule.history {
+				ttl = historyTTL(days)
+			}
+			break
+		}
+	}
+	if ttl == 0 {
+		return c.next.RoundTrip(req)
+	}
+
+	if entry, ok := c.get(dir, key); ok {
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", entry.Status, http.StatusText(entry.Status)),
+			StatusCode:    entry.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        entry.Header,
+			Body:          io.NopCloser(bytes.NewReader(entry.Body)),
+			ContentLength: int64(len(entry.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	res, err := c.next.RoundTrip(req)
+	if err != nil || res.StatusCode != http.StatusOK {
+		return res, err
+	}
+
+	// Read body and hand a copy back to the caller
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	res.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return res, nil
+	}
+
+	c.set(dir, key, cacheEntry{
+		Expires: time.Now().Add(ttl),
+		Status:  res.StatusCode,
+		Header:  res.Header,
+		Body:    body,
+	})
+
+	return res, nil
+}
+
+// ClearCache drops all cached responses, so data is fetched afresh
+func ClearCache() error {
+	httpCache.Lock()
+	httpCache.entries = make(map[string]cacheEntry)
+	httpCache.Unlock()
+
+	_, dir := cacheSettings()
+	if dir == "" {
+		return nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if err := os.Remove(file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
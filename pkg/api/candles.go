@@ -0,0 +1,156 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
+)
+
+// CandleTimeframes lists the timeframes candles can be fetched in
+var CandleTimeframes = []string{"1m", "15m", "1h", "1d"}
+
+// candleIntervals maps timeframes to CoinCap candle intervals and the period
+// they span
+var candleIntervals = map[string]struct {
+	Interval string
+	Period   time.Duration
+}{
+	"1m":  {"m1", time.Minute},
+	"15m": {"m15", time.Duration(15) * time.Minute},
+	"1h":  {"h1", time.Hour},
+	"1d":  {"d1", time.Duration(24) * time.Hour},
+}
+
+// candleCount is the number of candles fetched per request
+const candleCount = 120
+
+// coincapCandles holds candles of a market from CoinCap
+type coincapCandles struct {
+	Data []struct {
+		Open   string `json:"open"`
+		High   string `json:"high"`
+		Low    string `json:"low"`
+		Close  string `json:"close"`
+		Period int64  `json:"period"`
+	} `json:"data"`
+}
+
+// GetCandles returns the latest candles of a coin's Binance USDT market in the
+// given timeframe from CoinCap. Coins without such a market have no candles.
+func GetCandles(id CoinID, timeframe string) ([]Candle, error) {
+	interval, ok := candleIntervals[timeframe]
+	if !ok {
+		return nil, fmt.Errorf("unknown timeframe %q", timeframe)
+	}
+
+	end := time.Now()
+	start := end.Add(-candleCount * interval.Period)
+
+	url := fmt.Sprintf("%s/candles?exchange=binance&interval=%s&baseId=%s&quoteId=tether&start=%d&end=%d",
+		coincapURL, interval.Interval, id.CoinCapID, start.UnixNano()/1e6, end.UnixNano()/1e6)
+
+	data := coincapCandles{}
+	if err := getJSON(url, &data); err != nil {
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && (statusErr.Code == http.StatusBadRequest || statusErr.Code == http.StatusNotFound) {
+			return nil, fmt.Errorf("%w on CoinCap candles", ErrNotListed)
+		}
+		return nil, err
+	}
+
+	parse := func(s string) float64 {
+		val, _ := strconv.ParseFloat(s, 64)
+		return val
+	}
+
+	candles := []Candle{}
+	for _, c := range data.Data {
+		candles = append(candles, Candle{
+			Time:  time.Unix(0, c.Period*int64(time.Millisecond)),
+			Open:  parse(c.Open),
+			High:  parse(c.High),
+			Low:   parse(c.Low),
+			Close: parse(c.Close),
+		})
+	}
+
+	return candles, nil
+}
+
+// GetCoinCandles fetches candles of a coin every refreshInterval, in the
+// timeframe received through the timeframe channel, and sends them on
+// dataChannel. Nothing is fetched till a timeframe is received, and an empty
+// timeframe stops fetching again.
+func GetCoinCandles(ctx context.Context, id CoinID, refreshInterval time.Duration, timeframeChannel chan string, dataChannel chan CoinData) error {
+	timeframe := ""
+
+	return utils.LoopTick(ctx, refreshInterval, func(errChan chan error) {
+		var finalErr error = nil
+
+		defer func() {
+			if finalErr != nil {
+				errChan <- finalErr
+			}
+		}()
+
+		select {
+		case <-ctx.Done():
+			finalErr = ctx.Err()
+			return
+		case t := <-timeframeChannel:
+			// Update timeframe
+			timeframe = t
+		default:
+			break
+		}
+
+		if timeframe == "" {
+			return
+		}
+
+		// Candles are optional, so no candles are shown while CoinCap is
+		// unavailable rather than closing the coin page
+		candles, err := GetCandles(id, timeframe)
+		trackStatus("candles", refreshInterval, err)
+		if err != nil && !isUnavailable(err) {
+			finalErr = err
+			return
+		}
+
+		// Aggregate data
+		coinData := CoinData{
+			Type:      "CANDLES",
+			Candles:   candles,
+			Timeframe: timeframe,
+		}
+
+		// Send Data
+		select {
+		case <-ctx.Done():
+			finalErr = ctx.Err()
+			return
+		case dataChannel <- coinData:
+		}
+	})
+}
@@ -0,0 +1,167 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Capabilities describe what a provider serves beyond market data and
+// history, so pages hide or adapt features it lacks rather than failing to
+// fetch them
+type Capabilities struct {
+	Websocket     bool     // Live prices are streamed rather than polled
+	OHLC          bool     // Candles of markets
+	Tickers       bool     // Markets of coins on exchanges, with their volume
+	OrderBook     bool     // Bids and asks of markets
+	Granularities []string // History granularities which can be chosen, none if picked from the days requested
+}
+
+// providerCapabilities holds capabilities of providers by name
+var providerCapabilities = map[string]Capabilities{
+	"coingecko": {Tickers: true},
+	"coincap":   {Websocket: true, OHLC: true, Tickers: true, Granularities: Granularities()},
+	"binance":   {Websocket: true, OHLC: true, OrderBook: true, Granularities: Granularities()},
+	"coinbase":  {},
+	"kraken":    {},
+}
+
+// sourceCapabilities holds capabilities of sources by name, which may
+// combine providers, Eg: the default source streams prices from CoinCap
+var sourceCapabilities = map[string]Capabilities{
+	"default":   {Websocket: true, Tickers: true},
+	"coingecko": providerCapabilities["coingecko"],
+	"coincap":   providerCapabilities["coincap"],
+	"binance":   providerCapabilities["binance"],
+}
+
+// providerTitles are names of providers as shown on pages
+var providerTitles = map[string]string{
+	"coingecko": "CoinGecko",
+	"coincap":   "CoinCap",
+	"binance":   "Binance",
+}
+
+// feature describes an optional feature of the coin page, served by a
+// provider regardless of the selected source
+type feature struct {
+	provider string
+	serves   func(Capabilities) bool
+	lists    func(CoinID) bool
+}
+
+// features holds optional features of the coin page by the type of data
+// they show
+var features = map[string]feature{
+	"candles": {
+		provider: "coincap",
+		serves:   func(c Capabilities) bool { return c.OHLC },
+		lists:    func(id CoinID) bool { return id.CoinCapID != "" },
+	},
+	"order book": {
+		provider: "binance",
+		serves:   func(c Capabilities) bool { return c.OrderBook },
+		lists: func(id CoinID) bool {
+			_, err := binancePair(id)
+			return err == nil
+		},
+	},
+	"markets": {
+		provider: "coincap",
+		serves:   func(c Capabilities) bool { return c.Tickers },
+		lists:    func(id CoinID) bool { return id.CoinCapID != "" },
+	},
+	"volume": {
+		provider: "coingecko",
+		serves:   func(c Capabilities) bool { return c.Tickers },
+		lists:    func(id CoinID) bool { return id.CoinGeckoID != "" },
+	},
+}
+
+// providerTitle returns the name of a provider as shown on pages
+func providerTitle(name string) string {
+	if title, ok := providerTitles[name]; ok {
+		return title
+	}
+	return name
+}
+
+// SourceCapabilities returns the capabilities of a source, none if it was
+// registered without any
+func SourceCapabilities(src Source) Capabilities {
+	sourceMutex.RLock()
+	defer sourceMutex.RUnlock()
+	return sourceCapabilities[src.Name()]
+}
+
+// FeatureUnavailable returns why a feature of the coin page, named by the
+// type of data it shows, can't be shown for a coin, Eg: "no candles: ETH is
+// not listed on CoinCap". It returns an empty string if it can be shown.
+func FeatureUnavailable(name string, id CoinID) string {
+	f, ok := features[name]
+	if !ok {
+		return ""
+	}
+
+	sourceMutex.RLock()
+	caps := providerCapabilities[f.provider]
+	sourceMutex.RUnlock()
+
+	switch {
+	case !f.serves(caps):
+		return fmt.Sprintf("no %s: %s does not serve them", name, providerTitle(f.provider))
+	case !f.lists(id):
+		return fmt.Sprintf("no %s: %s is not listed on %s", name, id.Symbol, providerTitle(f.provider))
+	}
+	return ""
+}
+
+// Limitations returns why features of the coin page are unavailable or
+// adapted for a coin served from src, Eg: live prices being polled from a
+// source which can't stream them
+func Limitations(src Source, id CoinID) []string {
+	caps := SourceCapabilities(src)
+	limits := []string{}
+
+	if !caps.Websocket {
+		limits = append(limits, fmt.Sprintf("live price polled: %s does not stream prices", src.Name()))
+	}
+
+	if granularity := getHistoryGranularity(); granularity != AutoGranularity {
+		supported := false
+		for _, g := range caps.Granularities {
+			supported = supported || g == granularity
+		}
+		if !supported {
+			limits = append(limits, fmt.Sprintf("history granularity %s: %s picks its own", granularity, src.Name()))
+		}
+	}
+
+	names := []string{}
+	for name := range features {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if reason := FeatureUnavailable(name, id); reason != "" {
+			limits = append(limits, reason)
+		}
+	}
+
+	return limits
+}
@@ -0,0 +1,180 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	geckoTypes "github.com/superoo7/go-gecko/v3/types"
+)
+
+// CardVersion is the schema version of coin cards. Fields may be added to a
+// version, but are never renamed, removed or changed in meaning or type
+// without bumping it.
+const CardVersion = 1
+
+// Card is a snapshot of a coin meant to be consumed by other tools. Prices
+// are always in USD, regardless of the selected currency.
+type Card struct {
+	// Version is the schema version, see CardVersion
+	Version int `json:"version"`
+	// GeneratedAt is when the card was created
+	GeneratedAt time.Time `json:"generated_at"`
+
+	// ID is the CoinGecko ID of the coin
+	ID     string `json:"id"`
+	Symbol string `json:"symbol"`
+	Name   string `json:"name"`
+	// Rank by market cap, 0 if the coin is unranked
+	Rank int `json:"rank"`
+
+	PriceUSD     float64 `json:"price_usd"`
+	Change24hPct float64 `json:"change_24h_pct"`
+	Change7dPct  float64 `json:"change_7d_pct"`
+	MarketCapUSD float64 `json:"market_cap_usd"`
+	Volume24hUSD float64 `json:"volume_24h_usd"`
+
+	// Sparkline7dUSD holds hourly prices over the last 7 days, oldest first
+	Sparkline7dUSD []float64 `json:"sparkline_7d_usd"`
+
+	Links CardLinks `json:"links"`
+
+	// LastUpdated is when the market data was last updated by CoinGecko
+	LastUpdated time.Time `json:"last_updated"`
+}
+
+// CardLinks holds links of a coin. Lists are empty rather than null when a
+// coin has no such links.
+type CardLinks struct {
+	Homepage  []string `json:"homepage"`
+	Explorers []string `json:"explorers"`
+	CoinGecko string   `json:"coingecko"`
+}
+
+// GetCoinCard creates a card of a coin specified by its CoinGecko ID or
+// symbol. Market data is always served from CoinGecko.
+func GetCoinCard(coin string) (Card, error) {
+	geckoClient := NewGeckoClient()
+
+	// Set Parameters
+	localization := false
+	tickers := false
+	marketData := true
+	communityData := false
+	developerData := false
+	sparkline := true
+
+	id := strings.ToLower(strings.TrimSpace(ResolveAlias(coin)))
+	coinData, err := geckoClient.CoinsID(id, localization, tickers, marketData, communityData, developerData, sparkline)
+	if err != nil {
+		// Retry with the coin taken as a symbol
+		symbolID, symbolErr := coinIDOfSymbol(id)
+		if symbolErr != nil {
+			return Card{}, fmt.Errorf("%s: %v", coin, err)
+		}
+		coinData, err = geckoClient.CoinsID(symbolID, localization, tickers, marketData, communityData, developerData, sparkline)
+		if err != nil {
+			return Card{}, err
+		}
+	}
+
+	if coinData.MarketData == nil {
+		return Card{}, fmt.Errorf("no market data for %s", coin)
+	}
+	market := coinData.MarketData
+
+	card := Card{
+		Version:        CardVersion,
+		GeneratedAt:    time.Now().UTC(),
+		ID:             coinData.ID,
+		Symbol:         strings.ToUpper(coinData.Symbol),
+		Name:           coinData.Name,
+		Rank:           int(coinData.MarketCapRank),
+		PriceUSD:       market.CurrentPrice["usd"],
+		Change24hPct:   market.PriceChangePercentage24h,
+		Change7dPct:    market.PriceChangePercentage7d,
+		MarketCapUSD:   market.MarketCap["usd"],
+		Volume24hUSD:   market.TotalVolume["usd"],
+		Sparkline7dUSD: []float64{},
+		Links: CardLinks{
+			Homepage:  linkList(coinData.Links, "homepage"),
+			Explorers: linkList(coinData.Links, "blockchain_site"),
+			CoinGecko: "https://www.coingecko.com/en/coins/" + coinData.ID,
+		},
+	}
+
+	if market.Sparkline != nil {
+		card.Sparkline7dUSD = append(card.Sparkline7dUSD, market.Sparkline.Price...)
+	}
+
+	if lastUpdated, err := time.Parse(time.RFC3339, coinData.LastUpdated); err == nil {
+		card.LastUpdated = lastUpdated.UTC()
+	}
+
+	return card, nil
+}
+
+// coinIDOfSymbol returns the CoinGecko ID of the coin listed with symbol.
+// Top coins are preferred, as symbols aren't unique.
+func coinIDOfSymbol(symbol string) (string, error) {
+	topCoins, err := geckoSource{}.GetTopCoins(250)
+	if err == nil {
+		for _, coin := range topCoins {
+			if strings.EqualFold(coin.Symbol, symbol) {
+				return coin.ID, nil
+			}
+		}
+	}
+
+	geckoClient := NewGeckoClient()
+
+	list, err := geckoClient.CoinsList()
+	if err != nil {
+		return "", err
+	}
+
+	for _, coin := range *list {
+		if strings.EqualFold(coin.Symbol, symbol) {
+			return coin.ID, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w on CoinGecko", ErrNotListed)
+}
+
+// linkList returns the non empty links listed under key
+func linkList(links *geckoTypes.LinksItem, key string) []string {
+	list := []string{}
+	if links == nil {
+		return list
+	}
+
+	sites, ok := (*links)[key].([]interface{})
+	if !ok {
+		return list
+	}
+
+	for _, site := range sites {
+		if siteStr, ok := site.(string); ok && siteStr != "" {
+			list = append(list, siteStr)
+		}
+	}
+
+	return list
+}
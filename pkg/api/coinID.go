@@ -21,6 +21,8 @@ import (
 	"net/http"
 	"strings"
 	"sync"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
 )
 
 func NewCoinIDMap() CoinIDMap {
@@ -28,7 +30,10 @@ func NewCoinIDMap() CoinIDMap {
 	return c
 }
 
+// Populate fills the map with IDs of top coins, starting from those of the
+// bundled index so coins are found even if requests fail
 func (c *CoinIDMap) Populate() {
+	c.seedIndex()
 
 	var m sync.Mutex
 	var wg sync.WaitGroup
@@ -41,14 +46,12 @@ func (c *CoinIDMap) Populate() {
 		url := "https://api.coincap.io/v2/assets?limit=2000"
 		method := "GET"
 
-		client := &http.Client{}
-
 		req, err := http.NewRequest(method, url, nil)
 		if err != nil {
 			return
 		}
 
-		res, err := client.Do(req)
+		res, err := Client.Do(req)
 		if err != nil {
 			return
 		}
@@ -67,10 +70,12 @@ func (c *CoinIDMap) Populate() {
 				(*IDMap)[val.Symbol] = CoinID{
 					CoinCapID:   val.ID,
 					CoinGeckoID: (*IDMap)[val.Symbol].CoinGeckoID,
+					Symbol:      val.Symbol,
 				}
 			} else {
 				(*IDMap)[val.Symbol] = CoinID{
 					CoinCapID: val.ID,
+					Symbol:    val.Symbol,
 				}
 			}
 			m.Unlock()
@@ -80,7 +85,7 @@ func (c *CoinIDMap) Populate() {
 	go func(IDMap *CoinIDMap, m *sync.Mutex, wg *sync.WaitGroup) {
 		defer wg.Done()
 
-		coinPtr, err := getTopNCoins(200)
+		coinPtr, err := geckoSource{}.GetTopCoins(200)
 		if err != nil {
 			return
 		}
@@ -92,10 +97,12 @@ func (c *CoinIDMap) Populate() {
 				(*IDMap)[symbol] = CoinID{
 					CoinGeckoID: val.ID,
 					CoinCapID:   (*IDMap)[symbol].CoinCapID,
+					Symbol:      symbol,
 				}
 			} else {
 				(*IDMap)[symbol] = CoinID{
 					CoinGeckoID: val.ID,
+					Symbol:      symbol,
 				}
 			}
 			m.Unlock()
@@ -104,3 +111,55 @@ func (c *CoinIDMap) Populate() {
 
 	wg.Wait()
 }
+
+// FindCoinID returns IDs of a coin given by its symbol, CoinGecko ID or
+// CoinCap ID. Coins which aren't found are assumed to be given by an ID
+// used by both APIs.
+func FindCoinID(coin string) CoinID {
+	coinIDs := NewCoinIDMap()
+	coinIDs.Populate()
+
+	return coinIDs.Find(coin)
+}
+
+// Aliases maps shorthands of coins to their CoinGecko IDs, Eg: xbt to
+// bitcoin, set from the config file. Aliases are lower case.
+var Aliases = map[string]string{}
+
+// aliasMap returns Aliases, read under the settings lock. Aliases is
+// replaced rather than changed on reload, so the map returned can be read
+// after.
+func aliasMap() map[string]string {
+	utils.RLockSettings()
+	defer utils.RUnlockSettings()
+	return Aliases
+}
+
+// ResolveAlias returns the CoinGecko ID of coin if it is an alias, or coin
+// as it is otherwise
+func ResolveAlias(coin string) string {
+	if id, ok := aliasMap()[strings.ToLower(strings.TrimSpace(coin))]; ok {
+		return id
+	}
+	return coin
+}
+
+// Find returns IDs of a coin given by an alias, its symbol, CoinGecko ID or
+// CoinCap ID from the map. Coins which aren't found are assumed to be given
+// by an ID used by both APIs.
+func (c CoinIDMap) Find(coin string) CoinID {
+	coin = ResolveAlias(coin)
+
+	if id, ok := c[strings.ToUpper(coin)]; ok {
+		return id
+	}
+
+	coin = strings.ToLower(coin)
+	for _, id := range c {
+		if id.CoinGeckoID == coin || id.CoinCapID == coin {
+			return id
+		}
+	}
+
+	return CoinID{CoinGeckoID: coin, CoinCapID: coin}
+}
@@ -0,0 +1,318 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	geckoTypes "github.com/superoo7/go-gecko/v3/types"
+)
+
+const coincapURL = "https://api.coincap.io/v2"
+
+// coincapSource serves data from CoinCap. CoinCap only serves 24 hour
+// changes, so changes over other durations fall back to 24 hours.
+type coincapSource struct{}
+
+// coincapHistory holds price history of an asset from CoinCap
+type coincapHistory struct {
+	Data []struct {
+		PriceUsd string `json:"priceUsd"`
+		Time     int64  `json:"time"`
+	} `json:"data"`
+}
+
+// Name returns the name of the source
+func (coincapSource) Name() string {
+	return "coincap"
+}
+
+// StatusError is returned when an API responds with a non 200 status
+type StatusError struct {
+	URL  string
+	Code int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s returned %d %s", e.URL, e.Code, http.StatusText(e.Code))
+}
+
+// getJSON fetches url and decodes the JSON response into v
+func getJSON(url string, v interface{}) error {
+	res, err := Client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return &StatusError{URL: url, Code: res.StatusCode}
+	}
+
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+// toMarketItem converts a CoinCap asset to a CoinsMarketItem
+func (asset CoinCapAsset) toMarketItem() geckoTypes.CoinsMarketItem {
+	parse := func(s string) float64 {
+		val, _ := strconv.ParseFloat(s, 64)
+		return val
+	}
+
+	item := geckoTypes.CoinsMarketItem{}
+	item.ID = asset.ID
+	item.Symbol = strings.ToLower(asset.Symbol)
+	item.Name = asset.Name
+
+	rank, _ := strconv.Atoi(asset.Rank)
+	item.MarketCapRank = int16(rank)
+	item.CurrentPrice = parse(asset.PriceUsd)
+	item.MarketCap = parse(asset.MarketCapUsd)
+	item.TotalVolume = parse(asset.VolumeUsd24Hr)
+	item.CirculatingSupply = parse(asset.Supply)
+	item.TotalSupply = parse(asset.MaxSupply)
+
+	change := parse(asset.ChangePercent24Hr)
+	item.PriceChangePercentage24h = change
+	item.PriceChangePercentage24hInCurrency = &change
+
+	return item
+}
+
+// GetTopCoins returns market data of the top n coins by market cap
+func (coincapSource) GetTopCoins(n int) (geckoTypes.CoinsMarket, error) {
+	if n > 2000 {
+		return nil, fmt.Errorf("page size limit is 2000")
+	}
+
+	data := CoinCapData{}
+	err := getJSON(fmt.Sprintf("%s/assets?limit=%d", coincapURL, n), &data)
+	if err != nil {
+		return nil, err
+	}
+
+	coinData := geckoTypes.CoinsMarket{}
+	for _, val := range data.Data {
+		coinData = append(coinData, val.toMarketItem())
+	}
+
+	return coinData, nil
+}
+
+// GetAsset returns market data of a coin along with its 7 day sparkline
+func (s coincapSource) GetAsset(id CoinID) (geckoTypes.CoinsMarketItem, error) {
+	if id.CoinCapID == "" {
+		return geckoTypes.CoinsMarketItem{}, fmt.Errorf("%w on CoinCap", ErrNotListed)
+	}
+
+	// Requests are batched with those of other widgets
+	asset, err := coincapAssets.get(id.CoinCapID)
+	if err != nil {
+		return geckoTypes.CoinsMarketItem{}, err
+	}
+
+	item := asset.toMarketItem()
+
+	// Build sparkline from history
+	history, err := s.GetHistory(id, 7)
+	if err != nil {
+		return geckoTypes.CoinsMarketItem{}, err
+	}
+
+	sparkline := &geckoTypes.SparklineItem{}
+	for _, val := range history {
+		sparkline.Price = append(sparkline.Price, float64(val[1]))
+	}
+	item.SparklineIn7d = sparkline
+
+	return item, nil
+}
+
+// GetHistory returns the USD price history of a coin over the given number
+// of days
+func (s coincapSource) GetHistory(id CoinID, days int) ([]geckoTypes.ChartItem, error) {
+	end := time.Now()
+	return s.GetHistoryRange(id, end.AddDate(0, 0, -days), end)
+}
+
+// GetHistoryRange returns the USD price history of a coin between start and
+// end. The interval between points is picked to serve a similar number of
+// points as CoinGecko.
+func (coincapSource) GetHistoryRange(id CoinID, start, end time.Time) ([]geckoTypes.ChartItem, error) {
+	if id.CoinCapID == "" {
+		return nil, fmt.Errorf("%w on CoinCap", ErrNotListed)
+	}
+
+	days := rangeDays(start, end)
+
+	interval := "d1"
+	switch {
+	case days <= 1:
+		interval = "m5"
+	case days <= 7:
+		interval = "h1"
+	case days <= 14:
+		interval = "h2"
+	case days <= 30:
+		interval = "h6"
+	case days <= 90:
+		interval = "h12"
+	}
+
+	if g := granularityFor(days); g != AutoGranularity {
+		interval = g
+	}
+
+	url := fmt.Sprintf("%s/assets/%s/history?interval=%s&start=%d&end=%d",
+		coincapURL, id.CoinCapID, interval, start.UnixNano()/1e6, end.UnixNano()/1e6)
+
+	data := coincapHistory{}
+	err := getJSON(url, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	history := []geckoTypes.ChartItem{}
+	for _, val := range data.Data {
+		price, err := strconv.ParseFloat(val.PriceUsd, 64)
+		if err != nil {
+			continue
+		}
+		history = append(history, geckoTypes.ChartItem{float32(val.Time), float32(price)})
+	}
+
+	return history, nil
+}
+
+// GetLivePrice uses a websocket to stream realtime prices of a coin. The
+// socket is reconnected if it drops.
+func (coincapSource) GetLivePrice(ctx context.Context, id CoinID, dataChannel chan string) error {
+	if id.CoinCapID == "" {
+		return fmt.Errorf("%w on CoinCap", ErrNotListed)
+	}
+
+	url := fmt.Sprintf("wss://ws.coincap.io/prices?assets=%s", id.CoinCapID)
+
+	return reconnect(ctx, func(connected, received func()) error {
+		c, _, err := websocket.DefaultDialer.DialContext(ctx, url, nil)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+		connected()
+
+		// Unblock reads once cancelled
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			select {
+			case <-ctx.Done():
+				c.Close()
+			case <-done:
+			}
+		}()
+
+		msg := make(map[string]string)
+		for {
+			if err := c.ReadJSON(&msg); err != nil {
+				return err
+			}
+			received()
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case dataChannel <- msg[id.CoinCapID]:
+			}
+		}
+	})
+}
+
+// LivePrice is a realtime USD price of a coin
+type LivePrice struct {
+	ID    CoinID
+	Price float64
+	At    time.Time
+}
+
+// StreamPrices streams realtime prices of coins from CoinCap over a single
+// websocket, Eg: to check move alerts. The socket is reconnected if it
+// drops. Coins not listed on CoinCap are left out.
+func StreamPrices(ctx context.Context, ids []CoinID, dataChannel chan LivePrice) error {
+	byCoinCapID := map[string]CoinID{}
+	assets := []string{}
+	for _, id := range ids {
+		if id.CoinCapID != "" {
+			byCoinCapID[id.CoinCapID] = id
+			assets = append(assets, id.CoinCapID)
+		}
+	}
+	if len(assets) == 0 {
+		return fmt.Errorf("%w on CoinCap", ErrNotListed)
+	}
+
+	url := fmt.Sprintf("wss://ws.coincap.io/prices?assets=%s", strings.Join(assets, ","))
+
+	return reconnect(ctx, func(connected, received func()) error {
+		c, _, err := websocket.DefaultDialer.DialContext(ctx, url, nil)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+		connected()
+
+		// Unblock reads once cancelled
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			select {
+			case <-ctx.Done():
+				c.Close()
+			case <-done:
+			}
+		}()
+
+		for {
+			msg := make(map[string]string)
+			if err := c.ReadJSON(&msg); err != nil {
+				return err
+			}
+			received()
+
+			// Messages hold prices of assets which changed since the last
+			// message
+			for coinCapID, val := range msg {
+				price, err := strconv.ParseFloat(val, 64)
+				if err != nil {
+					continue
+				}
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case dataChannel <- LivePrice{ID: byCoinCapID[coinCapID], Price: price, At: time.Now()}:
+				}
+			}
+		}
+	})
+}
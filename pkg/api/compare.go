@@ -0,0 +1,133 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	MinCompareCoins = 2
+	MaxCompareCoins = 5
+)
+
+// getCompareCoin fetches history for an interval spanning days and 24 hour
+// stats of a coin from src
+func getCompareCoin(src Source, id CoinID, days int) (CompareCoin, error) {
+	history, err := src.GetHistory(id, days)
+	if err != nil {
+		return CompareCoin{}, err
+	}
+
+	asset, err := src.GetAsset(id)
+	if err != nil {
+		return CompareCoin{}, err
+	}
+
+	// Normalise history to the change since its first price
+	changes := []float64{}
+	if len(history) > 0 && history[0][1] != 0 {
+		start := float64(history[0][1])
+		for _, v := range history {
+			changes = append(changes, (float64(v[1])/start-1)*100)
+		}
+	}
+
+	return CompareCoin{
+		ID:          id,
+		Symbol:      strings.ToUpper(asset.Symbol),
+		Changes:     changes,
+		Price:       asset.CurrentPrice,
+		Change24h:   asset.PriceChangePercentage24h,
+		High24:      asset.High24,
+		Low24:       asset.Low24,
+		TotalVolume: asset.TotalVolume,
+	}, nil
+}
+
+// GetCompareData fetches histories and 24 hour stats of coins specified by
+// ids from src every refreshInterval, for an interval received through the
+// interval channel. Coins are fetched concurrently and sent together on the
+// dataChannel, in the order of ids.
+func GetCompareData(ctx context.Context, src Source, ids []CoinID, refreshInterval time.Duration, intervalChannel chan string, dataChannel chan CompareData) error {
+	if len(ids) < MinCompareCoins || len(ids) > MaxCompareCoins {
+		return fmt.Errorf("between %d and %d coins can be compared, got %d", MinCompareCoins, MaxCompareCoins, len(ids))
+	}
+
+	// Set Default Interval
+	i := defaultInterval()
+
+	return utils.LoopTick(ctx, refreshInterval, func(errChan chan error) {
+		var finalErr error = nil
+
+		defer func() {
+			if finalErr != nil {
+				errChan <- finalErr
+			}
+		}()
+
+		select {
+		case <-ctx.Done():
+			finalErr = ctx.Err()
+			return
+		case interval := <-intervalChannel:
+			// Update interval
+			i = interval
+		default:
+			break
+		}
+
+		// Fetch coins concurrently
+		coins := make([]CompareCoin, len(ids))
+		eg := errgroup.Group{}
+		for n, id := range ids {
+			n, id := n, id
+			eg.Go(func() error {
+				coin, err := getCompareCoin(src, id, intervalDays[i])
+				if err != nil {
+					return err
+				}
+				coins[n] = coin
+				return nil
+			})
+		}
+		if err := eg.Wait(); err != nil {
+			finalErr = err
+			return
+		}
+
+		// Aggregate data
+		data := CompareData{
+			Interval: i,
+			Coins:    coins,
+		}
+
+		// Send Data
+		select {
+		case <-ctx.Done():
+			finalErr = ctx.Err()
+			return
+		case dataChannel <- data:
+		}
+	})
+}
@@ -0,0 +1,109 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
+)
+
+const (
+	dominanceSnapshots = "dominance"
+	dominanceInterval  = time.Duration(5) * time.Minute
+	// Keep 7 days of snapshots
+	maxDominanceSnapshots = 7 * 24 * 12
+)
+
+// GetDominanceHistory serves the history of BTC dominance (share of total
+// market cap) for the main page. CoinGecko only serves the current
+// dominance, so snapshots are accumulated locally and persisted to disk
+// to build the history across sessions.
+func GetDominanceHistory(ctx context.Context, dataChannel chan AssetData, sendData *bool) error {
+
+	// Init Client
+	geckoClient := NewGeckoClient()
+
+	// Load previously recorded snapshots
+	snapshots := utils.GetSnapshots(dominanceSnapshots)
+
+	return utils.LoopTick(ctx, dominanceInterval, func(errChan chan error) {
+		var finalErr error = nil
+		data := AssetData{}
+
+		defer func() {
+			if finalErr != nil {
+				errChan <- finalErr
+			}
+		}()
+
+		// Fetch Data
+		global, err := geckoClient.Global()
+		if err != nil {
+			finalErr = err
+			return
+		}
+
+		// Record snapshot, unless glitched
+		btcDominance := global.MarketCapPercentage["btc"]
+		if !utils.AcceptPrice("dominance", btcDominance) {
+			return
+		}
+		snapshots = append(snapshots, utils.Snapshot{
+			Time:  time.Now().Unix(),
+			Value: btcDominance,
+		})
+		if len(snapshots) > maxDominanceSnapshots {
+			snapshots = snapshots[len(snapshots)-maxDominanceSnapshots:]
+		}
+		utils.SaveSnapshots(dominanceSnapshots, snapshots)
+
+		if !*sendData {
+			return
+		}
+
+		dominance := make([]float64, len(snapshots))
+		for i, snapshot := range snapshots {
+			dominance[i] = snapshot.Value
+		}
+
+		// Set max and min
+		min := utils.MinFloat64(dominance...)
+		max := utils.MaxFloat64(dominance...)
+
+		// Clean data for graph
+		for i := range dominance {
+			dominance[i] -= min
+		}
+
+		// Aggregate data
+		data.IsDominanceData = true
+		data.DominanceHistory = dominance
+		data.MinDominance = min
+		data.MaxDominance = max
+		data.DominanceSince = time.Unix(snapshots[0].Time, 0)
+
+		// Send data
+		select {
+		case <-ctx.Done():
+			finalErr = ctx.Err()
+			return
+		case dataChannel <- data:
+		}
+	})
+}
@@ -0,0 +1,264 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
+)
+
+const (
+	exchangesRefresh = time.Duration(1) * time.Minute
+	maxExchanges     = 100
+)
+
+// Exchange holds an exchange ranked by volume
+type Exchange struct {
+	ID          string // CoinCap ID, used to fetch markets of the exchange
+	Name        string
+	Rank        int
+	TrustScore  int     // CoinGecko trust score out of 10, 0 if unknown
+	VolumeUSD   float64 // Volume over 24 hours
+	VolumeShare float64 // Share of the total volume of all exchanges, in percent
+	Pairs       int
+}
+
+// coincapExchanges holds exchanges from CoinCap
+type coincapExchanges struct {
+	Data []struct {
+		ExchangeID         string `json:"exchangeId"`
+		Name               string `json:"name"`
+		Rank               string `json:"rank"`
+		PercentTotalVolume string `json:"percentTotalVolume"`
+		VolumeUsd          string `json:"volumeUsd"`
+		TradingPairs       string `json:"tradingPairs"`
+	} `json:"data"`
+}
+
+// geckoExchange holds the trust score of an exchange on CoinGecko
+type geckoExchange struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	TrustScore int    `json:"trust_score"`
+}
+
+// coincapExchangeMarkets holds markets of an exchange from CoinCap
+type coincapExchangeMarkets struct {
+	Data []struct {
+		BaseSymbol            string `json:"baseSymbol"`
+		QuoteSymbol           string `json:"quoteSymbol"`
+		PriceUsd              string `json:"priceUsd"`
+		VolumeUsd24Hr         string `json:"volumeUsd24Hr"`
+		PercentExchangeVolume string `json:"percentExchangeVolume"`
+	} `json:"data"`
+}
+
+// getTrustScores returns CoinGecko trust scores of exchanges, keyed by both
+// their ID and lower cased name as IDs differ between CoinGecko and CoinCap
+func getTrustScores() (map[string]int, error) {
+	body, err := NewGeckoClient().MakeReq(fmt.Sprintf("%s/exchanges?per_page=250", geckoURL))
+	if err != nil {
+		return nil, err
+	}
+
+	exchanges := []geckoExchange{}
+	if err := json.Unmarshal(body, &exchanges); err != nil {
+		return nil, err
+	}
+
+	scores := make(map[string]int)
+	for _, val := range exchanges {
+		scores[val.ID] = val.TrustScore
+		scores[strings.ToLower(val.Name)] = val.TrustScore
+	}
+
+	return scores, nil
+}
+
+// GetExchangeRanks returns the top exchanges on CoinCap by 24 hour volume,
+// along with their trust score on CoinGecko. Trust scores are left unknown
+// if they can't be fetched.
+func GetExchangeRanks(limit int) ([]Exchange, error) {
+	data := coincapExchanges{}
+	err := getJSON(fmt.Sprintf("%s/exchanges", coincapURL), &data)
+	if err != nil {
+		return nil, err
+	}
+
+	scores, err := getTrustScores()
+	if err != nil {
+		scores = map[string]int{}
+	}
+
+	parse := func(s string) float64 {
+		val, _ := strconv.ParseFloat(s, 64)
+		return val
+	}
+
+	exchanges := []Exchange{}
+	for _, val := range data.Data {
+		// Exchanges without volume are no longer tracked
+		if val.VolumeUsd == "" {
+			continue
+		}
+
+		rank, _ := strconv.Atoi(val.Rank)
+		pairs, _ := strconv.Atoi(val.TradingPairs)
+		score, ok := scores[val.ExchangeID]
+		if !ok {
+			score = scores[strings.ToLower(val.Name)]
+		}
+
+		exchanges = append(exchanges, Exchange{
+			ID:          val.ExchangeID,
+			Name:        val.Name,
+			Rank:        rank,
+			TrustScore:  score,
+			VolumeUSD:   parse(val.VolumeUsd),
+			VolumeShare: parse(val.PercentTotalVolume),
+			Pairs:       pairs,
+		})
+	}
+
+	sort.SliceStable(exchanges, func(i, j int) bool {
+		return exchanges[i].VolumeUSD > exchanges[j].VolumeUSD
+	})
+	if len(exchanges) > limit {
+		exchanges = exchanges[:limit]
+	}
+
+	return exchanges, nil
+}
+
+// GetExchangeMarkets returns the top markets of an exchange on CoinCap by 24
+// hour volume. The volume share of a market is its share of the exchange's
+// volume.
+func GetExchangeMarkets(exchange Exchange, limit int) ([]Market, error) {
+	url := fmt.Sprintf("%s/markets?exchangeId=%s&limit=%d", coincapURL, exchange.ID, limit)
+
+	data := coincapExchangeMarkets{}
+	err := getJSON(url, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	parse := func(s string) float64 {
+		val, _ := strconv.ParseFloat(s, 64)
+		return val
+	}
+
+	markets := []Market{}
+	for _, val := range data.Data {
+		markets = append(markets, Market{
+			Exchange:    exchange.Name,
+			Pair:        fmt.Sprintf("%s/%s", val.BaseSymbol, val.QuoteSymbol),
+			PriceUSD:    parse(val.PriceUsd),
+			VolumeUSD:   parse(val.VolumeUsd24Hr),
+			VolumeShare: parse(val.PercentExchangeVolume),
+		})
+	}
+
+	sort.SliceStable(markets, func(i, j int) bool {
+		return markets[i].VolumeUSD > markets[j].VolumeUSD
+	})
+
+	return markets, nil
+}
+
+// GetExchanges serves exchanges ranked by volume every minute. Once an
+// exchange is sent on exchangeChannel, its markets are served every
+// MarketsRefresh until another exchange is sent, or markets are closed by
+// sending an empty exchange.
+func GetExchanges(ctx context.Context, exchangeChannel chan Exchange, dataChannel chan ExchangeData) error {
+	selected := Exchange{}
+	ranked := time.Time{}
+	fetched := time.Time{}
+
+	return utils.LoopTick(ctx, time.Duration(1)*time.Second, func(errChan chan error) {
+		var finalErr error = nil
+
+		defer func() {
+			if finalErr != nil {
+				errChan <- finalErr
+			}
+		}()
+
+		send := func(data ExchangeData) {
+			select {
+			case <-ctx.Done():
+				finalErr = ctx.Err()
+			case dataChannel <- data:
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			finalErr = ctx.Err()
+			return
+		case e := <-exchangeChannel:
+			// Update selection, fetching markets right away
+			selected = e
+			fetched = time.Time{}
+		default:
+			break
+		}
+
+		if time.Since(ranked) >= utils.PollInterval(exchangesRefresh) {
+			ranked = time.Now()
+
+			exchanges, err := GetExchangeRanks(maxExchanges)
+			if err != nil {
+				finalErr = err
+				return
+			}
+
+			send(ExchangeData{
+				Type:      "EXCHANGES",
+				Exchanges: exchanges,
+			})
+			if finalErr != nil {
+				return
+			}
+		}
+
+		if selected.ID == "" || time.Since(fetched) < utils.PollInterval(marketsRefresh()) {
+			return
+		}
+		fetched = time.Now()
+
+		// Markets are left empty while CoinCap is unavailable rather than
+		// closing the page
+		markets, err := GetExchangeMarkets(selected, maxMarkets)
+		if err != nil && !isUnavailable(err) {
+			finalErr = err
+			return
+		}
+
+		send(ExchangeData{
+			Type:     "MARKETS",
+			Exchange: selected,
+			Markets:  markets,
+		})
+	})
+}
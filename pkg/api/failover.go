@@ -0,0 +1,186 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	geckoTypes "github.com/superoo7/go-gecko/v3/types"
+)
+
+// failoverSource serves data from a primary source and falls back to another
+// source when the primary is unavailable (rate limited, erroring or timing
+// out). The primary is retried on every request, so it is used again once
+// it recovers. Live prices fall back to live if set, as the fallback may
+// stream from the same provider as the primary.
+type failoverSource struct {
+	primary  Source
+	fallback Source
+	live     Source
+	state    *failoverState
+}
+
+// newFailoverSource returns a failoverSource serving data from primary, and
+// from fallback while primary is unavailable
+func newFailoverSource(primary, fallback Source) failoverSource {
+	return failoverSource{
+		primary:  primary,
+		fallback: fallback,
+		live:     fallback,
+		state:    &failoverState{active: make(map[string]string)},
+	}
+}
+
+// failoverState records which kinds of requests of a failoverSource were
+// last served by a fallback, and its name
+type failoverState struct {
+	sync.Mutex
+	active map[string]string
+}
+
+// setFallback records whether a kind of request was served by fallback,
+// nil if served by the primary
+func (f failoverSource) setFallback(kind string, fallback Source) {
+	f.state.Lock()
+	defer f.state.Unlock()
+
+	if fallback == nil {
+		delete(f.state.active, kind)
+		return
+	}
+	f.state.active[kind] = fallback.Name()
+}
+
+// SourceStatus returns the name of src, or the fallback source if any data
+// of src is currently served by it, Eg: "coincap (fallback)"
+func SourceStatus(src Source) string {
+	f, ok := src.(failoverSource)
+	if !ok {
+		return src.Name()
+	}
+
+	f.state.Lock()
+	defer f.state.Unlock()
+
+	for _, kind := range []string{"top", "asset", "history", "live"} {
+		if fallback, ok := f.state.active[kind]; ok {
+			return fmt.Sprintf("%s (fallback)", fallback)
+		}
+	}
+
+	return src.Name()
+}
+
+// ErrNotListed is returned when a source does not serve a coin
+var ErrNotListed = errors.New("coin not listed")
+
+// isUnavailable returns true if err indicates the source is unavailable or
+// does not serve the coin, rather than the request being invalid
+func isUnavailable(err error) bool {
+	if errors.Is(err, ErrNotListed) {
+		return true
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Code == http.StatusTooManyRequests || statusErr.Code >= 500
+	}
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// Name returns the name of the primary source
+func (f failoverSource) Name() string {
+	return f.primary.Name()
+}
+
+// GetTopCoins returns market data of the top n coins by market cap
+func (f failoverSource) GetTopCoins(n int) (geckoTypes.CoinsMarket, error) {
+	coinsData, err := f.primary.GetTopCoins(n)
+	if err == nil || !isUnavailable(err) {
+		f.setFallback("top", nil)
+		return coinsData, err
+	}
+
+	f.setFallback("top", f.fallback)
+	return f.fallback.GetTopCoins(n)
+}
+
+// GetAsset returns market data of a coin along with its 7 day sparkline
+func (f failoverSource) GetAsset(id CoinID) (geckoTypes.CoinsMarketItem, error) {
+	item, err := f.primary.GetAsset(id)
+	if err == nil || !isUnavailable(err) {
+		f.setFallback("asset", nil)
+		return item, err
+	}
+
+	f.setFallback("asset", f.fallback)
+	return f.fallback.GetAsset(id)
+}
+
+// GetHistory returns the USD price history of a coin over the given number
+// of days
+func (f failoverSource) GetHistory(id CoinID, days int) ([]geckoTypes.ChartItem, error) {
+	history, err := f.primary.GetHistory(id, days)
+	if err == nil || !isUnavailable(err) {
+		f.setFallback("history", nil)
+		return history, err
+	}
+
+	f.setFallback("history", f.fallback)
+	return f.fallback.GetHistory(id, days)
+}
+
+// GetHistoryRange returns the USD price history of a coin between start and
+// end
+func (f failoverSource) GetHistoryRange(id CoinID, start, end time.Time) ([]geckoTypes.ChartItem, error) {
+	history, err := f.primary.GetHistoryRange(id, start, end)
+	if err == nil || !isUnavailable(err) {
+		f.setFallback("history", nil)
+		return history, err
+	}
+
+	f.setFallback("history", f.fallback)
+	return f.fallback.GetHistoryRange(id, start, end)
+}
+
+// GetLivePrice streams prices from the primary source, and from the live
+// fallback if the primary stream can't be opened or drops
+func (f failoverSource) GetLivePrice(ctx context.Context, id CoinID, dataChannel chan string) error {
+	f.setFallback("live", nil)
+	err := f.primary.GetLivePrice(ctx, id, dataChannel)
+	if ctx.Err() != nil {
+		return err
+	}
+
+	f.setFallback("live", f.live)
+	defer f.setFallback("live", nil)
+	return f.live.GetLivePrice(ctx, id, dataChannel)
+}
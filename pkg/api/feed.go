@@ -0,0 +1,291 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
+)
+
+// StaleAfter is how long a live price stream may go without a message
+// before it is treated as degraded and prices are polled instead, set from
+// the config file
+var StaleAfter = time.Duration(30) * time.Second
+
+// staleAfter returns StaleAfter, read under the settings lock
+func staleAfter() time.Duration {
+	utils.RLockSettings()
+	defer utils.RUnlockSettings()
+	return StaleAfter
+}
+
+// feedState records the health of the live price feed of the open coin page
+var feedState = struct {
+	sync.Mutex
+	started     time.Time
+	lastMessage time.Time
+	avgGap      time.Duration
+	polling     bool
+	paused      bool // Stream paused in low power mode
+	// Reconnect attempt in progress, 0 while connected
+	reconnecting int
+	connectedAt  time.Time
+}{}
+
+// resetFeed clears the feed state when a new feed is started
+func resetFeed() {
+	feedState.Lock()
+	defer feedState.Unlock()
+
+	feedState.started = time.Now()
+	feedState.lastMessage = time.Time{}
+	feedState.avgGap = 0
+	feedState.polling = false
+	feedState.paused = false
+	feedState.reconnecting = 0
+	feedState.connectedAt = time.Time{}
+}
+
+// setReconnecting records the reconnect attempt in progress, 0 once the
+// stream is connected again
+func setReconnecting(attempt int) {
+	feedState.Lock()
+	defer feedState.Unlock()
+
+	if attempt == 0 && feedState.reconnecting > 0 {
+		feedState.connectedAt = time.Now()
+	}
+	feedState.reconnecting = attempt
+}
+
+// recordMessage records the arrival of a message, keeping a moving average
+// of the gap between messages
+func recordMessage() {
+	feedState.Lock()
+	defer feedState.Unlock()
+
+	now := time.Now()
+	if !feedState.lastMessage.IsZero() {
+		gap := now.Sub(feedState.lastMessage)
+		if feedState.avgGap == 0 {
+			feedState.avgGap = gap
+		} else {
+			feedState.avgGap = (feedState.avgGap*4 + gap) / 5
+		}
+	}
+	feedState.lastMessage = now
+}
+
+// feedAge returns the time since the last message, or since the feed was
+// started or reconnected if that was later. While reconnecting, the feed is
+// treated as fresh, as reconnects give up on their own.
+func feedAge() time.Duration {
+	feedState.Lock()
+	defer feedState.Unlock()
+
+	if feedState.reconnecting > 0 {
+		return 0
+	}
+
+	since := feedState.started
+	if feedState.lastMessage.After(since) {
+		since = feedState.lastMessage
+	}
+	if feedState.connectedAt.After(since) {
+		since = feedState.connectedAt
+	}
+	return time.Since(since)
+}
+
+// setPolling records that the feed switched to polling
+func setPolling() {
+	feedState.Lock()
+	defer feedState.Unlock()
+
+	feedState.polling = true
+}
+
+// setPaused records whether the stream is paused in low power mode
+func setPaused(paused bool) {
+	feedState.Lock()
+	defer feedState.Unlock()
+
+	feedState.paused = paused
+}
+
+// FeedHealth returns the health of the live price feed, Eg: "ok 2s ago" or
+// "stale, polling". The feed is slow when it is silent for much longer than
+// its usual gap between messages, or for half of StaleAfter.
+func FeedHealth() string {
+	feedState.Lock()
+	defer feedState.Unlock()
+
+	if feedState.polling {
+		return "stale, polling"
+	}
+	if feedState.paused {
+		return "paused, polling"
+	}
+	if feedState.reconnecting > 0 {
+		return fmt.Sprintf("reconnecting %d/%d…", feedState.reconnecting, maxReconnects)
+	}
+	if feedState.lastMessage.IsZero() {
+		return "waiting"
+	}
+
+	age := time.Since(feedState.lastMessage)
+	slow := age > staleAfter()/2
+	if feedState.avgGap > 0 && age > 10*feedState.avgGap && age > time.Duration(5)*time.Second {
+		slow = true
+	}
+
+	if slow {
+		return fmt.Sprintf("slow %s ago", age.Round(time.Second))
+	}
+	return fmt.Sprintf("ok %s ago", age.Round(time.Second))
+}
+
+// GetLivePrice streams realtime prices of a coin specified by id from src.
+// If the stream goes without a message for longer than StaleAfter, it is
+// dropped and prices are polled from CoinGecko instead. In low power mode
+// the stream is paused and prices are polled till power is restored.
+// Single ticks deviating from recent prices are dropped as glitches.
+func GetLivePrice(ctx context.Context, src Source, id CoinID, dataChannel chan string) error {
+	name := "live " + id.CoinGeckoID
+	utils.ResetSpikes(name)
+
+	prices := make(chan string)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case data := <-prices:
+				if price, err := strconv.ParseFloat(data, 64); err == nil && !utils.AcceptPrice(name, price) {
+					continue
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case dataChannel <- data:
+				}
+			}
+		}
+	}()
+
+	for {
+		if utils.LowPower() {
+			if err := pollLowPower(ctx, id, prices); err != nil {
+				return err
+			}
+		}
+
+		lowPower, err := streamLivePrice(ctx, src, id, prices)
+		if !lowPower {
+			return err
+		}
+	}
+}
+
+// pollLowPower polls prices from CoinGecko in place of the stream, returning
+// nil once low power mode is left
+func pollLowPower(ctx context.Context, id CoinID, dataChannel chan string) error {
+	setPaused(true)
+	defer setPaused(false)
+
+	pollCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- geckoSource{}.GetLivePrice(pollCtx, id, dataChannel)
+	}()
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case err := <-errChan:
+			return err
+
+		case <-ticker.C:
+			if !utils.LowPower() {
+				return nil
+			}
+		}
+	}
+}
+
+// streamLivePrice streams prices from src as GetLivePrice does, returning
+// true if it stopped for low power mode
+func streamLivePrice(ctx context.Context, src Source, id CoinID, dataChannel chan string) (bool, error) {
+	resetFeed()
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	messages := make(chan string)
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- src.GetLivePrice(streamCtx, id, messages)
+	}()
+
+	// CoinGecko is polled already, so there is nothing to switch to
+	watch := src.Name() != (geckoSource{}).Name()
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+
+		case err := <-errChan:
+			return false, err
+
+		case price := <-messages:
+			recordMessage()
+			select {
+			case <-ctx.Done():
+				return false, ctx.Err()
+			case dataChannel <- price:
+			}
+
+		case <-ticker.C:
+			if utils.LowPower() {
+				// Drop the stream to save power
+				return true, nil
+			}
+			if watch && feedAge() > staleAfter() {
+				// Drop the degraded stream and poll instead
+				cancel()
+				setPolling()
+				return false, geckoSource{}.GetLivePrice(ctx, id, dataChannel)
+			}
+		}
+	}
+}
@@ -0,0 +1,177 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
+)
+
+// FXRefresh is how often FX rates are refreshed, set from the config file
+var FXRefresh = time.Duration(5) * time.Minute
+
+// FXRate is the price in USD of a unit of a currency
+type FXRate struct {
+	Code    string // ISO 4217 code, or ticker of crypto currencies
+	Name    string
+	Unit    string // Sign of the currency, Eg: €
+	Type    string // fiat, crypto or commodity
+	RateUSD float64
+}
+
+// fxRates holds the last FX rates fetched, keyed by code
+var fxRates = struct {
+	sync.Mutex
+	rates map[string]FXRate
+}{rates: make(map[string]FXRate)}
+
+// UpdateFXRates fetches rates of currencies from CoinGecko, which serves
+// them per BTC, and keeps them for GetFXRate
+func UpdateFXRates() error {
+	rates, err := NewGeckoClient().ExchangeRates()
+	if err != nil {
+		return err
+	}
+
+	usd, ok := (*rates)["usd"]
+	if !ok || usd.Value <= 0 {
+		return fmt.Errorf("exchange rates have no USD rate")
+	}
+
+	fetched := make(map[string]FXRate)
+	for code, rate := range *rates {
+		if rate.Value <= 0 {
+			continue
+		}
+		code = strings.ToUpper(code)
+		fetched[code] = FXRate{
+			Code:    code,
+			Name:    rate.Name,
+			Unit:    rate.Unit,
+			Type:    rate.Type,
+			RateUSD: usd.Value / rate.Value,
+		}
+	}
+
+	fxRates.Lock()
+	defer fxRates.Unlock()
+	fxRates.rates = fetched
+
+	return nil
+}
+
+// RefreshFXRates refreshes FX rates every FXRefresh. Rates are kept when
+// a refresh fails, Eg: on rate limits, so it only returns once ctx is done.
+func RefreshFXRates(ctx context.Context) error {
+	return utils.LoopTick(ctx, FXRefresh, func(errChan chan error) {
+		UpdateFXRates()
+	})
+}
+
+// GetFXRate returns the last FX rate of a currency by its code, false if
+// no rate of it was fetched
+func GetFXRate(code string) (FXRate, bool) {
+	fxRates.Lock()
+	defer fxRates.Unlock()
+
+	code = strings.ToUpper(code)
+	rate, ok := fxRates.rates[code]
+
+	// Prefer rates streamed live
+	liveRates.Lock()
+	defer liveRates.Unlock()
+	if live, streamed := liveRates.rates[code]; streamed {
+		if !ok {
+			rate = FXRate{Code: code, Type: "crypto"}
+		}
+		rate.RateUSD = live
+		return rate, true
+	}
+
+	return rate, ok
+}
+
+// GetFXRates returns the last FX rates fetched, keyed by code
+func GetFXRates() map[string]FXRate {
+	fxRates.Lock()
+	defer fxRates.Unlock()
+
+	rates := make(map[string]FXRate)
+	for code, rate := range fxRates.rates {
+		rates[code] = rate
+	}
+	return rates
+}
+
+// liveRates holds rates of crypto currencies streamed by StreamFXRate,
+// keyed by code. They take precedence over the periodic FX rates.
+var liveRates = struct {
+	sync.Mutex
+	rates map[string]float64
+}{rates: make(map[string]float64)}
+
+// StreamFXRate streams the price in USD of a crypto currency, given by its
+// code and coin IDs, from src and keeps it as the FX rate of the currency
+// till ctx is done. The price is sent on dataChannel as it changes, so
+// values quoted in the currency can be updated with it.
+func StreamFXRate(ctx context.Context, src Source, id CoinID, code string, dataChannel chan string) error {
+	code = strings.ToUpper(code)
+
+	defer func() {
+		liveRates.Lock()
+		delete(liveRates.rates, code)
+		liveRates.Unlock()
+	}()
+
+	messages := make(chan string)
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- src.GetLivePrice(ctx, id, messages)
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case err := <-errChan:
+			return err
+
+		case data := <-messages:
+			price, err := strconv.ParseFloat(data, 64)
+			if err != nil || price <= 0 {
+				continue
+			}
+
+			liveRates.Lock()
+			liveRates.rates[code] = price
+			liveRates.Unlock()
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case dataChannel <- data:
+			}
+		}
+	}
+}
@@ -0,0 +1,204 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
+	geckoTypes "github.com/superoo7/go-gecko/v3/types"
+)
+
+// geckoURL is the base URL of the CoinGecko API
+const geckoURL = "https://api.coingecko.com/api/v3"
+
+// geckoSource serves data from CoinGecko
+type geckoSource struct{}
+
+// Name returns the name of the source
+func (geckoSource) Name() string {
+	return "coingecko"
+}
+
+// GetTopCoins returns market data of the top n coins by market cap
+func (geckoSource) GetTopCoins(n int) (geckoTypes.CoinsMarket, error) {
+	geckoClient := NewGeckoClient()
+
+	vsCurrency := "usd"
+	ids := []string{}
+
+	if n > 1000 {
+		return nil, fmt.Errorf("page size limit is 1000")
+	}
+
+	perPage := n
+	page := 1
+
+	sparkline := false
+
+	pcp := geckoTypes.PriceChangePercentageObject
+	priceChangePercentage := []string{pcp.PCP1h, pcp.PCP24h, pcp.PCP7d, pcp.PCP14d, pcp.PCP30d, pcp.PCP200d, pcp.PCP1y}
+
+	order := geckoTypes.OrderTypeObject.MarketCapDesc
+	coinDataPointer, err := geckoClient.CoinsMarket(vsCurrency, ids, order, perPage, page, sparkline, priceChangePercentage)
+
+	if err != nil {
+		return nil, err
+	}
+
+	coinData := *coinDataPointer
+
+	return coinData, nil
+}
+
+// GetCoinsByID returns market data of coins by their CoinGecko IDs, fetched
+// in pages of up to 250 coins. Coins CoinGecko does not list are left out.
+func GetCoinsByID(ids []string) (geckoTypes.CoinsMarket, error) {
+	geckoClient := NewGeckoClient()
+
+	order := geckoTypes.OrderTypeObject.MarketCapDesc
+	coins := geckoTypes.CoinsMarket{}
+	for start := 0; start < len(ids); start += 250 {
+		end := start + 250
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		coinDataPointer, err := geckoClient.CoinsMarket("usd", ids[start:end], order, end-start, 1, false, []string{})
+		if err != nil {
+			return nil, err
+		}
+		coins = append(coins, *coinDataPointer...)
+	}
+
+	return coins, nil
+}
+
+// GetAsset returns market data of a coin along with its 7 day sparkline
+func (geckoSource) GetAsset(id CoinID) (geckoTypes.CoinsMarketItem, error) {
+	if id.CoinGeckoID == "" {
+		return geckoTypes.CoinsMarketItem{}, fmt.Errorf("%w on CoinGecko", ErrNotListed)
+	}
+
+	// Init Client
+	geckoClient := NewGeckoClient()
+
+	// Set Parameters
+	vsCurrency := "usd"
+	order := geckoTypes.OrderTypeObject.MarketCapDesc
+	sparkline := true
+	priceChangePercentage := []string{}
+
+	// Fetch Data
+	coinDataPointer, err := geckoClient.CoinsMarket(vsCurrency, []string{id.CoinGeckoID}, order, 1, 1, sparkline, priceChangePercentage)
+	if err != nil {
+		return geckoTypes.CoinsMarketItem{}, err
+	}
+
+	if len(*coinDataPointer) == 0 {
+		return geckoTypes.CoinsMarketItem{}, fmt.Errorf("no market data for %s", id.CoinGeckoID)
+	}
+
+	return (*coinDataPointer)[0], nil
+}
+
+// GetHistory returns the USD price history of a coin over the given number
+// of days
+func (geckoSource) GetHistory(id CoinID, days int) ([]geckoTypes.ChartItem, error) {
+	if id.CoinGeckoID == "" {
+		return nil, fmt.Errorf("%w on CoinGecko", ErrNotListed)
+	}
+
+	geckoClient := NewGeckoClient()
+
+	data, err := geckoClient.CoinsIDMarketChart(id.CoinGeckoID, "usd", fmt.Sprintf("%d", days))
+	if err != nil {
+		return nil, err
+	}
+
+	return *data.Prices, nil
+}
+
+// GetHistoryRange returns the USD price history of a coin between start and
+// end
+func (geckoSource) GetHistoryRange(id CoinID, start, end time.Time) ([]geckoTypes.ChartItem, error) {
+	if id.CoinGeckoID == "" {
+		return nil, fmt.Errorf("%w on CoinGecko", ErrNotListed)
+	}
+
+	// go-gecko has no method for the range endpoint
+	url := fmt.Sprintf("%s/coins/%s/market_chart/range?vs_currency=usd&from=%d&to=%d",
+		geckoURL, id.CoinGeckoID, start.Unix(), end.Unix())
+
+	body, err := NewGeckoClient().MakeReq(url)
+	if err != nil {
+		return nil, err
+	}
+
+	data := geckoTypes.CoinsIDMarketChart{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, err
+	}
+	if data.Prices == nil {
+		return []geckoTypes.ChartItem{}, nil
+	}
+
+	return *data.Prices, nil
+}
+
+// GetLivePrice polls the USD price of a coin, as CoinGecko has no public
+// stream
+func (geckoSource) GetLivePrice(ctx context.Context, id CoinID, dataChannel chan string) error {
+	if id.CoinGeckoID == "" {
+		return fmt.Errorf("%w on CoinGecko", ErrNotListed)
+	}
+
+	geckoClient := NewGeckoClient()
+
+	return utils.LoopTick(ctx, time.Duration(5)*time.Second, func(errChan chan error) {
+		var finalErr error = nil
+
+		defer func() {
+			if finalErr != nil {
+				errChan <- finalErr
+			}
+		}()
+
+		// Fetch Data
+		prices, err := geckoClient.SimplePrice([]string{id.CoinGeckoID}, []string{"usd"})
+		if err != nil {
+			finalErr = err
+			return
+		}
+
+		price, ok := (*prices)[id.CoinGeckoID]["usd"]
+		if !ok {
+			return
+		}
+
+		// Send Data
+		select {
+		case <-ctx.Done():
+			finalErr = ctx.Err()
+			return
+		case dataChannel <- fmt.Sprintf("%f", price):
+		}
+	})
+}
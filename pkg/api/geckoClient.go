@@ -0,0 +1,104 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
+	gecko "github.com/superoo7/go-gecko/v3"
+)
+
+// geckoTransport maps failed CoinGecko responses to errors carrying their
+// status and endpoint, as go-gecko only returns the body of failed responses
+type geckoTransport struct{}
+
+// geckoEndpoint names the endpoint of a CoinGecko API path, Eg: markets for
+// /api/v3/coins/markets and market_chart for /api/v3/coins/{id}/market_chart
+func geckoEndpoint(path string) string {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) >= 3 && parts[0] == "api" {
+		parts = parts[2:]
+	}
+
+	switch {
+	case len(parts) == 2 && parts[0] == "coins" && parts[1] != "list" && parts[1] != "markets":
+		return "coin"
+	case len(parts) > 0:
+		return parts[len(parts)-1]
+	}
+
+	return path
+}
+
+// GeckoError is returned when a CoinGecko endpoint responds with an error
+type GeckoError struct {
+	Endpoint string
+	Status   *StatusError
+}
+
+func (e *GeckoError) Error() string {
+	return fmt.Sprintf("coingecko %s returned %d %s", e.Endpoint, e.Status.Code, http.StatusText(e.Status.Code))
+}
+
+func (e *GeckoError) Unwrap() error {
+	return e.Status
+}
+
+// geckoKey holds the CoinGecko API key, read from its store once
+var geckoKey struct {
+	sync.Once
+	key string
+}
+
+// RoundTrip sends a request through the transport of Client, returning a
+// GeckoError for responses other than 200 OK. Requests are sent with the
+// stored CoinGecko API key, if any.
+func (geckoTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	geckoKey.Do(func() {
+		geckoKey.key, _ = utils.GetAPIKey("coingecko")
+	})
+	if geckoKey.key != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("x-cg-demo-api-key", geckoKey.key)
+	}
+
+	res, err := apiTransport{}.RoundTrip(req)
+	if err != nil || res.StatusCode == http.StatusOK {
+		return res, err
+	}
+
+	// Drain body so the connection can be reused
+	io.Copy(ioutil.Discard, res.Body)
+	res.Body.Close()
+
+	return nil, &GeckoError{
+		Endpoint: geckoEndpoint(req.URL.Path),
+		Status:   &StatusError{URL: req.URL.String(), Code: res.StatusCode},
+	}
+}
+
+// NewGeckoClient returns a CoinGecko client whose errors can be told apart
+// by status, Eg: to fall back to another source when rate limited
+func NewGeckoClient() *gecko.Client {
+	return gecko.NewClient(&http.Client{Transport: geckoTransport{}})
+}
@@ -0,0 +1,127 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// genindex writes the index of top coins bundled with cryptgo, index.json
+// of package api, from CoinGecko markets and CoinCap assets. It is run with
+// go generate from pkg/api.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// indexSize is the number of coins of the index, by market cap
+const indexSize = 100
+
+// index is the layout of index.json, read by api.BundledIndex
+type index struct {
+	Generated string      `json:"generated"`
+	Coins     []indexCoin `json:"coins"`
+}
+
+// indexCoin is a coin of the index
+type indexCoin struct {
+	ID        string `json:"id"`
+	CoinCapID string `json:"coincap"`
+	Symbol    string `json:"symbol"`
+	Name      string `json:"name"`
+}
+
+// getJSON decodes the JSON response of a URL into v
+func getJSON(url string, v interface{}) error {
+	client := &http.Client{Timeout: time.Duration(30) * time.Second}
+	res, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", url, res.Status)
+	}
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+func main() {
+	path := "index.json"
+	if len(os.Args) > 1 {
+		path = os.Args[1]
+	}
+
+	markets := []struct {
+		ID     string `json:"id"`
+		Symbol string `json:"symbol"`
+		Name   string `json:"name"`
+	}{}
+	err := getJSON(fmt.Sprintf("https://api.coingecko.com/api/v3/coins/markets?vs_currency=usd&order=market_cap_desc&per_page=%d&page=1", indexSize), &markets)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to fetch CoinGecko markets: %v\n", err)
+		os.Exit(1)
+	}
+
+	// CoinCap IDs by symbol, of the asset ranked highest. The index is
+	// still written without them if CoinCap can't be reached.
+	coinCapIDs := map[string]string{}
+	assets := struct {
+		Data []struct {
+			ID     string `json:"id"`
+			Symbol string `json:"symbol"`
+		} `json:"data"`
+	}{}
+	if err := getJSON("https://api.coincap.io/v2/assets?limit=2000", &assets); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to fetch CoinCap assets, leaving out their IDs: %v\n", err)
+	}
+	for _, asset := range assets.Data {
+		symbol := strings.ToUpper(asset.Symbol)
+		if _, ok := coinCapIDs[symbol]; !ok {
+			coinCapIDs[symbol] = asset.ID
+		}
+	}
+
+	idx := index{
+		Generated: time.Now().UTC().Format("2006-01-02"),
+		Coins:     []indexCoin{},
+	}
+	for _, market := range markets {
+		symbol := strings.ToUpper(market.Symbol)
+		coinCapID := coinCapIDs[symbol]
+		if coinCapID == "" {
+			coinCapID = market.ID
+		}
+		idx.Coins = append(idx.Coins, indexCoin{
+			ID:        market.ID,
+			CoinCapID: coinCapID,
+			Symbol:    symbol,
+			Name:      market.Name,
+		})
+	}
+
+	data, err := json.MarshalIndent(idx, "", "\t")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Printf("Wrote %d coins to %s\n", len(idx.Coins), path)
+}
@@ -18,49 +18,26 @@ package api
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	"github.com/Gituser143/cryptgo/pkg/utils"
-	gecko "github.com/superoo7/go-gecko/v3"
 	geckoTypes "github.com/superoo7/go-gecko/v3/types"
 )
 
-func getTopNCoins(n int) (geckoTypes.CoinsMarket, error) {
-	geckoClient := gecko.NewClient(nil)
-
-	vsCurrency := "usd"
-	ids := []string{}
-
-	if n > 1000 {
-		return nil, fmt.Errorf("page size limit is 1000")
-	}
-
-	perPage := n
-	page := 1
-
-	sparkline := false
-
-	pcp := geckoTypes.PriceChangePercentageObject
-	priceChangePercentage := []string{pcp.PCP1h, pcp.PCP24h, pcp.PCP7d, pcp.PCP14d, pcp.PCP30d, pcp.PCP200d, pcp.PCP1y}
-
-	order := geckoTypes.OrderTypeObject.MarketCapDesc
-	coinDataPointer, err := geckoClient.CoinsMarket(vsCurrency, ids, order, perPage, page, sparkline, priceChangePercentage)
-
-	if err != nil {
-		return nil, err
-	}
-
-	coinData := *coinDataPointer
-
-	return coinData, nil
-}
-
 // GetPercentageChangeForDuration returns price change percentage given a
 // CoinsMarketItem and a duration, If the specified duration does not exist, 24
 // Hour change percent is returned
 func GetPercentageChangeForDuration(coinData geckoTypes.CoinsMarketItem, duration string) float64 {
+	if change, ok := PercentageChange(coinData, duration); ok {
+		return change
+	}
+	return coinData.PriceChangePercentage24h
+}
 
+// PercentageChange returns the percentage change of a coin over duration,
+// and false if its source doesn't serve changes over the duration, Eg:
+// CoinCap only serves 24 hour changes
+func PercentageChange(coinData geckoTypes.CoinsMarketItem, duration string) (float64, bool) {
 	m := map[string]*float64{
 		"1h":   coinData.PriceChangePercentage1hInCurrency,
 		"24h":  coinData.PriceChangePercentage24hInCurrency,
@@ -72,15 +49,69 @@ func GetPercentageChangeForDuration(coinData geckoTypes.CoinsMarketItem, duratio
 	}
 
 	if percentageDuration, isPresent := m[duration]; isPresent && percentageDuration != nil {
-		return *percentageDuration
+		return *percentageDuration, true
 	}
-	return coinData.PriceChangePercentage24h
+	if duration == "24h" {
+		return coinData.PriceChangePercentage24h, true
+	}
+	return 0, false
+}
+
+// GetFavouriteStats aggregates market cap and 24 hour change of the coins
+// in coinsData which are present in favourites
+func GetFavouriteStats(coinsData geckoTypes.CoinsMarket, favourites map[string]bool) FavouriteStats {
+	stats := FavouriteStats{}
+	totalChange := 0.0
+
+	for _, val := range coinsData {
+		if _, ok := favourites[val.ID]; !ok {
+			continue
+		}
+
+		stats.Count++
+		stats.MarketCap += val.MarketCap
+		totalChange += val.PriceChangePercentage24h
+
+		if val.PriceChangePercentage24h > 0 {
+			stats.Advancing++
+		} else if val.PriceChangePercentage24h < 0 {
+			stats.Declining++
+		}
+	}
+
+	if stats.Count > 0 {
+		stats.AverageChange24h = totalChange / float64(stats.Count)
+	}
+
+	return stats
+}
+
+// GetMarketBreadth counts the coins advancing and declining over 24 hours
+// among the top n coins (by market cap) in coinsData
+func GetMarketBreadth(coinsData geckoTypes.CoinsMarket, n int) MarketBreadth {
+	breadth := MarketBreadth{}
+
+	for _, val := range coinsData {
+		if val.MarketCapRank < 1 || int(val.MarketCapRank) > n {
+			continue
+		}
+
+		breadth.Total++
+		if val.PriceChangePercentage24h > 0 {
+			breadth.Advancing++
+		} else if val.PriceChangePercentage24h < 0 {
+			breadth.Declining++
+		}
+	}
+
+	return breadth
 }
 
-// Get Assets serves data about top 100 coins for the main page
+// Get Assets serves data about top 100 coins for the main page, polled every
+// AssetsRefresh, which applies from the next poll when changed
 func GetAssets(ctx context.Context, dataChannel chan AssetData, sendData *bool) error {
 
-	return utils.LoopTick(ctx, time.Duration(10)*time.Second, func(errChan chan error) {
+	return utils.LoopTickFunc(ctx, AssetsRefresh, func(errChan chan error) {
 		var finalErr error = nil
 		data := AssetData{}
 
@@ -92,7 +123,8 @@ func GetAssets(ctx context.Context, dataChannel chan AssetData, sendData *bool)
 
 		if *sendData {
 			// Fetch Data
-			coinsData, err := getTopNCoins(150)
+			coinsData, err := GetSource().GetTopCoins(150)
+			trackStatus("coins", AssetsRefresh(), err)
 			if err != nil {
 				finalErr = err
 				return
@@ -121,7 +153,7 @@ func GetAssets(ctx context.Context, dataChannel chan AssetData, sendData *bool)
 func GetTopCoinData(ctx context.Context, dataChannel chan AssetData, sendData *bool, ids []string) error {
 
 	// Init Client
-	geckoClient := gecko.NewClient(nil)
+	geckoClient := NewGeckoClient()
 
 	// Set Parameters
 	vsCurrency := "usd"
@@ -148,6 +180,7 @@ func GetTopCoinData(ctx context.Context, dataChannel chan AssetData, sendData *b
 
 			// Fetch Data
 			coinDataPointer, err := geckoClient.CoinsMarket(vsCurrency, ids, order, perPage, page, sparkline, priceChangePercentage)
+			trackStatus("top coins", time.Duration(1)*time.Minute, err)
 			if err != nil {
 				finalErr = err
 				return
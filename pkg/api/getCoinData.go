@@ -18,12 +18,14 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"math"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Gituser143/cryptgo/pkg/utils"
-	"github.com/gorilla/websocket"
 	gecko "github.com/superoo7/go-gecko/v3"
 	geckoTypes "github.com/superoo7/go-gecko/v3/types"
 )
@@ -33,12 +35,109 @@ const (
 	DOWN_ARROW = "▼"
 )
 
-// GetFavouritePrices gets coin prices for coins specified by favourites.
-// This data is returned on the dataChannel.
-func GetFavouritePrices(ctx context.Context, favourites map[string]bool, dataChannel chan CoinData) error {
+// intervalDays maps history intervals to the number of days they span
+var intervalDays = map[string]int{
+	"24hr": 1,
+	"7d":   7,
+	"14d":  14,
+	"30d":  30,
+	"90d":  90,
+	"180d": 180,
+	"1yr":  365,
+	"5yr":  1825,
+}
+
+// DefaultInterval is the history interval a coin page opens with
+var DefaultInterval = "24hr"
+
+// defaultInterval returns DefaultInterval, read under the settings lock
+func defaultInterval() string {
+	utils.RLockSettings()
+	defer utils.RUnlockSettings()
+	return DefaultInterval
+}
+
+// SetDefaultInterval sets the history interval a coin page opens with
+func SetDefaultInterval(interval string) error {
+	if _, err := HistoryDays(interval); err != nil {
+		return err
+	}
+	DefaultInterval = interval
+	return nil
+}
+
+// HistoryDays returns the number of days a history interval spans
+func HistoryDays(interval string) (int, error) {
+	days, ok := intervalDays[interval]
+	if !ok {
+		return 0, fmt.Errorf("unknown interval %q", interval)
+	}
+	return days, nil
+}
+
+// RangeLayout is the layout of dates of custom history ranges
+const RangeLayout = "2006-01-02"
+
+// ParseHistoryRange parses a custom history interval of two dates, Eg:
+// 2021-01-01 to 2021-06-30, returning its start and end. The end date is
+// included, and ranges end now at the latest.
+func ParseHistoryRange(interval string) (time.Time, time.Time, error) {
+	dates := strings.Split(interval, " to ")
+	if len(dates) != 2 {
+		return time.Time{}, time.Time{}, fmt.Errorf("expected a range like 2021-01-01 to 2021-06-30, got %q", interval)
+	}
+
+	start, err := time.ParseInLocation(RangeLayout, strings.TrimSpace(dates[0]), time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid start date %q, expected YYYY-MM-DD", dates[0])
+	}
+
+	end, err := time.ParseInLocation(RangeLayout, strings.TrimSpace(dates[1]), time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid end date %q, expected YYYY-MM-DD", dates[1])
+	}
+	end = end.AddDate(0, 0, 1)
+
+	if now := time.Now(); end.After(now) {
+		end = now
+	}
+	if !start.Before(end) {
+		return time.Time{}, time.Time{}, fmt.Errorf("range %q starts after it ends", interval)
+	}
+
+	return start, end, nil
+}
+
+// historyIn returns the price history of a coin over an interval, either a
+// preset or a custom range of dates
+func historyIn(src Source, id CoinID, interval string) ([]geckoTypes.ChartItem, error) {
+	if days, ok := intervalDays[interval]; ok {
+		return src.GetHistory(id, days)
+	}
+
+	start, end, err := ParseHistoryRange(interval)
+	if err != nil {
+		return nil, err
+	}
+	return src.GetHistoryRange(id, start, end)
+}
+
+// rangeDays returns the number of days between start and end, rounded up
+func rangeDays(start, end time.Time) int {
+	return int(math.Ceil(end.Sub(start).Hours() / 24))
+}
+
+// GetFavouritePrices gets coin prices for coins of the active watchlist,
+// Eg: favourites, every FavouritesRefresh, which applies from the next poll
+// when changed. This data, named by the list, is
+// returned on the dataChannel.
+// If the markets endpoint is unavailable, prices are fetched from the simple
+// price endpoint, keeping the last stats, and failing that the last prices
+// are sent again. Either way the data is marked as degraded.
+func GetFavouritePrices(ctx context.Context, list utils.Watchlist, dataChannel chan CoinData) error {
 
 	// Init Client
-	geckoClient := gecko.NewClient(nil)
+	geckoClient := NewGeckoClient()
 
 	// Set Parameters
 	vsCurrency := "usd"
@@ -47,11 +146,17 @@ func GetFavouritePrices(ctx context.Context, favourites map[string]bool, dataCha
 	sparkline := true
 	priceChangePercentage := []string{}
 
-	return utils.LoopTick(ctx, time.Duration(10)*time.Second, func(errChan chan error) {
+	// Last complete data, and symbols of coins it held
+	var m sync.Mutex
+	last := CoinData{}
+	symbols := make(map[string]string)
+
+	return utils.LoopTickFunc(ctx, FavouritesRefresh, func(errChan chan error) {
 
 		var finalErr error
 
 		favouriteData := make(map[string]float64)
+		sparklines := make(map[string][]float64)
 
 		defer func() {
 			if finalErr != nil {
@@ -61,29 +166,77 @@ func GetFavouritePrices(ctx context.Context, favourites map[string]bool, dataCha
 
 		// Get Coin IDs
 		IDs := []string{}
-		for id := range favourites {
+		for id := range list.IDs {
 			IDs = append(IDs, id)
 		}
 
 		perPage := len(IDs)
 
+		m.Lock()
+		defer m.Unlock()
+
 		// Fetch Data
+		var coinData CoinData
 		coinDataPointer, err := geckoClient.CoinsMarket(vsCurrency, IDs, order, perPage, page, sparkline, priceChangePercentage)
-		if err != nil {
+		trackStatus("favourites", FavouritesRefresh(), err)
+		switch {
+		case err == nil:
+			// Set Prices
+			for _, val := range *coinDataPointer {
+				symbol := strings.ToUpper(val.Symbol)
+				favouriteData[symbol] = val.CurrentPrice
+				symbols[val.ID] = symbol
+				if val.SparklineIn7d != nil {
+					sparklines[symbol] = val.SparklineIn7d.Price
+				}
+			}
+
+			// Aggregate data
+			coinData = CoinData{
+				Type:           "FAVOURITES",
+				Favourites:     favouriteData,
+				Sparklines:     sparklines,
+				FavouriteStats: GetFavouriteStats(*coinDataPointer, list.IDs),
+				FavouriteCoins: *coinDataPointer,
+				Watchlist:      list.Name,
+			}
+			last = coinData
+
+		case !isUnavailable(err):
 			finalErr = err
 			return
-		}
 
-		// Set Prices
-		for _, val := range *coinDataPointer {
-			symbol := strings.ToUpper(val.Symbol)
-			favouriteData[symbol] = val.CurrentPrice
-		}
+		default:
+			// Fall back to simple prices, named by the last known symbols
+			prices, priceErr := geckoClient.SimplePrice(IDs, []string{vsCurrency})
+			if priceErr == nil {
+				for id, price := range *prices {
+					symbol, ok := symbols[id]
+					if !ok {
+						symbol = strings.ToUpper(id)
+					}
+					favouriteData[symbol] = float64(price[vsCurrency])
+				}
 
-		// Aggregate data
-		coinData := CoinData{
-			Type:       "FAVOURITES",
-			Favourites: favouriteData,
+				coinData = CoinData{
+					Type:           "FAVOURITES",
+					Favourites:     favouriteData,
+					Sparklines:     last.Sparklines,
+					FavouriteStats: last.FavouriteStats,
+					FavouriteCoins: last.FavouriteCoins,
+					Watchlist:      list.Name,
+					Degraded:       "prices only",
+				}
+			} else if isUnavailable(priceErr) && last.Type != "" {
+				coinData = last
+				coinData.Degraded = "stale"
+			} else if isUnavailable(priceErr) {
+				// Nothing to show yet, try again next tick
+				return
+			} else {
+				finalErr = priceErr
+				return
+			}
 		}
 
 		// Send data
@@ -97,29 +250,36 @@ func GetFavouritePrices(ctx context.Context, favourites map[string]bool, dataCha
 	})
 }
 
-// GetCoinHistory gets price history of a coin specified by id, for an interval
-// received through the interval channel.
-// The default interval is set as 24 Hours
-func GetCoinHistory(ctx context.Context, id string, intervalChannel chan string, dataChannel chan CoinData) error {
-
-	intervalToDuration := map[string]string{
-		"24hr": "1",
-		"7d":   "7",
-		"14d":  "14",
-		"30d":  "30",
-		"90d":  "90",
-		"180d": "180",
-		"1yr":  "365",
-		"5yr":  "1825",
-	}
-
-	// Set Default Interval to 1 day
-	i := "24hr"
-
-	// Init Client
-	geckoClient := gecko.NewClient(nil)
-
-	return utils.LoopTick(ctx, time.Duration(3)*time.Second, func(errChan chan error) {
+// GetCoinHistory gets price history of a coin specified by id from src, for
+// an interval received through the interval channel. History is fetched
+// every refreshInterval, slowed down by the supervisor to keep within the
+// share of the source's rate limit of the priority ctx was made with.
+// The default interval is set by DefaultInterval. Intervals may also be a
+// custom range of dates, Eg: 2021-01-01 to 2021-06-30.
+// If a quote coin is received through the quote channel, history is priced
+// in the quote coin instead of USD. An empty CoinID resets pricing to USD.
+// While true is received through the year ago channel, history of the same
+// period a year earlier is sent along, to be overlaid for comparison.
+func GetCoinHistory(ctx context.Context, src Source, id CoinID, refreshInterval time.Duration, intervalChannel chan string, quoteChannel chan CoinID, yearAgoChannel chan bool, dataChannel chan CoinData) error {
+
+	// Set Default Interval
+	i := defaultInterval()
+
+	// Price in USD by default
+	quote := CoinID{}
+
+	// History a year earlier, fetched for the interval and quote of its key
+	compare := false
+	yearAgoKey := ""
+	yearAgoFetched := time.Time{}
+	yearAgoPrices := []float64{}
+	yearAgoTimes := []time.Time{}
+
+	// Poll within the share of the source's rate limit of the coin's priority
+	every, release := supervise(ctx, sourceProvider(src), refreshInterval)
+	defer release()
+
+	return utils.LoopTickFunc(ctx, every, func(errChan chan error) {
 		var finalErr error = nil
 
 		defer func() {
@@ -139,9 +299,31 @@ func GetCoinHistory(ctx context.Context, id string, intervalChannel chan string,
 			break
 		}
 
-		// Get interval duration and fetch data
-		intervalDuration := intervalToDuration[i]
-		data, err := geckoClient.CoinsIDMarketChart(id, "usd", intervalDuration)
+		select {
+		case <-ctx.Done():
+			finalErr = ctx.Err()
+			return
+		case q := <-quoteChannel:
+			// Update quote
+			quote = q
+		default:
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			finalErr = ctx.Err()
+			return
+		case c := <-yearAgoChannel:
+			// Update comparison
+			compare = c
+		default:
+			break
+		}
+
+		// Fetch data for the interval
+		history, err := historyIn(src, id, i)
+		trackStatus("history", every(), err)
 		if err != nil {
 			finalErr = err
 			return
@@ -149,25 +331,92 @@ func GetCoinHistory(ctx context.Context, id string, intervalChannel chan string,
 
 		// Aggregate price history
 		price := []float64{}
-		for _, v := range *data.Prices {
-			price = append(price, float64(v[1]))
+		times := []time.Time{}
+		if quote == (CoinID{}) {
+			for _, v := range history {
+				price = append(price, float64(v[1]))
+				times = append(times, chartTime(v))
+			}
+		} else {
+			// Fetch quote history for the same interval
+			quoteHistory, err := historyIn(src, quote, i)
+			trackStatus("history", every(), err)
+			if err != nil {
+				finalErr = err
+				return
+			}
+			price, times = PriceRatio(history, quoteHistory)
+		}
+
+		// Drop single points of history glitched by the provider
+		name := "history " + id.CoinGeckoID
+		if quote != (CoinID{}) {
+			name += "/" + quote.CoinGeckoID
+		}
+		kept := utils.FilterSpikes(name, price, times)
+		if len(kept) < len(price) {
+			filteredPrice := make([]float64, 0, len(kept))
+			filteredTimes := make([]time.Time, 0, len(kept))
+			for _, k := range kept {
+				filteredPrice = append(filteredPrice, price[k])
+				if k < len(times) {
+					filteredTimes = append(filteredTimes, times[k])
+				}
+			}
+			price, times = filteredPrice, filteredTimes
 		}
 
-		// Set max and min
-		min := utils.MinFloat64(price...)
-		max := utils.MaxFloat64(price...)
+		if len(price) == 0 {
+			return
+		}
 
-		// Clean price for graphs
+		// Fetch the same period a year earlier, again once it moved along.
+		// Failures are retried after a minute, leaving out the comparison.
+		yearAgo := []float64{}
+		if compare {
+			key := i + " " + quote.CoinGeckoID
+			if key != yearAgoKey || time.Since(yearAgoFetched) >= yearAgoRefresh {
+				prices, times, err := yearAgoHistory(src, id, quote, i)
+				if err != nil && !isUnavailable(err) {
+					finalErr = err
+					return
+				}
+				yearAgoKey, yearAgoFetched = key, time.Now()
+				if err != nil {
+					yearAgoFetched = time.Now().Add(time.Minute - yearAgoRefresh)
+				}
+				yearAgoPrices, yearAgoTimes = prices, times
+			}
+			yearAgo = alignPrices(yearAgoPrices, yearAgoTimes, times)
+		}
+
+		// Set max and min, spanning history a year earlier so both are
+		// drawn to the same scale
+		min := utils.MinFloat64(append(yearAgo, price...)...)
+		max := utils.MaxFloat64(append(yearAgo, price...)...)
+
+		// Clean price for graphs, keeping the time of each point
+		points := make([]Point, len(price))
 		for i, val := range price {
-			price[i] = val - min
+			points[i].Price = val - min
+			if i < len(times) {
+				points[i].Time = times[i]
+			}
+		}
+
+		for i := range yearAgo {
+			yearAgo[i] -= min
 		}
 
 		// Aggregate data
 		coinData := CoinData{
 			Type:         "HISTORY",
-			PriceHistory: price,
+			PriceHistory: points,
+			YearAgo:      yearAgo,
+			Interval:     i,
 			MinPrice:     min,
 			MaxPrice:     max,
+			Quote:        quote,
 		}
 
 		// Send Data
@@ -180,21 +429,75 @@ func GetCoinHistory(ctx context.Context, id string, intervalChannel chan string,
 	})
 }
 
-// GetCoinDetails fetches details for a coin specified by id
-// and sends the data on dataChannel
-func GetCoinDetails(ctx context.Context, id string, dataChannel chan CoinData) error {
+// GetPriceAt returns the USD price of a coin closest to t in its history
+// from src, Eg: to value a past trade made in another currency
+func GetPriceAt(src Source, id CoinID, t time.Time) (float64, error) {
+	// Prices a day either side are hourly for most sources
+	day := time.Duration(24) * time.Hour
+	history, err := src.GetHistoryRange(id, t.Add(-day), t.Add(day))
+	if err != nil {
+		return 0, err
+	}
+
+	price, closest := 0.0, time.Duration(math.MaxInt64)
+	for _, v := range history {
+		diff := chartTime(v).Sub(t)
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff < closest && v[1] > 0 {
+			price, closest = float64(v[1]), diff
+		}
+	}
+	if price == 0 {
+		return 0, fmt.Errorf("no price of %s around %s", id.Symbol, t.Format("2006-01-02 15:04"))
+	}
+
+	return price, nil
+}
+
+// coinMaxSupply holds the max supply of a coin from CoinGecko, which
+// go-gecko doesn't decode. It is null for coins without a capped supply.
+type coinMaxSupply struct {
+	MarketData struct {
+		MaxSupply *float64 `json:"max_supply"`
+	} `json:"market_data"`
+}
+
+// getCoinsID fetches details of a coin as go-gecko's CoinsID does, with
+// market data and nothing else, along with the coin's max supply
+func getCoinsID(geckoClient *gecko.Client, id string) (*geckoTypes.CoinsID, *float64, error) {
+	url := fmt.Sprintf("%s/coins/%s?localization=false&tickers=false&market_data=true&community_data=false&developer_data=false&sparkline=false", geckoURL, id)
+
+	body, err := geckoClient.MakeReq(url)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	coinData := &geckoTypes.CoinsID{}
+	if err := json.Unmarshal(body, coinData); err != nil {
+		return nil, nil, err
+	}
+
+	supply := coinMaxSupply{}
+	if err := json.Unmarshal(body, &supply); err != nil {
+		return nil, nil, err
+	}
+
+	return coinData, supply.MarketData.MaxSupply, nil
+}
+
+// GetCoinDetails fetches details for a coin specified by id every
+// refreshInterval, slowed down by the supervisor as history is, and sends the
+// data on dataChannel
+func GetCoinDetails(ctx context.Context, id string, refreshInterval time.Duration, dataChannel chan CoinData) error {
 	// Init client
-	geckoClient := gecko.NewClient(nil)
+	geckoClient := NewGeckoClient()
 
-	// Set Parameters
-	localization := false
-	tickers := false
-	marketData := true
-	communityData := false
-	developerData := false
-	sparkline := false
-
-	return utils.LoopTick(ctx, time.Duration(10)*time.Second, func(errChan chan error) {
+	every, release := supervise(ctx, "coingecko", refreshInterval)
+	defer release()
+
+	return utils.LoopTickFunc(ctx, every, func(errChan chan error) {
 		var finalErr error = nil
 
 		defer func() {
@@ -204,7 +507,8 @@ func GetCoinDetails(ctx context.Context, id string, dataChannel chan CoinData) e
 		}()
 
 		// Fetch Data
-		coinData, err := geckoClient.CoinsID(id, localization, tickers, marketData, communityData, developerData, sparkline)
+		coinData, maxSupply, err := getCoinsID(geckoClient, id)
+		trackStatus("details", every(), err)
 		if err != nil {
 			finalErr = err
 			return
@@ -224,10 +528,11 @@ func GetCoinDetails(ctx context.Context, id string, dataChannel chan CoinData) e
 			}
 		}
 
-		// Get Total Supply if coin has it
-		totalSupply := 0.0
-		if coinData.MarketData.TotalSupply != nil {
-			totalSupply = *coinData.MarketData.TotalSupply
+		// Get Max Supply if coin has it. It is null for coins without a cap
+		// on supply, Eg: ETH, even though they have a total supply
+		hasMaxSupply := maxSupply != nil && *maxSupply > 0
+		if !hasMaxSupply {
+			maxSupply = new(float64)
 		}
 
 		// Get Change Percents
@@ -251,6 +556,15 @@ func GetCoinDetails(ctx context.Context, id string, dataChannel chan CoinData) e
 			changePercents[i][1] = change
 		}
 
+		// Get performance summary, from the same market data
+		performance := []PerformanceChange{
+			{"1H", coinData.MarketData.PriceChangePercentage1hInCurrency["usd"]},
+			{"24H", coinData.MarketData.PriceChangePercentage24h},
+			{"7D", coinData.MarketData.PriceChangePercentage7d},
+			{"30D", coinData.MarketData.PriceChangePercentage30d},
+			{"1Y", coinData.MarketData.PriceChangePercentage1y},
+		}
+
 		// Get ATH, ATL and Last update times
 		timeLayout := "2006-01-02T15:04:05.000Z"
 		tATHDate, err := time.Parse(timeLayout, coinData.MarketData.ATHDate["usd"])
@@ -276,6 +590,8 @@ func GetCoinDetails(ctx context.Context, id string, dataChannel chan CoinData) e
 			Symbol:         strings.ToUpper(coinData.Symbol),
 			Rank:           fmt.Sprintf("%d", coinData.MarketCapRank),
 			BlockTime:      fmt.Sprintf("%d", coinData.BlockTimeInMin),
+			CurrentPrice:   coinData.MarketData.CurrentPrice["usd"],
+			Change24h:      coinData.MarketData.PriceChangePercentage24h,
 			MarketCap:      coinData.MarketData.MarketCap["usd"],
 			Website:        "",
 			Explorers:      explorerLinks,
@@ -287,7 +603,9 @@ func GetCoinDetails(ctx context.Context, id string, dataChannel chan CoinData) e
 			Low24:          coinData.MarketData.Low24["usd"],
 			TotalVolume:    coinData.MarketData.TotalVolume["usd"],
 			ChangePercents: changePercents,
-			TotalSupply:    totalSupply,
+			Performance:    performance,
+			MaxSupply:      *maxSupply,
+			HasMaxSupply:   hasMaxSupply,
 			CurrentSupply:  coinData.MarketData.CirculatingSupply,
 			LastUpdate:     tUpdate.Format(time.RFC822),
 		}
@@ -307,46 +625,3 @@ func GetCoinDetails(ctx context.Context, id string, dataChannel chan CoinData) e
 		}
 	})
 }
-
-// GetLivePrice uses a websocket to stream realtime prices of a coin specified
-// by id. The prices are sent on the dataChannel
-func GetLivePrice(ctx context.Context, id string, dataChannel chan string) error {
-	url := fmt.Sprintf("wss://ws.coincap.io/prices?assets=%s", id)
-	c, _, err := websocket.DefaultDialer.DialContext(ctx, url, nil)
-	if err != nil {
-		return err
-	}
-	defer c.Close()
-
-	msg := make(map[string]string)
-
-	return utils.LoopTick(ctx, time.Duration(100*time.Millisecond), func(errChan chan error) {
-		var finalErr error = nil
-
-		// Defer panic recovery for closed websocket
-		defer func() {
-			if e := recover(); e != nil {
-				finalErr = fmt.Errorf("socket read error")
-			}
-		}()
-
-		defer func() {
-			if finalErr != nil {
-				errChan <- finalErr
-			}
-		}()
-
-		err = c.ReadJSON(&msg)
-		if err != nil {
-			finalErr = err
-			return
-		}
-
-		select {
-		case <-ctx.Done():
-			finalErr = ctx.Err()
-			return
-		case dataChannel <- msg[id]:
-		}
-	})
-}
@@ -0,0 +1,99 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
+)
+
+// AutoGranularity lets each source pick a granularity from the number of
+// days of history requested
+const AutoGranularity = "auto"
+
+// maxHistoryPoints is the most points a single history request may span.
+// Coarser automatic granularities are used when a chosen granularity would
+// need more.
+const maxHistoryPoints = 1000
+
+// granularities maps supported history granularities to the duration of
+// a single point
+var granularities = map[string]time.Duration{
+	"m1":  time.Minute,
+	"m5":  5 * time.Minute,
+	"m15": 15 * time.Minute,
+	"h1":  time.Hour,
+	"h2":  2 * time.Hour,
+	"d1":  24 * time.Hour,
+}
+
+// historyGranularity is the granularity requested from sources which allow
+// choosing one
+var historyGranularity = AutoGranularity
+
+// getHistoryGranularity returns historyGranularity, read under the settings
+// lock
+func getHistoryGranularity() string {
+	utils.RLockSettings()
+	defer utils.RUnlockSettings()
+	return historyGranularity
+}
+
+// Granularities returns the supported history granularities, finest first
+func Granularities() []string {
+	names := []string{}
+	for name := range granularities {
+		names = append(names, name)
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		return granularities[names[i]] < granularities[names[j]]
+	})
+
+	return names
+}
+
+// SetHistoryGranularity sets the granularity of history requested from
+// CoinCap and Binance, independently of the interval being displayed
+func SetHistoryGranularity(name string) error {
+	if _, ok := granularities[name]; !ok && name != AutoGranularity {
+		return fmt.Errorf("unknown history granularity %q", name)
+	}
+
+	historyGranularity = name
+	return nil
+}
+
+// granularityFor returns the configured granularity if history over days
+// fits in a single request, else AutoGranularity
+func granularityFor(days int) string {
+	granularity := getHistoryGranularity()
+	step, ok := granularities[granularity]
+	if !ok {
+		return AutoGranularity
+	}
+
+	span := time.Duration(days) * 24 * time.Hour
+	if int(span/step) > maxHistoryPoints {
+		return AutoGranularity
+	}
+
+	return granularity
+}
@@ -0,0 +1,78 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"math"
+	"time"
+)
+
+// minStatsPoints is the fewest prices history stats are computed from
+const minStatsPoints = 3
+
+// HistoryStats are risk statistics of a price history
+type HistoryStats struct {
+	Return      float64 // Change over the history, in percent
+	Volatility  float64 // Annualised volatility of returns, in percent
+	MaxDrawdown float64 // Largest fall from a peak, in percent
+	Sharpe      float64 // Annualised mean return over volatility, without a risk free rate
+}
+
+// ComputeHistoryStats returns risk statistics of a price history, with
+// prices offset by base as those of PriceHistory are. Returns between
+// consecutive prices are annualised by the average spacing of the history,
+// over 365 days as coins trade every day. ok is false if the history is too
+// short.
+func ComputeHistoryStats(history []Point, base float64) (stats HistoryStats, ok bool) {
+	if len(history) < minStatsPoints {
+		return stats, false
+	}
+
+	returns := []float64{}
+	peak := history[0].Price + base
+	for i := 1; i < len(history); i++ {
+		prev, price := history[i-1].Price+base, history[i].Price+base
+		if prev > 0 {
+			returns = append(returns, price/prev-1)
+		}
+
+		peak = math.Max(peak, price)
+		if peak > 0 {
+			stats.MaxDrawdown = math.Max(stats.MaxDrawdown, (peak-price)/peak*100)
+		}
+	}
+
+	span := history[len(history)-1].Time.Sub(history[0].Time)
+	if len(returns) < minStatsPoints-1 || span <= 0 {
+		return stats, false
+	}
+	periodsPerYear := float64(tradingDays*24*time.Hour) / (float64(span) / float64(len(history)-1))
+
+	first, last := history[0].Price+base, history[len(history)-1].Price+base
+	if first > 0 {
+		stats.Return = (last/first - 1) * 100
+	}
+
+	s := statsOf(returns)
+	sd := math.Sqrt(s.variance)
+	stats.Volatility = sd * math.Sqrt(periodsPerYear) * 100
+	if sd > 0 {
+		stats.Sharpe = s.mean / sd * math.Sqrt(periodsPerYear)
+	}
+
+	return stats, true
+}
@@ -0,0 +1,98 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	_ "embed"
+	"encoding/json"
+	"strings"
+	"sync"
+)
+
+//go:generate go run ./genindex index.json
+
+// indexData is the index of top coins bundled with cryptgo, so coins can be
+// found before any request succeeds, Eg: on first run without a network.
+// It is regenerated with go generate.
+//
+//go:embed index.json
+var indexData []byte
+
+// IndexedCoin is a coin of the bundled index
+type IndexedCoin struct {
+	ID        string `json:"id"` // CoinGecko ID
+	CoinCapID string `json:"coincap"`
+	Symbol    string `json:"symbol"` // Upper case
+	Name      string `json:"name"`
+}
+
+// bundled holds the bundled index once read
+var bundled struct {
+	sync.Once
+	generated string
+	coins     []IndexedCoin
+}
+
+// BundledIndex returns coins of the bundled index, by market cap when it
+// was generated, along with the date it was generated on
+func BundledIndex() ([]IndexedCoin, string) {
+	bundled.Do(func() {
+		idx := struct {
+			Generated string        `json:"generated"`
+			Coins     []IndexedCoin `json:"coins"`
+		}{}
+		if err := json.Unmarshal(indexData, &idx); err != nil {
+			return
+		}
+		for i := range idx.Coins {
+			idx.Coins[i].Symbol = strings.ToUpper(idx.Coins[i].Symbol)
+		}
+		bundled.generated = idx.Generated
+		bundled.coins = idx.Coins
+	})
+
+	return bundled.coins, bundled.generated
+}
+
+// BundledListings returns coins of the bundled index as listings, to be
+// searched till the full list of coins can be fetched
+func BundledListings() []CoinListing {
+	coins, _ := BundledIndex()
+
+	listings := []CoinListing{}
+	for _, coin := range coins {
+		listings = append(listings, CoinListing{
+			ID:     coin.ID,
+			Symbol: coin.Symbol,
+			Name:   coin.Name,
+		})
+	}
+	return listings
+}
+
+// seedIndex adds IDs of coins of the bundled index to the map. IDs fetched
+// afterwards replace them.
+func (c CoinIDMap) seedIndex() {
+	coins, _ := BundledIndex()
+	for _, coin := range coins {
+		c[coin.Symbol] = CoinID{
+			CoinGeckoID: coin.ID,
+			CoinCapID:   coin.CoinCapID,
+			Symbol:      coin.Symbol,
+		}
+	}
+}
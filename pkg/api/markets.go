@@ -0,0 +1,158 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
+)
+
+// MarketsRefresh is how often markets of a coin are refreshed while shown,
+// set from the config file
+var MarketsRefresh = time.Duration(30) * time.Second
+
+// marketsRefresh returns MarketsRefresh, read under the settings lock
+func marketsRefresh() time.Duration {
+	utils.RLockSettings()
+	defer utils.RUnlockSettings()
+	return MarketsRefresh
+}
+
+// maxMarkets is the number of markets of a coin fetched
+const maxMarkets = 50
+
+// Market holds a market a coin trades on
+type Market struct {
+	Exchange    string
+	Pair        string  // Eg: BTC/USDT
+	PriceUSD    float64 // Price of the coin on the market
+	VolumeUSD   float64 // Volume over 24 hours
+	VolumeShare float64 // Share of the coin's (or exchange's) volume traded on the market, in percent
+}
+
+// coincapMarkets holds markets of an asset from CoinCap
+type coincapMarkets struct {
+	Data []struct {
+		ExchangeID    string `json:"exchangeId"`
+		BaseSymbol    string `json:"baseSymbol"`
+		QuoteSymbol   string `json:"quoteSymbol"`
+		PriceUsd      string `json:"priceUsd"`
+		VolumeUsd24Hr string `json:"volumeUsd24Hr"`
+		VolumePercent string `json:"volumePercent"`
+	} `json:"data"`
+}
+
+// GetMarkets returns the top markets of a coin on CoinCap by 24 hour volume
+func GetMarkets(id CoinID, limit int) ([]Market, error) {
+	if id.CoinCapID == "" {
+		return nil, fmt.Errorf("%w on CoinCap", ErrNotListed)
+	}
+
+	url := fmt.Sprintf("%s/assets/%s/markets?limit=%d", coincapURL, id.CoinCapID, limit)
+
+	data := coincapMarkets{}
+	err := getJSON(url, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	parse := func(s string) float64 {
+		val, _ := strconv.ParseFloat(s, 64)
+		return val
+	}
+
+	markets := []Market{}
+	for _, val := range data.Data {
+		markets = append(markets, Market{
+			Exchange:    val.ExchangeID,
+			Pair:        fmt.Sprintf("%s/%s", val.BaseSymbol, val.QuoteSymbol),
+			PriceUSD:    parse(val.PriceUsd),
+			VolumeUSD:   parse(val.VolumeUsd24Hr),
+			VolumeShare: parse(val.VolumePercent),
+		})
+	}
+
+	sort.SliceStable(markets, func(i, j int) bool {
+		return markets[i].VolumeUSD > markets[j].VolumeUSD
+	})
+
+	return markets, nil
+}
+
+// GetCoinMarkets fetches markets of a coin every MarketsRefresh while
+// enabled through the markets channel, and sends them on dataChannel.
+// Markets are fetched as soon as they are enabled.
+func GetCoinMarkets(ctx context.Context, id CoinID, marketsChannel chan bool, dataChannel chan CoinData) error {
+	enabled := false
+	fetched := time.Time{}
+
+	return utils.LoopTick(ctx, time.Duration(1)*time.Second, func(errChan chan error) {
+		var finalErr error = nil
+
+		defer func() {
+			if finalErr != nil {
+				errChan <- finalErr
+			}
+		}()
+
+		select {
+		case <-ctx.Done():
+			finalErr = ctx.Err()
+			return
+		case e := <-marketsChannel:
+			// Update state, fetching again once enabled
+			enabled = e
+			fetched = time.Time{}
+		default:
+			break
+		}
+
+		refresh := marketsRefresh()
+		if !enabled || time.Since(fetched) < utils.PollInterval(refresh) {
+			return
+		}
+		fetched = time.Now()
+
+		// Markets are optional, so none are shown while CoinCap is
+		// unavailable rather than closing the coin page
+		markets, err := GetMarkets(id, maxMarkets)
+		trackStatus("markets", refresh, err)
+		if err != nil && !isUnavailable(err) {
+			finalErr = err
+			return
+		}
+
+		// Aggregate data
+		coinData := CoinData{
+			Type:    "MARKETS",
+			Markets: markets,
+		}
+
+		// Send Data
+		select {
+		case <-ctx.Done():
+			finalErr = ctx.Err()
+			return
+		case dataChannel <- coinData:
+		}
+	})
+}
@@ -0,0 +1,97 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	geckoTypes "github.com/superoo7/go-gecko/v3/types"
+)
+
+// Settings of the top movers scanner of the daemon, set from the config
+// file
+var (
+	ScanMovers     = true // Notify about top movers
+	MoverThreshold = 15.0 // Hourly change, in %, of a top mover
+	MoverTop       = 100  // Coins (by market cap) scanned for movers
+)
+
+// MoverAlert is raised when a top coin moves by at least the threshold
+// within an hour
+type MoverAlert struct {
+	ID     string
+	Symbol string
+	Rank   int
+	Change float64
+}
+
+// Message describes the move, Eg: "SOL (#5) moved 16.20% in 1h"
+func (m MoverAlert) Message() string {
+	return fmt.Sprintf("%s (#%d) moved %.2f%% in 1h", m.Symbol, m.Rank, m.Change)
+}
+
+// MoverScanner scans top coins for extreme hourly moves. Coins are alerted
+// on when their move reaches the threshold and re-armed once it falls back.
+type MoverScanner struct {
+	Threshold float64
+	Top       int
+	moving    map[string]bool
+}
+
+// NewMoverScanner creates and returns an instance of MoverScanner
+func NewMoverScanner(threshold float64, top int) *MoverScanner {
+	return &MoverScanner{
+		Threshold: threshold,
+		Top:       top,
+		moving:    make(map[string]bool),
+	}
+}
+
+// Check returns alerts for coins among the top coins of coinsData whose 1
+// hour change reached the threshold, either way, since the previous check
+func (s *MoverScanner) Check(coinsData geckoTypes.CoinsMarket) []MoverAlert {
+	alerts := []MoverAlert{}
+	moving := make(map[string]bool)
+
+	for _, val := range coinsData {
+		if val.MarketCapRank < 1 || int(val.MarketCapRank) > s.Top || val.PriceChangePercentage1hInCurrency == nil {
+			continue
+		}
+
+		change := *val.PriceChangePercentage1hInCurrency
+		if math.Abs(change) < s.Threshold {
+			continue
+		}
+
+		moving[val.ID] = true
+		if s.moving[val.ID] {
+			continue
+		}
+
+		alerts = append(alerts, MoverAlert{
+			ID:     val.ID,
+			Symbol: strings.ToUpper(val.Symbol),
+			Rank:   int(val.MarketCapRank),
+			Change: change,
+		})
+	}
+	s.moving = moving
+
+	return alerts
+}
@@ -0,0 +1,284 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
+)
+
+const (
+	newsRefresh    = time.Duration(5) * time.Minute
+	maxHeadlines   = 30
+	cryptopanicURL = "https://cryptopanic.com/api/v1/posts/"
+)
+
+// newsFeeds are RSS feeds headlines are taken from when no CryptoPanic API
+// key is stored
+var newsFeeds = []string{
+	"https://www.coindesk.com/arc/outboundfeeds/rss/",
+	"https://cointelegraph.com/rss",
+	"https://decrypt.co/feed",
+}
+
+// Headline holds a news headline about a coin
+type Headline struct {
+	Title     string
+	Source    string
+	URL       string
+	Published time.Time
+}
+
+// rssFeed holds items of an RSS feed
+type rssFeed struct {
+	Channel struct {
+		Title string `xml:"title"`
+		Items []struct {
+			Title      string   `xml:"title"`
+			Link       string   `xml:"link"`
+			PubDate    string   `xml:"pubDate"`
+			Categories []string `xml:"category"`
+		} `xml:"item"`
+	} `xml:"channel"`
+}
+
+// cryptopanicPosts holds news posts from CryptoPanic
+type cryptopanicPosts struct {
+	Results []struct {
+		Title       string    `json:"title"`
+		URL         string    `json:"url"`
+		PublishedAt time.Time `json:"published_at"`
+		Source      struct {
+			Title string `json:"title"`
+		} `json:"source"`
+	} `json:"results"`
+}
+
+// newsClient is used for news requests, timed out so a slow feed doesn't
+// hold up the others. Requests are recorded and rate limited like those of
+// Client.
+var newsClient = &http.Client{
+	Timeout:   time.Duration(10) * time.Second,
+	Transport: apiTransport{},
+}
+
+// getNews fetches url with newsClient, returning a StatusError on a non 200
+// status
+func getNews(url string, decode func(*http.Response) error) error {
+	res, err := newsClient.Get(url)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return &StatusError{URL: url, Code: res.StatusCode}
+	}
+
+	return decode(res)
+}
+
+// mentions returns a function reporting if text mentions a coin, by its name
+// in any case or its symbol in upper case, Eg: Bitcoin or BTC
+func mentions(id CoinID) func(text string) bool {
+	name := strings.ReplaceAll(id.CoinGeckoID, "-", " ")
+	byName := regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(name) + `\b`)
+	bySymbol := regexp.MustCompile(`\b` + regexp.QuoteMeta(strings.ToUpper(id.Symbol)) + `\b`)
+
+	return func(text string) bool {
+		return (name != "" && byName.MatchString(text)) || (id.Symbol != "" && bySymbol.MatchString(text))
+	}
+}
+
+// getFeedHeadlines returns headlines of the RSS feeds mentioning a coin in
+// their title or categories. Feeds which can't be fetched are left out,
+// unless none can be.
+func getFeedHeadlines(id CoinID) ([]Headline, error) {
+	mentioned := mentions(id)
+
+	headlines := []Headline{}
+	var lastErr error
+	fetched := 0
+
+	for _, feedURL := range newsFeeds {
+		feed := rssFeed{}
+		err := getNews(feedURL, func(res *http.Response) error {
+			return xml.NewDecoder(res.Body).Decode(&feed)
+		})
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		fetched++
+
+		source := strings.TrimSpace(feed.Channel.Title)
+		if u, err := url.Parse(feedURL); err == nil && source == "" {
+			source = strings.TrimPrefix(u.Host, "www.")
+		}
+
+		for _, item := range feed.Channel.Items {
+			if !mentioned(item.Title + " " + strings.Join(item.Categories, " ")) {
+				continue
+			}
+
+			published, _ := time.Parse(time.RFC1123Z, strings.TrimSpace(item.PubDate))
+			if published.IsZero() {
+				published, _ = time.Parse(time.RFC1123, strings.TrimSpace(item.PubDate))
+			}
+
+			headlines = append(headlines, Headline{
+				Title:     strings.TrimSpace(item.Title),
+				Source:    source,
+				URL:       strings.TrimSpace(item.Link),
+				Published: published,
+			})
+		}
+	}
+
+	if fetched == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+
+	return headlines, nil
+}
+
+// getCryptopanicHeadlines returns headlines about a coin from CryptoPanic
+func getCryptopanicHeadlines(id CoinID, key string) ([]Headline, error) {
+	query := url.Values{}
+	query.Set("auth_token", key)
+	query.Set("currencies", strings.ToUpper(id.Symbol))
+	query.Set("kind", "news")
+	query.Set("public", "true")
+
+	posts := cryptopanicPosts{}
+	err := getNews(cryptopanicURL+"?"+query.Encode(), func(res *http.Response) error {
+		return json.NewDecoder(res.Body).Decode(&posts)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	headlines := []Headline{}
+	for _, post := range posts.Results {
+		headlines = append(headlines, Headline{
+			Title:     post.Title,
+			Source:    post.Source.Title,
+			URL:       post.URL,
+			Published: post.PublishedAt,
+		})
+	}
+
+	return headlines, nil
+}
+
+// GetHeadlines returns the latest headlines about a coin, newest first. They
+// are taken from CryptoPanic if an API key is stored for it, else from the
+// RSS feeds of crypto news sites.
+func GetHeadlines(id CoinID, limit int) ([]Headline, error) {
+	if id.Symbol == "" && id.CoinGeckoID == "" {
+		return nil, fmt.Errorf("%w for news", ErrNotListed)
+	}
+
+	var headlines []Headline
+	var err error
+	if key, _ := utils.GetAPIKey("cryptopanic"); key != "" {
+		headlines, err = getCryptopanicHeadlines(id, key)
+	} else {
+		headlines, err = getFeedHeadlines(id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(headlines, func(i, j int) bool {
+		return headlines[i].Published.After(headlines[j].Published)
+	})
+	if len(headlines) > limit {
+		headlines = headlines[:limit]
+	}
+
+	return headlines, nil
+}
+
+// GetCoinNews fetches headlines about a coin every 5 minutes while enabled
+// through the news channel, and sends them on dataChannel. Headlines are
+// fetched as soon as they are enabled.
+func GetCoinNews(ctx context.Context, id CoinID, newsChannel chan bool, dataChannel chan CoinData) error {
+	enabled := false
+	fetched := time.Time{}
+
+	return utils.LoopTick(ctx, time.Duration(1)*time.Second, func(errChan chan error) {
+		var finalErr error = nil
+
+		defer func() {
+			if finalErr != nil {
+				errChan <- finalErr
+			}
+		}()
+
+		select {
+		case <-ctx.Done():
+			finalErr = ctx.Err()
+			return
+		case e := <-newsChannel:
+			// Update state, fetching again once enabled
+			enabled = e
+			fetched = time.Time{}
+		default:
+			break
+		}
+
+		if !enabled || time.Since(fetched) < utils.PollInterval(newsRefresh) {
+			return
+		}
+		fetched = time.Now()
+
+		// News is optional and comes from sources outside those of prices,
+		// so failures are shown with the headlines rather than closing the
+		// coin page
+		headlines, err := GetHeadlines(id, maxHeadlines)
+		trackStatus("news", newsRefresh, err)
+		coinData := CoinData{
+			Type: "NEWS",
+			News: headlines,
+		}
+		if err != nil {
+			coinData.Degraded = "unavailable"
+			if !isUnavailable(err) {
+				coinData.Degraded = "unreadable"
+			}
+		}
+
+		// Send Data
+		select {
+		case <-ctx.Done():
+			finalErr = ctx.Err()
+			return
+		case dataChannel <- coinData:
+		}
+	})
+}
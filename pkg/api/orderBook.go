@@ -0,0 +1,147 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
+)
+
+// orderBookLevels is the number of bids and asks fetched
+const orderBookLevels = 20
+
+// OrderLevel holds the quantity ordered at a price
+type OrderLevel struct {
+	Price    float64
+	Quantity float64
+}
+
+// OrderBook holds bids (highest first) and asks (lowest first) of a market
+type OrderBook struct {
+	Bids []OrderLevel
+	Asks []OrderLevel
+}
+
+// binanceDepth holds an order book from Binance, with levels as price and
+// quantity pairs
+type binanceDepth struct {
+	Bids [][]string `json:"bids"`
+	Asks [][]string `json:"asks"`
+}
+
+// GetOrderBook returns the top bids and asks of a coin's USDT market on
+// Binance
+func GetOrderBook(id CoinID, levels int) (OrderBook, error) {
+	pair, err := binancePair(id)
+	if err != nil {
+		return OrderBook{}, err
+	}
+
+	url := fmt.Sprintf("%s/depth?symbol=%s&limit=%d", binanceURL, pair, levels)
+
+	depth := binanceDepth{}
+	err = getJSON(url, &depth)
+	if err != nil {
+		// Binance responds with 400 for unknown pairs
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && statusErr.Code == http.StatusBadRequest {
+			return OrderBook{}, fmt.Errorf("%w on Binance as %s", ErrNotListed, pair)
+		}
+		return OrderBook{}, err
+	}
+
+	parse := func(entries [][]string) []OrderLevel {
+		orderLevels := []OrderLevel{}
+		for _, entry := range entries {
+			if len(entry) < 2 {
+				continue
+			}
+			price, err := strconv.ParseFloat(entry[0], 64)
+			if err != nil {
+				continue
+			}
+			quantity, err := strconv.ParseFloat(entry[1], 64)
+			if err != nil {
+				continue
+			}
+			orderLevels = append(orderLevels, OrderLevel{Price: price, Quantity: quantity})
+		}
+		return orderLevels
+	}
+
+	return OrderBook{Bids: parse(depth.Bids), Asks: parse(depth.Asks)}, nil
+}
+
+// GetCoinOrderBook fetches the order book of a coin every refreshInterval
+// while enabled through the book channel, and sends it on dataChannel
+func GetCoinOrderBook(ctx context.Context, id CoinID, refreshInterval time.Duration, bookChannel chan bool, dataChannel chan CoinData) error {
+	enabled := false
+
+	return utils.LoopTick(ctx, refreshInterval, func(errChan chan error) {
+		var finalErr error = nil
+
+		defer func() {
+			if finalErr != nil {
+				errChan <- finalErr
+			}
+		}()
+
+		select {
+		case <-ctx.Done():
+			finalErr = ctx.Err()
+			return
+		case e := <-bookChannel:
+			// Update state
+			enabled = e
+		default:
+			break
+		}
+
+		if !enabled {
+			return
+		}
+
+		// The order book is optional, so an empty book is shown while
+		// Binance is unavailable rather than closing the coin page
+		book, err := GetOrderBook(id, orderBookLevels)
+		trackStatus("order book", refreshInterval, err)
+		if err != nil && !isUnavailable(err) {
+			finalErr = err
+			return
+		}
+
+		// Aggregate data
+		coinData := CoinData{
+			Type:      "ORDERBOOK",
+			OrderBook: book,
+		}
+
+		// Send Data
+		select {
+		case <-ctx.Done():
+			finalErr = ctx.Err()
+			return
+		case dataChannel <- coinData:
+		}
+	})
+}
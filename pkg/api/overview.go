@@ -0,0 +1,91 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
+)
+
+// overviewInterval is how often the global market overview is refreshed
+const overviewInterval = time.Duration(1) * time.Minute
+
+// GlobalOverview holds totals of the whole crypto market
+type GlobalOverview struct {
+	MarketCap       float64 // Total market cap in USD
+	MarketCapChange float64 // Change of the total market cap over 24 hours, in percent
+	Volume          float64 // Total volume over 24 hours in USD
+	BTCDominance    float64 // Share of the total market cap held by BTC, in percent
+	ActiveAssets    int
+	Markets         int
+}
+
+// GetGlobalOverview serves totals of the crypto market from CoinGecko's
+// global endpoint every minute, for the header of the main page. Refreshes
+// are skipped while CoinGecko is unavailable, keeping the last overview
+// shown.
+func GetGlobalOverview(ctx context.Context, dataChannel chan AssetData, sendData *bool) error {
+
+	// Init Client
+	geckoClient := NewGeckoClient()
+
+	return utils.LoopTick(ctx, overviewInterval, func(errChan chan error) {
+		var finalErr error = nil
+		data := AssetData{}
+
+		defer func() {
+			if finalErr != nil {
+				errChan <- finalErr
+			}
+		}()
+
+		if !*sendData {
+			return
+		}
+
+		// Fetch Data
+		global, err := geckoClient.Global()
+		trackStatus("overview", overviewInterval, err)
+		if err != nil {
+			if !isUnavailable(err) {
+				finalErr = err
+			}
+			return
+		}
+
+		// Aggregate data
+		data.IsOverviewData = true
+		data.Overview = GlobalOverview{
+			MarketCap:       global.TotalMarketCap["usd"],
+			MarketCapChange: float64(global.MarketCapChangePercentage24hUSD),
+			Volume:          global.TotalVolume["usd"],
+			BTCDominance:    global.MarketCapPercentage["btc"],
+			ActiveAssets:    int(global.ActiveCryptocurrencies),
+			Markets:         int(global.Markets),
+		}
+
+		// Send data
+		select {
+		case <-ctx.Done():
+			finalErr = ctx.Err()
+			return
+		case dataChannel <- data:
+		}
+	})
+}
@@ -0,0 +1,78 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"time"
+
+	geckoTypes "github.com/superoo7/go-gecko/v3/types"
+)
+
+// PriceRatio returns the price of base in terms of quote at every timestamp
+// of base. Both histories are expected in ascending order of time. As the
+// two coins are not sampled at the same instants, the quote price is linearly
+// interpolated between its neighbouring samples. Points of base lying outside
+// the time range of quote are dropped. The times of the points kept are
+// returned alongside.
+func PriceRatio(base, quote []geckoTypes.ChartItem) ([]float64, []time.Time) {
+	ratio := []float64{}
+	times := []time.Time{}
+	if len(quote) == 0 {
+		return ratio, times
+	}
+
+	j := 0
+	for _, b := range base {
+		t := b[0]
+
+		// Skip points before quote history begins
+		if t < quote[0][0] {
+			continue
+		}
+
+		// Move to the quote sample at or after t
+		for j < len(quote)-1 && quote[j+1][0] < t {
+			j++
+		}
+		if j == len(quote)-1 {
+			if t != quote[j][0] {
+				break
+			}
+		}
+
+		// Interpolate quote price at t
+		quotePrice := float64(quote[j][1])
+		if j < len(quote)-1 && quote[j+1][0] != quote[j][0] {
+			t0, t1 := float64(quote[j][0]), float64(quote[j+1][0])
+			p0, p1 := float64(quote[j][1]), float64(quote[j+1][1])
+			quotePrice = p0 + (p1-p0)*(float64(t)-t0)/(t1-t0)
+		}
+
+		if quotePrice == 0 {
+			continue
+		}
+		ratio = append(ratio, float64(b[1])/quotePrice)
+		times = append(times, chartTime(b))
+	}
+
+	return ratio, times
+}
+
+// chartTime returns the time of a chart item, which holds unix milliseconds
+func chartTime(item geckoTypes.ChartItem) time.Time {
+	return time.Unix(0, int64(item[0])*int64(time.Millisecond))
+}
@@ -0,0 +1,144 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
+	geckoTypes "github.com/superoo7/go-gecko/v3/types"
+)
+
+// HistoryWorkers is the number of histories GetHistories fetches at once,
+// set from the config file. Requests are rate limited by provider whatever
+// the number of workers, so more workers only fill in faster while the rate
+// limit allows.
+var HistoryWorkers = 4
+
+// MaxHistoryWorkers is the most HistoryWorkers allowed
+const MaxHistoryWorkers = 16
+
+// HistoryResult holds history of a coin fetched by GetHistories
+type HistoryResult struct {
+	ID      CoinID
+	History []geckoTypes.ChartItem
+	Err     error
+}
+
+// historyCall is a request of history in flight, shared by requests of the
+// same history made meanwhile
+type historyCall struct {
+	done    chan struct{}
+	history []geckoTypes.ChartItem
+	err     error
+}
+
+// historyFlights holds requests of history in flight by key
+var historyFlights = struct {
+	sync.Mutex
+	calls map[string]*historyCall
+}{calls: make(map[string]*historyCall)}
+
+// coalescedHistory returns history of a coin from src over days, joining a
+// request in flight for the same history rather than sending another. The
+// history returned may be shared, so it must not be modified.
+func coalescedHistory(src Source, id CoinID, days int) ([]geckoTypes.ChartItem, error) {
+	key := fmt.Sprintf("%s %s %s %d", src.Name(), id.CoinGeckoID, id.CoinCapID, days)
+
+	historyFlights.Lock()
+	if call, ok := historyFlights.calls[key]; ok {
+		historyFlights.Unlock()
+		<-call.done
+		return call.history, call.err
+	}
+	call := &historyCall{done: make(chan struct{})}
+	historyFlights.calls[key] = call
+	historyFlights.Unlock()
+
+	call.history, call.err = src.GetHistory(id, days)
+
+	historyFlights.Lock()
+	delete(historyFlights.calls, key)
+	historyFlights.Unlock()
+	close(call.done)
+
+	return call.history, call.err
+}
+
+// GetHistories fetches history of coins from src over days, HistoryWorkers
+// at once, sending each on results as soon as it is fetched. Requests of a
+// history already in flight are joined. It returns once all histories were
+// sent or ctx is cancelled.
+func GetHistories(ctx context.Context, src Source, ids []CoinID, days int, results chan HistoryResult) error {
+	utils.RLockSettings()
+	workers := HistoryWorkers
+	utils.RUnlockSettings()
+	if workers < 1 {
+		workers = 1
+	}
+
+	jobs := make(chan CoinID)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range jobs {
+				history, err := coalescedHistory(src, id, days)
+				select {
+				case <-ctx.Done():
+					return
+				case results <- HistoryResult{ID: id, History: history, Err: err}:
+				}
+			}
+		}()
+	}
+
+	// Queue coins till all are queued or ctx is cancelled
+queue:
+	for _, id := range ids {
+		select {
+		case <-ctx.Done():
+			break queue
+		case jobs <- id:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return ctx.Err()
+}
+
+// getHistories returns histories of coins fetched by GetHistories, by
+// CoinGecko ID
+func getHistories(ctx context.Context, src Source, ids []CoinID, days int) (map[string]HistoryResult, error) {
+	results := make(chan HistoryResult)
+	done := make(chan error, 1)
+	go func() {
+		done <- GetHistories(ctx, src, ids, days, results)
+		close(results)
+	}()
+
+	histories := make(map[string]HistoryResult)
+	for result := range results {
+		histories[result.ID.CoinGeckoID] = result
+	}
+
+	return histories, <-done
+}
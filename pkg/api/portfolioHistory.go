@@ -0,0 +1,222 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
+	geckoTypes "github.com/superoo7/go-gecko/v3/types"
+)
+
+const (
+	// PortfolioHistoryInterval is the interval value history of the
+	// portfolio page opens with
+	PortfolioHistoryInterval = "30d"
+	// portfolioHistoryRefresh is how often value history is computed again
+	// for the same holdings and interval
+	portfolioHistoryRefresh = time.Duration(10) * time.Minute
+	// portfolioHistoryRetry is how long value history is computed again
+	// after no history of holdings could be fetched
+	portfolioHistoryRetry = time.Duration(1) * time.Minute
+)
+
+// PortfolioHistory holds the value of the current quantities of holdings
+// over an interval, in USD
+type PortfolioHistory struct {
+	Interval string   // Interval of history, Eg: 30d
+	Points   []Point  // Values in ascending order of time
+	Missing  []CoinID // Holdings without history, left out
+}
+
+// portfolioValues returns values of holdings at the times of the longest of
+// their histories, keyed by CoinGecko ID. Holdings are valued at their last
+// price at or before each time, and times before a holding was first priced
+// are left out. Holdings without history are returned as missing.
+func portfolioValues(holdings []PortfolioHolding, histories map[string][]geckoTypes.ChartItem) ([]Point, []CoinID) {
+	held := []PortfolioHolding{}
+	missing := []CoinID{}
+	var longest []geckoTypes.ChartItem
+	for _, h := range holdings {
+		history := histories[h.ID.CoinGeckoID]
+		if len(history) == 0 {
+			missing = append(missing, h.ID)
+			continue
+		}
+		held = append(held, h)
+		if len(history) > len(longest) {
+			longest = history
+		}
+	}
+
+	points := []Point{}
+	for _, item := range longest {
+		ms := item[0]
+		value := 0.0
+		priced := true
+		for _, h := range held {
+			history := histories[h.ID.CoinGeckoID]
+			i := sort.Search(len(history), func(i int) bool { return history[i][0] > ms }) - 1
+			if i < 0 {
+				priced = false
+				break
+			}
+			value += h.Quantity * float64(history[i][1])
+		}
+		if priced {
+			points = append(points, Point{
+				Time:  time.Unix(0, int64(ms)*int64(time.Millisecond)),
+				Price: value,
+			})
+		}
+	}
+
+	return points, missing
+}
+
+// GetPortfolioHistory serves the value over time of the holdings received
+// through the holdings channel, sorted by CoinGecko ID, for the value graph
+// of the portfolio page. History is fetched over the interval received
+// through the interval channel, PortfolioHistoryInterval till one is
+// received, and computed again every portfolioHistoryRefresh for the same
+// holdings and interval. Histories are cached like those of the coin page.
+func GetPortfolioHistory(ctx context.Context, holdingsChannel chan []PortfolioHolding, intervalChannel chan string, dataChannel chan AssetData, sendData *bool) error {
+	holdings := []PortfolioHolding{}
+	interval := PortfolioHistoryInterval
+	var computed []PortfolioHolding
+	computedInterval := ""
+	var computedAt time.Time
+
+	// Ticks may outlast a computation, which runs one at a time
+	var m sync.Mutex
+	computing := false
+
+	return utils.LoopTick(ctx, time.Duration(1)*time.Second, func(errChan chan error) {
+		var finalErr error = nil
+
+		defer func() {
+			if finalErr != nil {
+				errChan <- finalErr
+			}
+		}()
+
+		m.Lock()
+		if computing {
+			m.Unlock()
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			m.Unlock()
+			finalErr = ctx.Err()
+			return
+		case newHoldings := <-holdingsChannel:
+			// Update holdings
+			holdings = newHoldings
+		case newInterval := <-intervalChannel:
+			// Update interval
+			interval = newInterval
+		default:
+			break
+		}
+
+		// Compute once holdings or the interval change, or the last value
+		// history is out of date
+		due := !sameHoldings(holdings, computed) || interval != computedInterval ||
+			time.Since(computedAt) >= utils.PollInterval(portfolioHistoryRefresh)
+		if len(holdings) == 0 || !due || !*sendData {
+			m.Unlock()
+			return
+		}
+		days, err := HistoryDays(interval)
+		if err != nil {
+			m.Unlock()
+			finalErr = err
+			return
+		}
+		computing = true
+		current := holdings
+		computed = current
+		computedInterval = interval
+		computedAt = time.Now()
+		m.Unlock()
+
+		defer func() {
+			m.Lock()
+			computing = false
+			m.Unlock()
+		}()
+
+		ids := []CoinID{}
+		for _, h := range current {
+			ids = append(ids, h.ID)
+		}
+
+		results, err := getHistories(ctx, geckoSource{}, ids, days)
+		if err != nil {
+			finalErr = err
+			return
+		}
+
+		// Holdings whose history can't be fetched are left out
+		histories := make(map[string][]geckoTypes.ChartItem)
+		var lastErr error
+		for id, result := range results {
+			if result.Err == nil {
+				histories[id] = result.History
+			} else {
+				lastErr = result.Err
+			}
+		}
+
+		points, missing := portfolioValues(current, histories)
+		if len(points) == 0 && lastErr != nil {
+			trackStatus("portfolio history", portfolioHistoryRefresh, lastErr)
+			m.Lock()
+			computedAt = time.Now().Add(portfolioHistoryRetry - utils.PollInterval(portfolioHistoryRefresh))
+			m.Unlock()
+			// Value history is optional, so only invalid requests are
+			// reported
+			if !isUnavailable(lastErr) {
+				finalErr = lastErr
+			}
+			return
+		}
+		trackStatus("portfolio history", portfolioHistoryRefresh, nil)
+
+		for !*sendData {
+			select {
+			case <-ctx.Done():
+				finalErr = ctx.Err()
+				return
+			case <-time.After(time.Second):
+			}
+		}
+
+		// Send data
+		history := PortfolioHistory{Interval: interval, Points: points, Missing: missing}
+		select {
+		case <-ctx.Done():
+			finalErr = ctx.Err()
+		case dataChannel <- AssetData{IsPortfolioHistoryData: true, PortfolioHistory: history}:
+		}
+	})
+}
@@ -0,0 +1,146 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Refresh priorities which can be assigned to a coin
+const (
+	PriorityHigh   = "high"
+	PriorityNormal = "normal"
+	PriorityLow    = "low"
+)
+
+// RefreshPolicy describes how data of a coin is served. Live determines if a
+// websocket is allocated to stream prices, while the intervals determine how
+// often history and details are polled.
+type RefreshPolicy struct {
+	Priority        string
+	Live            bool
+	HistoryInterval time.Duration
+	DetailsInterval time.Duration
+}
+
+// refreshMutex guards refresh policies and how often assets and favourites
+// are polled, as they are set again when the config file is reloaded while
+// pollers read them
+var refreshMutex sync.RWMutex
+
+var refreshPolicies = map[string]RefreshPolicy{
+	PriorityHigh: {
+		Priority:        PriorityHigh,
+		Live:            true,
+		HistoryInterval: time.Duration(1) * time.Second,
+		DetailsInterval: time.Duration(1) * time.Second,
+	},
+	PriorityNormal: {
+		Priority:        PriorityNormal,
+		Live:            true,
+		HistoryInterval: time.Duration(3) * time.Second,
+		DetailsInterval: time.Duration(10) * time.Second,
+	},
+	PriorityLow: {
+		Priority:        PriorityLow,
+		Live:            false,
+		HistoryInterval: time.Duration(60) * time.Second,
+		DetailsInterval: time.Duration(60) * time.Second,
+	},
+}
+
+// MinPollRefresh is the shortest interval assets and favourites may be
+// polled at, keeping within rate limits of providers
+const MinPollRefresh = time.Duration(2) * time.Second
+
+// assetsRefresh is how often market data of top coins is polled, and
+// favouritesRefresh how often prices of favourites are, set from the config
+// file
+var (
+	assetsRefresh     = time.Duration(10) * time.Second
+	favouritesRefresh = time.Duration(10) * time.Second
+)
+
+// AssetsRefresh returns how often market data of top coins is polled
+func AssetsRefresh() time.Duration {
+	refreshMutex.RLock()
+	defer refreshMutex.RUnlock()
+	return assetsRefresh
+}
+
+// FavouritesRefresh returns how often prices of favourites are polled
+func FavouritesRefresh() time.Duration {
+	refreshMutex.RLock()
+	defer refreshMutex.RUnlock()
+	return favouritesRefresh
+}
+
+// SetPollRefresh sets how often top coins and prices of favourites are
+// polled, at least every MinPollRefresh
+func SetPollRefresh(assets, favourites time.Duration) error {
+	if assets < MinPollRefresh || favourites < MinPollRefresh {
+		return fmt.Errorf("refresh of assets and favourites must be at least %s", MinPollRefresh)
+	}
+	refreshMutex.Lock()
+	assetsRefresh, favouritesRefresh = assets, favourites
+	refreshMutex.Unlock()
+	return nil
+}
+
+// GetRefreshPolicy returns the RefreshPolicy for a given priority. If the
+// priority is unknown, the policy for normal priority is returned
+func GetRefreshPolicy(priority string) RefreshPolicy {
+	refreshMutex.RLock()
+	defer refreshMutex.RUnlock()
+
+	if policy, ok := refreshPolicies[priority]; ok {
+		return policy
+	}
+	return refreshPolicies[PriorityNormal]
+}
+
+// SetRefreshIntervals sets how often history and details of coins with
+// normal priority are polled
+func SetRefreshIntervals(history, details time.Duration) error {
+	if history <= 0 || details <= 0 {
+		return fmt.Errorf("refresh intervals must be positive")
+	}
+
+	refreshMutex.Lock()
+	defer refreshMutex.Unlock()
+
+	policy := refreshPolicies[PriorityNormal]
+	policy.HistoryInterval = history
+	policy.DetailsInterval = details
+	refreshPolicies[PriorityNormal] = policy
+	return nil
+}
+
+// NextPriority returns the priority following the given one, cycling
+// normal -> high -> low -> normal
+func NextPriority(priority string) string {
+	switch priority {
+	case PriorityHigh:
+		return PriorityLow
+	case PriorityLow:
+		return PriorityNormal
+	default:
+		return PriorityHigh
+	}
+}
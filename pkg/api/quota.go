@@ -0,0 +1,280 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
+)
+
+// AdaptiveQuota determines if refreshes are stretched when a provider's
+// quota is about to run out, set from the config file
+var AdaptiveQuota = true
+
+// adaptiveQuota returns AdaptiveQuota, read under the settings lock
+func adaptiveQuota() bool {
+	utils.RLockSettings()
+	defer utils.RUnlockSettings()
+	return AdaptiveQuota
+}
+
+const (
+	// quotaWindow is the window quotas are taken to renew over when
+	// providers don't send when they do
+	quotaWindow = time.Duration(1) * time.Minute
+
+	// quotaExpiry is how long a quota read from a provider is trusted for
+	// without hearing from it again
+	quotaExpiry = time.Duration(5) * time.Minute
+
+	// minQuotaSample is the least time usage of a quota is measured over
+	// before forecasting, so a burst of requests isn't taken as the rate
+	minQuotaSample = time.Duration(5) * time.Second
+
+	// maxStretch is the most intervals between refreshes are stretched by
+	maxStretch = 8
+
+	// quotaMargin is how much more intervals are stretched by than needed,
+	// to make up for usage not being exactly proportional to refreshes
+	quotaMargin = 1.1
+
+	// binanceWeightLimit is the request weight Binance allows a minute
+	binanceWeightLimit = 6000
+)
+
+// quota holds the request quota of a provider, read from the headers of its
+// responses
+type quota struct {
+	remaining int
+	reset     time.Time // When the quota renews
+	updated   time.Time
+
+	// Usage of the current window is measured from its first response
+	windowStart    time.Time
+	startRemaining int
+	startStretch   float64
+	needs          float64 // Stretch needed to last till reset
+}
+
+var quotas = struct {
+	sync.Mutex
+	providers map[string]*quota
+}{providers: make(map[string]*quota)}
+
+// providerOf returns the provider requests to host are sent to, or an empty
+// string if it isn't known
+func providerOf(host string) string {
+	for name, hosts := range rateLimitHosts {
+		for _, h := range hosts {
+			if h == host {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// parseQuota reads the quota left from the headers of a response. Both the
+// common X-RateLimit headers and Binance's used weight are understood, as
+// is Retry-After on 429 responses.
+func parseQuota(res *http.Response, now time.Time) (remaining int, reset time.Time, ok bool) {
+	header := res.Header
+	reset = now.Add(quotaWindow)
+
+	if res.StatusCode == http.StatusTooManyRequests {
+		if secs, err := strconv.Atoi(header.Get("Retry-After")); err == nil {
+			reset = now.Add(time.Duration(secs) * time.Second)
+		}
+		return 0, reset, true
+	}
+
+	if used, err := strconv.Atoi(header.Get("X-Mbx-Used-Weight-1m")); err == nil {
+		return binanceWeightLimit - used, now.Truncate(time.Minute).Add(time.Minute), true
+	}
+
+	remaining, err := strconv.Atoi(header.Get("X-Ratelimit-Remaining"))
+	if err != nil {
+		return 0, reset, false
+	}
+
+	// Resets are sent as either a unix time or seconds left
+	if secs, err := strconv.ParseInt(header.Get("X-Ratelimit-Reset"), 10, 64); err == nil {
+		if secs > 1e9 {
+			reset = time.Unix(secs, 0)
+		} else {
+			reset = now.Add(time.Duration(secs) * time.Second)
+		}
+	}
+
+	return remaining, reset, true
+}
+
+// observeQuota updates the quota of the provider a response came from, and
+// stretches refreshes if any quota is forecast to run out before it renews
+func observeQuota(res *http.Response) {
+	if res == nil || res.Request == nil {
+		return
+	}
+	provider := providerOf(res.Request.URL.Hostname())
+	if provider == "" {
+		return
+	}
+
+	now := time.Now()
+	remaining, reset, ok := parseQuota(res, now)
+	if !ok {
+		return
+	}
+
+	quotas.Lock()
+	defer quotas.Unlock()
+
+	q, ok := quotas.providers[provider]
+	if !ok {
+		q = &quota{}
+		quotas.providers[provider] = q
+	}
+
+	// A new window starts once the quota renews
+	if q.windowStart.IsZero() || now.After(q.reset) || remaining > q.remaining {
+		q.windowStart = now
+		q.startRemaining = remaining
+		q.startStretch = utils.Stretch()
+	}
+
+	q.remaining, q.reset, q.updated = remaining, reset, now
+	q.needs = q.needed(now)
+
+	if adaptiveQuota() {
+		utils.SetStretch(neededStretch(now))
+	}
+}
+
+// needed returns how many times intervals between refreshes must be
+// stretched for the quota to last till it renews, 1 if it lasts as is
+func (q *quota) needed(now time.Time) float64 {
+	elapsed := now.Sub(q.windowStart)
+	resetIn := q.reset.Sub(now)
+	if resetIn <= 0 {
+		return 1
+	}
+	if q.remaining <= 0 {
+		return maxStretch
+	}
+	if elapsed < minQuotaSample {
+		return 1
+	}
+
+	// Usage at the cadence refreshes would have without stretching
+	used := float64(q.startRemaining - q.remaining)
+	rate := used / elapsed.Seconds() * q.startStretch
+
+	factor := rate * resetIn.Seconds() / float64(q.remaining) * quotaMargin
+	if factor < 1 {
+		return 1
+	}
+	if factor > maxStretch {
+		return maxStretch
+	}
+	return factor
+}
+
+// exhaustsIn returns how long the quota lasts at the current cadence, and
+// false if it lasts till it renews
+func (q *quota) exhaustsIn(now time.Time) (time.Duration, bool) {
+	resetIn := q.reset.Sub(now)
+	if resetIn <= 0 || now.Sub(q.updated) > quotaExpiry {
+		return 0, false
+	}
+	if q.remaining <= 0 {
+		return 0, true
+	}
+
+	elapsed := now.Sub(q.windowStart)
+	used := q.startRemaining - q.remaining
+	if elapsed < minQuotaSample || used <= 0 {
+		return 0, false
+	}
+
+	left := time.Duration(float64(q.remaining) / float64(used) * float64(elapsed))
+	if left >= resetIn {
+		return 0, false
+	}
+	return left, true
+}
+
+// neededStretch returns the stretch needed for every quota still current to
+// last till it renews. Callers must hold the quotas lock.
+func neededStretch(now time.Time) float64 {
+	factor := 1.0
+	for _, q := range quotas.providers {
+		if now.After(q.reset) || now.Sub(q.updated) > quotaExpiry {
+			continue
+		}
+		if q.needs > factor {
+			factor = q.needs
+		}
+	}
+	return factor
+}
+
+// QuotaStatus returns a short warning for titles of quotas about to run out
+// at the current cadence, and how much refreshes are stretched to make them
+// last, Eg: "coincap quota out in 40s, refresh x2.0". Empty if every quota
+// lasts till it renews.
+func QuotaStatus() string {
+	quotas.Lock()
+	defer quotas.Unlock()
+
+	now := time.Now()
+
+	// Quotas renewed since last heard from no longer need stretching
+	if adaptiveQuota() {
+		utils.SetStretch(neededStretch(now))
+	}
+
+	warnings := []string{}
+	for name, q := range quotas.providers {
+		left, ok := q.exhaustsIn(now)
+		if !ok {
+			continue
+		}
+		if left <= 0 {
+			warnings = append(warnings, fmt.Sprintf("%s quota out till %s", name, q.reset.Local().Format("15:04:05")))
+		} else {
+			warnings = append(warnings, fmt.Sprintf("%s quota out in %s", name, left.Round(time.Second)))
+		}
+	}
+	sort.Strings(warnings)
+
+	status := strings.Join(warnings, ", ")
+	if factor := utils.Stretch(); factor > 1 {
+		if status != "" {
+			status += ", "
+		}
+		status += fmt.Sprintf("refresh x%.1f", factor)
+	}
+
+	return status
+}
@@ -0,0 +1,95 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"strings"
+
+	geckoTypes "github.com/superoo7/go-gecko/v3/types"
+)
+
+// RankAlert is raised when a coin enters or leaves the top N coins by
+// market cap
+type RankAlert struct {
+	ID        string
+	Symbol    string
+	Threshold int
+	Rank      int
+	Entered   bool
+}
+
+// RankTracker tracks whether coins with a rank alert are within the top N
+// coins, to raise alerts when they cross their threshold
+type RankTracker struct {
+	inTop   map[string]bool
+	symbols map[string]string
+}
+
+// NewRankTracker creates and returns an instance of RankTracker
+func NewRankTracker() *RankTracker {
+	return &RankTracker{
+		inTop:   make(map[string]bool),
+		symbols: make(map[string]string),
+	}
+}
+
+// Check returns alerts for coins which crossed their threshold since the
+// previous check. thresholds maps a coin ID to N, where the coin is
+// watched for entering or leaving the top N. Coins missing from coinsData
+// are considered to be outside the top N. The first check of a coin only
+// records where it stands.
+func (r *RankTracker) Check(coinsData geckoTypes.CoinsMarket, thresholds map[string]int) []RankAlert {
+	ranks := make(map[string]int)
+	for _, val := range coinsData {
+		ranks[val.ID] = int(val.MarketCapRank)
+		r.symbols[val.ID] = strings.ToUpper(val.Symbol)
+	}
+
+	alerts := []RankAlert{}
+	for id, threshold := range thresholds {
+		rank, ok := ranks[id]
+		inTop := ok && rank > 0 && rank <= threshold
+
+		prev, seen := r.inTop[id]
+		r.inTop[id] = inTop
+		if !seen || prev == inTop {
+			continue
+		}
+
+		symbol := r.symbols[id]
+		if symbol == "" {
+			symbol = id
+		}
+
+		alerts = append(alerts, RankAlert{
+			ID:        id,
+			Symbol:    symbol,
+			Threshold: threshold,
+			Rank:      rank,
+			Entered:   inTop,
+		})
+	}
+
+	// Forget coins which are no longer watched
+	for id := range r.inTop {
+		if _, ok := thresholds[id]; !ok {
+			delete(r.inTop, id)
+		}
+	}
+
+	return alerts
+}
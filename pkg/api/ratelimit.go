@@ -0,0 +1,198 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rateLimitHosts maps providers whose requests are rate limited to their
+// hosts
+var rateLimitHosts = map[string][]string{
+	"coingecko": {"api.coingecko.com"},
+	"coincap":   {"api.coincap.io"},
+	"binance":   {"api.binance.com", "fapi.binance.com"},
+	"coinbase":  {"api.coinbase.com"},
+	"kraken":    {"api.kraken.com"},
+}
+
+// DefaultRateLimits are the requests per minute allowed to each provider,
+// kept below their public limits
+var DefaultRateLimits = map[string]int{
+	"coingecko": 30,
+	"coincap":   200,
+	"binance":   1200,
+	"coinbase":  150,
+	"kraken":    20,
+}
+
+// tokenBucket allows rate requests per second on average, with bursts of up
+// to burst requests
+type tokenBucket struct {
+	sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newTokenBucket returns a full bucket allowing perMinute requests a minute.
+// Bursts are limited to 10 seconds worth of requests.
+func newTokenBucket(perMinute int) *tokenBucket {
+	burst := float64(perMinute) / 6
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   float64(perMinute) / 60,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+// reserve takes a token and returns how long to wait before it may be used
+func (b *tokenBucket) reserve() time.Duration {
+	b.Lock()
+	defer b.Unlock()
+
+	// Refill tokens for the time passed
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// rateLimiter holds back requests to providers exceeding their rate limit
+// till a token is available, so pollers with their own tickers can't burst
+// past the limit together
+type rateLimiter struct {
+	sync.RWMutex
+	next    http.RoundTripper
+	buckets map[string]*tokenBucket
+	limits  map[string]int
+}
+
+// baseTransport sends requests once rate limited. It is a copy of the
+// default transport, so clients replacing that don't change it.
+var baseTransport = http.DefaultTransport.(*http.Transport).Clone()
+
+var httpLimiter = newRateLimiter(baseTransport)
+
+// RateLimiter returns a transport which only rate limits requests, for
+// clients whose requests mustn't be recorded or cached, Eg: signed requests
+// to exchange accounts
+func RateLimiter() http.RoundTripper {
+	return httpLimiter
+}
+
+// newRateLimiter returns a rate limiter with the default limits
+func newRateLimiter(next http.RoundTripper) *rateLimiter {
+	r := &rateLimiter{
+		next:    next,
+		buckets: make(map[string]*tokenBucket),
+		limits:  make(map[string]int),
+	}
+	for name, perMinute := range DefaultRateLimits {
+		r.set(name, perMinute)
+	}
+	return r
+}
+
+// set limits requests to a provider to perMinute, 0 removes the limit
+func (r *rateLimiter) set(name string, perMinute int) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.limits[name] = perMinute
+	for _, host := range rateLimitHosts[name] {
+		if perMinute > 0 {
+			r.buckets[host] = newTokenBucket(perMinute)
+		} else {
+			delete(r.buckets, host)
+		}
+	}
+}
+
+// limit returns the requests a minute allowed to a provider, 0 if unlimited
+func (r *rateLimiter) limit(name string) int {
+	r.RLock()
+	defer r.RUnlock()
+	return r.limits[name]
+}
+
+// RoundTrip waits for a token of the request's host and sends it, reading
+// the quota left from the response
+func (r *rateLimiter) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.RLock()
+	bucket, ok := r.buckets[req.URL.Hostname()]
+	r.RUnlock()
+
+	if ok {
+		if wait := bucket.reserve(); wait > 0 {
+			t := time.NewTimer(wait)
+			select {
+			case <-req.Context().Done():
+				t.Stop()
+				return nil, req.Context().Err()
+			case <-t.C:
+			}
+		}
+	}
+
+	res, err := r.next.RoundTrip(req)
+	if err == nil {
+		observeQuota(res)
+	}
+	return res, err
+}
+
+// RateLimitProviders returns names of providers which can be rate limited
+func RateLimitProviders() []string {
+	names := []string{}
+	for name := range rateLimitHosts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetRateLimit limits requests to a provider to perMinute requests a
+// minute, 0 removes the limit
+func SetRateLimit(name string, perMinute int) error {
+	if _, ok := rateLimitHosts[name]; !ok {
+		return fmt.Errorf("unknown provider %q, expected one of: %s", name, strings.Join(RateLimitProviders(), ", "))
+	}
+	if perMinute < 0 {
+		return fmt.Errorf("rate limit of %s must not be negative", name)
+	}
+	httpLimiter.set(name, perMinute)
+	return nil
+}
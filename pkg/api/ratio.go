@@ -0,0 +1,138 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
+	geckoTypes "github.com/superoo7/go-gecko/v3/types"
+	"golang.org/x/sync/errgroup"
+)
+
+// DefaultSMADays is the number of days the moving average of a price ratio
+// is taken over by default
+const DefaultSMADays = 50
+
+// SMA returns the simple moving average of values over a trailing window of
+// days, using the times of values. Averages are returned for the points
+// whose window lies entirely within the history, so the result lines up
+// with the last points of values.
+func SMA(values []float64, times []time.Time, days int) []float64 {
+	sma := []float64{}
+	if len(values) == 0 || len(values) != len(times) || days <= 0 {
+		return sma
+	}
+
+	window := time.Duration(days) * 24 * time.Hour
+	start, sum := 0, 0.0
+	for i, val := range values {
+		sum += val
+
+		// Drop points which fell out of the window
+		for times[i].Sub(times[start]) >= window {
+			sum -= values[start]
+			start++
+		}
+
+		if times[i].Sub(times[0]) >= window {
+			sma = append(sma, sum/float64(i-start+1))
+		}
+	}
+
+	return sma
+}
+
+// GetRatioData fetches the price history of base in terms of quote along
+// with its moving average over smaDays from src every refreshInterval, for
+// an interval received through the interval channel. History is fetched
+// smaDays further back, so the average spans the whole interval.
+func GetRatioData(ctx context.Context, src Source, base, quote CoinID, smaDays int, refreshInterval time.Duration, intervalChannel chan string, dataChannel chan RatioData) error {
+	// Set Default Interval
+	i := DefaultInterval
+
+	return utils.LoopTick(ctx, refreshInterval, func(errChan chan error) {
+		var finalErr error = nil
+
+		defer func() {
+			if finalErr != nil {
+				errChan <- finalErr
+			}
+		}()
+
+		select {
+		case <-ctx.Done():
+			finalErr = ctx.Err()
+			return
+		case interval := <-intervalChannel:
+			// Update interval
+			i = interval
+		default:
+			break
+		}
+
+		// Fetch both histories concurrently
+		days := intervalDays[i]
+		var baseHistory, quoteHistory []geckoTypes.ChartItem
+		eg := errgroup.Group{}
+		eg.Go(func() error {
+			history, err := src.GetHistory(base, days+smaDays)
+			baseHistory = history
+			return err
+		})
+		eg.Go(func() error {
+			history, err := src.GetHistory(quote, days+smaDays)
+			quoteHistory = history
+			return err
+		})
+		if err := eg.Wait(); err != nil {
+			finalErr = err
+			return
+		}
+
+		ratio, times := PriceRatio(baseHistory, quoteHistory)
+		sma := SMA(ratio, times, smaDays)
+
+		// Keep points within the interval
+		since := time.Now().AddDate(0, 0, -days)
+		skip := 0
+		for skip < len(times) && times[skip].Before(since) {
+			skip++
+		}
+		ratio, times = ratio[skip:], times[skip:]
+		if len(sma) > len(ratio) {
+			sma = sma[len(sma)-len(ratio):]
+		}
+
+		// Aggregate data
+		data := RatioData{
+			Interval: i,
+			Ratio:    ratio,
+			SMA:      sma,
+			Times:    times,
+		}
+
+		// Send Data
+		select {
+		case <-ctx.Done():
+			finalErr = ctx.Err()
+			return
+		case dataChannel <- data:
+		}
+	})
+}
@@ -0,0 +1,89 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+)
+
+const (
+	// maxReconnects is the number of failed attempts in a row after which a
+	// stream is given up on
+	maxReconnects = 5
+	// Delay before the first reconnect, doubled on every failure up to
+	// maxBackoff
+	baseBackoff = time.Second
+	maxBackoff  = time.Duration(30) * time.Second
+)
+
+// backoff returns the delay before reconnect attempt n (starting at 1). The
+// delay is jittered between half and all of the exponential delay, so that
+// clients don't reconnect in lockstep.
+func backoff(n int) time.Duration {
+	delay := maxBackoff
+	if n < 16 {
+		if d := baseBackoff << uint(n-1); d < maxBackoff {
+			delay = d
+		}
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// reconnect runs stream till ctx is cancelled, reconnecting with jittered
+// exponential backoff when it fails. stream calls connected once it is
+// connected and received on every message. Failures are counted till a
+// message is received, and the last error is returned after maxReconnects
+// failures in a row. Streams which never connected are not retried, so that
+// a fallback source can be used right away.
+func reconnect(ctx context.Context, stream func(connected, received func()) error) error {
+	failures := 0
+	everConnected := false
+
+	connected := func() {
+		everConnected = true
+		setReconnecting(0)
+	}
+	received := func() {
+		failures = 0
+	}
+
+	for {
+		err := stream(connected, received)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !everConnected || errors.Is(err, ErrNotListed) {
+			return err
+		}
+
+		failures++
+		if failures > maxReconnects {
+			setReconnecting(0)
+			return err
+		}
+		setReconnecting(failures)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff(failures)):
+		}
+	}
+}
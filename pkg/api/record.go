@@ -0,0 +1,221 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ReportVersion is the version of the report format, increased on
+// incompatible changes
+const ReportVersion = 1
+
+// maxRecordedBody is the most bytes of a response body kept
+const maxRecordedBody = 2 << 20
+
+// redactedParams are substrings of query parameter names whose values are
+// left out of recorded URLs, Eg: x_cg_pro_api_key
+var redactedParams = []string{"key", "token", "secret", "signature", "password"}
+
+// RecordedResponse holds a raw provider response
+type RecordedResponse struct {
+	Time      time.Time `json:"time"`
+	Method    string    `json:"method"`
+	URL       string    `json:"url"`
+	Status    int       `json:"status"`
+	Body      string    `json:"body"`
+	Truncated bool      `json:"truncated,omitempty"`
+}
+
+// Report holds the raw provider responses backing a page, so chart and
+// formatting bugs can be reproduced from them
+type Report struct {
+	Version   int                `json:"version"`
+	Created   time.Time          `json:"created"`
+	Page      string             `json:"page"`
+	Responses []RecordedResponse `json:"responses"`
+}
+
+// recorder keeps the latest response of each URL requested by Client
+type recorder struct {
+	sync.Mutex
+	next      http.RoundTripper
+	responses map[string]RecordedResponse
+}
+
+var responseRecorder = &recorder{
+	next:      httpCache,
+	responses: make(map[string]RecordedResponse),
+}
+
+// transport holds the transport requests of Client are sent through, which
+// records, caches and rate limits them unless replaced, Eg: by fixtures in
+// tests. Responses served from the cache are recorded as well, but don't
+// count towards rate limits.
+var transport = struct {
+	sync.RWMutex
+	rt http.RoundTripper
+}{rt: responseRecorder}
+
+// apiTransport sends requests through the transport in use
+type apiTransport struct{}
+
+// RoundTrip sends a request through the transport in use
+func (apiTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport.RLock()
+	rt := transport.rt
+	transport.RUnlock()
+	return rt.RoundTrip(req)
+}
+
+// Client is used for requests to data providers, Eg: by the CoinGecko,
+// CoinCap and Binance sources. Requests are timed out so an unresponsive API
+// can be failed over. Other clients, Eg: of exchange accounts, don't go
+// through the recorder and cache, see RateLimiter.
+var Client = &http.Client{
+	Timeout:   time.Duration(10) * time.Second,
+	Transport: apiTransport{},
+}
+
+// SetTransport replaces the transport requests of Client are sent through,
+// returning the one replaced, Eg: to replay fixtures in tests
+func SetTransport(rt http.RoundTripper) http.RoundTripper {
+	transport.Lock()
+	defer transport.Unlock()
+
+	previous := transport.rt
+	transport.rt = rt
+	return previous
+}
+
+// accountHeaders are request headers authenticating a request to an
+// exchange account, Eg: X-MBX-APIKEY
+var accountHeaders = []string{"X-Mbx-Apikey", "Cb-Access-Key", "Api-Key"}
+
+// RoundTrip sends a request and records its response. Responses of requests
+// to exchange accounts are not recorded, so reports don't reveal balances.
+func (r *recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := r.next.RoundTrip(req)
+	if err != nil {
+		return res, err
+	}
+	for _, name := range accountHeaders {
+		if req.Header.Get(name) != "" {
+			return res, nil
+		}
+	}
+
+	// Read body and hand a copy back to the caller
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	res.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return res, nil
+	}
+
+	record := RecordedResponse{
+		Time:   time.Now(),
+		Method: req.Method,
+		URL:    RedactURL(req.URL),
+		Status: res.StatusCode,
+	}
+	if len(body) > maxRecordedBody {
+		body = body[:maxRecordedBody]
+		record.Truncated = true
+	}
+	record.Body = string(body)
+
+	r.Lock()
+	r.responses[record.URL] = record
+	r.Unlock()
+
+	return res, nil
+}
+
+// RedactURL returns u with values of secret query parameters and user info
+// replaced, so it can be saved, Eg: in reports and fixtures
+func RedactURL(u *url.URL) string {
+	redacted := *u
+	redacted.User = nil
+
+	query := redacted.Query()
+	for param := range query {
+		for _, secret := range redactedParams {
+			if strings.Contains(strings.ToLower(param), secret) {
+				query.Set(param, "REDACTED")
+				break
+			}
+		}
+	}
+	redacted.RawQuery = query.Encode()
+
+	return redacted.String()
+}
+
+// GetReport returns the latest responses of URLs requested since a page was
+// opened, oldest first
+func GetReport(page string, since time.Time) Report {
+	responseRecorder.Lock()
+	responses := []RecordedResponse{}
+	for _, record := range responseRecorder.responses {
+		if !record.Time.Before(since) {
+			responses = append(responses, record)
+		}
+	}
+	responseRecorder.Unlock()
+
+	sort.Slice(responses, func(i, j int) bool {
+		return responses[i].Time.Before(responses[j].Time)
+	})
+
+	return Report{
+		Version:   ReportVersion,
+		Created:   time.Now(),
+		Page:      page,
+		Responses: responses,
+	}
+}
+
+// SaveReport writes the report of a page to a timestamped file in the home
+// directory and returns its path
+func SaveReport(page string, since time.Time) (string, error) {
+	report := GetReport(page, since)
+
+	// Get Home directory
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	filePath := filepath.Join(homeDir, "cryptgo-report-"+report.Created.Format("20060102-150405")+".json")
+	return filePath, os.WriteFile(filePath, data, 0644)
+}
@@ -0,0 +1,87 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Provider describes the API a source is served from, so its requests are
+// rate limited and its quota tracked like those of built in providers, and
+// features it can't serve are hidden
+type Provider struct {
+	Name         string       // Name the rate limit is set by in the config file
+	Hosts        []string     // Hosts requests are sent to, Eg: api.kraken.com
+	PerMinute    int          // Requests a minute allowed by default, 0 for no limit
+	Capabilities Capabilities // Features served, Eg: streaming live prices
+}
+
+// RegisterSource makes a source selectable by its name, Eg: from an
+// exchange integration's init function. Like CoinCap and Binance, the
+// source falls back to the default source when it is unavailable or does
+// not list a coin. Sources must be registered before the config is read.
+func RegisterSource(src Source, provider Provider) error {
+	name := src.Name()
+	if name == "" || strings.ToLower(name) != name {
+		return fmt.Errorf("invalid source name %q, expected a lower case name", name)
+	}
+	if provider.PerMinute < 0 {
+		return fmt.Errorf("rate limit of %s must not be negative", provider.Name)
+	}
+
+	sourceMutex.Lock()
+	defer sourceMutex.Unlock()
+
+	if _, ok := sources[name]; ok {
+		return fmt.Errorf("source %q is already registered", name)
+	}
+	if _, ok := rateLimitHosts[provider.Name]; ok {
+		return fmt.Errorf("provider %q is already registered", provider.Name)
+	}
+	for _, host := range provider.Hosts {
+		if known := providerOf(host); known != "" {
+			return fmt.Errorf("host %s is already served by %s", host, known)
+		}
+	}
+
+	sources[name] = newFailoverSource(src, defaultSource{})
+	sourceCapabilities[name] = provider.Capabilities
+
+	if provider.Name != "" {
+		rateLimitHosts[provider.Name] = provider.Hosts
+		DefaultRateLimits[provider.Name] = provider.PerMinute
+		httpLimiter.set(provider.Name, provider.PerMinute)
+		providerCapabilities[provider.Name] = provider.Capabilities
+	}
+
+	return nil
+}
+
+// IsUnavailable returns true if err indicates a source is unavailable or
+// does not serve a coin, in which case requests fall back to another
+// source. Sources should return errors wrapping ErrNotListed or a
+// StatusError so they can be told apart from invalid requests.
+func IsUnavailable(err error) bool {
+	return isUnavailable(err)
+}
+
+// RateLimited returns true if host belongs to a provider, whose requests
+// are rate limited and quota tracked
+func RateLimited(host string) bool {
+	return providerOf(host) != ""
+}
@@ -0,0 +1,336 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
+)
+
+const (
+	// riskDays is the number of days of history risk is computed over
+	riskDays = 180
+	// riskRefresh is how often risk is computed again for the same holdings
+	riskRefresh = time.Duration(1) * time.Hour
+	// riskConfidence is the confidence of value at risk
+	riskConfidence = 0.95
+	// tradingDays is the number of days returns are annualised over, as
+	// coins trade every day
+	tradingDays = 365
+	// riskRetry is how long risk is computed again after bitcoin's
+	// history couldn't be fetched
+	riskRetry = time.Duration(1) * time.Minute
+)
+
+// PortfolioHolding holds the quantity held of a coin
+type PortfolioHolding struct {
+	ID       CoinID
+	Quantity float64
+}
+
+// CoinRisk holds risk metrics of a single holding
+type CoinRisk struct {
+	ID         CoinID
+	Weight     float64 // Share of the portfolio's value, in percent
+	Volatility float64 // Annualised volatility of daily returns, in percent
+	Beta       float64 // Beta of daily returns against bitcoin
+}
+
+// PortfolioRisk holds risk metrics of a portfolio, computed from daily
+// returns of its current holdings over the last riskDays days
+type PortfolioRisk struct {
+	Volatility float64 // Annualised volatility of daily returns, in percent
+	Beta       float64 // Beta of daily returns against bitcoin
+	VaR        float64 // One day historical value at risk, in percent of value
+	Confidence float64 // Confidence of VaR, Eg: 0.95
+	Days       int     // Days of returns the metrics were computed from
+	Coins      []CoinRisk
+	Missing    []CoinID // Holdings without history, left out
+}
+
+// sameHoldings returns true if a and b hold the same quantities of the same
+// coins, in the same order
+func sameHoldings(a, b []PortfolioHolding) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// returnStats holds the mean and variance of returns
+type returnStats struct {
+	mean, variance float64
+}
+
+// statsOf returns the mean and sample variance of returns
+func statsOf(returns []float64) returnStats {
+	s := returnStats{}
+	if len(returns) < 2 {
+		return s
+	}
+	for _, r := range returns {
+		s.mean += r
+	}
+	s.mean /= float64(len(returns))
+	for _, r := range returns {
+		s.variance += (r - s.mean) * (r - s.mean)
+	}
+	s.variance /= float64(len(returns) - 1)
+	return s
+}
+
+// beta returns the beta of returns against market returns of the same days
+func beta(returns, market []float64) float64 {
+	m := statsOf(market)
+	if m.variance == 0 || len(returns) != len(market) {
+		return 0
+	}
+	r := statsOf(returns)
+	cov := 0.0
+	for i := range returns {
+		cov += (returns[i] - r.mean) * (market[i] - m.mean)
+	}
+	cov /= float64(len(returns) - 1)
+	return cov / m.variance
+}
+
+// annualised returns the annualised volatility of daily returns, in percent
+func annualised(returns []float64) float64 {
+	return math.Sqrt(statsOf(returns).variance*tradingDays) * 100
+}
+
+// historicalVaR returns the loss of returns not exceeded with confidence,
+// in percent
+func historicalVaR(returns []float64, confidence float64) float64 {
+	if len(returns) == 0 {
+		return 0
+	}
+	sorted := append([]float64{}, returns...)
+	sort.Float64s(sorted)
+	i := int(math.Floor((1 - confidence) * float64(len(sorted))))
+	return -sorted[i] * 100
+}
+
+// computeRisk returns risk metrics of holdings, valuing their current
+// quantities at the daily prices of each day all of them were priced on.
+// Prices are keyed by CoinGecko ID, and bitcoin's are needed for beta.
+func computeRisk(holdings []PortfolioHolding, prices map[string]map[int64]float64) PortfolioRisk {
+	risk := PortfolioRisk{Confidence: riskConfidence}
+
+	held := []PortfolioHolding{}
+	for _, h := range holdings {
+		if len(prices[h.ID.CoinGeckoID]) == 0 {
+			risk.Missing = append(risk.Missing, h.ID)
+			continue
+		}
+		held = append(held, h)
+	}
+	btc := prices["bitcoin"]
+	if len(held) == 0 || len(btc) == 0 {
+		return risk
+	}
+
+	// Days on which bitcoin and every holding were priced
+	days := []int64{}
+	for day := range btc {
+		priced := true
+		for _, h := range held {
+			if _, ok := prices[h.ID.CoinGeckoID][day]; !ok {
+				priced = false
+				break
+			}
+		}
+		if priced {
+			days = append(days, day)
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i] < days[j] })
+
+	// Returns of the portfolio, each holding and bitcoin between consecutive
+	// days
+	value := func(day int64) float64 {
+		total := 0.0
+		for _, h := range held {
+			total += h.Quantity * prices[h.ID.CoinGeckoID][day]
+		}
+		return total
+	}
+	portfolio, market := []float64{}, []float64{}
+	coins := make([][]float64, len(held))
+	for i := 1; i < len(days); i++ {
+		prev, day := days[i-1], days[i]
+		if day-prev != 1 || value(prev) == 0 || btc[prev] == 0 {
+			continue
+		}
+		portfolio = append(portfolio, value(day)/value(prev)-1)
+		market = append(market, btc[day]/btc[prev]-1)
+		for j, h := range held {
+			p := prices[h.ID.CoinGeckoID]
+			r := 0.0
+			if p[prev] != 0 {
+				r = p[day]/p[prev] - 1
+			}
+			coins[j] = append(coins[j], r)
+		}
+	}
+	if len(portfolio) < 2 {
+		return risk
+	}
+
+	risk.Days = len(portfolio)
+	risk.Volatility = annualised(portfolio)
+	risk.Beta = beta(portfolio, market)
+	risk.VaR = historicalVaR(portfolio, riskConfidence)
+
+	last := days[len(days)-1]
+	total := value(last)
+	for j, h := range held {
+		weight := 0.0
+		if total > 0 {
+			weight = h.Quantity * prices[h.ID.CoinGeckoID][last] / total * 100
+		}
+		risk.Coins = append(risk.Coins, CoinRisk{
+			ID:         h.ID,
+			Weight:     weight,
+			Volatility: annualised(coins[j]),
+			Beta:       beta(coins[j], market),
+		})
+	}
+	sort.Slice(risk.Coins, func(i, j int) bool { return risk.Coins[i].Weight > risk.Coins[j].Weight })
+
+	return risk
+}
+
+// GetPortfolioRisk serves risk metrics of holdings received on the holdings
+// channel, computed again when they change or every riskRefresh
+func GetPortfolioRisk(ctx context.Context, holdingsChannel chan []PortfolioHolding, dataChannel chan AssetData, sendData *bool) error {
+	holdings := []PortfolioHolding{}
+	var computed []PortfolioHolding
+	var computedAt time.Time
+
+	// Ticks may outlast a computation, which runs one at a time
+	var m sync.Mutex
+	computing := false
+
+	return utils.LoopTick(ctx, time.Duration(1)*time.Second, func(errChan chan error) {
+		var finalErr error = nil
+
+		defer func() {
+			if finalErr != nil {
+				errChan <- finalErr
+			}
+		}()
+
+		m.Lock()
+		if computing {
+			m.Unlock()
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			m.Unlock()
+			finalErr = ctx.Err()
+			return
+		case newHoldings := <-holdingsChannel:
+			// Update holdings
+			holdings = newHoldings
+		default:
+			break
+		}
+
+		// Compute once holdings change, or the last risk is out of date
+		due := !sameHoldings(holdings, computed) || time.Since(computedAt) >= utils.PollInterval(riskRefresh)
+		if len(holdings) == 0 || !due || !*sendData {
+			m.Unlock()
+			return
+		}
+		computing = true
+		current := holdings
+		computed = current
+		computedAt = time.Now()
+		m.Unlock()
+
+		defer func() {
+			m.Lock()
+			computing = false
+			m.Unlock()
+		}()
+
+		ids := []CoinID{{CoinGeckoID: "bitcoin", Symbol: "BTC"}}
+		for _, h := range current {
+			if h.ID.CoinGeckoID != "bitcoin" {
+				ids = append(ids, CoinID{CoinGeckoID: h.ID.CoinGeckoID, Symbol: h.ID.Symbol})
+			}
+		}
+
+		// Cached histories are reused when only holdings change
+		histories, err := getHistories(ctx, geckoSource{}, ids, riskDays)
+		if err != nil {
+			finalErr = err
+			return
+		}
+
+		// Holdings whose history can't be fetched are left out
+		prices := make(map[string]map[int64]float64)
+		for id, result := range histories {
+			if result.Err == nil {
+				prices[id] = dailyPrices(result.History)
+			}
+		}
+		btc := histories["bitcoin"]
+		trackStatus("risk", riskRefresh, btc.Err)
+		if btc.Err != nil {
+			m.Lock()
+			computedAt = time.Now().Add(riskRetry - utils.PollInterval(riskRefresh))
+			m.Unlock()
+			// Risk is optional, so only invalid requests are reported
+			if !isUnavailable(btc.Err) {
+				finalErr = btc.Err
+			}
+			return
+		}
+
+		risk := computeRisk(current, prices)
+
+		for !*sendData {
+			select {
+			case <-ctx.Done():
+				finalErr = ctx.Err()
+				return
+			case <-time.After(time.Second):
+			}
+		}
+
+		// Send data
+		select {
+		case <-ctx.Done():
+			finalErr = ctx.Err()
+		case dataChannel <- AssetData{IsRiskData: true, Risk: risk}:
+		}
+	})
+}
@@ -0,0 +1,296 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
+	geckoTypes "github.com/superoo7/go-gecko/v3/types"
+)
+
+const (
+	// ScreenerUniverse is the number of top coins by market cap screened
+	ScreenerUniverse = 250
+
+	screenerRefresh = time.Duration(2) * time.Minute
+	screenerRetry   = time.Duration(30) * time.Second
+)
+
+// Fields of coins screener criteria are set on
+const (
+	FieldMarketCap   = "mcap"    // Market cap in USD
+	FieldChange      = "change"  // Change % over 24 hours
+	FieldVolumeRatio = "volmcap" // Volume over 24 hours divided by market cap
+	FieldATH         = "ath"     // Change % from the all time high, 0 at most
+)
+
+// ScreenerFields are the fields criteria can be set on
+var ScreenerFields = []string{FieldMarketCap, FieldChange, FieldVolumeRatio, FieldATH}
+
+// screenerOps are operators of criteria, longer ones first so that >= isn't
+// read as >
+var screenerOps = []string{">=", "<=", ">", "<"}
+
+// Criterion bounds a field of coins, Eg: mcap>1b
+type Criterion struct {
+	Field string
+	Op    string
+	Value float64
+}
+
+// ScreenerQuery holds criteria all coins listed by a screener meet
+type ScreenerQuery struct {
+	Name     string
+	Criteria []Criterion
+}
+
+// SavedQueries are screener queries named in the config file, in the order
+// they are listed in
+var SavedQueries = []ScreenerQuery{}
+
+// GetSavedQueries returns SavedQueries, read under the settings lock
+func GetSavedQueries() []ScreenerQuery {
+	utils.RLockSettings()
+	defer utils.RUnlockSettings()
+	return SavedQueries
+}
+
+// ScreenerData is used to send coins meeting a query to the screener page
+type ScreenerData struct {
+	Query    ScreenerQuery
+	Coins    []geckoTypes.CoinsMarketItem
+	Screened int // Number of coins screened
+	Updated  time.Time
+}
+
+// ParseScreenerQuery parses criteria separated by spaces, all of which
+// coins must meet, Eg: mcap>100m change<-5 ath<=-70. Values may end with
+// k, m, b or t. An empty query lists every coin screened.
+func ParseScreenerQuery(s string) (ScreenerQuery, error) {
+	query := ScreenerQuery{Criteria: []Criterion{}}
+
+	for _, word := range strings.Fields(s) {
+		criterion := Criterion{}
+		for _, op := range screenerOps {
+			if i := strings.Index(word, op); i > 0 {
+				criterion.Field = strings.ToLower(word[:i])
+				criterion.Op = op
+				word = word[i+len(op):]
+				break
+			}
+		}
+		if criterion.Op == "" {
+			return query, fmt.Errorf("invalid criterion %q, expected a field, operator and value, Eg: mcap>1b", word)
+		}
+
+		known := false
+		for _, field := range ScreenerFields {
+			known = known || criterion.Field == field
+		}
+		if !known {
+			return query, fmt.Errorf("unknown field %q, expected one of: %s", criterion.Field, strings.Join(ScreenerFields, ", "))
+		}
+
+		val, ok := utils.ParseValue(strings.ToUpper(word))
+		if !ok {
+			return query, fmt.Errorf("invalid value %q of %s", word, criterion.Field)
+		}
+		criterion.Value = val
+
+		query.Criteria = append(query.Criteria, criterion)
+	}
+
+	return query, nil
+}
+
+// String returns the criteria of the query, as they are parsed
+func (q ScreenerQuery) String() string {
+	criteria := []string{}
+	for _, c := range q.Criteria {
+		criteria = append(criteria, fmt.Sprintf("%s%s%g", c.Field, c.Op, c.Value))
+	}
+	return strings.Join(criteria, " ")
+}
+
+// FindQuery returns the saved query named s, ignoring case, or else parses
+// s as criteria
+func FindQuery(s string) (ScreenerQuery, error) {
+	for _, query := range GetSavedQueries() {
+		if strings.EqualFold(query.Name, s) {
+			return query, nil
+		}
+	}
+	return ParseScreenerQuery(s)
+}
+
+// FieldValue returns the value of a field of a coin, and false if it isn't
+// served for the coin
+func FieldValue(coin geckoTypes.CoinsMarketItem, field string) (float64, bool) {
+	switch field {
+	case FieldMarketCap:
+		return coin.MarketCap, coin.MarketCap > 0
+	case FieldChange:
+		return coin.PriceChangePercentage24h, true
+	case FieldVolumeRatio:
+		if coin.MarketCap <= 0 {
+			return 0, false
+		}
+		return coin.TotalVolume / coin.MarketCap, true
+	case FieldATH:
+		return coin.ATHChangePercentage, coin.ATH > 0
+	}
+	return 0, false
+}
+
+// Match returns true if coin meets all criteria of the query. Coins a field
+// isn't served for don't meet criteria on it.
+func (q ScreenerQuery) Match(coin geckoTypes.CoinsMarketItem) bool {
+	for _, c := range q.Criteria {
+		val, ok := FieldValue(coin, c.Field)
+		if !ok {
+			return false
+		}
+
+		met := false
+		switch c.Op {
+		case ">":
+			met = val > c.Value
+		case ">=":
+			met = val >= c.Value
+		case "<":
+			met = val < c.Value
+		case "<=":
+			met = val <= c.Value
+		}
+		if !met {
+			return false
+		}
+	}
+	return true
+}
+
+// Screen returns coins meeting the query, in the order given
+func Screen(query ScreenerQuery, coins []geckoTypes.CoinsMarketItem) []geckoTypes.CoinsMarketItem {
+	matched := []geckoTypes.CoinsMarketItem{}
+	for _, coin := range coins {
+		if query.Match(coin) {
+			matched = append(matched, coin)
+		}
+	}
+	return matched
+}
+
+// GetScreenerCoins returns market data of the coins screened, from
+// CoinGecko as other sources don't serve all time highs
+func GetScreenerCoins() ([]geckoTypes.CoinsMarketItem, error) {
+	return geckoSource{}.GetTopCoins(ScreenerUniverse)
+}
+
+// GetScreener serves coins meeting the query last sent on queryChannel,
+// screened again as soon as a new query is sent and whenever market data is
+// refreshed
+func GetScreener(ctx context.Context, queryChannel chan ScreenerQuery, dataChannel chan ScreenerData) error {
+	var query *ScreenerQuery
+	coins := []geckoTypes.CoinsMarketItem{}
+	var fetchedAt, updated time.Time
+
+	// Ticks may outlast a fetch, which runs one at a time
+	var m sync.Mutex
+	fetching := false
+
+	return utils.LoopTick(ctx, time.Duration(1)*time.Second, func(errChan chan error) {
+		var finalErr error = nil
+
+		defer func() {
+			if finalErr != nil {
+				errChan <- finalErr
+			}
+		}()
+
+		m.Lock()
+		if fetching {
+			m.Unlock()
+			return
+		}
+
+		changed := false
+		select {
+		case <-ctx.Done():
+			m.Unlock()
+			finalErr = ctx.Err()
+			return
+		case newQuery := <-queryChannel:
+			query = &newQuery
+			changed = true
+		default:
+			break
+		}
+
+		if query == nil {
+			m.Unlock()
+			return
+		}
+
+		if time.Since(fetchedAt) >= utils.PollInterval(screenerRefresh) {
+			fetching = true
+			m.Unlock()
+
+			fetched, err := GetScreenerCoins()
+			trackStatus("screener", screenerRefresh, err)
+
+			m.Lock()
+			fetching = false
+			if err != nil {
+				// Retry sooner than the next refresh
+				fetchedAt = time.Now().Add(screenerRetry - utils.PollInterval(screenerRefresh))
+				if !isUnavailable(err) {
+					m.Unlock()
+					finalErr = err
+					return
+				}
+			} else {
+				coins = fetched
+				fetchedAt = time.Now()
+				updated = fetchedAt
+				changed = true
+			}
+		}
+
+		if !changed {
+			m.Unlock()
+			return
+		}
+		data := ScreenerData{
+			Query:    *query,
+			Coins:    Screen(*query, coins),
+			Screened: len(coins),
+			Updated:  updated,
+		}
+		m.Unlock()
+
+		select {
+		case <-ctx.Done():
+			finalErr = ctx.Err()
+		case dataChannel <- data:
+		}
+	})
+}
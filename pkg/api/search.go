@@ -0,0 +1,109 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
+)
+
+// CoinListing is a coin listed by CoinGecko
+type CoinListing struct {
+	ID     string
+	Symbol string
+	Name   string
+}
+
+// GetCoinList returns all coins listed by CoinGecko. The list is large, but
+// cached for a day along with other responses.
+func GetCoinList() ([]CoinListing, error) {
+	geckoClient := NewGeckoClient()
+
+	list, err := geckoClient.CoinsList()
+	if err != nil {
+		return nil, err
+	}
+
+	coins := []CoinListing{}
+	for _, coin := range *list {
+		coins = append(coins, CoinListing{
+			ID:     coin.ID,
+			Symbol: strings.ToUpper(coin.Symbol),
+			Name:   coin.Name,
+		})
+	}
+
+	return coins, nil
+}
+
+// SearchCoins returns up to n coins of list whose symbol, name or aliases
+// fuzzy match query, best matches first. Coins in preferred (Eg: the top
+// coins) are ranked above others sharing their symbol or name, and a coin
+// whose alias is the query is ranked first.
+func SearchCoins(list []CoinListing, query string, preferred map[string]bool, n int) []CoinListing {
+	type match struct {
+		coin  CoinListing
+		score int
+	}
+
+	// Aliases of each coin, by CoinGecko ID
+	aliases := map[string][]string{}
+	for alias, id := range aliasMap() {
+		aliases[id] = append(aliases[id], alias)
+	}
+
+	matches := []match{}
+	for _, coin := range list {
+		score, ok := utils.FuzzyScore(query, coin.Name)
+		if symbolScore, symbolOk := utils.FuzzyScore(query, coin.Symbol); symbolOk && (!ok || symbolScore > score) {
+			score, ok = symbolScore, true
+		}
+		for _, alias := range aliases[coin.ID] {
+			aliasScore, aliasOk := utils.FuzzyScore(query, alias)
+			if aliasOk && strings.EqualFold(query, alias) {
+				aliasScore += 100
+			}
+			if aliasOk && (!ok || aliasScore > score) {
+				score, ok = aliasScore, true
+			}
+		}
+		if !ok {
+			continue
+		}
+
+		if preferred[coin.ID] {
+			score += 50
+		}
+		matches = append(matches, match{coin, score})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].coin.ID < matches[j].coin.ID
+	})
+
+	results := []CoinListing{}
+	for i := 0; i < len(matches) && i < n; i++ {
+		results = append(results, matches[i].coin)
+	}
+
+	return results
+}
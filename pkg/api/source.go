@@ -0,0 +1,146 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	geckoTypes "github.com/superoo7/go-gecko/v3/types"
+)
+
+// Source is a backend serving market data. Data is returned in the
+// CoinGecko types the display code is built around, so that backends are
+// interchangeable. Backends pick the ID they need from CoinID.
+type Source interface {
+	// Name returns the name the source is selected by
+	Name() string
+
+	// GetTopCoins returns market data of the top n coins by market cap
+	GetTopCoins(n int) (geckoTypes.CoinsMarket, error)
+
+	// GetAsset returns market data of a coin along with its 7 day sparkline
+	GetAsset(id CoinID) (geckoTypes.CoinsMarketItem, error)
+
+	// GetHistory returns the USD price history of a coin over the given
+	// number of days, as (unix milliseconds, price) pairs in ascending order
+	GetHistory(id CoinID, days int) ([]geckoTypes.ChartItem, error)
+
+	// GetHistoryRange returns the USD price history of a coin between start
+	// and end, as GetHistory does
+	GetHistoryRange(id CoinID, start, end time.Time) ([]geckoTypes.ChartItem, error)
+
+	// GetLivePrice streams the USD price of a coin on dataChannel till ctx
+	// is cancelled or the stream fails
+	GetLivePrice(ctx context.Context, id CoinID, dataChannel chan string) error
+}
+
+// sources holds the available sources by name. Sources fall back to another
+// provider when they are unavailable or do not list a coin.
+var sources = map[string]Source{
+	"default":   defaultFailover(),
+	"coingecko": geckoSource{},
+	"coincap":   newFailoverSource(coincapSource{}, geckoSource{}),
+	"binance":   newFailoverSource(binanceSource{}, defaultSource{}),
+}
+
+// defaultFailover returns the default source, falling back to CoinCap for
+// data served by CoinGecko, and to polling CoinGecko for live prices
+// streamed from CoinCap
+func defaultFailover() failoverSource {
+	f := newFailoverSource(defaultSource{}, coincapSource{})
+	f.live = geckoSource{}
+	return f
+}
+
+// source is the selected Source, guarded by sourceMutex as it can be changed
+// on config reload
+var (
+	source      Source = sources["default"]
+	sourceMutex sync.RWMutex
+)
+
+// defaultSource serves data from CoinGecko and streams live prices from
+// CoinCap
+type defaultSource struct {
+	geckoSource
+}
+
+// Name returns the name of the source
+func (defaultSource) Name() string {
+	return "default"
+}
+
+// GetLivePrice streams prices from CoinCap
+func (defaultSource) GetLivePrice(ctx context.Context, id CoinID, dataChannel chan string) error {
+	return coincapSource{}.GetLivePrice(ctx, id, dataChannel)
+}
+
+// SourceNames returns names of the available sources
+func SourceNames() []string {
+	names := []string{}
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetSource selects the source data is served from
+func SetSource(name string) error {
+	s, ok := sources[name]
+	if !ok {
+		return fmt.Errorf("unknown source %q, available sources are: %s", name, strings.Join(SourceNames(), ", "))
+	}
+	sourceMutex.Lock()
+	source = s
+	sourceMutex.Unlock()
+	return nil
+}
+
+// GetSource returns the selected source
+func GetSource() Source {
+	sourceMutex.RLock()
+	defer sourceMutex.RUnlock()
+	return source
+}
+
+// CoinSource returns the source named name, used to serve a single coin from
+// a source other than the selected one. The selected source is returned if
+// name is empty or unknown.
+func CoinSource(name string) Source {
+	if s, ok := sources[name]; ok {
+		return s
+	}
+	return GetSource()
+}
+
+// NextSource returns the name of the source following the given one, cycling
+// through the available sources in order
+func NextSource(name string) string {
+	names := SourceNames()
+	for i, n := range names {
+		if n == name {
+			return names[(i+1)%len(names)]
+		}
+	}
+	return names[0]
+}
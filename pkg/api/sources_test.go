@@ -0,0 +1,49 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+	"github.com/Gituser143/cryptgo/pkg/api/sourcetest"
+)
+
+func TestSources(t *testing.T) {
+	bitcoin := api.CoinID{CoinGeckoID: "bitcoin", CoinCapID: "bitcoin", Symbol: "BTC"}
+
+	tests := []struct {
+		name string
+		src  api.Source
+	}{
+		{"coingecko", api.GeckoSource},
+		{"coincap", api.CoincapSource},
+		{"binance", api.BinanceSource},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			sourcetest.TestSource(t, test.src, sourcetest.Options{
+				Coin:     bitcoin,
+				Fixtures: filepath.Join("testdata", test.name),
+				Live:     true,
+			})
+		})
+	}
+}
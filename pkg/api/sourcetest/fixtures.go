@@ -0,0 +1,220 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sourcetest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"unicode"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+)
+
+// RecordEnv is the environment variable which, set to 1, records fixtures
+// from the live API rather than replaying them
+const RecordEnv = "CRYPTGO_RECORD_FIXTURES"
+
+// fixture holds a recorded response
+type fixture struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Status int         `json:"status"`
+	Header http.Header `json:"header"`
+	Body   string      `json:"body"`
+}
+
+// keptHeaders are response headers saved with fixtures, the others are left
+// out as they may identify the session
+var keptHeaders = []string{"Content-Type", "Retry-After", "X-Ratelimit-Remaining", "X-Ratelimit-Reset", "X-Mbx-Used-Weight-1m"}
+
+// Transport records responses to a directory of fixtures, or replays them
+// without network access. It also keeps the hosts requested, so they can be
+// checked against those of registered providers.
+type Transport struct {
+	Dir    string
+	Record bool
+	next   http.RoundTripper
+
+	sync.Mutex
+	hosts map[string]bool
+}
+
+// fixtureKey returns the name of the fixture of a request. Query values
+// which are timestamps change on every run, so they are left out.
+func fixtureKey(method string, u *url.URL) string {
+	query := u.Query()
+	for param, values := range query {
+		for i, val := range values {
+			if len(val) >= 10 && strings.IndexFunc(val, func(r rune) bool { return !unicode.IsDigit(r) }) == -1 {
+				values[i] = "T"
+			}
+		}
+		query[param] = values
+	}
+
+	redacted := *u
+	redacted.RawQuery = query.Encode()
+	sum := sha256.Sum256([]byte(method + " " + api.RedactURL(&redacted)))
+
+	return fmt.Sprintf("%x.json", sum[:8])
+}
+
+// RoundTrip serves a request from its fixture, or sends it and saves its
+// response as one when recording
+func (f *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.Lock()
+	f.hosts[req.URL.Hostname()] = true
+	f.Unlock()
+
+	path := filepath.Join(f.Dir, fixtureKey(req.Method, req.URL))
+
+	if !f.Record {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("no fixture of %s %s, record fixtures with %s=1: %v", req.Method, api.RedactURL(req.URL), RecordEnv, err)
+		}
+		fix := fixture{}
+		if err := json.Unmarshal(data, &fix); err != nil {
+			return nil, fmt.Errorf("invalid fixture %s: %v", path, err)
+		}
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", fix.Status, http.StatusText(fix.Status)),
+			StatusCode:    fix.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        fix.Header,
+			Body:          ioutil.NopCloser(strings.NewReader(fix.Body)),
+			ContentLength: int64(len(fix.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	res, err := f.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	header := http.Header{}
+	for _, name := range keptHeaders {
+		if val := res.Header.Get(name); val != "" {
+			header.Set(name, val)
+		}
+	}
+
+	data, err := json.MarshalIndent(fixture{
+		Method: req.Method,
+		URL:    api.RedactURL(req.URL),
+		Status: res.StatusCode,
+		Header: header,
+		Body:   string(body),
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(f.Dir, 0755); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Hosts returns the hosts requested through the transport, sorted
+func (f *Transport) Hosts() []string {
+	f.Lock()
+	defer f.Unlock()
+
+	hosts := []string{}
+	for host := range f.hosts {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// UseFixtures sends requests of api.Client through a Transport over dir till
+// the test ends. Fixtures are recorded
+// from the live API if RecordEnv is set to 1, else replayed.
+func UseFixtures(t *testing.T, dir string) *Transport {
+	t.Helper()
+
+	f := &Transport{
+		Dir:    dir,
+		Record: os.Getenv(RecordEnv) == "1",
+		hosts:  make(map[string]bool),
+	}
+	f.next = useTransport(t, f)
+
+	return f
+}
+
+// StatusTransport answers every request with a status, Eg: 429 to check a
+// source reports being rate limited
+type StatusTransport int
+
+// RoundTrip answers the request with the status and an empty JSON body
+func (s StatusTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	code := int(s)
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	if code == http.StatusTooManyRequests {
+		header.Set("Retry-After", "60")
+	}
+
+	return &http.Response{
+		Status:     fmt.Sprintf("%d %s", code, http.StatusText(code)),
+		StatusCode: code,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     header,
+		Body:       ioutil.NopCloser(strings.NewReader("{}")),
+		Request:    req,
+	}, nil
+}
+
+// useTransport sets the transport of api.Client till the test ends,
+// returning the one replaced
+func useTransport(t *testing.T, rt http.RoundTripper) http.RoundTripper {
+	previous := api.SetTransport(rt)
+	t.Cleanup(func() {
+		api.SetTransport(previous)
+	})
+	return previous
+}
@@ -0,0 +1,283 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sourcetest checks that implementations of api.Source behave like
+// the built in sources, so they can be registered and failed over in the
+// same way. Call TestSource from a test of the package implementing the
+// source, replaying responses recorded with UseFixtures.
+package sourcetest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+	geckoTypes "github.com/superoo7/go-gecko/v3/types"
+)
+
+// Options configure a conformance run
+type Options struct {
+	// Coin is a coin listed by the source, Eg: bitcoin
+	Coin api.CoinID
+
+	// Fixtures is the directory responses are recorded to and replayed
+	// from, see UseFixtures. Requests go to the live API if empty.
+	Fixtures string
+
+	// Live checks GetLivePrice, which streams can't be replayed for. It is
+	// only checked against the live API or while recording.
+	Live bool
+}
+
+// TestSource checks src behaves as the Source contract describes:
+//
+//   - Data is normalised to CoinGecko types, with lower case symbols and
+//     prices in USD
+//   - Top coins are ordered by rank and history is in ascending order of
+//     unix milliseconds
+//   - Coins without an ID the source needs fail with an error wrapping
+//     api.ErrNotListed
+//   - Rate limited (429) and failing (5xx) responses fail with errors
+//     api.IsUnavailable recognises, so requests fall back to another source
+//   - Requests are only sent to hosts of registered providers, so they are
+//     rate limited and their quota tracked
+func TestSource(t *testing.T, src api.Source, opts Options) {
+	t.Helper()
+
+	current := opts.Fixtures == ""
+	var fixtures *Transport
+	if opts.Fixtures != "" {
+		fixtures = UseFixtures(t, opts.Fixtures)
+		current = fixtures.Record
+	}
+
+	t.Run("Name", func(t *testing.T) {
+		name := src.Name()
+		if name == "" || strings.ToLower(name) != name {
+			t.Errorf("Name() = %q, expected a lower case name", name)
+		}
+	})
+
+	t.Run("TopCoins", func(t *testing.T) {
+		coins, err := src.GetTopCoins(10)
+		if err != nil {
+			t.Fatalf("GetTopCoins(10) failed: %v", err)
+		}
+		if len(coins) == 0 || len(coins) > 10 {
+			t.Fatalf("GetTopCoins(10) returned %d coins, expected 1 to 10", len(coins))
+		}
+
+		for i, coin := range coins {
+			checkMarketItem(t, "GetTopCoins", coin)
+			if i > 0 && coin.MarketCapRank != 0 && coins[i-1].MarketCapRank > coin.MarketCapRank {
+				t.Errorf("GetTopCoins: %s ranked %d after %s ranked %d, expected ascending ranks",
+					coin.ID, coin.MarketCapRank, coins[i-1].ID, coins[i-1].MarketCapRank)
+			}
+		}
+	})
+
+	t.Run("Asset", func(t *testing.T) {
+		asset, err := src.GetAsset(opts.Coin)
+		if err != nil {
+			t.Fatalf("GetAsset(%s) failed: %v", opts.Coin.Symbol, err)
+		}
+		checkMarketItem(t, "GetAsset", asset)
+		if asset.Symbol != strings.ToLower(opts.Coin.Symbol) {
+			t.Errorf("GetAsset(%s) returned symbol %q", opts.Coin.Symbol, asset.Symbol)
+		}
+	})
+
+	t.Run("History", func(t *testing.T) {
+		history, err := src.GetHistory(opts.Coin, 7)
+		if err != nil {
+			t.Fatalf("GetHistory(%s, 7) failed: %v", opts.Coin.Symbol, err)
+		}
+		checkHistory(t, "GetHistory", history)
+
+		// Replayed history is as old as its fixtures, so only its span is
+		// checked
+		if len(history) > 1 {
+			span := time.Duration(history[len(history)-1][0]-history[0][0]) * time.Millisecond
+			if span > time.Duration(8*24)*time.Hour {
+				t.Errorf("GetHistory(%s, 7) spans %s, expected 7 days", opts.Coin.Symbol, span)
+			}
+		}
+	})
+
+	t.Run("HistoryRange", func(t *testing.T) {
+		end := time.Now().AddDate(0, 0, -1).Truncate(time.Hour)
+		start := end.AddDate(0, 0, -2)
+
+		history, err := src.GetHistoryRange(opts.Coin, start, end)
+		if err != nil {
+			t.Fatalf("GetHistoryRange(%s) failed: %v", opts.Coin.Symbol, err)
+		}
+		checkHistory(t, "GetHistoryRange", history)
+
+		if current && len(history) > 0 {
+			// Points may be aligned to the interval between them
+			slack := time.Duration(1) * time.Hour
+			first := time.Unix(0, int64(history[0][0])*int64(time.Millisecond))
+			last := time.Unix(0, int64(history[len(history)-1][0])*int64(time.Millisecond))
+			if first.Before(start.Add(-slack)) || last.After(end.Add(slack)) {
+				t.Errorf("GetHistoryRange(%s) returned points from %s to %s, outside of %s to %s",
+					opts.Coin.Symbol, first, last, start, end)
+			}
+		}
+	})
+
+	t.Run("NotListed", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(10)*time.Second)
+		defer cancel()
+
+		unlisted := api.CoinID{}
+		now := time.Now()
+		checks := map[string]error{}
+		_, checks["GetAsset"] = src.GetAsset(unlisted)
+		_, checks["GetHistory"] = src.GetHistory(unlisted, 7)
+		_, checks["GetHistoryRange"] = src.GetHistoryRange(unlisted, now.AddDate(0, 0, -1), now)
+		checks["GetLivePrice"] = src.GetLivePrice(ctx, unlisted, make(chan string, 1))
+
+		for method, err := range checks {
+			if !errors.Is(err, api.ErrNotListed) {
+				t.Errorf("%s of a coin without IDs returned %v, expected an error wrapping api.ErrNotListed", method, err)
+			}
+		}
+	})
+
+	for _, code := range []int{http.StatusTooManyRequests, http.StatusInternalServerError} {
+		code := code
+		t.Run("Status"+strconv.Itoa(code), func(t *testing.T) {
+			useTransport(t, StatusTransport(code))
+
+			_, err := src.GetTopCoins(10)
+			checkUnavailable(t, "GetTopCoins", code, err)
+			_, err = src.GetHistory(opts.Coin, 7)
+			checkUnavailable(t, "GetHistory", code, err)
+		})
+	}
+
+	if opts.Live && current {
+		t.Run("LivePrice", func(t *testing.T) {
+			checkLivePrice(t, src, opts.Coin)
+		})
+	}
+
+	if fixtures != nil {
+		t.Run("Hosts", func(t *testing.T) {
+			for _, host := range fixtures.Hosts() {
+				if !api.RateLimited(host) {
+					t.Errorf("requests were sent to %s, which no registered provider serves", host)
+				}
+			}
+		})
+	}
+}
+
+// checkMarketItem checks market data is normalised
+func checkMarketItem(t *testing.T, method string, item geckoTypes.CoinsMarketItem) {
+	t.Helper()
+
+	if item.ID == "" || item.Name == "" {
+		t.Errorf("%s: coin with symbol %q has no ID or name", method, item.Symbol)
+	}
+	if item.Symbol == "" || strings.ToLower(item.Symbol) != item.Symbol {
+		t.Errorf("%s: %s has symbol %q, expected a lower case symbol", method, item.ID, item.Symbol)
+	}
+	if item.CurrentPrice <= 0 {
+		t.Errorf("%s: %s has price %f, expected a positive USD price", method, item.ID, item.CurrentPrice)
+	}
+	if item.MarketCap < 0 || item.TotalVolume < 0 {
+		t.Errorf("%s: %s has negative market cap or volume", method, item.ID)
+	}
+}
+
+// checkHistory checks history is in ascending order of unix milliseconds,
+// with positive prices
+func checkHistory(t *testing.T, method string, history []geckoTypes.ChartItem) {
+	t.Helper()
+
+	if len(history) == 0 {
+		t.Fatalf("%s returned no history", method)
+	}
+	for i, point := range history {
+		if point[0] < 1e12 {
+			t.Fatalf("%s: point %d has time %f, expected unix milliseconds", method, i, point[0])
+		}
+		if point[1] <= 0 {
+			t.Errorf("%s: point %d has price %f, expected a positive USD price", method, i, point[1])
+		}
+		if i > 0 && point[0] < history[i-1][0] {
+			t.Fatalf("%s: point %d is older than the one before, expected ascending order", method, i)
+		}
+	}
+}
+
+// checkUnavailable checks a failed response is reported as the source being
+// unavailable
+func checkUnavailable(t *testing.T, method string, code int, err error) {
+	t.Helper()
+
+	if err == nil {
+		t.Errorf("%s succeeded on %d responses, expected an error", method, code)
+	} else if !api.IsUnavailable(err) {
+		t.Errorf("%s on %d responses returned %v, expected an error api.IsUnavailable recognises, Eg: *api.StatusError", method, code, err)
+	}
+}
+
+// checkLivePrice checks prices are streamed as positive numbers, and the
+// stream ends once cancelled
+func checkLivePrice(t *testing.T, src api.Source, coin api.CoinID) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	prices := make(chan string)
+	done := make(chan error, 1)
+	go func() {
+		done <- src.GetLivePrice(ctx, coin, prices)
+	}()
+
+	select {
+	case price := <-prices:
+		if val, err := strconv.ParseFloat(price, 64); err != nil || val <= 0 {
+			t.Errorf("GetLivePrice sent %q, expected a positive USD price", price)
+		}
+	case err := <-done:
+		t.Fatalf("GetLivePrice ended before sending a price: %v", err)
+	case <-time.After(time.Duration(30) * time.Second):
+		t.Fatalf("GetLivePrice sent no price within 30 seconds")
+	}
+
+	cancel()
+
+	// Drain prices sent while cancelling
+	for {
+		select {
+		case <-prices:
+		case <-done:
+			return
+		case <-time.After(time.Duration(10) * time.Second):
+			t.Fatalf("GetLivePrice did not return within 10 seconds of being cancelled")
+		}
+	}
+}
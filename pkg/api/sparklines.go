@@ -0,0 +1,166 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
+)
+
+const (
+	// sparklineDays is the number of days of history in sparklines
+	sparklineDays = 7
+	// sparklineRefresh is how often sparklines are fetched again
+	sparklineRefresh = time.Duration(15) * time.Minute
+	// sparklineFlush is how often sparklines fetched so far are sent
+	sparklineFlush = time.Duration(500) * time.Millisecond
+)
+
+// GetSparklines serves 7 day sparklines of the coins received through the
+// id channel, for the coin table of the main page. Histories are fetched by
+// a pool of HistoryWorkers, and sent in batches as they come in so the table
+// fills in progressively. Coins received later are fetched right away, and
+// all of them again every sparklineRefresh. Sparklines are optional, so
+// coins whose history can't be fetched are left without one.
+func GetSparklines(ctx context.Context, idChannel chan []CoinID, dataChannel chan AssetData, sendData *bool) error {
+	ids := []CoinID{}
+	fetched := make(map[string]time.Time)
+
+	// Ticks may outlast a fetch, which runs one at a time
+	var m sync.Mutex
+	fetching := false
+
+	return utils.LoopTick(ctx, time.Duration(1)*time.Second, func(errChan chan error) {
+		var finalErr error = nil
+
+		defer func() {
+			if finalErr != nil {
+				errChan <- finalErr
+			}
+		}()
+
+		m.Lock()
+		if fetching {
+			m.Unlock()
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			m.Unlock()
+			finalErr = ctx.Err()
+			return
+		case newIDs := <-idChannel:
+			// Update coins
+			ids = newIDs
+		default:
+			break
+		}
+
+		// Pick coins without a recent sparkline
+		due := []CoinID{}
+		for _, id := range ids {
+			if time.Since(fetched[id.CoinGeckoID]) >= utils.PollInterval(sparklineRefresh) {
+				due = append(due, id)
+			}
+		}
+		if len(due) == 0 || !*sendData {
+			m.Unlock()
+			return
+		}
+		fetching = true
+		m.Unlock()
+
+		defer func() {
+			m.Lock()
+			fetching = false
+			m.Unlock()
+		}()
+
+		results := make(chan HistoryResult)
+		done := make(chan error, 1)
+		src := GetSource()
+		go func() {
+			done <- GetHistories(ctx, src, due, sparklineDays, results)
+			close(results)
+		}()
+
+		// send sends sparklines fetched since the last batch, waiting while
+		// data sending is paused
+		batch := make(map[string][]float64)
+		send := func() bool {
+			if len(batch) == 0 {
+				return true
+			}
+			for !*sendData {
+				select {
+				case <-ctx.Done():
+					return false
+				case <-time.After(time.Second):
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return false
+			case dataChannel <- AssetData{IsSparklineData: true, Sparklines: batch}:
+			}
+			batch = make(map[string][]float64)
+			return true
+		}
+
+		flush := time.NewTicker(sparklineFlush)
+		defer flush.Stop()
+
+		for {
+			select {
+			case result, ok := <-results:
+				if !ok {
+					if send() {
+						finalErr = <-done
+					} else {
+						finalErr = ctx.Err()
+					}
+					return
+				}
+
+				trackStatus("sparklines", sparklineRefresh, result.Err)
+				m.Lock()
+				fetched[result.ID.CoinGeckoID] = time.Now()
+				m.Unlock()
+				if result.Err != nil {
+					continue
+				}
+
+				prices := make([]float64, len(result.History))
+				for i, v := range result.History {
+					prices[i] = float64(v[1])
+				}
+				batch[result.ID.CoinGeckoID] = prices
+
+			case <-flush.C:
+				if !send() {
+					finalErr = ctx.Err()
+					return
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,166 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
+	geckoTypes "github.com/superoo7/go-gecko/v3/types"
+)
+
+const (
+	staleInterval = time.Duration(10) * time.Minute
+	// Coins not updated for this long are treated as stale
+	staleAge = time.Duration(24) * time.Hour
+	// Max suggestions listed for a stale coin
+	maxSuggestions = 3
+)
+
+// GetStaleCoins periodically checks if favourites and portfolio holdings
+// still return data from CoinGecko. Coins which are not found (Eg: delisted
+// or renamed) or have not been updated for a day are sent on dataChannel,
+// along with listed coins which may have replaced them.
+func GetStaleCoins(ctx context.Context, dataChannel chan AssetData, sendData *bool) error {
+
+	// Init Client
+	geckoClient := NewGeckoClient()
+
+	// Set Parameters
+	vsCurrency := "usd"
+	order := geckoTypes.OrderTypeObject.MarketCapDesc
+	page := 1
+	sparkline := false
+	priceChangePercentage := []string{}
+
+	return utils.LoopTick(ctx, staleInterval, func(errChan chan error) {
+		var finalErr error = nil
+		data := AssetData{IsStaleData: true}
+
+		defer func() {
+			if finalErr != nil {
+				errChan <- finalErr
+			}
+		}()
+
+		if !*sendData {
+			return
+		}
+
+		// Get tracked coins, metadata is read on every check to pick up
+		// changes made since the last one
+		tracked := map[string]bool{}
+		for id, ok := range utils.GetFavourites() {
+			if ok {
+				tracked[id] = true
+			}
+		}
+		for id := range utils.GetPortfolio() {
+			tracked[id] = true
+		}
+
+		IDs := []string{}
+		for id := range tracked {
+			IDs = append(IDs, id)
+		}
+		sort.Strings(IDs)
+
+		if len(IDs) > 0 {
+			// Fetch Data
+			coinDataPointer, err := geckoClient.CoinsMarket(vsCurrency, IDs, order, len(IDs), page, sparkline, priceChangePercentage)
+			if err != nil {
+				finalErr = err
+				return
+			}
+
+			found := map[string]bool{}
+			for _, val := range *coinDataPointer {
+				found[val.ID] = true
+
+				// Check time of last update
+				lastUpdated, err := time.Parse(time.RFC3339, val.LastUpdated)
+				if err == nil && time.Since(lastUpdated) > staleAge {
+					data.StaleCoins = append(data.StaleCoins, StaleCoin{
+						ID:     val.ID,
+						Reason: "not updated since " + lastUpdated.Format("02 Jan"),
+					})
+				}
+			}
+
+			for _, id := range IDs {
+				if !found[id] {
+					data.StaleCoins = append(data.StaleCoins, StaleCoin{
+						ID:     id,
+						Reason: "not found",
+					})
+				}
+			}
+		}
+
+		// Suggest replacements from the full coin list
+		if len(data.StaleCoins) > 0 {
+			coinList, err := geckoClient.CoinsList()
+			if err == nil {
+				for i, coin := range data.StaleCoins {
+					data.StaleCoins[i].Suggestions = suggestCoins(coin.ID, *coinList)
+				}
+			}
+		}
+
+		// Send Data
+		select {
+		case <-ctx.Done():
+			finalErr = ctx.Err()
+			return
+		case dataChannel <- data:
+		}
+	})
+}
+
+// suggestCoins returns IDs of listed coins which may have replaced the coin
+// specified by id. Renamed coins usually keep the old ID as a prefix (Eg:
+// terra-luna and terra-luna-2), else coins sharing the first word of the ID
+// are suggested.
+func suggestCoins(id string, coinList geckoTypes.CoinList) []string {
+	suggestions := []string{}
+	related := []string{}
+	firstWord := strings.SplitN(id, "-", 2)[0]
+
+	for _, coin := range coinList {
+		switch {
+		case coin.ID == id:
+			continue
+		case strings.HasPrefix(coin.ID, id):
+			suggestions = append(suggestions, coin.ID)
+		case coin.ID == firstWord || strings.HasPrefix(coin.ID, firstWord+"-"):
+			related = append(related, coin.ID)
+		}
+	}
+
+	sort.Strings(suggestions)
+	sort.Strings(related)
+	suggestions = append(suggestions, related...)
+
+	if len(suggestions) > maxSuggestions {
+		suggestions = suggestions[:maxSuggestions]
+	}
+
+	return suggestions
+}
@@ -0,0 +1,90 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StatsVersion is the schema version of quick stats. Fields may be added to
+// a version, but are never renamed, removed or changed in meaning or type
+// without bumping it.
+const StatsVersion = 1
+
+// QuickStats are stats of the coin open on the coin page, normalised for
+// bots and scripts tailing them. Prices are always in USD, regardless of the
+// selected currency.
+type QuickStats struct {
+	// Version is the schema version, see StatsVersion
+	Version int `json:"version"`
+	// Time is when the stats were written
+	Time time.Time `json:"time"`
+
+	// ID is the CoinGecko ID of the coin
+	ID     string `json:"id"`
+	Symbol string `json:"symbol"`
+	Name   string `json:"name"`
+	// Rank by market cap, 0 if the coin is unranked
+	Rank int `json:"rank"`
+
+	// PriceUSD is the live price if streamed, else the polled price
+	PriceUSD     float64 `json:"price_usd"`
+	Change24hPct float64 `json:"change_24h_pct"`
+	High24hUSD   float64 `json:"high_24h_usd"`
+	Low24hUSD    float64 `json:"low_24h_usd"`
+	MarketCapUSD float64 `json:"market_cap_usd"`
+	Volume24hUSD float64 `json:"volume_24h_usd"`
+	ATHUSD       float64 `json:"ath_usd"`
+
+	// Source is the name of the source prices are served from
+	Source string `json:"source"`
+}
+
+// NewQuickStats returns quick stats of a coin from its details, priced at
+// price if positive, Eg: the last live price
+func NewQuickStats(id CoinID, details CoinDetails, price float64, source string) QuickStats {
+	if price <= 0 {
+		price = details.CurrentPrice
+	}
+	rank, _ := strconv.Atoi(details.Rank)
+
+	return QuickStats{
+		Version:      StatsVersion,
+		Time:         time.Now().UTC(),
+		ID:           id.CoinGeckoID,
+		Symbol:       strings.ToUpper(details.Symbol),
+		Name:         details.Name,
+		Rank:         rank,
+		PriceUSD:     price,
+		Change24hPct: details.Change24h,
+		High24hUSD:   details.High24,
+		Low24hUSD:    details.Low24,
+		MarketCapUSD: details.MarketCap,
+		Volume24hUSD: details.TotalVolume,
+		ATHUSD:       details.ATH,
+		Source:       source,
+	}
+}
+
+// JSON returns the stats as a single line of JSON
+func (s QuickStats) JSON() (string, error) {
+	data, err := json.Marshal(s)
+	return string(data), err
+}
@@ -0,0 +1,102 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
+)
+
+// StaleRefreshes is the number of refreshes data may miss before it is
+// shown as stale
+const StaleRefreshes = 3
+
+// Status holds when a type of data was last updated, and the last error
+// fetching it, so pages can show data is stale rather than silently frozen
+type Status struct {
+	Type    string        // Type of data, Eg: history
+	Every   time.Duration // Interval the data is refreshed at, Eg: 10s
+	Updated time.Time     // Time of the last update, zero if never updated
+	Err     error         // Last error fetching the data, nil if none
+	Failed  time.Time     // Time of the last error
+}
+
+// Stale returns true if the data missed StaleRefreshes refreshes, which are
+// lengthened in low power mode
+func (s Status) Stale() bool {
+	if s.Updated.IsZero() {
+		return !s.Failed.IsZero()
+	}
+	return time.Since(s.Updated) > StaleRefreshes*utils.PollInterval(s.Every)
+}
+
+// Failing returns true if the data failed to be fetched since its last update
+func (s Status) Failing() bool {
+	return s.Err != nil && s.Failed.After(s.Updated)
+}
+
+// statusState holds statuses of types of data fetched
+var statusState = struct {
+	sync.Mutex
+	statuses map[string]Status
+}{statuses: make(map[string]Status)}
+
+// trackStatus records the outcome of fetching a type of data refreshed
+// every interval, err being nil if it was fetched
+func trackStatus(dataType string, every time.Duration, err error) {
+	statusState.Lock()
+	defer statusState.Unlock()
+
+	status := statusState.statuses[dataType]
+	status.Type = dataType
+	status.Every = every
+	if err != nil {
+		status.Err = err
+		status.Failed = time.Now()
+	} else {
+		status.Updated = time.Now()
+	}
+	statusState.statuses[dataType] = status
+}
+
+// GetStatus returns statuses of types of data in the order given, leaving
+// out types which were not fetched yet
+func GetStatus(types ...string) []Status {
+	statusState.Lock()
+	defer statusState.Unlock()
+
+	statuses := []Status{}
+	for _, dataType := range types {
+		if status, ok := statusState.statuses[dataType]; ok {
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses
+}
+
+// ResetStatus forgets statuses of types of data, Eg: those of the last coin
+// when another coin page is opened
+func ResetStatus(types ...string) {
+	statusState.Lock()
+	defer statusState.Unlock()
+
+	for _, dataType := range types {
+		delete(statusState.statuses, dataType)
+	}
+}
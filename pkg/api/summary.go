@@ -0,0 +1,27 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	geckoTypes "github.com/superoo7/go-gecko/v3/types"
+)
+
+// GetCoinSummary fetches market data of a coin specified by id along with
+// its 7 day sparkline from the selected source
+func GetCoinSummary(id CoinID) (geckoTypes.CoinsMarketItem, error) {
+	return GetSource().GetAsset(id)
+}
@@ -0,0 +1,124 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// PriorityShares weigh the parts of a provider's rate limit the supervisor
+// allocates to pollers of coins of each priority. The limit is split only
+// between priorities with pollers running, so a lone priority gets all of
+// it. Pollers sharing a part are slowed down to keep within it, rather than
+// being held back by the rate limiter or backing off once the provider
+// answers 429.
+var PriorityShares = map[string]float64{
+	PriorityHigh:   0.5,
+	PriorityNormal: 0.3,
+	PriorityLow:    0.2,
+}
+
+// priorityKey is the context key of the priority of a coin's pollers
+type priorityKey struct{}
+
+// WithPriority returns a context under which pollers are supervised as those
+// of a coin of the given priority. Pollers under other contexts are
+// supervised as those of a coin of normal priority.
+func WithPriority(ctx context.Context, priority string) context.Context {
+	return context.WithValue(ctx, priorityKey{}, GetRefreshPolicy(priority).Priority)
+}
+
+// priorityOf returns the priority pollers under ctx are supervised with
+func priorityOf(ctx context.Context) string {
+	if priority, ok := ctx.Value(priorityKey{}).(string); ok {
+		return priority
+	}
+	return PriorityNormal
+}
+
+// pollSupervisor counts the pollers running against each provider for each
+// priority, to split the provider's rate limit between them
+type pollSupervisor struct {
+	sync.Mutex
+	pollers map[string]map[string]int
+}
+
+var supervisor = &pollSupervisor{pollers: make(map[string]map[string]int)}
+
+// supervise registers a poller of provider with the priority of ctx. It
+// returns how often the poller may refresh when it wants to every t, checked
+// again every poll as pollers come and go, and a func releasing the poller.
+func supervise(ctx context.Context, provider string, t time.Duration) (every func() time.Duration, release func()) {
+	priority := priorityOf(ctx)
+
+	supervisor.Lock()
+	if supervisor.pollers[provider] == nil {
+		supervisor.pollers[provider] = make(map[string]int)
+	}
+	supervisor.pollers[provider][priority]++
+	supervisor.Unlock()
+
+	every = func() time.Duration {
+		return supervisor.interval(provider, priority, t)
+	}
+	release = func() {
+		supervisor.Lock()
+		supervisor.pollers[provider][priority]--
+		supervisor.Unlock()
+	}
+	return every, release
+}
+
+// interval returns how often a poller of provider with priority may refresh
+// when it wants to every t, lengthened so the pollers of the priority make
+// no more requests than its share of the provider's rate limit
+func (s *pollSupervisor) interval(provider, priority string, t time.Duration) time.Duration {
+	s.Lock()
+	pollers := s.pollers[provider][priority]
+	active := 0.0
+	for p, n := range s.pollers[provider] {
+		if n > 0 {
+			active += PriorityShares[p]
+		}
+	}
+	s.Unlock()
+
+	if pollers <= 0 || active <= 0 {
+		return t
+	}
+	budget := PriorityShares[priority] / active * float64(httpLimiter.limit(provider))
+	if budget <= 0 {
+		return t
+	}
+
+	// Each poller makes about a request a poll
+	if least := time.Duration(float64(pollers) / budget * float64(time.Minute)); least > t {
+		return least
+	}
+	return t
+}
+
+// sourceProvider returns the name of the provider a source is rate limited
+// as
+func sourceProvider(src Source) string {
+	if src.Name() == "default" {
+		return "coingecko"
+	}
+	return src.Name()
+}
@@ -16,18 +16,62 @@ limitations under the License.
 
 package api
 
-import geckoTypes "github.com/superoo7/go-gecko/v3/types"
+import (
+	"time"
+
+	geckoTypes "github.com/superoo7/go-gecko/v3/types"
+)
 
 // CoinData Holds data pertaining to a single coin.
 // This is used to serve per coin details.
 // It additionally holds a map of favourite coins.
 type CoinData struct {
-	Type         string
-	PriceHistory []float64
-	MinPrice     float64
-	MaxPrice     float64
-	Details      CoinDetails
-	Favourites   map[string]float64
+	Type           string
+	PriceHistory   []Point
+	YearAgo        []float64 // Prices a year earlier, offset by MinPrice and lined up with the last points of PriceHistory
+	Interval       string
+	MinPrice       float64
+	MaxPrice       float64
+	Details        CoinDetails
+	Favourites     map[string]float64
+	Sparklines     map[string][]float64 // 7 day USD prices of favourites by symbol
+	FavouriteStats FavouriteStats
+	FavouriteCoins geckoTypes.CoinsMarket // Market data of favourites, as last fetched
+	Watchlist      string                 // Name of the watchlist favourites are of
+	Quote          CoinID
+	Candles        []Candle
+	Timeframe      string
+	OrderBook      OrderBook
+	Markets        []Market
+	News           []Headline
+	Volumes        []ExchangeVolume
+	Degraded       string // Why data is partial or stale, empty if complete
+}
+
+// Point is a price of a coin at a point in time. Prices of PriceHistory are
+// offset by MinPrice.
+type Point struct {
+	Time  time.Time
+	Price float64
+}
+
+// Candle holds the open, high, low and close price of a coin over a period
+// starting at Time
+type Candle struct {
+	Time  time.Time
+	Open  float64
+	High  float64
+	Low   float64
+	Close float64
+}
+
+// FavouriteStats holds aggregate stats of the favourite coins
+type FavouriteStats struct {
+	Count            int
+	MarketCap        float64
+	AverageChange24h float64
+	Advancing        int
+	Declining        int
 }
 
 // CoinDetails holds information about a coin
@@ -36,6 +80,8 @@ type CoinDetails struct {
 	Symbol         string
 	Rank           string
 	BlockTime      string
+	CurrentPrice   float64
+	Change24h      float64
 	MarketCap      float64
 	Website        string
 	Explorers      [][]string
@@ -47,21 +93,67 @@ type CoinDetails struct {
 	Low24          float64
 	TotalVolume    float64
 	ChangePercents [][]string
-	TotalSupply    float64
+	Performance    []PerformanceChange
+	MaxSupply      float64
+	HasMaxSupply   bool
 	CurrentSupply  float64
 	LastUpdate     string
 }
 
+// PerformanceChange holds the change in percent of a coin's price over a
+// timeframe, Eg: 24H
+type PerformanceChange struct {
+	Label  string
+	Change float64
+}
+
+// MarketBreadth holds the number of coins advancing and declining among the
+// top ranked coins over 24 hours
+type MarketBreadth struct {
+	Total     int
+	Advancing int
+	Declining int
+}
+
 // AssetData is used to hold details of multiple coins and the price history
 // of top ranked coins along with their names
 type AssetData struct {
-	IsTopCoinData bool
-	TopCoinData   [][]float64
-	MaxPrices     []float64
-	MinPrices     []float64
-	TopCoins      []string
-	Ranks         []int16
-	AllCoinData   geckoTypes.CoinsMarket
+	IsTopCoinData          bool
+	TopCoinData            [][]float64
+	MaxPrices              []float64
+	MinPrices              []float64
+	TopCoins               []string
+	Ranks                  []int16
+	AllCoinData            geckoTypes.CoinsMarket
+	IsAltseasonData        bool
+	AltseasonIndex         float64
+	AltseasonHistory       []float64
+	IsDominanceData        bool
+	DominanceHistory       []float64
+	MinDominance           float64
+	MaxDominance           float64
+	DominanceSince         time.Time
+	IsOverviewData         bool
+	Overview               GlobalOverview
+	IsStaleData            bool
+	StaleCoins             []StaleCoin
+	IsWatchlistData        bool
+	Watchlist              map[string]bool
+	IsSparklineData        bool
+	Sparklines             map[string][]float64 // USD prices by CoinGecko ID
+	IsRiskData             bool
+	Risk                   PortfolioRisk
+	IsPortfolioHistoryData bool
+	PortfolioHistory       PortfolioHistory
+}
+
+// StaleCoin is a favourite or holding which no longer returns data, Eg:
+// after being delisted or renamed. Suggestions holds IDs of listed coins
+// which may have replaced it.
+type StaleCoin struct {
+	ID          string
+	Reason      string
+	Suggestions []string
 }
 
 // CoinCapAsset is used to marshal asset data from coinCap APIs
@@ -86,11 +178,76 @@ type CoinCapData struct {
 	Timestamp uint           `json:"timestamp"`
 }
 
-// CoinID holds the ID of a coin as stored in CoinGecko and CoinCap
+// CoinID holds the ID of a coin as stored in CoinGecko and CoinCap, along
+// with its symbol used to pick exchange pairs
 type CoinID struct {
 	CoinGeckoID string
 	CoinCapID   string
+	Symbol      string
 }
 
 // CoinIDMap maps a symbol to it's respective ID
 type CoinIDMap map[string]CoinID
+
+// YieldPool holds the yield of a stablecoin pool
+type YieldPool struct {
+	Project string
+	Chain   string
+	Symbol  string
+	TVL     float64
+	APY     float64
+}
+
+// FundingRate holds the last funding rate (%) of a perpetual contract and
+// the rate annualised
+type FundingRate struct {
+	Symbol     string
+	Rate       float64
+	AnnualRate float64
+}
+
+// YieldData is used to send stablecoin yields and funding rates to the
+// yields page
+type YieldData struct {
+	Pools      []YieldPool
+	Funding    []FundingRate
+	AverageAPY float64
+	TotalTVL   float64
+}
+
+// ExchangeData is used to send exchanges ranked by volume, or markets of the
+// selected exchange, to the exchanges page
+type ExchangeData struct {
+	Type      string // EXCHANGES or MARKETS
+	Exchanges []Exchange
+	Exchange  Exchange // Exchange the markets are of
+	Markets   []Market
+}
+
+// CompareCoin holds the price history of a compared coin, as the change in
+// percent since the start of the interval, along with its 24 hour stats
+type CompareCoin struct {
+	ID          CoinID
+	Symbol      string
+	Changes     []float64
+	Price       float64
+	Change24h   float64
+	High24      float64
+	Low24       float64
+	TotalVolume float64
+}
+
+// CompareData is used to send compared coins to the compare page
+type CompareData struct {
+	Interval string
+	Coins    []CompareCoin
+}
+
+// RatioData is used to send the price history of a coin in terms of another
+// to the ratio page. SMA lines up with the last points of Ratio.
+type RatioData struct {
+	Interval string
+	Ratio    []float64
+	SMA      []float64
+	Times    []time.Time
+}
@@ -0,0 +1,193 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
+)
+
+// volumeRefresh is how often the volume breakdown of a coin is refreshed
+// while shown
+const volumeRefresh = time.Duration(1) * time.Minute
+
+// maxVolumeExchanges is the number of exchanges volume is broken down by,
+// the rest are grouped as others
+const maxVolumeExchanges = 12
+
+// Trust scores CoinGecko rates tickers with
+const (
+	TrustHigh   = "green"
+	TrustMedium = "yellow"
+	TrustLow    = "red"
+)
+
+// ExchangeVolume holds the share of a coin's volume traded on an exchange
+type ExchangeVolume struct {
+	Exchange  string
+	Trust     string  // Trust score of the exchange's largest market of the coin, empty if unrated
+	VolumeUSD float64 // Volume over 24 hours
+	Share     float64 // Share of the coin's volume, in percent
+}
+
+// LowTrust returns true if CoinGecko rates trust in the exchange low
+func (v ExchangeVolume) LowTrust() bool {
+	return v.Trust == TrustLow
+}
+
+// geckoTickers holds tickers of a coin from CoinGecko
+type geckoTickers struct {
+	Tickers []struct {
+		Market struct {
+			Name string `json:"name"`
+		} `json:"market"`
+		ConvertedVolume struct {
+			USD float64 `json:"usd"`
+		} `json:"converted_volume"`
+		TrustScore string `json:"trust_score"`
+		IsAnomaly  bool   `json:"is_anomaly"`
+		IsStale    bool   `json:"is_stale"`
+	} `json:"tickers"`
+}
+
+// GetVolumeBreakdown returns the share of a coin's 24 hour volume traded on
+// each of its top exchanges, from its CoinGecko tickers ordered by volume.
+// Anomalous and stale tickers are left out, and exchanges past limit are
+// grouped as Others.
+func GetVolumeBreakdown(id CoinID, limit int) ([]ExchangeVolume, error) {
+	if id.CoinGeckoID == "" {
+		return nil, fmt.Errorf("%w on CoinGecko", ErrNotListed)
+	}
+
+	url := fmt.Sprintf("%s/coins/%s/tickers?order=volume_desc", geckoURL, id.CoinGeckoID)
+	body, err := NewGeckoClient().MakeReq(url)
+	if err != nil {
+		return nil, err
+	}
+
+	data := geckoTickers{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, err
+	}
+
+	// Sum volume of each exchange, rating it by its largest market
+	volumes := map[string]*ExchangeVolume{}
+	largest := map[string]float64{}
+	total := 0.0
+	for _, ticker := range data.Tickers {
+		if ticker.IsAnomaly || ticker.IsStale || ticker.ConvertedVolume.USD <= 0 {
+			continue
+		}
+
+		name := ticker.Market.Name
+		if _, ok := volumes[name]; !ok {
+			volumes[name] = &ExchangeVolume{Exchange: name}
+		}
+		volumes[name].VolumeUSD += ticker.ConvertedVolume.USD
+		if ticker.ConvertedVolume.USD > largest[name] {
+			largest[name] = ticker.ConvertedVolume.USD
+			volumes[name].Trust = ticker.TrustScore
+		}
+		total += ticker.ConvertedVolume.USD
+	}
+
+	breakdown := []ExchangeVolume{}
+	for _, val := range volumes {
+		val.Share = val.VolumeUSD / total * 100
+		breakdown = append(breakdown, *val)
+	}
+	sort.Slice(breakdown, func(i, j int) bool {
+		return breakdown[i].VolumeUSD > breakdown[j].VolumeUSD
+	})
+
+	if len(breakdown) > limit {
+		others := ExchangeVolume{Exchange: "Others"}
+		for _, val := range breakdown[limit:] {
+			others.VolumeUSD += val.VolumeUSD
+			others.Share += val.Share
+		}
+		breakdown = append(breakdown[:limit], others)
+	}
+
+	return breakdown, nil
+}
+
+// GetCoinVolume fetches the volume breakdown of a coin every volumeRefresh
+// while enabled through the volume channel, and sends it on dataChannel. It
+// is fetched as soon as it is enabled.
+func GetCoinVolume(ctx context.Context, id CoinID, volumeChannel chan bool, dataChannel chan CoinData) error {
+	enabled := false
+	fetched := time.Time{}
+
+	return utils.LoopTick(ctx, time.Duration(1)*time.Second, func(errChan chan error) {
+		var finalErr error = nil
+
+		defer func() {
+			if finalErr != nil {
+				errChan <- finalErr
+			}
+		}()
+
+		select {
+		case <-ctx.Done():
+			finalErr = ctx.Err()
+			return
+		case e := <-volumeChannel:
+			// Update state, fetching again once enabled
+			enabled = e
+			fetched = time.Time{}
+		default:
+			break
+		}
+
+		if !enabled || time.Since(fetched) < utils.PollInterval(volumeRefresh) {
+			return
+		}
+		fetched = time.Now()
+
+		// The breakdown is optional, so it is shown as unavailable while
+		// CoinGecko is rather than closing the coin page
+		volumes, err := GetVolumeBreakdown(id, maxVolumeExchanges)
+		trackStatus("volume", volumeRefresh, err)
+		if err != nil && !isUnavailable(err) {
+			finalErr = err
+			return
+		}
+
+		// Aggregate data
+		coinData := CoinData{
+			Type:    "VOLUME",
+			Volumes: volumes,
+		}
+		if err != nil {
+			coinData.Degraded = "unavailable"
+		}
+
+		// Send Data
+		select {
+		case <-ctx.Done():
+			finalErr = ctx.Err()
+			return
+		case dataChannel <- coinData:
+		}
+	})
+}
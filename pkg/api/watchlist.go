@@ -0,0 +1,72 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
+)
+
+// watchlistInterval is how often watchlist files are checked for changes
+const watchlistInterval = time.Duration(2) * time.Second
+
+// GetWatchlists watches the watchlist directory and sends coin IDs listed in
+// its files on dataChannel whenever they change, so that files written by
+// other tools are picked up without restarting.
+func GetWatchlists(ctx context.Context, dataChannel chan AssetData, sendData *bool) error {
+	// Version of the last sent watchlists, nothing is sent till it changes
+	sentVersion := ""
+	sentDir := ""
+
+	return utils.LoopTick(ctx, watchlistInterval, func(errChan chan error) {
+		var finalErr error = nil
+
+		defer func() {
+			if finalErr != nil {
+				errChan <- finalErr
+			}
+		}()
+
+		if !*sendData {
+			return
+		}
+
+		// The directory may change when config is reloaded
+		dir := utils.WatchlistDir
+		version := utils.WatchlistVersion(dir)
+		if version == sentVersion && dir == sentDir {
+			return
+		}
+
+		data := AssetData{
+			IsWatchlistData: true,
+			Watchlist:       utils.GetWatchlists(dir),
+		}
+
+		// Send Data
+		select {
+		case <-ctx.Done():
+			finalErr = ctx.Err()
+			return
+		case dataChannel <- data:
+			sentVersion = version
+			sentDir = dir
+		}
+	})
+}
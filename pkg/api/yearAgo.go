@@ -0,0 +1,108 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"time"
+)
+
+// yearAgoRefresh is how often history of the period a year earlier is
+// fetched again, as the period moves along with the interval
+const yearAgoRefresh = time.Duration(10) * time.Minute
+
+// intervalRange returns the start and end of an interval of history, Eg:
+// the last 7 days for 7d
+func intervalRange(interval string) (time.Time, time.Time, error) {
+	if days, ok := intervalDays[interval]; ok {
+		end := time.Now()
+		return end.AddDate(0, 0, -days), end, nil
+	}
+	return ParseHistoryRange(interval)
+}
+
+// yearAgoHistory returns the price history of a coin from src over an
+// interval a year earlier, with times moved a year later so they line up
+// with history of the interval. Prices are in terms of quote, or USD if
+// quote is empty.
+func yearAgoHistory(src Source, id, quote CoinID, interval string) ([]float64, []time.Time, error) {
+	start, end, err := intervalRange(interval)
+	if err != nil {
+		return nil, nil, err
+	}
+	start, end = start.AddDate(-1, 0, 0), end.AddDate(-1, 0, 0)
+
+	history, err := src.GetHistoryRange(id, start, end)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	prices := []float64{}
+	times := []time.Time{}
+	if quote == (CoinID{}) {
+		for _, v := range history {
+			prices = append(prices, float64(v[1]))
+			times = append(times, chartTime(v))
+		}
+	} else {
+		quoteHistory, err := src.GetHistoryRange(quote, start, end)
+		if err != nil {
+			return nil, nil, err
+		}
+		prices, times = PriceRatio(history, quoteHistory)
+	}
+
+	for i := range times {
+		times[i] = times[i].AddDate(1, 0, 0)
+	}
+
+	return prices, times, nil
+}
+
+// alignPrices returns prices sampled at times, at each time of at. Prices
+// are linearly interpolated between neighbouring samples, and times after
+// the last sample take its price. Leading times before the first sample are
+// dropped, Eg: for coins listed less than a year ago, so the prices line up
+// with the last times of at.
+func alignPrices(prices []float64, times []time.Time, at []time.Time) []float64 {
+	aligned := []float64{}
+	if len(prices) == 0 || len(prices) != len(times) {
+		return aligned
+	}
+
+	j := 0
+	for _, t := range at {
+		if t.Before(times[0]) {
+			continue
+		}
+
+		// Move to the sample at or before t
+		for j < len(times)-1 && !times[j+1].After(t) {
+			j++
+		}
+
+		price := prices[j]
+		if j < len(times)-1 {
+			span := times[j+1].Sub(times[j])
+			if span > 0 {
+				price += (prices[j+1] - prices[j]) * float64(t.Sub(times[j])) / float64(span)
+			}
+		}
+		aligned = append(aligned, price)
+	}
+
+	return aligned
+}
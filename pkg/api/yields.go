@@ -0,0 +1,187 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
+)
+
+const (
+	yieldPoolsURL   = "https://yields.llama.fi/pools"
+	fundingRatesURL = "https://fapi.binance.com/fapi/v1/premiumIndex"
+	yieldPools      = 50
+	yieldMinTVL     = 10000000 // Pools with lesser TVL (USD) are left out
+	fundingPerYear  = 3 * 365  // Funding is settled every 8 hours
+)
+
+// fundingSymbols holds perpetual contracts for which funding rates are shown
+var fundingSymbols = []string{"BTCUSDT", "ETHUSDT", "SOLUSDT", "BNBUSDT", "XRPUSDT"}
+
+// llamaPools holds the response of the DefiLlama yields API
+type llamaPools struct {
+	Status string `json:"status"`
+	Data   []struct {
+		Chain      string  `json:"chain"`
+		Project    string  `json:"project"`
+		Symbol     string  `json:"symbol"`
+		TVLUsd     float64 `json:"tvlUsd"`
+		APY        float64 `json:"apy"`
+		Stablecoin bool    `json:"stablecoin"`
+	} `json:"data"`
+}
+
+// premiumIndex holds funding details of a perpetual contract
+type premiumIndex struct {
+	Symbol          string `json:"symbol"`
+	LastFundingRate string `json:"lastFundingRate"`
+}
+
+// getStablecoinYields returns the largest stablecoin lending/earn pools by TVL
+func getStablecoinYields() ([]YieldPool, error) {
+	res, err := Client.Get(yieldPoolsURL)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	pools := llamaPools{}
+	err = json.NewDecoder(res.Body).Decode(&pools)
+	if err != nil {
+		return nil, err
+	}
+
+	yields := []YieldPool{}
+	for _, val := range pools.Data {
+		if !val.Stablecoin || val.TVLUsd < yieldMinTVL {
+			continue
+		}
+		yields = append(yields, YieldPool{
+			Project: val.Project,
+			Chain:   val.Chain,
+			Symbol:  val.Symbol,
+			TVL:     val.TVLUsd,
+			APY:     val.APY,
+		})
+	}
+
+	sort.Slice(yields, func(i, j int) bool {
+		return yields[i].TVL > yields[j].TVL
+	})
+	if len(yields) > yieldPools {
+		yields = yields[:yieldPools]
+	}
+
+	return yields, nil
+}
+
+// getFundingRates returns the last funding rate of perpetuals listed in
+// fundingSymbols
+func getFundingRates() ([]FundingRate, error) {
+	res, err := Client.Get(fundingRatesURL)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	indices := []premiumIndex{}
+	err = json.NewDecoder(res.Body).Decode(&indices)
+	if err != nil {
+		return nil, err
+	}
+
+	rates := make(map[string]float64)
+	for _, val := range indices {
+		rate, err := strconv.ParseFloat(val.LastFundingRate, 64)
+		if err == nil {
+			rates[val.Symbol] = rate
+		}
+	}
+
+	funding := []FundingRate{}
+	for _, symbol := range fundingSymbols {
+		rate, ok := rates[symbol]
+		if !ok {
+			continue
+		}
+		funding = append(funding, FundingRate{
+			Symbol:     symbol,
+			Rate:       rate * 100,
+			AnnualRate: rate * fundingPerYear * 100,
+		})
+	}
+
+	return funding, nil
+}
+
+// GetYields serves stablecoin yields from DefiLlama along with funding rates
+// of major perpetuals from Binance, to compare the yield of holding stablecoins
+// with that of a cash-and-carry trade. Funding rates are left empty if they
+// can't be fetched, as the endpoint is not reachable from all regions.
+func GetYields(ctx context.Context, dataChannel chan YieldData) error {
+	return utils.LoopTick(ctx, time.Duration(5)*time.Minute, func(errChan chan error) {
+		var finalErr error = nil
+		data := YieldData{}
+
+		defer func() {
+			if finalErr != nil {
+				errChan <- finalErr
+			}
+		}()
+
+		// Fetch Data
+		pools, err := getStablecoinYields()
+		if err != nil {
+			finalErr = err
+			return
+		}
+
+		funding, err := getFundingRates()
+		if err != nil {
+			funding = []FundingRate{}
+		}
+
+		// Compute TVL weighted average APY
+		totalTVL := 0.0
+		weightedAPY := 0.0
+		for _, pool := range pools {
+			totalTVL += pool.TVL
+			weightedAPY += pool.TVL * pool.APY
+		}
+		if totalTVL > 0 {
+			data.AverageAPY = weightedAPY / totalTVL
+		}
+
+		// Aggregate data
+		data.Pools = pools
+		data.Funding = funding
+		data.TotalTVL = totalTVL
+
+		// Send data
+		select {
+		case <-ctx.Done():
+			finalErr = ctx.Err()
+			return
+		case dataChannel <- data:
+		}
+	})
+}
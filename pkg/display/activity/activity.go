@@ -0,0 +1,312 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package activity
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/exchange"
+	"github.com/Gituser143/cryptgo/pkg/portfolio"
+	"github.com/Gituser143/cryptgo/pkg/utils"
+	"github.com/Gituser143/cryptgo/pkg/widgets"
+	ui "github.com/gizak/termui/v3"
+)
+
+// DisplayActivity displays open orders and recent fills of connected
+// exchange accounts, updated as they are sent on dataChannel. Fills are
+// added to imported trades once confirmed, priced with priceAt if not
+// quoted in USD.
+func DisplayActivity(ctx context.Context, dataChannel chan exchange.Activity, priceAt func(coin string, t time.Time) (float64, error)) error {
+
+	// Initialise UI
+	if err := ui.Init(); err != nil {
+		return fmt.Errorf("failed to initialise termui: %v", err)
+	}
+	defer ui.Close()
+
+	// Initialise page
+	page := newActivityPage(ui.TerminalDimensions())
+	selectedTable := page.FillsTable
+	utilitySelected := ""
+
+	// Initialise help menu
+	help := widgets.NewHelpMenu()
+	help.SelectHelpMenu("ACTIVITY")
+
+	// Initialise banner for imports and errors
+	banner := widgets.NewBanner()
+	banner.Title = " Activity "
+
+	// Fills last received
+	fills := []exchange.Fill{}
+
+	// importFills adds fills to imported trades once confirmed, leaving
+	// out those already imported
+	importFills := func(uiEvents <-chan ui.Event) {
+		trades, errs := portfolio.FillTrades(fills)
+		merged, added := portfolio.MergeTrades(utils.GetTrades(), trades)
+		if added == 0 {
+			banner.Show("Fills are already in the ledger", time.Duration(3)*time.Second)
+			return
+		}
+
+		inputStr := widgets.DrawPrompt(uiEvents, fmt.Sprintf(" Type yes to add %d trades to the ledger ", added))
+		if strings.TrimSpace(strings.ToLower(inputStr)) != "yes" {
+			banner.Show("Fills not added", time.Duration(3)*time.Second)
+			return
+		}
+
+		merged, priceErrs := portfolio.PriceTrades(merged, priceAt)
+		if err := utils.SaveTrades(merged); err != nil {
+			banner.Show(err.Error(), time.Duration(5)*time.Second)
+			return
+		}
+
+		message := fmt.Sprintf("Added %d trades to the ledger", added)
+		if skipped := len(errs) + len(priceErrs); skipped > 0 {
+			message += fmt.Sprintf(", %d fills skipped or not priced", skipped)
+		}
+		banner.Show(message, time.Duration(5)*time.Second)
+	}
+
+	previousKey := ""
+
+	// UpdateUI to refresh UI
+	updateUI := func() {
+		// Get Terminal Dimensions
+		w, h := ui.TerminalDimensions()
+		page.Grid.SetRect(0, 0, w, h)
+
+		// Clear UI
+		ui.Clear()
+
+		// Render required widgets
+		switch utilitySelected {
+		case "HELP":
+			help.Resize(w, h)
+			ui.Render(help)
+		default:
+			ui.Render(page.Grid)
+		}
+
+		if banner.Active() {
+			banner.Resize(w, h)
+			ui.Render(banner)
+		}
+	}
+
+	// Render Empty UI
+	updateUI()
+
+	// Create Channel to get keyboard events
+	uiEvents := ui.PollEvents()
+
+	// Create ticker to periodically refresh UI
+	t := time.NewTicker(utils.UIRefresh)
+	tick := t.C
+
+	// Reload config, Eg: after the config file was edited. The
+	// previous config is kept if the file can't be read or is invalid, and
+	// the error is flashed on the banner.
+	reload := func() {
+		if err := utils.Reload(); err != nil {
+			banner.Show("Config not reloaded: "+err.Error(), time.Duration(5)*time.Second)
+		}
+		updateUI()
+	}
+
+	// Create channels to get suspend and reload signals
+	suspendSignals := utils.SuspendSignals()
+	reloadSignals := utils.ReloadSignals()
+
+	for {
+		select {
+		case <-ctx.Done(): // Context cancelled, exit
+			return ctx.Err()
+
+		case <-suspendSignals: // SIGTSTP sent from outside the UI
+			if err := utils.Suspend(); err != nil {
+				return err
+			}
+			updateUI()
+
+		case <-reloadSignals: // SIGHUP
+			reload()
+
+		case e := <-uiEvents:
+			switch e.ID {
+
+			// handle button events
+			case "q", "<C-c>":
+				return fmt.Errorf("UI Closed")
+
+			case "<Escape>":
+				if utilitySelected != "" {
+					utilitySelected = ""
+					selectedTable = page.FillsTable
+					selectedTable.ShowCursor = true
+				}
+
+			case "<Resize>":
+				updateUI()
+
+			case "<C-z>":
+				if err := utils.Suspend(); err != nil {
+					return err
+				}
+				updateUI()
+
+			case "<C-r>":
+				reload()
+
+			case "?":
+				selectedTable.ShowCursor = false
+				selectedTable = help.Table
+				selectedTable.ShowCursor = true
+				utilitySelected = "HELP"
+
+			case "<Tab>":
+				if utilitySelected == "" {
+					selectedTable.ShowCursor = false
+					if selectedTable == page.FillsTable {
+						selectedTable = page.OrdersTable
+					} else {
+						selectedTable = page.FillsTable
+					}
+					selectedTable.ShowCursor = true
+				}
+
+			case "i":
+				if utilitySelected == "" {
+					importFills(uiEvents)
+				}
+
+			// Navigations
+			case "j", "<Down>":
+				selectedTable.ScrollDown()
+
+			case "k", "<Up>":
+				selectedTable.ScrollUp()
+
+			case "<C-d>":
+				selectedTable.ScrollHalfPageDown()
+
+			case "<C-u>":
+				selectedTable.ScrollHalfPageUp()
+
+			case "<C-f>":
+				selectedTable.ScrollPageDown()
+
+			case "<C-b>":
+				selectedTable.ScrollPageUp()
+
+			case "g":
+				if previousKey == "g" {
+					selectedTable.ScrollTop()
+				}
+
+			case "<Home>":
+				selectedTable.ScrollTop()
+
+			case "G", "<End>":
+				selectedTable.ScrollBottom()
+			}
+
+			updateUI()
+			if previousKey == "g" {
+				previousKey = ""
+			} else {
+				previousKey = e.ID
+			}
+
+		case data := <-dataChannel:
+			fills = data.Fills
+
+			rows := [][]string{}
+			for _, order := range data.Orders {
+				rows = append(rows, orderRow(order))
+			}
+			page.OrdersTable.Rows = rows
+
+			rows = [][]string{}
+			for _, fill := range data.Fills {
+				rows = append(rows, fillRow(fill))
+			}
+			page.FillsTable.Rows = rows
+
+			// Exchanges whose activity couldn't be fetched are named in
+			// titles, with the reasons shown on the banner
+			unavailable := []string{}
+			reasons := []string{}
+			for name, err := range data.Errors {
+				unavailable = append(unavailable, name)
+				reasons = append(reasons, fmt.Sprintf("%s: %v", name, err))
+			}
+			sort.Strings(unavailable)
+			sort.Strings(reasons)
+
+			page.OrdersTable.Title = fmt.Sprintf(" Open Orders - %d ", len(data.Orders))
+			page.FillsTable.Title = fmt.Sprintf(" Fills - %d ", len(data.Fills))
+			if len(unavailable) > 0 {
+				page.FillsTable.Title += fmt.Sprintf("- unavailable on %s ", strings.Join(unavailable, ", "))
+				banner.Show(strings.Join(reasons, " | "), time.Duration(5)*time.Second)
+			}
+
+		case <-tick:
+			updateUI()
+		}
+	}
+}
+
+// orderRow returns the row of an open order
+func orderRow(order exchange.Order) []string {
+	price := "market"
+	if order.Price > 0 {
+		price = strconv.FormatFloat(order.Price, 'f', -1, 64)
+	}
+
+	return []string{
+		order.Placed.Local().Format("2006-01-02 15:04"),
+		order.Exchange,
+		order.Pair,
+		order.Side,
+		order.Type,
+		strconv.FormatFloat(order.Quantity, 'f', -1, 64),
+		price,
+		strconv.FormatFloat(order.Filled, 'f', -1, 64),
+		order.Status,
+	}
+}
+
+// fillRow returns the row of a fill, with its fee in the asset paid in
+func fillRow(fill exchange.Fill) []string {
+	return []string{
+		fill.Time.Local().Format("2006-01-02 15:04"),
+		fill.Exchange,
+		fill.Base + "/" + fill.Quote,
+		fill.Side,
+		strconv.FormatFloat(fill.Quantity, 'f', -1, 64),
+		strconv.FormatFloat(fill.Price, 'f', -1, 64),
+		strconv.FormatFloat(fill.Amount, 'f', -1, 64),
+		strings.TrimSpace(strconv.FormatFloat(fill.Fee, 'f', -1, 64) + " " + fill.FeeAsset),
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package activity
+
+import (
+	"github.com/Gituser143/cryptgo/pkg/widgets"
+	ui "github.com/gizak/termui/v3"
+)
+
+// activityPage holds UI items for the activity page
+type activityPage struct {
+	Grid        *ui.Grid
+	OrdersTable *widgets.Table
+	FillsTable  *widgets.Table
+}
+
+func newActivityPage(w, h int) *activityPage {
+	page := &activityPage{
+		Grid:        ui.NewGrid(),
+		OrdersTable: widgets.NewTable(),
+		FillsTable:  widgets.NewTable(),
+	}
+
+	page.init(w, h)
+
+	return page
+}
+
+func (page *activityPage) init(w, h int) {
+	// Initialise Open Orders table
+	page.OrdersTable.Title = " Open Orders "
+	page.OrdersTable.BorderStyle.Fg = ui.ColorCyan
+	page.OrdersTable.TitleStyle.Fg = ui.ColorClear
+	page.OrdersTable.Header = []string{"Placed", "Exchange", "Pair", "Side", "Type", "Quantity", "Price", "Filled", "Status"}
+	page.OrdersTable.ColResizer = func() {
+		x := page.OrdersTable.Inner.Dx()
+		page.OrdersTable.ColWidths = []int{
+			ui.MaxInt(17, x/9),
+			ui.MaxInt(9, x/9),
+			ui.MaxInt(9, x/9),
+			ui.MaxInt(5, x/9),
+			ui.MaxInt(7, x/9),
+			ui.MaxInt(10, x/9),
+			ui.MaxInt(10, x/9),
+			ui.MaxInt(10, x/9),
+			ui.MaxInt(16, x/9),
+		}
+	}
+	page.OrdersTable.ShowCursor = false
+	page.OrdersTable.CursorColor = ui.ColorCyan
+
+	// Initialise Fills table
+	page.FillsTable.Title = " Fills "
+	page.FillsTable.BorderStyle.Fg = ui.ColorCyan
+	page.FillsTable.TitleStyle.Fg = ui.ColorClear
+	page.FillsTable.Header = []string{"Time", "Exchange", "Pair", "Side", "Quantity", "Price", "Amount", "Fee"}
+	page.FillsTable.ColResizer = func() {
+		x := page.FillsTable.Inner.Dx()
+		page.FillsTable.ColWidths = []int{
+			ui.MaxInt(17, x/8),
+			ui.MaxInt(9, x/8),
+			ui.MaxInt(10, x/8),
+			ui.MaxInt(5, x/8),
+			ui.MaxInt(10, x/8),
+			ui.MaxInt(10, x/8),
+			ui.MaxInt(10, x/8),
+			ui.MaxInt(12, x/8),
+		}
+	}
+	page.FillsTable.ShowCursor = true
+	page.FillsTable.CursorColor = ui.ColorCyan
+
+	// Set Grid layout
+	page.Grid.Set(
+		ui.NewRow(0.35, page.OrdersTable),
+		ui.NewRow(0.65, page.FillsTable),
+	)
+
+	page.Grid.SetRect(0, 0, w, h)
+}
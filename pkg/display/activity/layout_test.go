@@ -0,0 +1,74 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package activity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/display/layout/layouttest"
+	"github.com/Gituser143/cryptgo/pkg/exchange"
+	ui "github.com/gizak/termui/v3"
+)
+
+// fillActivityPage fills the page with activity of connected accounts.
+// Times are local, as rows show them in local time.
+func fillActivityPage(page *activityPage) {
+	now := time.Date(2024, time.March, 4, 9, 30, 0, 0, time.Local)
+
+	orders := []exchange.Order{
+		{Exchange: "binance", Pair: "BTCUSDT", Side: exchange.Buy, Type: exchange.Limit, Quantity: 0.01, Price: 61250, Status: exchange.StatusNew, Placed: now},
+		{Exchange: "kraken", Pair: "ETHUSD", Side: exchange.Sell, Type: exchange.Limit, Quantity: 0.5, Price: 3650, Filled: 0.1, Status: exchange.StatusPartial, Placed: now.Add(-5 * time.Hour)},
+	}
+	rows := [][]string{}
+	for _, order := range orders {
+		rows = append(rows, orderRow(order))
+	}
+	page.OrdersTable.Rows = rows
+	page.OrdersTable.Title = " Open Orders - 2 "
+
+	fills := []exchange.Fill{
+		{Exchange: "binance", Time: now.Add(-26 * time.Hour), Base: "SOL", Quote: "USDT", Side: exchange.Sell, Quantity: 12, Price: 132.4, Amount: 1588.8, Fee: 1.5888, FeeAsset: "USDT"},
+		{Exchange: "binance", Time: now.Add(-50 * time.Hour), Base: "BTC", Quote: "USDT", Side: exchange.Buy, Quantity: 0.02, Price: 60120.5, Amount: 1202.41, Fee: 0.00002, FeeAsset: "BTC"},
+		{Exchange: "kraken", Time: now.Add(-96 * time.Hour), Base: "ETH", Quote: "USD", Side: exchange.Buy, Quantity: 1, Price: 3401.2, Amount: 3401.2, Fee: 5.44, FeeAsset: "USD"},
+	}
+	rows = [][]string{}
+	for _, fill := range fills {
+		rows = append(rows, fillRow(fill))
+	}
+	page.FillsTable.Rows = rows
+	page.FillsTable.Title = " Fills - 3 - unavailable on coinbase "
+}
+
+func TestLayout(t *testing.T) {
+	layouttest.Run(t, []layouttest.Case{
+		{
+			Name: "activity_fetching",
+			Page: func(w, h int) []ui.Drawable {
+				return []ui.Drawable{newActivityPage(w, h).Grid}
+			},
+		},
+		{
+			Name: "activity",
+			Page: func(w, h int) []ui.Drawable {
+				page := newActivityPage(w, h)
+				fillActivityPage(page)
+				return []ui.Drawable{page.Grid}
+			},
+		},
+	})
+}
@@ -19,16 +19,22 @@ package allcoin
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/Gituser143/cryptgo/pkg/alerts"
 	"github.com/Gituser143/cryptgo/pkg/api"
 	"github.com/Gituser143/cryptgo/pkg/display/coin"
+	"github.com/Gituser143/cryptgo/pkg/display/compare"
+	"github.com/Gituser143/cryptgo/pkg/display/theme"
 	uw "github.com/Gituser143/cryptgo/pkg/display/utilitywidgets"
+	"github.com/Gituser143/cryptgo/pkg/keys"
 	"github.com/Gituser143/cryptgo/pkg/utils"
 	"github.com/Gituser143/cryptgo/pkg/widgets"
 	ui "github.com/gizak/termui/v3"
+	geckoTypes "github.com/superoo7/go-gecko/v3/types"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -37,30 +43,48 @@ const (
 	DOWN_ARROW = "▼"
 )
 
+// formatChange formats the change of a coin over duration with an arrow,
+// or NA if its source doesn't serve changes over the duration
+func formatChange(coinData geckoTypes.CoinsMarketItem, duration string) string {
+	change, ok := api.PercentageChange(coinData, duration)
+	if !ok {
+		return "NA"
+	}
+	if change < 0 {
+		return fmt.Sprintf("%s %.2f", DOWN_ARROW, -change)
+	}
+	return fmt.Sprintf("%s %.2f", UP_ARROW, change)
+}
+
 // DisplayAllCoins displays the main page with top coin prices, favourites and
-// general coin asset data
-func DisplayAllCoins(ctx context.Context, dataChannel chan api.AssetData, sendData *bool) error {
+// general coin asset data. Coins of the coin table are sent on the sparkline
+// channel to fetch their sparklines.
+func DisplayAllCoins(ctx context.Context, dataChannel chan api.AssetData, sparklineChannel chan []api.CoinID, sendData *bool) error {
 
 	// Initialise UI
 	if err := ui.Init(); err != nil {
 		return fmt.Errorf("failed to initialise termui: %v", err)
 	}
 	defer ui.Close()
+	defer utils.SetIdle(false)
+
+	// Responses recorded since the page was opened are saved with reports
+	opened := time.Now()
 
 	// Variables for CoinIDs
 	coinIDMap := api.NewCoinIDMap()
 	coinIDMap.Populate()
 
 	currencyWidget := uw.NewCurrencyPage()
-	currencyID := utils.GetCurrency()
-	currencyID, currency, currencyVal := currencyWidget.Get(currencyID)
+	currency := currencyWidget.Get(utils.GetCurrency())
 
-	// Variables for percentage change
-	changePercent := "24h"
+	// Duration of the last change column, the others are fixed to 1h, 24h
+	// and 7d
+	changePercent := "30d"
 	changePercentWidget := uw.NewChangePercentPage()
 
 	// Initalise page and set selected table
-	page := newAllCoinPage()
+	page := newAllCoinPage(ui.TerminalDimensions())
 	selectedTable := page.CoinTable
 	utilitySelected := ""
 
@@ -69,47 +93,199 @@ func DisplayAllCoins(ctx context.Context, dataChannel chan api.AssetData, sendDa
 	favourites := utils.GetFavourites()
 
 	defer func() {
-		utils.SaveMetadata(favourites, currencyID, portfolioMap)
+		utils.SaveMetadata(favourites, currency.ID, portfolioMap)
+	}()
+
+	// Initialise rank alerts of favourites
+	rankAlerts := utils.GetRankAlerts()
+	rankTracker := api.NewRankTracker()
+
+	// Coins listed in watchlist files, shown along with favourites
+	watchlist := map[string]bool{}
+
+	// Watchlist shown in the favourites table
+	activeList := utils.ActiveWatchlist
+	page.FavouritesTable.Title = fmt.Sprintf(" %s ", activeList)
+
+	// listed returns the watchlist shown in the favourites table.
+	// Favourites are shown along with coins in watchlist files.
+	listed := func() utils.Watchlist {
+		if activeList != utils.FavouritesList {
+			return utils.GetNamedWatchlist(activeList)
+		}
+		shown := map[string]bool{}
+		for id := range favourites {
+			shown[id] = true
+		}
+		for id := range watchlist {
+			shown[id] = true
+		}
+		return utils.Watchlist{Name: utils.FavouritesList, IDs: shown}
+	}
+
+	// Warning shown while favourites or holdings are stale
+	staleWarning := ""
+
+	// Prices of the watchlist shown are polled for aggregate stats in the
+	// favourites footer. The poller is restarted when the list changes.
+	favouritesChannel := make(chan api.CoinData)
+	polledList := utils.Watchlist{}
+	stopFavourites := func() {}
+	defer func() {
+		stopFavourites()
 	}()
 
+	// Aggregate stats of the list polled, kept to show them again in a new
+	// currency
+	favouriteStats := api.FavouriteStats{}
+
+	// updateFooter shows stats of the list polled in the currency in use
+	updateFooter := func() {
+		page.FavouritesTable.Footer = uw.FavouritesFooter(favouriteStats, currency)
+	}
+
+	// pollFavourites starts polling the watchlist shown, if it changed
+	// since last polled. There is nothing to poll for an empty list.
+	pollFavourites := func() {
+		list := listed()
+		if list.Name == polledList.Name && reflect.DeepEqual(list.IDs, polledList.IDs) {
+			return
+		}
+		stopFavourites()
+		stopFavourites = func() {}
+		polledList = list
+		favouriteStats = api.FavouriteStats{}
+		updateFooter()
+		if len(list.IDs) == 0 {
+			return
+		}
+
+		favouritesCtx, cancel := context.WithCancel(ctx)
+		stopFavourites = cancel
+		go api.GetFavouritePrices(favouritesCtx, list, favouritesChannel)
+	}
+	pollFavourites()
+
+	// Global market overview, kept to show it again in a new currency
+	var overview *api.GlobalOverview
+
+	// updateOverview shows the global market overview in the currency in use
+	updateOverview := func() {
+		if overview == nil {
+			return
+		}
+
+		change := fmt.Sprintf("%s %.2f%%", UP_ARROW, overview.MarketCapChange)
+		if overview.MarketCapChange < 0 {
+			change = fmt.Sprintf("%s %.2f%%", DOWN_ARROW, -overview.MarketCapChange)
+		}
+
+		page.OverviewBar.Text = fmt.Sprintf(
+			"Market Cap: %s (%s)  |  24h Volume: %s  |  BTC Dominance: %.2f%%  |  Active Assets: %d  |  Markets: %d",
+			currency.CompactMoney(overview.MarketCap), change,
+			currency.CompactMoney(overview.Volume),
+			overview.BTCDominance,
+			overview.ActiveAssets,
+			overview.Markets,
+		)
+	}
+
 	// Initialise Help Menu
 	help := widgets.NewHelpMenu()
 	help.SelectHelpMenu("ALL")
 
+	// Initialise banner for alerts
+	banner := widgets.NewBanner()
+
+	// Quit key waiting to be pressed again, if quitting must be confirmed
+	quitPrompt := utils.QuitPrompt{}
+
 	// Initiliase Portfolio Table
 	portfolioTable := uw.NewPortfolioPage()
 
+	// Initialise search, the list of coins is fetched on the first search
+	searchWidget := uw.NewSearchPage()
+	coinList := []api.CoinListing{}
+
+	// applyTheme colours the page and its menus with the theme in use
+	applyTheme := func() {
+		page.applyTheme()
+		theme.Current().Tables(help.Table, portfolioTable.Table, currencyWidget.Table, changePercentWidget.Table, searchWidget.Table)
+	}
+	applyTheme()
+
 	// Variables for sorting CoinTable
 	coinSortIdx := -1
 	coinSortAsc := false
 	coinHeader := []string{
 		"Rank",
 		"Symbol",
-		fmt.Sprintf("Price (%s)", currency),
+		fmt.Sprintf("Price (%s)", currency.Label()),
+		"1h %",
+		"24h %",
+		"7d %",
 		fmt.Sprintf("Change %%(%s)", changePercent),
 		"Supply / MaxSupply",
+		"7D",
 	}
 
+	// 7 day prices of coins by CoinGecko ID, drawn as sparklines, and IDs
+	// of the symbols of the coin table
+	sparklines := map[string][]float64{}
+	symbolIDs := map[string]string{}
+
 	// Variables for sorting FavouritesTable
 	favSortIdx := -1
 	favSortAsc := false
 	favHeader := []string{
 		"Symbol",
-		fmt.Sprintf("Price (%s)", currency),
+		fmt.Sprintf("Price (%s)", currency.Label()),
+		"1h %",
+		"24h %",
+		"7d %",
+		"30d %",
+		"7D",
 	}
 
-	previousKey := ""
+	// Symbols of coins marked for comparison, in the order marked
+	compareSymbols := []string{}
+
+	// Screensaver shown once idle, and what was shown before it
+	screensaver := uw.NewScreensaver()
+	idleReturn := ""
 
 	// Pause function to pause sending and receiving of data
 	pause := func() {
 		*sendData = !(*sendData)
+
+		// Favourites are polled again once unpaused
+		if !*sendData {
+			stopFavourites()
+			polledList = utils.Watchlist{}
+		}
 	}
 
 	// UpdateUI to refresh UI
 	updateUI := func() {
 		// Get Terminal Dimensions
 		w, h := ui.TerminalDimensions()
-		page.Grid.SetRect(0, 0, w, h)
+
+		// The screensaver is only drawn again once it moves
+		if utilitySelected == "IDLE" {
+			screensaver.Resize(w, h)
+			if !screensaver.Changed() && !banner.Active() {
+				return
+			}
+		}
+
+		page.resize(w, h)
+
+		// Focus the coin table if favourites were hidden
+		if page.hidden["favourites"] && selectedTable == page.FavouritesTable {
+			selectedTable.ShowCursor = false
+			selectedTable = page.CoinTable
+			selectedTable.ShowCursor = true
+		}
 
 		// Clear UI
 		ui.Clear()
@@ -128,10 +304,41 @@ func DisplayAllCoins(ctx context.Context, dataChannel chan api.AssetData, sendDa
 		case "CHANGE":
 			changePercentWidget.Resize(w, h)
 			ui.Render(changePercentWidget)
+		case "SEARCH":
+			searchWidget.Resize(w, h)
+			ui.Render(searchWidget)
+		case "IDLE":
+			ui.Render(screensaver)
 		default:
 			ui.Render(page.Grid)
+			if !page.hidden["status"] {
+				page.StatusBar.Update(api.GetSource(), "coins", "top coins", "overview", "sparklines")
+				ui.Render(page.StatusBar)
+			}
+		}
+
+		// Flash banner of triggered alerts
+		if banner.Active() {
+			banner.Resize(w, h)
+			ui.Render(banner)
+		}
+	}
+
+	// setCoinTitle shows coins marked to compare, low power mode and quotas
+	// about to run out in the title of the coin table
+	setCoinTitle := func() {
+		page.CoinTable.Title = " Coins "
+		if len(compareSymbols) > 0 {
+			page.CoinTable.Title = fmt.Sprintf(" Coins - compare: %s ", strings.Join(compareSymbols, ", "))
+		}
+		if status := utils.PowerStatus(); status != "" {
+			page.CoinTable.Title += fmt.Sprintf("- %s ", status)
+		}
+		if status := api.QuotaStatus(); status != "" {
+			page.CoinTable.Title += fmt.Sprintf("- %s ", status)
 		}
 	}
+	setCoinTitle()
 
 	// Render Empty UI
 	updateUI()
@@ -140,48 +347,290 @@ func DisplayAllCoins(ctx context.Context, dataChannel chan api.AssetData, sendDa
 	uiEvents := ui.PollEvents()
 
 	// Create ticker to periodically refresh UI
-	t := time.NewTicker(time.Duration(1) * time.Second)
+	t := time.NewTicker(utils.UIRefresh)
 	tick := t.C
 
+	// Reload config and metadata, Eg: after the config file was edited. The
+	// previous config is kept if the file can't be read or is invalid, and
+	// the error is shown in the status bar.
+	reload := func() {
+		utils.Reload()
+		favourites = utils.GetFavourites()
+		portfolioMap = utils.GetPortfolio()
+		rankAlerts = utils.GetRankAlerts()
+		currency = currencyWidget.Get(utils.GetCurrency())
+		updateOverview()
+		updateFooter()
+
+		// Lay out the page again, with profiles as reloaded
+		page.profile = ""
+		updateUI()
+	}
+
+	// openCoin opens the coin page of a coin, returning once it's closed
+	openCoin := func(coinIDs api.CoinID) error {
+		coinGeckoId := coinIDs.CoinGeckoID
+
+		if coinGeckoId != "" {
+			// Get refresh policy of coin
+			policy := api.GetRefreshPolicy(utils.GetPriorities()[coinGeckoId])
+
+			// Get data source of coin
+			src := api.CoinSource(utils.GetCoinSources()[coinGeckoId])
+
+			// Create new errorgroup for coin page
+			eg, coinCtx := errgroup.WithContext(api.WithPriority(ctx, policy.Priority))
+			coinDataChannel := make(chan api.CoinData)
+			coinPriceChannel := make(chan string)
+			// Buffered so the coin page isn't held up till the next poll
+			intervalChannel := make(chan string, 1)
+			quoteChannel := make(chan api.CoinID, 1)
+			yearAgoChannel := make(chan bool, 1)
+			timeframeChannel := make(chan string, 1)
+			bookChannel := make(chan bool, 1)
+			marketsChannel := make(chan bool, 1)
+			newsChannel := make(chan bool, 1)
+			volumeChannel := make(chan bool, 1)
+
+			// Open with the interval last used for the coin
+			if interval, ok := utils.GetCoinIntervals()[coinGeckoId]; ok {
+				intervalChannel <- interval
+			}
+
+			// Clear UI
+			ui.Clear()
+
+			// Serve Coin Price History
+			eg.Go(func() error {
+				err := api.GetCoinHistory(
+					coinCtx,
+					src,
+					coinIDs,
+					policy.HistoryInterval,
+					intervalChannel,
+					quoteChannel,
+					yearAgoChannel,
+					coinDataChannel,
+				)
+				return err
+			})
+
+			// Serve Coin candles once a timeframe is selected
+			eg.Go(func() error {
+				err := api.GetCoinCandles(
+					coinCtx,
+					coinIDs,
+					policy.HistoryInterval,
+					timeframeChannel,
+					coinDataChannel,
+				)
+				return err
+			})
+
+			// Serve Coin order book once shown
+			eg.Go(func() error {
+				err := api.GetCoinOrderBook(
+					coinCtx,
+					coinIDs,
+					policy.HistoryInterval,
+					bookChannel,
+					coinDataChannel,
+				)
+				return err
+			})
+
+			// Serve Coin markets once shown
+			eg.Go(func() error {
+				err := api.GetCoinMarkets(coinCtx, coinIDs, marketsChannel, coinDataChannel)
+				return err
+			})
+
+			// Serve Coin volume breakdown once shown
+			eg.Go(func() error {
+				err := api.GetCoinVolume(coinCtx, coinIDs, volumeChannel, coinDataChannel)
+				return err
+			})
+
+			// Serve Coin news once shown
+			eg.Go(func() error {
+				err := api.GetCoinNews(coinCtx, coinIDs, newsChannel, coinDataChannel)
+				return err
+			})
+
+			// Serve Coin Asset data
+			eg.Go(func() error {
+				err := api.GetCoinDetails(coinCtx, coinGeckoId, policy.DetailsInterval, coinDataChannel)
+				return err
+			})
+
+			// Serve favourie coin prices
+			eg.Go(func() error {
+				err := api.GetFavouritePrices(coinCtx,
+					listed(),
+					coinDataChannel,
+				)
+				return err
+			})
+
+			// Serve Live price of coin if a stream is allocated to it
+			if policy.Live {
+				eg.Go(func() error {
+					api.GetLivePrice(coinCtx, src, coinIDs, coinPriceChannel)
+					// Send NA to indicate price is not being updated
+					go func() {
+						coinPriceChannel <- "NA"
+					}()
+					return nil
+				})
+			}
+
+			utils.SaveMetadata(favourites, currency.ID, portfolioMap)
+
+			// Serve Visuals for coin
+			eg.Go(func() error {
+				err := coin.DisplayCoin(
+					coinCtx,
+					coinIDs,
+					coinIDMap,
+					intervalChannel,
+					quoteChannel,
+					yearAgoChannel,
+					timeframeChannel,
+					bookChannel,
+					marketsChannel,
+					newsChannel,
+					volumeChannel,
+					coinDataChannel,
+					coinPriceChannel,
+					uiEvents,
+				)
+				return err
+			})
+
+			if err := eg.Wait(); err != nil {
+				if err.Error() != "UI Closed" {
+					// Unpause
+					pause()
+					return err
+				}
+			}
+
+			currency = currencyWidget.Get(utils.GetCurrency())
+			updateOverview()
+			updateFooter()
+
+			// Follow a theme changed on the coin page
+			applyTheme()
+		}
+		return nil
+	}
+
+	// Create channels to get suspend and reload signals
+	suspendSignals := utils.SuspendSignals()
+	reloadSignals := utils.ReloadSignals()
+
 	for {
 		select {
 		case <-ctx.Done(): // Context cancelled, exit
 			return ctx.Err()
 
+		case <-suspendSignals: // SIGTSTP sent from outside the UI
+			if err := utils.Suspend(); err != nil {
+				return err
+			}
+			updateUI()
+
+		case <-reloadSignals: // SIGHUP
+			reload()
+
 		case e := <-uiEvents: // keyboard events
+			// Any key closes the screensaver, going back to what was shown
+			if e.ID != "<Resize>" {
+				screensaver.Touch()
+			}
+			if utilitySelected == "IDLE" {
+				if e.ID != "<Resize>" {
+					utilitySelected = idleReturn
+					utils.SetIdle(false)
+				}
+				updateUI()
+				break
+			}
+
 			// Handle Utility Selection, resize and Quit
-			switch e.ID {
-			case "q", "<C-c>":
-				return fmt.Errorf("UI Closed")
+			action := keys.Main.Action(e.ID)
+
+			// Mouse events act on the table under the pointer, which is
+			// selected like with f and F
+			if e.Type == ui.MouseEvent {
+				tables := []*widgets.Table{selectedTable}
+				if utilitySelected == "" {
+					tables = []*widgets.Table{page.CoinTable}
+					if !page.hidden["favourites"] {
+						tables = append(tables, page.FavouritesTable)
+					}
+				}
+
+				var table *widgets.Table
+				table, action = keys.Mouse(e, tables...)
+				if table != nil && table != selectedTable {
+					selectedTable.ShowCursor = false
+					selectedTable = table
+					selectedTable.ShowCursor = true
+				}
+			}
+
+			switch action {
+			case keys.Quit:
+				if quitPrompt.Confirm() {
+					return fmt.Errorf("UI Closed")
+				}
+				banner.Show(fmt.Sprintf("Press %s again to quit", e.ID), utils.QuitWindow)
+				updateUI()
+
+			case keys.Workspace:
+				// Close the page for the next workspace, if any are saved
+				if next := utils.NextWorkspace(utils.ActiveWorkspace); next != "" {
+					return utils.SwitchWorkspace{Name: next}
+				}
 
 			case "<Resize>":
 				updateUI()
 
-			case "p":
+			case keys.Suspend:
+				if err := utils.Suspend(); err != nil {
+					return err
+				}
+				updateUI()
+
+			case keys.Reload:
+				reload()
+
+			case keys.Pause:
 				pause()
 
-			case "?":
+			case keys.Help:
 				selectedTable.ShowCursor = false
 				selectedTable = help.Table
 				selectedTable.ShowCursor = true
 				utilitySelected = "HELP"
 				updateUI()
 
-			case "f":
-				if utilitySelected == "" {
+			case keys.Favourites:
+				if utilitySelected == "" && !page.hidden["favourites"] {
 					selectedTable.ShowCursor = false
 					selectedTable = page.FavouritesTable
 					selectedTable.ShowCursor = true
 				}
 
-			case "F":
+			case keys.Coins:
 				if utilitySelected == "" {
 					selectedTable.ShowCursor = false
 					selectedTable = page.CoinTable
 					selectedTable.ShowCursor = true
 				}
 
-			case "c":
+			case keys.Currency:
 				if utilitySelected == "" {
 					selectedTable.ShowCursor = false
 					selectedTable = currencyWidget.Table
@@ -190,7 +639,7 @@ func DisplayAllCoins(ctx context.Context, dataChannel chan api.AssetData, sendDa
 					utilitySelected = "CURRENCY"
 				}
 
-			case "C":
+			case keys.CurrencyAll:
 				if utilitySelected == "" {
 					selectedTable.ShowCursor = false
 					selectedTable = currencyWidget.Table
@@ -199,7 +648,39 @@ func DisplayAllCoins(ctx context.Context, dataChannel chan api.AssetData, sendDa
 					utilitySelected = "CURRENCY"
 				}
 
-			case "%":
+			case keys.Search:
+				if utilitySelected == "" {
+					query := strings.TrimSpace(widgets.DrawPrompt(uiEvents, " Search coins "))
+
+					if query != "" {
+						searchList := coinList
+						if len(searchList) == 0 {
+							list, err := api.GetCoinList()
+							if err == nil {
+								coinList = list
+								searchList = list
+							} else {
+								// Search the bundled index till the
+								// full list can be fetched
+								searchList = api.BundledListings()
+							}
+						}
+
+						// Rank top coins above others sharing their symbol
+						preferred := map[string]bool{}
+						for _, coinIDs := range coinIDMap {
+							preferred[coinIDs.CoinGeckoID] = true
+						}
+
+						searchWidget.UpdateRows(query, api.SearchCoins(searchList, query, preferred, 20))
+						selectedTable.ShowCursor = false
+						selectedTable = searchWidget.Table
+						selectedTable.ShowCursor = true
+						utilitySelected = "SEARCH"
+					}
+				}
+
+			case keys.Interval:
 				if utilitySelected == "" {
 					selectedTable.ShowCursor = false
 					selectedTable = changePercentWidget.Table
@@ -207,44 +688,40 @@ func DisplayAllCoins(ctx context.Context, dataChannel chan api.AssetData, sendDa
 					utilitySelected = "CHANGE"
 				}
 
-			case "P":
+			case keys.Portfolio:
 				if utilitySelected == "" {
 					selectedTable.ShowCursor = false
 					selectedTable = portfolioTable.Table
 					selectedTable.ShowCursor = true
-					portfolioTable.UpdateRows(portfolioMap, currency, currencyVal)
+					portfolioTable.UpdateRows(portfolioMap, currency)
 					utilitySelected = "PORTFOLIO"
 				}
 
 			// Handle Navigations
-			case "<Escape>":
+			case keys.Back:
 				utilitySelected = ""
 				selectedTable = page.CoinTable
 				selectedTable.ShowCursor = true
 				updateUI()
-			case "j", "<Down>":
+			case keys.Down:
 				selectedTable.ScrollDown()
-			case "k", "<Up>":
+			case keys.Up:
 				selectedTable.ScrollUp()
-			case "<C-d>":
+			case keys.HalfPageDown:
 				selectedTable.ScrollHalfPageDown()
-			case "<C-u>":
+			case keys.HalfPageUp:
 				selectedTable.ScrollHalfPageUp()
-			case "<C-f>":
+			case keys.PageDown:
 				selectedTable.ScrollPageDown()
-			case "<C-b>":
+			case keys.PageUp:
 				selectedTable.ScrollPageUp()
-			case "g":
-				if previousKey == "g" {
-					selectedTable.ScrollTop()
-				}
-			case "<Home>":
+			case keys.Top:
 				selectedTable.ScrollTop()
-			case "G", "<End>":
+			case keys.Bottom:
 				selectedTable.ScrollBottom()
 
 			// Handle Actions
-			case "e":
+			case keys.Edit:
 				switch utilitySelected {
 				case "PORTFOLIO":
 					id := ""
@@ -271,9 +748,13 @@ func DisplayAllCoins(ctx context.Context, dataChannel chan api.AssetData, sendDa
 								delete(portfolioMap, id)
 							}
 						}
+
+						// Persist the edit straight away, so it isn't lost if
+						// cryptgo is killed before the page is closed
+						utils.SaveMetadata(favourites, currency.ID, portfolioMap)
 					}
 
-					portfolioTable.UpdateRows(portfolioMap, currency, currencyVal)
+					portfolioTable.UpdateRows(portfolioMap, currency)
 
 				case "":
 					id := ""
@@ -308,10 +789,14 @@ func DisplayAllCoins(ctx context.Context, dataChannel chan api.AssetData, sendDa
 								delete(portfolioMap, id)
 							}
 						}
+
+						// Persist the edit straight away, so it isn't lost if
+						// cryptgo is killed before the page is closed
+						utils.SaveMetadata(favourites, currency.ID, portfolioMap)
 					}
 				}
 
-			case "<Enter>":
+			case keys.Select:
 				switch utilitySelected {
 				case "CURRENCY":
 
@@ -319,13 +804,40 @@ func DisplayAllCoins(ctx context.Context, dataChannel chan api.AssetData, sendDa
 					if currencyWidget.SelectedRow < len(currencyWidget.Rows) {
 						row := currencyWidget.Rows[currencyWidget.SelectedRow]
 
-						// Get currency and rate
-						currencyID = row[0]
-						currencyID, currency, currencyVal = currencyWidget.Get(currencyID)
+						// Get currency
+						currency = currencyWidget.Get(row[0])
 
 						// Update currency fields
-						coinHeader[2] = fmt.Sprintf("Price (%s)", currency)
-						favHeader[1] = fmt.Sprintf("Price (%s)", currency)
+						coinHeader[2] = fmt.Sprintf("Price (%s)", currency.Label())
+						favHeader[1] = fmt.Sprintf("Price (%s)", currency.Label())
+						updateOverview()
+						updateFooter()
+
+						// Persist currency
+						utils.SaveMetadata(favourites, currency.ID, portfolioMap)
+					}
+					utilitySelected = ""
+
+				case "SEARCH":
+					if searchWidget.SelectedRow < len(searchWidget.Results) {
+						result := searchWidget.Results[searchWidget.SelectedRow]
+
+						// Use CoinCap ID of the coin if known
+						coinIDs := api.CoinID{CoinGeckoID: result.ID, Symbol: result.Symbol}
+						if known := coinIDMap[result.Symbol]; known.CoinGeckoID == result.ID {
+							coinIDs = known
+						}
+
+						// pause UI and data send
+						pause()
+
+						if err := openCoin(coinIDs); err != nil {
+							return err
+						}
+
+						// unpause data send and receive
+						pause()
+						updateUI()
 					}
 					utilitySelected = ""
 
@@ -335,7 +847,7 @@ func DisplayAllCoins(ctx context.Context, dataChannel chan api.AssetData, sendDa
 
 						changePercent = uw.DurationMap[row[0]]
 
-						coinHeader[3] = fmt.Sprintf("Change %%(%s)", changePercent)
+						coinHeader[6] = fmt.Sprintf("Change %%(%s)", changePercent)
 					}
 					utilitySelected = ""
 
@@ -357,86 +869,8 @@ func DisplayAllCoins(ctx context.Context, dataChannel chan api.AssetData, sendDa
 							symbol = row[0]
 						}
 					}
-					coinIDs := coinIDMap[symbol]
-
-					coinCapId := coinIDs.CoinCapID
-					coinGeckoId := coinIDs.CoinGeckoID
-
-					if coinGeckoId != "" {
-						// Create new errorgroup for coin page
-						eg, coinCtx := errgroup.WithContext(ctx)
-						coinDataChannel := make(chan api.CoinData)
-						coinPriceChannel := make(chan string)
-						intervalChannel := make(chan string)
-
-						// Clear UI
-						ui.Clear()
-
-						// Serve Coin Price History
-						eg.Go(func() error {
-							err := api.GetCoinHistory(
-								coinCtx,
-								coinGeckoId,
-								intervalChannel,
-								coinDataChannel,
-							)
-							return err
-						})
-
-						// Serve Coin Asset data
-						eg.Go(func() error {
-							err := api.GetCoinDetails(coinCtx, coinGeckoId, coinDataChannel)
-							return err
-						})
-
-						// Serve favourie coin prices
-						eg.Go(func() error {
-							err := api.GetFavouritePrices(coinCtx,
-								favourites,
-								coinDataChannel,
-							)
-							return err
-						})
-
-						// Serve Live price of coin
-						if coinCapId != "" {
-							eg.Go(func() error {
-								api.GetLivePrice(coinCtx, coinCapId, coinPriceChannel)
-								// Send NA to indicate price is not being updated
-								go func() {
-									coinPriceChannel <- "NA"
-								}()
-								return nil
-							})
-						}
-
-						utils.SaveMetadata(favourites, currencyID, portfolioMap)
-
-						// Serve Visuals for coin
-						eg.Go(func() error {
-							err := coin.DisplayCoin(
-								coinCtx,
-								coinGeckoId,
-								coinIDMap,
-								intervalChannel,
-								coinDataChannel,
-								coinPriceChannel,
-								uiEvents,
-							)
-							return err
-						})
-
-						if err := eg.Wait(); err != nil {
-							if err.Error() != "UI Closed" {
-								// Unpause
-								pause()
-								return err
-							}
-						}
-
-						currencyID = utils.GetCurrency()
-						currencyID, currency, currencyVal = currencyWidget.Get(currencyID)
-
+					if err := openCoin(coinIDMap[symbol]); err != nil {
+						return err
 					}
 
 					// unpause data send and receive
@@ -451,7 +885,7 @@ func DisplayAllCoins(ctx context.Context, dataChannel chan api.AssetData, sendDa
 					selectedTable.ShowCursor = true
 				}
 
-			case "s":
+			case keys.Favourite:
 				if utilitySelected == "" {
 					id := ""
 					symbol := ""
@@ -471,9 +905,172 @@ func DisplayAllCoins(ctx context.Context, dataChannel chan api.AssetData, sendDa
 					coinIDs := coinIDMap[symbol]
 					id = coinIDs.CoinGeckoID
 					favourites[id] = true
+					utils.SaveMetadata(favourites, currency.ID, portfolioMap)
+				}
+
+			case keys.ClearCache:
+				if utilitySelected == "" {
+					// Drop cached responses, so data is fetched afresh
+					if err := api.ClearCache(); err == nil {
+						banner.Show("Cache cleared, refreshing", time.Duration(3)*time.Second)
+					} else {
+						banner.Show("Unable to clear cache: "+err.Error(), time.Duration(10)*time.Second)
+					}
+				}
+
+			case keys.Report:
+				if utilitySelected == "" {
+					// Save raw responses backing the page to reproduce bugs
+					path, err := api.SaveReport("all coins", opened)
+					if err == nil {
+						banner.Show("Report saved to "+path, time.Duration(10)*time.Second)
+					} else {
+						banner.Show("Unable to save report: "+err.Error(), time.Duration(10)*time.Second)
+					}
+				}
+
+			case keys.Theme:
+				if utilitySelected == "" {
+					// Cycle through themes for the rest of the session
+					name := theme.Next()
+					applyTheme()
+					banner.Show("Theme: "+name, time.Duration(3)*time.Second)
+				}
+
+			case keys.LowPower:
+				if utilitySelected == "" {
+					// Cycle power mode, Eg: to stay live on battery
+					mode := utils.NextPowerMode(utils.GetPowerMode())
+					utils.SetPowerMode(mode)
+					setCoinTitle()
+					banner.Show("Power mode: "+mode, time.Duration(3)*time.Second)
+				}
+
+			case keys.Watchlist:
+				if utilitySelected == "" {
+					// Cycle the favourites table through watchlists,
+					// rows are updated with the next coin data
+					names := utils.WatchlistNames()
+					next := utils.FavouritesList
+					for i, name := range names {
+						if name == activeList {
+							next = names[(i+1)%len(names)]
+							break
+						}
+					}
+					activeList = next
+					page.FavouritesTable.Title = fmt.Sprintf(" %s ", activeList)
+					page.FavouritesTable.SelectedRow = 0
 				}
 
-			case "S":
+			case keys.Mark:
+				if utilitySelected == "" {
+					symbol := ""
+
+					// Get symbol
+					if selectedTable == page.CoinTable {
+						if page.CoinTable.SelectedRow < len(page.CoinTable.Rows) {
+							row := page.CoinTable.Rows[page.CoinTable.SelectedRow]
+							symbol = row[1]
+						}
+					} else {
+						if page.FavouritesTable.SelectedRow < len(page.FavouritesTable.Rows) {
+							row := page.FavouritesTable.Rows[page.FavouritesTable.SelectedRow]
+							symbol = row[0]
+						}
+					}
+
+					// Mark or unmark coin for comparison
+					marked := false
+					for i, s := range compareSymbols {
+						if s == symbol {
+							compareSymbols = append(compareSymbols[:i], compareSymbols[i+1:]...)
+							marked = true
+							break
+						}
+					}
+					if !marked && symbol != "" && len(compareSymbols) < api.MaxCompareCoins {
+						compareSymbols = append(compareSymbols, symbol)
+					}
+
+					// Show marked coins in title
+					setCoinTitle()
+				}
+
+			case keys.Compare:
+				if utilitySelected == "" && len(compareSymbols) >= api.MinCompareCoins {
+					// pause UI and data send
+					pause()
+
+					ids := []api.CoinID{}
+					for _, symbol := range compareSymbols {
+						ids = append(ids, coinIDMap[symbol])
+					}
+
+					// Create new errorgroup for compare page
+					eg, compareCtx := errgroup.WithContext(ctx)
+					compareDataChannel := make(chan api.CompareData)
+					// Buffered so the compare page isn't held up till the next poll
+					intervalChannel := make(chan string, 1)
+					intervalChannel <- api.DefaultInterval
+
+					// Clear UI
+					ui.Clear()
+
+					// Serve histories and stats of coins
+					eg.Go(func() error {
+						policy := api.GetRefreshPolicy(api.PriorityNormal)
+						return api.GetCompareData(compareCtx, api.GetSource(), ids, policy.HistoryInterval, intervalChannel, compareDataChannel)
+					})
+
+					// Serve Visuals for comparison
+					eg.Go(func() error {
+						return compare.DisplayCompare(compareCtx, api.DefaultInterval, intervalChannel, compareDataChannel, uiEvents)
+					})
+
+					if err := eg.Wait(); err != nil {
+						if err.Error() != "UI Closed" {
+							// Unpause
+							pause()
+							return err
+						}
+					}
+
+					// unpause data send and receive
+					pause()
+					updateUI()
+				}
+
+			case keys.RankAlert:
+				if utilitySelected == "" && selectedTable == page.FavouritesTable {
+					symbol := ""
+
+					// Get symbol
+					if page.FavouritesTable.SelectedRow < len(page.FavouritesTable.Rows) {
+						row := page.FavouritesTable.Rows[page.FavouritesTable.SelectedRow]
+						symbol = row[0]
+					}
+
+					id := coinIDMap[symbol].CoinGeckoID
+
+					if id != "" {
+						// Get N to alert on entering/leaving top N
+						inputStr := widgets.DrawPrompt(uiEvents, fmt.Sprintf(" Top N alert for %s ", symbol))
+						n, err := strconv.Atoi(strings.TrimSpace(inputStr))
+
+						// Update alert
+						if err == nil {
+							if n > 0 {
+								rankAlerts[id] = n
+							} else {
+								delete(rankAlerts, id)
+							}
+							utils.SaveRankAlerts(rankAlerts)
+						}
+					}
+				}
+
+			case keys.Unfavourite:
 				if utilitySelected == "" {
 					id := ""
 					symbol := ""
@@ -496,6 +1093,7 @@ func DisplayAllCoins(ctx context.Context, dataChannel chan api.AssetData, sendDa
 					id = coinIDs.CoinGeckoID
 
 					delete(favourites, id)
+					utils.SaveMetadata(favourites, currency.ID, portfolioMap)
 				}
 			}
 
@@ -503,55 +1101,61 @@ func DisplayAllCoins(ctx context.Context, dataChannel chan api.AssetData, sendDa
 				// Handle Sorting of tables
 				switch selectedTable {
 				case page.CoinTable:
-					switch e.ID {
+					switch action {
 					// Sort Ascending
-					case "1", "2", "3", "4":
-						idx, _ := strconv.Atoi(e.ID)
+					case "1", "2", "3", "4", "5", "6", "7":
+						idx, _ := strconv.Atoi(action)
 						coinSortIdx = idx - 1
 						page.CoinTable.Header = append([]string{}, coinHeader...)
 						page.CoinTable.Header[coinSortIdx] = coinHeader[coinSortIdx] + " " + UP_ARROW
 						coinSortAsc = true
-						utils.SortData(page.CoinTable.Rows, coinSortIdx, coinSortAsc, "COINS")
+						utils.SortData(page.CoinTable.Rows, coinSortIdx, coinSortAsc, utils.CoinsLayout)
 
 					// Sort Descending
-					case "<F1>", "<F2>", "<F3>", "<F4>":
+					case "<F1>", "<F2>", "<F3>", "<F4>", "<F5>", "<F6>", "<F7>":
 						page.CoinTable.Header = append([]string{}, coinHeader...)
-						idx, _ := strconv.Atoi(e.ID[2:3])
+						idx, _ := strconv.Atoi(action[2:3])
 						coinSortIdx = idx - 1
 						page.CoinTable.Header[coinSortIdx] = coinHeader[coinSortIdx] + " " + DOWN_ARROW
 						coinSortAsc = false
-						utils.SortData(page.CoinTable.Rows, coinSortIdx, coinSortAsc, "COINS")
+						utils.SortData(page.CoinTable.Rows, coinSortIdx, coinSortAsc, utils.CoinsLayout)
 					}
 
 				case page.FavouritesTable:
-					switch e.ID {
+					switch action {
 					// Sort Ascending
-					case "1", "2":
-						idx, _ := strconv.Atoi(e.ID)
+					case "1", "2", "3", "4", "5", "6":
+						idx, _ := strconv.Atoi(action)
 						favSortIdx = idx - 1
 						page.FavouritesTable.Header = append([]string{}, favHeader...)
 						page.FavouritesTable.Header[favSortIdx] = favHeader[favSortIdx] + " " + UP_ARROW
 						favSortAsc = true
-						utils.SortData(page.FavouritesTable.Rows, favSortIdx, favSortAsc, "FAVOURITES")
+						utils.SortData(page.FavouritesTable.Rows, favSortIdx, favSortAsc, utils.FavouritesLayout)
 
 					// Sort Descending
-					case "<F1>", "<F2>":
+					case "<F1>", "<F2>", "<F3>", "<F4>", "<F5>", "<F6>":
 						page.FavouritesTable.Header = append([]string{}, favHeader...)
-						idx, _ := strconv.Atoi(e.ID[2:3])
+						idx, _ := strconv.Atoi(action[2:3])
 						favSortIdx = idx - 1
 						page.FavouritesTable.Header[favSortIdx] = favHeader[favSortIdx] + " " + DOWN_ARROW
 						favSortAsc = false
-						utils.SortData(page.FavouritesTable.Rows, favSortIdx, favSortAsc, "FAVOURITES")
+						utils.SortData(page.FavouritesTable.Rows, favSortIdx, favSortAsc, utils.FavouritesLayout)
 					}
 				}
 			}
 
+			// Put off the screensaver till idle again after pages opened
+			// from here are closed
+			screensaver.Touch()
 			updateUI()
-			if previousKey == "g" {
-				previousKey = ""
-			} else {
-				previousKey = e.ID
+
+		case data := <-favouritesChannel:
+			// Ignore stats of a list since replaced
+			if data.Watchlist != polledList.Name {
+				break
 			}
+			favouriteStats = data.FavouriteStats
+			updateFooter()
 
 		case data := <-dataChannel:
 			if data.IsTopCoinData {
@@ -563,31 +1167,91 @@ func DisplayAllCoins(ctx context.Context, dataChannel chan api.AssetData, sendDa
 					// Update value graphs
 					page.TopCoinGraphs[i].Data["Value"] = v
 
-					// Set value, max & min values
-					maxValue := data.MaxPrices[i] / currencyVal
-					minValue := data.MinPrices[i] / currencyVal
-					// Current value is last point (cleaned) in graph + minimum value
-					value := (v[len(v)-1] + data.MinPrices[i]) / currencyVal
+					// Set value, max & min values. Current value is last
+					// point (cleaned) in graph + minimum value
+					page.TopCoinGraphs[i].Labels["Value"] = currency.Money(v[len(v)-1] + data.MinPrices[i])
+					page.TopCoinGraphs[i].Labels["Max"] = currency.Money(data.MaxPrices[i])
+					page.TopCoinGraphs[i].Labels["Min"] = currency.Money(data.MinPrices[i])
+				}
+			} else if data.IsDominanceData {
+				// Update BTC dominance history
+				v := data.DominanceHistory
+				page.DominanceGraph.Title = fmt.Sprintf(" BTC Dominance (since %s) ", data.DominanceSince.Format("02 Jan 15:04"))
+				page.DominanceGraph.Data["Value"] = v
+				page.DominanceGraph.Labels["Value"] = fmt.Sprintf("%.2f%%", v[len(v)-1]+data.MinDominance)
+				page.DominanceGraph.Labels["Max"] = fmt.Sprintf("%.2f%%", data.MaxDominance)
+				page.DominanceGraph.Labels["Min"] = fmt.Sprintf("%.2f%%", data.MinDominance)
+			} else if data.IsOverviewData {
+				// Update global market overview
+				overview = &data.Overview
+				updateOverview()
+			} else if data.IsAltseasonData {
+				// Update Altseason index and its history
+				season := "Neutral"
+				switch {
+				case data.AltseasonIndex >= 75:
+					season = "Altcoin Season"
+				case data.AltseasonIndex <= 25:
+					season = "Bitcoin Season"
+				}
+
+				// Show the most recent history which fits in the graph
+				history := data.AltseasonHistory
+				if width := page.AltseasonGraph.Inner.Dx(); width > 0 && len(history) > width {
+					history = history[len(history)-width:]
+				}
 
-					page.TopCoinGraphs[i].Labels["Value"] = fmt.Sprintf("%.2f %s", value, currency)
-					page.TopCoinGraphs[i].Labels["Max"] = fmt.Sprintf("%.2f %s", maxValue, currency)
-					page.TopCoinGraphs[i].Labels["Min"] = fmt.Sprintf("%.2f %s", minValue, currency)
+				page.AltseasonGraph.Sparklines[0].Data = history
+				page.AltseasonGraph.Sparklines[0].Title = fmt.Sprintf("%.0f / 100 - %s", data.AltseasonIndex, season)
+			} else if data.IsStaleData {
+				// Warn about favourites and holdings which no longer return data
+				warning := uw.StaleWarning(data.StaleCoins)
+				if warning != "" {
+					page.FavouritesTable.Title = fmt.Sprintf(" %s | %s ", activeList, warning)
+				} else if staleWarning != "" {
+					page.FavouritesTable.Title = fmt.Sprintf(" %s ", activeList)
+				}
+				staleWarning = warning
+			} else if data.IsWatchlistData {
+				// Rows are updated with the next coin data
+				watchlist = data.Watchlist
+			} else if data.IsSparklineData {
+				// Draw sparklines of coins fetched so far
+				for id, prices := range data.Sparklines {
+					sparklines[id] = prices
+				}
+				for _, row := range page.CoinTable.Rows {
+					if prices, ok := data.Sparklines[symbolIDs[row[1]]]; ok {
+						row[8] = utils.Sparkline(prices, sparklineWidth)
+					}
+				}
+				for _, row := range page.FavouritesTable.Rows {
+					if prices, ok := data.Sparklines[symbolIDs[row[0]]]; ok {
+						row[6] = utils.Sparkline(prices, favouriteSparklineWidth)
+					}
 				}
 			} else {
 				rows := [][]string{}
 				favouritesData := [][]string{}
 
+				shown := listed().IDs
+
 				// Update currency headers
-				page.CoinTable.Header[2] = fmt.Sprintf("Price (%s)", currency)
-				page.CoinTable.Header[3] = fmt.Sprintf("Change %%(%s)", changePercent)
-				page.FavouritesTable.Header[1] = fmt.Sprintf("Price (%s)", currency)
+				page.CoinTable.Header[2] = fmt.Sprintf("Price (%s)", currency.Label())
+				page.CoinTable.Header[6] = fmt.Sprintf("Change %%(%s)", changePercent)
+				page.FavouritesTable.Header[1] = fmt.Sprintf("Price (%s)", currency.Label())
 
 				// Iterate over coin assets
 				for _, val := range data.AllCoinData {
 					// Get coin price
-					price := fmt.Sprintf("%.2f", val.CurrentPrice/currencyVal)
+					price := currency.Format(val.CurrentPrice)
 
-					// Get change %
+					// Get change % over each window, and of the selected
+					// duration, falling back to the 24 hour change
+					changes := map[string]string{}
+					for _, duration := range []string{"1h", "24h", "7d", "30d"} {
+						changes[duration] = formatChange(val, duration)
+					}
 					change := "NA"
 					percentageChange := api.GetPercentageChangeForDuration(val, changePercent)
 					if percentageChange < 0 {
@@ -614,21 +1278,32 @@ func DisplayAllCoins(ctx context.Context, dataChannel chan api.AssetData, sendDa
 					}
 
 					rank := fmt.Sprintf("%d", val.MarketCapRank)
+					symbol := strings.ToUpper(val.Symbol)
+					symbolIDs[symbol] = val.ID
 
 					// Aggregate data
 					rows = append(rows, []string{
 						rank,
-						strings.ToUpper(val.Symbol),
+						symbol,
 						price,
+						changes["1h"],
+						changes["24h"],
+						changes["7d"],
 						change,
 						supplyData,
+						utils.Sparkline(sparklines[val.ID], sparklineWidth),
 					})
 
 					// Aggregate favourite data
-					if _, ok := favourites[val.ID]; ok {
+					if shown[val.ID] {
 						favouritesData = append(favouritesData, []string{
 							strings.ToUpper(val.Symbol),
 							price,
+							changes["1h"],
+							changes["24h"],
+							changes["7d"],
+							changes["30d"],
+							utils.Sparkline(sparklines[val.ID], favouriteSparklineWidth),
 						})
 					}
 				}
@@ -636,9 +1311,78 @@ func DisplayAllCoins(ctx context.Context, dataChannel chan api.AssetData, sendDa
 				page.CoinTable.Rows = rows
 				page.FavouritesTable.Rows = favouritesData
 
+				// Cycle favourites' prices and 24 hour changes on the
+				// screensaver
+				screensaver.Items = []string{}
+				for _, row := range favouritesData {
+					screensaver.Items = append(screensaver.Items, strings.Join([]string{row[0], row[1], row[3]}, "  "))
+				}
+
+				// Fetch sparklines of the top coins and favourites,
+				// replacing coins not yet picked up
+				ids := []api.CoinID{}
+				for i, val := range data.AllCoinData {
+					if i >= sparklineCoins && !shown[val.ID] {
+						continue
+					}
+					coinIDs := coinIDMap[strings.ToUpper(val.Symbol)]
+					if coinIDs.CoinGeckoID != val.ID {
+						coinIDs = api.CoinID{CoinGeckoID: val.ID, Symbol: val.Symbol}
+					}
+					ids = append(ids, coinIDs)
+				}
+				select {
+				case <-sparklineChannel:
+				default:
+				}
+				sparklineChannel <- ids
+
+				// Check price alerts
+				messages := []string{}
+				triggered := []alerts.Triggered{}
+				for _, val := range data.AllCoinData {
+					for _, t := range alerts.Check(val.ID, val.Symbol, val.CurrentPrice, val.PriceChangePercentage24h) {
+						messages = append(messages, t.Message())
+						triggered = append(triggered, t)
+					}
+				}
+				if len(messages) > 0 {
+					message := strings.Join(messages, " | ")
+					banner.Show(message, time.Duration(10)*time.Second)
+					go alerts.Notify("cryptgo", message, triggered...)
+				}
+
+				// Check rank alerts of favourites
+				watched := map[string]int{}
+				for id, n := range rankAlerts {
+					if favourites[id] {
+						watched[id] = n
+					}
+				}
+				for _, alert := range rankTracker.Check(data.AllCoinData, watched) {
+					movement := "left"
+					if alert.Entered {
+						movement = "entered"
+					}
+					page.FavouritesTable.Title = fmt.Sprintf(" %s | %s %s %s top %d ",
+						activeList, time.Now().Format("15:04"), alert.Symbol, movement, alert.Threshold)
+				}
+
+				// Update market breadth gauge
+				breadth := api.GetMarketBreadth(data.AllCoinData, 100)
+				if breadth.Total > 0 {
+					page.BreadthGauge.Percent = breadth.Advancing * 100 / breadth.Total
+					page.BreadthGauge.Label = fmt.Sprintf("%d%% up (%d%s %d%s)",
+						page.BreadthGauge.Percent, breadth.Advancing, UP_ARROW, breadth.Declining, DOWN_ARROW)
+					page.BreadthGauge.BarColor = theme.Current().Up
+					if page.BreadthGauge.Percent < 50 {
+						page.BreadthGauge.BarColor = theme.Current().Down
+					}
+				}
+
 				// Sort CoinTable data
 				if coinSortIdx != -1 {
-					utils.SortData(page.CoinTable.Rows, coinSortIdx, coinSortAsc, "COINS")
+					utils.SortData(page.CoinTable.Rows, coinSortIdx, coinSortAsc, utils.CoinsLayout)
 
 					if coinSortAsc {
 						page.CoinTable.Header[coinSortIdx] = coinHeader[coinSortIdx] + " " + UP_ARROW
@@ -649,7 +1393,7 @@ func DisplayAllCoins(ctx context.Context, dataChannel chan api.AssetData, sendDa
 
 				// Sort FavouritesTable Data
 				if favSortIdx != -1 {
-					utils.SortData(page.FavouritesTable.Rows, favSortIdx, favSortAsc, "FAVOURITES")
+					utils.SortData(page.FavouritesTable.Rows, favSortIdx, favSortAsc, utils.FavouritesLayout)
 
 					if favSortAsc {
 						page.FavouritesTable.Header[favSortIdx] = favHeader[favSortIdx] + " " + UP_ARROW
@@ -660,6 +1404,18 @@ func DisplayAllCoins(ctx context.Context, dataChannel chan api.AssetData, sendDa
 			}
 
 		case <-tick: // Refresh UI
+			// Poll the list shown once favourites or watchlists changed
+			if *sendData {
+				pollFavourites()
+			}
+
+			// Show the screensaver once idle
+			if screensaver.Due() && utilitySelected != "IDLE" {
+				idleReturn = utilitySelected
+				utilitySelected = "IDLE"
+				utils.SetIdle(true)
+			}
+			setCoinTitle()
 			if *sendData {
 				updateUI()
 			}
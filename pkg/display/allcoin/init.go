@@ -17,8 +17,24 @@ limitations under the License.
 package allcoin
 
 import (
+	"github.com/Gituser143/cryptgo/pkg/display/layout"
+	"github.com/Gituser143/cryptgo/pkg/display/theme"
+	"github.com/Gituser143/cryptgo/pkg/display/utilitywidgets"
 	"github.com/Gituser143/cryptgo/pkg/widgets"
 	ui "github.com/gizak/termui/v3"
+	tw "github.com/gizak/termui/v3/widgets"
+)
+
+const (
+	// sparklineWidth is the number of characters of sparklines of the coin
+	// table
+	sparklineWidth = 12
+	// sparklineCoins is the number of top coins sparklines are fetched for,
+	// along with favourites
+	sparklineCoins = 100
+	// favouriteSparklineWidth is the number of characters of sparklines of
+	// the favourites table
+	favouriteSparklineWidth = 8
 )
 
 // allCoinPage holds UI items for the home page
@@ -27,10 +43,18 @@ type allCoinPage struct {
 	CoinTable       *widgets.Table
 	TopCoinGraphs   []*widgets.LineGraph
 	FavouritesTable *widgets.Table
+	BreadthGauge    *tw.Gauge
+	AltseasonGraph  *tw.SparklineGroup
+	DominanceGraph  *widgets.LineGraph
+	OverviewBar     *tw.Paragraph
+	StatusBar       *utilitywidgets.StatusBar
+
+	profile string          // Layout profile the grid is set for
+	hidden  map[string]bool // Widgets hidden by the profile
 }
 
 // newallCoinPage creates, initialises and returns a pointer to an instance of allCoinPage
-func newAllCoinPage() *allCoinPage {
+func newAllCoinPage(w, h int) *allCoinPage {
 	coinGraphs := []*widgets.LineGraph{}
 	for i := 0; i < 3; i++ {
 		coinGraphs = append(coinGraphs, widgets.NewLineGraph())
@@ -41,74 +65,162 @@ func newAllCoinPage() *allCoinPage {
 		CoinTable:       widgets.NewTable(),
 		TopCoinGraphs:   coinGraphs,
 		FavouritesTable: widgets.NewTable(),
+		BreadthGauge:    tw.NewGauge(),
+		AltseasonGraph:  tw.NewSparklineGroup(tw.NewSparkline()),
+		DominanceGraph:  widgets.NewLineGraph(),
+		OverviewBar:     tw.NewParagraph(),
+		StatusBar:       utilitywidgets.NewStatusBar(),
 	}
 
-	page.init()
+	page.init(w, h)
 
 	return page
 }
 
 // init initialises the widgets of an allCoinPage
-func (page *allCoinPage) init() {
+func (page *allCoinPage) init(w, h int) {
 	// Initialise CoinTable
 	page.CoinTable.Title = " Coins "
-	page.CoinTable.BorderStyle.Fg = ui.ColorCyan
-	page.CoinTable.TitleStyle.Fg = ui.ColorClear
-	page.CoinTable.Header = []string{"Rank", "Symbol", "Price", "Change %", "Supply / MaxSupply"}
+	page.CoinTable.Header = []string{"Rank", "Symbol", "Price", "1h %", "24h %", "7d %", "Change %", "Supply / MaxSupply", "7D"}
 	page.CoinTable.ColResizer = func() {
 		x := page.CoinTable.Inner.Dx()
 		page.CoinTable.ColWidths = []int{
-			ui.MaxInt(8, x/5),
-			ui.MaxInt(8, x/5),
-			ui.MaxInt(15, x/5),
-			ui.MaxInt(5, x/5),
-			ui.MaxInt(20, x/5),
+			ui.MaxInt(5, x/14),
+			ui.MaxInt(7, x/12),
+			ui.MaxInt(12, x/7),
+			ui.MaxInt(8, x/12),
+			ui.MaxInt(8, x/12),
+			ui.MaxInt(8, x/12),
+			ui.MaxInt(12, x/9),
+			ui.MaxInt(18, x/6),
+			ui.MaxInt(sparklineWidth, x/7),
 		}
 	}
 	page.CoinTable.ShowCursor = true
-	page.CoinTable.CursorColor = ui.ColorCyan
-	page.CoinTable.ChangeCol[3] = true
+	for i := 3; i <= 6; i++ {
+		page.CoinTable.ChangeCol[i] = true
+	}
 
 	// Initialise Favourites table
 	page.FavouritesTable.Title = " Favourites "
-	page.FavouritesTable.BorderStyle.Fg = ui.ColorCyan
-	page.FavouritesTable.TitleStyle.Fg = ui.ColorClear
-	page.FavouritesTable.Header = []string{"Symbol", "Price"}
+	page.FavouritesTable.Header = []string{"Symbol", "Price", "1h %", "24h %", "7d %", "30d %", "7D"}
 	page.FavouritesTable.ColResizer = func() {
 		x := page.FavouritesTable.Inner.Dx()
 		page.FavouritesTable.ColWidths = []int{
-			4 * x / 10,
-			6 * x / 10,
+			ui.MaxInt(5, 3*x/24),
+			ui.MaxInt(8, 5*x/24),
+			ui.MaxInt(7, 3*x/24),
+			ui.MaxInt(7, 3*x/24),
+			ui.MaxInt(7, 3*x/24),
+			ui.MaxInt(7, 3*x/24),
+			ui.MaxInt(favouriteSparklineWidth, 4*x/24),
 		}
 	}
-	page.FavouritesTable.CursorColor = ui.ColorCyan
+	for i := 2; i <= 5; i++ {
+		page.FavouritesTable.ChangeCol[i] = true
+	}
+
+	// Initialise Market Breadth Gauge
+	page.BreadthGauge.Title = " Market Breadth (Top 100, 24H) "
+	page.BreadthGauge.Label = "NA"
+
+	// Initialise Altseason Graph
+	page.AltseasonGraph.Title = " Altseason Index "
+	page.AltseasonGraph.Sparklines[0].Title = "Fetching history..."
+	page.AltseasonGraph.Sparklines[0].MaxVal = 100
 
 	// Initialise Top Coin Graphs
 	for i := 0; i < 3; i++ {
-		page.TopCoinGraphs[i].TitleStyle = ui.NewStyle(ui.ColorClear)
 		page.TopCoinGraphs[i].HorizontalScale = 1
-		page.TopCoinGraphs[i].LineColors["Max"] = ui.ColorGreen
-		page.TopCoinGraphs[i].LineColors["Min"] = ui.ColorRed
-		page.TopCoinGraphs[i].LineColors["Value"] = ui.ColorBlue
-		page.TopCoinGraphs[i].BorderStyle.Fg = ui.ColorCyan
 		page.TopCoinGraphs[i].Data["Max"] = []float64{}
 		page.TopCoinGraphs[i].Data["Min"] = []float64{}
 	}
 
+	// Initialise Dominance Graph
+	page.DominanceGraph.Title = " BTC Dominance "
+	page.DominanceGraph.HorizontalScale = 1
+	page.DominanceGraph.Data["Max"] = []float64{}
+	page.DominanceGraph.Data["Min"] = []float64{}
+
+	// Initialise Overview Bar
+	page.OverviewBar.Title = " Market Overview "
+	page.OverviewBar.Text = "Fetching..."
+
 	// Set Grid layout
-	w, h := ui.TerminalDimensions()
-	page.Grid.Set(
-		ui.NewRow(0.33,
-			ui.NewCol(0.33, page.TopCoinGraphs[0]),
-			ui.NewCol(0.33, page.TopCoinGraphs[1]),
-			ui.NewCol(0.34, page.TopCoinGraphs[2]),
-		),
-		ui.NewRow(0.67,
-			ui.NewCol(0.33, page.FavouritesTable),
-			ui.NewCol(0.67, page.CoinTable),
-		),
-	)
-
-	page.Grid.SetRect(0, 0, w, h)
+	page.resize(w, h)
+
+	page.applyTheme()
+}
+
+// resize fits the grid and status bar to a terminal w columns wide and h
+// rows tall, laying out the page again if the terminal's layout profile
+// changed
+func (page *allCoinPage) resize(w, h int) {
+	if profile := layout.Profile(w, h); profile != page.profile {
+		page.profile = profile
+		page.hidden = layout.Hidden(profile, "main")
+		page.layout()
+	}
+
+	// Status bar takes the last row, grid the rest
+	bottom := h
+	if !page.hidden["status"] {
+		bottom = h - 1
+	}
+	page.StatusBar.SetRect(0, h-1, w, h)
+	page.Grid.SetRect(0, 0, w, bottom)
+}
+
+// layout sets a new grid of the widgets of an allCoinPage, leaving out
+// hidden widgets
+func (page *allCoinPage) layout() {
+	top := layout.Split(ui.NewCol, []layout.Item{
+		{Name: "top_coins", Ratio: 0.25, Entry: page.TopCoinGraphs[0]},
+		{Name: "top_coins", Ratio: 0.25, Entry: page.TopCoinGraphs[1]},
+		{Name: "top_coins", Ratio: 0.25, Entry: page.TopCoinGraphs[2]},
+		{Name: "dominance", Ratio: 0.25, Entry: page.DominanceGraph},
+	}, page.hidden)
+
+	side := layout.Split(ui.NewRow, []layout.Item{
+		{Name: "favourites", Ratio: 0.6, Entry: page.FavouritesTable},
+		{Name: "breadth", Ratio: 0.15, Entry: page.BreadthGauge},
+		{Name: "altseason", Ratio: 0.25, Entry: page.AltseasonGraph},
+	}, page.hidden)
+
+	bottom := layout.Split(ui.NewCol, []layout.Item{
+		{Ratio: 0.33, Entry: side},
+		{Ratio: 0.67, Entry: page.CoinTable},
+	}, page.hidden)
+
+	page.Grid = ui.NewGrid()
+	page.Grid.Set(layout.Split(ui.NewRow, []layout.Item{
+		{Name: "overview", Ratio: 0.1, Entry: page.OverviewBar},
+		{Ratio: 0.3, Entry: top},
+		{Ratio: 0.6, Entry: bottom},
+	}, page.hidden)...)
+}
+
+// applyTheme colours the widgets of an allCoinPage with the theme in use
+func (page *allCoinPage) applyTheme() {
+	t := theme.Current()
+
+	t.Table(page.CoinTable)
+	t.Table(page.FavouritesTable)
+	for _, graph := range page.TopCoinGraphs {
+		t.LineGraph(graph, t.Line)
+	}
+	t.LineGraph(page.DominanceGraph, t.Accent)
+
+	// Colour breadth by whether most coins are up
+	t.Block(&page.BreadthGauge.Block)
+	page.BreadthGauge.BarColor = t.Up
+	if page.BreadthGauge.Label != "NA" && page.BreadthGauge.Percent < 50 {
+		page.BreadthGauge.BarColor = t.Down
+	}
+
+	t.Block(&page.OverviewBar.Block)
 
+	t.Block(&page.AltseasonGraph.Block)
+	page.AltseasonGraph.Sparklines[0].TitleStyle = ui.NewStyle(t.Title)
+	page.AltseasonGraph.Sparklines[0].LineColor = t.Line
 }
@@ -0,0 +1,130 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package allcoin
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+	"github.com/Gituser143/cryptgo/pkg/display/layout/layouttest"
+	uw "github.com/Gituser143/cryptgo/pkg/display/utilitywidgets"
+	"github.com/Gituser143/cryptgo/pkg/utils"
+	ui "github.com/gizak/termui/v3"
+)
+
+// sparkline returns a sparkline width characters wide of n points of
+// fixture history
+func sparkline(n, width int) string {
+	return utils.Sparkline(layouttest.Series(n, 0, 1), width)
+}
+
+// fillAllCoinPage fills the page with top coins, favourites and market
+// history as fetched from CoinGecko
+func fillAllCoinPage(page *allCoinPage) {
+	page.CoinTable.Header[2] = "Price (USD)"
+	page.CoinTable.Header[6] = "Change %(30d)"
+	page.CoinTable.Rows = [][]string{
+		{"1", "BTC", "43250.12", "▲ 0.42", "▲ 2.41", "▼ 1.18", "▲ 8.73", "19.58M / 21.00M", sparkline(168, sparklineWidth)},
+		{"2", "ETH", "2291.84", "▲ 0.12", "▼ 0.87", "▼ 3.62", "▲ 1.06", "120.17M / NA", sparkline(150, sparklineWidth)},
+		{"3", "USDT", "1.00", "▼ 0.01", "▲ 0.01", "▲ 0.02", "▼ 0.03", "91.73B / NA", sparkline(40, sparklineWidth)},
+		{"4", "BNB", "312.40", "▲ 0.25", "▲ 1.15", "▲ 4.80", "▲ 6.12", "153.86M / 153.86M", sparkline(120, sparklineWidth)},
+		{"5", "SOL", "98.67", "▼ 0.31", "▼ 3.52", "▲ 7.95", "▲ 24.10", "432.96M / NA", sparkline(90, sparklineWidth)},
+		{"6", "XRP", "0.62", "▲ 0.08", "▲ 0.44", "▼ 2.10", "▼ 4.37", "54.28B / 100.00B", sparkline(60, sparklineWidth)},
+		{"7", "USDC", "1.00", "▲ 0.00", "▼ 0.02", "▲ 0.01", "▲ 0.01", "24.71B / NA", sparkline(30, sparklineWidth)},
+		{"8", "ADA", "0.58", "▼ 0.44", "▼ 1.73", "▼ 5.26", "▲ 9.88", "35.11B / 45.00B", sparkline(100, sparklineWidth)},
+	}
+
+	page.FavouritesTable.Header[1] = "Price (USD)"
+	page.FavouritesTable.Rows = [][]string{
+		{"BTC", "43250.12", "▲ 0.42", "▲ 2.41", "▼ 1.18", "▲ 8.73", sparkline(168, favouriteSparklineWidth)},
+		{"ETH", "2291.84", "▲ 0.12", "▼ 0.87", "▼ 3.62", "▲ 1.06", sparkline(150, favouriteSparklineWidth)},
+		{"SOL", "98.67", "▼ 0.31", "▼ 3.52", "▲ 7.95", "▲ 24.10", sparkline(90, favouriteSparklineWidth)},
+	}
+	page.FavouritesTable.Footer = uw.FavouritesFooter(api.FavouriteStats{
+		Count:            3,
+		MarketCap:        1.16e12,
+		AverageChange24h: -0.66,
+		Advancing:        1,
+		Declining:        2,
+	}, uw.USD)
+
+	for i, coin := range []struct {
+		symbol   string
+		min, max float64
+	}{
+		{"BTC", 41020.55, 43980.10},
+		{"ETH", 2205.30, 2402.77},
+		{"USDT", 0.9991, 1.0012},
+	} {
+		page.TopCoinGraphs[i].Title = fmt.Sprintf(" %s (7D) - Rank #%d", coin.symbol, i+1)
+		page.TopCoinGraphs[i].Data["Value"] = layouttest.Series(168, 0, coin.max-coin.min)
+		page.TopCoinGraphs[i].Labels["Max"] = fmt.Sprintf("%.2f USD", coin.max)
+		page.TopCoinGraphs[i].Labels["Min"] = fmt.Sprintf("%.2f USD", coin.min)
+	}
+
+	page.DominanceGraph.Title = " BTC Dominance (since 12 Jan 09:30) "
+	page.DominanceGraph.Data["Value"] = layouttest.Series(48, 0, 1.8)
+	page.DominanceGraph.Labels["Max"] = "52.31%"
+	page.DominanceGraph.Labels["Min"] = "50.51%"
+
+	page.AltseasonGraph.Sparklines[0].Data = layouttest.Series(30, 20, 45)
+	page.AltseasonGraph.Sparklines[0].Title = "38 / 100 - Neutral"
+
+	page.BreadthGauge.Percent = 62
+	page.BreadthGauge.Label = "62% up (62▲ 38▼)"
+
+	now := time.Now()
+	page.StatusBar.Source = "coingecko"
+	page.StatusBar.Statuses = []api.Status{
+		{Type: "coins", Every: 10 * time.Second, Updated: now.Add(-4 * time.Second)},
+		{Type: "top coins", Every: time.Minute, Updated: now.Add(-42 * time.Second)},
+		{Type: "overview", Every: time.Minute, Updated: now.Add(-5 * time.Minute), Err: errors.New("429 Too Many Requests"), Failed: now.Add(-20 * time.Second)},
+	}
+
+	page.OverviewBar.Text = "Market Cap: 1.71 T USD (▲ 1.86%)  |  24h Volume: 48.62 B USD  |  BTC Dominance: 51.62%  |  Active Assets: 12493  |  Markets: 1031"
+}
+
+// allCoinItems returns widgets of the page in the order they're drawn
+func allCoinItems(page *allCoinPage) []ui.Drawable {
+	items := []ui.Drawable{page.Grid}
+	if !page.hidden["status"] {
+		items = append(items, page.StatusBar)
+	}
+	return items
+}
+
+func TestLayout(t *testing.T) {
+	layouttest.Run(t, []layouttest.Case{
+		{
+			Name: "allcoin_fetching",
+			Page: func(w, h int) []ui.Drawable {
+				return allCoinItems(newAllCoinPage(w, h))
+			},
+		},
+		{
+			Name: "allcoin",
+			Page: func(w, h int) []ui.Drawable {
+				page := newAllCoinPage(w, h)
+				fillAllCoinPage(page)
+				return allCoinItems(page)
+			},
+		},
+	})
+}
@@ -19,11 +19,18 @@ package coin
 import (
 	"context"
 	"fmt"
+	"image"
+	"math"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/Gituser143/cryptgo/pkg/alerts"
 	"github.com/Gituser143/cryptgo/pkg/api"
+	"github.com/Gituser143/cryptgo/pkg/display/theme"
 	uw "github.com/Gituser143/cryptgo/pkg/display/utilitywidgets"
+	"github.com/Gituser143/cryptgo/pkg/keys"
 	"github.com/Gituser143/cryptgo/pkg/utils"
 	"github.com/Gituser143/cryptgo/pkg/widgets"
 	ui "github.com/gizak/termui/v3"
@@ -37,48 +44,238 @@ const (
 // DisplayCoin displays the per coin values and details along with a favourites table. It uses the same uiEvents channel as the root page
 func DisplayCoin(
 	ctx context.Context,
-	id string,
+	coinID api.CoinID,
 	coinIDs api.CoinIDMap,
 	intervalChannel chan string,
+	quoteChannel chan api.CoinID,
+	yearAgoChannel chan bool,
+	timeframeChannel chan string,
+	bookChannel chan bool,
+	marketsChannel chan bool,
+	newsChannel chan bool,
+	volumeChannel chan bool,
 	dataChannel chan api.CoinData,
 	priceChannel chan string,
 	uiEvents <-chan ui.Event) error {
 
 	defer ui.Clear()
 
+	// Responses recorded since the page was opened are saved with reports
+	opened := time.Now()
+
+	// Coin metadata is keyed by CoinGecko ID
+	id := coinID.CoinGeckoID
+
 	// Init Coin page
-	page := newCoinPage()
+	page := newCoinPage(ui.TerminalDimensions())
 
 	// Currency table
 	currencyWidget := uw.NewCurrencyPage()
 
-	currencyID := utils.GetCurrency()
-	currencyID, currency, currencyVal := currencyWidget.Get(currencyID)
-
-	// variables for graph interval
-	changeInterval := "24 Hours"
+	currency := currencyWidget.Get(utils.GetCurrency())
+
+	// History received, sampled down to the graph width when drawn. Prices
+	// are above the lowest price, historyBase, and drawn on a log scale
+	// while logScale is set.
+	history := []api.Point{}
+	historyBase := 0.0
+	logScale := false
+
+	// Range of history zoomed into by dragging over the value graph or
+	// with the zoom keys, all history is shown while unset. Times of the
+	// points drawn are kept to find the range dragged over.
+	zoomStart, zoomEnd := time.Time{}, time.Time{}
+	drawnTimes := []time.Time{}
+	dragFrom := -1
+
+	// History of the same period a year earlier, lined up with the last
+	// points of history and overlaid while compared
+	showYearAgo := false
+	yearAgo := []float64{}
+
+	// Data last shown on the page by type, priced in USD, kept to show it
+	// again right away when the currency is changed
+	shown := map[string]api.CoinData{}
+
+	// variables for graph interval, opening with the interval last used
+	coinIntervals := utils.GetCoinIntervals()
+	changeInterval := uw.IntervalLabel(api.DefaultInterval)
+	if label := uw.IntervalLabel(coinIntervals[id]); label != "" {
+		changeInterval = label
+	}
 	changeIntervalWidget := uw.NewChangeIntervalPage()
 
+	// setInterval sends the graph interval to fetch history in, replacing
+	// one not yet picked up, and saves it as the interval of the coin.
+	// Custom ranges of dates aren't saved.
+	setInterval := func(label string) {
+		changeInterval = label
+		interval := uw.IntervalOf(label)
+
+		// Empty current graph
+		history = []api.Point{}
+		page.ValueGraph.Data["Value"] = []float64{}
+		zoomStart, zoomEnd = time.Time{}, time.Time{}
+
+		select {
+		case <-intervalChannel:
+		default:
+		}
+		intervalChannel <- interval
+
+		if _, ok := uw.IntervalMap[label]; !ok {
+			return
+		}
+
+		if interval == api.DefaultInterval {
+			delete(coinIntervals, id)
+		} else {
+			coinIntervals[id] = interval
+		}
+		utils.SaveCoinIntervals(coinIntervals)
+	}
+
+	// variables for pair mode, history is priced in USD when quote is empty
+	quote := api.CoinID{}
+	quoteSymbol := ""
+
+	// variables for the order book, shown over explorers and supply
+	showBook := false
+	book := newOrderBook()
+	book.Format = func(val float64) string {
+		return currency.Format(val)
+	}
+
+	// variables for the markets table, shown in place of the order book
+	// and sorted by volume unless sorted otherwise
+	showMarkets := false
+	marketsSortIdx := 3
+	marketsSortAsc := false
+	marketsHeader := []string{"Exchange", "Pair", "Price", "Volume (24h)", "Volume %"}
+	marketsTable := widgets.NewTable()
+	marketsTable.Header = append([]string{}, marketsHeader...)
+	marketsTable.Header[marketsSortIdx] += " " + DOWN_ARROW
+	marketsTable.ColResizer = func() {
+		x := marketsTable.Inner.Dx()
+		marketsTable.ColWidths = []int{
+			x / 5,
+			x / 5,
+			x / 5,
+			x / 5,
+			x / 5,
+		}
+	}
+
+	// variables for the volume breakdown, shown in place of the order book
+	// and ordered by volume
+	showVolume := false
+	volumeTable := widgets.NewTable()
+	volumeTable.Header = []string{"Exchange", "Trust", "Volume (24h)", "Share"}
+	volumeTable.ColResizer = func() {
+		x := volumeTable.Inner.Dx()
+		volumeTable.ColWidths = []int{
+			x / 4,
+			x / 6,
+			x / 5,
+			x - x/4 - x/6 - x/5,
+		}
+	}
+
+	// variables for the news table, shown in place of details
+	showNews := false
+	newsTable := widgets.NewTable()
+	newsTable.Header = []string{"Published", "Source", "Headline"}
+	newsTable.ColResizer = func() {
+		x := newsTable.Inner.Dx()
+		newsTable.ColWidths = []int{
+			x / 6,
+			x / 6,
+			4 * x / 6,
+		}
+	}
+
+	// variables for candle mode, the value graph is shown when disabled
+	showCandles := false
+	candleTimeframe := "1h"
+	page.CandleChart.Format = func(val float64) string {
+		return currency.Format(val)
+	}
+
+	// setTimeframe sends the timeframe to fetch candles in, replacing one
+	// not yet picked up. An empty timeframe stops fetching candles.
+	setTimeframe := func(timeframe string) {
+		page.CandleChart.Candles = nil
+		page.CandleChart.EmptyText = "Fetching candles..."
+		page.CandleChart.Title = fmt.Sprintf(" Candles (%s) ", timeframe)
+
+		select {
+		case <-timeframeChannel:
+		default:
+		}
+		timeframeChannel <- timeframe
+	}
+
 	// Selection of default table
 	selectedTable := page.ExplorerTable
 	selectedTable.ShowCursor = true
 	utilitySelected := ""
 
+	// Screensaver shown once idle, and what was shown before it
+	screensaver := uw.NewScreensaver()
+	idleReturn := ""
+	defer utils.SetIdle(false)
+
 	// variables to sort favourites table
 	favSortIdx := -1
 	favSortAsc := false
 	favHeader := []string{
 		"Symbol",
-		fmt.Sprintf("Price (%s)", currency),
+		fmt.Sprintf("Price (%s)", currency.Label()),
+		"7D",
 	}
 
 	// Initialise portfolio
 	favourites := utils.GetFavourites()
 	portfolioMap := utils.GetPortfolio()
 	defer func() {
-		utils.SaveMetadata(favourites, currencyID, portfolioMap)
+		utils.SaveMetadata(favourites, currency.ID, portfolioMap)
 	}()
 
+	// Get refresh priority of coin
+	priorities := utils.GetPriorities()
+	policy := api.GetRefreshPolicy(priorities[id])
+
+	// Get data source of coin
+	coinSources := utils.GetCoinSources()
+	src := api.CoinSource(coinSources[id])
+
+	// Explain features the source or providers can't serve for the coin,
+	// which are hidden or adapted rather than failing to be fetched
+	page.StatusBar.Notes = api.Limitations(src, coinID)
+
+	// Forget statuses of data of the last coin opened
+	api.ResetStatus("history", "details", "candles", "order book", "markets", "volume", "news")
+
+	// Stream the price of a crypto currency prices are quoted in, so they
+	// update with both legs of the pair, Eg: 0.0153 BTC
+	lastPrice := 0.0
+	rateChannel := make(chan string)
+	rateCancel := func() {}
+	defer func() { rateCancel() }()
+
+	streamRate := func() {
+		rateCancel()
+		rateCancel = func() {}
+		if currency.Type != "crypto" {
+			return
+		}
+
+		var rateCtx context.Context
+		rateCtx, rateCancel = context.WithCancel(ctx)
+		go api.StreamFXRate(rateCtx, src, coinIDs.Find(currency.Code), currency.Code, rateChannel)
+	}
+	streamRate()
+
 	// Initiliase Portfolio Table
 	portfolioTable := uw.NewPortfolioPage()
 
@@ -86,15 +283,159 @@ func DisplayCoin(
 	help := widgets.NewHelpMenu()
 	help.SelectHelpMenu("COIN")
 
+	// Initialise banner for alerts
+	banner := widgets.NewBanner()
+
+	// applyTheme colours the page and its menus with the theme in use
+	applyTheme := func() {
+		page.applyTheme()
+		theme.Current().Tables(help.Table, portfolioTable.Table, currencyWidget.Table, changeIntervalWidget.Table, marketsTable, volumeTable, newsTable)
+	}
+	applyTheme()
+
+	// setPriceTitle shows the source of prices, health of the live feed and
+	// quotas about to run out in the title of the price box
+	setPriceTitle := func() {
+		page.PriceBox.Title = fmt.Sprintf(" Live Price (%s) - source: %s ", currency.Label(), api.SourceStatus(src))
+		if policy.Live {
+			page.PriceBox.Title = fmt.Sprintf(" Live Price (%s) - source: %s - feed: %s ", currency.Label(), api.SourceStatus(src), api.FeedHealth())
+		}
+		if status := utils.PowerStatus(); status != "" {
+			page.PriceBox.Title += fmt.Sprintf("- %s ", status)
+		}
+		if status := api.QuotaStatus(); status != "" {
+			page.PriceBox.Title += fmt.Sprintf("- %s ", status)
+		}
+	}
+
+	// zoomRange returns the indices of history in the range zoomed into
+	zoomRange := func() (int, int) {
+		first, last := 0, len(history)
+		if !zoomStart.IsZero() {
+			first = sort.Search(len(history), func(i int) bool {
+				return !history[i].Time.Before(zoomStart)
+			})
+			last = sort.Search(len(history), func(i int) bool {
+				return history[i].Time.After(zoomEnd)
+			})
+			if last < first {
+				last = first
+			}
+		}
+		return first, last
+	}
+
+	// drawHistory sets the value graph to as many points of history as the
+	// graph can show, labelled with their dates and marking trading
+	// sessions on intraday charts. History a year earlier is overlaid while
+	// compared. Only the range zoomed into is drawn, if set, scaled to fit
+	// the graph. On a log scale, prices are drawn by their orders of
+	// magnitude between the lowest and highest price drawn.
+	drawHistory := func() {
+		n := (page.ValueGraph.Inner.Dx() + 1) * 2
+		if utils.HistoryPoints > 0 && utils.HistoryPoints < n {
+			n = utils.HistoryPoints
+		}
+
+		first, last := zoomRange()
+
+		indices := utils.SampleIndices(last-first, n)
+		price := make([]float64, 0, len(indices))
+		times := make([]time.Time, 0, len(indices))
+		lastYear := []float64{}
+		offset := len(history) - len(yearAgo)
+		for _, i := range indices {
+			i += first
+			p := history[i].Price
+			if logScale {
+				p += historyBase
+			}
+			if quote == (api.CoinID{}) {
+				p = currency.Convert(p)
+			}
+			price = append(price, p)
+			times = append(times, history[i].Time)
+
+			if showYearAgo && i >= offset {
+				p := yearAgo[i-offset]
+				if logScale {
+					p += historyBase
+				}
+				if quote == (api.CoinID{}) {
+					p = currency.Convert(p)
+				}
+				lastYear = append(lastYear, p)
+			}
+		}
+
+		// Prices are drawn above the lowest of all history, so a zoomed
+		// range is lowered to the bottom of the graph
+		if logScale {
+			logPrices(price, lastYear)
+		} else if !zoomStart.IsZero() && len(price) > 0 {
+			low := price[0]
+			for _, p := range price {
+				low = math.Min(low, p)
+			}
+			for _, p := range lastYear {
+				low = math.Min(low, p)
+			}
+			for i := range price {
+				price[i] -= low
+			}
+			for i := range lastYear {
+				lastYear[i] -= low
+			}
+		}
+
+		page.ValueGraph.Data["Value"] = price
+		delete(page.ValueGraph.Data, "Year Ago")
+		if len(lastYear) > 0 {
+			page.ValueGraph.Data["Year Ago"] = lastYear
+		}
+		page.ValueGraph.XLabels = DateLabels(times)
+		drawnTimes = times
+
+		page.ValueGraph.Markers = nil
+		if session := utils.GetSession(); session.Enabled && (changeInterval == "24 Hours" || changeInterval == "7 Days") {
+			opens, closes := session.Markers(times)
+			for _, i := range opens {
+				page.ValueGraph.Markers = append(page.ValueGraph.Markers, widgets.Marker{Index: i, Color: theme.Current().Up})
+			}
+			for _, i := range closes {
+				page.ValueGraph.Markers = append(page.ValueGraph.Markers, widgets.Marker{Index: i, Color: theme.Current().Down})
+			}
+		}
+	}
+
 	// UpdateUI to refresh UI
 	updateUI := func() {
 		// Get Terminal Dimensions
 		w, h := ui.TerminalDimensions()
 
+		// The screensaver is only drawn again once it moves
+		if utilitySelected == "IDLE" {
+			screensaver.Resize(w, h)
+			if !screensaver.Changed() && !banner.Active() {
+				return
+			}
+		}
+
 		// Adjust Suuply chart Bar graph values
 		page.SupplyChart.BarGap = ((w / 3) - (2 * page.SupplyChart.BarWidth)) / 2
 
-		page.Grid.SetRect(0, 0, w, h)
+		page.resize(w, h)
+
+		// Focus a table which is shown if the selected one was hidden
+		if (page.hidden["explorers"] && selectedTable == page.ExplorerTable) ||
+			(page.hidden["favourites"] && selectedTable == page.FavouritesTable) {
+			selectedTable.ShowCursor = false
+			selectedTable = page.ExplorerTable
+			if page.hidden["explorers"] {
+				selectedTable = page.FavouritesTable
+			}
+			selectedTable.ShowCursor = true
+		}
 
 		// Clear UI
 		ui.Clear()
@@ -113,28 +454,627 @@ func DisplayCoin(
 		case "CHANGE":
 			changeIntervalWidget.Resize(w, h)
 			ui.Render(changeIntervalWidget)
+		case "IDLE":
+			ui.Render(screensaver)
 		default:
+			drawHistory()
 			ui.Render(page.Grid)
+			if !page.hidden["strip"] {
+				ui.Render(page.PerformanceStrip)
+			}
+
+			// Draw candles over the value graph
+			if showCandles {
+				r := page.ValueGraph.GetRect()
+				page.CandleChart.SetRect(r.Min.X, r.Min.Y, r.Max.X, r.Max.Y)
+				ui.Render(page.CandleChart)
+			}
+
+			// Draw order book, markets or volume over explorers and supply
+			if showBook {
+				book.SetRect(page.bookRect())
+				ui.Render(book)
+			}
+			if showMarkets {
+				marketsTable.SetRect(page.bookRect())
+				ui.Render(marketsTable)
+			}
+			if showVolume {
+				volumeTable.SetRect(page.bookRect())
+				ui.Render(volumeTable)
+			}
+
+			// Draw news over details
+			if showNews {
+				newsTable.SetRect(page.newsRect())
+				ui.Render(newsTable)
+			}
+
+			// Show statuses of data fetched for shown widgets
+			if !page.hidden["status"] {
+				types := []string{"history", "details", "favourites"}
+				optional := map[string]bool{
+					"candles":    showCandles,
+					"order book": showBook,
+					"markets":    showMarkets,
+					"volume":     showVolume,
+					"news":       showNews,
+				}
+				for _, dataType := range []string{"candles", "order book", "markets", "volume", "news"} {
+					if optional[dataType] {
+						types = append(types, dataType)
+					}
+				}
+				page.StatusBar.Update(src, types...)
+				ui.Render(page.StatusBar)
+			}
+		}
+
+		// Flash banner of triggered alerts
+		if banner.Active() {
+			banner.Resize(w, h)
+			ui.Render(banner)
+		}
+	}
+
+	// setBook shows or hides the order book, fetched while shown
+	setBook := func(show bool) {
+		showBook = show
+		book.Book = api.OrderBook{}
+		book.Title = " Order Book "
+		book.EmptyText = "Fetching order book..."
+
+		// Replace state not yet picked up
+		select {
+		case <-bookChannel:
+		default:
+		}
+		bookChannel <- showBook
+	}
+
+	// sortMarkets sorts the markets table, marking the sorted column
+	sortMarkets := func() {
+		marketsTable.Header = append([]string{}, marketsHeader...)
+		if marketsSortAsc {
+			marketsTable.Header[marketsSortIdx] += " " + UP_ARROW
+		} else {
+			marketsTable.Header[marketsSortIdx] += " " + DOWN_ARROW
+		}
+		utils.SortData(marketsTable.Rows, marketsSortIdx, marketsSortAsc, utils.MarketsLayout)
+	}
+
+	// setMarkets shows or hides the markets table, fetched and focused
+	// while shown
+	setMarkets := func(show bool) {
+		showMarkets = show
+		delete(shown, "MARKETS")
+		marketsTable.Rows = [][]string{}
+		marketsTable.Title = " Markets - fetching... "
+
+		// Replace state not yet picked up
+		select {
+		case <-marketsChannel:
+		default:
+		}
+		marketsChannel <- showMarkets
+
+		selectedTable.ShowCursor = false
+		if showMarkets {
+			selectedTable = marketsTable
+		} else if selectedTable == marketsTable {
+			selectedTable = page.ExplorerTable
+		}
+		selectedTable.ShowCursor = true
+	}
+
+	// setVolume shows or hides the volume breakdown, fetched and focused
+	// while shown
+	setVolume := func(show bool) {
+		showVolume = show
+		delete(shown, "VOLUME")
+		volumeTable.Rows = [][]string{}
+		volumeTable.Title = " Volume by Exchange - fetching... "
+
+		// Replace state not yet picked up
+		select {
+		case <-volumeChannel:
+		default:
+		}
+		volumeChannel <- showVolume
+
+		selectedTable.ShowCursor = false
+		if showVolume {
+			selectedTable = volumeTable
+		} else if selectedTable == volumeTable {
+			selectedTable = page.ExplorerTable
+		}
+		selectedTable.ShowCursor = true
+	}
+
+	// setNews shows or hides the news table, fetched and focused while
+	// shown
+	setNews := func(show bool) {
+		showNews = show
+		newsTable.Rows = [][]string{}
+		newsTable.Title = " News - fetching... "
+
+		// Replace state not yet picked up
+		select {
+		case <-newsChannel:
+		default:
+		}
+		newsChannel <- showNews
+
+		selectedTable.ShowCursor = false
+		if showNews {
+			selectedTable = newsTable
+		} else if selectedTable == newsTable {
+			selectedTable = page.ExplorerTable
 		}
+		selectedTable.ShowCursor = true
 	}
 
+	// headlineURLs are links of the headlines listed in the news table
+	headlineURLs := []string{}
+
+	// sortFavourites sorts the favourites table, by symbol unless sorted
+	// otherwise
+	sortFavourites := func() {
+		if favSortIdx != -1 {
+			utils.SortData(page.FavouritesTable.Rows, favSortIdx, favSortAsc, utils.FavouritesLayout)
+
+			if favSortAsc {
+				page.FavouritesTable.Header[favSortIdx] = favHeader[favSortIdx] + " " + UP_ARROW
+			} else {
+				page.FavouritesTable.Header[favSortIdx] = favHeader[favSortIdx] + " " + DOWN_ARROW
+			}
+		} else {
+			utils.SortData(page.FavouritesTable.Rows, 0, true, utils.FavouritesLayout)
+		}
+
+	}
+
+	// showFavourites fills the favourites table and its footer
+	showFavourites := func(data api.CoinData) {
+		rows := [][]string{}
+		for symbol, price := range data.Favourites {
+			p := currency.Format(price)
+			rows = append(rows, []string{symbol, p, utils.Sparkline(data.Sparklines[symbol], favouriteSparklineWidth)})
+		}
+		page.FavouritesTable.Header[1] = fmt.Sprintf("Price (%s)", currency.Label())
+		page.FavouritesTable.Rows = rows
+
+		// Cycle favourites' prices on the screensaver, in order
+		screensaver.Items = []string{}
+		for _, row := range rows {
+			screensaver.Items = append(screensaver.Items, row[0]+"  "+row[1])
+		}
+		sort.Strings(screensaver.Items)
+
+		// Show when prices are partial or stale, Eg: CoinGecko
+		// markets being unavailable
+		page.FavouritesTable.Title = fmt.Sprintf(" %s ", data.Watchlist)
+		if data.Degraded != "" {
+			page.FavouritesTable.Title = fmt.Sprintf(" %s - %s ", data.Watchlist, data.Degraded)
+		}
+
+		// Update favourites footer with aggregate stats
+		page.FavouritesTable.Footer = uw.FavouritesFooter(data.FavouriteStats, currency)
+	}
+
+	// labelHistory labels the value graph with the latest, highest and
+	// lowest price of the history shown, or of the range zoomed into
+	// showStats shows volatility, drawdown and Sharpe ratio of the history
+	// of the interval last received, in the quote it is priced in
+	showStats := func(data api.CoinData) {
+		page.StatsTable.Title = fmt.Sprintf(" Statistics (%s) ", changeInterval)
+		if quote != (api.CoinID{}) {
+			page.StatsTable.Title = fmt.Sprintf(" Statistics (%s) in %s ", changeInterval, quoteSymbol)
+		}
+
+		stats, ok := api.ComputeHistoryStats(data.PriceHistory, data.MinPrice)
+		if !ok {
+			page.StatsTable.Rows = [][]string{{"Too little history", ""}}
+			return
+		}
+		page.StatsTable.Rows = [][]string{
+			{"Return", fmt.Sprintf("%.2f%%", stats.Return)},
+			{"Volatility (ann.)", fmt.Sprintf("%.2f%%", stats.Volatility)},
+			{"Max Drawdown", fmt.Sprintf("%.2f%%", stats.MaxDrawdown)},
+			{"Sharpe (ann.)", fmt.Sprintf("%.2f", stats.Sharpe)},
+		}
+	}
+
+	labelHistory := func(data api.CoinData) {
+		high, low := data.MaxPrice, data.MinPrice
+		if first, last := zoomRange(); !zoomStart.IsZero() && last > first {
+			high, low = math.Inf(-1), math.Inf(1)
+			for _, point := range history[first:last] {
+				high = math.Max(high, point.Price+data.MinPrice)
+				low = math.Min(low, point.Price+data.MinPrice)
+			}
+		}
+
+		if quote == (api.CoinID{}) {
+			value := history[len(history)-1].Price + data.MinPrice
+
+			page.ValueGraph.Labels["Value"] = currency.Money(value)
+			page.ValueGraph.Labels["Max"] = currency.Money(high)
+			page.ValueGraph.Labels["Min"] = currency.Money(low)
+
+			// Update Graph title
+			page.ValueGraph.Title = fmt.Sprintf(" Value History (%s) ", changeInterval)
+		} else {
+			value := history[len(history)-1].Price + data.MinPrice
+
+			page.ValueGraph.Labels["Value"] = fmt.Sprintf("%.8f %s", value, quoteSymbol)
+			page.ValueGraph.Labels["Max"] = fmt.Sprintf("%.8f %s", high, quoteSymbol)
+			page.ValueGraph.Labels["Min"] = fmt.Sprintf("%.8f %s", low, quoteSymbol)
+
+			// Update Graph title
+			page.ValueGraph.Title = fmt.Sprintf(" Value History (%s) in %s ", changeInterval, quoteSymbol)
+		}
+		if logScale {
+			page.ValueGraph.Title += "- log scale "
+		}
+		if !zoomStart.IsZero() {
+			page.ValueGraph.Title += fmt.Sprintf("- zoomed to %s - %s ", zoomStart.Format("02 Jan 15:04"), zoomEnd.Format("02 Jan 15:04"))
+		}
+
+		// Label the last price a year earlier with the change since
+		delete(page.ValueGraph.Labels, "Year Ago")
+		if n := len(data.YearAgo); n > 0 {
+			then := data.YearAgo[n-1] + data.MinPrice
+			value := history[len(history)-1].Price + data.MinPrice
+			label := fmt.Sprintf("%.8f %s", then, quoteSymbol)
+			if quote == (api.CoinID{}) {
+				label = currency.Money(then)
+			}
+			if then != 0 {
+				label += fmt.Sprintf(" (%+.2f%% since)", (value/then-1)*100)
+			}
+			page.ValueGraph.Labels["Year Ago"] = label
+		}
+	}
+
+	// setZoom zooms the value graph into history from start to end, kept
+	// within the history received. A zero start, or a range covering all
+	// history, shows all history again.
+	setZoom := func(start, end time.Time) {
+		if len(history) > 0 && !start.IsZero() {
+			oldest, latest := history[0].Time, history[len(history)-1].Time
+			switch {
+			case end.Sub(start) >= latest.Sub(oldest):
+				start = time.Time{}
+			case start.Before(oldest):
+				start, end = oldest, end.Add(oldest.Sub(start))
+			case end.After(latest):
+				start, end = start.Add(latest.Sub(end)), latest
+			}
+		}
+		if start.IsZero() {
+			end = time.Time{}
+		}
+		zoomStart, zoomEnd = start, end
+
+		if len(history) > 0 {
+			drawHistory()
+			if data, ok := shown["HISTORY"]; ok {
+				labelHistory(data)
+			}
+		}
+	}
+
+	// setCandlesTitle shows the last candle in the title of the candle chart
+	setCandlesTitle := func() {
+		candles := page.CandleChart.Candles
+		if len(candles) > 0 {
+			last := candles[len(candles)-1]
+			page.CandleChart.Title = fmt.Sprintf(" Candles (%s) - O %s H %s L %s C %s %s ", candleTimeframe,
+				currency.Format(last.Open), currency.Format(last.High), currency.Format(last.Low), currency.Format(last.Close), currency.Label())
+		}
+	}
+
+	// setBookTitle shows the spread of the order book in its title
+	setBookTitle := func() {
+		book.Title = fmt.Sprintf(" Order Book (%s) ", currency.Label())
+		if spread, ok := book.Spread(); ok {
+			book.Title = fmt.Sprintf(" Order Book (%s) - spread %.3f%% ", currency.Label(), spread)
+		}
+	}
+
+	// showMarketRows fills the markets table
+	showMarketRows := func(data api.CoinData) {
+		rows := [][]string{}
+		for _, market := range data.Markets {
+			rows = append(rows, []string{
+				market.Exchange,
+				market.Pair,
+				currency.Format(market.PriceUSD),
+				currency.Compact(market.VolumeUSD),
+				fmt.Sprintf("%.2f%%", market.VolumeShare),
+			})
+		}
+		marketsTable.Rows = rows
+		marketsTable.Title = fmt.Sprintf(" Markets (%s) ", currency.Label())
+		if len(rows) == 0 {
+			marketsTable.Title = " Markets - none listed on CoinCap "
+		}
+		sortMarkets()
+	}
+
+	// showVolumeRows fills the volume breakdown, flagging exchanges
+	// CoinGecko rates low trust in along with the share of volume they hold
+	showVolumeRows := func(data api.CoinData) {
+		rows := [][]string{}
+		lowShare := 0.0
+		for _, val := range data.Volumes {
+			trust := "-"
+			switch val.Trust {
+			case api.TrustHigh:
+				trust = "high"
+			case api.TrustMedium:
+				trust = "medium"
+			case api.TrustLow:
+				trust = "low !"
+				lowShare += val.Share
+			}
+
+			rows = append(rows, []string{
+				val.Exchange,
+				trust,
+				currency.Compact(val.VolumeUSD),
+				fmt.Sprintf("%5.2f%% %s", val.Share, utils.ShareBar(val.Share, 10)),
+			})
+		}
+		volumeTable.Rows = rows
+
+		switch {
+		case data.Degraded != "":
+			volumeTable.Title = " Volume by Exchange - unavailable "
+		case len(rows) == 0:
+			volumeTable.Title = " Volume by Exchange - none listed on CoinGecko "
+		case lowShare > 0:
+			volumeTable.Title = fmt.Sprintf(" Volume by Exchange (%s) | %.1f%% on low trust ", currency.Label(), lowShare)
+		default:
+			volumeTable.Title = fmt.Sprintf(" Volume by Exchange (%s) ", currency.Label())
+		}
+	}
+
+	// showDetails fills the details table and prices of the price box
+	showDetails := func(data api.CoinData) {
+		page.DetailsTable.Title = " Details "
+		page.DetailsTable.Header = []string{"Name", data.Details.Name}
+
+		marketCap := currency.CompactMoney(data.Details.MarketCap)
+		ATH := currency.CompactMoney(data.Details.ATH)
+		ATL := currency.CompactMoney(data.Details.ATL)
+		TotalVolume := currency.CompactMoney(data.Details.TotalVolume)
+
+		// Show pending priority if it was changed on this page
+		priority := api.GetRefreshPolicy(priorities[id]).Priority
+		if priority != policy.Priority {
+			priority = fmt.Sprintf("%s (on reopen)", priority)
+		}
+
+		// List price alerts of coin
+		alertsStr := "None"
+		if coinAlerts := alerts.Get(id); len(coinAlerts) > 0 {
+			alertStrs := []string{}
+			for _, alert := range coinAlerts {
+				alertStrs = append(alertStrs, alert.String())
+			}
+			alertsStr = strings.Join(alertStrs, ", ")
+		}
+
+		// Show pending source if it was changed on this page
+		sourceName := api.CoinSource(coinSources[id]).Name()
+		if sourceName != src.Name() {
+			sourceName = fmt.Sprintf("%s (on reopen)", sourceName)
+		}
+
+		rows := [][]string{
+			{"Symbol", data.Details.Symbol},
+			{"Rank", data.Details.Rank},
+			{"BlockTime (min)", data.Details.BlockTime},
+			{"MarketCap", marketCap},
+			{"ATH", ATH},
+			{"ATHDate", data.Details.ATHDate},
+			{"ATL", ATL},
+			{"ATLDate", data.Details.ATLDate},
+			{"TotalVolume", TotalVolume},
+			{"LastUpdate", data.Details.LastUpdate},
+			{"Refresh Priority", priority},
+			{"Source", sourceName},
+			{"Alerts", alertsStr},
+		}
+
+		page.DetailsTable.Rows = rows
+
+		// Use polled price when no live stream is allocated
+		if !policy.Live {
+			lastPrice = data.Details.CurrentPrice
+			page.PriceBox.Rows[0][0] = currency.Format(data.Details.CurrentPrice)
+		}
+
+		// Update 24 High/Low
+		page.PriceBox.Rows[0][1] = currency.Format(data.Details.High24)
+		page.PriceBox.Rows[0][2] = currency.Format(data.Details.Low24)
+		setPriceTitle()
+	}
+
+	// convertShown shows data already fetched in the currency in use, from
+	// the USD prices it was fetched in
+	convertShown := func() {
+		setPriceTitle()
+		if lastPrice > 0 {
+			page.PriceBox.Rows[0][0] = currency.Format(lastPrice)
+		}
+
+		if data, ok := shown["FAVOURITES"]; ok {
+			showFavourites(data)
+			sortFavourites()
+		}
+		if data, ok := shown["HISTORY"]; ok && data.Quote == quote && data.Interval == uw.IntervalOf(changeInterval) {
+			drawHistory()
+			labelHistory(data)
+		}
+		if showCandles {
+			setCandlesTitle()
+		}
+		if _, ok := shown["ORDERBOOK"]; ok && showBook {
+			setBookTitle()
+		}
+		if data, ok := shown["MARKETS"]; ok && showMarkets {
+			showMarketRows(data)
+		}
+		if data, ok := shown["VOLUME"]; ok && showVolume {
+			showVolumeRows(data)
+		}
+		if data, ok := shown["DETAILS"]; ok {
+			showDetails(data)
+		}
+	}
+
+	// quickStats returns quick stats of the coin as a line of JSON, once
+	// its details are fetched
+	quickStats := func() (string, bool) {
+		data, ok := shown["DETAILS"]
+		if !ok {
+			return "", false
+		}
+		line, err := api.NewQuickStats(coinID, data.Details, lastPrice, src.Name()).JSON()
+		return line, err == nil
+	}
+
+	// Stats are written once a tick when they changed, if there is an
+	// output for them
+	statsChanged := false
+
 	// Render empty UI
 	updateUI()
 
 	// Create ticker to periodically refresh UI
-	t := time.NewTicker(time.Duration(1) * time.Second)
+	t := time.NewTicker(utils.UIRefresh)
 	tick := t.C
 
-	previousKey := ""
+	// Create ticker to throttle redraws of live price, slowed down in low
+	// power mode
+	renderInterval := utils.RenderInterval()
+	r := time.NewTicker(renderInterval)
+	defer r.Stop()
+	renderTick := r.C
+	priceChanged := false
+
+	// Reload config and metadata, Eg: after the config file was edited. The
+	// previous config is kept if the file can't be read or is invalid, and
+	// the error is shown in the status bar.
+	reload := func() {
+		utils.Reload()
+		favourites = utils.GetFavourites()
+		portfolioMap = utils.GetPortfolio()
+		currency = currencyWidget.Get(utils.GetCurrency())
+		streamRate()
+		convertShown()
+
+		// Lay out the page again, with profiles as reloaded
+		page.profile = ""
+		updateUI()
+	}
+
+	// Create channels to get suspend and reload signals
+	suspendSignals := utils.SuspendSignals()
+	reloadSignals := utils.ReloadSignals()
 
 	for {
 		select {
 		case <-ctx.Done(): // Context cancelled, exit
 			return ctx.Err()
 
+		case <-suspendSignals: // SIGTSTP sent from outside the UI
+			if err := utils.Suspend(); err != nil {
+				return err
+			}
+			updateUI()
+
+		case <-reloadSignals: // SIGHUP
+			reload()
+
 		case e := <-uiEvents: // keyboard events
-			switch e.ID {
-			case "<Escape>", "q", "<C-c>":
+			// Any key closes the screensaver, going back to what was shown
+			if e.ID != "<Resize>" {
+				screensaver.Touch()
+			}
+			if utilitySelected == "IDLE" {
+				if e.ID != "<Resize>" {
+					utilitySelected = idleReturn
+					utils.SetIdle(false)
+				}
+				updateUI()
+				break
+			}
+
+			action := keys.Coin.Action(e.ID)
+
+			// Mouse events act on the table under the pointer. Dragging
+			// over the value graph zooms into the range dragged over, and
+			// right clicking it shows all history again.
+			if e.Type == ui.MouseEvent {
+				mouse, _ := e.Payload.(ui.Mouse)
+				graph := page.ValueGraph
+				overGraph := utilitySelected == "" && !showCandles && image.Pt(mouse.X, mouse.Y).In(graph.Inner)
+
+				switch {
+				case e.ID == "<MouseLeft>" && (overGraph || dragFrom != -1):
+					if dragFrom == -1 {
+						dragFrom = mouse.X
+					}
+					graph.Selection = []int{dragFrom, mouse.X}
+					action = ""
+
+				case e.ID == "<MouseRelease>" && dragFrom != -1:
+					from, to := graph.IndexAt(dragFrom), graph.IndexAt(mouse.X)
+					if from > to {
+						from, to = to, from
+					}
+					if from != -1 && to > from && to < len(drawnTimes) {
+						setZoom(drawnTimes[from], drawnTimes[to])
+					}
+					dragFrom = -1
+					graph.Selection = nil
+					action = ""
+
+				case e.ID == "<MouseRight>" && overGraph:
+					if !zoomStart.IsZero() {
+						setZoom(time.Time{}, time.Time{})
+					}
+					action = ""
+
+				default:
+					tables := []*widgets.Table{selectedTable}
+					if utilitySelected == "" {
+						tables = []*widgets.Table{}
+						if !page.hidden["favourites"] {
+							tables = append(tables, page.FavouritesTable)
+						}
+						// Explorers are drawn over by the order book, markets and volume
+						if !page.hidden["explorers"] && !showBook && !showMarkets && !showVolume {
+							tables = append(tables, page.ExplorerTable)
+						}
+					}
+
+					var table *widgets.Table
+					table, action = keys.Mouse(e, tables...)
+					if table != nil && table != selectedTable {
+						selectedTable.ShowCursor = false
+						selectedTable = table
+						selectedTable.ShowCursor = true
+					}
+				}
+			}
+
+			switch action {
+			case keys.Quit:
 				if utilitySelected != "" {
 					utilitySelected = ""
 					selectedTable = page.ExplorerTable
@@ -147,14 +1087,23 @@ func DisplayCoin(
 			case "<Resize>":
 				updateUI()
 
-			case "?":
+			case keys.Suspend:
+				if err := utils.Suspend(); err != nil {
+					return err
+				}
+				updateUI()
+
+			case keys.Reload:
+				reload()
+
+			case keys.Help:
 				selectedTable.ShowCursor = false
 				selectedTable = help.Table
 				selectedTable.ShowCursor = true
 				utilitySelected = "HELP"
 				updateUI()
 
-			case "c":
+			case keys.Currency:
 				if utilitySelected == "" {
 					selectedTable.ShowCursor = false
 					selectedTable = currencyWidget.Table
@@ -163,7 +1112,7 @@ func DisplayCoin(
 					utilitySelected = "CURRENCY"
 				}
 
-			case "C":
+			case keys.CurrencyAll:
 				if utilitySelected == "" {
 					selectedTable.ShowCursor = false
 					selectedTable = currencyWidget.Table
@@ -172,7 +1121,7 @@ func DisplayCoin(
 					utilitySelected = "CURRENCY"
 				}
 
-			case "d":
+			case keys.Interval:
 				if utilitySelected == "" {
 					selectedTable.ShowCursor = false
 					selectedTable = changeIntervalWidget.Table
@@ -180,76 +1129,404 @@ func DisplayCoin(
 					utilitySelected = "CHANGE"
 				}
 
-			case "f":
-				if utilitySelected == "" {
+			case keys.Favourites:
+				if utilitySelected == "" && !page.hidden["favourites"] {
 					selectedTable.ShowCursor = false
 					selectedTable = page.FavouritesTable
 					selectedTable.ShowCursor = true
 				}
 
-			case "F":
-				if utilitySelected == "" {
+			case keys.Explorers:
+				if utilitySelected == "" && !page.hidden["explorers"] {
 					selectedTable.ShowCursor = false
 					selectedTable = page.ExplorerTable
 					selectedTable.ShowCursor = true
 				}
 
-			case "P":
+			case keys.Priority:
+				if utilitySelected == "" {
+					// Cycle refresh priority, applied when the coin is next opened
+					priority := api.NextPriority(api.GetRefreshPolicy(priorities[id]).Priority)
+					if priority == api.PriorityNormal {
+						delete(priorities, id)
+					} else {
+						priorities[id] = priority
+					}
+					utils.SavePriorities(priorities)
+				}
+
+			case keys.Source:
+				if utilitySelected == "" {
+					// Cycle data source, applied when the coin is next opened
+					name := api.NextSource(api.CoinSource(coinSources[id]).Name())
+					if name == api.GetSource().Name() {
+						delete(coinSources, id)
+					} else {
+						coinSources[id] = name
+					}
+					utils.SaveCoinSources(coinSources)
+				}
+
+			case keys.Quote:
+				if utilitySelected == "" {
+					// Get quote coin to price history in
+					inputStr := widgets.DrawPrompt(uiEvents, " Quote Symbol (empty for fiat) ")
+					symbol := strings.ToUpper(strings.TrimSpace(inputStr))
+
+					newQuote := api.CoinID{}
+					if symbol != "" {
+						newQuote = coinIDs[symbol]
+					}
+
+					// Update pair if quote is valid and different
+					if (symbol == "" || newQuote != api.CoinID{}) && newQuote != coinID && newQuote != quote {
+						quote = newQuote
+						quoteSymbol = symbol
+
+						// Empty current graph
+						history = []api.Point{}
+						page.ValueGraph.Data["Value"] = []float64{}
+
+						// Send Updated Quote, replacing one not yet picked up
+						select {
+						case <-quoteChannel:
+						default:
+						}
+						quoteChannel <- quote
+					}
+					updateUI()
+				}
+
+			case keys.IntervalShorter:
+				if utilitySelected == "" {
+					// Step to shorter interval
+					if label := uw.StepInterval(changeInterval, -1); label != changeInterval {
+						setInterval(label)
+					}
+				}
+
+			case keys.IntervalLonger:
+				if utilitySelected == "" {
+					// Step to longer interval
+					if label := uw.StepInterval(changeInterval, 1); label != changeInterval {
+						setInterval(label)
+					}
+				}
+
+			case keys.HistoryRange:
+				if utilitySelected == "" {
+					// Get custom range of dates to show history over
+					inputStr := widgets.DrawPrompt(uiEvents, " History range, Eg: 2021-01-01 to 2021-06-30 ")
+					inputStr = strings.Join(strings.Fields(inputStr), " ")
+					if inputStr != "" {
+						if _, _, err := api.ParseHistoryRange(inputStr); err != nil {
+							banner.Show(err.Error(), time.Duration(5)*time.Second)
+						} else if inputStr != changeInterval {
+							setInterval(inputStr)
+						}
+					}
+					updateUI()
+				}
+
+			case keys.ZoomIn, keys.ZoomOut, keys.PanLeft, keys.PanRight:
+				if utilitySelected == "" && !showCandles && len(history) > 1 {
+					start, end := zoomStart, zoomEnd
+					if start.IsZero() {
+						start, end = history[0].Time, history[len(history)-1].Time
+					}
+					span := end.Sub(start)
+					centre := start.Add(span / 2)
+
+					switch action {
+					case keys.ZoomIn:
+						// Keep enough points to draw a line
+						if first, last := zoomRange(); last-first > 8 {
+							setZoom(centre.Add(-span/4), centre.Add(span/4))
+						}
+					case keys.ZoomOut:
+						if !zoomStart.IsZero() {
+							setZoom(centre.Add(-span), centre.Add(span))
+						}
+					case keys.PanLeft:
+						if !zoomStart.IsZero() {
+							setZoom(start.Add(-span/4), end.Add(-span/4))
+						}
+					case keys.PanRight:
+						if !zoomStart.IsZero() {
+							setZoom(start.Add(span/4), end.Add(span/4))
+						}
+					}
+					updateUI()
+				}
+
+			case keys.LogScale:
+				if utilitySelected == "" && !showCandles {
+					logScale = !logScale
+					if len(history) > 0 {
+						drawHistory()
+						if data, ok := shown["HISTORY"]; ok {
+							labelHistory(data)
+						}
+					}
+					updateUI()
+				}
+
+			case keys.Candles:
+				if utilitySelected == "" {
+					// Toggle candle mode, if candles are served for the coin
+					if reason := api.FeatureUnavailable("candles", coinID); reason != "" && !showCandles {
+						banner.Show(reason, time.Duration(3)*time.Second)
+						updateUI()
+						break
+					}
+					showCandles = !showCandles
+					if showCandles {
+						setTimeframe(candleTimeframe)
+					} else {
+						setTimeframe("")
+					}
+				}
+
+			case keys.YearAgo:
+				if utilitySelected == "" {
+					// Toggle comparison with the same period a year
+					// earlier, fetched with the next history
+					showYearAgo = !showYearAgo
+					select {
+					case <-yearAgoChannel:
+					default:
+					}
+					yearAgoChannel <- showYearAgo
+
+					if showYearAgo {
+						banner.Show("Comparing with a year ago", time.Duration(3)*time.Second)
+					} else {
+						yearAgo = []float64{}
+						drawHistory()
+					}
+					updateUI()
+				}
+
+			case keys.CandleTimeframe:
+				if utilitySelected == "" && showCandles {
+					// Cycle candle timeframe
+					for i, timeframe := range api.CandleTimeframes {
+						if timeframe == candleTimeframe {
+							candleTimeframe = api.CandleTimeframes[(i+1)%len(api.CandleTimeframes)]
+							break
+						}
+					}
+					setTimeframe(candleTimeframe)
+				}
+
+			case keys.OrderBook:
+				if utilitySelected == "" {
+					if reason := api.FeatureUnavailable("order book", coinID); reason != "" && !showBook {
+						banner.Show(reason, time.Duration(3)*time.Second)
+						updateUI()
+						break
+					}
+
+					// Toggle order book, in place of markets and volume
+					if showMarkets {
+						setMarkets(false)
+					}
+					if showVolume {
+						setVolume(false)
+					}
+					setBook(!showBook)
+				}
+
+			case keys.Markets:
+				if utilitySelected == "" {
+					if reason := api.FeatureUnavailable("markets", coinID); reason != "" && !showMarkets {
+						banner.Show(reason, time.Duration(3)*time.Second)
+						updateUI()
+						break
+					}
+
+					// Toggle markets, in place of the order book and volume
+					if showBook {
+						setBook(false)
+					}
+					if showVolume {
+						setVolume(false)
+					}
+					setMarkets(!showMarkets)
+				}
+
+			case keys.Volume:
+				if utilitySelected == "" {
+					if reason := api.FeatureUnavailable("volume", coinID); reason != "" && !showVolume {
+						banner.Show(reason, time.Duration(3)*time.Second)
+						updateUI()
+						break
+					}
+
+					// Toggle volume, in place of the order book and markets
+					if showBook {
+						setBook(false)
+					}
+					if showMarkets {
+						setMarkets(false)
+					}
+					setVolume(!showVolume)
+				}
+
+			case keys.News:
+				if utilitySelected == "" {
+					// Toggle news, in place of details
+					setNews(!showNews)
+				}
+
+			case keys.Alert:
+				if utilitySelected == "" {
+					// Get price alert of coin
+					inputStr := widgets.DrawPrompt(uiEvents, " Alert: >price, <price, %change or ~move/window (empty to clear) ")
+					if strings.TrimSpace(inputStr) == "" {
+						alerts.Clear(id)
+					} else if alert, err := alerts.Parse(id, inputStr); err != nil {
+						banner.Show(err.Error(), time.Duration(5)*time.Second)
+					} else {
+						alerts.Add(alert)
+					}
+					updateUI()
+				}
+
+			case keys.CopySummary:
+				if utilitySelected == "" {
+					// Copy text summary of coin to clipboard
+					summary, err := getSummary(coinID, currency)
+					if err == nil {
+						err = utils.CopyToClipboard(summary)
+					}
+
+					if err == nil {
+						page.DetailsTable.Title = " Details - Summary copied "
+					} else {
+						page.DetailsTable.Title = " Details - Unable to copy summary "
+					}
+				}
+
+			case keys.CopyStats:
+				if utilitySelected == "" {
+					// Write quick stats of coin as JSON, to the stats output
+					// if set, else the clipboard
+					line, ok := quickStats()
+					switch {
+					case !ok:
+						banner.Show("Quick stats not fetched yet", time.Duration(3)*time.Second)
+					case utils.StatsOutput != "":
+						if utils.WriteStats(line) {
+							banner.Show("Quick stats written to "+utils.StatsOutput, time.Duration(3)*time.Second)
+						} else {
+							banner.Show("Unable to write quick stats, output is behind", time.Duration(5)*time.Second)
+						}
+					case utils.CopyToClipboard(line) == nil:
+						page.DetailsTable.Title = " Details - Quick stats copied "
+					default:
+						page.DetailsTable.Title = " Details - Unable to copy quick stats "
+					}
+				}
+
+			case keys.Export:
+				if utilitySelected == "" {
+					// Export data shown to timestamped files to share or
+					// analyse it
+					view, err := newExportView(coinID, shown, lastPrice, src.Name(), quoteSymbol)
+					paths := []string{}
+					if err == nil {
+						paths, err = writeExport(view)
+					}
+					if err == nil {
+						banner.Show("Exported to "+strings.Join(paths, ", "), time.Duration(10)*time.Second)
+					} else {
+						banner.Show("Unable to export: "+err.Error(), time.Duration(10)*time.Second)
+					}
+				}
+
+			case keys.ClearCache:
+				if utilitySelected == "" {
+					// Drop cached responses, so data is fetched afresh
+					if err := api.ClearCache(); err == nil {
+						banner.Show("Cache cleared, refreshing", time.Duration(3)*time.Second)
+					} else {
+						banner.Show("Unable to clear cache: "+err.Error(), time.Duration(10)*time.Second)
+					}
+				}
+
+			case keys.Report:
+				if utilitySelected == "" {
+					// Save raw responses backing the page to reproduce bugs
+					path, err := api.SaveReport("coin "+id, opened)
+					if err == nil {
+						banner.Show("Report saved to "+path, time.Duration(10)*time.Second)
+					} else {
+						banner.Show("Unable to save report: "+err.Error(), time.Duration(10)*time.Second)
+					}
+				}
+
+			case keys.LowPower:
+				if utilitySelected == "" {
+					// Cycle power mode, Eg: to stay live on battery
+					mode := utils.NextPowerMode(utils.GetPowerMode())
+					utils.SetPowerMode(mode)
+					setPriceTitle()
+					banner.Show("Power mode: "+mode, time.Duration(3)*time.Second)
+				}
+
+			case keys.Theme:
+				if utilitySelected == "" {
+					// Cycle through themes for the rest of the session
+					name := theme.Next()
+					applyTheme()
+					banner.Show("Theme: "+name, time.Duration(3)*time.Second)
+				}
+
+			case keys.Portfolio:
 				if utilitySelected == "" {
 					selectedTable.ShowCursor = false
 					selectedTable = portfolioTable.Table
 					selectedTable.ShowCursor = true
-					portfolioTable.UpdateRows(portfolioMap, currency, currencyVal)
+					portfolioTable.UpdateRows(portfolioMap, currency)
 					utilitySelected = "PORTFOLIO"
 				}
 
 			// Navigations
-			case "j", "<Down>":
+			case keys.Down:
 				selectedTable.ScrollDown()
 
-			case "k", "<Up>":
+			case keys.Up:
 				selectedTable.ScrollUp()
 
-			case "<C-d>":
+			case keys.HalfPageDown:
 				selectedTable.ScrollHalfPageDown()
 
-			case "<C-u>":
+			case keys.HalfPageUp:
 				selectedTable.ScrollHalfPageUp()
 
-			case "<C-f>":
+			case keys.PageDown:
 				selectedTable.ScrollPageDown()
 
-			case "<C-b>":
+			case keys.PageUp:
 				selectedTable.ScrollPageUp()
 
-			case "g":
-				if previousKey == "g" {
-					selectedTable.ScrollTop()
-				}
-
-			case "<Home>":
+			case keys.Top:
 				selectedTable.ScrollTop()
 
-			case "G", "<End>":
+			case keys.Bottom:
 				selectedTable.ScrollBottom()
 
 			// Actions
-			case "<Enter>":
+			case keys.Select:
 				switch utilitySelected {
 				case "CHANGE":
 					// Update Graph Durations
 					if changeIntervalWidget.SelectedRow < len(changeIntervalWidget.Rows) {
 						row := changeIntervalWidget.Rows[changeIntervalWidget.SelectedRow]
 
-						// Get newer selected duration
-						changeInterval = row[0]
-						newChangeInterval := uw.IntervalMap[changeInterval]
-
-						// Empty current graph
-						page.ValueGraph.Data["Value"] = []float64{}
-
-						// Send Updated Interval
-						intervalChannel <- newChangeInterval
+						// Send newer selected duration
+						setInterval(row[0])
 					}
 					utilitySelected = ""
 
@@ -259,23 +1536,35 @@ func DisplayCoin(
 					if currencyWidget.SelectedRow < len(currencyWidget.Rows) {
 						row := currencyWidget.Rows[currencyWidget.SelectedRow]
 
-						// Get currency and rate
-						currencyID = row[0]
-						currencyID, currency, currencyVal = currencyWidget.Get(currencyID)
+						// Get currency
+						currency = currencyWidget.Get(row[0])
+						streamRate()
 
 						// Update currency fields
-						favHeader[1] = fmt.Sprintf("Price (%s)", currency)
+						favHeader[1] = fmt.Sprintf("Price (%s)", currency.Label())
+						convertShown()
+
+						// Persist currency
+						utils.SaveMetadata(favourites, currency.ID, portfolioMap)
 					}
 					utilitySelected = ""
 				}
 
-				if utilitySelected == "" {
+				if utilitySelected == "" && selectedTable == newsTable {
+					// Copy link of the selected headline
+					if newsTable.SelectedRow < len(headlineURLs) {
+						newsTable.Title = " News - link copied "
+						if err := utils.CopyToClipboard(headlineURLs[newsTable.SelectedRow]); err != nil {
+							newsTable.Title = " News - unable to copy link "
+						}
+					}
+				} else if utilitySelected == "" {
 					selectedTable.ShowCursor = false
 					selectedTable = page.ExplorerTable
 					selectedTable.ShowCursor = true
 				}
 
-			case "e":
+			case keys.Edit:
 				switch utilitySelected {
 				case "PORTFOLIO":
 					id := ""
@@ -303,43 +1592,62 @@ func DisplayCoin(
 								delete(portfolioMap, id)
 							}
 						}
+
+						// Persist the edit straight away, so it isn't lost if
+						// cryptgo is killed before the page is closed
+						utils.SaveMetadata(favourites, currency.ID, portfolioMap)
 					}
 
-					portfolioTable.UpdateRows(portfolioMap, currency, currencyVal)
+					portfolioTable.UpdateRows(portfolioMap, currency)
 				}
 			}
 
 			if utilitySelected == "" {
 				switch selectedTable {
+				case marketsTable:
+					switch action {
+					// Sort Ascending
+					case "1", "2", "3", "4", "5":
+						idx, _ := strconv.Atoi(action)
+						marketsSortIdx = idx - 1
+						marketsSortAsc = true
+						sortMarkets()
+
+					// Sort Descending
+					case "<F1>", "<F2>", "<F3>", "<F4>", "<F5>":
+						idx, _ := strconv.Atoi(action[2:3])
+						marketsSortIdx = idx - 1
+						marketsSortAsc = false
+						sortMarkets()
+					}
+
 				case page.FavouritesTable:
-					switch e.ID {
+					switch action {
 					// Sort Ascending
 					case "1", "2":
-						idx, _ := strconv.Atoi(e.ID)
+						idx, _ := strconv.Atoi(action)
 						favSortIdx = idx - 1
 						page.FavouritesTable.Header = append([]string{}, favHeader...)
 						page.FavouritesTable.Header[favSortIdx] = favHeader[favSortIdx] + " " + UP_ARROW
 						favSortAsc = true
-						utils.SortData(page.FavouritesTable.Rows, favSortIdx, favSortAsc, "FAVOURITES")
+						utils.SortData(page.FavouritesTable.Rows, favSortIdx, favSortAsc, utils.FavouritesLayout)
 
 					// Sort Descending
 					case "<F1>", "<F2>":
 						page.FavouritesTable.Header = append([]string{}, favHeader...)
-						idx, _ := strconv.Atoi(e.ID[2:3])
+						idx, _ := strconv.Atoi(action[2:3])
 						favSortIdx = idx - 1
 						page.FavouritesTable.Header[favSortIdx] = favHeader[favSortIdx] + " " + DOWN_ARROW
 						favSortAsc = false
-						utils.SortData(page.FavouritesTable.Rows, favSortIdx, favSortAsc, "FAVOURITES")
+						utils.SortData(page.FavouritesTable.Rows, favSortIdx, favSortAsc, utils.FavouritesLayout)
 					}
 				}
 			}
 
+			// Put off the screensaver till idle again after pages opened
+			// from here are closed
+			screensaver.Touch()
 			updateUI()
-			if previousKey == "g" {
-				previousKey = ""
-			} else {
-				previousKey = e.ID
-			}
 
 		case data := <-priceChannel:
 			// Update live price
@@ -349,86 +1657,174 @@ func DisplayCoin(
 				}
 			} else {
 				p, _ := strconv.ParseFloat(data, 64)
+				lastPrice = p
+				statsChanged = true
+
+				// Check move alerts against live prices, which are in USD
+				if triggered := alerts.CheckMoves(id, coinID.Symbol, p, time.Now()); len(triggered) > 0 {
+					messages := []string{}
+					for _, t := range triggered {
+						messages = append(messages, t.Message())
+					}
+					message := strings.Join(messages, " | ")
+					banner.Show(message, time.Duration(10)*time.Second)
+					go alerts.Notify("cryptgo", message, triggered...)
+				}
 				if utilitySelected == "" {
-					page.PriceBox.Rows[0][0] = fmt.Sprintf("%.2f", p/currencyVal)
-					ui.Render(page.PriceBox)
+					// Render on next render tick
+					page.PriceBox.Rows[0][0] = currency.Format(p)
+					priceChanged = true
 				}
 			}
 
+		case <-rateChannel:
+			// Update live price with the rate of the currency
+			if lastPrice > 0 && utilitySelected == "" {
+				page.PriceBox.Rows[0][0] = currency.Format(lastPrice)
+				priceChanged = true
+			}
+
+		case <-renderTick:
+			if priceChanged && utilitySelected == "" {
+				ui.Render(page.PriceBox)
+			}
+			priceChanged = false
+
 		case data := <-dataChannel:
 			switch data.Type {
 
 			case "FAVOURITES":
-				// Update favorites table
-				rows := [][]string{}
-				for symbol, price := range data.Favourites {
-					p := fmt.Sprintf("%.2f", price/currencyVal)
-					rows = append(rows, []string{symbol, p})
-				}
-				page.FavouritesTable.Header[1] = fmt.Sprintf("Price (%s)", currency)
-				page.FavouritesTable.Rows = rows
+				shown[data.Type] = data
+				showFavourites(data)
 
 			case "HISTORY":
+				// Ignore history priced in a previous quote or interval
+				if data.Quote != quote || data.Interval != uw.IntervalOf(changeInterval) {
+					break
+				}
+
 				// Update History graph
-				price := data.PriceHistory
+				history = data.PriceHistory
+				historyBase = data.MinPrice
+				yearAgo = data.YearAgo
+				drawHistory()
+
+				shown[data.Type] = data
+				labelHistory(data)
+				showStats(data)
+
+			case "CANDLES":
+				// Ignore candles of a previous timeframe
+				if !showCandles || data.Timeframe != candleTimeframe {
+					break
+				}
+
+				candles := []widgets.Candle{}
+				for _, c := range data.Candles {
+					candles = append(candles, widgets.Candle{Open: c.Open, High: c.High, Low: c.Low, Close: c.Close})
+				}
+				page.CandleChart.Candles = candles
+				page.CandleChart.EmptyText = "No Binance USDT candles for this coin"
 
-				// Set value, min & max price
-				page.ValueGraph.Data["Value"] = price
-				value := (price[len(price)-1] + data.MinPrice) / currencyVal
+				setCandlesTitle()
 
-				page.ValueGraph.Labels["Value"] = fmt.Sprintf("%.2f %s", value, currency)
-				page.ValueGraph.Labels["Max"] = fmt.Sprintf("%.2f %s", data.MaxPrice/currencyVal, currency)
-				page.ValueGraph.Labels["Min"] = fmt.Sprintf("%.2f %s", data.MinPrice/currencyVal, currency)
+			case "ORDERBOOK":
+				if !showBook {
+					break
+				}
 
-				// Update Graph title
-				page.ValueGraph.Title = fmt.Sprintf(" Value History (%s) ", changeInterval)
+				// Update order book
+				book.Book = data.OrderBook
+				book.EmptyText = "No Binance USDT market for this coin"
+				shown[data.Type] = data
+				setBookTitle()
 
-			case "DETAILS":
-				// Update Details table
-				page.DetailsTable.Header = []string{"Name", data.Details.Name}
+			case "MARKETS":
+				if !showMarkets {
+					break
+				}
 
-				marketCapVals, units := utils.RoundValues(data.Details.MarketCap, 0)
-				marketCap := fmt.Sprintf("%.2f %s %s", marketCapVals[0]/currencyVal, units, currency)
+				// Update markets
+				shown[data.Type] = data
+				showMarketRows(data)
 
-				ATHVals, units := utils.RoundValues(data.Details.ATH, 0)
-				ATH := fmt.Sprintf("%.2f %s %s", ATHVals[0]/currencyVal, units, currency)
+			case "VOLUME":
+				if !showVolume {
+					break
+				}
+
+				// Update volume breakdown
+				shown[data.Type] = data
+				showVolumeRows(data)
 
-				ATLVals, units := utils.RoundValues(data.Details.ATL, 0)
-				ATL := fmt.Sprintf("%.2f %s %s", ATLVals[0]/currencyVal, units, currency)
+			case "NEWS":
+				if !showNews {
+					break
+				}
 
-				TotalVolVals, units := utils.RoundValues(data.Details.TotalVolume, 0)
-				TotalVolume := fmt.Sprintf("%.2f %s %s", TotalVolVals[0]/currencyVal, units, currency)
+				// Update news, newest first
+				rows := [][]string{}
+				headlineURLs = []string{}
+				for _, headline := range data.News {
+					published := "-"
+					if !headline.Published.IsZero() {
+						published = headline.Published.Local().Format("Jan 02 15:04")
+					}
+					rows = append(rows, []string{published, headline.Source, headline.Title})
+					headlineURLs = append(headlineURLs, headline.URL)
+				}
+				newsTable.Rows = rows
+				newsTable.Title = " News "
+				if len(rows) == 0 {
+					newsTable.Title = " News - no recent headlines "
+				}
+				if data.Degraded != "" {
+					newsTable.Title = fmt.Sprintf(" News - %s ", data.Degraded)
+				}
 
-				rows := [][]string{
-					{"Symbol", data.Details.Symbol},
-					{"Rank", data.Details.Rank},
-					{"BlockTime (min)", data.Details.BlockTime},
-					{"MarketCap", marketCap},
-					{"ATH", ATH},
-					{"ATHDate", data.Details.ATHDate},
-					{"ATL", ATL},
-					{"ATLDate", data.Details.ATLDate},
-					{"TotalVolume", TotalVolume},
-					{"LastUpdate", data.Details.LastUpdate},
+			case "DETAILS":
+				// Check price alerts of coin
+				triggered := alerts.Check(id, data.Details.Symbol, data.Details.CurrentPrice, data.Details.Change24h)
+				if len(triggered) > 0 {
+					messages := []string{}
+					for _, t := range triggered {
+						messages = append(messages, t.Message())
+					}
+					message := strings.Join(messages, " | ")
+					banner.Show(message, time.Duration(10)*time.Second)
+					go alerts.Notify("cryptgo", message, triggered...)
 				}
 
-				page.DetailsTable.Rows = rows
+				// Update Performance strip
+				chips := []widgets.Chip{}
+				for _, p := range data.Details.Performance {
+					chips = append(chips, widgets.Chip{Label: p.Label, Change: p.Change})
+				}
+				page.PerformanceStrip.Chips = chips
 
-				// Update 24 High/Low
-				page.PriceBox.Rows[0][1] = fmt.Sprintf("%.2f", data.Details.High24/currencyVal)
-				page.PriceBox.Rows[0][2] = fmt.Sprintf("%.2f", data.Details.Low24/currencyVal)
-				page.PriceBox.Title = fmt.Sprintf(" Live Price (%s) ", currency)
+				// Update Details table and prices
+				shown[data.Type] = data
+				showDetails(data)
+				statsChanged = true
 
 				// Get Change Percents
 				page.ChangesTable.Rows = data.Details.ChangePercents
 
 				// Get supply and Max supply
 				supply := data.Details.CurrentSupply
-				maxSupply := data.Details.TotalSupply
+				maxSupply := data.Details.MaxSupply
 
 				supplyVals, units := utils.RoundValues(supply, maxSupply)
-				page.SupplyChart.Data = supplyVals
-				page.SupplyChart.Title = fmt.Sprintf(" Supply (%s) ", units)
+				if data.Details.HasMaxSupply {
+					page.SupplyChart.Data = supplyVals
+					page.SupplyChart.Labels = []string{"Supply", "Max Supply"}
+					page.SupplyChart.Title = fmt.Sprintf(" Supply (%s) ", units)
+				} else {
+					// Show circulating supply alone when there is no max supply
+					page.SupplyChart.Data = supplyVals[:1]
+					page.SupplyChart.Labels = []string{"Supply"}
+					page.SupplyChart.Title = fmt.Sprintf(" Supply (%s) - ∞ max ", units)
+				}
 
 				// Get Explorers
 				page.ExplorerTable.Rows = data.Details.Explorers
@@ -436,20 +1832,83 @@ func DisplayCoin(
 			}
 
 			// Sort favourites table
-			if favSortIdx != -1 {
-				utils.SortData(page.FavouritesTable.Rows, favSortIdx, favSortAsc, "FAVOURITES")
+			sortFavourites()
 
-				if favSortAsc {
-					page.FavouritesTable.Header[favSortIdx] = favHeader[favSortIdx] + " " + UP_ARROW
-				} else {
-					page.FavouritesTable.Header[favSortIdx] = favHeader[favSortIdx] + " " + DOWN_ARROW
+		case <-tick: // Refresh UI
+			// Show the screensaver once idle
+			if screensaver.Due() && utilitySelected != "IDLE" {
+				idleReturn = utilitySelected
+				utilitySelected = "IDLE"
+				utils.SetIdle(true)
+			}
+			if interval := utils.RenderInterval(); interval != renderInterval {
+				renderInterval = interval
+				r.Reset(renderInterval)
+			}
+			setPriceTitle()
+			updateUI()
+
+			// Write quick stats for bots and scripts tailing them
+			if statsChanged && utils.StatsOutput != "" {
+				if line, ok := quickStats(); ok {
+					utils.WriteStats(line)
 				}
+			}
+			statsChanged = false
+		}
+	}
+}
+
+// DateLabels returns labels of the start, middle and end of history, with
+// times of day if it spans a couple of days at most. Points without a time
+// are left unlabelled.
+func DateLabels(times []time.Time) map[int]string {
+	labels := map[int]string{}
+	if len(times) < 2 {
+		return labels
+	}
+
+	layout := api.RangeLayout
+	if times[len(times)-1].Sub(times[0]) <= time.Duration(48)*time.Hour {
+		layout = "01-02 15:04"
+	}
+
+	for _, i := range []int{0, (len(times) - 1) / 2, len(times) - 1} {
+		if !times[i].IsZero() {
+			labels[i] = times[i].Local().Format(layout)
+		}
+	}
+
+	return labels
+}
+
+// logPrices replaces prices of series with their orders of magnitude, drawn
+// from 0 for the lowest price to 100 for the highest. Prices which aren't
+// positive are drawn as the lowest.
+func logPrices(series ...[]float64) {
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, prices := range series {
+		for i, p := range prices {
+			if p > 0 {
+				prices[i] = math.Log10(p)
+				low = math.Min(low, prices[i])
+				high = math.Max(high, prices[i])
 			} else {
-				utils.SortData(page.FavouritesTable.Rows, 0, true, "FAVOURITES")
+				prices[i] = math.Inf(-1)
 			}
+		}
+	}
 
-		case <-tick: // Refresh UI
-			updateUI()
+	for _, prices := range series {
+		for i, p := range prices {
+			switch {
+			case math.IsInf(low, 1) || math.IsInf(p, -1):
+				prices[i] = 0
+			case high > low:
+				prices[i] = (p - low) / (high - low) * 100
+			default:
+				prices[i] = 50
+			}
 		}
 	}
 }
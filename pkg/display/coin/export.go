@@ -0,0 +1,203 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coin
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+	"github.com/Gituser143/cryptgo/pkg/utils"
+)
+
+// Size of graphs exported to PNG, in pixels
+const (
+	exportWidth  = 1200
+	exportHeight = 600
+)
+
+// exportView is a snapshot of the coin page, exported to share or analyse
+// it. Prices are in USD, but for history priced in a quote coin.
+type exportView struct {
+	Created    time.Time         `json:"created"`
+	Details    api.QuickStats    `json:"details"`
+	Favourites []exportFavourite `json:"favourites"`
+	History    exportHistory     `json:"history"`
+}
+
+// exportFavourite is a row of the favourites table
+type exportFavourite struct {
+	Symbol   string  `json:"symbol"`
+	PriceUSD float64 `json:"price_usd"`
+}
+
+// exportHistory is the price history shown on the value graph
+type exportHistory struct {
+	Interval string        `json:"interval"`
+	Quote    string        `json:"quote"` // USD unless in pair mode
+	Points   []exportPoint `json:"points"`
+}
+
+// exportPoint is a price of history at a point in time
+type exportPoint struct {
+	Time  time.Time `json:"time"`
+	Price float64   `json:"price"`
+}
+
+// newExportView returns a snapshot of data shown on the coin page, once
+// its details are fetched
+func newExportView(id api.CoinID, shown map[string]api.CoinData, price float64, source, quoteSymbol string) (exportView, error) {
+	details, ok := shown["DETAILS"]
+	if !ok {
+		return exportView{}, fmt.Errorf("details not fetched yet")
+	}
+
+	view := exportView{
+		Created:    time.Now(),
+		Details:    api.NewQuickStats(id, details.Details, price, source),
+		Favourites: []exportFavourite{},
+		History:    exportHistory{Quote: "USD", Points: []exportPoint{}},
+	}
+
+	for symbol, price := range shown["FAVOURITES"].Favourites {
+		view.Favourites = append(view.Favourites, exportFavourite{Symbol: symbol, PriceUSD: price})
+	}
+	sort.Slice(view.Favourites, func(i, j int) bool {
+		return view.Favourites[i].Symbol < view.Favourites[j].Symbol
+	})
+
+	// History is sent offset by its lowest price
+	if history, ok := shown["HISTORY"]; ok {
+		view.History.Interval = history.Interval
+		if history.Quote != (api.CoinID{}) {
+			view.History.Quote = quoteSymbol
+		}
+		for _, p := range history.PriceHistory {
+			view.History.Points = append(view.History.Points, exportPoint{
+				Time:  p.Time.UTC(),
+				Price: p.Price + history.MinPrice,
+			})
+		}
+	}
+
+	return view, nil
+}
+
+// writeExport writes a snapshot of the coin page to a timestamped file in
+// the home directory, in the export format, and its history to a PNG if
+// enabled. Paths of the files written are returned.
+func writeExport(view exportView) ([]string, error) {
+	name := view.Details.ID
+	path, err := utils.ExportPath(name, view.Created, utils.ExportFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	if utils.ExportFormat == utils.ExportCSV {
+		err = writeExportCSV(f, view)
+	} else {
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(view)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, err
+	}
+	paths := []string{path}
+
+	if utils.ExportPNG && len(view.History.Points) > 1 {
+		path, err := utils.ExportPath(name, view.Created, "png")
+		if err != nil {
+			return paths, err
+		}
+		f, err := os.Create(path)
+		if err != nil {
+			return paths, err
+		}
+
+		prices := make([]float64, len(view.History.Points))
+		for i, p := range view.History.Points {
+			prices[i] = p.Price
+		}
+		err = utils.PlotPNG(f, prices, exportWidth, exportHeight)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
+
+// writeExportCSV writes a snapshot as CSV tables of details, favourites and
+// history, separated by blank lines
+func writeExportCSV(f *os.File, view exportView) error {
+	w := csv.NewWriter(f)
+	format := func(val float64) string {
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	}
+
+	d := view.Details
+	w.Write([]string{"field", "value"})
+	w.WriteAll([][]string{
+		{"time", d.Time.Format(time.RFC3339)},
+		{"id", d.ID},
+		{"symbol", d.Symbol},
+		{"name", d.Name},
+		{"rank", strconv.Itoa(d.Rank)},
+		{"price_usd", format(d.PriceUSD)},
+		{"change_24h_pct", format(d.Change24hPct)},
+		{"high_24h_usd", format(d.High24hUSD)},
+		{"low_24h_usd", format(d.Low24hUSD)},
+		{"market_cap_usd", format(d.MarketCapUSD)},
+		{"volume_24h_usd", format(d.Volume24hUSD)},
+		{"ath_usd", format(d.ATHUSD)},
+		{"source", d.Source},
+	})
+	f.WriteString("\n")
+
+	w.Write([]string{"symbol", "price_usd"})
+	for _, fav := range view.Favourites {
+		w.Write([]string{fav.Symbol, format(fav.PriceUSD)})
+	}
+	w.Flush()
+	f.WriteString("\n")
+
+	w.Write([]string{"time", "price_" + strings.ToLower(view.History.Quote)})
+	for _, p := range view.History.Points {
+		w.Write([]string{p.Time.Format(time.RFC3339), format(p.Price)})
+	}
+	w.Flush()
+
+	return w.Error()
+}
@@ -17,69 +17,86 @@ limitations under the License.
 package coin
 
 import (
+	"image"
+
+	"github.com/Gituser143/cryptgo/pkg/display/layout"
+	"github.com/Gituser143/cryptgo/pkg/display/theme"
+	"github.com/Gituser143/cryptgo/pkg/display/utilitywidgets"
 	"github.com/Gituser143/cryptgo/pkg/widgets"
 	ui "github.com/gizak/termui/v3"
 )
 
+// stripHeight is the number of rows taken by the performance strip
+const stripHeight = 3
+
 // coinPage holds UI items for a coin page
 type coinPage struct {
-	Grid            *ui.Grid
-	FavouritesTable *widgets.Table
-	ValueGraph      *widgets.LineGraph
-	DetailsTable    *widgets.Table
-	ChangesTable    *widgets.Table
-	PriceBox        *widgets.Table
-	ExplorerTable   *widgets.Table
-	SupplyChart     *widgets.BarChart
+	Grid             *ui.Grid
+	PerformanceStrip *widgets.ChipStrip
+	FavouritesTable  *widgets.Table
+	ValueGraph       *widgets.LineGraph
+	CandleChart      *widgets.CandleChart
+	DetailsTable     *widgets.Table
+	StatsTable       *widgets.Table
+	ChangesTable     *widgets.Table
+	PriceBox         *widgets.Table
+	ExplorerTable    *widgets.Table
+	SupplyChart      *widgets.BarChart
+	StatusBar        *utilitywidgets.StatusBar
+
+	profile string          // Layout profile the grid is set for
+	hidden  map[string]bool // Widgets hidden by the profile
 }
 
 // newcoinPage creates, initialises and returns a pointer to an instance of coinPage
-func newCoinPage() *coinPage {
+func newCoinPage(w, h int) *coinPage {
 	page := &coinPage{
-		Grid:            ui.NewGrid(),
-		FavouritesTable: widgets.NewTable(),
-		ValueGraph:      widgets.NewLineGraph(),
-		DetailsTable:    widgets.NewTable(),
-		ChangesTable:    widgets.NewTable(),
-		PriceBox:        widgets.NewTable(),
-		ExplorerTable:   widgets.NewTable(),
-		SupplyChart:     widgets.NewBarChart(),
+		Grid:             ui.NewGrid(),
+		PerformanceStrip: widgets.NewChipStrip(),
+		FavouritesTable:  widgets.NewTable(),
+		ValueGraph:       widgets.NewLineGraph(),
+		CandleChart:      widgets.NewCandleChart(),
+		DetailsTable:     widgets.NewTable(),
+		StatsTable:       widgets.NewTable(),
+		ChangesTable:     widgets.NewTable(),
+		PriceBox:         widgets.NewTable(),
+		ExplorerTable:    widgets.NewTable(),
+		SupplyChart:      widgets.NewBarChart(),
+		StatusBar:        utilitywidgets.NewStatusBar(),
 	}
-	page.init()
+	page.init(w, h)
 
 	return page
 }
 
 // init initialises the widgets of an coinPage
-func (page *coinPage) init() {
+func (page *coinPage) init(w, h int) {
+	// Initialise Performance strip
+	page.PerformanceStrip.Title = " Performance "
+
 	// Initialise Favourites table
 	page.FavouritesTable.Title = " Favourites "
-	page.FavouritesTable.BorderStyle.Fg = ui.ColorCyan
-	page.FavouritesTable.TitleStyle.Fg = ui.ColorClear
-	page.FavouritesTable.Header = []string{"Symbol", "Price"}
+	page.FavouritesTable.Header = []string{"Symbol", "Price", "7D"}
 	page.FavouritesTable.ColResizer = func() {
 		x := page.FavouritesTable.Inner.Dx()
 		page.FavouritesTable.ColWidths = []int{
+			3 * x / 10,
 			4 * x / 10,
-			6 * x / 10,
+			3 * x / 10,
 		}
 	}
-	page.FavouritesTable.CursorColor = ui.ColorCyan
 
 	// Initialise Value Graph
-	page.ValueGraph.TitleStyle = ui.NewStyle(ui.ColorClear)
 	page.ValueGraph.HorizontalScale = 1
-	page.ValueGraph.LineColors["Max"] = ui.ColorGreen
-	page.ValueGraph.LineColors["Min"] = ui.ColorRed
-	page.ValueGraph.LineColors["Value"] = ui.ColorBlue
-	page.ValueGraph.BorderStyle.Fg = ui.ColorCyan
+	page.ValueGraph.MarkerSeries = "Value"
 	page.ValueGraph.Data["Max"] = []float64{}
 	page.ValueGraph.Data["Min"] = []float64{}
 
+	// Initialise Candle Chart, drawn in place of the Value Graph
+	page.CandleChart.EmptyText = "Fetching candles..."
+
 	// Initialise Details Table
 	page.DetailsTable.Title = " Details "
-	page.DetailsTable.BorderStyle.Fg = ui.ColorCyan
-	page.DetailsTable.TitleStyle.Fg = ui.ColorClear
 	page.DetailsTable.ColResizer = func() {
 		x := page.DetailsTable.Inner.Dx()
 		page.DetailsTable.ColWidths = []int{
@@ -88,10 +105,20 @@ func (page *coinPage) init() {
 		}
 	}
 
+	// Initialise Stats Table
+	page.StatsTable.Title = " Statistics "
+	page.StatsTable.ColResizer = func() {
+		x := page.StatsTable.Inner.Dx()
+		page.StatsTable.ColWidths = []int{
+			6 * x / 10,
+			4 * x / 10,
+		}
+	}
+	page.StatsTable.ShowCursor = false
+	page.StatsTable.Rows = [][]string{{"Fetching history...", ""}}
+
 	// Initialise Change Table
 	page.ChangesTable.Title = " Changes "
-	page.ChangesTable.BorderStyle.Fg = ui.ColorCyan
-	page.ChangesTable.BorderStyle.Bg = ui.ColorClear
 	page.ChangesTable.Header = []string{"Interval", "Change"}
 	page.ChangesTable.ColResizer = func() {
 		x := page.ChangesTable.Inner.Dx()
@@ -105,8 +132,6 @@ func (page *coinPage) init() {
 
 	// Initialise Price Box
 	page.PriceBox.Title = " Live Price "
-	page.PriceBox.BorderStyle.Fg = ui.ColorCyan
-	page.PriceBox.TitleStyle.Fg = ui.ColorClear
 	page.PriceBox.Header = []string{"Price", "24H High", "24H Low"}
 	page.PriceBox.ColResizer = func() {
 		x := page.PriceBox.Inner.Dx()
@@ -117,52 +142,141 @@ func (page *coinPage) init() {
 		}
 	}
 	page.PriceBox.Rows = [][]string{{"NA", "", ""}}
-	page.PriceBox.ColColor[1] = ui.ColorGreen
-	page.PriceBox.ColColor[2] = ui.ColorRed
 
 	// Initialise Explorer Table
 	page.ExplorerTable.Title = " Explorers "
-	page.ExplorerTable.BorderStyle.Fg = ui.ColorCyan
-	page.ExplorerTable.TitleStyle.Fg = ui.ColorClear
 	page.ExplorerTable.Header = []string{"Links"}
 	page.ExplorerTable.ColResizer = func() {
 		x := page.ExplorerTable.Inner.Dx()
 		page.ExplorerTable.ColWidths = []int{x}
 	}
-	page.ExplorerTable.CursorColor = ui.ColorCyan
 
 	// Initalise Bar Graph
 	page.SupplyChart.Title = " Supply "
 	page.SupplyChart.Data = []float64{0, 0}
 	page.SupplyChart.Labels = []string{"Supply", "Max Supply"}
-	page.SupplyChart.BorderStyle.Fg = ui.ColorCyan
-	page.SupplyChart.TitleStyle.Fg = ui.ColorClear
 	page.SupplyChart.BarWidth = 9
-	page.SupplyChart.BarColors = []ui.Color{ui.ColorGreen, ui.ColorCyan}
-	page.SupplyChart.LabelStyles = []ui.Style{ui.NewStyle(ui.ColorClear)}
-	page.SupplyChart.NumStyles = []ui.Style{ui.NewStyle(ui.ColorBlack)}
 
 	// Set Grid layout
-	w, h := ui.TerminalDimensions()
-	page.Grid.Set(
-		ui.NewCol(0.33,
-			ui.NewRow(0.5, page.FavouritesTable),
-			ui.NewRow(0.5, page.DetailsTable),
-		),
-		ui.NewCol(0.67,
-			ui.NewRow(0.5, page.ValueGraph),
-			ui.NewRow(0.5,
-				ui.NewCol(0.5,
-					ui.NewRow(0.4, page.PriceBox),
-					ui.NewRow(0.6, page.ChangesTable),
-				),
-				ui.NewCol(0.5,
-					ui.NewRow(0.5, page.ExplorerTable),
-					ui.NewRow(0.5, page.SupplyChart),
-				),
-			),
-		),
-	)
-
-	page.Grid.SetRect(0, 0, w, h)
+	page.resize(w, h)
+
+	page.applyTheme()
+}
+
+// resize fits the performance strip, grid and status bar to a terminal w
+// columns wide and h rows tall, laying out the page again if the terminal's
+// layout profile changed
+func (page *coinPage) resize(w, h int) {
+	if profile := layout.Profile(w, h); profile != page.profile {
+		page.profile = profile
+		page.hidden = layout.Hidden(profile, "coin")
+		page.layout()
+	}
+
+	// Performance strip takes the top rows, status bar the last row and
+	// grid the rest
+	top, bottom := 0, h
+	if !page.hidden["strip"] {
+		top = stripHeight
+	}
+	if !page.hidden["status"] {
+		bottom = h - 1
+	}
+	page.PerformanceStrip.SetRect(0, 0, w, stripHeight)
+	page.StatusBar.SetRect(0, h-1, w, h)
+	page.Grid.SetRect(0, top, w, bottom)
+}
+
+// layout sets a new grid of the widgets of a coinPage, leaving out hidden
+// widgets
+func (page *coinPage) layout() {
+	side := layout.Split(ui.NewRow, []layout.Item{
+		{Name: "favourites", Ratio: 0.4, Entry: page.FavouritesTable},
+		{Name: "details", Ratio: 0.4, Entry: page.DetailsTable},
+		{Name: "stats", Ratio: 0.2, Entry: page.StatsTable},
+	}, page.hidden)
+
+	prices := layout.Split(ui.NewRow, []layout.Item{
+		{Ratio: 0.4, Entry: page.PriceBox},
+		{Name: "changes", Ratio: 0.6, Entry: page.ChangesTable},
+	}, page.hidden)
+
+	links := layout.Split(ui.NewRow, []layout.Item{
+		{Name: "explorers", Ratio: 0.5, Entry: page.ExplorerTable},
+		{Name: "supply", Ratio: 0.5, Entry: page.SupplyChart},
+	}, page.hidden)
+
+	graph := layout.Split(ui.NewRow, []layout.Item{
+		{Ratio: 0.5, Entry: page.ValueGraph},
+		{Ratio: 0.5, Entry: layout.Split(ui.NewCol, []layout.Item{
+			{Ratio: 0.5, Entry: prices},
+			{Ratio: 0.5, Entry: links},
+		}, page.hidden)},
+	}, page.hidden)
+
+	page.Grid = ui.NewGrid()
+	page.Grid.Set(layout.Split(ui.NewCol, []layout.Item{
+		{Ratio: 0.33, Entry: side},
+		{Ratio: 0.67, Entry: graph},
+	}, page.hidden)...)
+}
+
+// bookRect returns the corners of the order book, drawn over explorers and
+// supply which are shown, or over prices and changes if both are hidden
+func (page *coinPage) bookRect() (int, int, int, int) {
+	under := []image.Rectangle{}
+	if !page.hidden["explorers"] {
+		under = append(under, page.ExplorerTable.GetRect())
+	}
+	if !page.hidden["supply"] {
+		under = append(under, page.SupplyChart.GetRect())
+	}
+	if len(under) == 0 {
+		under = append(under, page.PriceBox.GetRect())
+		if !page.hidden["changes"] {
+			under = append(under, page.ChangesTable.GetRect())
+		}
+	}
+
+	r := under[0]
+	for _, rect := range under[1:] {
+		r = r.Union(rect)
+	}
+	return r.Min.X, r.Min.Y, r.Max.X, r.Max.Y
+}
+
+// newsRect returns the corners of the news table, drawn over details, or
+// over favourites or the value graph if details are hidden
+func (page *coinPage) newsRect() (int, int, int, int) {
+	r := page.DetailsTable.GetRect()
+	if page.hidden["details"] {
+		r = page.FavouritesTable.GetRect()
+		if page.hidden["favourites"] {
+			r = page.ValueGraph.GetRect()
+		}
+	}
+	return r.Min.X, r.Min.Y, r.Max.X, r.Max.Y
+}
+
+// applyTheme colours the widgets of a coinPage with the theme in use
+func (page *coinPage) applyTheme() {
+	t := theme.Current()
+
+	t.Block(page.PerformanceStrip.Block)
+	t.Table(page.FavouritesTable)
+	t.LineGraph(page.ValueGraph, t.Line)
+	t.Block(page.CandleChart.Block)
+	page.CandleChart.UpColor = t.Up
+	page.CandleChart.DownColor = t.Down
+	t.Table(page.DetailsTable)
+	t.Table(page.StatsTable)
+	t.Table(page.ChangesTable)
+	t.Table(page.PriceBox)
+	page.PriceBox.ColColor[1] = t.Up
+	page.PriceBox.ColColor[2] = t.Down
+	t.Table(page.ExplorerTable)
+	t.Block(&page.SupplyChart.Block)
+	page.SupplyChart.BarColors = []ui.Color{t.Up, t.Accent}
+	page.SupplyChart.LabelStyles = []ui.Style{ui.NewStyle(t.Title)}
+	page.SupplyChart.NumStyles = []ui.Style{ui.NewStyle(t.Text)}
 }
@@ -0,0 +1,155 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+	"github.com/Gituser143/cryptgo/pkg/display/layout/layouttest"
+	uw "github.com/Gituser143/cryptgo/pkg/display/utilitywidgets"
+	"github.com/Gituser143/cryptgo/pkg/utils"
+	"github.com/Gituser143/cryptgo/pkg/widgets"
+	ui "github.com/gizak/termui/v3"
+)
+
+// sparkline returns a sparkline of favourites of n points of fixture history
+func sparkline(n int) string {
+	return utils.Sparkline(layouttest.Series(n, 0, 1), favouriteSparklineWidth)
+}
+
+// fillCoinPage fills the page with details, history and favourites of
+// bitcoin as fetched from CoinGecko
+func fillCoinPage(page *coinPage) {
+	page.PerformanceStrip.Chips = []widgets.Chip{
+		{Label: "1H", Change: 0.42},
+		{Label: "24H", Change: 2.41},
+		{Label: "7D", Change: -1.18},
+		{Label: "30D", Change: 8.73},
+		{Label: "1Y", Change: 61.05},
+	}
+
+	page.FavouritesTable.Header[1] = "Price (USD)"
+	page.FavouritesTable.Rows = [][]string{
+		{"BTC", "43250.12", sparkline(168)},
+		{"ETH", "2291.84", sparkline(150)},
+		{"SOL", "98.67", sparkline(90)},
+	}
+	page.FavouritesTable.Footer = uw.FavouritesFooter(api.FavouriteStats{
+		Count:            3,
+		MarketCap:        1.16e12,
+		AverageChange24h: 1.12,
+		Advancing:        2,
+		Declining:        1,
+	}, uw.USD)
+
+	page.ValueGraph.Title = " Value History (7 Days) "
+	page.ValueGraph.Data["Value"] = layouttest.Series(336, 0, 43980.10-41020.55)
+	page.ValueGraph.Labels["Value"] = "43250.12 USD"
+	page.ValueGraph.Labels["Max"] = "43980.10 USD"
+	page.ValueGraph.Labels["Min"] = "41020.55 USD"
+
+	page.DetailsTable.Header = []string{"Name", "Bitcoin"}
+	page.DetailsTable.Rows = [][]string{
+		{"Symbol", "BTC"},
+		{"Rank", "1"},
+		{"BlockTime (min)", "10"},
+		{"MarketCap", "846.82 B USD"},
+		{"ATH", "69.04 K USD"},
+		{"ATHDate", "10 Nov 2021"},
+		{"ATL", "67.81 USD"},
+		{"ATLDate", "06 Jul 2013"},
+		{"TotalVolume", "21.37 B USD"},
+		{"LastUpdate", "12 Jan 2024 09:30"},
+		{"Refresh Priority", "normal"},
+		{"Source", "coingecko"},
+		{"Alerts", "None"},
+	}
+
+	page.StatsTable.Title = " Statistics (14d) "
+	page.StatsTable.Rows = [][]string{
+		{"Return", "4.72%"},
+		{"Volatility (ann.)", "38.15%"},
+		{"Max Drawdown", "-6.73%"},
+		{"Sharpe (ann.)", "1.84"},
+	}
+
+	page.PriceBox.Title = " Live Price (USD) - source: coingecko "
+	page.PriceBox.Rows = [][]string{{"43250.12", "43612.90", "42011.37"}}
+
+	page.ChangesTable.Rows = [][]string{
+		{"24H", "▲ 2.41"},
+		{"7D", "▼ 1.18"},
+		{"14D", "▲ 4.06"},
+		{"30D", "▲ 8.73"},
+		{"60D", "▲ 22.40"},
+		{"200D", "▲ 71.92"},
+		{"1Y", "▲ 61.05"},
+	}
+
+	page.SupplyChart.Title = " Supply (M) "
+	page.SupplyChart.Data = []float64{19.58, 21}
+
+	now := time.Now()
+	page.StatusBar.Source = "coingecko"
+	page.StatusBar.Statuses = []api.Status{
+		{Type: "history", Every: time.Minute, Updated: now.Add(-12 * time.Second)},
+		{Type: "details", Every: time.Minute, Updated: now.Add(-3 * time.Second)},
+		{Type: "favourites", Every: 10 * time.Second, Updated: now.Add(-2 * time.Second)},
+	}
+
+	page.ExplorerTable.Rows = [][]string{
+		{"https://blockchair.com/bitcoin/"},
+		{"https://btc.com/"},
+		{"https://btc.tokenview.io/"},
+	}
+}
+
+// coinItems returns widgets of the page in the order they're drawn, with
+// the supply chart's bars spaced as on resize
+func coinItems(page *coinPage, w int) []ui.Drawable {
+	page.SupplyChart.BarGap = ((w / 3) - (2 * page.SupplyChart.BarWidth)) / 2
+
+	items := []ui.Drawable{page.Grid}
+	if !page.hidden["strip"] {
+		items = append(items, page.PerformanceStrip)
+	}
+	if !page.hidden["status"] {
+		items = append(items, page.StatusBar)
+	}
+	return items
+}
+
+func TestLayout(t *testing.T) {
+	layouttest.Run(t, []layouttest.Case{
+		{
+			Name: "coin_fetching",
+			Page: func(w, h int) []ui.Drawable {
+				return coinItems(newCoinPage(w, h), w)
+			},
+		},
+		{
+			Name: "coin",
+			Page: func(w, h int) []ui.Drawable {
+				page := newCoinPage(w, h)
+				fillCoinPage(page)
+				return coinItems(page, w)
+			},
+		},
+	})
+}
@@ -0,0 +1,163 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coin
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+	"github.com/Gituser143/cryptgo/pkg/display/theme"
+	ui "github.com/gizak/termui/v3"
+)
+
+// orderBook shows the top bids and asks of a coin side by side, over a
+// depth chart of their cumulative quantity. Bids grow left and asks grow
+// right from the centre.
+type orderBook struct {
+	*ui.Block
+
+	Book api.OrderBook
+
+	// Format formats prices shown in the book
+	Format func(float64) string
+
+	// EmptyText is shown when the book has no orders
+	EmptyText string
+}
+
+// newOrderBook creates and returns an orderBook instance
+func newOrderBook() *orderBook {
+	b := &orderBook{
+		Block: ui.NewBlock(),
+		Format: func(val float64) string {
+			return fmt.Sprintf("%.2f", val)
+		},
+		EmptyText: "Fetching order book...",
+	}
+	b.Title = " Order Book "
+
+	return b
+}
+
+// Spread returns the spread between the best ask and bid in percent of the
+// best ask, and false if either side is empty
+func (b *orderBook) Spread() (float64, bool) {
+	if len(b.Book.Bids) == 0 || len(b.Book.Asks) == 0 || b.Book.Asks[0].Price == 0 {
+		return 0, false
+	}
+	ask, bid := b.Book.Asks[0].Price, b.Book.Bids[0].Price
+	return (ask - bid) / ask * 100, true
+}
+
+func (b *orderBook) Draw(buf *ui.Buffer) {
+	// Follow the theme in use, which may change while the book is shown
+	t := theme.Current()
+	t.Block(b.Block)
+	b.Block.Draw(buf)
+
+	width, height := b.Inner.Dx(), b.Inner.Dy()
+	if width < 2 || height < 1 {
+		return
+	}
+
+	if len(b.Book.Bids) == 0 && len(b.Book.Asks) == 0 {
+		x := b.Inner.Min.X + (width-len(b.EmptyText))/2
+		y := b.Inner.Min.Y + height/2
+		buf.SetString(b.EmptyText, ui.NewStyle(ui.ColorClear), image.Pt(x, y))
+		return
+	}
+
+	centre := b.Inner.Min.X + width/2
+	headerStyle := ui.NewStyle(ui.ColorClear, ui.ColorClear, ui.ModifierBold)
+	buf.SetString("Bids", headerStyle, image.Pt(centre-5, b.Inner.Min.Y))
+	buf.SetString("Asks", headerStyle, image.Pt(centre+1, b.Inner.Min.Y))
+
+	// Show as many levels as fit below the header
+	rows := height - 1
+	bids, asks := b.Book.Bids, b.Book.Asks
+	if len(bids) > rows {
+		bids = bids[:rows]
+	}
+	if len(asks) > rows {
+		asks = asks[:rows]
+	}
+
+	// Depth bars are scaled to the larger cumulative quantity of both sides
+	cumulative := func(levels []api.OrderLevel) []float64 {
+		sums := make([]float64, len(levels))
+		sum := 0.0
+		for i, level := range levels {
+			sum += level.Quantity
+			sums[i] = sum
+		}
+		return sums
+	}
+	bidDepth, askDepth := cumulative(bids), cumulative(asks)
+	maxDepth := 0.0
+	if len(bidDepth) > 0 {
+		maxDepth = bidDepth[len(bidDepth)-1]
+	}
+	if len(askDepth) > 0 && askDepth[len(askDepth)-1] > maxDepth {
+		maxDepth = askDepth[len(askDepth)-1]
+	}
+
+	leftWidth, rightWidth := centre-b.Inner.Min.X, b.Inner.Max.X-centre
+	barLength := func(depth float64, sideWidth int) int {
+		if maxDepth == 0 {
+			return 0
+		}
+		return int(depth / maxDepth * float64(sideWidth))
+	}
+
+	for i, level := range bids {
+		y := b.Inner.Min.Y + 1 + i
+		bar := barLength(bidDepth[i], leftWidth)
+		text := fmt.Sprintf("%.4f %s ", level.Quantity, b.Format(level.Price))
+		textStart := centre - len([]rune(text))
+
+		for x := b.Inner.Min.X; x < centre; x++ {
+			char := ' '
+			if x >= textStart {
+				char = []rune(text)[x-textStart]
+			}
+			style := ui.NewStyle(t.Up)
+			if centre-x <= bar {
+				style = ui.NewStyle(t.Text, t.Up)
+			}
+			buf.SetCell(ui.NewCell(char, style), image.Pt(x, y))
+		}
+	}
+
+	for i, level := range asks {
+		y := b.Inner.Min.Y + 1 + i
+		bar := barLength(askDepth[i], rightWidth)
+		text := []rune(fmt.Sprintf(" %s %.4f", b.Format(level.Price), level.Quantity))
+
+		for x := centre; x < b.Inner.Max.X; x++ {
+			char := ' '
+			if x-centre < len(text) {
+				char = text[x-centre]
+			}
+			style := ui.NewStyle(t.Down)
+			if x-centre < bar {
+				style = ui.NewStyle(t.Text, t.Down)
+			}
+			buf.SetCell(ui.NewCell(char, style), image.Pt(x, y))
+		}
+	}
+}
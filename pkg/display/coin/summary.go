@@ -0,0 +1,62 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coin
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+	uw "github.com/Gituser143/cryptgo/pkg/display/utilitywidgets"
+	"github.com/Gituser143/cryptgo/pkg/utils"
+)
+
+// sparklineWidth is the number of characters the 7 day sparkline spans, and
+// favouriteSparklineWidth that of sparklines of the favourites table
+const (
+	sparklineWidth          = 28
+	favouriteSparklineWidth = 8
+)
+
+// getSummary returns a text summary of a coin, formatted to be pasted into
+// chats. Values are shown in the given currency.
+func getSummary(id api.CoinID, currency uw.Currency) (string, error) {
+	data, err := api.GetCoinSummary(id)
+	if err != nil {
+		return "", err
+	}
+
+	change := fmt.Sprintf("%s %.2f%%", UP_ARROW, data.PriceChangePercentage24h)
+	if data.PriceChangePercentage24h < 0 {
+		change = fmt.Sprintf("%s %.2f%%", DOWN_ARROW, -data.PriceChangePercentage24h)
+	}
+
+	sparkline := "NA"
+	if data.SparklineIn7d != nil && len(data.SparklineIn7d.Price) > 0 {
+		sparkline = utils.Sparkline(data.SparklineIn7d.Price, sparklineWidth)
+	}
+
+	lines := []string{
+		fmt.Sprintf("%s (%s) #%d", data.Name, strings.ToUpper(data.Symbol), data.MarketCapRank),
+		fmt.Sprintf("Price: %s", currency.Money(data.CurrentPrice)),
+		fmt.Sprintf("24h: %s", change),
+		fmt.Sprintf("Market Cap: %s", currency.CompactMoney(data.MarketCap)),
+		fmt.Sprintf("7d: %s", sparkline),
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
@@ -0,0 +1,295 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compare
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+	uw "github.com/Gituser143/cryptgo/pkg/display/utilitywidgets"
+	"github.com/Gituser143/cryptgo/pkg/utils"
+	"github.com/Gituser143/cryptgo/pkg/widgets"
+	ui "github.com/gizak/termui/v3"
+)
+
+const (
+	UP_ARROW   = "▲"
+	DOWN_ARROW = "▼"
+)
+
+// formatChange formats a change in percent with an arrow, Eg: ▲ 1.23
+func formatChange(change float64) string {
+	if change < 0 {
+		return fmt.Sprintf("%s %.2f", DOWN_ARROW, -change)
+	}
+	return fmt.Sprintf("%s %.2f", UP_ARROW, change)
+}
+
+// DisplayCompare plots the change in price of coins over an interval on a
+// single graph, along with their 24 hour stats. The page opens on interval,
+// which is changed by sending it through the intervalChannel.
+func DisplayCompare(
+	ctx context.Context,
+	interval string,
+	intervalChannel chan string,
+	dataChannel chan api.CompareData,
+	uiEvents <-chan ui.Event) error {
+
+	defer ui.Clear()
+
+	// Init Compare page
+	page := newComparePage(ui.TerminalDimensions())
+	selectedTable := page.StatsTable
+	utilitySelected := ""
+
+	// Initialise help menu
+	help := widgets.NewHelpMenu()
+	help.SelectHelpMenu("COMPARE")
+
+	// Currency table
+	currencyWidget := uw.NewCurrencyPage()
+	currency := currencyWidget.Get(utils.GetCurrency())
+
+	changeInterval := uw.IntervalLabel(interval)
+
+	// Send interval and empty the graph till history of it arrives
+	setInterval := func(label string) {
+		changeInterval = label
+
+		page.ValueGraph.Data = make(map[string][]float64)
+		page.ValueGraph.Labels = make(map[string]string)
+
+		select {
+		case <-intervalChannel:
+		default:
+		}
+		intervalChannel <- uw.IntervalMap[label]
+	}
+
+	previousKey := ""
+
+	// Banner showing why config wasn't reloaded
+	banner := widgets.NewBanner()
+	banner.Title = " Config "
+
+	// UpdateUI to refresh UI
+	updateUI := func() {
+		// Get Terminal Dimensions
+		w, h := ui.TerminalDimensions()
+		page.Grid.SetRect(0, 0, w, h)
+
+		// Clear UI
+		ui.Clear()
+
+		// Render required widgets
+		switch utilitySelected {
+		case "HELP":
+			help.Resize(w, h)
+			ui.Render(help)
+		default:
+			ui.Render(page.Grid)
+		}
+
+		if banner.Active() {
+			banner.Resize(w, h)
+			ui.Render(banner)
+		}
+	}
+
+	// Render empty UI
+	page.ValueGraph.Title = fmt.Sprintf(" Change History (%s) ", changeInterval)
+	updateUI()
+
+	// Create ticker to periodically refresh UI
+	t := time.NewTicker(utils.UIRefresh)
+	tick := t.C
+
+	// Reload config, Eg: after the config file was edited. The
+	// previous config is kept if the file can't be read or is invalid, and
+	// the error is flashed on the banner.
+	reload := func() {
+		if err := utils.Reload(); err != nil {
+			banner.Show("Config not reloaded: "+err.Error(), time.Duration(5)*time.Second)
+		}
+		currency = currencyWidget.Get(utils.GetCurrency())
+		updateUI()
+	}
+
+	// Create channels to get suspend and reload signals
+	suspendSignals := utils.SuspendSignals()
+	reloadSignals := utils.ReloadSignals()
+
+	for {
+		select {
+		case <-ctx.Done(): // Context cancelled, exit
+			return ctx.Err()
+
+		case <-suspendSignals: // SIGTSTP sent from outside the UI
+			if err := utils.Suspend(); err != nil {
+				return err
+			}
+			updateUI()
+
+		case <-reloadSignals: // SIGHUP
+			reload()
+
+		case e := <-uiEvents: // keyboard events
+			switch e.ID {
+			case "<Escape>", "q", "<C-c>":
+				if utilitySelected != "" {
+					utilitySelected = ""
+					selectedTable = page.StatsTable
+					selectedTable.ShowCursor = true
+				} else {
+					return fmt.Errorf("UI Closed")
+				}
+
+			case "<Resize>":
+				updateUI()
+
+			case "<C-z>":
+				if err := utils.Suspend(); err != nil {
+					return err
+				}
+				updateUI()
+
+			case "<C-r>":
+				reload()
+
+			case "?":
+				selectedTable.ShowCursor = false
+				selectedTable = help.Table
+				selectedTable.ShowCursor = true
+				utilitySelected = "HELP"
+
+			case "<":
+				if utilitySelected == "" {
+					// Step to shorter interval
+					if label := uw.StepInterval(changeInterval, -1); label != changeInterval {
+						setInterval(label)
+					}
+				}
+
+			case ">":
+				if utilitySelected == "" {
+					// Step to longer interval
+					if label := uw.StepInterval(changeInterval, 1); label != changeInterval {
+						setInterval(label)
+					}
+				}
+
+			// Navigations
+			case "j", "<Down>":
+				selectedTable.ScrollDown()
+
+			case "k", "<Up>":
+				selectedTable.ScrollUp()
+
+			case "<C-d>":
+				selectedTable.ScrollHalfPageDown()
+
+			case "<C-u>":
+				selectedTable.ScrollHalfPageUp()
+
+			case "<C-f>":
+				selectedTable.ScrollPageDown()
+
+			case "<C-b>":
+				selectedTable.ScrollPageUp()
+
+			case "g":
+				if previousKey == "g" {
+					selectedTable.ScrollTop()
+				}
+
+			case "<Home>":
+				selectedTable.ScrollTop()
+
+			case "G", "<End>":
+				selectedTable.ScrollBottom()
+			}
+
+			updateUI()
+			if previousKey == "g" {
+				previousKey = ""
+			} else {
+				previousKey = e.ID
+			}
+
+		case data := <-dataChannel:
+			// Ignore history of a previous interval
+			if data.Interval != uw.IntervalMap[changeInterval] {
+				break
+			}
+
+			// Shift changes of all coins by the lowest one, as the graph
+			// plots values from zero
+			min := 0.0
+			for _, coin := range data.Coins {
+				if len(coin.Changes) > 0 {
+					min = utils.MinFloat64(min, utils.MinFloat64(coin.Changes...))
+				}
+			}
+
+			page.ValueGraph.Data = make(map[string][]float64)
+			page.ValueGraph.Labels = make(map[string]string)
+			rows := [][]string{}
+			for i, coin := range data.Coins {
+				change := 0.0
+				if len(coin.Changes) > 0 {
+					change = coin.Changes[len(coin.Changes)-1]
+				}
+
+				// Plot change history of coin
+				values := make([]float64, len(coin.Changes))
+				for j, val := range coin.Changes {
+					values[j] = val - min
+				}
+				page.ValueGraph.Data[coin.Symbol] = values
+				page.ValueGraph.Labels[coin.Symbol] = formatChange(change) + "%"
+				page.ValueGraph.LineColors[coin.Symbol] = lineColors[i%len(lineColors)]
+
+				rows = append(rows, []string{
+					coin.Symbol,
+					currency.Format(coin.Price),
+					formatChange(coin.Change24h),
+					currency.Format(coin.High24),
+					currency.Format(coin.Low24),
+					currency.Compact(coin.TotalVolume),
+					formatChange(change),
+				})
+			}
+			page.StatsTable.Rows = rows
+
+			page.StatsTable.Header = []string{
+				"Coin",
+				fmt.Sprintf("Price (%s)", currency.Label()),
+				"Change % (24H)",
+				"24H High",
+				"24H Low",
+				"Volume",
+				fmt.Sprintf("Change %% (%s)", changeInterval),
+			}
+			page.ValueGraph.Title = fmt.Sprintf(" Change History (%s) ", changeInterval)
+
+		case <-tick: // Refresh UI
+			updateUI()
+		}
+	}
+}
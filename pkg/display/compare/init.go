@@ -0,0 +1,84 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compare
+
+import (
+	"github.com/Gituser143/cryptgo/pkg/widgets"
+	ui "github.com/gizak/termui/v3"
+)
+
+// lineColors are the colours compared coins are plotted with, in order
+var lineColors = []ui.Color{ui.ColorBlue, ui.ColorYellow, ui.ColorMagenta, ui.ColorCyan, ui.ColorWhite}
+
+// comparePage holds UI items for the compare page
+type comparePage struct {
+	Grid       *ui.Grid
+	ValueGraph *widgets.LineGraph
+	StatsTable *widgets.Table
+}
+
+// newComparePage creates, initialises and returns a pointer to an instance
+// of comparePage
+func newComparePage(w, h int) *comparePage {
+	page := &comparePage{
+		Grid:       ui.NewGrid(),
+		ValueGraph: widgets.NewLineGraph(),
+		StatsTable: widgets.NewTable(),
+	}
+
+	page.init(w, h)
+
+	return page
+}
+
+// init initialises the widgets of a comparePage
+func (page *comparePage) init(w, h int) {
+	// Initialise Value Graph
+	page.ValueGraph.Title = " Change History "
+	page.ValueGraph.TitleStyle = ui.NewStyle(ui.ColorClear)
+	page.ValueGraph.HorizontalScale = 1
+	page.ValueGraph.BorderStyle.Fg = ui.ColorCyan
+
+	// Initialise Stats table
+	page.StatsTable.Title = " 24H Stats "
+	page.StatsTable.BorderStyle.Fg = ui.ColorCyan
+	page.StatsTable.TitleStyle.Fg = ui.ColorClear
+	page.StatsTable.ColResizer = func() {
+		x := page.StatsTable.Inner.Dx()
+		page.StatsTable.ColWidths = []int{
+			x / 7,
+			x / 7,
+			x / 7,
+			x / 7,
+			x / 7,
+			x / 7,
+			x / 7,
+		}
+	}
+	page.StatsTable.ChangeCol[2] = true
+	page.StatsTable.ChangeCol[6] = true
+	page.StatsTable.ShowCursor = true
+	page.StatsTable.CursorColor = ui.ColorCyan
+
+	// Set Grid layout
+	page.Grid.Set(
+		ui.NewRow(0.65, page.ValueGraph),
+		ui.NewRow(0.35, page.StatsTable),
+	)
+
+	page.Grid.SetRect(0, 0, w, h)
+}
@@ -0,0 +1,68 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compare
+
+import (
+	"testing"
+
+	"github.com/Gituser143/cryptgo/pkg/display/layout/layouttest"
+	ui "github.com/gizak/termui/v3"
+)
+
+// fillComparePage fills the page with change history and stats of
+// bitcoin, ethereum and solana as fetched from CoinGecko
+func fillComparePage(page *comparePage) {
+	page.ValueGraph.Title = " Change History (7 Days) "
+	page.StatsTable.Header = []string{"Coin", "Price (USD)", "Change % (24H)", "24H High", "24H Low", "Volume", "Change % (7 Days)"}
+	page.StatsTable.Rows = [][]string{
+		{"BTC", "43250.12", formatChange(2.41), "43612.90", "42011.37", "21.37B", formatChange(-1.18)},
+		{"ETH", "2291.84", formatChange(-0.87), "2340.15", "2270.02", "9.84B", formatChange(-3.62)},
+		{"SOL", "98.67", formatChange(-3.52), "104.20", "96.31", "2.13B", formatChange(7.95)},
+	}
+
+	for i, coin := range []struct {
+		symbol   string
+		min, max float64
+	}{
+		{"BTC", -2.90, 1.20},
+		{"ETH", -4.75, 0.35},
+		{"SOL", -1.10, 9.40},
+	} {
+		page.ValueGraph.Data[coin.symbol] = layouttest.Series(168, coin.min+4.75, coin.max+4.75)
+		page.ValueGraph.Labels[coin.symbol] = page.StatsTable.Rows[i][6] + "%"
+		page.ValueGraph.LineColors[coin.symbol] = lineColors[i]
+	}
+}
+
+func TestLayout(t *testing.T) {
+	layouttest.Run(t, []layouttest.Case{
+		{
+			Name: "compare_fetching",
+			Page: func(w, h int) []ui.Drawable {
+				return []ui.Drawable{newComparePage(w, h).Grid}
+			},
+		},
+		{
+			Name: "compare",
+			Page: func(w, h int) []ui.Drawable {
+				page := newComparePage(w, h)
+				fillComparePage(page)
+				return []ui.Drawable{page.Grid}
+			},
+		},
+	})
+}
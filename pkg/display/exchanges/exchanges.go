@@ -0,0 +1,390 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exchanges
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+	"github.com/Gituser143/cryptgo/pkg/utils"
+	"github.com/Gituser143/cryptgo/pkg/widgets"
+	ui "github.com/gizak/termui/v3"
+)
+
+const (
+	UP_ARROW   = "▲"
+	DOWN_ARROW = "▼"
+)
+
+// DisplayExchanges displays exchanges ranked by volume. Selecting an exchange
+// lists its markets in place of the exchanges, until <Escape> is pressed.
+func DisplayExchanges(ctx context.Context, exchangeChannel chan api.Exchange, dataChannel chan api.ExchangeData) error {
+
+	// Initialise UI
+	if err := ui.Init(); err != nil {
+		return fmt.Errorf("failed to initialise termui: %v", err)
+	}
+	defer ui.Close()
+
+	// Initialise page
+	page := newExchangesPage(ui.TerminalDimensions())
+	selectedTable := page.ExchangesTable
+	utilitySelected := ""
+
+	// Initialise help menu
+	help := widgets.NewHelpMenu()
+	help.SelectHelpMenu("EXCHANGES")
+
+	// Exchanges shown, by name, to look up the selected exchange
+	exchanges := map[string]api.Exchange{}
+	selected := api.Exchange{}
+
+	// Variables for sorting tables
+	exchangeSortIdx := 3
+	exchangeSortAsc := false
+	exchangeHeader := []string{
+		"Rank",
+		"Exchange",
+		"Trust",
+		"Volume (24h USD)",
+		"Volume %",
+		"Pairs",
+	}
+
+	marketSortIdx := 2
+	marketSortAsc := false
+	marketHeader := []string{
+		"Pair",
+		"Price (USD)",
+		"Volume (24h USD)",
+		"Volume %",
+	}
+
+	// sortExchanges sorts the exchanges table, marking the sorted column
+	sortExchanges := func() {
+		page.ExchangesTable.Header = append([]string{}, exchangeHeader...)
+		if exchangeSortAsc {
+			page.ExchangesTable.Header[exchangeSortIdx] += " " + UP_ARROW
+		} else {
+			page.ExchangesTable.Header[exchangeSortIdx] += " " + DOWN_ARROW
+		}
+		utils.SortData(page.ExchangesTable.Rows, exchangeSortIdx, exchangeSortAsc, utils.ExchangesLayout)
+	}
+
+	// sortMarkets sorts the markets table, marking the sorted column
+	sortMarkets := func() {
+		page.MarketsTable.Header = append([]string{}, marketHeader...)
+		if marketSortAsc {
+			page.MarketsTable.Header[marketSortIdx] += " " + UP_ARROW
+		} else {
+			page.MarketsTable.Header[marketSortIdx] += " " + DOWN_ARROW
+		}
+		utils.SortData(page.MarketsTable.Rows, marketSortIdx, marketSortAsc, utils.ExchangeMarketsLayout)
+	}
+
+	// selectExchange requests markets of an exchange, or stops fetching
+	// markets for an empty exchange
+	selectExchange := func(exchange api.Exchange) {
+		selected = exchange
+
+		// Replace selection not yet picked up
+		select {
+		case <-exchangeChannel:
+		default:
+		}
+		exchangeChannel <- exchange
+	}
+
+	previousKey := ""
+
+	// Banner asking for the quit key to be pressed again, if quitting must
+	// be confirmed, or showing why config wasn't reloaded
+	banner := widgets.NewBanner()
+	quitPrompt := utils.QuitPrompt{}
+
+	// UpdateUI to refresh UI
+	updateUI := func() {
+		// Get Terminal Dimensions
+		w, h := ui.TerminalDimensions()
+		page.Grid.SetRect(0, 0, w, h)
+
+		// Clear UI
+		ui.Clear()
+
+		// Render required widgets
+		switch utilitySelected {
+		case "HELP":
+			help.Resize(w, h)
+			ui.Render(help)
+		case "MARKETS":
+			page.MarketsTable.SetRect(0, 0, w, h)
+			ui.Render(page.MarketsTable)
+		default:
+			ui.Render(page.Grid)
+		}
+
+		if banner.Active() {
+			banner.Resize(w, h)
+			ui.Render(banner)
+		}
+	}
+
+	// Render Empty UI
+	sortExchanges()
+	sortMarkets()
+	updateUI()
+
+	// Create Channel to get keyboard events
+	uiEvents := ui.PollEvents()
+
+	// Create ticker to periodically refresh UI
+	t := time.NewTicker(utils.UIRefresh)
+	tick := t.C
+
+	// Reload config, Eg: after the config file was edited. The
+	// previous config is kept if the file can't be read or is invalid, and
+	// the error is flashed on the banner.
+	reload := func() {
+		if err := utils.Reload(); err != nil {
+			banner.Title = " Config "
+			banner.Show("Config not reloaded: "+err.Error(), time.Duration(5)*time.Second)
+		}
+		updateUI()
+	}
+
+	// Create channels to get suspend and reload signals
+	suspendSignals := utils.SuspendSignals()
+	reloadSignals := utils.ReloadSignals()
+
+	for {
+		select {
+		case <-ctx.Done(): // Context cancelled, exit
+			return ctx.Err()
+
+		case <-suspendSignals: // SIGTSTP sent from outside the UI
+			if err := utils.Suspend(); err != nil {
+				return err
+			}
+			updateUI()
+
+		case <-reloadSignals: // SIGHUP
+			reload()
+
+		case e := <-uiEvents:
+			switch e.ID {
+
+			// handle button events
+			case "q", "<C-c>":
+				if quitPrompt.Confirm() {
+					return fmt.Errorf("UI Closed")
+				}
+				banner.Title = " Quit "
+				banner.Show(fmt.Sprintf("Press %s again to quit", e.ID), utils.QuitWindow)
+				updateUI()
+
+			case "W":
+				// Close the page for the next workspace, if any are saved
+				if next := utils.NextWorkspace(utils.ActiveWorkspace); next != "" {
+					return utils.SwitchWorkspace{Name: next}
+				}
+
+			case "<Escape>":
+				switch utilitySelected {
+				case "HELP":
+					// Return to markets if they were shown
+					utilitySelected = ""
+					selectedTable = page.ExchangesTable
+					if selected.ID != "" {
+						utilitySelected = "MARKETS"
+						selectedTable = page.MarketsTable
+					}
+					selectedTable.ShowCursor = true
+				case "MARKETS":
+					utilitySelected = ""
+					selectExchange(api.Exchange{})
+					selectedTable = page.ExchangesTable
+					selectedTable.ShowCursor = true
+				}
+
+			case "<Enter>":
+				// List markets of the exchange under the cursor
+				if utilitySelected == "" && len(page.ExchangesTable.Rows) > 0 {
+					row := page.ExchangesTable.Rows[page.ExchangesTable.SelectedRow]
+					if exchange, ok := exchanges[row[1]]; ok {
+						selectExchange(exchange)
+						page.MarketsTable.Rows = [][]string{}
+						page.MarketsTable.SelectedRow = 0
+						page.MarketsTable.TopRow = 0
+						page.MarketsTable.Title = fmt.Sprintf(" %s Markets - fetching... ", exchange.Name)
+						utilitySelected = "MARKETS"
+						selectedTable = page.MarketsTable
+						selectedTable.ShowCursor = true
+					}
+				}
+
+			case "<Resize>":
+				updateUI()
+
+			case "<C-z>":
+				if err := utils.Suspend(); err != nil {
+					return err
+				}
+				updateUI()
+
+			case "<C-r>":
+				reload()
+
+			case "?":
+				selectedTable.ShowCursor = false
+				selectedTable = help.Table
+				selectedTable.ShowCursor = true
+				utilitySelected = "HELP"
+
+			// Navigations
+			case "j", "<Down>":
+				selectedTable.ScrollDown()
+
+			case "k", "<Up>":
+				selectedTable.ScrollUp()
+
+			case "<C-d>":
+				selectedTable.ScrollHalfPageDown()
+
+			case "<C-u>":
+				selectedTable.ScrollHalfPageUp()
+
+			case "<C-f>":
+				selectedTable.ScrollPageDown()
+
+			case "<C-b>":
+				selectedTable.ScrollPageUp()
+
+			case "g":
+				if previousKey == "g" {
+					selectedTable.ScrollTop()
+				}
+
+			case "<Home>":
+				selectedTable.ScrollTop()
+
+			case "G", "<End>":
+				selectedTable.ScrollBottom()
+
+			// handle sorting
+			case "1", "2", "3", "4", "5", "6":
+				// Sort Ascending
+				idx, _ := strconv.Atoi(e.ID)
+				switch utilitySelected {
+				case "":
+					exchangeSortIdx = idx - 1
+					exchangeSortAsc = true
+					sortExchanges()
+				case "MARKETS":
+					if idx <= len(marketHeader) {
+						marketSortIdx = idx - 1
+						marketSortAsc = true
+						sortMarkets()
+					}
+				}
+
+			case "<F1>", "<F2>", "<F3>", "<F4>", "<F5>", "<F6>":
+				// Sort Descending
+				idx, _ := strconv.Atoi(e.ID[2:3])
+				switch utilitySelected {
+				case "":
+					exchangeSortIdx = idx - 1
+					exchangeSortAsc = false
+					sortExchanges()
+				case "MARKETS":
+					if idx <= len(marketHeader) {
+						marketSortIdx = idx - 1
+						marketSortAsc = false
+						sortMarkets()
+					}
+				}
+			}
+
+			updateUI()
+			if previousKey == "g" {
+				previousKey = ""
+			} else {
+				previousKey = e.ID
+			}
+
+		case data := <-dataChannel:
+			switch data.Type {
+			case "EXCHANGES":
+				// Update exchanges table
+				rows := [][]string{}
+				totalVolume := 0.0
+				exchanges = map[string]api.Exchange{}
+				for _, exchange := range data.Exchanges {
+					trust := "NA"
+					if exchange.TrustScore > 0 {
+						trust = fmt.Sprintf("%d", exchange.TrustScore)
+					}
+					volume, units := utils.RoundValues(exchange.VolumeUSD, 0)
+					rows = append(rows, []string{
+						fmt.Sprintf("%d", exchange.Rank),
+						exchange.Name,
+						trust,
+						fmt.Sprintf("%.2f %s", volume[0], units),
+						fmt.Sprintf("%.2f%%", exchange.VolumeShare),
+						fmt.Sprintf("%d", exchange.Pairs),
+					})
+					exchanges[exchange.Name] = exchange
+					totalVolume += exchange.VolumeUSD
+				}
+
+				volume, units := utils.RoundValues(totalVolume, 0)
+				page.ExchangesTable.Rows = rows
+				page.ExchangesTable.Title = fmt.Sprintf(" Exchanges - %.2f %s USD traded in 24h ", volume[0], units)
+				sortExchanges()
+
+			case "MARKETS":
+				// Drop markets of an exchange no longer selected
+				if data.Exchange.ID != selected.ID {
+					break
+				}
+
+				// Update markets table
+				rows := [][]string{}
+				for _, market := range data.Markets {
+					volume, units := utils.RoundValues(market.VolumeUSD, 0)
+					rows = append(rows, []string{
+						market.Pair,
+						fmt.Sprintf("%.6f", market.PriceUSD),
+						fmt.Sprintf("%.2f %s", volume[0], units),
+						fmt.Sprintf("%.2f%%", market.VolumeShare),
+					})
+				}
+				page.MarketsTable.Rows = rows
+				page.MarketsTable.Title = fmt.Sprintf(" %s Markets ", selected.Name)
+				if len(rows) == 0 {
+					page.MarketsTable.Title = fmt.Sprintf(" %s Markets - none listed on CoinCap ", selected.Name)
+				}
+				sortMarkets()
+			}
+
+		case <-tick:
+			updateUI()
+		}
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exchanges
+
+import (
+	"github.com/Gituser143/cryptgo/pkg/widgets"
+	ui "github.com/gizak/termui/v3"
+)
+
+// exchangesPage holds UI items for the exchanges page
+type exchangesPage struct {
+	Grid           *ui.Grid
+	ExchangesTable *widgets.Table
+	MarketsTable   *widgets.Table
+}
+
+func newExchangesPage(w, h int) *exchangesPage {
+	page := &exchangesPage{
+		Grid:           ui.NewGrid(),
+		ExchangesTable: widgets.NewTable(),
+		MarketsTable:   widgets.NewTable(),
+	}
+
+	page.init(w, h)
+
+	return page
+}
+
+func (page *exchangesPage) init(w, h int) {
+	// Initialise Exchanges table
+	page.ExchangesTable.Title = " Exchanges - fetching... "
+	page.ExchangesTable.BorderStyle.Fg = ui.ColorCyan
+	page.ExchangesTable.TitleStyle.Fg = ui.ColorClear
+	page.ExchangesTable.ColResizer = func() {
+		x := page.ExchangesTable.Inner.Dx()
+		page.ExchangesTable.ColWidths = []int{
+			x / 10,
+			3 * x / 10,
+			x / 10,
+			2 * x / 10,
+			x / 10,
+			x / 10,
+		}
+	}
+	page.ExchangesTable.UniqueCol = 1
+	page.ExchangesTable.ShowCursor = true
+	page.ExchangesTable.CursorColor = ui.ColorCyan
+
+	// Initialise Markets table, shown in place of exchanges
+	page.MarketsTable.BorderStyle.Fg = ui.ColorCyan
+	page.MarketsTable.TitleStyle.Fg = ui.ColorClear
+	page.MarketsTable.ColResizer = func() {
+		x := page.MarketsTable.Inner.Dx()
+		page.MarketsTable.ColWidths = []int{
+			x / 4,
+			x / 4,
+			x / 4,
+			x / 4,
+		}
+	}
+	page.MarketsTable.ShowCursor = true
+	page.MarketsTable.CursorColor = ui.ColorCyan
+
+	// Set Grid layout
+	page.Grid.Set(
+		ui.NewRow(1.0, page.ExchangesTable),
+	)
+
+	page.Grid.SetRect(0, 0, w, h)
+}
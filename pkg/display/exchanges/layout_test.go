@@ -0,0 +1,78 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exchanges
+
+import (
+	"testing"
+
+	"github.com/Gituser143/cryptgo/pkg/display/layout/layouttest"
+	ui "github.com/gizak/termui/v3"
+)
+
+// fillExchangesPage fills the page with exchanges ranked by volume as
+// fetched from CoinGecko, and markets of Binance as fetched from CoinCap
+func fillExchangesPage(page *exchangesPage) {
+	page.ExchangesTable.Title = " Exchanges - 48.62 B USD traded in 24h "
+	page.ExchangesTable.Header = []string{"Rank", "Exchange", "Trust", "Volume (24h USD) ▼", "Volume %", "Pairs"}
+	page.ExchangesTable.Rows = [][]string{
+		{"1", "Binance", "10", "21.37 B", "43.95%", "1512"},
+		{"2", "Coinbase Exchange", "10", "4.12 B", "8.47%", "597"},
+		{"3", "OKX", "10", "3.88 B", "7.98%", "728"},
+		{"4", "Bybit", "10", "3.02 B", "6.21%", "641"},
+		{"5", "Kraken", "10", "1.24 B", "2.55%", "689"},
+		{"6", "KuCoin", "9", "0.98 B", "2.02%", "1304"},
+		{"7", "Gate.io", "8", "0.87 B", "1.79%", "2571"},
+	}
+
+	page.MarketsTable.Title = " Binance Markets "
+	page.MarketsTable.Header = []string{"Pair", "Price (USD)", "Volume (24h USD) ▼", "Volume %"}
+	page.MarketsTable.Rows = [][]string{
+		{"BTC/USDT", "43250.120000", "5.21 B", "24.38%"},
+		{"ETH/USDT", "2291.840000", "2.08 B", "9.73%"},
+		{"SOL/USDT", "98.670000", "0.91 B", "4.26%"},
+		{"XRP/USDT", "0.620100", "0.44 B", "2.06%"},
+	}
+}
+
+func TestLayout(t *testing.T) {
+	layouttest.Run(t, []layouttest.Case{
+		{
+			Name: "exchanges_fetching",
+			Page: func(w, h int) []ui.Drawable {
+				return []ui.Drawable{newExchangesPage(w, h).Grid}
+			},
+		},
+		{
+			Name: "exchanges",
+			Page: func(w, h int) []ui.Drawable {
+				page := newExchangesPage(w, h)
+				fillExchangesPage(page)
+				return []ui.Drawable{page.Grid}
+			},
+		},
+		{
+			// Markets of an exchange are drawn over the whole terminal
+			Name: "exchanges_markets",
+			Page: func(w, h int) []ui.Drawable {
+				page := newExchangesPage(w, h)
+				fillExchangesPage(page)
+				page.MarketsTable.SetRect(0, 0, w, h)
+				return []ui.Drawable{page.MarketsTable}
+			},
+		},
+	})
+}
@@ -0,0 +1,337 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package holdings
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+	uw "github.com/Gituser143/cryptgo/pkg/display/utilitywidgets"
+	"github.com/Gituser143/cryptgo/pkg/portfolio"
+	"github.com/Gituser143/cryptgo/pkg/utils"
+	"github.com/Gituser143/cryptgo/pkg/widgets"
+	ui "github.com/gizak/termui/v3"
+	geckoTypes "github.com/superoo7/go-gecko/v3/types"
+)
+
+const (
+	UP_ARROW   = "▲"
+	DOWN_ARROW = "▼"
+)
+
+// DisplayHoldings displays holdings read from the config file along with
+// their cost basis, unrealised profit/loss and allocation
+func DisplayHoldings(ctx context.Context, dataChannel chan api.AssetData) error {
+
+	// Read holdings and fees
+	lots, err := portfolio.ReadLots()
+	if err != nil {
+		return err
+	}
+	fees, err := portfolio.ReadFees()
+	if err != nil {
+		return err
+	}
+	_, realised := portfolio.ImportedLots()
+
+	// Initialise UI
+	if err := ui.Init(); err != nil {
+		return fmt.Errorf("failed to initialise termui: %v", err)
+	}
+	defer ui.Close()
+
+	// Initialise page
+	page := newHoldingsPage(ui.TerminalDimensions())
+	selectedTable := page.HoldingsTable
+	utilitySelected := ""
+
+	// Get currency
+	currencyWidget := uw.NewCurrencyPage()
+	currency := currencyWidget.Get(utils.GetCurrency())
+
+	// Initialise help menu
+	help := widgets.NewHelpMenu()
+	help.SelectHelpMenu("HOLDINGS")
+
+	// Variables for sorting HoldingsTable
+	holdingSortIdx := -1
+	holdingSortAsc := false
+	holdingHeader := []string{
+		"Coin",
+		"Quantity",
+		fmt.Sprintf("Avg Buy (%s)", currency.Label()),
+		fmt.Sprintf("Price (%s)", currency.Label()),
+		fmt.Sprintf("Value (%s)", currency.Label()),
+		fmt.Sprintf("P/L (%s)", currency.Label()),
+		"P/L %",
+		"Allocation %",
+		"Since",
+	}
+	page.HoldingsTable.Header = append([]string{}, holdingHeader...)
+
+	// Coins of the last update, used to recompute on reload
+	var coinsData geckoTypes.CoinsMarket
+
+	previousKey := ""
+
+	// UpdateUI to refresh UI
+	updateUI := func() {
+		// Get Terminal Dimensions
+		w, h := ui.TerminalDimensions()
+		page.Grid.SetRect(0, 0, w, h)
+
+		// Clear UI
+		ui.Clear()
+
+		// Render required widgets
+		switch utilitySelected {
+		case "HELP":
+			help.Resize(w, h)
+			ui.Render(help)
+		default:
+			ui.Render(page.Grid)
+		}
+	}
+
+	// formatChange formats a change with an arrow, Eg: ▲ 2.31
+	formatChange := func(change float64) string {
+		if change < 0 {
+			return fmt.Sprintf("%s %.2f", DOWN_ARROW, -change)
+		}
+		return fmt.Sprintf("%s %.2f", UP_ARROW, change)
+	}
+
+	// updateTables recomputes positions from the latest coin data
+	updateTables := func() {
+		if coinsData == nil {
+			return
+		}
+
+		summary := portfolio.Compute(lots, fees, coinsData)
+
+		// Update holdings table
+		rows := [][]string{}
+		for _, position := range summary.Positions {
+			since := "NA"
+			if !position.Since.IsZero() {
+				since = position.Since.Format(portfolio.DateLayout)
+			}
+
+			row := []string{
+				position.Symbol,
+				strconv.FormatFloat(position.Quantity, 'f', -1, 64),
+				currency.Format(position.AverageBuyPrice()),
+				"NA",
+				"NA",
+				"NA",
+				"NA",
+				"NA",
+				since,
+			}
+			if position.Priced {
+				row[3] = currency.Format(position.Price)
+				row[4] = currency.Format(position.Value)
+				row[5] = currency.Format(position.PnL)
+				row[6] = formatChange(position.PnLPercent)
+				row[7] = fmt.Sprintf("%.2f", position.Allocation)
+			}
+			rows = append(rows, row)
+		}
+		page.HoldingsTable.Rows = rows
+		utils.SortData(page.HoldingsTable.Rows, holdingSortIdx, holdingSortAsc, utils.HoldingsLayout)
+
+		// Update summary table
+		page.SummaryTable.Rows = [][]string{
+			{fmt.Sprintf("Value (%s)", currency.Label()), currency.Format(summary.Value)},
+			{fmt.Sprintf("Cost (%s)", currency.Label()), currency.Format(summary.Cost)},
+			{fmt.Sprintf("Fees Paid (%s)", currency.Label()), currency.Format(summary.Fees)},
+			{fmt.Sprintf("Est. Exit Fees (%s)", currency.Label()), currency.Format(summary.ExitFees)},
+			{fmt.Sprintf("Unrealised P/L (%s)", currency.Label()), currency.Format(summary.PnL)},
+			{"Unrealised P/L %", formatChange(summary.PnLPercent)},
+			{"Positions", fmt.Sprintf("%d", len(summary.Positions))},
+		}
+
+		// Show P/L realised by imported sells, matched first in first out
+		if len(realised) > 0 {
+			page.SummaryTable.Rows = append(page.SummaryTable.Rows, []string{
+				fmt.Sprintf("Realised P/L (%s)", currency.Label()),
+				currency.Format(portfolio.RealisedPnL(realised)),
+			})
+		}
+	}
+
+	// Render Empty UI
+	if len(lots) == 0 {
+		page.HoldingsTable.Title = " Positions | No holdings found in config or imported trades "
+	}
+	updateUI()
+
+	// Create Channel to get keyboard events
+	uiEvents := ui.PollEvents()
+
+	// Create ticker to periodically refresh UI
+	t := time.NewTicker(utils.UIRefresh)
+	tick := t.C
+
+	// Reload config and holdings, Eg: after the config file was edited. The
+	// previous config and holdings are kept if the file can't be read or is
+	// invalid, and the error is shown in the title.
+	reload := func() {
+		var newLots []portfolio.Lot
+		err := utils.Reload()
+		if err != nil {
+			err = fmt.Errorf("config not reloaded: %v", err)
+		} else {
+			newLots, err = portfolio.ReadLots()
+		}
+		if err == nil {
+			fees, err = portfolio.ReadFees()
+		}
+		if err == nil {
+			lots = newLots
+			_, realised = portfolio.ImportedLots()
+			page.HoldingsTable.Title = " Positions "
+		} else {
+			page.HoldingsTable.Title = fmt.Sprintf(" Positions | %s ", err)
+		}
+		updateTables()
+		updateUI()
+	}
+
+	// Create channels to get suspend and reload signals
+	suspendSignals := utils.SuspendSignals()
+	reloadSignals := utils.ReloadSignals()
+
+	for {
+		select {
+		case <-ctx.Done(): // Context cancelled, exit
+			return ctx.Err()
+
+		case <-suspendSignals: // SIGTSTP sent from outside the UI
+			if err := utils.Suspend(); err != nil {
+				return err
+			}
+			updateUI()
+
+		case <-reloadSignals: // SIGHUP
+			reload()
+
+		case e := <-uiEvents:
+			switch e.ID {
+
+			// handle button events
+			case "q", "<C-c>":
+				return fmt.Errorf("UI Closed")
+
+			case "<Escape>":
+				if utilitySelected != "" {
+					utilitySelected = ""
+					selectedTable = page.HoldingsTable
+					selectedTable.ShowCursor = true
+				}
+
+			case "<Resize>":
+				updateUI()
+
+			case "<C-z>":
+				if err := utils.Suspend(); err != nil {
+					return err
+				}
+				updateUI()
+
+			case "<C-r>":
+				reload()
+
+			case "?":
+				selectedTable.ShowCursor = false
+				selectedTable = help.Table
+				selectedTable.ShowCursor = true
+				utilitySelected = "HELP"
+
+			// Navigations
+			case "j", "<Down>":
+				selectedTable.ScrollDown()
+
+			case "k", "<Up>":
+				selectedTable.ScrollUp()
+
+			case "<C-d>":
+				selectedTable.ScrollHalfPageDown()
+
+			case "<C-u>":
+				selectedTable.ScrollHalfPageUp()
+
+			case "<C-f>":
+				selectedTable.ScrollPageDown()
+
+			case "<C-b>":
+				selectedTable.ScrollPageUp()
+
+			case "g":
+				if previousKey == "g" {
+					selectedTable.ScrollTop()
+				}
+
+			case "<Home>":
+				selectedTable.ScrollTop()
+
+			case "G", "<End>":
+				selectedTable.ScrollBottom()
+
+			// handle sorting
+			case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+				// Sort Ascending
+				if utilitySelected == "" {
+					idx, _ := strconv.Atoi(e.ID)
+					holdingSortIdx = idx - 1
+					page.HoldingsTable.Header = append([]string{}, holdingHeader...)
+					page.HoldingsTable.Header[holdingSortIdx] = holdingHeader[holdingSortIdx] + " " + UP_ARROW
+					holdingSortAsc = true
+					utils.SortData(page.HoldingsTable.Rows, holdingSortIdx, holdingSortAsc, utils.HoldingsLayout)
+				}
+
+			case "<F1>", "<F2>", "<F3>", "<F4>", "<F5>", "<F6>", "<F7>", "<F8>", "<F9>":
+				// Sort Descending
+				if utilitySelected == "" {
+					page.HoldingsTable.Header = append([]string{}, holdingHeader...)
+					idx, _ := strconv.Atoi(e.ID[2:3])
+					holdingSortIdx = idx - 1
+					page.HoldingsTable.Header[holdingSortIdx] = holdingHeader[holdingSortIdx] + " " + DOWN_ARROW
+					holdingSortAsc = false
+					utils.SortData(page.HoldingsTable.Rows, holdingSortIdx, holdingSortAsc, utils.HoldingsLayout)
+				}
+			}
+
+			updateUI()
+			if previousKey == "g" {
+				previousKey = ""
+			} else {
+				previousKey = e.ID
+			}
+
+		case data := <-dataChannel:
+			coinsData = data.AllCoinData
+			updateTables()
+
+		case <-tick:
+			updateUI()
+		}
+	}
+}
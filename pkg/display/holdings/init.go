@@ -0,0 +1,88 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package holdings
+
+import (
+	"github.com/Gituser143/cryptgo/pkg/widgets"
+	ui "github.com/gizak/termui/v3"
+)
+
+// holdingsPage holds UI items for the holdings page
+type holdingsPage struct {
+	Grid          *ui.Grid
+	SummaryTable  *widgets.Table
+	HoldingsTable *widgets.Table
+}
+
+func newHoldingsPage(w, h int) *holdingsPage {
+	page := &holdingsPage{
+		Grid:          ui.NewGrid(),
+		SummaryTable:  widgets.NewTable(),
+		HoldingsTable: widgets.NewTable(),
+	}
+
+	page.init(w, h)
+
+	return page
+}
+
+func (page *holdingsPage) init(w, h int) {
+	// Initialise Summary table
+	page.SummaryTable.Title = " Holdings "
+	page.SummaryTable.BorderStyle.Fg = ui.ColorCyan
+	page.SummaryTable.TitleStyle.Fg = ui.ColorClear
+	page.SummaryTable.Header = []string{"Summary", ""}
+	page.SummaryTable.ColResizer = func() {
+		x := page.SummaryTable.Inner.Dx()
+		page.SummaryTable.ColWidths = []int{
+			x / 2,
+			x / 2,
+		}
+	}
+	page.SummaryTable.ShowCursor = false
+
+	// Initialise Holdings table
+	page.HoldingsTable.Title = " Positions "
+	page.HoldingsTable.BorderStyle.Fg = ui.ColorCyan
+	page.HoldingsTable.TitleStyle.Fg = ui.ColorClear
+	page.HoldingsTable.Header = []string{"Coin", "Quantity", "Avg Buy", "Price", "Value", "P/L", "P/L %", "Allocation %", "Since"}
+	page.HoldingsTable.ColResizer = func() {
+		x := page.HoldingsTable.Inner.Dx()
+		page.HoldingsTable.ColWidths = []int{
+			x / 9,
+			x / 9,
+			x / 9,
+			x / 9,
+			x / 9,
+			x / 9,
+			x / 9,
+			x / 9,
+			x / 9,
+		}
+	}
+	page.HoldingsTable.ShowCursor = true
+	page.HoldingsTable.CursorColor = ui.ColorCyan
+	page.HoldingsTable.ChangeCol[6] = true
+
+	// Set Grid layout
+	page.Grid.Set(
+		ui.NewRow(0.3, page.SummaryTable),
+		ui.NewRow(0.7, page.HoldingsTable),
+	)
+
+	page.Grid.SetRect(0, 0, w, h)
+}
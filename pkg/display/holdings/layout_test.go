@@ -0,0 +1,62 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package holdings
+
+import (
+	"testing"
+
+	"github.com/Gituser143/cryptgo/pkg/display/layout/layouttest"
+	ui "github.com/gizak/termui/v3"
+)
+
+// fillHoldingsPage fills the page with positions built from imported trades
+func fillHoldingsPage(page *holdingsPage) {
+	page.HoldingsTable.Rows = [][]string{
+		{"BTC", "0.25", "36120.40", "43250.12", "10812.53", "1782.43", "▲ 19.74", "68.41", "2023-03-14"},
+		{"ETH", "1.5", "1844.10", "2291.84", "3437.76", "671.61", "▲ 24.28", "21.75", "2023-05-02"},
+		{"SOL", "16", "104.55", "98.67", "1578.72", "-94.08", "▼ 5.62", "9.99", "2023-11-20"},
+		{"DOT", "40", "5.12", "NA", "NA", "NA", "NA", "NA", "2023-08-09"},
+	}
+	page.SummaryTable.Rows = [][]string{
+		{"Value (USD)", "15829.01"},
+		{"Cost (USD)", "13469.05"},
+		{"Fees Paid (USD)", "21.84"},
+		{"Est. Exit Fees (USD)", "15.83"},
+		{"Unrealised P/L (USD)", "2359.96"},
+		{"Unrealised P/L %", "▲ 17.52"},
+		{"Positions", "4"},
+	}
+}
+
+func TestLayout(t *testing.T) {
+	layouttest.Run(t, []layouttest.Case{
+		{
+			Name: "holdings_fetching",
+			Page: func(w, h int) []ui.Drawable {
+				return []ui.Drawable{newHoldingsPage(w, h).Grid}
+			},
+		},
+		{
+			Name: "holdings",
+			Page: func(w, h int) []ui.Drawable {
+				page := newHoldingsPage(w, h)
+				fillHoldingsPage(page)
+				return []ui.Drawable{page.Grid}
+			},
+		},
+	})
+}
@@ -0,0 +1,175 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package layout
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	ui "github.com/gizak/termui/v3"
+)
+
+// Profiles of layouts, chosen by the size of the terminal
+const (
+	Small = "small"
+	Large = "large"
+)
+
+// Profiles are names of profiles
+var Profiles = []string{Small, Large}
+
+// Widgets maps pages to the widgets which can be hidden on them
+var Widgets = map[string][]string{
+	"main": {"overview", "top_coins", "dominance", "favourites", "breadth", "altseason", "status"},
+	"coin": {"strip", "favourites", "details", "stats", "changes", "explorers", "supply", "status"},
+}
+
+// Terminals at most SmallWidth columns wide or SmallHeight rows tall use
+// the small profile, others the large one
+var (
+	SmallWidth  = 100
+	SmallHeight = 30
+)
+
+// hidden maps profiles to pages, and pages to widgets hidden on them
+var hidden = map[string]map[string]map[string]bool{
+	Small: {
+		"main": {"breadth": true, "altseason": true},
+		"coin": {"explorers": true, "supply": true, "stats": true},
+	},
+	Large: {},
+}
+
+// Set sets widgets hidden on pages in a profile. Pages missing from hide
+// show all of their widgets.
+func Set(profile string, hide map[string][]string) error {
+	pages, err := parse(profile, hide)
+	if err != nil {
+		return err
+	}
+	hidden[profile] = pages
+	return nil
+}
+
+// Check returns the error Set would return, without setting anything
+func Check(profile string, hide map[string][]string) error {
+	_, err := parse(profile, hide)
+	return err
+}
+
+// parse returns widgets hidden on pages in a profile, by page
+func parse(profile string, hide map[string][]string) (map[string]map[string]bool, error) {
+	if _, ok := hidden[profile]; !ok {
+		return nil, fmt.Errorf("unknown profile %q, expected %s or %s", profile, Small, Large)
+	}
+
+	pages := map[string]map[string]bool{}
+	for page, names := range hide {
+		widgets, ok := Widgets[page]
+		if !ok {
+			return nil, fmt.Errorf("unknown page %q, expected main or coin", page)
+		}
+
+		pages[page] = map[string]bool{}
+		for _, name := range names {
+			known := false
+			for _, widget := range widgets {
+				known = known || widget == name
+			}
+			if !known {
+				sorted := append([]string{}, widgets...)
+				sort.Strings(sorted)
+				return nil, fmt.Errorf("unknown widget %q of the %s page, expected one of: %s", name, page, strings.Join(sorted, ", "))
+			}
+			pages[page][name] = true
+		}
+	}
+
+	return pages, nil
+}
+
+// Profile returns the profile of a terminal w columns wide and h rows tall
+func Profile(w, h int) string {
+	if w <= SmallWidth || h <= SmallHeight {
+		return Small
+	}
+	return Large
+}
+
+// Hidden returns widgets hidden on a page in a profile
+func Hidden(profile, page string) map[string]bool {
+	widgets := map[string]bool{}
+	for name := range hidden[profile][page] {
+		widgets[name] = true
+	}
+	return widgets
+}
+
+// HiddenNames returns names of widgets hidden on a page in a profile,
+// sorted
+func HiddenNames(profile, page string) []string {
+	names := []string{}
+	for name := range hidden[profile][page] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Item is an entry of a grid row or column, Eg: a widget or the rows of a
+// column returned by Split, named to be hidden. Items without a name are
+// always shown.
+type Item struct {
+	Name  string
+	Ratio float64
+	Entry interface{}
+}
+
+// shown returns true if item isn't hidden and has something to show
+func (item Item) shown(hide map[string]bool) bool {
+	if entries, ok := item.Entry.([]interface{}); ok && len(entries) == 0 {
+		return false
+	}
+	return !hide[item.Name]
+}
+
+// Split returns items which aren't hidden as rows or columns, made with
+// newItem (ui.NewRow or ui.NewCol). Ratios of shown items are scaled to
+// fill the space of hidden ones, and items of nested rows or columns which
+// are all hidden are left out.
+func Split(newItem func(float64, ...interface{}) ui.GridItem, items []Item, hide map[string]bool) []interface{} {
+	total := 0.0
+	for _, item := range items {
+		if item.shown(hide) {
+			total += item.Ratio
+		}
+	}
+
+	entries := []interface{}{}
+	for _, item := range items {
+		if !item.shown(hide) {
+			continue
+		}
+		if nested, ok := item.Entry.([]interface{}); ok {
+			entries = append(entries, newItem(item.Ratio/total, nested...))
+		} else {
+			entries = append(entries, newItem(item.Ratio/total, item.Entry))
+		}
+	}
+	return entries
+}
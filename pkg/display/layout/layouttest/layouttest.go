@@ -0,0 +1,152 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package layouttest renders pages filled with fixture data into a termui
+// Buffer and checks them against golden files, so layouts can be tested
+// without a terminal.
+package layouttest
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	ui "github.com/gizak/termui/v3"
+	rw "github.com/mattn/go-runewidth"
+)
+
+// update rewrites golden files with the rendered pages instead of checking
+// them, Eg: go test ./pkg/display/coin -update
+var update = flag.Bool("update", false, "update golden files of page layouts")
+
+// size is a terminal size pages are rendered at
+type size struct {
+	w, h int
+}
+
+// String returns the size as columns x rows, as used in golden file names
+func (s size) String() string {
+	return fmt.Sprintf("%dx%d", s.w, s.h)
+}
+
+// sizes are the terminal sizes pages are checked at, covering the small and
+// large layout profiles
+var sizes = []size{
+	{80, 24},
+	{120, 40},
+	{200, 60},
+}
+
+// Case is a state of a page checked against golden files
+type Case struct {
+	Name string // Golden files are named <Name>_<columns>x<rows>.golden
+
+	// Page returns the widgets of the page, laid out for a terminal w
+	// columns wide and h rows tall and filled with fixture data, in the
+	// order they're drawn
+	Page func(w, h int) []ui.Drawable
+}
+
+// Run renders each case at every size pages are checked at, comparing it
+// with its golden file in testdata
+func Run(t *testing.T, cases []Case) {
+	for _, c := range cases {
+		for _, s := range sizes {
+			c, s := c, s
+			name := c.Name + "_" + s.String()
+			t.Run(name, func(t *testing.T) {
+				check(t, name, render(s.w, s.h, c.Page(s.w, s.h)...))
+			})
+		}
+	}
+}
+
+// render draws items into a Buffer of w columns and h rows, in order, and
+// returns its text. Styles are left out, only the layout is kept. Trailing
+// spaces of rows are trimmed.
+func render(w, h int, items ...ui.Drawable) string {
+	buf := ui.NewBuffer(image.Rect(0, 0, w, h))
+	for _, item := range items {
+		item.Lock()
+		item.Draw(buf)
+		item.Unlock()
+	}
+
+	var text strings.Builder
+	for y := 0; y < h; y++ {
+		var row strings.Builder
+		for x := 0; x < w; x++ {
+			r := buf.GetCell(image.Pt(x, y)).Rune
+			if r == 0 {
+				r = ' '
+			}
+			row.WriteRune(r)
+
+			// Wide runes take the cell after them as well
+			if rw.RuneWidth(r) > 1 {
+				x++
+			}
+		}
+		text.WriteString(strings.TrimRight(row.String(), " "))
+		text.WriteByte('\n')
+	}
+
+	return text.String()
+}
+
+// check compares got with the golden file testdata/<name>.golden, or writes
+// it when the update flag is set
+func check(t *testing.T, name, got string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll("testdata", 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v, run with -update to create it", err)
+	}
+
+	if got != string(want) {
+		t.Errorf("%s does not match %s, run with -update if the change is intended\ngot:\n%s\nwant:\n%s", name, path, got, want)
+	}
+}
+
+// Series returns n points of fixture history, rising from low to high with
+// swings between, for filling graphs
+func Series(n int, low, high float64) []float64 {
+	series := make([]float64, n)
+	for i := range series {
+		trend := float64(i) / float64(ui.MaxInt(n-1, 1))
+		swing := (math.Sin(float64(i)/2) + 1) / 8
+		series[i] = low + (high-low)*(trend*0.75+swing)
+	}
+	return series
+}
@@ -0,0 +1,71 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orders
+
+import (
+	"github.com/Gituser143/cryptgo/pkg/widgets"
+	ui "github.com/gizak/termui/v3"
+)
+
+// ordersPage holds UI items for the orders page
+type ordersPage struct {
+	Grid        *ui.Grid
+	OrdersTable *widgets.Table
+}
+
+func newOrdersPage(w, h int) *ordersPage {
+	page := &ordersPage{
+		Grid:        ui.NewGrid(),
+		OrdersTable: widgets.NewTable(),
+	}
+
+	page.init(w, h)
+
+	return page
+}
+
+func (page *ordersPage) init(w, h int) {
+	// Initialise Orders table
+	page.OrdersTable.Title = " Orders "
+	page.OrdersTable.BorderStyle.Fg = ui.ColorCyan
+	page.OrdersTable.TitleStyle.Fg = ui.ColorClear
+	page.OrdersTable.Header = []string{"Placed", "Exchange", "ID", "Pair", "Side", "Type", "Quantity", "Price", "Filled", "Status"}
+	page.OrdersTable.ColResizer = func() {
+		x := page.OrdersTable.Inner.Dx()
+		page.OrdersTable.ColWidths = []int{
+			ui.MaxInt(17, x/10),
+			ui.MaxInt(9, x/10),
+			ui.MaxInt(12, x/10),
+			ui.MaxInt(9, x/10),
+			ui.MaxInt(5, x/10),
+			ui.MaxInt(7, x/10),
+			ui.MaxInt(10, x/10),
+			ui.MaxInt(10, x/10),
+			ui.MaxInt(10, x/10),
+			ui.MaxInt(16, x/10),
+		}
+	}
+	page.OrdersTable.ShowCursor = true
+	page.OrdersTable.CursorColor = ui.ColorCyan
+
+	// Set Grid layout
+	page.Grid.Set(
+		ui.NewRow(1.0, page.OrdersTable),
+	)
+
+	page.Grid.SetRect(0, 0, w, h)
+}
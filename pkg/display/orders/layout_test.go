@@ -0,0 +1,63 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orders
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/display/layout/layouttest"
+	"github.com/Gituser143/cryptgo/pkg/exchange"
+	ui "github.com/gizak/termui/v3"
+)
+
+// fillOrdersPage fills the page with orders placed on Binance
+func fillOrdersPage(page *ordersPage) {
+	placed := time.Date(2024, time.March, 4, 9, 30, 0, 0, time.UTC)
+	orders := []exchange.Order{
+		{Exchange: "binance", ID: "28457193021", Pair: "BTCUSDT", Side: exchange.Buy, Type: exchange.Limit, Quantity: 0.01, Price: 61250, Status: exchange.StatusNew, Placed: placed},
+		{Exchange: "binance", ID: "28457190877", Pair: "ETHUSDT", Side: exchange.Buy, Type: exchange.Limit, Quantity: 0.5, Price: 3320.5, Filled: 0.2, Status: exchange.StatusPartial, Placed: placed.Add(-2 * time.Hour)},
+		{Exchange: "binance", ID: "28456981245", Pair: "SOLUSDT", Side: exchange.Sell, Type: exchange.Market, Quantity: 12, Filled: 12, Status: exchange.StatusFilled, Placed: placed.Add(-26 * time.Hour)},
+		{Exchange: "binance", ID: "28456511902", Pair: "DOTUSDT", Side: exchange.Buy, Type: exchange.Limit, Quantity: 40, Price: 7.85, Status: exchange.StatusCancelled, Placed: placed.Add(-72 * time.Hour)},
+	}
+
+	rows := [][]string{}
+	for _, order := range orders {
+		rows = append(rows, orderRow(order))
+	}
+	page.OrdersTable.Rows = rows
+	page.OrdersTable.Title = " Orders on binance - 2 open "
+}
+
+func TestLayout(t *testing.T) {
+	layouttest.Run(t, []layouttest.Case{
+		{
+			Name: "orders_fetching",
+			Page: func(w, h int) []ui.Drawable {
+				return []ui.Drawable{newOrdersPage(w, h).Grid}
+			},
+		},
+		{
+			Name: "orders",
+			Page: func(w, h int) []ui.Drawable {
+				page := newOrdersPage(w, h)
+				fillOrdersPage(page)
+				return []ui.Drawable{page.Grid}
+			},
+		},
+	})
+}
@@ -0,0 +1,348 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orders
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/exchange"
+	"github.com/Gituser143/cryptgo/pkg/utils"
+	"github.com/Gituser143/cryptgo/pkg/widgets"
+	ui "github.com/gizak/termui/v3"
+)
+
+// DisplayOrders displays orders placed with cryptgo, with statuses of open
+// orders updated as they are sent on dataChannel. Orders are placed on the
+// named exchange, and every order placed or cancelled is confirmed by
+// typing yes.
+func DisplayOrders(ctx context.Context, name string, dryRun bool, dataChannel chan exchange.OrdersData) error {
+
+	// Initialise UI
+	if err := ui.Init(); err != nil {
+		return fmt.Errorf("failed to initialise termui: %v", err)
+	}
+	defer ui.Close()
+
+	// Initialise page
+	page := newOrdersPage(ui.TerminalDimensions())
+	selectedTable := page.OrdersTable
+	utilitySelected := ""
+
+	// Initialise help menu
+	help := widgets.NewHelpMenu()
+	help.SelectHelpMenu("ORDERS")
+
+	// Initialise banner for placed orders and errors
+	banner := widgets.NewBanner()
+	banner.Title = " Orders "
+
+	// Orders shown, in the order of rows
+	orders := []exchange.Order{}
+
+	// showOrders fills the orders table
+	showOrders := func(latest []exchange.Order) {
+		orders = latest
+		rows := [][]string{}
+		for _, order := range orders {
+			rows = append(rows, orderRow(order))
+		}
+		page.OrdersTable.Rows = rows
+
+		open := 0
+		for _, order := range orders {
+			if order.Open() {
+				open++
+			}
+		}
+		mode := ""
+		if _, forceDryRun := exchange.TradingSettings(); dryRun || forceDryRun {
+			mode = "- dry run "
+		}
+		page.OrdersTable.Title = fmt.Sprintf(" Orders on %s - %d open %s", name, open, mode)
+	}
+	showOrders(exchange.Orders())
+
+	// confirm asks to type yes before an action is taken
+	confirm := func(uiEvents <-chan ui.Event, action string) bool {
+		if _, forceDryRun := exchange.TradingSettings(); dryRun || forceDryRun {
+			action += " (dry run)"
+		}
+		inputStr := widgets.DrawPrompt(uiEvents, fmt.Sprintf(" Type yes to %s ", action))
+		return strings.TrimSpace(strings.ToLower(inputStr)) == "yes"
+	}
+
+	// place prompts for an order and places it once confirmed
+	place := func(uiEvents <-chan ui.Event, side string) {
+		inputStr := widgets.DrawPrompt(uiEvents, fmt.Sprintf(" %s pair quantity [price], Eg: BTCUSDT 0.01 30000 ", strings.Title(side)))
+		if strings.TrimSpace(inputStr) == "" {
+			return
+		}
+		order, err := parseOrder(name, side, inputStr)
+		if err != nil {
+			banner.Show(err.Error(), time.Duration(5)*time.Second)
+			return
+		}
+		if !confirm(uiEvents, order.String()) {
+			banner.Show("Order not placed", time.Duration(3)*time.Second)
+			return
+		}
+
+		order, err = exchange.PlaceOrder(ctx, order, dryRun)
+		if err != nil {
+			banner.Show(err.Error(), time.Duration(5)*time.Second)
+			return
+		}
+		banner.Show(fmt.Sprintf("Order %s is %s", order.ID, order.Status), time.Duration(3)*time.Second)
+		showOrders(exchange.Orders())
+	}
+
+	// cancel cancels the selected order once confirmed
+	cancel := func(uiEvents <-chan ui.Event) {
+		idx := page.OrdersTable.SelectedRow
+		if idx < 0 || idx >= len(orders) {
+			return
+		}
+		order := orders[idx]
+		if !order.Open() {
+			banner.Show(fmt.Sprintf("Order %s is %s", order.ID, order.Status), time.Duration(3)*time.Second)
+			return
+		}
+		if !confirm(uiEvents, "cancel order "+order.ID) {
+			return
+		}
+
+		order, err := exchange.CancelOrder(ctx, order.ID)
+		if err != nil {
+			banner.Show(err.Error(), time.Duration(5)*time.Second)
+			return
+		}
+		banner.Show(fmt.Sprintf("Order %s is %s", order.ID, order.Status), time.Duration(3)*time.Second)
+		showOrders(exchange.Orders())
+	}
+
+	previousKey := ""
+
+	// UpdateUI to refresh UI
+	updateUI := func() {
+		// Get Terminal Dimensions
+		w, h := ui.TerminalDimensions()
+		page.Grid.SetRect(0, 0, w, h)
+
+		// Clear UI
+		ui.Clear()
+
+		// Render required widgets
+		switch utilitySelected {
+		case "HELP":
+			help.Resize(w, h)
+			ui.Render(help)
+		default:
+			ui.Render(page.Grid)
+		}
+
+		if banner.Active() {
+			banner.Resize(w, h)
+			ui.Render(banner)
+		}
+	}
+
+	// Render Empty UI
+	updateUI()
+
+	// Create Channel to get keyboard events
+	uiEvents := ui.PollEvents()
+
+	// Create ticker to periodically refresh UI
+	t := time.NewTicker(utils.UIRefresh)
+	tick := t.C
+
+	// Reload config, Eg: after the config file was edited. The
+	// previous config is kept if the file can't be read or is invalid, and
+	// the error is flashed on the banner.
+	reload := func() {
+		if err := utils.Reload(); err != nil {
+			banner.Show("Config not reloaded: "+err.Error(), time.Duration(5)*time.Second)
+		}
+		updateUI()
+	}
+
+	// Create channels to get suspend and reload signals
+	suspendSignals := utils.SuspendSignals()
+	reloadSignals := utils.ReloadSignals()
+
+	for {
+		select {
+		case <-ctx.Done(): // Context cancelled, exit
+			return ctx.Err()
+
+		case <-suspendSignals: // SIGTSTP sent from outside the UI
+			if err := utils.Suspend(); err != nil {
+				return err
+			}
+			updateUI()
+
+		case <-reloadSignals: // SIGHUP
+			reload()
+
+		case e := <-uiEvents:
+			switch e.ID {
+
+			// handle button events
+			case "q", "<C-c>":
+				return fmt.Errorf("UI Closed")
+
+			case "<Escape>":
+				if utilitySelected != "" {
+					utilitySelected = ""
+					selectedTable = page.OrdersTable
+					selectedTable.ShowCursor = true
+				}
+
+			case "<Resize>":
+				updateUI()
+
+			case "<C-z>":
+				if err := utils.Suspend(); err != nil {
+					return err
+				}
+				updateUI()
+
+			case "<C-r>":
+				reload()
+
+			case "?":
+				selectedTable.ShowCursor = false
+				selectedTable = help.Table
+				selectedTable.ShowCursor = true
+				utilitySelected = "HELP"
+
+			case "b":
+				if utilitySelected == "" {
+					place(uiEvents, exchange.Buy)
+				}
+
+			case "s":
+				if utilitySelected == "" {
+					place(uiEvents, exchange.Sell)
+				}
+
+			case "c":
+				if utilitySelected == "" {
+					cancel(uiEvents)
+				}
+
+			// Navigations
+			case "j", "<Down>":
+				selectedTable.ScrollDown()
+
+			case "k", "<Up>":
+				selectedTable.ScrollUp()
+
+			case "<C-d>":
+				selectedTable.ScrollHalfPageDown()
+
+			case "<C-u>":
+				selectedTable.ScrollHalfPageUp()
+
+			case "<C-f>":
+				selectedTable.ScrollPageDown()
+
+			case "<C-b>":
+				selectedTable.ScrollPageUp()
+
+			case "g":
+				if previousKey == "g" {
+					selectedTable.ScrollTop()
+				}
+
+			case "<Home>":
+				selectedTable.ScrollTop()
+
+			case "G", "<End>":
+				selectedTable.ScrollBottom()
+			}
+
+			updateUI()
+			if previousKey == "g" {
+				previousKey = ""
+			} else {
+				previousKey = e.ID
+			}
+
+		case data := <-dataChannel:
+			showOrders(data.Orders)
+			if data.Err != nil {
+				banner.Show(data.Err.Error(), time.Duration(5)*time.Second)
+			}
+
+		case <-tick:
+			updateUI()
+		}
+	}
+}
+
+// parseOrder parses an order entered as pair, quantity and an optional
+// price, Eg: BTCUSDT 0.01 30000. Orders without a price are market orders.
+func parseOrder(name, side, input string) (exchange.Order, error) {
+	order := exchange.Order{
+		Exchange: name,
+		Side:     side,
+	}
+
+	fields := strings.Fields(input)
+	if len(fields) < 2 || len(fields) > 3 {
+		return order, fmt.Errorf("expected a pair, quantity and optional price, Eg: BTCUSDT 0.01 30000")
+	}
+	order.Pair = fields[0]
+
+	var err error
+	if order.Quantity, err = strconv.ParseFloat(fields[1], 64); err != nil {
+		return order, fmt.Errorf("invalid quantity %q", fields[1])
+	}
+	if len(fields) == 3 {
+		if order.Price, err = strconv.ParseFloat(fields[2], 64); err != nil {
+			return order, fmt.Errorf("invalid price %q", fields[2])
+		}
+	}
+
+	return exchange.ValidateOrder(order)
+}
+
+// orderRow returns the row of an order in the orders table
+func orderRow(order exchange.Order) []string {
+	price := "market"
+	if order.Type == exchange.Limit {
+		price = strconv.FormatFloat(order.Price, 'f', -1, 64)
+	}
+
+	return []string{
+		order.Placed.Format("2006-01-02 15:04"),
+		order.Exchange,
+		order.ID,
+		order.Pair,
+		order.Side,
+		order.Type,
+		strconv.FormatFloat(order.Quantity, 'f', -1, 64),
+		price,
+		strconv.FormatFloat(order.Filled, 'f', -1, 64),
+		order.Status,
+	}
+}
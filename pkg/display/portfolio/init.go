@@ -18,6 +18,7 @@ package portfolio
 
 import (
 	"math"
+	"sort"
 
 	"github.com/Gituser143/cryptgo/pkg/widgets"
 	ui "github.com/gizak/termui/v3"
@@ -30,6 +31,52 @@ type portfolioPage struct {
 	CoinTable           *widgets.Table
 	BestPerformerTable  *widgets.Table
 	WorstPerformerTable *widgets.Table
+	AllocationChart     *widgets.PieChart
+	ValueGraph          *widgets.LineGraph
+
+	// Risk tab
+	RiskGrid      *ui.Grid
+	RiskTable     *widgets.Table
+	RiskCoinTable *widgets.Table
+}
+
+// otherShare is the share of the portfolio under which holdings are grouped
+// into Other in the allocation chart
+const otherShare = 0.01
+
+// allocation returns labels and balances of slices of the allocation chart,
+// largest first, with holdings under otherShare of the total grouped
+func allocation(balances map[string]float64, total float64) ([]string, []float64) {
+	symbols := []string{}
+	for symbol, balance := range balances {
+		if balance > 0 {
+			symbols = append(symbols, symbol)
+		}
+	}
+	sort.Slice(symbols, func(i, j int) bool {
+		if balances[symbols[i]] == balances[symbols[j]] {
+			return symbols[i] < symbols[j]
+		}
+		return balances[symbols[i]] > balances[symbols[j]]
+	})
+
+	labels := []string{}
+	data := []float64{}
+	other := 0.0
+	for _, symbol := range symbols {
+		if balances[symbol] < total*otherShare {
+			other += balances[symbol]
+			continue
+		}
+		labels = append(labels, symbol)
+		data = append(data, balances[symbol])
+	}
+	if other > 0 {
+		labels = append(labels, "Other")
+		data = append(data, other)
+	}
+
+	return labels, data
 }
 
 // performer holds best and worst perfomer details
@@ -53,21 +100,26 @@ func getEmptyPerformers() map[string]performer {
 	return m
 }
 
-func newPortfolioPage() *portfolioPage {
+func newPortfolioPage(w, h int) *portfolioPage {
 	page := &portfolioPage{
 		Grid:                ui.NewGrid(),
 		DetailsTable:        widgets.NewTable(),
 		CoinTable:           widgets.NewTable(),
 		BestPerformerTable:  widgets.NewTable(),
 		WorstPerformerTable: widgets.NewTable(),
+		AllocationChart:     widgets.NewPieChart(),
+		ValueGraph:          widgets.NewLineGraph(),
+		RiskGrid:            ui.NewGrid(),
+		RiskTable:           widgets.NewTable(),
+		RiskCoinTable:       widgets.NewTable(),
 	}
 
-	page.init()
+	page.init(w, h)
 
 	return page
 }
 
-func (page *portfolioPage) init() {
+func (page *portfolioPage) init(w, h int) {
 	// Initialise Details table
 	page.DetailsTable.Title = " Details "
 	page.DetailsTable.BorderStyle.Fg = ui.ColorCyan
@@ -136,16 +188,70 @@ func (page *portfolioPage) init() {
 	page.WorstPerformerTable.CursorColor = ui.ColorCyan
 	page.WorstPerformerTable.ChangeCol[2] = true
 
+	// Initialise Allocation Chart
+	page.AllocationChart.Title = " Allocation "
+	page.AllocationChart.BorderStyle.Fg = ui.ColorCyan
+	page.AllocationChart.TitleStyle.Fg = ui.ColorClear
+
+	// Initialise Value Graph
+	page.ValueGraph.Title = " Value History - computing... "
+	page.ValueGraph.BorderStyle.Fg = ui.ColorCyan
+	page.ValueGraph.TitleStyle.Fg = ui.ColorClear
+	page.ValueGraph.HorizontalScale = 1
+	page.ValueGraph.MarkerSeries = "Value"
+	page.ValueGraph.Data["Max"] = []float64{}
+	page.ValueGraph.Data["Min"] = []float64{}
+	page.ValueGraph.LineColors["Value"] = ui.ColorCyan
+	page.ValueGraph.LineColors["Max"] = ui.ColorGreen
+	page.ValueGraph.LineColors["Min"] = ui.ColorRed
+
+	// Initialise Risk table
+	page.RiskTable.Title = " Risk (<Tab> for holdings) "
+	page.RiskTable.BorderStyle.Fg = ui.ColorCyan
+	page.RiskTable.TitleStyle.Fg = ui.ColorClear
+	page.RiskTable.Header = []string{"Metric", "Value"}
+	page.RiskTable.Rows = [][]string{{"Computing from history...", ""}}
+	page.RiskTable.ColResizer = func() {
+		x := page.RiskTable.Inner.Dx()
+		page.RiskTable.ColWidths = []int{
+			6 * x / 10,
+			4 * x / 10,
+		}
+	}
+
+	// Initialise Risk Coin table
+	page.RiskCoinTable.Title = " Holdings Risk "
+	page.RiskCoinTable.BorderStyle.Fg = ui.ColorCyan
+	page.RiskCoinTable.TitleStyle.Fg = ui.ColorClear
+	page.RiskCoinTable.Header = []string{"Symbol", "Weight %", "Volatility % (1y)", "Beta (BTC)"}
+	page.RiskCoinTable.ColResizer = func() {
+		x := page.RiskCoinTable.Inner.Dx()
+		page.RiskCoinTable.ColWidths = []int{
+			ui.MaxInt(6, x/4),
+			ui.MaxInt(8, x/4),
+			ui.MaxInt(17, x/4),
+			ui.MaxInt(10, x/4),
+		}
+	}
+	page.RiskCoinTable.CursorColor = ui.ColorCyan
+
 	// Set Grid layout
-	w, h := ui.TerminalDimensions()
 	page.Grid.Set(
 		ui.NewRow(0.3,
 			ui.NewCol(0.2, page.DetailsTable),
-			ui.NewCol(0.4, page.BestPerformerTable),
-			ui.NewCol(0.4, page.WorstPerformerTable),
+			ui.NewCol(0.3, page.AllocationChart),
+			ui.NewCol(0.25, page.BestPerformerTable),
+			ui.NewCol(0.25, page.WorstPerformerTable),
 		),
-		ui.NewRow(0.7, page.CoinTable),
+		ui.NewRow(0.3, page.ValueGraph),
+		ui.NewRow(0.4, page.CoinTable),
+	)
+
+	page.RiskGrid.Set(
+		ui.NewCol(0.4, page.RiskTable),
+		ui.NewCol(0.6, page.RiskCoinTable),
 	)
 
 	page.Grid.SetRect(0, 0, w, h)
+	page.RiskGrid.SetRect(0, 0, w, h)
 }
@@ -0,0 +1,118 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package portfolio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+	"github.com/Gituser143/cryptgo/pkg/display/coin"
+	"github.com/Gituser143/cryptgo/pkg/display/layout/layouttest"
+	uw "github.com/Gituser143/cryptgo/pkg/display/utilitywidgets"
+	ui "github.com/gizak/termui/v3"
+)
+
+// fillPortfolioPage fills the page with holdings from the config file,
+// priced as fetched from CoinGecko
+func fillPortfolioPage(page *portfolioPage) {
+	page.DetailsTable.Header = []string{"Balance", "15829.01"}
+	page.DetailsTable.Rows = [][]string{
+		{"Currency", "USD"},
+		{"Coins", "3"},
+	}
+
+	page.CoinTable.Rows = [][]string{
+		{"1", "BTC", "43250.12", "▲ 2.41", "0.25000", "10812.53", "68.31"},
+		{"2", "ETH", "2291.84", "▼ 0.87", "1.50000", "3437.76", "21.72"},
+		{"5", "SOL", "98.67", "▼ 3.52", "16.00000", "1578.72", "9.97"},
+	}
+
+	balances := map[string]float64{"BTC": 10812.53, "ETH": 3437.76, "SOL": 1578.72}
+	page.AllocationChart.Labels, page.AllocationChart.Data = allocation(balances, 15829.01)
+	page.AllocationChart.CenterLabel = "15829.01"
+
+	// Values are drawn above the lowest, as on the page
+	values := layouttest.Series(120, 0, 2400.27)
+	times := make([]time.Time, len(values))
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for i := range times {
+		times[i] = start.Add(time.Duration(i) * 6 * time.Hour)
+	}
+	page.ValueGraph.Title = " Value History (30d) "
+	page.ValueGraph.Data["Value"] = values
+	page.ValueGraph.XLabels = coin.DateLabels(times)
+	page.ValueGraph.Labels["Value"] = uw.USD.FormatValue(values[len(values)-1] + 13428.74)
+	page.ValueGraph.Labels["Max"] = uw.USD.FormatValue(15829.01)
+	page.ValueGraph.Labels["Min"] = uw.USD.FormatValue(13428.74)
+
+	page.BestPerformerTable.Rows = [][]string{
+		{"1h", "BTC", "▲ 0.42"},
+		{"24h", "BTC", "▲ 2.41"},
+		{"7d", "SOL", "▲ 7.95"},
+		{"30d", "SOL", "▲ 24.10"},
+		{"1y", "SOL", "▲ 412.77"},
+	}
+	page.WorstPerformerTable.Rows = [][]string{
+		{"1h", "SOL", "▼ 0.31"},
+		{"24h", "SOL", "▼ 3.52"},
+		{"7d", "ETH", "▼ 3.62"},
+		{"30d", "ETH", "▲ 1.06"},
+		{"1y", "ETH", "▲ 38.40"},
+	}
+}
+
+// portfolioRisk is risk of the holdings computed from a year of history
+var portfolioRisk = api.PortfolioRisk{
+	Volatility: 58.41,
+	Beta:       1.07,
+	VaR:        4.92,
+	Confidence: 0.95,
+	Days:       365,
+	Coins: []api.CoinRisk{
+		{ID: api.CoinID{CoinGeckoID: "bitcoin", Symbol: "BTC"}, Weight: 68.31, Volatility: 49.72, Beta: 1},
+		{ID: api.CoinID{CoinGeckoID: "ethereum", Symbol: "ETH"}, Weight: 21.72, Volatility: 61.05, Beta: 1.18},
+		{ID: api.CoinID{CoinGeckoID: "solana", Symbol: "SOL"}, Weight: 9.97, Volatility: 98.33, Beta: 1.52},
+	},
+}
+
+func TestLayout(t *testing.T) {
+	layouttest.Run(t, []layouttest.Case{
+		{
+			Name: "portfolio_fetching",
+			Page: func(w, h int) []ui.Drawable {
+				return []ui.Drawable{newPortfolioPage(w, h).Grid}
+			},
+		},
+		{
+			Name: "portfolio",
+			Page: func(w, h int) []ui.Drawable {
+				page := newPortfolioPage(w, h)
+				fillPortfolioPage(page)
+				return []ui.Drawable{page.Grid}
+			},
+		},
+		{
+			Name: "portfolio_risk",
+			Page: func(w, h int) []ui.Drawable {
+				page := newPortfolioPage(w, h)
+				page.updateRisk(portfolioRisk, 15829.01, uw.USD)
+				return []ui.Drawable{page.RiskGrid}
+			},
+		},
+	})
+}
@@ -19,6 +19,8 @@ package portfolio
 import (
 	"context"
 	"fmt"
+	"math"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -26,6 +28,7 @@ import (
 	"github.com/Gituser143/cryptgo/pkg/api"
 	"github.com/Gituser143/cryptgo/pkg/display/coin"
 	uw "github.com/Gituser143/cryptgo/pkg/display/utilitywidgets"
+	"github.com/Gituser143/cryptgo/pkg/exchange"
 	"github.com/Gituser143/cryptgo/pkg/utils"
 	"github.com/Gituser143/cryptgo/pkg/widgets"
 	ui "github.com/gizak/termui/v3"
@@ -37,7 +40,19 @@ const (
 	DOWN_ARROW = "▼"
 )
 
-func DisplayPortfolio(ctx context.Context, dataChannel chan api.AssetData, sendData *bool) error {
+// DisplayPortfolio displays the portfolio page, with holdings valued at
+// prices of the data channel. Holdings are sent on the risk channel to
+// compute risk metrics of the risk tab, and on the value channel to compute
+// their value history over the interval sent on the interval channel.
+func DisplayPortfolio(
+	ctx context.Context,
+	dataChannel chan api.AssetData,
+	holdingsChannel chan exchange.Holdings,
+	riskChannel chan []api.PortfolioHolding,
+	valueChannel chan []api.PortfolioHolding,
+	intervalChannel chan string,
+	sendData *bool,
+) error {
 
 	// Initialise UI
 	if err := ui.Init(); err != nil {
@@ -46,18 +61,28 @@ func DisplayPortfolio(ctx context.Context, dataChannel chan api.AssetData, sendD
 	defer ui.Close()
 
 	// Initialise page
-	page := newPortfolioPage()
+	page := newPortfolioPage(ui.TerminalDimensions())
 	selectedTable := page.CoinTable
 	utilitySelected := ""
 
+	// Tab shown, holdings or risk
+	showRisk := false
+
+	// Last risk of holdings, and their value in the selected currency
+	var risk *api.PortfolioRisk
+	portfolioValue := 0.0
+
+	// Interval of the value graph, and the last value history of holdings
+	valueInterval := uw.IntervalLabel(api.PortfolioHistoryInterval)
+	var valueHistory *api.PortfolioHistory
+
 	// Variables for CoinIDs
 	coinIDMap := api.NewCoinIDMap()
 	coinIDMap.Populate()
 
 	// currency variables
 	currencyWidget := uw.NewCurrencyPage()
-	currencyID := utils.GetCurrency()
-	currencyID, currency, currencyVal := currencyWidget.Get(currencyID)
+	currency := currencyWidget.Get(utils.GetCurrency())
 
 	// get portfolio details
 	portfolioMap := utils.GetPortfolio()
@@ -68,9 +93,12 @@ func DisplayPortfolio(ctx context.Context, dataChannel chan api.AssetData, sendD
 	// get favourites
 	favourites := utils.GetFavourites()
 
+	// balances of connected exchange accounts, added to holdings
+	wallets := exchange.Holdings{}
+
 	// Save metadata back to disk
 	defer func() {
-		utils.SaveMetadata(favourites, currencyID, portfolioMap)
+		utils.SaveMetadata(favourites, currency.ID, portfolioMap)
 	}()
 
 	// Initialise help menu
@@ -83,15 +111,20 @@ func DisplayPortfolio(ctx context.Context, dataChannel chan api.AssetData, sendD
 	coinHeader := []string{
 		"Rank",
 		"Symbol",
-		fmt.Sprintf("Price (%s)", currency),
+		fmt.Sprintf("Price (%s)", currency.Label()),
 		"Change % (1d)",
 		"Holding",
-		fmt.Sprintf("Balance (%s)", currency),
+		fmt.Sprintf("Balance (%s)", currency.Label()),
 		"Holding %",
 	}
 
 	previousKey := ""
 
+	// Banner asking for the quit key to be pressed again, if quitting must
+	// be confirmed, or showing why config wasn't reloaded
+	banner := widgets.NewBanner()
+	quitPrompt := utils.QuitPrompt{}
+
 	// Pause function to pause sending and receiving of data
 	pause := func() {
 		*sendData = !(*sendData)
@@ -102,6 +135,7 @@ func DisplayPortfolio(ctx context.Context, dataChannel chan api.AssetData, sendD
 		// Get Terminal Dimensions
 		w, h := ui.TerminalDimensions()
 		page.Grid.SetRect(0, 0, w, h)
+		page.RiskGrid.SetRect(0, 0, w, h)
 
 		// Clear UI
 		ui.Clear()
@@ -115,38 +149,188 @@ func DisplayPortfolio(ctx context.Context, dataChannel chan api.AssetData, sendD
 			currencyWidget.Resize(w, h)
 			ui.Render(currencyWidget)
 		default:
-			ui.Render(page.Grid)
+			if showRisk {
+				ui.Render(page.RiskGrid)
+			} else {
+				ui.Render(page.Grid)
+			}
+		}
+
+		if banner.Active() {
+			banner.Resize(w, h)
+			ui.Render(banner)
 		}
 	}
 
+	// drawValueHistory sets the value graph to as many points of the value
+	// history as it can show, in the selected currency, labelled with their
+	// dates and the latest, highest and lowest value
+	drawValueHistory := func() {
+		page.ValueGraph.Title = fmt.Sprintf(" Value History (%s) - computing... ", valueInterval)
+		if valueHistory == nil || valueHistory.Interval != uw.IntervalOf(valueInterval) {
+			return
+		}
+		points := valueHistory.Points
+
+		page.ValueGraph.Title = fmt.Sprintf(" Value History (%s) ", valueInterval)
+		if len(valueHistory.Missing) > 0 {
+			symbols := []string{}
+			for _, id := range valueHistory.Missing {
+				symbols = append(symbols, id.Symbol)
+			}
+			page.ValueGraph.Title += fmt.Sprintf("- without %s ", strings.Join(symbols, ", "))
+		}
+		if len(points) == 0 {
+			page.ValueGraph.Data["Value"] = []float64{}
+			page.ValueGraph.XLabels = nil
+			delete(page.ValueGraph.Labels, "Value")
+			delete(page.ValueGraph.Labels, "Max")
+			delete(page.ValueGraph.Labels, "Min")
+			return
+		}
+
+		n := (page.ValueGraph.Inner.Dx() + 1) * 2
+		if utils.HistoryPoints > 0 && utils.HistoryPoints < n {
+			n = utils.HistoryPoints
+		}
+		indices := utils.SampleIndices(len(points), n)
+
+		values := make([]float64, 0, len(indices))
+		times := make([]time.Time, 0, len(indices))
+		high, low := math.Inf(-1), math.Inf(1)
+		for _, i := range indices {
+			value := currency.Convert(points[i].Price)
+			values = append(values, value)
+			times = append(times, points[i].Time)
+			high = math.Max(high, value)
+			low = math.Min(low, value)
+		}
+
+		// Values are drawn above the lowest, so moves fill the graph
+		for i := range values {
+			values[i] -= low
+		}
+
+		page.ValueGraph.Data["Value"] = values
+		page.ValueGraph.XLabels = coin.DateLabels(times)
+		page.ValueGraph.Labels["Value"] = currency.FormatValue(values[len(values)-1] + low)
+		page.ValueGraph.Labels["Max"] = currency.FormatValue(high)
+		page.ValueGraph.Labels["Min"] = currency.FormatValue(low)
+	}
+
+	// setValueInterval sends the interval of the value graph, replacing one
+	// not yet picked up
+	setValueInterval := func(label string) {
+		valueInterval = label
+		drawValueHistory()
+
+		select {
+		case <-intervalChannel:
+		default:
+		}
+		intervalChannel <- uw.IntervalOf(label)
+	}
+
 	// Render Empty UI
+	drawValueHistory()
 	updateUI()
 
 	// Create Channel to get keyboard events
 	uiEvents := ui.PollEvents()
 
 	// Create ticker to periodically refresh UI
-	t := time.NewTicker(time.Duration(1) * time.Second)
+	t := time.NewTicker(utils.UIRefresh)
 	tick := t.C
 
+	// Reload config and metadata, Eg: after the config file was edited. The
+	// previous config is kept if the file can't be read or is invalid, and
+	// the error is flashed on the banner.
+	reload := func() {
+		if err := utils.Reload(); err != nil {
+			banner.Title = " Config "
+			banner.Show("Config not reloaded: "+err.Error(), time.Duration(5)*time.Second)
+		}
+		favourites = utils.GetFavourites()
+		portfolioMap = utils.GetPortfolio()
+		currency = currencyWidget.Get(utils.GetCurrency())
+		updateUI()
+	}
+
+	// Create channels to get suspend and reload signals
+	suspendSignals := utils.SuspendSignals()
+	reloadSignals := utils.ReloadSignals()
+
 	for {
 		select {
 		case <-ctx.Done(): // Context cancelled, exit
 			return ctx.Err()
 
+		case <-suspendSignals: // SIGTSTP sent from outside the UI
+			if err := utils.Suspend(); err != nil {
+				return err
+			}
+			updateUI()
+
+		case <-reloadSignals: // SIGHUP
+			reload()
+
 		case e := <-uiEvents:
 			switch e.ID {
 
 			// handle button events
 			case "q", "<C-c>":
-				return fmt.Errorf("UI Closed")
+				if quitPrompt.Confirm() {
+					return fmt.Errorf("UI Closed")
+				}
+				banner.Title = " Quit "
+				banner.Show(fmt.Sprintf("Press %s again to quit", e.ID), utils.QuitWindow)
+				updateUI()
+
+			case "W":
+				// Close the page for the next workspace, if any are saved
+				if next := utils.NextWorkspace(utils.ActiveWorkspace); next != "" {
+					return utils.SwitchWorkspace{Name: next}
+				}
 
 			case "<Resize>":
 				updateUI()
 
+			case "<C-z>":
+				if err := utils.Suspend(); err != nil {
+					return err
+				}
+				updateUI()
+
+			case "<C-r>":
+				reload()
+
 			case "p":
 				pause()
 
+			case "<", ">":
+				// Shorten or lengthen the interval of the value graph
+				if utilitySelected == "" && !showRisk {
+					step := -1
+					if e.ID == ">" {
+						step = 1
+					}
+					if label := uw.StepInterval(valueInterval, step); label != valueInterval {
+						setValueInterval(label)
+					}
+				}
+
+			case "<Tab>":
+				// Switch between the holdings and risk tabs
+				if utilitySelected == "" {
+					showRisk = !showRisk
+					selectedTable.ShowCursor = false
+					selectedTable = page.CoinTable
+					if showRisk {
+						selectedTable = page.RiskCoinTable
+					}
+					selectedTable.ShowCursor = true
+				}
+
 			case "?":
 				selectedTable.ShowCursor = false
 				selectedTable = help.Table
@@ -202,6 +386,10 @@ func DisplayPortfolio(ctx context.Context, dataChannel chan api.AssetData, sendD
 								delete(portfolioMap, id)
 							}
 						}
+
+						// Persist the edit straight away, so it isn't lost if
+						// cryptgo is killed before the page is closed
+						utils.SaveMetadata(favourites, currency.ID, portfolioMap)
 					}
 				}
 
@@ -212,13 +400,16 @@ func DisplayPortfolio(ctx context.Context, dataChannel chan api.AssetData, sendD
 					if currencyWidget.SelectedRow < len(currencyWidget.Rows) {
 						row := currencyWidget.Rows[currencyWidget.SelectedRow]
 
-						// Get currency and rate
-						currencyID = row[0]
-						currencyID, currency, currencyVal = currencyWidget.Get(currencyID)
+						// Get currency
+						currency = currencyWidget.Get(row[0])
 
 						// Update currency fields
-						coinHeader[2] = fmt.Sprintf("Price (%s)", currency)
-						coinHeader[5] = fmt.Sprintf("Balance (%s)", currency)
+						coinHeader[2] = fmt.Sprintf("Price (%s)", currency.Label())
+						coinHeader[5] = fmt.Sprintf("Balance (%s)", currency.Label())
+
+						// Persist currency
+						utils.SaveMetadata(favourites, currency.ID, portfolioMap)
+						drawValueHistory()
 					}
 					utilitySelected = ""
 
@@ -239,15 +430,33 @@ func DisplayPortfolio(ctx context.Context, dataChannel chan api.AssetData, sendD
 
 					coinIDs := coinIDMap[symbol]
 
-					coinCapId := coinIDs.CoinCapID
 					coinGeckoId := coinIDs.CoinGeckoID
 
 					if coinGeckoId != "" {
+						// Get refresh policy of coin
+						policy := api.GetRefreshPolicy(utils.GetPriorities()[coinGeckoId])
+
+						// Get data source of coin
+						src := api.CoinSource(utils.GetCoinSources()[coinGeckoId])
+
 						// Create new errorgroup for coin page
-						eg, coinCtx := errgroup.WithContext(ctx)
+						eg, coinCtx := errgroup.WithContext(api.WithPriority(ctx, policy.Priority))
 						coinDataChannel := make(chan api.CoinData)
 						coinPriceChannel := make(chan string)
-						intervalChannel := make(chan string)
+						// Buffered so the coin page isn't held up till the next poll
+						intervalChannel := make(chan string, 1)
+						quoteChannel := make(chan api.CoinID, 1)
+						yearAgoChannel := make(chan bool, 1)
+						timeframeChannel := make(chan string, 1)
+						bookChannel := make(chan bool, 1)
+						marketsChannel := make(chan bool, 1)
+						newsChannel := make(chan bool, 1)
+						volumeChannel := make(chan bool, 1)
+
+						// Open with the interval last used for the coin
+						if interval, ok := utils.GetCoinIntervals()[coinGeckoId]; ok {
+							intervalChannel <- interval
+						}
 
 						// Clear UI
 						ui.Clear()
@@ -256,32 +465,78 @@ func DisplayPortfolio(ctx context.Context, dataChannel chan api.AssetData, sendD
 						eg.Go(func() error {
 							err := api.GetCoinHistory(
 								coinCtx,
-								coinGeckoId,
+								src,
+								coinIDs,
+								policy.HistoryInterval,
 								intervalChannel,
+								quoteChannel,
+								yearAgoChannel,
+								coinDataChannel,
+							)
+							return err
+						})
+
+						// Serve Coin candles once a timeframe is selected
+						eg.Go(func() error {
+							err := api.GetCoinCandles(
+								coinCtx,
+								coinIDs,
+								policy.HistoryInterval,
+								timeframeChannel,
+								coinDataChannel,
+							)
+							return err
+						})
+
+						// Serve Coin order book once shown
+						eg.Go(func() error {
+							err := api.GetCoinOrderBook(
+								coinCtx,
+								coinIDs,
+								policy.HistoryInterval,
+								bookChannel,
 								coinDataChannel,
 							)
 							return err
 						})
 
+						// Serve Coin markets once shown
+						eg.Go(func() error {
+							err := api.GetCoinMarkets(coinCtx, coinIDs, marketsChannel, coinDataChannel)
+							return err
+						})
+
+						// Serve Coin volume breakdown once shown
+						eg.Go(func() error {
+							err := api.GetCoinVolume(coinCtx, coinIDs, volumeChannel, coinDataChannel)
+							return err
+						})
+
+						// Serve Coin news once shown
+						eg.Go(func() error {
+							err := api.GetCoinNews(coinCtx, coinIDs, newsChannel, coinDataChannel)
+							return err
+						})
+
 						// Serve Coin Asset data
 						eg.Go(func() error {
-							err := api.GetCoinDetails(coinCtx, coinGeckoId, coinDataChannel)
+							err := api.GetCoinDetails(coinCtx, coinGeckoId, policy.DetailsInterval, coinDataChannel)
 							return err
 						})
 
 						// Serve favourie coin prices
 						eg.Go(func() error {
 							err := api.GetFavouritePrices(coinCtx,
-								favourites,
+								utils.Watchlist{Name: utils.FavouritesList, IDs: favourites},
 								coinDataChannel,
 							)
 							return err
 						})
 
-						// Serve Live price of coin
-						if coinCapId != "" {
+						// Serve Live price of coin if a stream is allocated to it
+						if policy.Live {
 							eg.Go(func() error {
-								api.GetLivePrice(coinCtx, coinCapId, coinPriceChannel)
+								api.GetLivePrice(coinCtx, src, coinIDs, coinPriceChannel)
 								// Send NA to indicate price is not being updated
 								go func() {
 									coinPriceChannel <- "NA"
@@ -290,15 +545,22 @@ func DisplayPortfolio(ctx context.Context, dataChannel chan api.AssetData, sendD
 							})
 						}
 
-						utils.SaveMetadata(favourites, currencyID, portfolioMap)
+						utils.SaveMetadata(favourites, currency.ID, portfolioMap)
 
 						// Serve Visuals for coin
 						eg.Go(func() error {
 							err := coin.DisplayCoin(
 								coinCtx,
-								coinGeckoId,
+								coinIDs,
 								coinIDMap,
 								intervalChannel,
+								quoteChannel,
+								yearAgoChannel,
+								timeframeChannel,
+								bookChannel,
+								marketsChannel,
+								newsChannel,
+								volumeChannel,
 								coinDataChannel,
 								coinPriceChannel,
 								uiEvents,
@@ -314,8 +576,7 @@ func DisplayPortfolio(ctx context.Context, dataChannel chan api.AssetData, sendD
 							}
 						}
 
-						currencyID = utils.GetCurrency()
-						currencyID, currency, currencyVal = currencyWidget.Get(currencyID)
+						currency = currencyWidget.Get(utils.GetCurrency())
 
 					}
 
@@ -328,6 +589,9 @@ func DisplayPortfolio(ctx context.Context, dataChannel chan api.AssetData, sendD
 				if utilitySelected == "" {
 					selectedTable.ShowCursor = false
 					selectedTable = page.CoinTable
+					if showRisk {
+						selectedTable = page.RiskCoinTable
+					}
 					selectedTable.ShowCursor = true
 				}
 
@@ -335,6 +599,9 @@ func DisplayPortfolio(ctx context.Context, dataChannel chan api.AssetData, sendD
 			case "<Escape>":
 				utilitySelected = ""
 				selectedTable = page.CoinTable
+				if showRisk {
+					selectedTable = page.RiskCoinTable
+				}
 				selectedTable.ShowCursor = true
 				updateUI()
 
@@ -376,7 +643,7 @@ func DisplayPortfolio(ctx context.Context, dataChannel chan api.AssetData, sendD
 					page.CoinTable.Header = append([]string{}, coinHeader...)
 					page.CoinTable.Header[coinSortIdx] = coinHeader[coinSortIdx] + " " + UP_ARROW
 					coinSortAsc = true
-					utils.SortData(page.CoinTable.Rows, coinSortIdx, coinSortAsc, "PORTFOLIO")
+					utils.SortData(page.CoinTable.Rows, coinSortIdx, coinSortAsc, utils.PortfolioLayout)
 				}
 
 			case "<F1>", "<F2>", "<F3>", "<F4>", "<F5>", "<F6>", "<F7>":
@@ -387,7 +654,7 @@ func DisplayPortfolio(ctx context.Context, dataChannel chan api.AssetData, sendD
 					coinSortIdx = idx - 1
 					page.CoinTable.Header[coinSortIdx] = coinHeader[coinSortIdx] + " " + DOWN_ARROW
 					coinSortAsc = false
-					utils.SortData(page.CoinTable.Rows, coinSortIdx, coinSortAsc, "PORTFOLIO")
+					utils.SortData(page.CoinTable.Rows, coinSortIdx, coinSortAsc, utils.PortfolioLayout)
 				}
 			}
 
@@ -399,23 +666,71 @@ func DisplayPortfolio(ctx context.Context, dataChannel chan api.AssetData, sendD
 			}
 
 		case data := <-dataChannel:
+			// Show risk of holdings
+			if data.IsRiskData {
+				risk = &data.Risk
+				page.updateRisk(*risk, portfolioValue, currency)
+				break
+			}
+
+			// Show value history of holdings
+			if data.IsPortfolioHistoryData {
+				valueHistory = &data.PortfolioHistory
+				drawValueHistory()
+				break
+			}
+
+			// Warn about holdings which no longer return data
+			if data.IsStaleData {
+				page.CoinTable.Title = " Coins "
+				if warning := uw.StaleWarning(data.StaleCoins); warning != "" {
+					page.CoinTable.Title = fmt.Sprintf(" Coins | %s ", warning)
+				}
+				break
+			}
+
 			rows := [][]string{}
 
 			// Update currency headers
-			page.CoinTable.Header[2] = fmt.Sprintf("Price (%s)", currency)
-			page.CoinTable.Header[5] = fmt.Sprintf("Balance (%s)", currency)
+			page.CoinTable.Header[2] = fmt.Sprintf("Price (%s)", currency.Label())
+			page.CoinTable.Header[5] = fmt.Sprintf("Balance (%s)", currency.Label())
 
 			// variables to calculate holding %
 			balanceMap := map[string]float64{}
 			portfolioTotal := 0.0
 			durations := []string{"1h", "24h", "7d", "30d", "1y"}
 
+			// variables to value exchange accounts
+			walletCounted := map[string]bool{}
+			walletValues := map[string]float64{}
+
+			// quantities held, to compute risk from
+			holdings := []api.PortfolioHolding{}
+
 			// Iterate over coin assets
 			for _, val := range data.AllCoinData {
-				// Get coins in portfolio
-				if portfolioHolding, ok := portfolioMap[val.ID]; ok {
+				// Add balances of exchange accounts, counted once by the
+				// highest ranked coin of their symbol
+				walletHolding := 0.0
+				if symbol := strings.ToUpper(val.Symbol); !walletCounted[symbol] {
+					walletCounted[symbol] = true
+					walletHolding = wallets.Total(symbol)
+					for name, balances := range wallets.Balances {
+						walletValues[name] += currency.Convert(val.CurrentPrice * balances[symbol])
+					}
+				}
+
+				// Get coins in portfolio or held on exchanges
+				portfolioHolding, ok := portfolioMap[val.ID]
+				if ok || walletHolding > 0 {
+					portfolioHolding += walletHolding
+					holdings = append(holdings, api.PortfolioHolding{
+						ID:       api.CoinID{CoinGeckoID: val.ID, Symbol: strings.ToUpper(val.Symbol)},
+						Quantity: portfolioHolding,
+					})
+
 					// Get coin details
-					price := fmt.Sprintf("%.2f", val.CurrentPrice/currencyVal)
+					price := currency.Format(val.CurrentPrice)
 
 					change := "NA"
 					percentageChange := api.GetPercentageChangeForDuration(val, "24h")
@@ -428,8 +743,8 @@ func DisplayPortfolio(ctx context.Context, dataChannel chan api.AssetData, sendD
 					rank := fmt.Sprintf("%d", val.MarketCapRank)
 					symbol := strings.ToUpper(val.Symbol)
 					holding := fmt.Sprintf("%.5f", portfolioHolding)
-					balanceFloat := val.CurrentPrice / currencyVal * portfolioHolding
-					balance := fmt.Sprintf("%.2f", balanceFloat)
+					balanceFloat := currency.Convert(val.CurrentPrice * portfolioHolding)
+					balance := currency.Format(val.CurrentPrice * portfolioHolding)
 
 					// Aggregate data
 					rows = append(rows, []string{
@@ -482,14 +797,59 @@ func DisplayPortfolio(ctx context.Context, dataChannel chan api.AssetData, sendD
 			// Update coin table
 			page.CoinTable.Rows = rows
 
+			// Compute risk of the holdings, replacing holdings not yet
+			// picked up
+			sort.Slice(holdings, func(i, j int) bool {
+				return holdings[i].ID.CoinGeckoID < holdings[j].ID.CoinGeckoID
+			})
+			select {
+			case <-riskChannel:
+			default:
+			}
+			riskChannel <- holdings
+
+			// Compute value history of the holdings too
+			select {
+			case <-valueChannel:
+			default:
+			}
+			valueChannel <- holdings
+
+			// Update risk tab with the new value of holdings
+			portfolioValue = portfolioTotal
+			if risk != nil {
+				page.updateRisk(*risk, portfolioValue, currency)
+			}
+
+			// Update allocation chart
+			page.AllocationChart.Labels, page.AllocationChart.Data = allocation(balanceMap, portfolioTotal)
+			page.AllocationChart.CenterLabel = currency.FormatValue(portfolioTotal)
+
 			// Update details table
 			page.DetailsTable.Header = []string{
 				"Balance",
-				fmt.Sprintf("%.2f", portfolioTotal),
+				currency.FormatValue(portfolioTotal),
 			}
 			page.DetailsTable.Rows = [][]string{
-				{"Currency", currency},
-				{"Coins", fmt.Sprintf("%d", len(portfolioMap))},
+				{"Currency", currency.Label()},
+				{"Coins", fmt.Sprintf("%d", len(rows))},
+			}
+
+			// Show the value held on each connected exchange
+			accounts := []string{}
+			for name := range wallets.Balances {
+				accounts = append(accounts, name)
+			}
+			for name := range wallets.Errors {
+				accounts = append(accounts, name)
+			}
+			sort.Strings(accounts)
+			for _, name := range accounts {
+				value := currency.FormatValue(walletValues[name])
+				if _, failed := wallets.Errors[name]; failed {
+					value = "unavailable"
+				}
+				page.DetailsTable.Rows = append(page.DetailsTable.Rows, []string{strings.Title(name), value})
 			}
 
 			// Update Best Performers Table
@@ -530,7 +890,7 @@ func DisplayPortfolio(ctx context.Context, dataChannel chan api.AssetData, sendD
 
 			// Sort CoinTable data
 			if coinSortIdx != -1 {
-				utils.SortData(page.CoinTable.Rows, coinSortIdx, coinSortAsc, "PORTFOLIO")
+				utils.SortData(page.CoinTable.Rows, coinSortIdx, coinSortAsc, utils.PortfolioLayout)
 
 				if coinSortAsc {
 					page.CoinTable.Header[coinSortIdx] = coinHeader[coinSortIdx] + " " + UP_ARROW
@@ -539,9 +899,49 @@ func DisplayPortfolio(ctx context.Context, dataChannel chan api.AssetData, sendD
 				}
 			}
 
+		case wallets = <-holdingsChannel:
+			// Shown with the next asset data, once they can be valued
+
 		case <-tick: // Refresh UI
 			updateUI()
 		}
 	}
 
 }
+
+// updateRisk shows risk metrics of the portfolio on the risk tab, with value
+// at risk of the portfolio's value in the selected currency
+func (page *portfolioPage) updateRisk(risk api.PortfolioRisk, value float64, currency uw.Currency) {
+	if risk.Days == 0 {
+		page.RiskTable.Rows = [][]string{{"Not enough history of holdings", ""}}
+		page.RiskCoinTable.Rows = [][]string{}
+		return
+	}
+
+	confidence := fmt.Sprintf("%.0f%%", risk.Confidence*100)
+	page.RiskTable.Rows = [][]string{
+		{"Volatility (annualised)", fmt.Sprintf("%.2f%%", risk.Volatility)},
+		{"Beta vs BTC", fmt.Sprintf("%.2f", risk.Beta)},
+		{fmt.Sprintf("VaR (1 day, %s)", confidence), fmt.Sprintf("%.2f%%", risk.VaR)},
+		{fmt.Sprintf("VaR (1 day, %s, %s)", confidence, currency.Label()), currency.FormatValue(value * risk.VaR / 100)},
+		{"Days of history", fmt.Sprintf("%d", risk.Days)},
+	}
+	if len(risk.Missing) > 0 {
+		symbols := []string{}
+		for _, id := range risk.Missing {
+			symbols = append(symbols, id.Symbol)
+		}
+		page.RiskTable.Rows = append(page.RiskTable.Rows, []string{"Left out, without history", strings.Join(symbols, ", ")})
+	}
+
+	rows := [][]string{}
+	for _, coin := range risk.Coins {
+		rows = append(rows, []string{
+			coin.ID.Symbol,
+			fmt.Sprintf("%.2f", coin.Weight),
+			fmt.Sprintf("%.2f", coin.Volatility),
+			fmt.Sprintf("%.2f", coin.Beta),
+		})
+	}
+	page.RiskCoinTable.Rows = rows
+}
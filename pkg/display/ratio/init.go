@@ -0,0 +1,80 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratio
+
+import (
+	"github.com/Gituser143/cryptgo/pkg/display/theme"
+	"github.com/Gituser143/cryptgo/pkg/widgets"
+	ui "github.com/gizak/termui/v3"
+)
+
+// ratioPage holds UI items for the ratio page
+type ratioPage struct {
+	Grid       *ui.Grid
+	RatioGraph *widgets.LineGraph
+	StatsTable *widgets.Table
+}
+
+// newRatioPage creates, initialises and returns a pointer to an instance of
+// ratioPage
+func newRatioPage(w, h int) *ratioPage {
+	page := &ratioPage{
+		Grid:       ui.NewGrid(),
+		RatioGraph: widgets.NewLineGraph(),
+		StatsTable: widgets.NewTable(),
+	}
+
+	page.init(w, h)
+
+	return page
+}
+
+// init initialises the widgets of a ratioPage
+func (page *ratioPage) init(w, h int) {
+	t := theme.Current()
+
+	// Initialise Ratio Graph
+	page.RatioGraph.Title = " Relative Strength "
+	page.RatioGraph.HorizontalScale = 1
+	t.Block(page.RatioGraph.Block)
+
+	// Initialise Stats table
+	page.StatsTable.Title = " Stats "
+	page.StatsTable.Header = []string{"Ratio", "SMA", "vs SMA %", "Change %", "Trend"}
+	page.StatsTable.ColResizer = func() {
+		x := page.StatsTable.Inner.Dx()
+		page.StatsTable.ColWidths = []int{
+			x / 5,
+			x / 5,
+			x / 5,
+			x / 5,
+			x / 5,
+		}
+	}
+	page.StatsTable.ChangeCol[2] = true
+	page.StatsTable.ChangeCol[3] = true
+	page.StatsTable.ShowCursor = false
+	t.Table(page.StatsTable)
+
+	// Set Grid layout
+	page.Grid.Set(
+		ui.NewRow(0.8, page.RatioGraph),
+		ui.NewRow(0.2, page.StatsTable),
+	)
+
+	page.Grid.SetRect(0, 0, w, h)
+}
@@ -0,0 +1,58 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratio
+
+import (
+	"testing"
+
+	"github.com/Gituser143/cryptgo/pkg/display/layout/layouttest"
+	ui "github.com/gizak/termui/v3"
+)
+
+// fillRatioPage fills the page with the ETH/BTC ratio and its average as
+// fetched from CoinGecko
+func fillRatioPage(page *ratioPage) {
+	page.RatioGraph.Title = " Relative Strength ETH/BTC (30 Days) "
+	page.RatioGraph.Data["ETH/BTC"] = layouttest.Series(180, 0, 0.00612)
+	page.RatioGraph.Labels["ETH/BTC"] = "0.05299061"
+	page.RatioGraph.Data["SMA 20D"] = layouttest.Series(180, 0.00102, 0.00483)
+	page.RatioGraph.Labels["SMA 20D"] = "0.05171204"
+
+	page.StatsTable.Header[3] = "Change % (30 Days)"
+	page.StatsTable.Rows = [][]string{
+		{"0.05299061", "0.05171204", formatChange(2.47), formatChange(-6.88), "Above SMA"},
+	}
+}
+
+func TestLayout(t *testing.T) {
+	layouttest.Run(t, []layouttest.Case{
+		{
+			Name: "ratio_fetching",
+			Page: func(w, h int) []ui.Drawable {
+				return []ui.Drawable{newRatioPage(w, h).Grid}
+			},
+		},
+		{
+			Name: "ratio",
+			Page: func(w, h int) []ui.Drawable {
+				page := newRatioPage(w, h)
+				fillRatioPage(page)
+				return []ui.Drawable{page.Grid}
+			},
+		},
+	})
+}
@@ -0,0 +1,257 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratio
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+	"github.com/Gituser143/cryptgo/pkg/display/theme"
+	uw "github.com/Gituser143/cryptgo/pkg/display/utilitywidgets"
+	"github.com/Gituser143/cryptgo/pkg/utils"
+	"github.com/Gituser143/cryptgo/pkg/widgets"
+	ui "github.com/gizak/termui/v3"
+)
+
+const (
+	UP_ARROW   = "▲"
+	DOWN_ARROW = "▼"
+)
+
+// formatChange formats a change in percent with an arrow, Eg: ▲ 1.23
+func formatChange(change float64) string {
+	if change < 0 {
+		return fmt.Sprintf("%s %.2f", DOWN_ARROW, -change)
+	}
+	return fmt.Sprintf("%s %.2f", UP_ARROW, change)
+}
+
+// DisplayRatio plots the price of a coin in terms of another, named by pair
+// (Eg: ETH/BTC), along with its moving average over smaDays. The page opens
+// on interval, which is changed by sending it through the intervalChannel.
+func DisplayRatio(
+	ctx context.Context,
+	pair string,
+	smaDays int,
+	interval string,
+	intervalChannel chan string,
+	dataChannel chan api.RatioData,
+	uiEvents <-chan ui.Event) error {
+
+	defer ui.Clear()
+
+	// Init Ratio page
+	page := newRatioPage(ui.TerminalDimensions())
+	utilitySelected := ""
+
+	// Initialise help menu
+	help := widgets.NewHelpMenu()
+	help.SelectHelpMenu("RATIO")
+	theme.Current().Table(help.Table)
+
+	smaName := fmt.Sprintf("SMA %dD", smaDays)
+	changeInterval := uw.IntervalLabel(interval)
+
+	// Send interval and empty the graph till history of it arrives
+	setInterval := func(label string) {
+		changeInterval = label
+
+		page.RatioGraph.Data = make(map[string][]float64)
+		page.RatioGraph.Labels = make(map[string]string)
+		page.RatioGraph.Title = fmt.Sprintf(" Relative Strength %s (%s) ", pair, changeInterval)
+
+		select {
+		case <-intervalChannel:
+		default:
+		}
+		intervalChannel <- uw.IntervalMap[label]
+	}
+
+	// Banner showing why config wasn't reloaded
+	banner := widgets.NewBanner()
+	banner.Title = " Config "
+
+	// UpdateUI to refresh UI
+	updateUI := func() {
+		// Get Terminal Dimensions
+		w, h := ui.TerminalDimensions()
+		page.Grid.SetRect(0, 0, w, h)
+
+		// Clear UI
+		ui.Clear()
+
+		// Render required widgets
+		switch utilitySelected {
+		case "HELP":
+			help.Resize(w, h)
+			ui.Render(help)
+		default:
+			ui.Render(page.Grid)
+		}
+
+		if banner.Active() {
+			banner.Resize(w, h)
+			ui.Render(banner)
+		}
+	}
+
+	// Render empty UI
+	page.RatioGraph.Title = fmt.Sprintf(" Relative Strength %s (%s) ", pair, changeInterval)
+	updateUI()
+
+	// Create ticker to periodically refresh UI
+	t := time.NewTicker(utils.UIRefresh)
+	tick := t.C
+
+	// Reload config, Eg: after the config file was edited. The
+	// previous config is kept if the file can't be read or is invalid, and
+	// the error is flashed on the banner.
+	reload := func() {
+		if err := utils.Reload(); err != nil {
+			banner.Show("Config not reloaded: "+err.Error(), time.Duration(5)*time.Second)
+		}
+		updateUI()
+	}
+
+	// Create channels to get suspend and reload signals
+	suspendSignals := utils.SuspendSignals()
+	reloadSignals := utils.ReloadSignals()
+
+	for {
+		select {
+		case <-ctx.Done(): // Context cancelled, exit
+			return ctx.Err()
+
+		case <-suspendSignals: // SIGTSTP sent from outside the UI
+			if err := utils.Suspend(); err != nil {
+				return err
+			}
+			updateUI()
+
+		case <-reloadSignals: // SIGHUP
+			reload()
+
+		case e := <-uiEvents: // keyboard events
+			switch e.ID {
+			case "<Escape>", "q", "<C-c>":
+				if utilitySelected != "" {
+					utilitySelected = ""
+				} else {
+					return fmt.Errorf("UI Closed")
+				}
+
+			case "<Resize>":
+				updateUI()
+
+			case "<C-z>":
+				if err := utils.Suspend(); err != nil {
+					return err
+				}
+
+			case "<C-r>":
+				reload()
+
+			case "?":
+				utilitySelected = "HELP"
+
+			case "<":
+				if utilitySelected == "" {
+					// Step to shorter interval
+					if label := uw.StepInterval(changeInterval, -1); label != changeInterval {
+						setInterval(label)
+					}
+				}
+
+			case ">":
+				if utilitySelected == "" {
+					// Step to longer interval
+					if label := uw.StepInterval(changeInterval, 1); label != changeInterval {
+						setInterval(label)
+					}
+				}
+
+			case "j", "<Down>":
+				if utilitySelected == "HELP" {
+					help.ScrollDown()
+				}
+
+			case "k", "<Up>":
+				if utilitySelected == "HELP" {
+					help.ScrollUp()
+				}
+			}
+
+			updateUI()
+
+		case data := <-dataChannel:
+			// Ignore history of a previous interval
+			if data.Interval != uw.IntervalMap[changeInterval] || len(data.Ratio) == 0 {
+				break
+			}
+
+			// Shift ratio and average by the lowest value, as the graph
+			// plots values from zero
+			min := utils.MinFloat64(data.Ratio...)
+			if len(data.SMA) > 0 {
+				min = utils.MinFloat64(min, utils.MinFloat64(data.SMA...))
+			}
+
+			shift := func(values []float64) []float64 {
+				shifted := make([]float64, len(values))
+				for i, val := range values {
+					shifted[i] = val - min
+				}
+				return shifted
+			}
+
+			colors := theme.Current()
+			last := data.Ratio[len(data.Ratio)-1]
+			page.RatioGraph.Data = map[string][]float64{pair: shift(data.Ratio)}
+			page.RatioGraph.Labels = map[string]string{pair: fmt.Sprintf("%.8f", last)}
+			page.RatioGraph.LineColors = map[string]ui.Color{pair: colors.Line, smaName: colors.Accent}
+
+			row := []string{fmt.Sprintf("%.8f", last), "NA", "NA", "", "NA"}
+			if first := data.Ratio[0]; first != 0 {
+				row[3] = formatChange((last/first - 1) * 100)
+			}
+
+			// Compare ratio to its average, rising above it signals
+			// strength of the base coin
+			if len(data.SMA) > 0 {
+				sma := data.SMA[len(data.SMA)-1]
+				page.RatioGraph.Data[smaName] = shift(data.SMA)
+				page.RatioGraph.Labels[smaName] = fmt.Sprintf("%.8f", sma)
+
+				row[1] = fmt.Sprintf("%.8f", sma)
+				row[2] = formatChange((last/sma - 1) * 100)
+				row[4] = "Above SMA"
+				if last < sma {
+					row[4] = "Below SMA"
+				}
+			}
+
+			page.StatsTable.Rows = [][]string{row}
+			page.StatsTable.Header[3] = fmt.Sprintf("Change %% (%s)", changeInterval)
+			page.RatioGraph.Title = fmt.Sprintf(" Relative Strength %s (%s) ", pair, changeInterval)
+
+		case <-tick: // Refresh UI
+			updateUI()
+		}
+	}
+}
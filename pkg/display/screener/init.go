@@ -0,0 +1,87 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package screener
+
+import (
+	"github.com/Gituser143/cryptgo/pkg/widgets"
+	ui "github.com/gizak/termui/v3"
+)
+
+// screenerPage holds UI items for the screener page
+type screenerPage struct {
+	Grid         *ui.Grid
+	QueryTable   *widgets.Table
+	ResultsTable *widgets.Table
+}
+
+func newScreenerPage(w, h int) *screenerPage {
+	page := &screenerPage{
+		Grid:         ui.NewGrid(),
+		QueryTable:   widgets.NewTable(),
+		ResultsTable: widgets.NewTable(),
+	}
+
+	page.init(w, h)
+
+	return page
+}
+
+func (page *screenerPage) init(w, h int) {
+	// Initialise Query table
+	page.QueryTable.Title = " Query "
+	page.QueryTable.BorderStyle.Fg = ui.ColorCyan
+	page.QueryTable.TitleStyle.Fg = ui.ColorClear
+	page.QueryTable.Header = []string{"Query", ""}
+	page.QueryTable.ColResizer = func() {
+		x := page.QueryTable.Inner.Dx()
+		page.QueryTable.ColWidths = []int{
+			x / 4,
+			3 * x / 4,
+		}
+	}
+	page.QueryTable.ShowCursor = false
+
+	// Initialise Results table
+	page.ResultsTable.Title = " Results "
+	page.ResultsTable.BorderStyle.Fg = ui.ColorCyan
+	page.ResultsTable.TitleStyle.Fg = ui.ColorClear
+	page.ResultsTable.Header = []string{"Rank", "Symbol", "Name", "Price (USD)", "Change % (24h)", "Market Cap", "Volume / MCap", "From ATH %"}
+	page.ResultsTable.ColResizer = func() {
+		x := page.ResultsTable.Inner.Dx()
+		page.ResultsTable.ColWidths = []int{
+			5,
+			ui.MaxInt(8, (x-5)/7),
+			ui.MaxInt(12, (x-5)/7),
+			ui.MaxInt(12, (x-5)/7),
+			ui.MaxInt(15, (x-5)/7),
+			ui.MaxInt(12, (x-5)/7),
+			ui.MaxInt(14, (x-5)/7),
+			ui.MaxInt(11, (x-5)/7),
+		}
+	}
+	page.ResultsTable.ShowCursor = true
+	page.ResultsTable.CursorColor = ui.ColorCyan
+	page.ResultsTable.ChangeCol[4] = true
+
+	// Set Grid layout
+	page.Grid.Set(
+		ui.NewRow(0.25, page.QueryTable),
+		ui.NewRow(0.75, page.ResultsTable),
+	)
+
+	page.Grid.SetRect(0, 0, w, h)
+}
@@ -0,0 +1,90 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package screener
+
+import (
+	"testing"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+	"github.com/Gituser143/cryptgo/pkg/display/layout/layouttest"
+	uw "github.com/Gituser143/cryptgo/pkg/display/utilitywidgets"
+	ui "github.com/gizak/termui/v3"
+	geckoTypes "github.com/superoo7/go-gecko/v3/types"
+)
+
+// fillScreenerPage fills the page with results of a saved query
+func fillScreenerPage(page *screenerPage) {
+	query := api.ScreenerQuery{
+		Name: "Oversold large caps",
+		Criteria: []api.Criterion{
+			{Field: api.FieldMarketCap, Op: ">", Value: 1e10},
+			{Field: api.FieldATH, Op: "<", Value: -50},
+		},
+	}
+	page.QueryTable.Rows = [][]string{
+		{"Name", query.Name},
+		{"Criteria", query.String()},
+		{"Updated", "14:32:05"},
+	}
+
+	coins := []struct {
+		symbol, name            string
+		rank                    int16
+		price, change, cap, vol float64
+		fromATH                 float64
+	}{
+		{"sol", "Solana", 5, 98.67, -3.41, 4.28e10, 2.1e9, -62.4},
+		{"ada", "Cardano", 9, 0.5821, 1.27, 2.05e10, 4.3e8, -81.2},
+		{"dot", "Polkadot", 14, 6.94, -0.85, 9.1e9, 1.8e8, -87.5},
+	}
+
+	rows := [][]string{}
+	for _, c := range coins {
+		coin := geckoTypes.CoinsMarketItem{}
+		coin.Symbol = c.symbol
+		coin.Name = c.name
+		coin.MarketCapRank = c.rank
+		coin.CurrentPrice = c.price
+		coin.PriceChangePercentage24h = c.change
+		coin.MarketCap = c.cap
+		coin.TotalVolume = c.vol
+		coin.ATH = c.price * 3
+		coin.ATHChangePercentage = c.fromATH
+		rows = append(rows, resultRow(coin, uw.USD))
+	}
+	page.ResultsTable.Rows = rows
+	page.ResultsTable.Title = " Results - 3 of 250 coins "
+}
+
+func TestLayout(t *testing.T) {
+	layouttest.Run(t, []layouttest.Case{
+		{
+			Name: "screener_fetching",
+			Page: func(w, h int) []ui.Drawable {
+				return []ui.Drawable{newScreenerPage(w, h).Grid}
+			},
+		},
+		{
+			Name: "screener",
+			Page: func(w, h int) []ui.Drawable {
+				page := newScreenerPage(w, h)
+				fillScreenerPage(page)
+				return []ui.Drawable{page.Grid}
+			},
+		},
+	})
+}
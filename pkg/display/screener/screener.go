@@ -0,0 +1,352 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package screener
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+	uw "github.com/Gituser143/cryptgo/pkg/display/utilitywidgets"
+	"github.com/Gituser143/cryptgo/pkg/utils"
+	"github.com/Gituser143/cryptgo/pkg/widgets"
+	ui "github.com/gizak/termui/v3"
+	geckoTypes "github.com/superoo7/go-gecko/v3/types"
+)
+
+const (
+	UP_ARROW   = "▲"
+	DOWN_ARROW = "▼"
+)
+
+// DisplayScreener displays coins meeting a query, updated as market data is
+// refreshed. The query is sent on queryChannel, and sent again when edited
+// or another saved query is picked.
+func DisplayScreener(ctx context.Context, query api.ScreenerQuery, queryChannel chan api.ScreenerQuery, dataChannel chan api.ScreenerData) error {
+
+	// Initialise UI
+	if err := ui.Init(); err != nil {
+		return fmt.Errorf("failed to initialise termui: %v", err)
+	}
+	defer ui.Close()
+
+	// Initialise page
+	page := newScreenerPage(ui.TerminalDimensions())
+	selectedTable := page.ResultsTable
+	utilitySelected := ""
+
+	// Get currency
+	currencyWidget := uw.NewCurrencyPage()
+	currency := currencyWidget.Get(utils.GetCurrency())
+
+	// Initialise help menu
+	help := widgets.NewHelpMenu()
+	help.SelectHelpMenu("SCREENER")
+
+	// Initialise banner for invalid criteria
+	banner := widgets.NewBanner()
+	banner.Title = " Screener "
+
+	// Variables for sorting ResultsTable
+	resultSortIdx := -1
+	resultSortAsc := false
+	resultHeader := []string{
+		"Rank",
+		"Symbol",
+		"Name",
+		fmt.Sprintf("Price (%s)", currency.Label()),
+		"Change % (24h)",
+		fmt.Sprintf("Market Cap (%s)", currency.Label()),
+		"Volume / MCap",
+		"From ATH %",
+	}
+	page.ResultsTable.Header = append([]string{}, resultHeader...)
+
+	// Results last received, kept to show them again as soon as the
+	// query is changed
+	var last *api.ScreenerData
+
+	// showQuery shows the query and when its results were last updated
+	showQuery := func() {
+		name := query.Name
+		if name == "" {
+			name = "Custom"
+		}
+		criteria := query.String()
+		if criteria == "" {
+			criteria = "All coins"
+		}
+		page.QueryTable.Rows = [][]string{
+			{"Name", name},
+			{"Criteria", criteria},
+		}
+		if last != nil {
+			page.QueryTable.Rows = append(page.QueryTable.Rows, []string{"Updated", last.Updated.Format("15:04:05")})
+		}
+	}
+
+	// setQuery sends the query to screen coins with, replacing one not yet
+	// picked up
+	setQuery := func(q api.ScreenerQuery) {
+		query = q
+		select {
+		case <-queryChannel:
+		default:
+		}
+		queryChannel <- query
+		showQuery()
+	}
+	setQuery(query)
+
+	previousKey := ""
+
+	// UpdateUI to refresh UI
+	updateUI := func() {
+		// Get Terminal Dimensions
+		w, h := ui.TerminalDimensions()
+		page.Grid.SetRect(0, 0, w, h)
+
+		// Clear UI
+		ui.Clear()
+
+		// Render required widgets
+		switch utilitySelected {
+		case "HELP":
+			help.Resize(w, h)
+			ui.Render(help)
+		default:
+			ui.Render(page.Grid)
+		}
+
+		if banner.Active() {
+			banner.Resize(w, h)
+			ui.Render(banner)
+		}
+	}
+
+	// Render Empty UI
+	updateUI()
+
+	// Create Channel to get keyboard events
+	uiEvents := ui.PollEvents()
+
+	// Create ticker to periodically refresh UI
+	t := time.NewTicker(utils.UIRefresh)
+	tick := t.C
+
+	// Reload config, Eg: after the config file was edited. The
+	// previous config is kept if the file can't be read or is invalid, and
+	// the error is flashed on the banner.
+	reload := func() {
+		if err := utils.Reload(); err != nil {
+			banner.Show("Config not reloaded: "+err.Error(), time.Duration(5)*time.Second)
+		}
+		updateUI()
+	}
+
+	// Create channels to get suspend and reload signals
+	suspendSignals := utils.SuspendSignals()
+	reloadSignals := utils.ReloadSignals()
+
+	for {
+		select {
+		case <-ctx.Done(): // Context cancelled, exit
+			return ctx.Err()
+
+		case <-suspendSignals: // SIGTSTP sent from outside the UI
+			if err := utils.Suspend(); err != nil {
+				return err
+			}
+			updateUI()
+
+		case <-reloadSignals: // SIGHUP
+			reload()
+
+		case e := <-uiEvents:
+			switch e.ID {
+
+			// handle button events
+			case "q", "<C-c>":
+				return fmt.Errorf("UI Closed")
+
+			case "<Escape>":
+				if utilitySelected != "" {
+					utilitySelected = ""
+					selectedTable = page.ResultsTable
+					selectedTable.ShowCursor = true
+				}
+
+			case "<Resize>":
+				updateUI()
+
+			case "<C-z>":
+				if err := utils.Suspend(); err != nil {
+					return err
+				}
+				updateUI()
+
+			case "<C-r>":
+				reload()
+
+			case "?":
+				selectedTable.ShowCursor = false
+				selectedTable = help.Table
+				selectedTable.ShowCursor = true
+				utilitySelected = "HELP"
+
+			case "e":
+				if utilitySelected == "" {
+					// Get criteria to screen coins with
+					inputStr := widgets.DrawPrompt(uiEvents, " Criteria, Eg: mcap>100m change<-5 volmcap>0.1 ath<-70 ")
+					if strings.TrimSpace(inputStr) != "" {
+						newQuery, err := api.ParseScreenerQuery(inputStr)
+						if err != nil {
+							banner.Show(err.Error(), time.Duration(5)*time.Second)
+						} else {
+							setQuery(newQuery)
+						}
+					}
+				}
+
+			case "<Tab>":
+				saved := api.GetSavedQueries()
+				if utilitySelected == "" && len(saved) > 0 {
+					// Cycle saved queries, starting from the first
+					next := 0
+					for i, q := range saved {
+						if q.Name == query.Name {
+							next = (i + 1) % len(saved)
+						}
+					}
+					setQuery(saved[next])
+				}
+
+			// Navigations
+			case "j", "<Down>":
+				selectedTable.ScrollDown()
+
+			case "k", "<Up>":
+				selectedTable.ScrollUp()
+
+			case "<C-d>":
+				selectedTable.ScrollHalfPageDown()
+
+			case "<C-u>":
+				selectedTable.ScrollHalfPageUp()
+
+			case "<C-f>":
+				selectedTable.ScrollPageDown()
+
+			case "<C-b>":
+				selectedTable.ScrollPageUp()
+
+			case "g":
+				if previousKey == "g" {
+					selectedTable.ScrollTop()
+				}
+
+			case "<Home>":
+				selectedTable.ScrollTop()
+
+			case "G", "<End>":
+				selectedTable.ScrollBottom()
+
+			// handle sorting
+			case "1", "2", "3", "4", "5", "6", "7", "8":
+				// Sort Ascending
+				if utilitySelected == "" {
+					idx, _ := strconv.Atoi(e.ID)
+					resultSortIdx = idx - 1
+					page.ResultsTable.Header = append([]string{}, resultHeader...)
+					page.ResultsTable.Header[resultSortIdx] = resultHeader[resultSortIdx] + " " + UP_ARROW
+					resultSortAsc = true
+					utils.SortData(page.ResultsTable.Rows, resultSortIdx, resultSortAsc, utils.ScreenerLayout)
+				}
+
+			case "<F1>", "<F2>", "<F3>", "<F4>", "<F5>", "<F6>", "<F7>", "<F8>":
+				// Sort Descending
+				if utilitySelected == "" {
+					page.ResultsTable.Header = append([]string{}, resultHeader...)
+					idx, _ := strconv.Atoi(e.ID[2:3])
+					resultSortIdx = idx - 1
+					page.ResultsTable.Header[resultSortIdx] = resultHeader[resultSortIdx] + " " + DOWN_ARROW
+					resultSortAsc = false
+					utils.SortData(page.ResultsTable.Rows, resultSortIdx, resultSortAsc, utils.ScreenerLayout)
+				}
+			}
+
+			updateUI()
+			if previousKey == "g" {
+				previousKey = ""
+			} else {
+				previousKey = e.ID
+			}
+
+		case data := <-dataChannel:
+			// Ignore results of a query since replaced
+			if data.Query.String() != query.String() {
+				break
+			}
+			last = &data
+			showQuery()
+
+			// Update results table
+			rows := [][]string{}
+			for _, coin := range data.Coins {
+				rows = append(rows, resultRow(coin, currency))
+			}
+			page.ResultsTable.Rows = rows
+			utils.SortData(page.ResultsTable.Rows, resultSortIdx, resultSortAsc, utils.ScreenerLayout)
+			page.ResultsTable.Title = fmt.Sprintf(" Results - %d of %d coins ", len(data.Coins), data.Screened)
+
+		case <-tick:
+			updateUI()
+		}
+	}
+}
+
+// resultRow returns the row of a coin in the results table, with fields
+// not served for the coin as NA
+func resultRow(coin geckoTypes.CoinsMarketItem, currency uw.Currency) []string {
+	change := fmt.Sprintf("%s %.2f", UP_ARROW, coin.PriceChangePercentage24h)
+	if coin.PriceChangePercentage24h < 0 {
+		change = fmt.Sprintf("%s %.2f", DOWN_ARROW, -coin.PriceChangePercentage24h)
+	}
+
+	ratio := "NA"
+	if val, ok := api.FieldValue(coin, api.FieldVolumeRatio); ok {
+		ratio = fmt.Sprintf("%.3f", val)
+	}
+	fromATH := "NA"
+	if val, ok := api.FieldValue(coin, api.FieldATH); ok {
+		fromATH = fmt.Sprintf("%.2f", val)
+	}
+
+	return []string{
+		fmt.Sprintf("%d", coin.MarketCapRank),
+		strings.ToUpper(coin.Symbol),
+		coin.Name,
+		currency.Format(coin.CurrentPrice),
+		change,
+		currency.Compact(coin.MarketCap),
+		ratio,
+		fromATH,
+	}
+}
@@ -0,0 +1,190 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package theme
+
+import (
+	"fmt"
+
+	"github.com/Gituser143/cryptgo/pkg/widgets"
+	ui "github.com/gizak/termui/v3"
+)
+
+// Theme is a named palette of colours widgets are drawn with. Colours are
+// from the 256 colour palette.
+type Theme struct {
+	Name   string
+	Border ui.Color // Borders of widgets
+	Title  ui.Color // Titles of widgets
+	Cursor ui.Color // Selected rows of tables
+	Up     ui.Color // Rising prices and changes
+	Down   ui.Color // Falling prices and changes
+	Line   ui.Color // Price lines of graphs
+	Accent ui.Color // Secondary lines and bars
+	Text   ui.Color // Text drawn over bars
+
+	UpShades   []ui.Color // Shades of rising changes, dimmest first
+	DownShades []ui.Color // Shades of falling changes, dimmest first
+}
+
+// Names of themes, in the order they are cycled through
+var Names = []string{"default", "dracula", "gruvbox", "solarized", "monochrome"}
+
+// themes maps names of themes to their palettes
+var themes = map[string]Theme{
+	"default": {
+		Border:     ui.ColorCyan,
+		Title:      ui.ColorClear,
+		Cursor:     ui.ColorCyan,
+		Up:         ui.ColorGreen,
+		Down:       ui.ColorRed,
+		Line:       ui.ColorBlue,
+		Accent:     ui.ColorYellow,
+		Text:       ui.ColorBlack,
+		UpShades:   []ui.Color{22, 28, 34, 40, 46},
+		DownShades: []ui.Color{52, 88, 124, 160, 196},
+	},
+	"dracula": {
+		Border:     141,
+		Title:      255,
+		Cursor:     212,
+		Up:         84,
+		Down:       203,
+		Line:       117,
+		Accent:     228,
+		Text:       236,
+		UpShades:   []ui.Color{23, 29, 36, 78, 84},
+		DownShades: []ui.Color{53, 89, 125, 168, 203},
+	},
+	"gruvbox": {
+		Border:     108,
+		Title:      223,
+		Cursor:     214,
+		Up:         142,
+		Down:       167,
+		Line:       109,
+		Accent:     175,
+		Text:       235,
+		UpShades:   []ui.Color{58, 64, 100, 106, 142},
+		DownShades: []ui.Color{52, 88, 124, 131, 167},
+	},
+	"solarized": {
+		Border:     37,
+		Title:      246,
+		Cursor:     33,
+		Up:         64,
+		Down:       160,
+		Line:       33,
+		Accent:     136,
+		Text:       234,
+		UpShades:   []ui.Color{22, 28, 58, 64, 70},
+		DownShades: []ui.Color{52, 88, 124, 125, 160},
+	},
+	"monochrome": {
+		Border:     250,
+		Title:      ui.ColorClear,
+		Cursor:     255,
+		Up:         255,
+		Down:       244,
+		Line:       252,
+		Accent:     247,
+		Text:       ui.ColorBlack,
+		UpShades:   []ui.Color{247, 249, 251, 253, 255},
+		DownShades: []ui.Color{243, 242, 241, 240, 239},
+	},
+}
+
+// current is the theme in use
+var current = Get("default")
+
+// Get returns the theme of the given name, or the default theme if unknown
+func Get(name string) Theme {
+	t, ok := themes[name]
+	if !ok {
+		name = "default"
+		t = themes[name]
+	}
+
+	t.Name = name
+	return t
+}
+
+// Current returns the theme in use
+func Current() Theme {
+	return current
+}
+
+// Set sets the theme in use by name. Widgets created afterwards use it,
+// pages apply it to existing widgets.
+func Set(name string) error {
+	if _, ok := themes[name]; !ok {
+		return fmt.Errorf("unknown theme %q", name)
+	}
+
+	current = Get(name)
+
+	// Colour changes in tables and defaults of new blocks
+	widgets.UpColor = current.Up
+	widgets.DownColor = current.Down
+	widgets.UpShades = current.UpShades
+	widgets.DownShades = current.DownShades
+	ui.Theme.Block.Border.Fg = current.Border
+	ui.Theme.Block.Title.Fg = current.Title
+
+	return nil
+}
+
+// Next sets the theme following the one in use and returns its name
+func Next() string {
+	next := Names[0]
+	for i, name := range Names {
+		if name == current.Name && i+1 < len(Names) {
+			next = Names[i+1]
+		}
+	}
+
+	Set(next)
+	return next
+}
+
+// Block colours the border and title of a block
+func (t Theme) Block(b *ui.Block) {
+	b.BorderStyle.Fg = t.Border
+	b.TitleStyle.Fg = t.Title
+}
+
+// Table colours a table and its cursor
+func (t Theme) Table(table *widgets.Table) {
+	t.Block(table.Block)
+	table.CursorColor = t.Cursor
+}
+
+// Tables colours tables and their cursors
+func (t Theme) Tables(tables ...*widgets.Table) {
+	for _, table := range tables {
+		t.Table(table)
+	}
+}
+
+// LineGraph colours a graph, drawing its value line in the given colour and
+// the max and min lines as rises and falls
+func (t Theme) LineGraph(graph *widgets.LineGraph, value ui.Color) {
+	t.Block(graph.Block)
+	graph.LineColors["Max"] = t.Up
+	graph.LineColors["Min"] = t.Down
+	graph.LineColors["Value"] = value
+	graph.LineColors["Year Ago"] = t.Accent
+}
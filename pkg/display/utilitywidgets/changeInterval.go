@@ -17,6 +17,7 @@ limitations under the License.
 package utilitywidgets
 
 import (
+	"github.com/Gituser143/cryptgo/pkg/display/theme"
 	"github.com/Gituser143/cryptgo/pkg/widgets"
 	ui "github.com/gizak/termui/v3"
 )
@@ -35,6 +36,44 @@ var IntervalMap map[string]string = map[string]string{
 	"5 Years":  "5yr",
 }
 
+// IntervalLabel returns the duration shown for an interval given in the
+// format required by CoinGecko API, Eg: 24 Hours for 24hr
+func IntervalLabel(interval string) string {
+	for label, val := range IntervalMap {
+		if val == interval {
+			return label
+		}
+	}
+	return ""
+}
+
+// IntervalOf returns the interval of a duration label in the format required
+// by CoinGecko API, or label itself if it is a custom range of dates
+func IntervalOf(label string) string {
+	if interval, ok := IntervalMap[label]; ok {
+		return interval
+	}
+	return label
+}
+
+// StepInterval returns the duration step places after label, stopping at
+// the shortest and longest durations
+func StepInterval(label string, step int) string {
+	for i, row := range intervalRows {
+		if row[0] == label {
+			i += step
+			if i < 0 {
+				i = 0
+			}
+			if i >= len(intervalRows) {
+				i = len(intervalRows) - 1
+			}
+			return intervalRows[i][0]
+		}
+	}
+	return label
+}
+
 type ChangeIntervalDurationTable struct {
 	*widgets.Table
 }
@@ -49,7 +88,7 @@ func NewChangeIntervalPage() *ChangeIntervalDurationTable {
 	c.Table.Title = " Select Duration for Coin History Interval"
 	c.Table.Header = []string{"Duration"}
 	c.Table.Rows = intervalRows
-	c.Table.CursorColor = ui.ColorCyan
+	theme.Current().Table(c.Table)
 	c.Table.ShowCursor = true
 	c.Table.ColWidths = []int{5}
 	c.Table.ColResizer = func() {
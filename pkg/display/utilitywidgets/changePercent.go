@@ -17,6 +17,7 @@ limitations under the License.
 package utilitywidgets
 
 import (
+	"github.com/Gituser143/cryptgo/pkg/display/theme"
 	"github.com/Gituser143/cryptgo/pkg/widgets"
 	ui "github.com/gizak/termui/v3"
 )
@@ -48,7 +49,7 @@ func NewChangePercentPage() *ChangePercentageDurationTable {
 	c.Table.Title = " Select Duration for Percentage Change "
 	c.Table.Header = []string{"Duration"}
 	c.Table.Rows = durationRows
-	c.Table.CursorColor = ui.ColorCyan
+	theme.Current().Table(c.Table)
 	c.Table.ShowCursor = true
 	c.Table.ColWidths = []int{5}
 	c.Table.ColResizer = func() {
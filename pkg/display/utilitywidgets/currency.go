@@ -19,9 +19,14 @@ package utilitywidgets
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/Gituser143/cryptgo/pkg/api"
+	"github.com/Gituser143/cryptgo/pkg/display/theme"
 	"github.com/Gituser143/cryptgo/pkg/utils"
 	"github.com/Gituser143/cryptgo/pkg/widgets"
 	ui "github.com/gizak/termui/v3"
@@ -46,13 +51,213 @@ type AllCurrencyData struct {
 type CurrencyTable struct {
 	*widgets.Table
 	IDMap *CurrencyIDMap
+
+	// Trends of fiat currencies against USD, keyed by code
+	trends        map[string][]float64
+	trendsFetched time.Time
 }
 
-// Currency holds information of a single currency, it used to populate currencyIDMaps
+// Currency holds information of a single currency, it used to populate
+// currencyIDMaps and to display values in the currency
 type Currency struct {
-	Symbol  string
-	RateUSD float64
-	Type    string
+	ID        string  // CoinCap ID, used to save the selected currency
+	Code      string  // ISO 4217 code, or ticker for crypto currencies
+	Symbol    string  // Sign of the currency, Eg: €
+	RateUSD   float64 // Price of a unit in USD
+	Type      string  // fiat or crypto
+	Precision int     // Number of decimal places used to display values
+}
+
+// USD is the default currency
+var USD = Currency{
+	ID:        "united-states-dollar",
+	Code:      "USD",
+	Symbol:    "$",
+	RateUSD:   1,
+	Type:      "fiat",
+	Precision: 2,
+}
+
+// minorUnits holds ISO 4217 currencies whose minor unit isn't 2 decimals
+var minorUnits = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0,
+	"KRW": 0, "PYG": 0, "RWF": 0, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0,
+	"XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+}
+
+// numberFormat holds conventions of writing amounts of a currency
+type numberFormat struct {
+	Decimal     string // Decimal separator
+	Group       string // Separator of thousands
+	SymbolAfter bool   // Symbol follows the amount, Eg: 1.234,56 €
+	Space       bool   // Symbol is spaced from the amount
+}
+
+// groupSpace separates thousands where they are grouped by spaces. It is a
+// narrow no-break space, so values aren't split into words when sorted.
+const groupSpace = "\u202f"
+
+// defaultFormat is how amounts are written in USD, and currencies without
+// conventions of their own, Eg: 1,234.56
+var defaultFormat = numberFormat{Decimal: ".", Group: ","}
+
+// numberFormats holds conventions of currencies written unlike USD, keyed by
+// ISO 4217 code
+var numberFormats = map[string]numberFormat{
+	"EUR": {Decimal: ",", Group: ".", SymbolAfter: true, Space: true},
+	"BRL": {Decimal: ",", Group: ".", Space: true},
+	"ARS": {Decimal: ",", Group: ".", Space: true},
+	"IDR": {Decimal: ",", Group: ".", Space: true},
+	"TRY": {Decimal: ",", Group: "."},
+	"CHF": {Decimal: ".", Group: "'", Space: true},
+	"SEK": {Decimal: ",", Group: groupSpace, SymbolAfter: true, Space: true},
+	"NOK": {Decimal: ",", Group: groupSpace, SymbolAfter: true, Space: true},
+	"DKK": {Decimal: ",", Group: ".", SymbolAfter: true, Space: true},
+	"PLN": {Decimal: ",", Group: groupSpace, SymbolAfter: true, Space: true},
+	"CZK": {Decimal: ",", Group: groupSpace, SymbolAfter: true, Space: true},
+	"HUF": {Decimal: ",", Group: groupSpace, SymbolAfter: true, Space: true},
+	"RUB": {Decimal: ",", Group: groupSpace, SymbolAfter: true, Space: true},
+	"UAH": {Decimal: ",", Group: groupSpace, SymbolAfter: true, Space: true},
+	"ZAR": {Decimal: ".", Group: groupSpace, Space: true},
+}
+
+// getPrecision returns the decimal places values in a currency are shown
+// with. Fiat currencies use their ISO 4217 minor unit and crypto currencies
+// use 8 decimals.
+func getPrecision(code, currencyType string) int {
+	if currencyType == "crypto" {
+		return 8
+	}
+	if precision, ok := minorUnits[code]; ok {
+		return precision
+	}
+	return 2
+}
+
+// Label returns the code and symbol of the currency, Eg: EUR €
+func (c Currency) Label() string {
+	if c.Symbol == "" || c.Symbol == c.Code {
+		return c.Code
+	}
+	return fmt.Sprintf("%s %s", c.Code, c.Symbol)
+}
+
+// Rate returns the price of a unit of the currency in USD, live if FX
+// rates include the currency
+func (c Currency) Rate() float64 {
+	if fx, ok := api.GetFXRate(c.Code); ok {
+		return fx.RateUSD
+	}
+	return c.RateUSD
+}
+
+// Convert converts a value in USD to the currency
+func (c Currency) Convert(usd float64) float64 {
+	rate := c.Rate()
+	if rate == 0 {
+		return usd
+	}
+	return usd / rate
+}
+
+// numberFormat returns the conventions amounts of the currency are written
+// with
+func (c Currency) numberFormat() numberFormat {
+	if f, ok := numberFormats[c.Code]; ok && c.Type != "crypto" {
+		return f
+	}
+	return defaultFormat
+}
+
+// format writes a number with precision decimals, grouping thousands
+func (f numberFormat) format(val float64, precision int) string {
+	s := strconv.FormatFloat(math.Abs(val), 'f', precision, 64)
+
+	whole, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i != -1 {
+		whole, frac = s[:i], s[i+1:]
+	}
+
+	groups := []string{}
+	for len(whole) > 3 {
+		groups = append([]string{whole[len(whole)-3:]}, groups...)
+		whole = whole[:len(whole)-3]
+	}
+	s = strings.Join(append([]string{whole}, groups...), f.Group)
+	if frac != "" {
+		s += f.Decimal + frac
+	}
+
+	// Values rounded to zero aren't signed
+	if val < 0 && strings.Trim(s, "0"+f.Decimal) != "" {
+		s = "-" + s
+	}
+	return s
+}
+
+// withSymbol places the symbol of the currency before or after an amount
+// written in it, or its code if it has no symbol
+func (c Currency) withSymbol(amount string) string {
+	f := c.numberFormat()
+	symbol := c.Symbol
+	if symbol == "" || symbol == c.Code {
+		symbol = c.Code
+		f.SymbolAfter, f.Space = true, true
+	}
+
+	space := ""
+	if f.Space {
+		space = " "
+	}
+	if f.SymbolAfter {
+		return amount + space + symbol
+	}
+	if strings.HasPrefix(amount, "-") {
+		return "-" + symbol + space + amount[1:]
+	}
+	return symbol + space + amount
+}
+
+// FormatValue formats a value in the currency with its precision and
+// separators, Eg: 1.234,56 for EUR
+func (c Currency) FormatValue(val float64) string {
+	return c.numberFormat().format(val, c.Precision)
+}
+
+// Format converts a value in USD to the currency and formats it with the
+// precision and separators of the currency
+func (c Currency) Format(usd float64) string {
+	return c.FormatValue(c.Convert(usd))
+}
+
+// Amount formats a value in the currency along with its symbol, placed as
+// the currency is written, Eg: 1.234,56 € or R$ 1.234,56
+func (c Currency) Amount(val float64) string {
+	return c.withSymbol(c.FormatValue(val))
+}
+
+// Money converts a value in USD to the currency and formats it along with
+// its symbol
+func (c Currency) Money(usd float64) string {
+	return c.Amount(c.Convert(usd))
+}
+
+// Compact converts a value in USD to the currency and formats it rounded to
+// units of thousands, Eg: 1,23 B for EUR
+func (c Currency) Compact(usd float64) string {
+	vals, units := utils.RoundValues(c.Convert(usd), 0)
+	s := c.numberFormat().format(vals[0], 2)
+	if units != "" {
+		s += " " + units
+	}
+	return s
+}
+
+// CompactMoney formats a value in USD as Compact does, along with the
+// symbol of the currency
+func (c Currency) CompactMoney(usd float64) string {
+	return c.withSymbol(c.Compact(usd))
 }
 
 // CurrencyIDMap maps a currency Id to it's symbol and price in USD
@@ -64,13 +269,47 @@ func NewCurencyIDMap() CurrencyIDMap {
 	return c
 }
 
-// Populate fetches currency rates and populates the map
+// Populate fetches currency rates and populates the map. Currencies with
+// FX rates which CoinCap doesn't list are added, with IDs made from their
+// names as CoinCap does, Eg: argentine-peso.
 func (c *CurrencyIDMap) Populate() {
+	c.populateCoinCap()
+
+	// Fetch FX rates if they weren't yet, so a saved currency listed only
+	// by them is found
+	rates := api.GetFXRates()
+	if len(rates) == 0 {
+		api.UpdateFXRates()
+		rates = api.GetFXRates()
+	}
+
+	listed := map[string]bool{}
+	for _, currency := range *c {
+		listed[currency.Code] = true
+	}
+
+	for code, rate := range rates {
+		currencyID := strings.ToLower(strings.Join(strings.Fields(rate.Name), "-"))
+		if _, ok := (*c)[currencyID]; ok || listed[code] {
+			continue
+		}
+
+		(*c)[currencyID] = Currency{
+			ID:        currencyID,
+			Code:      code,
+			Symbol:    rate.Unit,
+			RateUSD:   rate.RateUSD,
+			Type:      rate.Type,
+			Precision: getPrecision(code, rate.Type),
+		}
+	}
+}
+
+// populateCoinCap fetches currency rates from CoinCap and populates the map
+func (c *CurrencyIDMap) populateCoinCap() {
 	url := "https://api.coincap.io/v2/rates"
 	method := "GET"
 
-	client := &http.Client{}
-
 	// Create Request
 	req, err := http.NewRequest(method, url, nil)
 	if err != nil {
@@ -78,9 +317,8 @@ func (c *CurrencyIDMap) Populate() {
 	}
 
 	// Send Request and get response
-	res, err := client.Do(req)
+	res, err := api.Client.Do(req)
 	if err != nil {
-		res.Body.Close()
 		return
 	}
 
@@ -100,22 +338,25 @@ func (c *CurrencyIDMap) Populate() {
 		if err == nil {
 
 			(*c)[currencyID] = Currency{
-				Symbol:  fmt.Sprintf("%s %s", curr.Symbol, curr.CurrencySymbol),
-				RateUSD: rate,
-				Type:    curr.Type,
+				ID:        currencyID,
+				Code:      curr.Symbol,
+				Symbol:    curr.CurrencySymbol,
+				RateUSD:   rate,
+				Type:      curr.Type,
+				Precision: getPrecision(curr.Symbol, curr.Type),
 			}
 		}
 	}
 }
 
-// Get returns the symbol and USD rate for a given currency ID
-// If the given currency ID does not exist in the Map, values
-// for US Dollar are returned
-func (c *CurrencyTable) Get(currencyID string) (string, string, float64) {
+// Get returns the Currency for a given currency ID
+// If the given currency ID does not exist in the Map, US Dollar
+// is returned
+func (c *CurrencyTable) Get(currencyID string) Currency {
 	if val, ok := (*c.IDMap)[currencyID]; ok {
-		return currencyID, val.Symbol, val.RateUSD
+		return val
 	} else {
-		return "united-states-dollar", "USD $", 1
+		return USD
 	}
 }
 
@@ -130,15 +371,16 @@ func NewCurrencyPage() *CurrencyTable {
 	}
 
 	c.Table.Title = " Select Currency "
-	c.Table.Header = []string{"Currency", "Symbol", "Type", "USD rate"}
-	c.Table.CursorColor = ui.ColorCyan
+	c.Table.Header = []string{"Currency", "Symbol", "Type", "USD rate", fmt.Sprintf("Trend (%dd)", fxTrendDays)}
+	theme.Current().Table(c.Table)
 	c.Table.ShowCursor = true
-	c.Table.ColWidths = []int{5, 5, 5, 5}
+	c.Table.ColWidths = []int{5, 5, 5, 5, 5}
 	c.Table.ColResizer = func() {
 		x := c.Table.Inner.Dx()
 		c.Table.ColWidths = []int{
-			4 * x / 10,
+			3 * x / 10,
 			2 * x / 10,
+			1 * x / 10,
 			2 * x / 10,
 			2 * x / 10,
 		}
@@ -175,6 +417,9 @@ func (c *CurrencyTable) Draw(buf *ui.Buffer) {
 	c.Table.Draw(buf)
 }
 
+// trendWidth is the width of sparklines of currency trends
+const trendWidth = 15
+
 // UpdateAll fetches rates of all currencies and updates them as rows in the table
 func (c *CurrencyTable) UpdateRows(allCurrencies bool) {
 	currencies := map[string]bool{
@@ -186,9 +431,20 @@ func (c *CurrencyTable) UpdateRows(allCurrencies bool) {
 		"australian-dollar":      true,
 		"canadian-dollar":        true,
 		"chinese-yuan-renminbi":  true,
+		"bitcoin":                true,
+		"ethereum":               true,
 	}
 
 	c.IDMap.Populate()
+	c.updateTrends()
+
+	// trend returns a sparkline of the currency against USD
+	trend := func(currency Currency) string {
+		if currency.Type != "fiat" {
+			return ""
+		}
+		return utils.Sparkline(c.trends[currency.Code], trendWidth)
+	}
 
 	rows := make([][]string, 0)
 
@@ -198,9 +454,10 @@ func (c *CurrencyTable) UpdateRows(allCurrencies bool) {
 			// Aggregate data
 			row := []string{
 				currencyID,
-				currency.Symbol,
+				currency.Label(),
 				currency.Type,
-				fmt.Sprintf("%.4f", currency.RateUSD),
+				fmt.Sprintf("%.4f", currency.Rate()),
+				trend(currency),
 			}
 
 			rows = append(rows, row)
@@ -208,13 +465,18 @@ func (c *CurrencyTable) UpdateRows(allCurrencies bool) {
 	} else {
 		// Iterate over selected currencies
 		for currencyID := range currencies {
-			currency := (*c.IDMap)[currencyID]
+			currency, ok := (*c.IDMap)[currencyID]
+			if !ok {
+				continue
+			}
+
 			// Aggregate data
 			row := []string{
 				currencyID,
-				currency.Symbol,
+				currency.Label(),
 				currency.Type,
-				fmt.Sprintf("%.4f", currency.RateUSD),
+				fmt.Sprintf("%.4f", currency.Rate()),
+				trend(currency),
 			}
 
 			rows = append(rows, row)
@@ -223,5 +485,5 @@ func (c *CurrencyTable) UpdateRows(allCurrencies bool) {
 
 	// Update table rows and sort alphabetically
 	c.Table.Rows = rows
-	utils.SortData(c.Table.Rows, 0, true, "CURRENCY")
+	utils.SortData(c.Table.Rows, 0, true, utils.CurrencyLayout)
 }
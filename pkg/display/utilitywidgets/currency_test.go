@@ -0,0 +1,149 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utilitywidgets
+
+import "testing"
+
+// Currencies covering the conventions amounts are written with
+var (
+	eur = Currency{Code: "EUR", Symbol: "€", RateUSD: 1.25, Type: "fiat", Precision: 2}
+	brl = Currency{Code: "BRL", Symbol: "R$", RateUSD: 0.2, Type: "fiat", Precision: 2}
+	chf = Currency{Code: "CHF", Symbol: "Fr.", RateUSD: 1, Type: "fiat", Precision: 2}
+	sek = Currency{Code: "SEK", Symbol: "kr", RateUSD: 0.1, Type: "fiat", Precision: 2}
+	jpy = Currency{Code: "JPY", Symbol: "¥", RateUSD: 0.01, Type: "fiat", Precision: 0}
+	kwd = Currency{Code: "KWD", Symbol: "KWD", RateUSD: 4, Type: "fiat", Precision: 3}
+	btc = Currency{Code: "BTC", Symbol: "₿", RateUSD: 50000, Type: "crypto", Precision: 8}
+	xyz = Currency{Code: "XYZ", RateUSD: 1, Type: "fiat", Precision: 2}
+)
+
+func TestCurrencyFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		currency Currency
+		usd      float64
+		want     string
+	}{
+		{"usd", USD, 1234.5, "1,234.50"},
+		{"usd millions", USD, 1234567.891, "1,234,567.89"},
+		{"usd negative", USD, -1234.5, "-1,234.50"},
+		{"usd rounded to zero", USD, -0.001, "0.00"},
+		{"eur comma decimal", eur, 1250, "1.000,00"},
+		{"sek space groups", sek, 123456, "1\u202f234\u202f560,00"},
+		{"chf apostrophe groups", chf, 1234.5, "1'234.50"},
+		{"jpy no decimals", jpy, 1234.56, "123,456"},
+		{"kwd three decimals", kwd, 1, "0.250"},
+		{"btc eight decimals", btc, 25000, "0.50000000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.currency.Format(tt.usd); got != tt.want {
+				t.Errorf("Format(%v) = %q, want %q", tt.usd, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCurrencyMoney(t *testing.T) {
+	tests := []struct {
+		name     string
+		currency Currency
+		usd      float64
+		want     string
+	}{
+		{"symbol before", USD, 1234.5, "$1,234.50"},
+		{"sign before symbol", USD, -5, "-$5.00"},
+		{"symbol after", eur, 1250, "1.000,00 €"},
+		{"spaced symbol before", brl, 246.9, "R$ 1.234,50"},
+		{"spaced negative", brl, -2, "-R$ 10,00"},
+		{"symbol after space groups", sek, 123456, "1\u202f234\u202f560,00 kr"},
+		{"code without symbol", xyz, 12, "12.00 XYZ"},
+		{"symbol same as code", kwd, 10, "2.500 KWD"},
+		{"crypto", btc, 100000, "₿2.00000000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.currency.Money(tt.usd); got != tt.want {
+				t.Errorf("Money(%v) = %q, want %q", tt.usd, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCurrencyCompact(t *testing.T) {
+	tests := []struct {
+		name     string
+		currency Currency
+		usd      float64
+		want     string
+		wantSign string
+	}{
+		{"below thousand", USD, 999, "999.00", "$999.00"},
+		{"millions", USD, 1234567, "1.20 M", "$1.20 M"},
+		{"trillions", USD, 2.5e12, "2.50 T", "$2.50 T"},
+		{"eur billions", eur, 2.5e9, "2,00 B", "2,00 B €"},
+		{"jpy keeps two decimals", jpy, 12345, "1.20 M", "¥1.20 M"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.currency.Compact(tt.usd); got != tt.want {
+				t.Errorf("Compact(%v) = %q, want %q", tt.usd, got, tt.want)
+			}
+			if got := tt.currency.CompactMoney(tt.usd); got != tt.wantSign {
+				t.Errorf("CompactMoney(%v) = %q, want %q", tt.usd, got, tt.wantSign)
+			}
+		})
+	}
+}
+
+func TestCurrencyLabel(t *testing.T) {
+	tests := []struct {
+		currency Currency
+		want     string
+	}{
+		{USD, "USD $"},
+		{eur, "EUR €"},
+		{kwd, "KWD"},
+		{xyz, "XYZ"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.currency.Label(); got != tt.want {
+			t.Errorf("Label() of %s = %q, want %q", tt.currency.Code, got, tt.want)
+		}
+	}
+}
+
+func TestGetPrecision(t *testing.T) {
+	tests := []struct {
+		code, currencyType string
+		want               int
+	}{
+		{"USD", "fiat", 2},
+		{"JPY", "fiat", 0},
+		{"BHD", "fiat", 3},
+		{"BTC", "crypto", 8},
+	}
+
+	for _, tt := range tests {
+		if got := getPrecision(tt.code, tt.currencyType); got != tt.want {
+			t.Errorf("getPrecision(%q, %q) = %d, want %d", tt.code, tt.currencyType, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utilitywidgets
+
+import (
+	"fmt"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+)
+
+// FavouritesFooter returns aggregate stats of favourites to be shown in the
+// footer of a favourites table, Eg: MCap 1.23 T | 24h ▲ 2.31% | 4▲ 1▼. An
+// empty string is returned if there are no favourites.
+func FavouritesFooter(stats api.FavouriteStats, currency Currency) string {
+	if stats.Count == 0 {
+		return ""
+	}
+
+	change := fmt.Sprintf("▲ %.2f%%", stats.AverageChange24h)
+	if stats.AverageChange24h < 0 {
+		change = fmt.Sprintf("▼ %.2f%%", -stats.AverageChange24h)
+	}
+	return fmt.Sprintf("MCap %s | 24h %s | %d▲ %d▼",
+		currency.Compact(stats.MarketCap), change, stats.Advancing, stats.Declining)
+}
@@ -0,0 +1,100 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utilitywidgets
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+)
+
+const (
+	// fxHistoryURL serves daily reference rates of fiat currencies
+	fxHistoryURL = "https://api.frankfurter.app"
+	// fxTrendDays is the number of days trends of currencies are shown for
+	fxTrendDays = 30
+	// fxTrendRefresh is how long fetched trends are reused
+	fxTrendRefresh = time.Hour
+)
+
+// fxClient is used to fetch FX history, timed out so that the currency
+// table isn't held up
+var fxClient = &http.Client{Timeout: time.Duration(5) * time.Second}
+
+// fxHistory holds daily rates of currencies per USD, keyed by date
+type fxHistory struct {
+	Rates map[string]map[string]float64 `json:"rates"`
+}
+
+// getFXTrends returns the daily price in USD of fiat currencies over the
+// last days, oldest first, keyed by ISO 4217 code. A rising trend means the
+// currency strengthened against USD.
+func getFXTrends(days int) (map[string][]float64, error) {
+	start := time.Now().AddDate(0, 0, -days).Format("2006-01-02")
+	url := fmt.Sprintf("%s/%s..?from=USD", fxHistoryURL, start)
+
+	res, err := fxClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned %d", url, res.StatusCode)
+	}
+
+	history := fxHistory{}
+	if err := json.NewDecoder(res.Body).Decode(&history); err != nil {
+		return nil, err
+	}
+
+	// Dates are in YYYY-MM-DD, so they sort in order
+	dates := []string{}
+	for date := range history.Rates {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	trends := map[string][]float64{}
+	for _, date := range dates {
+		for code, rate := range history.Rates[date] {
+			if rate > 0 {
+				trends[code] = append(trends[code], 1/rate)
+			}
+		}
+	}
+
+	return trends, nil
+}
+
+// updateTrends fetches trends of fiat currencies if they were not fetched
+// within fxTrendRefresh. Previously fetched trends are kept on errors.
+func (c *CurrencyTable) updateTrends() {
+	if time.Since(c.trendsFetched) < fxTrendRefresh {
+		return
+	}
+
+	trends, err := getFXTrends(fxTrendDays)
+	if err != nil {
+		return
+	}
+
+	c.trends = trends
+	c.trendsFetched = time.Now()
+}
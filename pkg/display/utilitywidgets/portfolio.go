@@ -18,14 +18,14 @@ package utilitywidgets
 
 import (
 	"fmt"
-	"net/http"
 	"strings"
 	"sync"
 
+	"github.com/Gituser143/cryptgo/pkg/api"
+	"github.com/Gituser143/cryptgo/pkg/display/theme"
 	"github.com/Gituser143/cryptgo/pkg/utils"
 	"github.com/Gituser143/cryptgo/pkg/widgets"
 	ui "github.com/gizak/termui/v3"
-	gecko "github.com/superoo7/go-gecko/v3"
 )
 
 type PortfolioTable struct {
@@ -40,7 +40,7 @@ func NewPortfolioPage() *PortfolioTable {
 
 	p.Table.Title = " Portfolio "
 	p.Table.Header = []string{"Coin", "Symbol", "Price", "Holding", "Balance"}
-	p.Table.CursorColor = ui.ColorCyan
+	theme.Current().Table(p.Table)
 	p.Table.ShowCursor = true
 	p.Table.ColWidths = []int{5, 5, 5, 5, 5}
 	p.Table.ColResizer = func() {
@@ -80,14 +80,12 @@ func (p *PortfolioTable) Draw(buf *ui.Buffer) {
 }
 
 // Update Portfolio data
-func (p *PortfolioTable) UpdateRows(portfolio map[string]float64, currency string, currencyVal float64) {
+func (p *PortfolioTable) UpdateRows(portfolio map[string]float64, currency Currency) {
 
 	var wg sync.WaitGroup
 	var m sync.Mutex
 
-	client := &http.Client{}
-
-	geckoClient := gecko.NewClient(client)
+	geckoClient := api.NewGeckoClient()
 
 	rows := [][]string{}
 	sum := 0.0
@@ -106,13 +104,13 @@ func (p *PortfolioTable) UpdateRows(portfolio map[string]float64, currency strin
 			row := []string{
 				data.Name,
 				strings.ToUpper(data.Symbol),
-				fmt.Sprintf("%.2f", p/currencyVal),
+				currency.Format(p),
 				fmt.Sprintf("%.6f", amt),
-				fmt.Sprintf("%.4f", p*amt/currencyVal),
+				currency.Format(p * amt),
 			}
 
 			m.Lock()
-			sum += p * amt / currencyVal
+			sum += currency.Convert(p * amt)
 			rows = append(rows, row)
 			m.Unlock()
 
@@ -121,9 +119,9 @@ func (p *PortfolioTable) UpdateRows(portfolio map[string]float64, currency strin
 
 	wg.Wait()
 
-	p.Header[2] = fmt.Sprintf("Price (%s)", currency)
-	p.Header[4] = fmt.Sprintf("Balance (%s)", currency)
+	p.Header[2] = fmt.Sprintf("Price (%s)", currency.Label())
+	p.Header[4] = fmt.Sprintf("Balance (%s)", currency.Label())
 	p.Rows = rows
-	p.Title = fmt.Sprintf(" Portfolio: %.4f %s ", sum, currency)
-	utils.SortData(p.Rows, 4, false, "PORTFOLIO")
+	p.Title = fmt.Sprintf(" Portfolio: %s ", currency.Amount(sum))
+	utils.SortData(p.Rows, 4, false, utils.PortfolioLayout)
 }
@@ -0,0 +1,117 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utilitywidgets
+
+import (
+	"image"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
+	ui "github.com/gizak/termui/v3"
+	rw "github.com/mattn/go-runewidth"
+)
+
+// screensaverCycle is how long each price is shown, and how often the
+// screensaver moves and is redrawn
+const screensaverCycle = time.Duration(5) * time.Second
+
+// Screensaver implements a full screen clock and ticker of prices, shown
+// after IdleTimeout without key presses. Its text moves every cycle so it
+// doesn't burn into the screen.
+type Screensaver struct {
+	*ui.Block
+
+	Items []string // Prices cycled through, Eg: BTC  $43,210.12  ▲ 2.31
+
+	lastKey time.Time
+	drawn   int64 // Cycle last drawn
+}
+
+// NewScreensaver creates and returns a Screensaver instance
+func NewScreensaver() *Screensaver {
+	s := &Screensaver{
+		Block:   ui.NewBlock(),
+		lastKey: time.Now(),
+		drawn:   -1,
+	}
+	s.Border = false
+	return s
+}
+
+// Touch records a key press, putting off the screensaver
+func (s *Screensaver) Touch() {
+	s.lastKey = time.Now()
+}
+
+// Due returns true once no key was pressed for IdleTimeout
+func (s *Screensaver) Due() bool {
+	return utils.IdleTimeout > 0 && time.Since(s.lastKey) >= utils.IdleTimeout
+}
+
+// Changed returns true if the screensaver moved since it was last drawn, so
+// it is only redrawn once a cycle
+func (s *Screensaver) Changed() bool {
+	return s.cycle() != s.drawn
+}
+
+// Resize resizes the screensaver to fill the terminal, drawing it again if
+// the terminal was resized
+func (s *Screensaver) Resize(termWidth, termHeight int) {
+	if s.GetRect() != image.Rect(0, 0, termWidth, termHeight) {
+		s.drawn = -1
+	}
+	s.SetRect(0, 0, termWidth, termHeight)
+}
+
+// cycle returns the number of the current cycle
+func (s *Screensaver) cycle() int64 {
+	return time.Now().UnixNano() / int64(screensaverCycle)
+}
+
+// Draw draws the clock and the price of the current cycle, at a position
+// picked from the cycle
+func (s *Screensaver) Draw(buf *ui.Buffer) {
+	cycle := s.cycle()
+	s.drawn = cycle
+
+	lines := []string{time.Now().Format("15:04")}
+	if len(s.Items) > 0 {
+		lines = append(lines, "", s.Items[int(cycle%int64(len(s.Items)))])
+	}
+
+	width := 0
+	for _, line := range lines {
+		width = ui.MaxInt(width, rw.StringWidth(line))
+	}
+
+	// Spread positions over the screen, stepping by primes so they don't
+	// repeat soon
+	free := image.Pt(s.Inner.Dx()-width, s.Inner.Dy()-len(lines))
+	pos := s.Inner.Min
+	if free.X > 0 {
+		pos.X += int(cycle * 37 % int64(free.X+1))
+	}
+	if free.Y > 0 {
+		pos.Y += int(cycle * 17 % int64(free.Y+1))
+	}
+
+	for i, line := range lines {
+		// Centre lines on each other
+		x := pos.X + (width-rw.StringWidth(line))/2
+		buf.SetString(line, ui.NewStyle(ui.ColorClear), image.Pt(x, pos.Y+i))
+	}
+}
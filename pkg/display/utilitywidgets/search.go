@@ -0,0 +1,94 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utilitywidgets
+
+import (
+	"fmt"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+	"github.com/Gituser143/cryptgo/pkg/display/theme"
+	"github.com/Gituser143/cryptgo/pkg/widgets"
+	ui "github.com/gizak/termui/v3"
+)
+
+// SearchTable lists coins matching a search, best matches first
+type SearchTable struct {
+	*widgets.Table
+	Results []api.CoinListing
+}
+
+// NewSearchPage creates, initialises and returns a pointer to an instance of
+// SearchTable
+func NewSearchPage() *SearchTable {
+	s := &SearchTable{
+		Table: widgets.NewTable(),
+	}
+
+	s.Table.Title = " Search "
+	s.Table.Header = []string{"Symbol", "Name", "ID"}
+	theme.Current().Table(s.Table)
+	s.Table.ShowCursor = true
+	s.Table.ColResizer = func() {
+		x := s.Table.Inner.Dx()
+		s.Table.ColWidths = []int{
+			2 * x / 10,
+			4 * x / 10,
+			4 * x / 10,
+		}
+	}
+	return s
+}
+
+// UpdateRows lists results of a search for query
+func (s *SearchTable) UpdateRows(query string, results []api.CoinListing) {
+	s.Results = results
+	s.Table.Title = fmt.Sprintf(" Search: %s ", query)
+	if len(results) == 0 {
+		s.Table.Title = fmt.Sprintf(" Search: %s - no matches ", query)
+	}
+
+	rows := [][]string{}
+	for _, coin := range results {
+		rows = append(rows, []string{coin.Symbol, coin.Name, coin.ID})
+	}
+	s.Table.Rows = rows
+	s.Table.SelectedRow = 0
+}
+
+// Resize resizes the widget based on specified width and height
+func (s *SearchTable) Resize(termWidth, termHeight int) {
+	textWidth := 80
+
+	textHeight := len(s.Table.Rows) + 4
+	x := (termWidth - textWidth) / 2
+	y := (termHeight - textHeight) / 2
+	if x < 0 {
+		x = 0
+		textWidth = termWidth
+	}
+	if y < 0 {
+		y = 0
+		textHeight = termHeight
+	}
+
+	s.Table.SetRect(x, y, textWidth+x, textHeight+y)
+}
+
+// Draw puts the required text into the widget
+func (s *SearchTable) Draw(buf *ui.Buffer) {
+	s.Table.Draw(buf)
+}
@@ -0,0 +1,44 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utilitywidgets
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+)
+
+// StaleWarning returns a warning about the first stale coin along with its
+// suggested replacements, to be shown in a table title. An empty string is
+// returned if there are no stale coins.
+func StaleWarning(staleCoins []api.StaleCoin) string {
+	if len(staleCoins) == 0 {
+		return ""
+	}
+
+	coin := staleCoins[0]
+	warning := fmt.Sprintf("! %s %s", coin.ID, coin.Reason)
+	if len(coin.Suggestions) > 0 {
+		warning += ", try " + strings.Join(coin.Suggestions, ", ")
+	}
+	if len(staleCoins) > 1 {
+		warning += fmt.Sprintf(" (+%d more)", len(staleCoins)-1)
+	}
+
+	return warning
+}
@@ -0,0 +1,165 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utilitywidgets
+
+import (
+	"fmt"
+	"image"
+	"strings"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+	"github.com/Gituser143/cryptgo/pkg/utils"
+	ui "github.com/gizak/termui/v3"
+)
+
+// statusErrorAge is how long the last error is shown after it happened,
+// unless its data is still failing
+const statusErrorAge = time.Duration(5) * time.Minute
+
+// StatusBar implements a line along the bottom of a page showing the source
+// data is served from, how long ago each type of data was updated and the
+// last error fetching it, followed by the error of the last reload of the
+// config file and notes of features which are unavailable, Eg:
+// source: coingecko │ history 4s │ details 3m stale │ error 20s ago: ...
+type StatusBar struct {
+	*ui.Block
+
+	Source    string
+	Statuses  []api.Status
+	ReloadErr error
+	Notes     []string
+}
+
+// NewStatusBar creates and returns a StatusBar instance
+func NewStatusBar() *StatusBar {
+	s := &StatusBar{
+		Block: ui.NewBlock(),
+	}
+	s.Border = false
+	return s
+}
+
+// Update sets the source of src, statuses of types of data and the error
+// of the last reload to be shown
+func (s *StatusBar) Update(src api.Source, types ...string) {
+	s.Source = api.SourceStatus(src)
+	s.Statuses = api.GetStatus(types...)
+	s.ReloadErr = utils.ReloadError()
+}
+
+// statusSegment holds text of the status bar drawn in a style
+type statusSegment struct {
+	text  string
+	style ui.Style
+}
+
+// formatAge returns a duration rounded to its largest unit, Eg: 3m
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+}
+
+// segments returns the text of the status bar
+func (s *StatusBar) segments() []statusSegment {
+	plain := ui.NewStyle(ui.ColorClear)
+	note := ui.NewStyle(ui.ColorYellow)
+	stale := ui.NewStyle(ui.ColorYellow, ui.ColorClear, ui.ModifierBold)
+	failed := ui.NewStyle(ui.ColorRed, ui.ColorClear, ui.ModifierBold)
+
+	segments := []statusSegment{{text: "source: " + s.Source, style: plain}}
+
+	var last api.Status
+	for _, status := range s.Statuses {
+		segment := statusSegment{style: plain}
+		switch {
+		case status.Updated.IsZero():
+			segment.text = status.Type + " failed"
+			segment.style = failed
+		case status.Stale():
+			segment.text = fmt.Sprintf("%s %s stale", status.Type, formatAge(time.Since(status.Updated)))
+			segment.style = stale
+		default:
+			segment.text = fmt.Sprintf("%s %s", status.Type, formatAge(time.Since(status.Updated)))
+		}
+		segments = append(segments, segment)
+
+		if status.Err != nil && status.Failed.After(last.Failed) {
+			last = status
+		}
+	}
+
+	// Show the last error while its data is failing, or for a while after
+	if last.Err != nil && (last.Failing() || time.Since(last.Failed) < statusErrorAge) {
+		// Errors may hold response bodies spanning lines
+		message := strings.Join(strings.Fields(last.Err.Error()), " ")
+		segments = append(segments, statusSegment{
+			text:  fmt.Sprintf("error %s ago: %s: %s", formatAge(time.Since(last.Failed)), last.Type, message),
+			style: failed,
+		})
+	}
+
+	// Show the config is not reloaded until a reload succeeds
+	if s.ReloadErr != nil {
+		segments = append(segments, statusSegment{
+			text:  "config not reloaded: " + strings.Join(strings.Fields(s.ReloadErr.Error()), " "),
+			style: failed,
+		})
+	}
+
+	for _, text := range s.Notes {
+		segments = append(segments, statusSegment{text: text, style: note})
+	}
+
+	return segments
+}
+
+// Draw draws segments of the status bar separated by bars, cutting off
+// those which don't fit
+func (s *StatusBar) Draw(buf *ui.Buffer) {
+	s.Block.Draw(buf)
+
+	if s.Inner.Dy() < 1 {
+		return
+	}
+
+	x, y := s.Inner.Min.X+1, s.Inner.Min.Y
+	for i, segment := range s.segments() {
+		text := []rune(segment.text)
+		if i > 0 {
+			buf.SetString(" │ ", ui.NewStyle(ui.ColorClear), image.Pt(x, y))
+			x += 3
+		}
+
+		space := s.Inner.Max.X - x - 1
+		if space <= 0 {
+			return
+		}
+		if len(text) > space {
+			text = append(text[:space-1], '…')
+		}
+
+		buf.SetString(string(text), segment.style, image.Pt(x, y))
+		x += len(text)
+	}
+}
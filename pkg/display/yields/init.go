@@ -0,0 +1,105 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package yields
+
+import (
+	"github.com/Gituser143/cryptgo/pkg/widgets"
+	ui "github.com/gizak/termui/v3"
+)
+
+// yieldsPage holds UI items for the yields page
+type yieldsPage struct {
+	Grid         *ui.Grid
+	SummaryTable *widgets.Table
+	FundingTable *widgets.Table
+	PoolsTable   *widgets.Table
+}
+
+func newYieldsPage(w, h int) *yieldsPage {
+	page := &yieldsPage{
+		Grid:         ui.NewGrid(),
+		SummaryTable: widgets.NewTable(),
+		FundingTable: widgets.NewTable(),
+		PoolsTable:   widgets.NewTable(),
+	}
+
+	page.init(w, h)
+
+	return page
+}
+
+func (page *yieldsPage) init(w, h int) {
+	// Initialise Summary table
+	page.SummaryTable.Title = " Stablecoin Yields "
+	page.SummaryTable.BorderStyle.Fg = ui.ColorCyan
+	page.SummaryTable.TitleStyle.Fg = ui.ColorClear
+	page.SummaryTable.Header = []string{"Summary", ""}
+	page.SummaryTable.ColResizer = func() {
+		x := page.SummaryTable.Inner.Dx()
+		page.SummaryTable.ColWidths = []int{
+			x / 2,
+			x / 2,
+		}
+	}
+	page.SummaryTable.ShowCursor = false
+
+	// Initialise Funding table
+	page.FundingTable.Title = " Funding Rates (Binance Perpetuals) "
+	page.FundingTable.BorderStyle.Fg = ui.ColorCyan
+	page.FundingTable.TitleStyle.Fg = ui.ColorClear
+	page.FundingTable.Header = []string{"Contract", "Funding % (8h)", "Funding APR %", "Carry vs Stables %"}
+	page.FundingTable.ColResizer = func() {
+		x := page.FundingTable.Inner.Dx()
+		page.FundingTable.ColWidths = []int{
+			x / 4,
+			x / 4,
+			x / 4,
+			x / 4,
+		}
+	}
+	page.FundingTable.ShowCursor = false
+	page.FundingTable.ChangeCol[3] = true
+
+	// Initialise Pools table
+	page.PoolsTable.Title = " Stablecoin Pools "
+	page.PoolsTable.BorderStyle.Fg = ui.ColorCyan
+	page.PoolsTable.TitleStyle.Fg = ui.ColorClear
+	page.PoolsTable.Header = []string{"Project", "Chain", "Symbol", "TVL (M USD)", "APY %"}
+	page.PoolsTable.ColResizer = func() {
+		x := page.PoolsTable.Inner.Dx()
+		page.PoolsTable.ColWidths = []int{
+			x / 5,
+			x / 5,
+			x / 5,
+			x / 5,
+			x / 5,
+		}
+	}
+	page.PoolsTable.ShowCursor = true
+	page.PoolsTable.CursorColor = ui.ColorCyan
+
+	// Set Grid layout
+	page.Grid.Set(
+		ui.NewRow(0.3,
+			ui.NewCol(0.3, page.SummaryTable),
+			ui.NewCol(0.7, page.FundingTable),
+		),
+		ui.NewRow(0.7, page.PoolsTable),
+	)
+
+	page.Grid.SetRect(0, 0, w, h)
+}
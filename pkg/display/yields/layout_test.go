@@ -0,0 +1,65 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package yields
+
+import (
+	"testing"
+
+	"github.com/Gituser143/cryptgo/pkg/display/layout/layouttest"
+	ui "github.com/gizak/termui/v3"
+)
+
+// fillYieldsPage fills the page with stablecoin pools from DefiLlama and
+// funding rates of Binance perpetuals
+func fillYieldsPage(page *yieldsPage) {
+	page.SummaryTable.Rows = [][]string{
+		{"Pools", "5"},
+		{"Total TVL", "3.12 B USD"},
+		{"Avg APY (TVL weighted)", "4.86 %"},
+	}
+	page.FundingTable.Rows = [][]string{
+		{"BTCUSDT", "0.0100", "10.95", "▲ 6.09"},
+		{"ETHUSDT", "0.0087", "9.53", "▲ 4.67"},
+		{"SOLUSDT", "-0.0021", "-2.30", "▼ 7.16"},
+	}
+	page.PoolsTable.Rows = [][]string{
+		{"aave-v3", "Ethereum", "USDC", "1204.55", "5.12"},
+		{"compound-v3", "Ethereum", "USDC", "841.30", "4.75"},
+		{"curve-dex", "Ethereum", "DAI-USDC-USDT", "612.08", "3.21"},
+		{"aave-v3", "Arbitrum", "USDT", "298.74", "6.40"},
+		{"spark", "Ethereum", "DAI", "163.91", "5.00"},
+	}
+}
+
+func TestLayout(t *testing.T) {
+	layouttest.Run(t, []layouttest.Case{
+		{
+			Name: "yields_fetching",
+			Page: func(w, h int) []ui.Drawable {
+				return []ui.Drawable{newYieldsPage(w, h).Grid}
+			},
+		},
+		{
+			Name: "yields",
+			Page: func(w, h int) []ui.Drawable {
+				page := newYieldsPage(w, h)
+				fillYieldsPage(page)
+				return []ui.Drawable{page.Grid}
+			},
+		},
+	})
+}
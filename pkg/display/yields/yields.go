@@ -0,0 +1,275 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package yields
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+	"github.com/Gituser143/cryptgo/pkg/utils"
+	"github.com/Gituser143/cryptgo/pkg/widgets"
+	ui "github.com/gizak/termui/v3"
+)
+
+const (
+	UP_ARROW   = "▲"
+	DOWN_ARROW = "▼"
+)
+
+// DisplayYields displays stablecoin yields alongside funding rates of major
+// perpetuals, showing the carry of a funding trade over holding stablecoins
+func DisplayYields(ctx context.Context, dataChannel chan api.YieldData) error {
+
+	// Initialise UI
+	if err := ui.Init(); err != nil {
+		return fmt.Errorf("failed to initialise termui: %v", err)
+	}
+	defer ui.Close()
+
+	// Initialise page
+	page := newYieldsPage(ui.TerminalDimensions())
+	selectedTable := page.PoolsTable
+	utilitySelected := ""
+
+	// Initialise help menu
+	help := widgets.NewHelpMenu()
+	help.SelectHelpMenu("YIELDS")
+
+	// Variables for sorting PoolsTable
+	poolSortIdx := -1
+	poolSortAsc := false
+	poolHeader := []string{
+		"Project",
+		"Chain",
+		"Symbol",
+		"TVL (M USD)",
+		"APY %",
+	}
+
+	previousKey := ""
+
+	// Banner showing why config wasn't reloaded
+	banner := widgets.NewBanner()
+	banner.Title = " Config "
+
+	// UpdateUI to refresh UI
+	updateUI := func() {
+		// Get Terminal Dimensions
+		w, h := ui.TerminalDimensions()
+		page.Grid.SetRect(0, 0, w, h)
+
+		// Clear UI
+		ui.Clear()
+
+		// Render required widgets
+		switch utilitySelected {
+		case "HELP":
+			help.Resize(w, h)
+			ui.Render(help)
+		default:
+			ui.Render(page.Grid)
+		}
+
+		if banner.Active() {
+			banner.Resize(w, h)
+			ui.Render(banner)
+		}
+	}
+
+	// Render Empty UI
+	updateUI()
+
+	// Create Channel to get keyboard events
+	uiEvents := ui.PollEvents()
+
+	// Create ticker to periodically refresh UI
+	t := time.NewTicker(utils.UIRefresh)
+	tick := t.C
+
+	// Reload config, Eg: after the config file was edited. The
+	// previous config is kept if the file can't be read or is invalid, and
+	// the error is flashed on the banner.
+	reload := func() {
+		if err := utils.Reload(); err != nil {
+			banner.Show("Config not reloaded: "+err.Error(), time.Duration(5)*time.Second)
+		}
+		updateUI()
+	}
+
+	// Create channels to get suspend and reload signals
+	suspendSignals := utils.SuspendSignals()
+	reloadSignals := utils.ReloadSignals()
+
+	for {
+		select {
+		case <-ctx.Done(): // Context cancelled, exit
+			return ctx.Err()
+
+		case <-suspendSignals: // SIGTSTP sent from outside the UI
+			if err := utils.Suspend(); err != nil {
+				return err
+			}
+			updateUI()
+
+		case <-reloadSignals: // SIGHUP
+			reload()
+
+		case e := <-uiEvents:
+			switch e.ID {
+
+			// handle button events
+			case "q", "<C-c>":
+				return fmt.Errorf("UI Closed")
+
+			case "<Escape>":
+				if utilitySelected != "" {
+					utilitySelected = ""
+					selectedTable = page.PoolsTable
+					selectedTable.ShowCursor = true
+				}
+
+			case "<Resize>":
+				updateUI()
+
+			case "<C-z>":
+				if err := utils.Suspend(); err != nil {
+					return err
+				}
+				updateUI()
+
+			case "<C-r>":
+				reload()
+
+			case "?":
+				selectedTable.ShowCursor = false
+				selectedTable = help.Table
+				selectedTable.ShowCursor = true
+				utilitySelected = "HELP"
+
+			// Navigations
+			case "j", "<Down>":
+				selectedTable.ScrollDown()
+
+			case "k", "<Up>":
+				selectedTable.ScrollUp()
+
+			case "<C-d>":
+				selectedTable.ScrollHalfPageDown()
+
+			case "<C-u>":
+				selectedTable.ScrollHalfPageUp()
+
+			case "<C-f>":
+				selectedTable.ScrollPageDown()
+
+			case "<C-b>":
+				selectedTable.ScrollPageUp()
+
+			case "g":
+				if previousKey == "g" {
+					selectedTable.ScrollTop()
+				}
+
+			case "<Home>":
+				selectedTable.ScrollTop()
+
+			case "G", "<End>":
+				selectedTable.ScrollBottom()
+
+			// handle sorting
+			case "1", "2", "3", "4", "5":
+				// Sort Ascending
+				if utilitySelected == "" {
+					idx, _ := strconv.Atoi(e.ID)
+					poolSortIdx = idx - 1
+					page.PoolsTable.Header = append([]string{}, poolHeader...)
+					page.PoolsTable.Header[poolSortIdx] = poolHeader[poolSortIdx] + " " + UP_ARROW
+					poolSortAsc = true
+					utils.SortData(page.PoolsTable.Rows, poolSortIdx, poolSortAsc, utils.YieldsLayout)
+				}
+
+			case "<F1>", "<F2>", "<F3>", "<F4>", "<F5>":
+				// Sort Descending
+				if utilitySelected == "" {
+					page.PoolsTable.Header = append([]string{}, poolHeader...)
+					idx, _ := strconv.Atoi(e.ID[2:3])
+					poolSortIdx = idx - 1
+					page.PoolsTable.Header[poolSortIdx] = poolHeader[poolSortIdx] + " " + DOWN_ARROW
+					poolSortAsc = false
+					utils.SortData(page.PoolsTable.Rows, poolSortIdx, poolSortAsc, utils.YieldsLayout)
+				}
+			}
+
+			updateUI()
+			if previousKey == "g" {
+				previousKey = ""
+			} else {
+				previousKey = e.ID
+			}
+
+		case data := <-dataChannel:
+			// Update pools table
+			rows := [][]string{}
+			for _, pool := range data.Pools {
+				rows = append(rows, []string{
+					pool.Project,
+					pool.Chain,
+					pool.Symbol,
+					fmt.Sprintf("%.2f", pool.TVL/1e6),
+					fmt.Sprintf("%.2f", pool.APY),
+				})
+			}
+			page.PoolsTable.Rows = rows
+			utils.SortData(page.PoolsTable.Rows, poolSortIdx, poolSortAsc, utils.YieldsLayout)
+
+			// Update summary table
+			TVLVals, units := utils.RoundValues(data.TotalTVL, 0)
+			page.SummaryTable.Rows = [][]string{
+				{"Pools", fmt.Sprintf("%d", len(data.Pools))},
+				{"Total TVL", fmt.Sprintf("%.2f %s USD", TVLVals[0], units)},
+				{"Avg APY (TVL weighted)", fmt.Sprintf("%.2f %%", data.AverageAPY)},
+			}
+
+			// Update funding table, carry is the funding APR in excess of
+			// the average stablecoin yield
+			rows = [][]string{}
+			for _, funding := range data.Funding {
+				carry := funding.AnnualRate - data.AverageAPY
+				carryStr := fmt.Sprintf("%s %.2f", UP_ARROW, carry)
+				if carry < 0 {
+					carryStr = fmt.Sprintf("%s %.2f", DOWN_ARROW, -carry)
+				}
+				rows = append(rows, []string{
+					funding.Symbol,
+					fmt.Sprintf("%.4f", funding.Rate),
+					fmt.Sprintf("%.2f", funding.AnnualRate),
+					carryStr,
+				})
+			}
+			if len(rows) == 0 {
+				rows = append(rows, []string{"NA", "NA", "NA", "NA"})
+			}
+			page.FundingTable.Rows = rows
+
+		case <-tick:
+			updateUI()
+		}
+	}
+}
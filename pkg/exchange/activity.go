@@ -0,0 +1,150 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exchange
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
+)
+
+// activityRefresh is how often fills and open orders of connected accounts
+// are fetched
+const activityRefresh = time.Duration(1) * time.Minute
+
+// Fill is an executed trade of an account
+type Fill struct {
+	Exchange string
+	ID       string
+	Time     time.Time
+	Base     string // Upper case symbol of the asset traded, Eg: BTC
+	Quote    string // Upper case symbol of the asset it is priced in, Eg: USDT
+	Side     string
+	Quantity float64
+	Price    float64 // In Quote
+	Amount   float64 // In Quote, before fees
+	Fee      float64
+	FeeAsset string // Upper case symbol
+}
+
+// History reads recent fills and open orders of an account. Exchanges which
+// don't serve them to read only keys don't implement it.
+type History interface {
+	// Fills returns recent fills of the account
+	Fills(ctx context.Context, creds Credentials) ([]Fill, error)
+
+	// OpenOrders returns orders of the account not yet filled or
+	// cancelled
+	OpenOrders(ctx context.Context, creds Credentials) ([]Order, error)
+}
+
+// Activity holds recent fills and open orders of connected accounts
+type Activity struct {
+	Fills  []Fill           // Latest first
+	Orders []Order          // Latest first
+	Errors map[string]error // Errors of exchanges whose activity couldn't be fetched
+}
+
+// GetActivity fetches recent fills and open orders of every connected
+// account. Accounts which fail, or whose exchange doesn't serve them, are
+// reported in Errors, rather than failing the others.
+func GetActivity(ctx context.Context) Activity {
+	activity := Activity{
+		Fills:  []Fill{},
+		Orders: []Order{},
+		Errors: map[string]error{},
+	}
+
+	names, err := Connected()
+	if err != nil {
+		activity.Errors["accounts"] = err
+	}
+	for _, name := range names {
+		ex, err := get(name)
+		if err != nil {
+			activity.Errors[name] = err
+			continue
+		}
+		history, ok := ex.(History)
+		if !ok {
+			activity.Errors[name] = fmt.Errorf("%s doesn't serve fills or open orders to read only keys", name)
+			continue
+		}
+		creds, err := credentials(name)
+		if err != nil {
+			activity.Errors[name] = err
+			continue
+		}
+
+		fills, err := history.Fills(ctx, creds)
+		if err != nil {
+			activity.Errors[name] = err
+			continue
+		}
+		orders, err := history.OpenOrders(ctx, creds)
+		if err != nil {
+			activity.Errors[name] = err
+			continue
+		}
+		activity.Fills = append(activity.Fills, fills...)
+		activity.Orders = append(activity.Orders, orders...)
+	}
+
+	sort.SliceStable(activity.Fills, func(i, j int) bool {
+		return activity.Fills[i].Time.After(activity.Fills[j].Time)
+	})
+	sort.SliceStable(activity.Orders, func(i, j int) bool {
+		return activity.Orders[i].Placed.After(activity.Orders[j].Placed)
+	})
+
+	return activity
+}
+
+// GetActivityData serves fills and open orders of connected accounts for
+// the activity page
+func GetActivityData(ctx context.Context, dataChannel chan Activity) error {
+	fetched := time.Time{}
+
+	return utils.LoopTick(ctx, time.Duration(1)*time.Second, func(errChan chan error) {
+		var finalErr error = nil
+
+		defer func() {
+			if finalErr != nil {
+				errChan <- finalErr
+			}
+		}()
+
+		if time.Since(fetched) < utils.PollInterval(activityRefresh) {
+			return
+		}
+		fetched = time.Now()
+
+		// Failed accounts are shown on the page rather than closing it
+		activity := GetActivity(ctx)
+
+		// Send Data
+		select {
+		case <-ctx.Done():
+			finalErr = ctx.Err()
+			return
+		case dataChannel <- activity:
+		}
+	})
+}
@@ -0,0 +1,244 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exchange
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// binanceAccountURL is the endpoint of Binance spot account balances
+const binanceAccountURL = "https://api.binance.com/api/v3/account"
+
+// binance reads balances of Binance spot accounts
+type binance struct{}
+
+// Name returns the name of the exchange
+func (binance) Name() string {
+	return "binance"
+}
+
+// binanceSigned returns a request to Binance signed with an HMAC-SHA256 of
+// its query string
+func binanceSigned(ctx context.Context, method, endpoint string, params url.Values, creds Credentials) (*http.Request, error) {
+	params.Set("recvWindow", "10000")
+	params.Set("timestamp", strconv.FormatInt(time.Now().UnixNano()/int64(time.Millisecond), 10))
+	query := params.Encode()
+
+	mac := hmac.New(sha256.New, []byte(creds.Secret))
+	mac.Write([]byte(query))
+	query += "&signature=" + hex.EncodeToString(mac.Sum(nil))
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint+"?"+query, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-MBX-APIKEY", creds.Key)
+	return req, nil
+}
+
+// Balances returns free and locked amounts of each asset held
+func (binance) Balances(ctx context.Context, creds Credentials) (map[string]float64, error) {
+	params := url.Values{}
+	params.Set("omitZeroBalances", "true")
+
+	req, err := binanceSigned(ctx, http.MethodGet, binanceAccountURL, params, creds)
+	if err != nil {
+		return nil, err
+	}
+
+	account := struct {
+		Balances []struct {
+			Asset  string `json:"asset"`
+			Free   string `json:"free"`
+			Locked string `json:"locked"`
+		} `json:"balances"`
+	}{}
+	if err := doJSON(req, &account); err != nil {
+		return nil, err
+	}
+
+	balances := map[string]float64{}
+	for _, b := range account.Balances {
+		free, _ := strconv.ParseFloat(b.Free, 64)
+		locked, _ := strconv.ParseFloat(b.Locked, 64)
+		if amount := free + locked; amount > 0 {
+			balances[strings.ToUpper(b.Asset)] += amount
+		}
+	}
+
+	return balances, nil
+}
+
+// Endpoints of Binance fills and open orders
+const (
+	binanceTradesURL     = "https://api.binance.com/api/v3/myTrades"
+	binanceOpenOrdersURL = "https://api.binance.com/api/v3/openOrders"
+)
+
+// binanceFillQuote is the asset fills are fetched in pairs of, as Binance
+// only serves fills by pair
+const binanceFillQuote = "USDT"
+
+// binanceFillPairs is the most pairs fills are fetched of, limiting the
+// weight of requests
+const binanceFillPairs = 20
+
+// binanceFillLimit is the most fills fetched of each pair
+const binanceFillLimit = 50
+
+// binanceTime returns the time of Binance milliseconds since the epoch
+func binanceTime(ms int64) time.Time {
+	return time.Unix(0, ms*int64(time.Millisecond))
+}
+
+// Fills returns recent fills of pairs of assets held and those with open
+// orders, against USDT. Pairs Binance doesn't list are skipped.
+func (b binance) Fills(ctx context.Context, creds Credentials) ([]Fill, error) {
+	balances, err := b.Balances(ctx, creds)
+	if err != nil {
+		return nil, err
+	}
+	orders, err := b.OpenOrders(ctx, creds)
+	if err != nil {
+		return nil, err
+	}
+
+	pairs := map[string]string{}
+	for asset := range balances {
+		if asset != binanceFillQuote {
+			pairs[asset+binanceFillQuote] = asset
+		}
+	}
+	for _, order := range orders {
+		if strings.HasSuffix(order.Pair, binanceFillQuote) {
+			pairs[order.Pair] = strings.TrimSuffix(order.Pair, binanceFillQuote)
+		}
+	}
+	symbols := []string{}
+	for pair := range pairs {
+		symbols = append(symbols, pair)
+	}
+	sort.Strings(symbols)
+	if len(symbols) > binanceFillPairs {
+		symbols = symbols[:binanceFillPairs]
+	}
+
+	fills := []Fill{}
+	failed := 0
+	var firstErr error
+	for _, symbol := range symbols {
+		params := url.Values{}
+		params.Set("symbol", symbol)
+		params.Set("limit", strconv.Itoa(binanceFillLimit))
+
+		req, err := binanceSigned(ctx, http.MethodGet, binanceTradesURL, params, creds)
+		if err != nil {
+			return nil, err
+		}
+
+		trades := []struct {
+			ID              int64  `json:"id"`
+			Price           string `json:"price"`
+			Qty             string `json:"qty"`
+			QuoteQty        string `json:"quoteQty"`
+			Commission      string `json:"commission"`
+			CommissionAsset string `json:"commissionAsset"`
+			Time            int64  `json:"time"`
+			IsBuyer         bool   `json:"isBuyer"`
+		}{}
+		if err := binanceJSON(req, &trades); err != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+
+		for _, t := range trades {
+			fill := Fill{
+				Exchange: "binance",
+				ID:       strconv.FormatInt(t.ID, 10),
+				Time:     binanceTime(t.Time),
+				Base:     pairs[symbol],
+				Quote:    binanceFillQuote,
+				Side:     Sell,
+				FeeAsset: strings.ToUpper(t.CommissionAsset),
+			}
+			if t.IsBuyer {
+				fill.Side = Buy
+			}
+			fill.Quantity, _ = strconv.ParseFloat(t.Qty, 64)
+			fill.Price, _ = strconv.ParseFloat(t.Price, 64)
+			fill.Amount, _ = strconv.ParseFloat(t.QuoteQty, 64)
+			fill.Fee, _ = strconv.ParseFloat(t.Commission, 64)
+			fills = append(fills, fill)
+		}
+	}
+
+	// Pairs of assets not traded against USDT fail, so only fail if all do
+	if failed > 0 && failed == len(symbols) {
+		return nil, firstErr
+	}
+	return fills, nil
+}
+
+// OpenOrders returns open spot orders of every pair
+func (binance) OpenOrders(ctx context.Context, creds Credentials) ([]Order, error) {
+	req, err := binanceSigned(ctx, http.MethodGet, binanceOpenOrdersURL, url.Values{}, creds)
+	if err != nil {
+		return nil, err
+	}
+
+	open := []struct {
+		binanceOrder
+		Symbol  string `json:"symbol"`
+		Price   string `json:"price"`
+		OrigQty string `json:"origQty"`
+		Type    string `json:"type"`
+		Side    string `json:"side"`
+		Time    int64  `json:"time"`
+	}{}
+	if err := binanceJSON(req, &open); err != nil {
+		return nil, err
+	}
+
+	orders := []Order{}
+	for _, o := range open {
+		order := Order{
+			Exchange: "binance",
+			Pair:     o.Symbol,
+			Side:     strings.ToLower(o.Side),
+			Type:     strings.ToLower(strings.ReplaceAll(o.Type, "_", " ")),
+			Placed:   binanceTime(o.Time),
+			Updated:  time.Now(),
+		}
+		order.Quantity, _ = strconv.ParseFloat(o.OrigQty, 64)
+		order.Price, _ = strconv.ParseFloat(o.Price, 64)
+		orders = append(orders, o.binanceOrder.apply(order))
+	}
+
+	return orders, nil
+}
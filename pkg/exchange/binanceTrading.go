@@ -0,0 +1,166 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+)
+
+// Endpoints of Binance spot orders. Orders sent to the test endpoint are
+// validated without being placed.
+const (
+	binanceOrderURL     = "https://api.binance.com/api/v3/order"
+	binanceTestOrderURL = "https://api.binance.com/api/v3/order/test"
+)
+
+// binanceStatuses maps statuses of Binance orders to those of cryptgo
+var binanceStatuses = map[string]string{
+	"NEW":              StatusNew,
+	"PARTIALLY_FILLED": StatusPartial,
+	"FILLED":           StatusFilled,
+	"CANCELED":         StatusCancelled,
+	"PENDING_CANCEL":   StatusNew,
+	"REJECTED":         StatusRejected,
+	"EXPIRED":          StatusExpired,
+	"EXPIRED_IN_MATCH": StatusExpired,
+}
+
+// binanceOrder is an order as served by Binance
+type binanceOrder struct {
+	OrderID     int64  `json:"orderId"`
+	Status      string `json:"status"`
+	ExecutedQty string `json:"executedQty"`
+}
+
+// apply sets the ID, status and filled quantity of an order from that
+// served by Binance
+func (b binanceOrder) apply(order Order) Order {
+	order.ID = strconv.FormatInt(b.OrderID, 10)
+	order.Filled, _ = strconv.ParseFloat(b.ExecutedQty, 64)
+	order.Status = binanceStatuses[b.Status]
+	if order.Status == "" {
+		order.Status = strings.ToLower(b.Status)
+	}
+	return order
+}
+
+// binanceJSON sends a signed request to Binance and decodes the JSON
+// response into v. Rejected requests return the reason given by Binance,
+// Eg: an insufficient balance.
+func binanceJSON(req *http.Request, v interface{}) error {
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		reason := struct {
+			Code int    `json:"code"`
+			Msg  string `json:"msg"`
+		}{}
+		if json.NewDecoder(res.Body).Decode(&reason) == nil && reason.Msg != "" {
+			return fmt.Errorf("binance rejected the request: %s (code %d)", reason.Msg, reason.Code)
+		}
+		return &api.StatusError{URL: api.RedactURL(req.URL), Code: res.StatusCode}
+	}
+
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+// PlaceOrder places a spot order. Market orders are filled at the best
+// prices available, and limit orders stay open till cancelled.
+func (binance) PlaceOrder(ctx context.Context, creds Credentials, order Order, dryRun bool) (Order, error) {
+	params := url.Values{}
+	params.Set("symbol", order.Pair)
+	params.Set("side", strings.ToUpper(order.Side))
+	params.Set("type", strings.ToUpper(order.Type))
+	params.Set("quantity", strconv.FormatFloat(order.Quantity, 'f', -1, 64))
+	if order.Type == Limit {
+		params.Set("timeInForce", "GTC")
+		params.Set("price", strconv.FormatFloat(order.Price, 'f', -1, 64))
+	}
+	params.Set("newOrderRespType", "RESULT")
+
+	endpoint := binanceOrderURL
+	if dryRun {
+		endpoint = binanceTestOrderURL
+	}
+	req, err := binanceSigned(ctx, http.MethodPost, endpoint, params, creds)
+	if err != nil {
+		return order, err
+	}
+
+	placed := binanceOrder{}
+	if err := binanceJSON(req, &placed); err != nil {
+		return order, err
+	}
+
+	// Test orders are served without an ID, so one is made up to record
+	// them by
+	if dryRun {
+		order.ID = fmt.Sprintf("dry-%d", time.Now().UnixNano()/int64(time.Millisecond))
+		order.Status = StatusDryRun
+		return order, nil
+	}
+	return placed.apply(order), nil
+}
+
+// OrderStatus returns an order with its status as served by Binance
+func (binance) OrderStatus(ctx context.Context, creds Credentials, order Order) (Order, error) {
+	params := url.Values{}
+	params.Set("symbol", order.Pair)
+	params.Set("orderId", order.ID)
+
+	req, err := binanceSigned(ctx, http.MethodGet, binanceOrderURL, params, creds)
+	if err != nil {
+		return order, err
+	}
+
+	status := binanceOrder{}
+	if err := binanceJSON(req, &status); err != nil {
+		return order, err
+	}
+	return status.apply(order), nil
+}
+
+// CancelOrder cancels an open order
+func (binance) CancelOrder(ctx context.Context, creds Credentials, order Order) (Order, error) {
+	params := url.Values{}
+	params.Set("symbol", order.Pair)
+	params.Set("orderId", order.ID)
+
+	req, err := binanceSigned(ctx, http.MethodDelete, binanceOrderURL, params, creds)
+	if err != nil {
+		return order, err
+	}
+
+	cancelled := binanceOrder{}
+	if err := binanceJSON(req, &cancelled); err != nil {
+		return order, err
+	}
+	return cancelled.apply(order), nil
+}
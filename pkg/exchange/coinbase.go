@@ -0,0 +1,90 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exchange
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// coinbaseAPI is the base URL of the Coinbase API
+const coinbaseAPI = "https://api.coinbase.com"
+
+// coinbaseVersion is the version of the API responses are requested in
+const coinbaseVersion = "2021-06-01"
+
+// coinbase reads balances of Coinbase accounts
+type coinbase struct{}
+
+// Name returns the name of the exchange
+func (coinbase) Name() string {
+	return "coinbase"
+}
+
+// Balances returns the balance of each wallet held, following pages of
+// wallets. Requests are signed with an HMAC-SHA256 of their timestamp,
+// method and path.
+func (coinbase) Balances(ctx context.Context, creds Credentials) (map[string]float64, error) {
+	balances := map[string]float64{}
+
+	path := "/v2/accounts?limit=100"
+	for path != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		mac := hmac.New(sha256.New, []byte(creds.Secret))
+		mac.Write([]byte(timestamp + http.MethodGet + path))
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, coinbaseAPI+path, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("CB-ACCESS-KEY", creds.Key)
+		req.Header.Set("CB-ACCESS-SIGN", hex.EncodeToString(mac.Sum(nil)))
+		req.Header.Set("CB-ACCESS-TIMESTAMP", timestamp)
+		req.Header.Set("CB-VERSION", coinbaseVersion)
+
+		page := struct {
+			Pagination struct {
+				NextURI string `json:"next_uri"`
+			} `json:"pagination"`
+			Data []struct {
+				Balance struct {
+					Amount   string `json:"amount"`
+					Currency string `json:"currency"`
+				} `json:"balance"`
+			} `json:"data"`
+		}{}
+		if err := doJSON(req, &page); err != nil {
+			return nil, err
+		}
+
+		for _, wallet := range page.Data {
+			amount, _ := strconv.ParseFloat(wallet.Balance.Amount, 64)
+			if amount > 0 {
+				balances[strings.ToUpper(wallet.Balance.Currency)] += amount
+			}
+		}
+		path = page.Pagination.NextURI
+	}
+
+	return balances, nil
+}
@@ -0,0 +1,240 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exchange
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
+)
+
+// Credentials hold a read only API key of an exchange account
+type Credentials struct {
+	Key    string `json:"key"`
+	Secret string `json:"secret"`
+}
+
+// accountsPath returns the path of the file connected accounts are kept in,
+// exchanges.json in the cryptgo config directory. The file earlier versions
+// kept in the home directory, ~/.cryptgo-exchanges.json, is moved there.
+func accountsPath() (string, error) {
+	configDir, err := utils.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(configDir, "cryptgo", "exchanges.json")
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return path, nil
+	}
+	old := filepath.Join(homeDir, ".cryptgo-exchanges.json")
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		return path, nil
+	}
+	if _, err := os.Stat(old); err != nil {
+		return path, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", err
+	}
+	if err := os.Rename(old, path); err != nil {
+		return "", fmt.Errorf("failed to move %s to %s: %v", old, path, err)
+	}
+	return path, nil
+}
+
+// readAccounts returns sealed credentials of connected accounts, keyed by
+// exchange. A missing accounts file holds no accounts, other failures to
+// read it are returned, so accounts aren't written over.
+func readAccounts() (map[string]string, error) {
+	accounts := map[string]string{}
+
+	path, err := accountsPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return accounts, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &accounts); err != nil {
+		return nil, fmt.Errorf("invalid accounts file %s: %v", path, err)
+	}
+
+	return accounts, nil
+}
+
+// writeAccounts writes sealed credentials of connected accounts, readable
+// only by the user
+func writeAccounts(accounts map[string]string) error {
+	path, err := accountsPath()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(accounts)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return err
+	}
+
+	// WriteFile keeps permissions of existing files
+	return os.Chmod(path, 0600)
+}
+
+// newCipher returns an AES-GCM cipher with the encryption key of cryptgo
+func newCipher() (cipher.AEAD, error) {
+	key, err := utils.EncryptionKey()
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts credentials of an exchange. The name of the exchange is
+// authenticated with them, so they can't be moved to another exchange.
+func seal(name string, creds Credentials) (string, error) {
+	gcm, err := newCipher()
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(creds)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, data, []byte(name))
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// unseal decrypts credentials of an exchange sealed with seal
+func unseal(name, sealed string) (Credentials, error) {
+	creds := Credentials{}
+
+	gcm, err := newCipher()
+	if err != nil {
+		return creds, err
+	}
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil || len(data) < gcm.NonceSize() {
+		return creds, fmt.Errorf("invalid credentials of %s, connect the account again", name)
+	}
+
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	data, err = gcm.Open(nil, nonce, ciphertext, []byte(name))
+	if err != nil {
+		return creds, fmt.Errorf("can't decrypt credentials of %s, connect the account again: %v", name, err)
+	}
+
+	err = json.Unmarshal(data, &creds)
+	return creds, err
+}
+
+// credentials returns the credentials of the account connected on an
+// exchange
+func credentials(name string) (Credentials, error) {
+	accounts, err := readAccounts()
+	if err != nil {
+		return Credentials{}, err
+	}
+	sealed, ok := accounts[name]
+	if !ok {
+		return Credentials{}, fmt.Errorf("no %s account is connected", name)
+	}
+	return unseal(name, sealed)
+}
+
+// Connect keeps credentials of an account, replacing those of an account
+// connected on the exchange before
+func Connect(name string, creds Credentials) error {
+	if _, err := get(name); err != nil {
+		return err
+	}
+	if creds.Key == "" || creds.Secret == "" {
+		return fmt.Errorf("both the API key and secret of %s are needed", name)
+	}
+
+	sealed, err := seal(name, creds)
+	if err != nil {
+		return err
+	}
+
+	accounts, err := readAccounts()
+	if err != nil {
+		return err
+	}
+	accounts[name] = sealed
+	return writeAccounts(accounts)
+}
+
+// Disconnect removes credentials of the account connected on an exchange
+func Disconnect(name string) error {
+	accounts, err := readAccounts()
+	if err != nil {
+		return err
+	}
+	if _, ok := accounts[name]; !ok {
+		return fmt.Errorf("no %s account is connected", name)
+	}
+	delete(accounts, name)
+	return writeAccounts(accounts)
+}
+
+// Connected returns names of exchanges with an account connected for
+// balances, sorted
+func Connected() ([]string, error) {
+	accounts, err := readAccounts()
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for name := range accounts {
+		if !strings.HasPrefix(name, tradePrefix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
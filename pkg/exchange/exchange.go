@@ -0,0 +1,185 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package exchange reads balances of exchange accounts connected with read
+// only API keys, and places orders on those connected for trading once it
+// is enabled. Keys are kept encrypted in the home directory, and every
+// request is signed as each exchange requires.
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+	"github.com/Gituser143/cryptgo/pkg/utils"
+)
+
+// balancesRefresh is how often balances of connected accounts are fetched
+const balancesRefresh = time.Duration(1) * time.Minute
+
+// client is used for requests to exchanges. Requests are rate limited like
+// those of data sources, but never recorded or cached.
+var client = &http.Client{
+	Timeout:   time.Duration(10) * time.Second,
+	Transport: api.RateLimiter(),
+}
+
+// Exchange reads balances of an account
+type Exchange interface {
+	// Name returns the name of the exchange, Eg: binance
+	Name() string
+
+	// Balances returns amounts held of each asset, keyed by upper case
+	// symbol, Eg: BTC. Assets which aren't held are left out.
+	Balances(ctx context.Context, creds Credentials) (map[string]float64, error)
+}
+
+// exchanges holds the exchanges accounts can be connected on, by name
+var exchanges = map[string]Exchange{
+	"binance":  binance{},
+	"coinbase": coinbase{},
+	"kraken":   kraken{},
+}
+
+// Names returns names of exchanges accounts can be connected on
+func Names() []string {
+	names := []string{}
+	for name := range exchanges {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// get returns the exchange of a name
+func get(name string) (Exchange, error) {
+	ex, ok := exchanges[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown exchange %q, expected one of: %s", name, strings.Join(Names(), ", "))
+	}
+	return ex, nil
+}
+
+// Holdings holds balances of connected accounts
+type Holdings struct {
+	Balances map[string]map[string]float64 // Amounts by exchange and upper case symbol
+	Errors   map[string]error              // Errors of exchanges whose balances couldn't be fetched
+}
+
+// Total returns the amount of a symbol held across exchanges
+func (h Holdings) Total(symbol string) float64 {
+	total := 0.0
+	for _, balances := range h.Balances {
+		total += balances[strings.ToUpper(symbol)]
+	}
+	return total
+}
+
+// GetBalances fetches balances of every connected account. Accounts which
+// fail are reported in Errors, rather than failing the others.
+func GetBalances(ctx context.Context) Holdings {
+	holdings := Holdings{
+		Balances: map[string]map[string]float64{},
+		Errors:   map[string]error{},
+	}
+
+	names, err := Connected()
+	if err != nil {
+		holdings.Errors["accounts"] = err
+	}
+	for _, name := range names {
+		ex, err := get(name)
+		if err != nil {
+			holdings.Errors[name] = err
+			continue
+		}
+		creds, err := credentials(name)
+		if err != nil {
+			holdings.Errors[name] = err
+			continue
+		}
+		balances, err := ex.Balances(ctx, creds)
+		if err != nil {
+			holdings.Errors[name] = err
+			continue
+		}
+		holdings.Balances[name] = balances
+	}
+
+	return holdings
+}
+
+// GetHoldings serves balances of connected accounts for the portfolio page,
+// if any are connected
+func GetHoldings(ctx context.Context, dataChannel chan Holdings, sendData *bool) error {
+	names, err := Connected()
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	fetched := time.Time{}
+
+	return utils.LoopTick(ctx, time.Duration(1)*time.Second, func(errChan chan error) {
+		var finalErr error = nil
+
+		defer func() {
+			if finalErr != nil {
+				errChan <- finalErr
+			}
+		}()
+
+		if !*sendData || time.Since(fetched) < utils.PollInterval(balancesRefresh) {
+			return
+		}
+		fetched = time.Now()
+
+		// Balances are shown along with holdings entered by hand, so failed
+		// accounts are shown on the page rather than closing it
+		holdings := GetBalances(ctx)
+
+		// Send Data
+		select {
+		case <-ctx.Done():
+			finalErr = ctx.Err()
+			return
+		case dataChannel <- holdings:
+		}
+	})
+}
+
+// doJSON sends a signed request and decodes the JSON response into v. Errors
+// name the URL without its signature.
+func doJSON(req *http.Request, v interface{}) error {
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return &api.StatusError{URL: api.RedactURL(req.URL), Code: res.StatusCode}
+	}
+
+	return json.NewDecoder(res.Body).Decode(v)
+}
@@ -0,0 +1,248 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exchange
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// krakenAPI is the base URL of the Kraken API
+const krakenAPI = "https://api.kraken.com"
+
+// krakenBalancePath is the path of the endpoint of account balances
+const krakenBalancePath = "/0/private/Balance"
+
+// krakenAssets maps legacy Kraken asset names to their symbols, Eg: XXBT
+var krakenAssets = map[string]string{
+	"XXBT": "BTC",
+	"XBT":  "BTC",
+	"XETH": "ETH",
+	"ETH2": "ETH",
+	"XLTC": "LTC",
+	"XXRP": "XRP",
+	"XXLM": "XLM",
+	"XXMR": "XMR",
+	"XZEC": "ZEC",
+	"XETC": "ETC",
+	"XMLN": "MLN",
+	"XREP": "REP",
+	"XXDG": "DOGE",
+	"XDG":  "DOGE",
+	"ZUSD": "USD",
+	"ZEUR": "EUR",
+	"ZGBP": "GBP",
+	"ZCAD": "CAD",
+	"ZJPY": "JPY",
+}
+
+// kraken reads balances of Kraken accounts
+type kraken struct{}
+
+// Name returns the name of the exchange
+func (kraken) Name() string {
+	return "kraken"
+}
+
+// krakenSymbol returns the symbol of a Kraken asset. Staked and earning
+// balances are suffixed, Eg: DOT.S, and counted with the asset.
+func krakenSymbol(asset string) string {
+	asset = strings.ToUpper(strings.SplitN(asset, ".", 2)[0])
+	if symbol, ok := krakenAssets[asset]; ok {
+		return symbol
+	}
+	return asset
+}
+
+// krakenPrivate sends a request to a private endpoint and decodes its result
+// into v. Requests are signed with an HMAC-SHA512 of their path and a hash
+// of their nonce and body, keyed by the base64 decoded secret.
+func krakenPrivate(ctx context.Context, creds Credentials, path string, params url.Values, v interface{}) error {
+	secret, err := base64.StdEncoding.DecodeString(creds.Secret)
+	if err != nil {
+		return fmt.Errorf("invalid kraken secret, expected the base64 private key: %v", err)
+	}
+
+	// Nonces must increase with every request of a key
+	nonce := strconv.FormatInt(time.Now().UnixNano()/int64(time.Microsecond), 10)
+	params.Set("nonce", nonce)
+	body := params.Encode()
+
+	sum := sha256.Sum256([]byte(nonce + body))
+	mac := hmac.New(sha512.New, secret)
+	mac.Write(append([]byte(path), sum[:]...))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, krakenAPI+path, strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("API-Key", creds.Key)
+	req.Header.Set("API-Sign", base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+
+	res := struct {
+		Error  []string        `json:"error"`
+		Result json.RawMessage `json:"result"`
+	}{}
+	if err := doJSON(req, &res); err != nil {
+		return err
+	}
+	if len(res.Error) > 0 {
+		return fmt.Errorf("kraken request failed: %s", strings.Join(res.Error, ", "))
+	}
+
+	return json.Unmarshal(res.Result, v)
+}
+
+// Balances returns the amount of each asset held
+func (kraken) Balances(ctx context.Context, creds Credentials) (map[string]float64, error) {
+	result := map[string]string{}
+	if err := krakenPrivate(ctx, creds, krakenBalancePath, url.Values{}, &result); err != nil {
+		return nil, err
+	}
+
+	balances := map[string]float64{}
+	for asset, val := range result {
+		amount, _ := strconv.ParseFloat(val, 64)
+		if amount > 0 {
+			balances[krakenSymbol(asset)] += amount
+		}
+	}
+
+	return balances, nil
+}
+
+// Paths of the endpoints of fills and open orders
+const (
+	krakenTradesPath     = "/0/private/TradesHistory"
+	krakenOpenOrdersPath = "/0/private/OpenOrders"
+)
+
+// krakenQuotes are assets pairs are quoted in, Eg: ZUSD of XXBTZUSD. Longer
+// names come first, so USDT isn't taken for USD.
+var krakenQuotes = []string{"ZUSD", "ZEUR", "ZGBP", "ZCAD", "ZJPY", "USDT", "USDC", "XXBT", "XETH", "USD", "EUR", "GBP", "CAD", "JPY", "XBT", "ETH", "DAI"}
+
+// krakenPair returns symbols of the base and quote assets of a Kraken pair,
+// Eg: BTC and USD of XXBTZUSD
+func krakenPair(pair string) (string, string) {
+	pair = strings.ToUpper(pair)
+	for _, quote := range krakenQuotes {
+		if strings.HasSuffix(pair, quote) && len(pair) > len(quote) {
+			return krakenSymbol(strings.TrimSuffix(pair, quote)), krakenSymbol(quote)
+		}
+	}
+	return krakenSymbol(pair), ""
+}
+
+// krakenTime returns the time of Kraken seconds since the epoch
+func krakenTime(sec float64) time.Time {
+	return time.Unix(0, int64(sec*float64(time.Second)))
+}
+
+// Fills returns the latest 50 fills. Kraken charges fees in the quote
+// asset.
+func (kraken) Fills(ctx context.Context, creds Credentials) ([]Fill, error) {
+	result := struct {
+		Trades map[string]struct {
+			Pair  string  `json:"pair"`
+			Time  float64 `json:"time"`
+			Type  string  `json:"type"`
+			Price string  `json:"price"`
+			Cost  string  `json:"cost"`
+			Fee   string  `json:"fee"`
+			Vol   string  `json:"vol"`
+		} `json:"trades"`
+	}{}
+	if err := krakenPrivate(ctx, creds, krakenTradesPath, url.Values{}, &result); err != nil {
+		return nil, err
+	}
+
+	fills := []Fill{}
+	for id, t := range result.Trades {
+		base, quote := krakenPair(t.Pair)
+		fill := Fill{
+			Exchange: "kraken",
+			ID:       id,
+			Time:     krakenTime(t.Time),
+			Base:     base,
+			Quote:    quote,
+			Side:     strings.ToLower(t.Type),
+			FeeAsset: quote,
+		}
+		fill.Quantity, _ = strconv.ParseFloat(t.Vol, 64)
+		fill.Price, _ = strconv.ParseFloat(t.Price, 64)
+		fill.Amount, _ = strconv.ParseFloat(t.Cost, 64)
+		fill.Fee, _ = strconv.ParseFloat(t.Fee, 64)
+		fills = append(fills, fill)
+	}
+
+	return fills, nil
+}
+
+// OpenOrders returns open orders of every pair
+func (kraken) OpenOrders(ctx context.Context, creds Credentials) ([]Order, error) {
+	result := struct {
+		Open map[string]struct {
+			Status  string  `json:"status"`
+			OpenTm  float64 `json:"opentm"`
+			Vol     string  `json:"vol"`
+			VolExec string  `json:"vol_exec"`
+			Descr   struct {
+				Pair      string `json:"pair"`
+				Type      string `json:"type"`
+				OrderType string `json:"ordertype"`
+				Price     string `json:"price"`
+			} `json:"descr"`
+		} `json:"open"`
+	}{}
+	if err := krakenPrivate(ctx, creds, krakenOpenOrdersPath, url.Values{}, &result); err != nil {
+		return nil, err
+	}
+
+	orders := []Order{}
+	for id, o := range result.Open {
+		order := Order{
+			Exchange: "kraken",
+			ID:       id,
+			Pair:     strings.ToUpper(o.Descr.Pair),
+			Side:     strings.ToLower(o.Descr.Type),
+			Type:     strings.ToLower(o.Descr.OrderType),
+			Status:   StatusNew,
+			Placed:   krakenTime(o.OpenTm),
+			Updated:  time.Now(),
+		}
+		order.Quantity, _ = strconv.ParseFloat(o.Vol, 64)
+		order.Filled, _ = strconv.ParseFloat(o.VolExec, 64)
+		order.Price, _ = strconv.ParseFloat(o.Descr.Price, 64)
+		if order.Filled > 0 {
+			order.Status = StatusPartial
+		}
+		orders = append(orders, order)
+	}
+
+	return orders, nil
+}
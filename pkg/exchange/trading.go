@@ -0,0 +1,404 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
+)
+
+// Trading is off unless opted into with trading.enabled, and dry runs can
+// be forced for every order with trading.dryrun
+var (
+	TradingEnabled = false
+	DryRun         = false
+)
+
+// TradingSettings returns TradingEnabled and DryRun, read under the
+// settings lock
+func TradingSettings() (enabled, dryRun bool) {
+	utils.RLockSettings()
+	defer utils.RUnlockSettings()
+	return TradingEnabled, DryRun
+}
+
+// ordersRefresh is how often statuses of open orders are fetched
+const ordersRefresh = time.Duration(10) * time.Second
+
+// tradePrefix names trading credentials in the accounts file, so they are
+// kept apart from read only keys used for balances
+const tradePrefix = "trade:"
+
+// Order sides and types
+const (
+	Buy    = "buy"
+	Sell   = "sell"
+	Limit  = "limit"
+	Market = "market"
+)
+
+// Order statuses. Orders are open till they are filled, cancelled,
+// rejected or expired.
+const (
+	StatusNew       = "new"
+	StatusPartial   = "partially filled"
+	StatusFilled    = "filled"
+	StatusCancelled = "cancelled"
+	StatusRejected  = "rejected"
+	StatusExpired   = "expired"
+	StatusDryRun    = "dry run"
+)
+
+// Order is an order placed on an exchange
+type Order struct {
+	Exchange string    `json:"exchange"`
+	ID       string    `json:"id"`
+	Pair     string    `json:"pair"` // Upper case, Eg: BTCUSDT
+	Side     string    `json:"side"`
+	Type     string    `json:"type"`
+	Quantity float64   `json:"quantity"`
+	Price    float64   `json:"price,omitempty"` // Limit orders only
+	Filled   float64   `json:"filled"`
+	Status   string    `json:"status"`
+	Placed   time.Time `json:"placed"`
+	Updated  time.Time `json:"updated"`
+}
+
+// Open returns if the order may still be filled
+func (o Order) Open() bool {
+	return o.Status == StatusNew || o.Status == StatusPartial
+}
+
+// String describes the order, Eg: buy 0.01 BTCUSDT at 30000 on binance
+func (o Order) String() string {
+	price := "market price"
+	if o.Type == Limit {
+		price = strconv.FormatFloat(o.Price, 'f', -1, 64)
+	}
+	return fmt.Sprintf("%s %s %s at %s on %s", o.Side, strconv.FormatFloat(o.Quantity, 'f', -1, 64), o.Pair, price, o.Exchange)
+}
+
+// Trader places orders on an exchange
+type Trader interface {
+	// PlaceOrder places an order and returns it with its ID and status.
+	// Dry runs are validated by the exchange without being placed.
+	PlaceOrder(ctx context.Context, creds Credentials, order Order, dryRun bool) (Order, error)
+
+	// OrderStatus returns an order with its filled quantity and status
+	OrderStatus(ctx context.Context, creds Credentials, order Order) (Order, error)
+
+	// CancelOrder cancels an open order and returns it with its status
+	CancelOrder(ctx context.Context, creds Credentials, order Order) (Order, error)
+}
+
+// traders holds exchanges orders can be placed on, by name
+var traders = map[string]Trader{
+	"binance": binance{},
+}
+
+// TradingNames returns names of exchanges orders can be placed on
+func TradingNames() []string {
+	names := []string{}
+	for name := range traders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// trader returns the trader of an exchange, if trading is enabled
+func trader(name string) (Trader, error) {
+	if enabled, _ := TradingSettings(); !enabled {
+		return nil, fmt.Errorf("trading is disabled, set trading.enabled to true in the config file to enable it")
+	}
+	t, ok := traders[name]
+	if !ok {
+		return nil, fmt.Errorf("can't trade on %q, expected one of: %s", name, strings.Join(TradingNames(), ", "))
+	}
+	return t, nil
+}
+
+// ConnectTrading keeps credentials of an account orders are placed with.
+// They are kept apart from those connected for balances, so read only keys
+// can still be used for those.
+func ConnectTrading(name string, creds Credentials) error {
+	if _, err := trader(name); err != nil {
+		return err
+	}
+	if creds.Key == "" || creds.Secret == "" {
+		return fmt.Errorf("both the API key and secret of %s are needed", name)
+	}
+
+	sealed, err := seal(tradePrefix+name, creds)
+	if err != nil {
+		return err
+	}
+
+	accounts, err := readAccounts()
+	if err != nil {
+		return err
+	}
+	accounts[tradePrefix+name] = sealed
+	return writeAccounts(accounts)
+}
+
+// DisconnectTrading removes credentials of the account orders are placed
+// with on an exchange
+func DisconnectTrading(name string) error {
+	accounts, err := readAccounts()
+	if err != nil {
+		return err
+	}
+	if _, ok := accounts[tradePrefix+name]; !ok {
+		return fmt.Errorf("no %s account is connected for trading", name)
+	}
+	delete(accounts, tradePrefix+name)
+	return writeAccounts(accounts)
+}
+
+// ValidateOrder checks an order can be placed, filling in its type from
+// its price
+func ValidateOrder(order Order) (Order, error) {
+	order.Exchange = strings.ToLower(order.Exchange)
+	order.Pair = strings.ToUpper(order.Pair)
+	order.Side = strings.ToLower(order.Side)
+
+	if order.Side != Buy && order.Side != Sell {
+		return order, fmt.Errorf("invalid side %q, expected buy or sell", order.Side)
+	}
+	if order.Pair == "" {
+		return order, fmt.Errorf("a pair is needed, Eg: BTCUSDT")
+	}
+	if order.Quantity <= 0 {
+		return order, fmt.Errorf("invalid quantity %v, expected a positive amount", order.Quantity)
+	}
+	if order.Price < 0 {
+		return order, fmt.Errorf("invalid price %v, expected a positive price", order.Price)
+	}
+
+	order.Type = Market
+	if order.Price > 0 {
+		order.Type = Limit
+	}
+	return order, nil
+}
+
+// PlaceOrder places an order with the trading account of its exchange and
+// records it. Orders are only validated by the exchange if dryRun or
+// trading.dryrun is set.
+func PlaceOrder(ctx context.Context, order Order, dryRun bool) (Order, error) {
+	order, err := ValidateOrder(order)
+	if err != nil {
+		return order, err
+	}
+	t, err := trader(order.Exchange)
+	if err != nil {
+		return order, err
+	}
+	creds, err := credentials(tradePrefix + order.Exchange)
+	if err != nil {
+		return order, fmt.Errorf("no %s account is connected for trading", order.Exchange)
+	}
+
+	_, forceDryRun := TradingSettings()
+	order, err = t.PlaceOrder(ctx, creds, order, dryRun || forceDryRun)
+	if err != nil {
+		return order, err
+	}
+	order.Placed = time.Now()
+	order.Updated = order.Placed
+
+	return order, saveOrder(order)
+}
+
+// CancelOrder cancels an open order recorded by its ID
+func CancelOrder(ctx context.Context, id string) (Order, error) {
+	var order Order
+	found := false
+	for _, o := range Orders() {
+		if o.ID == id {
+			order, found = o, true
+		}
+	}
+	if !found {
+		return order, fmt.Errorf("no order with ID %s", id)
+	}
+	if !order.Open() {
+		return order, fmt.Errorf("order %s is %s, only open orders can be cancelled", id, order.Status)
+	}
+
+	t, err := trader(order.Exchange)
+	if err != nil {
+		return order, err
+	}
+	creds, err := credentials(tradePrefix + order.Exchange)
+	if err != nil {
+		return order, fmt.Errorf("no %s account is connected for trading", order.Exchange)
+	}
+
+	order, err = t.CancelOrder(ctx, creds, order)
+	if err != nil {
+		return order, err
+	}
+	order.Updated = time.Now()
+
+	return order, saveOrder(order)
+}
+
+// RefreshOrders fetches statuses of open orders and records them. Orders
+// whose status can't be fetched are kept as they were, and the first error
+// is returned.
+func RefreshOrders(ctx context.Context) ([]Order, error) {
+	var firstErr error
+	for _, order := range Orders() {
+		if !order.Open() {
+			continue
+		}
+
+		t, err := trader(order.Exchange)
+		if err == nil {
+			var creds Credentials
+			creds, err = credentials(tradePrefix + order.Exchange)
+			if err == nil {
+				order, err = t.OrderStatus(ctx, creds, order)
+			}
+		}
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("can't fetch status of order %s: %v", order.ID, err)
+			}
+			continue
+		}
+
+		order.Updated = time.Now()
+		if err := saveOrder(order); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return Orders(), firstErr
+}
+
+// GetOrders serves recorded orders for the orders page, fetching statuses
+// of open orders. Errors are sent along with the orders, rather than
+// closing the page.
+func GetOrders(ctx context.Context, dataChannel chan OrdersData) error {
+	fetched := time.Time{}
+
+	return utils.LoopTick(ctx, time.Duration(1)*time.Second, func(errChan chan error) {
+		var finalErr error = nil
+
+		defer func() {
+			if finalErr != nil {
+				errChan <- finalErr
+			}
+		}()
+
+		if time.Since(fetched) < utils.PollInterval(ordersRefresh) {
+			return
+		}
+		fetched = time.Now()
+
+		orders, err := RefreshOrders(ctx)
+
+		// Send Data
+		select {
+		case <-ctx.Done():
+			finalErr = ctx.Err()
+			return
+		case dataChannel <- OrdersData{Orders: orders, Err: err}:
+		}
+	})
+}
+
+// OrdersData holds recorded orders and the error of fetching their
+// statuses, if any
+type OrdersData struct {
+	Orders []Order
+	Err    error
+}
+
+// ordersMutex guards the orders file, written by both the page and the
+// routine fetching statuses
+var ordersMutex sync.Mutex
+
+// ordersFile is the name of the file orders are recorded in, kept hidden in
+// the home directory
+const ordersFile = "cryptgo-orders.json"
+
+// Orders returns recorded orders, latest first
+func Orders() []Order {
+	ordersMutex.Lock()
+	defer ordersMutex.Unlock()
+
+	return readOrders()
+}
+
+// readOrders reads recorded orders, latest first
+func readOrders() []Order {
+	orders := []Order{}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return orders
+	}
+	data, err := os.ReadFile(homeDir + "/." + ordersFile)
+	if err != nil || json.Unmarshal(data, &orders) != nil {
+		return []Order{}
+	}
+
+	sort.SliceStable(orders, func(i, j int) bool {
+		return orders[i].Placed.After(orders[j].Placed)
+	})
+	return orders
+}
+
+// saveOrder records an order, replacing the one of the same exchange and
+// ID
+func saveOrder(order Order) error {
+	ordersMutex.Lock()
+	defer ordersMutex.Unlock()
+
+	orders := []Order{order}
+	for _, o := range readOrders() {
+		if o.Exchange != order.Exchange || o.ID != order.ID {
+			orders = append(orders, o)
+		}
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+
+	// Visible and hidden paths are used for the same reason as in
+	// utils.SaveMetadata
+	data, err := json.MarshalIndent(orders, "", "\t")
+	if err != nil {
+		return err
+	}
+	return utils.WriteFile(homeDir+"/"+ordersFile, homeDir+"/."+ordersFile, data)
+}
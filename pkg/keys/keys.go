@@ -0,0 +1,297 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keys
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Actions bound to keys
+const (
+	Quit            = "quit"
+	Back            = "back"
+	Suspend         = "suspend"
+	Reload          = "reload"
+	Pause           = "pause"
+	Help            = "help"
+	Currency        = "currency"
+	CurrencyAll     = "currency_all"
+	Interval        = "interval"
+	IntervalShorter = "interval_shorter"
+	IntervalLonger  = "interval_longer"
+	HistoryRange    = "history_range"
+	ZoomIn          = "zoom_in"
+	ZoomOut         = "zoom_out"
+	PanLeft         = "pan_left"
+	PanRight        = "pan_right"
+	LogScale        = "log_scale"
+	Favourites      = "favourites"
+	Coins           = "coins"
+	Explorers       = "explorers"
+	Portfolio       = "portfolio"
+	Search          = "search"
+	Favourite       = "favourite"
+	Unfavourite     = "unfavourite"
+	Mark            = "mark"
+	Compare         = "compare"
+	RankAlert       = "rank_alert"
+	Alert           = "alert"
+	Priority        = "priority"
+	Source          = "source"
+	Quote           = "quote"
+	YearAgo         = "year_ago"
+	Candles         = "candles"
+	CandleTimeframe = "candle_timeframe"
+	OrderBook       = "order_book"
+	Markets         = "markets"
+	News            = "news"
+	Volume          = "volume"
+	CopySummary     = "copy_summary"
+	CopyStats       = "copy_stats"
+	Export          = "export"
+	ClearCache      = "clear_cache"
+	Report          = "report"
+	Theme           = "theme"
+	Watchlist       = "watchlist"
+	Workspace       = "workspace"
+	LowPower        = "low_power"
+	Down            = "down"
+	Up              = "up"
+	HalfPageDown    = "half_page_down"
+	HalfPageUp      = "half_page_up"
+	PageDown        = "page_down"
+	PageUp          = "page_up"
+	Top             = "top"
+	Bottom          = "bottom"
+	Select          = "select"
+	Edit            = "edit"
+)
+
+// navigation are the default keys of table navigation, shared by pages
+var navigation = map[string][]string{
+	Down:         {"j", "<Down>"},
+	Up:           {"k", "<Up>"},
+	HalfPageDown: {"<C-d>"},
+	HalfPageUp:   {"<C-u>"},
+	PageDown:     {"<C-f>"},
+	PageUp:       {"<C-b>"},
+	Top:          {"gg", "<Home>"},
+	Bottom:       {"G", "<End>"},
+}
+
+// Keymap translates termui event IDs of a page to named actions. Keys of
+// several characters, Eg: gg, are sequences pressed one after another.
+type Keymap struct {
+	defaults map[string][]string
+	actions  map[string]string
+	prefixes map[string]bool
+	pending  string
+}
+
+// newKeymap returns a keymap of a page binding actions to keys, along with
+// table navigation
+func newKeymap(defaults map[string][]string) *Keymap {
+	for action, keys := range navigation {
+		defaults[action] = keys
+	}
+
+	k := &Keymap{defaults: defaults}
+	if err := k.Set(nil); err != nil {
+		panic(err)
+	}
+
+	return k
+}
+
+// Main is the keymap of the main page
+var Main = newKeymap(map[string][]string{
+	Quit:        {"q", "<C-c>"},
+	Back:        {"<Escape>"},
+	Suspend:     {"<C-z>"},
+	Reload:      {"<C-r>"},
+	Pause:       {"p"},
+	Help:        {"?"},
+	Favourites:  {"f"},
+	Coins:       {"F"},
+	Currency:    {"c"},
+	CurrencyAll: {"C"},
+	Search:      {"/"},
+	Interval:    {"%"},
+	Portfolio:   {"P"},
+	Edit:        {"e"},
+	Select:      {"<Enter>"},
+	Favourite:   {"s"},
+	Unfavourite: {"S"},
+	ClearCache:  {"<C-l>"},
+	Report:      {"R"},
+	Theme:       {"t"},
+	Mark:        {"m"},
+	Compare:     {"M"},
+	RankAlert:   {"a"},
+	Watchlist:   {"w"},
+	Workspace:   {"W"},
+	LowPower:    {"L"},
+})
+
+// Coin is the keymap of the coin page
+var Coin = newKeymap(map[string][]string{
+	Quit:            {"<Escape>", "q", "<C-c>"},
+	Suspend:         {"<C-z>"},
+	Reload:          {"<C-r>"},
+	Help:            {"?"},
+	Currency:        {"c"},
+	CurrencyAll:     {"C"},
+	Interval:        {"d"},
+	IntervalShorter: {"<"},
+	IntervalLonger:  {">"},
+	HistoryRange:    {"D"},
+	ZoomIn:          {"+", "="},
+	ZoomOut:         {"-"},
+	PanLeft:         {"<Left>"},
+	PanRight:        {"<Right>"},
+	LogScale:        {"l"},
+	Favourites:      {"f"},
+	Explorers:       {"F"},
+	Priority:        {"r"},
+	Source:          {"s"},
+	Quote:           {"x"},
+	YearAgo:         {"H"},
+	Candles:         {"o"},
+	CandleTimeframe: {"O"},
+	OrderBook:       {"b"},
+	Markets:         {"m"},
+	News:            {"n"},
+	Volume:          {"v"},
+	Alert:           {"a"},
+	CopySummary:     {"y"},
+	CopyStats:       {"Y"},
+	Export:          {"E"},
+	ClearCache:      {"<C-l>"},
+	Report:          {"R"},
+	Theme:           {"t"},
+	Portfolio:       {"P"},
+	Select:          {"<Enter>"},
+	Edit:            {"e"},
+	LowPower:        {"L"},
+})
+
+// Keymaps maps names of pages to their keymaps, as set in the config file
+var Keymaps = map[string]*Keymap{
+	"main": Main,
+	"coin": Coin,
+}
+
+// isSequence returns true if key is a sequence of characters rather than a
+// single key, Eg: gg but not <C-c>
+func isSequence(key string) bool {
+	return utf8.RuneCountInString(key) > 1 && !strings.HasPrefix(key, "<")
+}
+
+// Set binds actions to the given keys, replacing their default keys. Other
+// actions keep their default keys.
+func (k *Keymap) Set(bindings map[string][]string) error {
+	actions, prefixes, err := k.bind(bindings)
+	if err != nil {
+		return err
+	}
+
+	k.actions = actions
+	k.prefixes = prefixes
+	k.pending = ""
+	return nil
+}
+
+// Check returns the error Set would return, without binding anything
+func (k *Keymap) Check(bindings map[string][]string) error {
+	_, _, err := k.bind(bindings)
+	return err
+}
+
+// bind returns actions of keys bound by bindings, and keys starting
+// sequences
+func (k *Keymap) bind(bindings map[string][]string) (map[string]string, map[string]bool, error) {
+	actions := map[string]string{}
+	prefixes := map[string]bool{}
+
+	// Bind actions in order, so conflicts are reported consistently
+	names := []string{}
+	for action := range k.defaults {
+		names = append(names, action)
+	}
+	sort.Strings(names)
+
+	for action := range bindings {
+		if _, ok := k.defaults[action]; !ok {
+			return nil, nil, fmt.Errorf("unknown action %q, expected one of %s", action, strings.Join(names, ", "))
+		}
+	}
+
+	for _, action := range names {
+		keys, ok := bindings[action]
+		if !ok {
+			keys = k.defaults[action]
+		}
+
+		for _, key := range keys {
+			if bound, ok := actions[key]; ok && bound != action {
+				return nil, nil, fmt.Errorf("key %q bound to both %s and %s", key, bound, action)
+			}
+			actions[key] = action
+
+			if isSequence(key) {
+				first, _ := utf8.DecodeRuneInString(key)
+				prefixes[string(first)] = true
+			}
+		}
+	}
+
+	// Keys starting sequences can't be bound on their own
+	for prefix := range prefixes {
+		if action, ok := actions[prefix]; ok {
+			return nil, nil, fmt.Errorf("key %q bound to %s also starts a sequence", prefix, action)
+		}
+	}
+
+	return actions, prefixes, nil
+}
+
+// Action returns the action bound to an event ID. IDs not bound to an
+// action, Eg: <Resize> or keys of sorting, are returned as is. Empty is
+// returned while a sequence is being typed.
+func (k *Keymap) Action(id string) string {
+	if k.pending != "" {
+		sequence := k.pending + id
+		k.pending = ""
+		if action, ok := k.actions[sequence]; ok {
+			return action
+		}
+	}
+
+	if action, ok := k.actions[id]; ok {
+		return action
+	}
+
+	if k.prefixes[id] {
+		k.pending = id
+		return ""
+	}
+
+	return id
+}
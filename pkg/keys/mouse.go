@@ -0,0 +1,62 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keys
+
+import (
+	"image"
+
+	"github.com/Gituser143/cryptgo/pkg/widgets"
+	ui "github.com/gizak/termui/v3"
+)
+
+// Mouse translates a mouse event to an action on the table it happened
+// over, among tables. The table is nil if it happened over none of them.
+//
+//   - The wheel scrolls the table up or down
+//   - Clicking a row moves the cursor to it, and clicking the row under the
+//     cursor again selects it, as <Enter> does
+func Mouse(e ui.Event, tables ...*widgets.Table) (*widgets.Table, string) {
+	mouse, ok := e.Payload.(ui.Mouse)
+	if !ok {
+		return nil, ""
+	}
+
+	for _, table := range tables {
+		if !image.Pt(mouse.X, mouse.Y).In(table.GetRect()) {
+			continue
+		}
+
+		switch e.ID {
+		case "<MouseWheelUp>":
+			return table, Up
+		case "<MouseWheelDown>":
+			return table, Down
+		case "<MouseLeft>":
+			row := table.RowAt(mouse.X, mouse.Y)
+			if row == -1 || mouse.Drag {
+				return table, ""
+			}
+			if row == table.SelectedRow && table.ShowCursor {
+				return table, Select
+			}
+			table.HandleClick(mouse.X, mouse.Y)
+		}
+		return table, ""
+	}
+
+	return nil, ""
+}
@@ -0,0 +1,106 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package portfolio
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+	geckoTypes "github.com/superoo7/go-gecko/v3/types"
+)
+
+// Kinds of orders, which determine the trading fee of a lot
+const (
+	Maker = "maker"
+	Taker = "taker"
+)
+
+// DefaultExchange names the fee schedule applied to lots without an exchange
+const DefaultExchange = "default"
+
+// FeeSchedule holds fees of an exchange. Maker and Taker are percentages of
+// the value traded, while Withdrawal maps coins (CoinGecko IDs or symbols) to
+// the quantity taken when withdrawing them.
+type FeeSchedule struct {
+	Maker      float64            `mapstructure:"maker"`
+	Taker      float64            `mapstructure:"taker"`
+	Withdrawal map[string]float64 `mapstructure:"withdrawal"`
+}
+
+// TradeFee returns the fee in percent of an order, taker if not given
+func (f FeeSchedule) TradeFee(order string) float64 {
+	if order == Maker {
+		return f.Maker
+	}
+	return f.Taker
+}
+
+// WithdrawalFee returns the quantity taken when withdrawing a coin, given by
+// the coin of a lot and its market data if found
+func (f FeeSchedule) WithdrawalFee(coin string, data *geckoTypes.CoinsMarketItem) float64 {
+	if fee, ok := f.Withdrawal[strings.ToLower(coin)]; ok {
+		return fee
+	}
+	if data != nil {
+		if fee, ok := f.Withdrawal[data.ID]; ok {
+			return fee
+		}
+		if fee, ok := f.Withdrawal[strings.ToLower(data.Symbol)]; ok {
+			return fee
+		}
+	}
+	return 0
+}
+
+// ReadFees reads fee schedules of exchanges from the config file. Lots
+// without an exchange are charged the "default" schedule, Eg:
+//
+//	fees:
+//	  default:
+//	    taker: 0.1
+//	  binance:
+//	    maker: 0.1
+//	    taker: 0.1
+//	    withdrawal:
+//	      bitcoin: 0.0002
+func ReadFees() (map[string]FeeSchedule, error) {
+	fees := map[string]FeeSchedule{}
+	if err := viper.UnmarshalKey("fees", &fees); err != nil {
+		return nil, fmt.Errorf("invalid fees: %v", err)
+	}
+
+	for exchange, schedule := range fees {
+		if schedule.Maker < 0 || schedule.Taker < 0 {
+			return nil, fmt.Errorf("fees of %s must not be negative", exchange)
+		}
+		withdrawal := map[string]float64{}
+		for coin, fee := range schedule.Withdrawal {
+			if fee < 0 {
+				return nil, fmt.Errorf("withdrawal fee of %s on %s must not be negative", coin, exchange)
+			}
+			withdrawal[strings.ToLower(coin)] = fee
+		}
+		schedule.Withdrawal = withdrawal
+		fees[exchange] = schedule
+	}
+
+	// Lots without an exchange use the default schedule
+	fees[""] = fees[DefaultExchange]
+
+	return fees, nil
+}
@@ -0,0 +1,153 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package portfolio
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
+)
+
+// dust is the quantity below which a lot is considered sold
+const dust = 1e-12
+
+// Realised holds profit/loss realised by selling a lot, or the part of one
+// sold. A sell of several lots is realised once for each.
+type Realised struct {
+	Coin     string
+	Acquired time.Time // Zero for quantity sold beyond what was bought
+	Disposed time.Time
+	Quantity float64
+	Proceeds float64 // USD, net of its share of the fee to sell
+	Cost     float64 // USD, of the lot sold including its share of fees
+	PnL      float64
+}
+
+// MatchFIFO matches sells of trades against the oldest lots bought of their
+// coin, first in first out. It returns lots left open, along with the P/L
+// realised by each lot sold. Sells of more than was bought, Eg: of coins
+// transferred in, are realised with no cost for the rest and reported in
+// warnings.
+func MatchFIFO(trades []utils.Trade) ([]Lot, []Realised, []string) {
+	trades = append([]utils.Trade{}, trades...)
+	sort.SliceStable(trades, func(i, j int) bool {
+		return trades[i].Time.Before(trades[j].Time)
+	})
+
+	open := map[string][]utils.Trade{}
+	coins := []string{}
+	realised := []Realised{}
+	warnings := []string{}
+
+	for _, t := range trades {
+		coin := strings.ToUpper(t.Coin)
+		if _, ok := open[coin]; !ok {
+			coins = append(coins, coin)
+		}
+
+		if t.Side == Buy {
+			open[coin] = append(open[coin], t)
+			continue
+		}
+
+		// Proceeds of each lot sold are its share of the sell's
+		proceeds := func(sold float64) float64 {
+			return (t.Quantity*t.Price - t.Fee) * sold / t.Quantity
+		}
+
+		// Take from the oldest lots, leaving the rest of a lot partly sold
+		// along with its share of the fee
+		left := t.Quantity
+		lots := open[coin]
+		for len(lots) > 0 && left > dust {
+			lot := &lots[0]
+			sold := left
+			if sold > lot.Quantity {
+				sold = lot.Quantity
+			}
+			share := sold / lot.Quantity
+
+			sale := Realised{
+				Coin:     coin,
+				Acquired: lot.Time,
+				Disposed: t.Time,
+				Quantity: sold,
+				Proceeds: proceeds(sold),
+				Cost:     sold*lot.Price + lot.Fee*share,
+			}
+			sale.PnL = sale.Proceeds - sale.Cost
+			realised = append(realised, sale)
+
+			lot.Fee -= lot.Fee * share
+			lot.Quantity -= sold
+			left -= sold
+
+			if lot.Quantity <= dust {
+				lots = lots[1:]
+			}
+		}
+		open[coin] = lots
+
+		if left > dust {
+			warnings = append(warnings, fmt.Sprintf("%s sold %g %s more than bought, realised with no cost",
+				t.Time.Format(DateLayout), left, coin))
+			realised = append(realised, Realised{
+				Coin:     coin,
+				Disposed: t.Time,
+				Quantity: left,
+				Proceeds: proceeds(left),
+				PnL:      proceeds(left),
+			})
+		}
+	}
+
+	// Lots left open are held
+	lots := []Lot{}
+	for _, coin := range coins {
+		for _, t := range open[coin] {
+			lots = append(lots, Lot{
+				Coin:     coin,
+				Quantity: t.Quantity,
+				Price:    t.Price,
+				Date:     t.Time.Format(DateLayout),
+				Exchange: strings.ToLower(t.Exchange),
+				Fee:      t.Fee,
+			})
+		}
+	}
+
+	return lots, realised, warnings
+}
+
+// ImportedLots returns lots left open by trades imported to the local
+// store, along with the P/L realised by the lots sold
+func ImportedLots() ([]Lot, []Realised) {
+	lots, realised, _ := MatchFIFO(utils.GetTrades())
+	return lots, realised
+}
+
+// RealisedPnL returns the total P/L realised by lots sold
+func RealisedPnL(realised []Realised) float64 {
+	total := 0.0
+	for _, sale := range realised {
+		total += sale.PnL
+	}
+	return total
+}
@@ -0,0 +1,207 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package portfolio
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
+)
+
+// day returns midnight UTC of a date, Eg: day(2024, 3, 1)
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+// near returns true if a and b are equal but for rounding
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+// sameLots returns true if lots match, with quantities and fees compared
+// but for rounding
+func sameLots(got, want []Lot) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		g, w := got[i], want[i]
+		if g.Coin != w.Coin || g.Date != w.Date || g.Exchange != w.Exchange || g.Price != w.Price ||
+			!near(g.Quantity, w.Quantity) || !near(g.Fee, w.Fee) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameRealised returns true if sales match, with amounts compared but for
+// rounding
+func sameRealised(got, want []Realised) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		g, w := got[i], want[i]
+		if g.Coin != w.Coin || !g.Acquired.Equal(w.Acquired) || !g.Disposed.Equal(w.Disposed) ||
+			!near(g.Quantity, w.Quantity) || !near(g.Proceeds, w.Proceeds) ||
+			!near(g.Cost, w.Cost) || !near(g.PnL, w.PnL) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestMatchFIFO(t *testing.T) {
+	tests := []struct {
+		name         string
+		trades       []utils.Trade
+		wantLots     []Lot
+		wantRealised []Realised
+		wantWarnings []string
+	}{
+		{
+			name: "buys only",
+			trades: []utils.Trade{
+				{Time: day(2024, 1, 5), Coin: "btc", Side: Buy, Quantity: 0.5, Price: 42000, Fee: 21, Exchange: "Binance"},
+				{Time: day(2024, 1, 2), Coin: "ETH", Side: Buy, Quantity: 2, Price: 2300, Fee: 4.6},
+			},
+			wantLots: []Lot{
+				{Coin: "ETH", Quantity: 2, Price: 2300, Date: "2024-01-02", Fee: 4.6},
+				{Coin: "BTC", Quantity: 0.5, Price: 42000, Date: "2024-01-05", Exchange: "binance", Fee: 21},
+			},
+			wantRealised: []Realised{},
+			wantWarnings: []string{},
+		},
+		{
+			name: "partial lot with fees",
+			trades: []utils.Trade{
+				{Time: day(2024, 1, 1), Coin: "BTC", Side: Buy, Quantity: 2, Price: 100, Fee: 2},
+				{Time: day(2024, 2, 1), Coin: "BTC", Side: Sell, Quantity: 0.5, Price: 150, Fee: 1.5},
+			},
+			wantLots: []Lot{
+				{Coin: "BTC", Quantity: 1.5, Price: 100, Date: "2024-01-01", Fee: 1.5},
+			},
+			wantRealised: []Realised{
+				{Coin: "BTC", Acquired: day(2024, 1, 1), Disposed: day(2024, 2, 1), Quantity: 0.5, Proceeds: 73.5, Cost: 50.5, PnL: 23},
+			},
+			wantWarnings: []string{},
+		},
+		{
+			name: "sell across lots",
+			trades: []utils.Trade{
+				{Time: day(2024, 1, 1), Coin: "BTC", Side: Buy, Quantity: 1, Price: 100, Fee: 1},
+				{Time: day(2024, 2, 1), Coin: "BTC", Side: Buy, Quantity: 1, Price: 200, Fee: 2},
+				{Time: day(2024, 3, 1), Coin: "BTC", Side: Sell, Quantity: 1.5, Price: 300, Fee: 3},
+			},
+			wantLots: []Lot{
+				{Coin: "BTC", Quantity: 0.5, Price: 200, Date: "2024-02-01", Fee: 1},
+			},
+			wantRealised: []Realised{
+				{Coin: "BTC", Acquired: day(2024, 1, 1), Disposed: day(2024, 3, 1), Quantity: 1, Proceeds: 298, Cost: 101, PnL: 197},
+				{Coin: "BTC", Acquired: day(2024, 2, 1), Disposed: day(2024, 3, 1), Quantity: 0.5, Proceeds: 149, Cost: 101, PnL: 48},
+			},
+			wantWarnings: []string{},
+		},
+		{
+			name: "sells matched in order of time",
+			trades: []utils.Trade{
+				{Time: day(2024, 3, 1), Coin: "ETH", Side: Sell, Quantity: 1, Price: 3000},
+				{Time: day(2024, 2, 1), Coin: "ETH", Side: Buy, Quantity: 1, Price: 2500},
+				{Time: day(2024, 1, 1), Coin: "ETH", Side: Buy, Quantity: 1, Price: 2000},
+			},
+			wantLots: []Lot{
+				{Coin: "ETH", Quantity: 1, Price: 2500, Date: "2024-02-01"},
+			},
+			wantRealised: []Realised{
+				{Coin: "ETH", Acquired: day(2024, 1, 1), Disposed: day(2024, 3, 1), Quantity: 1, Proceeds: 3000, Cost: 2000, PnL: 1000},
+			},
+			wantWarnings: []string{},
+		},
+		{
+			name: "coins matched apart",
+			trades: []utils.Trade{
+				{Time: day(2024, 1, 1), Coin: "BTC", Side: Buy, Quantity: 1, Price: 40000},
+				{Time: day(2024, 1, 2), Coin: "ETH", Side: Buy, Quantity: 10, Price: 2000},
+				{Time: day(2024, 1, 3), Coin: "eth", Side: Sell, Quantity: 4, Price: 2100},
+			},
+			wantLots: []Lot{
+				{Coin: "BTC", Quantity: 1, Price: 40000, Date: "2024-01-01"},
+				{Coin: "ETH", Quantity: 6, Price: 2000, Date: "2024-01-02"},
+			},
+			wantRealised: []Realised{
+				{Coin: "ETH", Acquired: day(2024, 1, 2), Disposed: day(2024, 1, 3), Quantity: 4, Proceeds: 8400, Cost: 8000, PnL: 400},
+			},
+			wantWarnings: []string{},
+		},
+		{
+			name: "sell larger than holdings",
+			trades: []utils.Trade{
+				{Time: day(2024, 1, 1), Coin: "SOL", Side: Buy, Quantity: 1, Price: 100},
+				{Time: day(2024, 3, 1), Coin: "SOL", Side: Sell, Quantity: 3, Price: 150, Fee: 3},
+			},
+			wantLots: []Lot{},
+			wantRealised: []Realised{
+				{Coin: "SOL", Acquired: day(2024, 1, 1), Disposed: day(2024, 3, 1), Quantity: 1, Proceeds: 149, Cost: 100, PnL: 49},
+				{Coin: "SOL", Disposed: day(2024, 3, 1), Quantity: 2, Proceeds: 298, PnL: 298},
+			},
+			wantWarnings: []string{"2024-03-01 sold 2 SOL more than bought, realised with no cost"},
+		},
+		{
+			name: "sell without buys",
+			trades: []utils.Trade{
+				{Time: day(2024, 4, 1), Coin: "DOT", Side: Sell, Quantity: 10, Price: 8, Fee: 0.8},
+			},
+			wantLots: []Lot{},
+			wantRealised: []Realised{
+				{Coin: "DOT", Disposed: day(2024, 4, 1), Quantity: 10, Proceeds: 79.2, PnL: 79.2},
+			},
+			wantWarnings: []string{"2024-04-01 sold 10 DOT more than bought, realised with no cost"},
+		},
+		{
+			name: "lot sold out exactly",
+			trades: []utils.Trade{
+				{Time: day(2024, 1, 1), Coin: "BTC", Side: Buy, Quantity: 0.3, Price: 40000, Fee: 12},
+				{Time: day(2024, 2, 1), Coin: "BTC", Side: Sell, Quantity: 0.1, Price: 45000},
+				{Time: day(2024, 3, 1), Coin: "BTC", Side: Sell, Quantity: 0.2, Price: 50000},
+			},
+			wantLots: []Lot{},
+			wantRealised: []Realised{
+				{Coin: "BTC", Acquired: day(2024, 1, 1), Disposed: day(2024, 2, 1), Quantity: 0.1, Proceeds: 4500, Cost: 4004, PnL: 496},
+				{Coin: "BTC", Acquired: day(2024, 1, 1), Disposed: day(2024, 3, 1), Quantity: 0.2, Proceeds: 10000, Cost: 8008, PnL: 1992},
+			},
+			wantWarnings: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lots, realised, warnings := MatchFIFO(tt.trades)
+			if !sameLots(lots, tt.wantLots) {
+				t.Errorf("MatchFIFO() lots = %+v, want %+v", lots, tt.wantLots)
+			}
+			if !sameRealised(realised, tt.wantRealised) {
+				t.Errorf("MatchFIFO() realised = %+v, want %+v", realised, tt.wantRealised)
+			}
+			if !reflect.DeepEqual(warnings, tt.wantWarnings) {
+				t.Errorf("MatchFIFO() warnings = %q, want %q", warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
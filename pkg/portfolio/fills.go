@@ -0,0 +1,47 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package portfolio
+
+import (
+	"fmt"
+
+	"github.com/Gituser143/cryptgo/pkg/exchange"
+	"github.com/Gituser143/cryptgo/pkg/utils"
+)
+
+// FillTrades returns trades of fills of connected exchange accounts, to be
+// merged with imported trades. Fills which can't be taken as trades are
+// returned as errors, Eg: those of pairs whose quote asset is unknown.
+func FillTrades(fills []exchange.Fill) ([]utils.Trade, []error) {
+	trades := []utils.Trade{}
+	errs := []error{}
+
+	for _, fill := range fills {
+		if fill.Quote == "" {
+			errs = append(errs, fmt.Errorf("fill %s on %s: unknown quote asset of %s", fill.ID, fill.Exchange, fill.Base))
+			continue
+		}
+		fillTrades, err := pairTrades(fill.Time, fill.Base, fill.Quote, fill.Side, fill.Quantity, fill.Price, fill.Amount, fill.Fee, fill.FeeAsset, fill.Exchange)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("fill %s on %s: %v", fill.ID, fill.Exchange, err))
+			continue
+		}
+		trades = append(trades, fillTrades...)
+	}
+
+	return trades, errs
+}
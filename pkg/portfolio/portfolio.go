@@ -0,0 +1,258 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package portfolio
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	geckoTypes "github.com/superoo7/go-gecko/v3/types"
+)
+
+// DateLayout is the layout of purchase dates in the config file
+const DateLayout = "2006-01-02"
+
+// Lot is a purchase of a coin, read from holdings in the config file or
+// left open by imported trades. Coin is a CoinGecko ID or symbol, and Price
+// is the buy price in USD. Fees of Exchange are applied to the lot, a maker
+// or taker fee depending on Order and the withdrawal fee of the coin if it
+// was Withdrawn. Fee is the fee paid in USD if known, in place of the
+// trading fee of Exchange.
+type Lot struct {
+	Coin      string  `mapstructure:"coin"`
+	Quantity  float64 `mapstructure:"quantity"`
+	Price     float64 `mapstructure:"price"`
+	Date      string  `mapstructure:"date"`
+	Exchange  string  `mapstructure:"exchange"`
+	Order     string  `mapstructure:"order"`
+	Withdrawn bool    `mapstructure:"withdrawn"`
+	Fee       float64 `mapstructure:"fee"`
+}
+
+// Position aggregates the lots of a coin. Priced is false if the coin isn't
+// among the fetched coins, in which case only the quantity and cost are set.
+// Cost includes fees paid, while P/L is net of the fees to sell the position
+// at its current price (ExitFees).
+type Position struct {
+	ID         string
+	Symbol     string
+	Quantity   float64
+	Cost       float64
+	Fees       float64
+	ExitFees   float64
+	Price      float64
+	Value      float64
+	PnL        float64
+	PnLPercent float64
+	Allocation float64
+	Since      time.Time
+	Priced     bool
+
+	// Sum of quantities of lots weighted by the taker fee to sell them
+	exitFeeQuantity float64
+}
+
+// AverageBuyPrice returns the cost per coin of the position
+func (p Position) AverageBuyPrice() float64 {
+	if p.Quantity == 0 {
+		return 0
+	}
+	return p.Cost / p.Quantity
+}
+
+// Summary holds positions along with their totals. Totals only include
+// priced positions.
+type Summary struct {
+	Positions  []Position
+	Value      float64
+	Cost       float64
+	Fees       float64
+	ExitFees   float64
+	PnL        float64
+	PnLPercent float64
+}
+
+// ReadLots reads holdings from the config file, along with lots left open by
+// imported trades, Eg:
+//
+//	holdings:
+//	  - coin: bitcoin
+//	    quantity: 0.5
+//	    price: 30000
+//	    date: 2021-05-01
+//	    exchange: binance
+func ReadLots() ([]Lot, error) {
+	lots := []Lot{}
+	if err := viper.UnmarshalKey("holdings", &lots); err != nil {
+		return nil, fmt.Errorf("invalid holdings: %v", err)
+	}
+
+	fees, err := ReadFees()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, lot := range lots {
+		if lot.Coin == "" {
+			return nil, fmt.Errorf("holding %d has no coin", i+1)
+		}
+		if lot.Quantity <= 0 {
+			return nil, fmt.Errorf("holding %d (%s) must have a positive quantity", i+1, lot.Coin)
+		}
+		if lot.Price < 0 {
+			return nil, fmt.Errorf("holding %d (%s) has a negative price", i+1, lot.Coin)
+		}
+		if lot.Date != "" {
+			if _, err := time.Parse(DateLayout, lot.Date); err != nil {
+				return nil, fmt.Errorf("holding %d (%s) has an invalid date, expected YYYY-MM-DD", i+1, lot.Coin)
+			}
+		}
+		// Exchanges are matched regardless of case, like keys of the config
+		lot.Exchange = strings.ToLower(lot.Exchange)
+		lots[i].Exchange = lot.Exchange
+		if _, ok := fees[lot.Exchange]; lot.Exchange != "" && !ok {
+			return nil, fmt.Errorf("holding %d (%s) has exchange %q with no fees set", i+1, lot.Coin, lot.Exchange)
+		}
+		if lot.Order != "" && lot.Order != Maker && lot.Order != Taker {
+			return nil, fmt.Errorf("holding %d (%s) has an invalid order, expected maker or taker", i+1, lot.Coin)
+		}
+		if lot.Fee < 0 {
+			return nil, fmt.Errorf("holding %d (%s) has a negative fee", i+1, lot.Coin)
+		}
+	}
+
+	imported, _ := ImportedLots()
+	return append(lots, imported...), nil
+}
+
+// findCoin returns the coin in coinsData specified by a CoinGecko ID or
+// symbol, matching IDs before symbols. nil is returned if it isn't found.
+func findCoin(coin string, coinsData geckoTypes.CoinsMarket) *geckoTypes.CoinsMarketItem {
+	coin = strings.ToLower(coin)
+
+	var found *geckoTypes.CoinsMarketItem
+	for i, val := range coinsData {
+		if val.ID == coin {
+			return &coinsData[i]
+		}
+		if found == nil && strings.ToLower(val.Symbol) == coin {
+			found = &coinsData[i]
+		}
+	}
+
+	return found
+}
+
+// Compute aggregates lots into positions and prices them from coinsData,
+// returning positions sorted by value. Fees of the lots' exchanges are taken
+// from fees.
+func Compute(lots []Lot, fees map[string]FeeSchedule, coinsData geckoTypes.CoinsMarket) Summary {
+	summary := Summary{}
+
+	// Aggregate lots per coin, lots of a coin may refer to it by ID or symbol
+	positions := map[string]*Position{}
+	order := []string{}
+	for _, lot := range lots {
+		key := strings.ToLower(lot.Coin)
+		coin := findCoin(lot.Coin, coinsData)
+		if coin != nil {
+			key = coin.ID
+		}
+
+		position, ok := positions[key]
+		if !ok {
+			position = &Position{ID: key, Symbol: strings.ToUpper(lot.Coin)}
+			if coin != nil {
+				position.Symbol = strings.ToUpper(coin.Symbol)
+				position.Price = coin.CurrentPrice
+				position.Priced = true
+			}
+			positions[key] = position
+			order = append(order, key)
+		}
+
+		// Trading fees add to the cost, withdrawal fees take from the
+		// quantity held
+		schedule := fees[lot.Exchange]
+		quantity := lot.Quantity
+		buyFee := lot.Quantity * lot.Price * schedule.TradeFee(lot.Order) / 100
+		if lot.Fee > 0 {
+			buyFee = lot.Fee
+		}
+		if lot.Withdrawn {
+			withdrawal := schedule.WithdrawalFee(lot.Coin, coin)
+			if withdrawal > quantity {
+				withdrawal = quantity
+			}
+			quantity -= withdrawal
+			position.Fees += withdrawal * lot.Price
+		}
+
+		position.Quantity += quantity
+		position.Cost += lot.Quantity*lot.Price + buyFee
+		position.Fees += buyFee
+		position.exitFeeQuantity += quantity * schedule.Taker
+
+		if date, err := time.Parse(DateLayout, lot.Date); err == nil {
+			if position.Since.IsZero() || date.Before(position.Since) {
+				position.Since = date
+			}
+		}
+	}
+
+	// Value priced positions
+	for _, key := range order {
+		position := positions[key]
+
+		if position.Priced {
+			position.Value = position.Quantity * position.Price
+			position.ExitFees = position.exitFeeQuantity * position.Price / 100
+			position.PnL = position.Value - position.ExitFees - position.Cost
+			if position.Cost > 0 {
+				position.PnLPercent = position.PnL / position.Cost * 100
+			}
+
+			summary.Value += position.Value
+			summary.Cost += position.Cost
+			summary.Fees += position.Fees
+			summary.ExitFees += position.ExitFees
+		}
+
+		summary.Positions = append(summary.Positions, *position)
+	}
+
+	// Set allocations and totals
+	for i, position := range summary.Positions {
+		if position.Priced && summary.Value > 0 {
+			summary.Positions[i].Allocation = position.Value / summary.Value * 100
+		}
+	}
+
+	summary.PnL = summary.Value - summary.ExitFees - summary.Cost
+	if summary.Cost > 0 {
+		summary.PnLPercent = summary.PnL / summary.Cost * 100
+	}
+
+	sort.SliceStable(summary.Positions, func(i, j int) bool {
+		return summary.Positions[i].Value > summary.Positions[j].Value
+	})
+
+	return summary
+}
@@ -0,0 +1,183 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package portfolio
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
+)
+
+// LongTermDays is the holding period after which gains are long term
+const LongTermDays = 365
+
+// GainColumns are columns of the realised gains CSV
+var GainColumns = []string{
+	"tax_year", "coin", "quantity", "acquired", "disposed", "holding_days",
+	"term", "proceeds_usd", "cost_basis_usd", "gain_usd",
+}
+
+// Gain is a lot sold, in the tax year it was sold in
+type Gain struct {
+	Realised
+	TaxYear     string
+	HoldingDays int // -1 if not known
+}
+
+// LongTerm returns true if the lot was held longer than LongTermDays
+func (g Gain) LongTerm() bool {
+	return g.HoldingDays > LongTermDays
+}
+
+// Term returns short or long for the holding period, or unknown for
+// quantity sold beyond what was bought
+func (g Gain) Term() string {
+	switch {
+	case g.HoldingDays < 0:
+		return "unknown"
+	case g.LongTerm():
+		return "long"
+	default:
+		return "short"
+	}
+}
+
+// PriceTrades sets the USD price of trades missing one, Eg: trades of pairs
+// not quoted in USD, to the price of their coin at the time given by
+// priceAt. Prices of a coin are fetched once an hour of trades. Trades which
+// couldn't be priced are left missing one, and reported in errs.
+func PriceTrades(trades []utils.Trade, priceAt func(coin string, t time.Time) (float64, error)) ([]utils.Trade, []error) {
+	priced := append([]utils.Trade{}, trades...)
+	prices := map[string]float64{}
+	failed := map[string]bool{}
+	errs := []error{}
+
+	for i, t := range priced {
+		if t.Price > 0 {
+			continue
+		}
+
+		key := t.Coin + t.Time.Truncate(time.Hour).String()
+		if failed[key] {
+			continue
+		}
+		price, ok := prices[key]
+		if !ok {
+			var err error
+			price, err = priceAt(t.Coin, t.Time)
+			if err != nil {
+				failed[key] = true
+				errs = append(errs, fmt.Errorf("%s %s of %s: %v", t.Time.Format(DateLayout), t.Side, t.Coin, err))
+				continue
+			}
+			prices[key] = price
+		}
+
+		priced[i].Price = price
+	}
+
+	return priced, errs
+}
+
+// TaxYear names the tax year t is in, for tax years starting on the month
+// and day of start, Eg: 2023 for years starting on January 1 or 2023-24 for
+// years starting on April 6
+func TaxYear(t time.Time, start time.Time) string {
+	year := t.Year()
+	if t.Before(time.Date(year, start.Month(), start.Day(), 0, 0, 0, 0, t.Location())) {
+		year--
+	}
+
+	if start.Month() == time.January && start.Day() == 1 {
+		return strconv.Itoa(year)
+	}
+	return fmt.Sprintf("%d-%02d", year, (year+1)%100)
+}
+
+// Gains returns lots sold as gains of the tax years they were sold in, for
+// tax years starting on the month and day of yearStart. Gains are in order
+// of when they were sold, and only those of year are returned unless it is
+// empty.
+func Gains(realised []Realised, yearStart time.Time, year string) []Gain {
+	gains := []Gain{}
+	for _, sale := range realised {
+		gain := Gain{
+			Realised:    sale,
+			TaxYear:     TaxYear(sale.Disposed, yearStart),
+			HoldingDays: -1,
+		}
+		if year != "" && gain.TaxYear != year {
+			continue
+		}
+		if !sale.Acquired.IsZero() {
+			gain.HoldingDays = int(sale.Disposed.Sub(sale.Acquired).Hours() / 24)
+		}
+		gains = append(gains, gain)
+	}
+
+	sort.SliceStable(gains, func(i, j int) bool {
+		return gains[i].Disposed.Before(gains[j].Disposed)
+	})
+	return gains
+}
+
+// WriteGainsCSV writes gains to w as CSV, with a header of GainColumns
+func WriteGainsCSV(w io.Writer, gains []Gain) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(GainColumns); err != nil {
+		return err
+	}
+
+	usd := func(val float64) string {
+		return strconv.FormatFloat(math.Round(val*100)/100, 'f', 2, 64)
+	}
+	for _, g := range gains {
+		acquired := ""
+		if !g.Acquired.IsZero() {
+			acquired = g.Acquired.Format(DateLayout)
+		}
+		days := ""
+		if g.HoldingDays >= 0 {
+			days = strconv.Itoa(g.HoldingDays)
+		}
+
+		record := []string{
+			g.TaxYear,
+			g.Coin,
+			strconv.FormatFloat(g.Quantity, 'f', -1, 64),
+			acquired,
+			g.Disposed.Format(DateLayout),
+			days,
+			g.Term(),
+			usd(g.Proceeds),
+			usd(g.Cost),
+			usd(g.PnL),
+		}
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
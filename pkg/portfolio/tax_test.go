@@ -0,0 +1,147 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package portfolio
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTaxYear(t *testing.T) {
+	january := day(2000, 1, 1)
+	april := day(2000, 4, 6)
+
+	tests := []struct {
+		name  string
+		t     time.Time
+		start time.Time
+		want  string
+	}{
+		{"calendar year", day(2023, 7, 14), january, "2023"},
+		{"last moment of calendar year", time.Date(2023, 12, 31, 23, 59, 59, 0, time.UTC), january, "2023"},
+		{"first day of calendar year", day(2024, 1, 1), january, "2024"},
+		{"day before april start", day(2024, 4, 5), april, "2023-24"},
+		{"april start", day(2024, 4, 6), april, "2024-25"},
+		{"january in april year", day(2024, 1, 20), april, "2023-24"},
+		{"april year across century", day(2000, 2, 1), april, "1999-00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TaxYear(tt.t, tt.start); got != tt.want {
+				t.Errorf("TaxYear(%v, %v) = %q, want %q", tt.t, tt.start, got, tt.want)
+			}
+		})
+	}
+}
+
+// gainSummary is what a gain is checked against, the tax year and holding
+// period of the lot sold
+type gainSummary struct {
+	Coin        string
+	TaxYear     string
+	HoldingDays int
+	Term        string
+}
+
+func TestGains(t *testing.T) {
+	sale := func(coin string, acquired, disposed time.Time) Realised {
+		return Realised{Coin: coin, Acquired: acquired, Disposed: disposed, Quantity: 1, Proceeds: 200, Cost: 100, PnL: 100}
+	}
+
+	realised := []Realised{
+		// Held a year to the day, short term
+		sale("BTC", day(2023, 1, 1), day(2024, 1, 1)),
+		// Held a day past a year, long term
+		sale("ETH", day(2022, 12, 31), day(2024, 1, 1)),
+		// Held a year over February 29, long term
+		sale("SOL", day(2023, 3, 1), day(2024, 3, 1)),
+		// Sold the day before the tax year starting April 6
+		sale("DOT", day(2023, 10, 1), day(2024, 4, 5)),
+		// Sold on the day the tax year starting April 6 starts
+		sale("ADA", day(2023, 10, 1), day(2024, 4, 6)),
+		// Sold beyond what was bought, so held for an unknown period
+		{Coin: "XRP", Disposed: day(2023, 12, 31), Quantity: 5, Proceeds: 3, PnL: 3},
+	}
+
+	tests := []struct {
+		name      string
+		yearStart time.Time
+		year      string
+		want      []gainSummary
+	}{
+		{
+			name:      "calendar years",
+			yearStart: day(2000, 1, 1),
+			want: []gainSummary{
+				{"XRP", "2023", -1, "unknown"},
+				{"BTC", "2024", 365, "short"},
+				{"ETH", "2024", 366, "long"},
+				{"SOL", "2024", 366, "long"},
+				{"DOT", "2024", 187, "short"},
+				{"ADA", "2024", 188, "short"},
+			},
+		},
+		{
+			name:      "calendar year across boundary",
+			yearStart: day(2000, 1, 1),
+			year:      "2023",
+			want: []gainSummary{
+				{"XRP", "2023", -1, "unknown"},
+			},
+		},
+		{
+			name:      "april years",
+			yearStart: day(2000, 4, 6),
+			want: []gainSummary{
+				{"XRP", "2023-24", -1, "unknown"},
+				{"BTC", "2023-24", 365, "short"},
+				{"ETH", "2023-24", 366, "long"},
+				{"SOL", "2023-24", 366, "long"},
+				{"DOT", "2023-24", 187, "short"},
+				{"ADA", "2024-25", 188, "short"},
+			},
+		},
+		{
+			name:      "april year starting on the day sold",
+			yearStart: day(2000, 4, 6),
+			year:      "2024-25",
+			want: []gainSummary{
+				{"ADA", "2024-25", 188, "short"},
+			},
+		},
+		{
+			name:      "year without sales",
+			yearStart: day(2000, 1, 1),
+			year:      "2022",
+			want:      []gainSummary{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []gainSummary{}
+			for _, g := range Gains(realised, tt.yearStart, tt.year) {
+				got = append(got, gainSummary{g.Coin, g.TaxYear, g.HoldingDays, g.Term()})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Gains(%q) = %+v, want %+v", tt.year, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,477 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package portfolio
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
+)
+
+// Sides of a trade
+const (
+	Buy  = "buy"
+	Sell = "sell"
+)
+
+// Formats of trade exports which can be imported
+const (
+	FormatAuto     = "auto"
+	FormatCoinbase = "coinbase"
+	FormatBinance  = "binance"
+	FormatGeneric  = "generic"
+)
+
+// ImportFormats are formats trades can be imported from, auto detecting
+// the format by its header by default
+var ImportFormats = []string{FormatAuto, FormatCoinbase, FormatBinance, FormatGeneric}
+
+// formatColumns are columns a header must have to be of a format, in lower
+// case
+var formatColumns = map[string][]string{
+	FormatCoinbase: {"timestamp", "transaction type", "asset", "quantity transacted"},
+	FormatBinance:  {"pair", "side", "price", "executed"},
+	FormatGeneric:  {"date", "type", "coin", "quantity", "price"},
+}
+
+// usdQuotes are assets prices are taken as USD in, longest first so pairs
+// are split by the longest quote they end with, Eg: BTCBUSD
+var usdQuotes = []string{"FDUSD", "USDT", "USDC", "BUSD", "TUSD", "DAI", "USD"}
+
+// timeLayouts are layouts times of exports are given in
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05 MST",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	DateLayout,
+}
+
+// coinbaseConvert reads notes of Coinbase conversions, Eg: Converted 0.1
+// ETH to 180.5 USDC
+var coinbaseConvert = regexp.MustCompile(`(?i)converted\s+([\d.,]+)\s+(\S+)\s+to\s+([\d.,]+)\s+(\S+)`)
+
+// SkippedRow is a row of an export which couldn't be imported
+type SkippedRow struct {
+	Line   int
+	Reason string
+}
+
+// row gives access to columns of a CSV row by their name in the header
+type row struct {
+	cols   map[string]int
+	fields []string
+}
+
+// get returns the first of the named columns the row has, trimmed
+func (r row) get(names ...string) string {
+	for _, name := range names {
+		if i, ok := r.cols[name]; ok && i < len(r.fields) {
+			return strings.TrimSpace(r.fields[i])
+		}
+	}
+	return ""
+}
+
+// parseNumber reads a number of an export, which may have currency signs
+// and thousands separators, Eg: -$1,234.50. Signs are dropped, as the side
+// of a trade is given by its type.
+func parseNumber(s string) (float64, error) {
+	s = strings.NewReplacer("$", "", "€", "", "£", "", ",", "", " ", "").Replace(s)
+	if s == "" {
+		return 0, nil
+	}
+	val, err := strconv.ParseFloat(s, 64)
+	return math.Abs(val), err
+}
+
+// parseAmount reads an amount suffixed with its asset, Eg: 0.5BTC
+func parseAmount(s string) (float64, string, error) {
+	s = strings.ReplaceAll(strings.TrimSpace(s), ",", "")
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.' && r != '-'
+	})
+	if i == -1 {
+		i = len(s)
+	}
+
+	val, err := parseNumber(s[:i])
+	return val, strings.ToUpper(strings.TrimSpace(s[i:])), err
+}
+
+// parseTime reads a time of an export, in UTC unless zoned
+func parseTime(s string) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q", s)
+}
+
+// findHeader returns the index of the header row of an export and its
+// format, skipping lines before it, Eg: the account details Coinbase starts
+// exports with
+func findHeader(records [][]string, format string) (int, string, error) {
+	formats := []string{format}
+	if format == FormatAuto {
+		formats = []string{FormatCoinbase, FormatBinance, FormatGeneric}
+	} else if _, ok := formatColumns[format]; !ok {
+		return 0, "", fmt.Errorf("unknown format %q, expected one of: %s", format, strings.Join(ImportFormats, ", "))
+	}
+
+	for i, record := range records {
+		cols := map[string]bool{}
+		for _, col := range record {
+			cols[strings.ToLower(strings.TrimSpace(col))] = true
+		}
+
+		for _, f := range formats {
+			found := true
+			for _, col := range formatColumns[f] {
+				found = found && cols[col]
+			}
+			if found {
+				return i, f, nil
+			}
+		}
+	}
+
+	if format == FormatAuto {
+		return 0, "", fmt.Errorf("unknown export, expected a Coinbase or Binance export, or columns %s", strings.Join(formatColumns[FormatGeneric], ", "))
+	}
+	return 0, "", fmt.Errorf("no %s header found, expected columns %s", format, strings.Join(formatColumns[format], ", "))
+}
+
+// ParseTrades reads trades from a CSV export of an exchange, or a generic
+// export with columns date, type (buy or sell), coin, quantity, price and
+// optionally fee and exchange. Rows which aren't trades priced in USD are
+// skipped, along with why.
+func ParseTrades(r io.Reader, format string) ([]utils.Trade, []SkippedRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	start, format, err := findHeader(records, format)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cols := map[string]int{}
+	for i, col := range records[start] {
+		cols[strings.ToLower(strings.TrimSpace(col))] = i
+	}
+
+	trades := []utils.Trade{}
+	skipped := []SkippedRow{}
+	for i, record := range records[start+1:] {
+		line := start + i + 2
+		if len(record) == 0 || (len(record) == 1 && strings.TrimSpace(record[0]) == "") {
+			continue
+		}
+
+		var parsed []utils.Trade
+		switch format {
+		case FormatCoinbase:
+			parsed, err = parseCoinbase(row{cols, record})
+		case FormatBinance:
+			parsed, err = parseBinance(row{cols, record})
+		default:
+			parsed, err = parseGeneric(row{cols, record})
+		}
+
+		if err != nil {
+			skipped = append(skipped, SkippedRow{Line: line, Reason: err.Error()})
+			continue
+		}
+		trades = append(trades, parsed...)
+	}
+
+	return trades, skipped, nil
+}
+
+// parseCoinbase reads a row of a Coinbase transaction history. Rewards and
+// staking income are bought at their spot price, and conversions are a sell
+// of one asset and a buy of the other. Prices in other currencies than USD
+// are left missing, along with fees, to be priced by PriceTrades.
+func parseCoinbase(r row) ([]utils.Trade, error) {
+	t, err := parseTime(r.get("timestamp"))
+	if err != nil {
+		return nil, err
+	}
+	kind := strings.ToLower(r.get("transaction type"))
+	asset := strings.ToUpper(r.get("asset"))
+	currency := strings.ToUpper(r.get("spot price currency"))
+
+	quantity, err := parseNumber(r.get("quantity transacted"))
+	if err != nil {
+		return nil, err
+	}
+	price, err := parseNumber(r.get("spot price at transaction"))
+	if err != nil {
+		return nil, err
+	}
+	fee, err := parseNumber(r.get("fees and/or spread", "fees"))
+	if err != nil {
+		return nil, err
+	}
+
+	trade := utils.Trade{
+		Time:     t,
+		Coin:     asset,
+		Quantity: quantity,
+		Price:    price,
+		Fee:      fee,
+		Exchange: FormatCoinbase,
+	}
+	if currency != "" && currency != "USD" {
+		trade.Price, trade.Fee = 0, 0
+	}
+
+	switch {
+	case kind == "buy" || kind == "advanced trade buy":
+		trade.Side = Buy
+	case kind == "sell" || kind == "advanced trade sell":
+		trade.Side = Sell
+	case strings.Contains(kind, "reward") || strings.Contains(kind, "income"):
+		trade.Side = Buy
+		trade.Fee = 0
+	case kind == "convert":
+		match := coinbaseConvert.FindStringSubmatch(r.get("notes"))
+		if match == nil {
+			return nil, fmt.Errorf("conversion without the assets converted in its notes")
+		}
+		received, err := parseNumber(match[3])
+		if err != nil || received == 0 {
+			return nil, fmt.Errorf("invalid quantity converted to %q", match[3])
+		}
+
+		// The value sold buys the asset converted to
+		trade.Side = Sell
+		bought := utils.Trade{
+			Time:     t,
+			Coin:     strings.ToUpper(match[4]),
+			Side:     Buy,
+			Quantity: received,
+			Price:    quantity * trade.Price / received,
+			Exchange: FormatCoinbase,
+		}
+		return []utils.Trade{trade, bought}, nil
+	default:
+		return nil, fmt.Errorf("%q is not a trade", r.get("transaction type"))
+	}
+
+	if quantity == 0 {
+		return nil, fmt.Errorf("%s of no %s", kind, asset)
+	}
+	return []utils.Trade{trade}, nil
+}
+
+// parseBinance reads a row of a Binance spot trade history
+func parseBinance(r row) ([]utils.Trade, error) {
+	t, err := parseTime(r.get("date(utc)", "date", "time"))
+	if err != nil {
+		return nil, err
+	}
+
+	side := strings.ToLower(r.get("side"))
+	if side != Buy && side != Sell {
+		return nil, fmt.Errorf("invalid side %q, expected buy or sell", r.get("side"))
+	}
+
+	price, err := parseNumber(r.get("price"))
+	if err != nil {
+		return nil, err
+	}
+	quantity, base, err := parseAmount(r.get("executed"))
+	if err != nil {
+		return nil, err
+	}
+	amount, quote, err := parseAmount(r.get("amount"))
+	if err != nil {
+		return nil, err
+	}
+	feeQuantity, feeAsset, err := parseAmount(r.get("fee"))
+	if err != nil {
+		return nil, err
+	}
+
+	// Older exports give amounts without their asset, so they are split
+	// from the pair
+	if base == "" || quote == "" {
+		pair := strings.ToUpper(strings.NewReplacer("/", "", "-", "", "_", "").Replace(r.get("pair")))
+		base, quote = "", ""
+		for _, q := range usdQuotes {
+			if strings.HasSuffix(pair, q) && len(pair) > len(q) {
+				base, quote = strings.TrimSuffix(pair, q), q
+				break
+			}
+		}
+		if base == "" {
+			return nil, fmt.Errorf("%s is not quoted in USD or a USD stablecoin", pair)
+		}
+	}
+
+	return pairTrades(t, base, quote, side, quantity, price, amount, feeQuantity, feeAsset, FormatBinance)
+}
+
+// pairTrades returns the trades of a fill of a pair, with amount the
+// quantity of the quote asset traded. Fees paid in the coin bought reduce
+// the quantity received, and fees paid in other assets than the pair's, Eg:
+// BNB, are left out. Trades of pairs not quoted in USD are also a trade of
+// the quote asset the other way, both left missing a price to be priced by
+// PriceTrades.
+func pairTrades(t time.Time, base, quote, side string, quantity, price, amount, feeQuantity float64, feeAsset, exchange string) ([]utils.Trade, error) {
+	if amount == 0 {
+		amount = quantity * price
+	}
+
+	usd := false
+	for _, q := range usdQuotes {
+		usd = usd || quote == q
+	}
+
+	fee := 0.0
+	switch feeAsset {
+	case quote:
+		fee = feeQuantity
+		if side == Buy {
+			amount += feeQuantity
+		} else {
+			amount -= feeQuantity
+		}
+	case base:
+		fee = feeQuantity * price
+		if side == Buy {
+			quantity -= feeQuantity
+		}
+	}
+
+	if quantity <= 0 {
+		return nil, fmt.Errorf("%s of no %s", side, base)
+	}
+	trade := utils.Trade{
+		Time:     t,
+		Coin:     base,
+		Side:     side,
+		Quantity: quantity,
+		Price:    price,
+		Fee:      fee,
+		Exchange: exchange,
+	}
+	if usd {
+		return []utils.Trade{trade}, nil
+	}
+
+	// Fees are in the quantity of the quote asset traded
+	trade.Price, trade.Fee = 0, 0
+	counter := utils.Trade{
+		Time:     t,
+		Coin:     quote,
+		Side:     Buy,
+		Quantity: amount,
+		Exchange: exchange,
+	}
+	if side == Buy {
+		counter.Side = Sell
+	}
+	return []utils.Trade{trade, counter}, nil
+}
+
+// parseGeneric reads a row of a generic export
+func parseGeneric(r row) ([]utils.Trade, error) {
+	t, err := parseTime(r.get("date"))
+	if err != nil {
+		return nil, err
+	}
+
+	side := strings.ToLower(r.get("type"))
+	if side != Buy && side != Sell {
+		return nil, fmt.Errorf("invalid type %q, expected buy or sell", r.get("type"))
+	}
+	coin := strings.ToUpper(r.get("coin"))
+	if coin == "" {
+		return nil, fmt.Errorf("%s of no coin", side)
+	}
+
+	quantity, err := parseNumber(r.get("quantity"))
+	if err != nil || quantity == 0 {
+		return nil, fmt.Errorf("invalid quantity %q", r.get("quantity"))
+	}
+	price, err := parseNumber(r.get("price"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid price %q", r.get("price"))
+	}
+	fee, err := parseNumber(r.get("fee"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid fee %q", r.get("fee"))
+	}
+
+	return []utils.Trade{{
+		Time:     t,
+		Coin:     coin,
+		Side:     side,
+		Quantity: quantity,
+		Price:    price,
+		Fee:      fee,
+		Exchange: strings.ToLower(r.get("exchange")),
+	}}, nil
+}
+
+// tradeKey identifies a trade, so trades imported twice are kept once
+func tradeKey(t utils.Trade) string {
+	// Prices are left out, as missing prices are filled in once imported
+	return fmt.Sprintf("%d|%s|%s|%g|%s", t.Time.Unix(), t.Coin, t.Side, t.Quantity, t.Exchange)
+}
+
+// MergeTrades adds trades to those imported before, leaving out those
+// already imported, Eg: when importing an overlapping export. The merged
+// trades are returned in order of time, along with how many were added.
+func MergeTrades(imported, trades []utils.Trade) ([]utils.Trade, int) {
+	seen := map[string]bool{}
+	for _, t := range imported {
+		seen[tradeKey(t)] = true
+	}
+
+	merged := append([]utils.Trade{}, imported...)
+	added := 0
+	for _, t := range trades {
+		// Identical fills of one export are all kept
+		if !seen[tradeKey(t)] {
+			merged = append(merged, t)
+			added++
+		}
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Time.Before(merged[j].Time)
+	})
+
+	return merged, added
+}
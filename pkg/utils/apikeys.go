@@ -0,0 +1,265 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Stores API keys can be kept in
+const (
+	KeyStoreAuto    = "auto"    // The keyring if available, else the key file
+	KeyStoreKeyring = "keyring" // The OS keyring
+	KeyStoreFile    = "file"    // ~/.cryptgo-keys.json, readable only by the user
+)
+
+// APIKeyStore is the store API keys are kept in, set from the config file
+var APIKeyStore = KeyStoreAuto
+
+// encryptionKeyName is the name the key encrypting local secrets is stored
+// under in the keyring
+const encryptionKeyName = "cryptgo-encryption"
+
+// errKeyNotFound is returned by keyring lookups of keys which aren't stored
+var errKeyNotFound = errors.New("key not found")
+
+// APIKeyProviders are providers whose API keys can be stored
+var APIKeyProviders = []string{"coingecko", "cryptopanic", "telegram"}
+
+// checkProvider returns an error if API keys of provider can't be stored
+func checkProvider(provider string) error {
+	for _, name := range APIKeyProviders {
+		if name == provider {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown provider %q, expected one of: %s", provider, strings.Join(APIKeyProviders, ", "))
+}
+
+// useKeyring returns true if API keys are kept in the keyring
+func useKeyring() (bool, error) {
+	switch APIKeyStore {
+	case KeyStoreFile:
+		return false, nil
+	case KeyStoreKeyring:
+		if !keyringAvailable() {
+			return false, fmt.Errorf("no OS keyring available")
+		}
+		return true, nil
+	default:
+		return keyringAvailable(), nil
+	}
+}
+
+// keyFilePath returns the path of the key file, ~/.cryptgo-keys.json
+func keyFilePath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return homeDir + "/.cryptgo-keys.json", nil
+}
+
+// readKeyFile returns API keys in the key file, keyed by provider
+func readKeyFile() map[string]string {
+	keys, err := loadKeyFile()
+	if err != nil {
+		return map[string]string{}
+	}
+	return keys
+}
+
+// loadKeyFile returns API keys in the key file, keyed by provider. A missing
+// key file holds no keys, other failures to read it are returned.
+func loadKeyFile() (map[string]string, error) {
+	keys := map[string]string{}
+
+	path, err := keyFilePath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return keys, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("invalid key file %s: %v", path, err)
+	}
+
+	return keys, nil
+}
+
+// writeKeyFile writes API keys to the key file, readable only by the user
+func writeKeyFile(keys map[string]string) error {
+	path, err := keyFilePath()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(keys)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return err
+	}
+
+	// WriteFile keeps permissions of existing files
+	return os.Chmod(path, 0600)
+}
+
+// SetAPIKey stores the API key of a provider, returning the store it was
+// kept in. With the auto store, keys are kept in the key file if the
+// keyring fails.
+func SetAPIKey(provider, key string) (string, error) {
+	if err := checkProvider(provider); err != nil {
+		return "", err
+	}
+
+	return storeKey(provider, key)
+}
+
+// storeKey stores the key of a provider, returning the store it was kept in
+func storeKey(provider, key string) (string, error) {
+	keyring, err := useKeyring()
+	if err != nil {
+		return "", err
+	}
+
+	if keyring {
+		err := keyringSet(provider, key)
+		if err == nil {
+			// Drop a key kept before, so it isn't used in place of this one
+			keys := readKeyFile()
+			if _, ok := keys[provider]; ok {
+				delete(keys, provider)
+				writeKeyFile(keys)
+			}
+			return KeyStoreKeyring, nil
+		}
+		if APIKeyStore == KeyStoreKeyring {
+			return "", err
+		}
+	}
+
+	keys := readKeyFile()
+	keys[provider] = key
+	return KeyStoreFile, writeKeyFile(keys)
+}
+
+// GetAPIKey returns the API key of a provider and the store it was found
+// in, or empty strings if no key is stored
+func GetAPIKey(provider string) (string, string) {
+	if keyring, err := useKeyring(); err == nil && keyring {
+		if key, err := keyringGet(provider); err == nil && key != "" {
+			return key, KeyStoreKeyring
+		}
+	}
+
+	if APIKeyStore != KeyStoreKeyring {
+		if key := readKeyFile()[provider]; key != "" {
+			return key, KeyStoreFile
+		}
+	}
+
+	return "", ""
+}
+
+// DeleteAPIKey removes the API key of a provider from both stores
+func DeleteAPIKey(provider string) error {
+	if err := checkProvider(provider); err != nil {
+		return err
+	}
+
+	if keyringAvailable() {
+		keyringDelete(provider)
+	}
+
+	keys := readKeyFile()
+	if _, ok := keys[provider]; !ok {
+		return nil
+	}
+	delete(keys, provider)
+	return writeKeyFile(keys)
+}
+
+// EncryptionKey returns the 256 bit key local secrets are encrypted with, Eg:
+// exchange API keys, creating it on first use. The key is kept in the OS
+// keyring, whatever the API key store: in the key file it would sit in plain
+// text beside the data it encrypts. Without a keyring, or once a passphrase
+// was used, the key is derived from the passphrase in PassphraseEnv instead.
+// A new key is only created if the keyring reports none is stored, other
+// failures are returned, so secrets sealed with the stored key aren't
+// orphaned.
+func EncryptionKey() ([]byte, error) {
+	keys, err := loadKeyFile()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read encryption key: %v", err)
+	}
+	if _, ok := keys[encryptionSaltName]; ok || !keyringAvailable() {
+		return passphraseKey(keys)
+	}
+
+	stored, err := keyringGet(encryptionKeyName)
+	if err == nil {
+		return decodeEncryptionKey(stored)
+	}
+	if err != errKeyNotFound {
+		return nil, fmt.Errorf("failed to read encryption key: %v", err)
+	}
+
+	// Earlier versions could keep the key in the key file, it's moved to the
+	// keyring so secrets sealed with it can still be read
+	stored, moved := keys[encryptionKeyName]
+	if !moved {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+		stored = hex.EncodeToString(key)
+	}
+
+	if err := keyringSet(encryptionKeyName, stored); err != nil {
+		return nil, fmt.Errorf("failed to store encryption key: %v", err)
+	}
+	if moved {
+		delete(keys, encryptionKeyName)
+		if err := writeKeyFile(keys); err != nil {
+			return nil, err
+		}
+	}
+
+	return decodeEncryptionKey(stored)
+}
+
+// decodeEncryptionKey returns the encryption key stored hex encoded
+func decodeEncryptionKey(stored string) ([]byte, error) {
+	key, err := hex.DecodeString(stored)
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("invalid encryption key stored in the keyring")
+	}
+	return key, nil
+}
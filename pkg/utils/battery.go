@@ -0,0 +1,74 @@
+//go:build !windows
+// +build !windows
+
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// onBattery returns true if the system runs on battery power, read from
+// pmset on macOS and the power supply class of sysfs on Linux. Other
+// systems are treated as plugged in.
+func onBattery() bool {
+	switch runtime.GOOS {
+	case "darwin":
+		out, err := exec.Command("pmset", "-g", "batt").Output()
+		if err != nil {
+			return false
+		}
+		return strings.Contains(string(out), "'Battery Power'")
+
+	case "linux":
+		supplies, err := filepath.Glob("/sys/class/power_supply/*")
+		if err != nil {
+			return false
+		}
+
+		// read returns the contents of a file of a power supply
+		read := func(supply, name string) string {
+			data, err := os.ReadFile(filepath.Join(supply, name))
+			if err != nil {
+				return ""
+			}
+			return strings.TrimSpace(string(data))
+		}
+
+		battery := false
+		for _, supply := range supplies {
+			switch read(supply, "type") {
+			case "Mains", "USB":
+				if read(supply, "online") == "1" {
+					return false
+				}
+			case "Battery":
+				if read(supply, "status") == "Discharging" {
+					battery = true
+				}
+			}
+		}
+		return battery
+	}
+
+	return false
+}
@@ -0,0 +1,50 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Formats views can be exported in
+const (
+	ExportJSON = "json"
+	ExportCSV  = "csv"
+)
+
+// ExportFormats are formats views can be exported in
+var ExportFormats = []string{ExportJSON, ExportCSV}
+
+// ExportFormat is the format views are exported in, and ExportPNG renders
+// graphs of views exported to PNG as well
+var (
+	ExportFormat = ExportJSON
+	ExportPNG    = false
+)
+
+// ExportPath returns the path of a timestamped file in the home directory
+// to export a view to, Eg: ~/cryptgo-export-bitcoin-20210901-120000.json
+func ExportPath(name string, created time.Time, ext string) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(homeDir, "cryptgo-export-"+name+"-"+created.Format("20060102-150405")+"."+ext), nil
+}
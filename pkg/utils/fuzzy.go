@@ -0,0 +1,63 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// FuzzyScore scores how well query matches target, ignoring case. Characters
+// of query must appear in target in order, but not necessarily together.
+// Matches at the start of words and runs of consecutive characters score
+// higher, as do exact matches and shorter targets. ok is false if query
+// doesn't match.
+func FuzzyScore(query, target string) (score int, ok bool) {
+	q := []rune(strings.ToLower(query))
+	t := []rune(strings.ToLower(target))
+	if len(q) == 0 || len(q) > len(t) {
+		return 0, false
+	}
+
+	qi, prev := 0, -2
+	for ti := 0; ti < len(t) && qi < len(q); ti++ {
+		if t[ti] != q[qi] {
+			continue
+		}
+
+		score++
+		if ti == prev+1 {
+			// Consecutive characters
+			score += 5
+		}
+		if ti == 0 || !unicode.IsLetter(t[ti-1]) && !unicode.IsDigit(t[ti-1]) {
+			// Start of a word
+			score += 10
+		}
+		prev = ti
+		qi++
+	}
+	if qi < len(q) {
+		return 0, false
+	}
+
+	if len(q) == len(t) {
+		score += 100
+	}
+
+	return score - (len(t) - len(q)), true
+}
@@ -0,0 +1,51 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// IdleTimeout is how long pages wait without key presses before showing
+// the screensaver, set from the config file. The screensaver is off if 0.
+var IdleTimeout = time.Duration(15) * time.Minute
+
+// IdleFactor is how many times longer pollers wait between refreshes while
+// the screensaver is shown
+const IdleFactor = 6
+
+// idle holds whether the screensaver is shown
+var idle = struct {
+	sync.Mutex
+	on bool
+}{}
+
+// SetIdle sets whether the screensaver is shown, slowing down refreshes
+// while it is
+func SetIdle(on bool) {
+	idle.Lock()
+	idle.on = on
+	idle.Unlock()
+}
+
+// Idle returns true while the screensaver is shown
+func Idle() bool {
+	idle.Lock()
+	defer idle.Unlock()
+	return idle.on
+}
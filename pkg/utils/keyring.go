@@ -0,0 +1,154 @@
+//go:build !windows
+// +build !windows
+
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// keyringService is the service API keys are stored under in the keyring
+const keyringService = "cryptgo"
+
+// keyringProbe is the key looked up to check Secret Service is running
+const keyringProbe = "cryptgo-probe"
+
+var (
+	keyringOnce sync.Once
+	hasKeyring  bool
+)
+
+// keyringAvailable returns true if the OS keyring can be used, through
+// security on macOS or secret-tool (Secret Service) elsewhere. secret-tool
+// is often installed without Secret Service running, Eg: on headless
+// machines, so a lookup is tried once rather than only finding the tool.
+func keyringAvailable() bool {
+	keyringOnce.Do(func() {
+		tool := "secret-tool"
+		if runtime.GOOS == "darwin" {
+			tool = "security"
+		}
+		if _, err := exec.LookPath(tool); err != nil {
+			return
+		}
+		if runtime.GOOS == "darwin" {
+			hasKeyring = true
+			return
+		}
+
+		_, err := keyringGet(keyringProbe)
+		hasKeyring = err == nil || err == errKeyNotFound
+	})
+	return hasKeyring
+}
+
+// runKeyring runs a keyring tool with stdin, returning its output
+func runKeyring(stdin string, name string, args ...string) (string, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%s failed: %v %s", name, err, strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// securityQuote quotes an argument of a command run by security in
+// interactive mode
+func securityQuote(arg string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(arg) + `"`
+}
+
+// keyringSet stores the API key of a provider in the keyring. Keys are
+// passed on stdin, as arguments can be read by any local user, Eg: with ps.
+func keyringSet(provider, key string) error {
+	if runtime.GOOS == "darwin" {
+		// security reads the command from stdin in interactive mode. It
+		// reports failures on stderr rather than by its exit status.
+		command := fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n",
+			securityQuote(keyringService), securityQuote(provider), securityQuote(key))
+
+		cmd := exec.Command("security", "-i")
+		cmd.Stdin = strings.NewReader(command)
+
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("security failed: %v %s", err, strings.TrimSpace(stderr.String()))
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("security failed: %s", msg)
+		}
+		return nil
+	}
+
+	_, err := runKeyring(key, "secret-tool", "store", "--label", keyringService+" "+provider,
+		"service", keyringService, "provider", provider)
+	return err
+}
+
+// keyringGet returns the API key of a provider from the keyring, or
+// errKeyNotFound if the tool reports it isn't stored: security exits with 44
+// (errSecItemNotFound), secret-tool with 1 and nothing on stderr
+func keyringGet(provider string) (string, error) {
+	name, args := "secret-tool", []string{"lookup", "service", keyringService, "provider", provider}
+	notFound := 1
+	if runtime.GOOS == "darwin" {
+		name, args = "security", []string{"find-generic-password", "-s", keyringService, "-a", provider, "-w"}
+		notFound = 44
+	}
+
+	cmd := exec.Command(name, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == notFound &&
+		(runtime.GOOS == "darwin" || stderr.Len() == 0) {
+		return "", errKeyNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s failed: %v %s", name, err, strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// keyringDelete removes the API key of a provider from the keyring
+func keyringDelete(provider string) error {
+	if runtime.GOOS == "darwin" {
+		_, err := runKeyring("", "security", "delete-generic-password", "-s", keyringService, "-a", provider)
+		return err
+	}
+
+	_, err := runKeyring("", "secret-tool", "clear", "service", keyringService, "provider", provider)
+	return err
+}
@@ -0,0 +1,140 @@
+//go:build windows
+// +build windows
+
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// keyringService prefixes targets API keys are stored under in the
+// Credential Manager
+const keyringService = "cryptgo"
+
+// credGeneric and credPersistLocalMachine are CRED_TYPE_GENERIC and
+// CRED_PERSIST_LOCAL_MACHINE of wincred.h
+const (
+	credGeneric             = 1
+	credPersistLocalMachine = 2
+)
+
+var (
+	advapi32    = syscall.NewLazyDLL("advapi32.dll")
+	credWrite   = advapi32.NewProc("CredWriteW")
+	credRead    = advapi32.NewProc("CredReadW")
+	credDelete  = advapi32.NewProc("CredDeleteW")
+	credFree    = advapi32.NewProc("CredFree")
+	errNotFound = syscall.Errno(1168) // ERROR_NOT_FOUND
+)
+
+// credential is CREDENTIALW of wincred.h
+type credential struct {
+	Flags              uint32
+	Type               uint32
+	TargetName         *uint16
+	Comment            *uint16
+	LastWritten        syscall.Filetime
+	CredentialBlobSize uint32
+	CredentialBlob     *byte
+	Persist            uint32
+	AttributeCount     uint32
+	Attributes         uintptr
+	TargetAlias        *uint16
+	UserName           *uint16
+}
+
+// keyringAvailable returns true if the Credential Manager can be used
+func keyringAvailable() bool {
+	return credRead.Find() == nil
+}
+
+// keyringTarget returns the target the API key of a provider is stored
+// under, Eg: cryptgo:coingecko
+func keyringTarget(provider string) (*uint16, error) {
+	return syscall.UTF16PtrFromString(keyringService + ":" + provider)
+}
+
+// keyringSet stores the API key of a provider in the Credential Manager
+func keyringSet(provider, key string) error {
+	target, err := keyringTarget(provider)
+	if err != nil {
+		return err
+	}
+	user, err := syscall.UTF16PtrFromString(provider)
+	if err != nil {
+		return err
+	}
+
+	blob := []byte(key)
+	cred := credential{
+		Type:               credGeneric,
+		TargetName:         target,
+		UserName:           user,
+		CredentialBlobSize: uint32(len(blob)),
+		Persist:            credPersistLocalMachine,
+	}
+	if len(blob) > 0 {
+		cred.CredentialBlob = &blob[0]
+	}
+
+	if ok, _, err := credWrite.Call(uintptr(unsafe.Pointer(&cred)), 0); ok == 0 {
+		return fmt.Errorf("CredWrite failed: %v", err)
+	}
+	return nil
+}
+
+// keyringGet returns the API key of a provider from the Credential Manager,
+// or errKeyNotFound if it isn't stored
+func keyringGet(provider string) (string, error) {
+	target, err := keyringTarget(provider)
+	if err != nil {
+		return "", err
+	}
+
+	var cred *credential
+	if ok, _, err := credRead.Call(uintptr(unsafe.Pointer(target)), credGeneric, 0, uintptr(unsafe.Pointer(&cred))); ok == 0 {
+		if err == errNotFound {
+			return "", errKeyNotFound
+		}
+		return "", fmt.Errorf("CredRead failed: %v", err)
+	}
+	defer credFree.Call(uintptr(unsafe.Pointer(cred)))
+
+	if cred.CredentialBlobSize == 0 {
+		return "", nil
+	}
+	size := cred.CredentialBlobSize
+	return string((*[1 << 20]byte)(unsafe.Pointer(cred.CredentialBlob))[:size:size]), nil
+}
+
+// keyringDelete removes the API key of a provider from the Credential
+// Manager
+func keyringDelete(provider string) error {
+	target, err := keyringTarget(provider)
+	if err != nil {
+		return err
+	}
+
+	if ok, _, err := credDelete.Call(uintptr(unsafe.Pointer(target)), credGeneric, 0); ok == 0 && err != errNotFound {
+		return fmt.Errorf("CredDelete failed: %v", err)
+	}
+	return nil
+}
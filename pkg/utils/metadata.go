@@ -18,13 +18,18 @@ package utils
 
 import (
 	"encoding/json"
-	"os"
+	"errors"
+	"sync"
 )
 
 type Metadata struct {
 	Favourites map[string]bool    `json:"favourites"`
 	Currency   string             `json:"currency"`
 	Portfolio  map[string]float64 `json:"portfolio"`
+	Priorities map[string]string  `json:"priorities"`
+	RankAlerts map[string]int     `json:"rankAlerts"`
+	Sources    map[string]string  `json:"sources"`
+	Intervals  map[string]string  `json:"intervals"`
 }
 
 type Currency struct {
@@ -44,33 +49,62 @@ type AllCurrencyData struct {
 	Timestamp uint       `json:"timestamp"`
 }
 
-// GetFavourites reads stored favourite coin details from
-// ~/.cryptgo-data.json and returns a map.
-func GetFavourites() map[string]bool {
-	metadata := Metadata{}
+// DefaultCurrency is the currency used until one is set in the config file
+// or saved
+const DefaultCurrency = "united-states-dollar"
+
+// Defaults used until favourites and currency are first saved, set from the
+// config file. They are guarded by defaultsMutex as they are set again when
+// the config file is reloaded.
+var (
+	favouriteDefaults = map[string]bool{}
+	currencyDefault   = DefaultCurrency
+	defaultsMutex     sync.RWMutex
+)
 
-	// Get home directory
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return map[string]bool{}
+// SetDefaults sets the favourites and currency used until they are first
+// saved
+func SetDefaults(favourites []string, currency string) {
+	defaultsMutex.Lock()
+	defer defaultsMutex.Unlock()
+
+	favouriteDefaults = map[string]bool{}
+	for _, id := range favourites {
+		favouriteDefaults[id] = true
 	}
+	currencyDefault = currency
+}
 
-	// Check if metadata file exists
-	configPath := homeDir + "/.cryptgo-data.json"
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		return map[string]bool{}
+// defaultFavourites returns a copy of the default favourites
+func defaultFavourites() map[string]bool {
+	defaultsMutex.RLock()
+	defer defaultsMutex.RUnlock()
+
+	favourites := map[string]bool{}
+	for id, ok := range favouriteDefaults {
+		favourites[id] = ok
 	}
+	return favourites
+}
 
-	// Open file
-	configFile, err := os.Open(configPath)
+// defaultCurrency returns the default currency
+func defaultCurrency() string {
+	defaultsMutex.RLock()
+	defer defaultsMutex.RUnlock()
+	return currencyDefault
+}
+
+// GetFavourites reads stored favourite coin details from the local store
+// and returns a map. The default favourites are returned if favourites were
+// never saved.
+func GetFavourites() map[string]bool {
+	metadata, err := readMetadata()
 	if err != nil {
 		return map[string]bool{}
 	}
 
-	// Read content
-	err = json.NewDecoder(configFile).Decode(&metadata)
-	if err != nil {
-		return map[string]bool{}
+	if metadata.Favourites == nil {
+		return defaultFavourites()
 	}
 
 	if len(metadata.Favourites) > 0 {
@@ -80,110 +114,188 @@ func GetFavourites() map[string]bool {
 	return map[string]bool{}
 }
 
-// GetPortfolio reads stored portfolio details from
-// ~/.cryptgo-data.json and returns a map.
+// GetPortfolio reads stored portfolio details from the local store and
+// returns a map.
 func GetPortfolio() map[string]float64 {
-	metadata := Metadata{}
-
-	// Get home directory
-	homeDir, err := os.UserHomeDir()
+	metadata, err := readMetadata()
 	if err != nil {
 		return map[string]float64{}
 	}
 
-	// Check if metadta file exists
-	configPath := homeDir + "/.cryptgo-data.json"
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		return map[string]float64{}
+	if len(metadata.Portfolio) > 0 {
+		return metadata.Portfolio
 	}
 
-	// Open file
-	configFile, err := os.Open(configPath)
-	if err != nil {
-		return map[string]float64{}
+	return map[string]float64{}
+}
+
+// GetCurrency reads the stored currency ID from the local store.
+// The default currency is returned if a currency was never saved.
+func GetCurrency() string {
+	metadata, err := readMetadata()
+	if err != nil || metadata.Currency == "" {
+		return defaultCurrency()
 	}
 
-	// Read content
-	err = json.NewDecoder(configFile).Decode(&metadata)
+	return metadata.Currency
+}
+
+// GetPriorities reads stored refresh priorities of coins from
+// the local store and returns a map.
+func GetPriorities() map[string]string {
+	metadata, err := readMetadata()
 	if err != nil {
-		return map[string]float64{}
+		return map[string]string{}
 	}
 
-	if len(metadata.Portfolio) > 0 {
-		return metadata.Portfolio
+	if len(metadata.Priorities) > 0 {
+		return metadata.Priorities
 	}
 
-	return map[string]float64{}
+	return map[string]string{}
 }
 
-func GetCurrency() string {
-	metadata := Metadata{}
-
-	// Get home directory
-	homeDir, err := os.UserHomeDir()
+// GetRankAlerts reads stored rank alerts (coin ID to top N threshold) from
+// the local store and returns a map.
+func GetRankAlerts() map[string]int {
+	metadata, err := readMetadata()
 	if err != nil {
-		return "united-states-dollar"
+		return map[string]int{}
 	}
 
-	// Check if metadta file exists
-	configPath := homeDir + "/.cryptgo-data.json"
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		return "united-states-dollar"
+	if len(metadata.RankAlerts) > 0 {
+		return metadata.RankAlerts
 	}
 
-	// Open file
-	configFile, err := os.Open(configPath)
+	return map[string]int{}
+}
+
+// GetCoinSources reads stored data sources of coins (coin ID to source
+// name) from the local store and returns a map.
+func GetCoinSources() map[string]string {
+	metadata, err := readMetadata()
 	if err != nil {
-		return "united-states-dollar"
+		return map[string]string{}
 	}
 
-	// Read content
-	err = json.NewDecoder(configFile).Decode(&metadata)
+	if len(metadata.Sources) > 0 {
+		return metadata.Sources
+	}
+
+	return map[string]string{}
+}
+
+// GetCoinIntervals reads stored graph intervals of coins (coin ID to
+// interval) from the local store and returns a map.
+func GetCoinIntervals() map[string]string {
+	metadata, err := readMetadata()
 	if err != nil {
-		return "united-states-dollar"
+		return map[string]string{}
 	}
 
-	return metadata.Currency
+	if len(metadata.Intervals) > 0 {
+		return metadata.Intervals
+	}
+
+	return map[string]string{}
 }
 
 // SaveMetadata exports favourites, currency and portfolio to disk.
-// Data is saved on ~/.cryptgo-data.json
+// Data is saved in the local store, and favourites and currency are written
+// to the config file as well
 func SaveMetadata(favourites map[string]bool, currency string, portfolio map[string]float64) error {
-	// Get Home directory
-	homeDir, err := os.UserHomeDir()
+	// Retain fields which are not managed here
+	metadata, _ := readMetadata()
+
+	metadata.Favourites = favourites
+	metadata.Currency = currency
+	metadata.Portfolio = portfolio
+
+	if err := writeMetadata(metadata); err != nil {
+		return err
+	}
+	return SaveConfig(favourites, currency)
+}
+
+// SavePriorities exports refresh priorities of coins to disk.
+// Data is saved in the local store
+func SavePriorities(priorities map[string]string) error {
+	metadata, err := readMetadata()
 	if err != nil {
 		return err
 	}
 
-	// configPath and hidden path are used explicitly because we
-	// get a permission denied error on trying to write/create
-	// to a hidden file
-	configPath := homeDir + "/cryptgo-data.json"
-	hiddenPath := homeDir + "/.cryptgo-data.json"
+	metadata.Priorities = priorities
+
+	return writeMetadata(metadata)
+}
 
-	// Create data
-	metadata := Metadata{
-		Favourites: favourites,
-		Currency:   currency,
-		Portfolio:  portfolio,
+// SaveRankAlerts exports rank alerts of coins to disk.
+// Data is saved in the local store
+func SaveRankAlerts(rankAlerts map[string]int) error {
+	metadata, err := readMetadata()
+	if err != nil {
+		return err
 	}
 
-	data, err := json.MarshalIndent(metadata, "", "\t")
+	metadata.RankAlerts = rankAlerts
+
+	return writeMetadata(metadata)
+}
+
+// SaveCoinSources exports data sources of coins to disk.
+// Data is saved in the local store
+func SaveCoinSources(coinSources map[string]string) error {
+	metadata, err := readMetadata()
 	if err != nil {
 		return err
 	}
 
-	// Write to file
-	err = os.WriteFile(configPath, data, 0666)
+	metadata.Sources = coinSources
+
+	return writeMetadata(metadata)
+}
+
+// SaveCoinIntervals exports graph intervals of coins to disk.
+// Data is saved in the local store
+func SaveCoinIntervals(intervals map[string]string) error {
+	metadata, err := readMetadata()
 	if err != nil {
 		return err
 	}
 
-	// Hide file
-	err = os.Rename(configPath, hiddenPath)
+	metadata.Intervals = intervals
+
+	return writeMetadata(metadata)
+}
+
+// metadataRecord is the name metadata is stored under, kept in
+// ~/.cryptgo-data.json by the file store
+const metadataRecord = "data"
+
+// readMetadata reads all stored metadata from the local store. An empty
+// Metadata is returned if it was never saved.
+func readMetadata() (Metadata, error) {
+	metadata := Metadata{}
+
+	data, err := getRecord(metadataRecord)
+	if errors.Is(err, ErrNotStored) {
+		return metadata, nil
+	}
+	if err != nil {
+		return metadata, err
+	}
+
+	err = json.Unmarshal(data, &metadata)
+	return metadata, err
+}
+
+// writeMetadata writes the given metadata to the local store
+func writeMetadata(metadata Metadata) error {
+	data, err := json.MarshalIndent(metadata, "", "\t")
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return putRecord(metadataRecord, data)
 }
@@ -48,3 +48,26 @@ func MaxFloat64(a ...float64) float64 {
 	}
 	return max
 }
+
+// HistoryPoints caps the number of history points drawn in graphs. Zero
+// draws as many points as the graph is wide.
+var HistoryPoints = 0
+
+// SampleIndices returns up to n evenly spaced indices into a slice of the
+// given length, always keeping the first and last index
+func SampleIndices(length, n int) []int {
+	if n <= 0 || length <= n {
+		n = length
+	}
+
+	indices := make([]int, 0, n)
+	if n == 1 {
+		return append(indices, length-1)
+	}
+
+	for i := 0; i < n; i++ {
+		indices = append(indices, i*(length-1)/(n-1))
+	}
+
+	return indices
+}
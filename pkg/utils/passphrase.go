@@ -0,0 +1,135 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"os"
+	"sync"
+)
+
+// PassphraseEnv is the environment variable holding the passphrase local
+// secrets are encrypted with when no OS keyring is available
+const PassphraseEnv = "CRYPTGO_PASSPHRASE"
+
+// Names the salt and check value of passphrase derived keys are kept under
+// in the key file. Neither reveals the key.
+const (
+	encryptionSaltName  = "cryptgo-encryption-salt"
+	encryptionCheckName = "cryptgo-encryption-check"
+)
+
+// passphraseIterations is the PBKDF2 iteration count keys are derived with
+const passphraseIterations = 200000
+
+// derivedKey caches the passphrase derived key, as deriving it is slow
+var derivedKey struct {
+	sync.Mutex
+	key []byte
+}
+
+// passphraseKey returns the encryption key derived from the passphrase in
+// PassphraseEnv, with the salt in keys, the key file. A salt and check value
+// are written on first use, the check value is compared on later ones so a
+// wrong passphrase is reported rather than failing to decrypt secrets.
+func passphraseKey(keys map[string]string) ([]byte, error) {
+	derivedKey.Lock()
+	defer derivedKey.Unlock()
+
+	if derivedKey.key != nil {
+		return derivedKey.key, nil
+	}
+
+	passphrase := os.Getenv(PassphraseEnv)
+	if passphrase == "" {
+		return nil, fmt.Errorf("no OS keyring available, set %s to a passphrase to encrypt exchange keys with", PassphraseEnv)
+	}
+
+	storedSalt, ok := keys[encryptionSaltName]
+	salt, err := hex.DecodeString(storedSalt)
+	if !ok {
+		salt = make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, err
+		}
+	} else if err != nil || len(salt) != 16 {
+		return nil, fmt.Errorf("invalid encryption salt stored in the key file")
+	}
+
+	key := pbkdf2(sha256.New, []byte(passphrase), salt, passphraseIterations, 32)
+	check := hex.EncodeToString(passphraseCheck(key))
+
+	if ok {
+		if !hmac.Equal([]byte(check), []byte(keys[encryptionCheckName])) {
+			return nil, fmt.Errorf("wrong passphrase in %s", PassphraseEnv)
+		}
+	} else {
+		keys[encryptionSaltName] = hex.EncodeToString(salt)
+		keys[encryptionCheckName] = check
+		if err := writeKeyFile(keys); err != nil {
+			return nil, err
+		}
+	}
+
+	derivedKey.key = key
+	return key, nil
+}
+
+// passphraseCheck returns the value a derived key is checked against
+func passphraseCheck(key []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("cryptgo passphrase check"))
+	return mac.Sum(nil)
+}
+
+// pbkdf2 derives a key of keyLen bytes from password and salt as in
+// RFC 8018, with HMAC of hash as the pseudorandom function
+func pbkdf2(hash func() hash.Hash, password, salt []byte, iterations, keyLen int) []byte {
+	prf := hmac.New(hash, password)
+	size := prf.Size()
+	blocks := (keyLen + size - 1) / size
+
+	key := make([]byte, 0, blocks*size)
+	index := make([]byte, 4)
+	for block := 1; block <= blocks; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		binary.BigEndian.PutUint32(index, uint32(block))
+		prf.Write(index)
+		u := prf.Sum(nil)
+
+		t := make([]byte, size)
+		copy(t, u)
+		for n := 2; n <= iterations; n++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for i := range t {
+				t[i] ^= u[i]
+			}
+		}
+		key = append(key, t...)
+	}
+
+	return key[:keyLen]
+}
@@ -0,0 +1,126 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+)
+
+// Colours of charts rendered to PNG
+var (
+	plotBackground = color.RGBA{255, 255, 255, 255}
+	plotGrid       = color.RGBA{225, 225, 225, 255}
+	plotUp         = color.RGBA{22, 163, 74, 255}
+	plotDown       = color.RGBA{220, 38, 38, 255}
+)
+
+// plotMargin is the number of pixels left blank around charts
+const plotMargin = 20
+
+// PlotPNG renders values as a line chart over horizontal grid lines, and
+// writes it to w as a PNG of width by height pixels. The line is green if
+// the last value is at least the first, else red.
+func PlotPNG(w io.Writer, values []float64, width, height int) error {
+	if len(values) < 2 {
+		return fmt.Errorf("at least 2 values are needed to plot a chart")
+	}
+	if width <= 2*plotMargin || height <= 2*plotMargin {
+		return fmt.Errorf("chart of %dx%d pixels is too small", width, height)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{plotBackground}, image.Point{}, draw.Src)
+
+	plotWidth := width - 2*plotMargin - 1
+	plotHeight := height - 2*plotMargin - 1
+
+	// Grid lines at quarters of the range of values
+	for i := 0; i <= 4; i++ {
+		y := plotMargin + i*plotHeight/4
+		for x := plotMargin; x <= plotMargin+plotWidth; x++ {
+			img.Set(x, y, plotGrid)
+		}
+	}
+
+	min := MinFloat64(values...)
+	max := MaxFloat64(values...)
+	pointAt := func(i int) (int, int) {
+		x := plotMargin + i*plotWidth/(len(values)-1)
+		y := plotMargin + plotHeight/2
+		if max > min {
+			y = plotMargin + plotHeight - int((values[i]-min)/(max-min)*float64(plotHeight))
+		}
+		return x, y
+	}
+
+	line := plotUp
+	if values[len(values)-1] < values[0] {
+		line = plotDown
+	}
+
+	x0, y0 := pointAt(0)
+	for i := 1; i < len(values); i++ {
+		x1, y1 := pointAt(i)
+		drawLine(img, x0, y0, x1, y1, line)
+
+		// Thicken the line, so it is seen when scaled down
+		drawLine(img, x0, y0+1, x1, y1+1, line)
+		x0, y0 = x1, y1
+	}
+
+	return png.Encode(w, img)
+}
+
+// drawLine draws a line between two points with Bresenham's algorithm
+func drawLine(img *image.RGBA, x0, y0, x1, y1 int, c color.Color) {
+	dx, dy := x1-x0, y1-y0
+	if dx < 0 {
+		dx = -dx
+	}
+	if dy < 0 {
+		dy = -dy
+	}
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+
+	err := dx - dy
+	for {
+		img.Set(x0, y0, c)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * err
+		if e2 > -dy {
+			err -= dy
+			x0 += sx
+		}
+		if e2 < dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
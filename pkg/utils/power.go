@@ -0,0 +1,163 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Power modes, low power is entered on battery power in auto mode, or
+// always or never when forced on or off
+const (
+	PowerAuto = "auto"
+	PowerOn   = "on"
+	PowerOff  = "off"
+)
+
+// PowerModes lists power modes in the order they are cycled through
+var PowerModes = []string{PowerAuto, PowerOn, PowerOff}
+
+// LowPowerFactor is how many times longer pollers wait between refreshes in
+// low power mode, and LowPowerFPS caps redraws per second. Both are set
+// from the config file.
+var (
+	LowPowerFactor = 4
+	LowPowerFPS    = 2
+)
+
+// batteryCheckInterval is how often the power source is checked in auto mode
+const batteryCheckInterval = time.Duration(30) * time.Second
+
+// power holds the power mode and the last check of the power source
+var power = struct {
+	sync.Mutex
+	mode      string
+	onBattery bool
+	checked   time.Time
+}{mode: PowerAuto}
+
+// SetPowerMode sets the power mode, one of auto, on or off
+func SetPowerMode(mode string) error {
+	mode = strings.ToLower(mode)
+	for _, m := range PowerModes {
+		if m == mode {
+			power.Lock()
+			power.mode = mode
+			power.Unlock()
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown power mode %q, expected one of %s", mode, strings.Join(PowerModes, ", "))
+}
+
+// GetPowerMode returns the power mode
+func GetPowerMode() string {
+	power.Lock()
+	defer power.Unlock()
+	return power.mode
+}
+
+// NextPowerMode returns the power mode following mode, cycling
+// auto -> on -> off -> auto
+func NextPowerMode(mode string) string {
+	for i, m := range PowerModes {
+		if m == mode {
+			return PowerModes[(i+1)%len(PowerModes)]
+		}
+	}
+	return PowerAuto
+}
+
+// LowPower returns true if refreshes should be slowed down to save power,
+// Eg: when running on battery in auto mode. Systems whose power source
+// can't be read are treated as plugged in.
+func LowPower() bool {
+	power.Lock()
+	defer power.Unlock()
+
+	switch power.mode {
+	case PowerOn:
+		return true
+	case PowerOff:
+		return false
+	}
+
+	if time.Since(power.checked) > batteryCheckInterval {
+		power.onBattery = onBattery()
+		power.checked = time.Now()
+	}
+	return power.onBattery
+}
+
+// PowerStatus returns a short indicator of low power mode for titles, Eg:
+// "low power (battery)", or an empty string outside of it
+func PowerStatus() string {
+	if !LowPower() {
+		return ""
+	}
+	if GetPowerMode() == PowerOn {
+		return "low power"
+	}
+	return "low power (battery)"
+}
+
+// stretch is how many times longer pollers wait between refreshes to stay
+// within the quotas of providers, 1 unless stretched
+var stretch = struct {
+	sync.Mutex
+	factor float64
+}{factor: 1}
+
+// SetStretch stretches intervals between refreshes by factor, at least 1
+func SetStretch(factor float64) {
+	if factor < 1 {
+		factor = 1
+	}
+	stretch.Lock()
+	stretch.factor = factor
+	stretch.Unlock()
+}
+
+// Stretch returns how many times intervals between refreshes are stretched
+func Stretch() float64 {
+	stretch.Lock()
+	defer stretch.Unlock()
+	return stretch.factor
+}
+
+// PollInterval returns how long a poller refreshing every t waits between
+// refreshes, lengthened in low power mode, while the screensaver is shown
+// and while stretched
+func PollInterval(t time.Duration) time.Duration {
+	RLockSettings()
+	lowPowerFactor := LowPowerFactor
+	RUnlockSettings()
+
+	if LowPower() && lowPowerFactor > 1 {
+		t *= time.Duration(lowPowerFactor)
+	}
+	if Idle() {
+		t *= IdleFactor
+	}
+	if factor := Stretch(); factor > 1 {
+		t = time.Duration(float64(t) * factor)
+	}
+	return t
+}
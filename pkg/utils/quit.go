@@ -0,0 +1,156 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"os"
+	"os/signal"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	ui "github.com/gizak/termui/v3"
+	"github.com/nsf/termbox-go"
+)
+
+// ConfirmQuit makes pages ask for the quit key to be pressed again before
+// closing, so a stray q doesn't close cryptgo
+var ConfirmQuit = false
+
+// QuitWindow is how long the quit key can be pressed again for to confirm
+const QuitWindow = time.Duration(3) * time.Second
+
+// QuitPrompt tracks the quit key pressed on a page
+type QuitPrompt struct {
+	asked time.Time
+}
+
+// Confirm returns true if the page should close. With ConfirmQuit set, the
+// first press returns false, so the page can ask for the key to be pressed
+// again within QuitWindow.
+func (q *QuitPrompt) Confirm() bool {
+	if !ConfirmQuit {
+		return true
+	}
+
+	now := time.Now()
+	if now.Sub(q.asked) < QuitWindow {
+		return true
+	}
+	q.asked = now
+
+	return false
+}
+
+var (
+	exitMutex sync.Mutex
+	exitHooks = map[int]func(){}
+	nextHook  int
+
+	// writeMutex is held while records are written, so that exiting on a
+	// signal never cuts a write short
+	writeMutex sync.Mutex
+)
+
+// OnExit runs f when cryptgo is terminated by a signal, Eg: to save state
+// which is otherwise saved once a page is closed. Hooks run in the reverse
+// order of being added. The returned function removes the hook.
+func OnExit(f func()) func() {
+	exitMutex.Lock()
+	defer exitMutex.Unlock()
+
+	id := nextHook
+	nextHook++
+	exitHooks[id] = f
+
+	return func() {
+		exitMutex.Lock()
+		defer exitMutex.Unlock()
+		delete(exitHooks, id)
+	}
+}
+
+// HandleQuitSignals exits on SIGTERM or SIGINT once state is saved, so
+// killing cryptgo or closing it from outside the UI never loses edits.
+// Hooks added with OnExit are run, writes in progress are waited for and
+// the terminal is restored before exiting.
+func HandleQuitSignals() {
+	quitChan := make(chan os.Signal, 1)
+	signal.Notify(quitChan, syscall.SIGTERM, syscall.SIGINT)
+
+	go func() {
+		sig := <-quitChan
+
+		exitMutex.Lock()
+		ids := []int{}
+		for id := range exitHooks {
+			ids = append(ids, id)
+		}
+		sort.Sort(sort.Reverse(sort.IntSlice(ids)))
+		for _, id := range ids {
+			exitHooks[id]()
+		}
+
+		// Writes are left locked, as no more are made before exiting
+		writeMutex.Lock()
+
+		if termbox.IsInit {
+			ui.Close()
+		}
+
+		code := 1
+		if s, ok := sig.(syscall.Signal); ok {
+			code = 128 + int(s)
+		}
+		os.Exit(code)
+	}()
+}
+
+// WriteFile writes data to a visible file and renames it to the hidden
+// path, like records of the file store. Writes to files outside the store,
+// Eg: of alerts, should use it so they are waited for before exiting.
+func WriteFile(visiblePath, hiddenPath string, data []byte) error {
+	writeMutex.Lock()
+	defer writeMutex.Unlock()
+
+	return writeHidden(visiblePath, hiddenPath, data)
+}
+
+// writeHidden writes data to a visible file and renames it to the hidden
+// path. The data is synced to disk before renaming, so the hidden file is
+// either left as it was or fully replaced, even if cryptgo is killed.
+func writeHidden(visiblePath, hiddenPath string, data []byte) error {
+	file, err := os.OpenFile(visiblePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	// Hide file
+	return os.Rename(visiblePath, hiddenPath)
+}
@@ -0,0 +1,98 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// ReloadConfig re-reads and applies the config file. It is set by the
+// command being run, as config is read there. Nothing is applied if the
+// file can't be read or holds an invalid setting.
+var ReloadConfig = func() error {
+	return nil
+}
+
+// reloadErr holds the error of the last reload, nil if it succeeded
+var reloadErr = struct {
+	sync.Mutex
+	err error
+}{}
+
+// Reload reloads config with ReloadConfig, keeping its error to be shown
+// by pages until a reload succeeds
+func Reload() error {
+	err := ReloadConfig()
+	reloadErr.Lock()
+	reloadErr.err = err
+	reloadErr.Unlock()
+	return err
+}
+
+// ReloadError returns the error of the last reload, nil if it succeeded
+func ReloadError() error {
+	reloadErr.Lock()
+	defer reloadErr.Unlock()
+	return reloadErr.err
+}
+
+// settingsMutex guards settings applied from the config file which are read
+// by pollers, so a reload is applied all at once
+var settingsMutex sync.RWMutex
+
+// ApplySettings runs apply holding the settings lock, so settings read
+// under RLockSettings are seen as before or after apply, never in between
+func ApplySettings(apply func()) {
+	settingsMutex.Lock()
+	defer settingsMutex.Unlock()
+	apply()
+}
+
+// RLockSettings locks settings applied from the config file for reading
+func RLockSettings() {
+	settingsMutex.RLock()
+}
+
+// RUnlockSettings undoes RLockSettings
+func RUnlockSettings() {
+	settingsMutex.RUnlock()
+}
+
+// SaveConfig writes favourites and the currency changed in the UI to the
+// config file. It is set by the command being run, as config is read there.
+var SaveConfig = func(favourites map[string]bool, currency string) error {
+	return nil
+}
+
+var (
+	reloadChan chan os.Signal
+	reloadOnce sync.Once
+)
+
+// ReloadSignals returns a channel receiving SIGHUP, on which pages reload
+// config and metadata. The same channel is shared by all pages, so only the
+// page being viewed handles the signal. It never fires on Windows.
+func ReloadSignals() chan os.Signal {
+	reloadOnce.Do(func() {
+		reloadChan = make(chan os.Signal, 1)
+		signal.Notify(reloadChan, syscall.SIGHUP)
+	})
+	return reloadChan
+}
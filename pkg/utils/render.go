@@ -0,0 +1,48 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import "time"
+
+// MaxFPS caps how many times per second frequently updated widgets (Eg:
+// live prices) are redrawn. Updates received in between redraws are
+// coalesced, so bursts of messages don't saturate slow connections.
+var MaxFPS = 10
+
+// UIRefresh is how often pages are refreshed, Eg: to redraw titles and
+// statuses, set from the config file
+var UIRefresh = time.Duration(1) * time.Second
+
+// MinUIRefresh is the shortest UIRefresh allowed
+const MinUIRefresh = time.Duration(250) * time.Millisecond
+
+// RenderInterval returns the minimum interval between redraws as set by
+// MaxFPS, or LowPowerFPS if lower in low power mode. A non positive MaxFPS
+// disables throttling.
+func RenderInterval() time.Duration {
+	RLockSettings()
+	fps, lowPowerFPS := MaxFPS, LowPowerFPS
+	RUnlockSettings()
+
+	if LowPower() && lowPowerFPS > 0 && (fps <= 0 || fps > lowPowerFPS) {
+		fps = lowPowerFPS
+	}
+	if fps <= 0 {
+		return time.Millisecond
+	}
+	return time.Second / time.Duration(fps)
+}
@@ -32,7 +32,10 @@ func roundOffNearestTen(num float64, divisor float64) float64 {
 }
 
 // Round values rounds off a pair of given floats to Thousands (K),
-// Millions (M) or Billions (B).
+// Millions (M), Billions (B) or Trillions (T). The unit is picked by the
+// larger of the two values and both are rounded to one decimal place in that
+// unit. Values below a thousand and values of a quadrillion or more are
+// returned as is with no unit. Units can be parsed back with ParseValue.
 func RoundValues(num1, num2 float64) ([]float64, string) {
 	nums := []float64{}
 	var units string
@@ -64,7 +67,7 @@ func RoundValues(num1, num2 float64) ([]float64, string) {
 		nums = append(nums, roundOffNearestTen(num2, G))
 		units = "B"
 
-	case n >= Q:
+	case n < Q:
 		nums = append(nums, roundOffNearestTen(num1, T))
 		nums = append(nums, roundOffNearestTen(num2, T))
 		units = "T"
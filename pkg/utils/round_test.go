@@ -0,0 +1,72 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestRoundValues(t *testing.T) {
+	tests := []struct {
+		name       string
+		num1, num2 float64
+		want       []float64
+		wantUnits  string
+	}{
+		{"below thousand", 999, 12.5, []float64{999, 12.5}, ""},
+		{"thousands", 1234, 56, []float64{1.2, 0.1}, "K"},
+		{"millions", 1250000, 999999, []float64{1.3, 1}, "M"},
+		{"billions", 3456789012, 0, []float64{3.5, 0}, "B"},
+		{"trillions", 1.25e12, 2.04e12, []float64{1.3, 2}, "T"},
+		{"unit of larger value", 500, 2.5e9, []float64{0, 2.5}, "B"},
+		{"quadrillion as is", 1e15, 5, []float64{1e15, 5}, ""},
+		{"negative below larger", -4.2e6, 1.1e7, []float64{-4.2, 11}, "M"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, units := RoundValues(tt.num1, tt.num2)
+			if !reflect.DeepEqual(got, tt.want) || units != tt.wantUnits {
+				t.Errorf("RoundValues(%v, %v) = %v %q, want %v %q", tt.num1, tt.num2, got, units, tt.want, tt.wantUnits)
+			}
+		})
+	}
+}
+
+func TestRoundValuesParse(t *testing.T) {
+	// Values rounded by RoundValues parse back to the rounded value
+	for _, num := range []float64{512, 4.2e3, 7.7e6, 9.9e9, 1.5e12} {
+		vals, units := RoundValues(num, 0)
+		cell := formatCell(vals[0], units)
+		got, ok := ParseValue(cell)
+		if !ok || math.Abs(got-num) > num*1e-9 {
+			t.Errorf("ParseValue(%q) = %v %v, want %v", cell, got, ok, num)
+		}
+	}
+}
+
+// formatCell renders a rounded value as tables do, Eg: 1.5 T
+func formatCell(val float64, units string) string {
+	s := strconv.FormatFloat(val, 'f', -1, 64)
+	if units != "" {
+		s += " " + units
+	}
+	return s
+}
@@ -0,0 +1,112 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// TradingSession describes the daily hours of a traditional market, Eg: US
+// equities from 09:30 to 16:00 in America/New_York. Sessions are assumed to
+// run on weekdays only.
+type TradingSession struct {
+	Enabled  bool
+	Location *time.Location
+	Open     time.Duration // Time of day the session opens at
+	Close    time.Duration // Time of day the session closes at
+}
+
+// session is marked on intraday charts when enabled. It is guarded by
+// sessionMutex as it is set again when the config file is reloaded.
+var (
+	session = TradingSession{
+		Location: time.UTC,
+		Open:     time.Duration(9)*time.Hour + time.Duration(30)*time.Minute,
+		Close:    time.Duration(16) * time.Hour,
+	}
+	sessionMutex sync.RWMutex
+)
+
+// GetSession returns the trading session marked on intraday charts
+func GetSession() TradingSession {
+	sessionMutex.RLock()
+	defer sessionMutex.RUnlock()
+	return session
+}
+
+// SetSession sets the trading session marked on intraday charts
+func SetSession(s TradingSession) {
+	sessionMutex.Lock()
+	session = s
+	sessionMutex.Unlock()
+}
+
+// ParseTimeOfDay parses a time of day such as "09:30" into the duration
+// since midnight
+func ParseTimeOfDay(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Markers returns indices into times (in ascending order) of the first
+// points at or after each session open and close
+func (s TradingSession) Markers(times []time.Time) (opens, closes []int) {
+	if len(times) < 2 {
+		return nil, nil
+	}
+
+	// index returns the index of the first point at or after t, or -1 if t
+	// is outside times
+	index := func(t time.Time) int {
+		if !t.After(times[0]) || t.After(times[len(times)-1]) {
+			return -1
+		}
+		return sort.Search(len(times), func(i int) bool {
+			return !times[i].Before(t)
+		})
+	}
+
+	// Check every day covered by times
+	first := times[0].In(s.Location)
+	day := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, s.Location)
+	for !day.After(times[len(times)-1]) {
+		if day.Weekday() != time.Saturday && day.Weekday() != time.Sunday {
+			if i := index(s.at(day, s.Open)); i != -1 {
+				opens = append(opens, i)
+			}
+			if i := index(s.at(day, s.Close)); i != -1 {
+				closes = append(closes, i)
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+
+	return opens, closes
+}
+
+// at returns the time of day on day in the session's location. The wall
+// clock time is used so that sessions follow daylight saving changes.
+func (s TradingSession) at(day time.Time, timeOfDay time.Duration) time.Time {
+	hours := int(timeOfDay / time.Hour)
+	minutes := int((timeOfDay % time.Hour) / time.Minute)
+	return time.Date(day.Year(), day.Month(), day.Day(), hours, minutes, 0, 0, s.Location)
+}
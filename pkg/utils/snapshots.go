@@ -0,0 +1,58 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"encoding/json"
+)
+
+// Snapshot holds a value recorded at a point in time (unix seconds)
+type Snapshot struct {
+	Time  int64   `json:"time"`
+	Value float64 `json:"value"`
+}
+
+// GetSnapshots reads snapshots stored under the given name from the local
+// store and returns them in the order they were recorded.
+func GetSnapshots(name string) []Snapshot {
+	snapshots := []Snapshot{}
+
+	// Read record
+	data, err := getRecord(name)
+	if err != nil {
+		return snapshots
+	}
+
+	// Read content
+	err = json.Unmarshal(data, &snapshots)
+	if err != nil {
+		return []Snapshot{}
+	}
+
+	return snapshots
+}
+
+// SaveSnapshots exports snapshots to the local store under the given name.
+// The file store saves them on ~/.cryptgo-<name>.json
+func SaveSnapshots(name string, snapshots []Snapshot) error {
+	data, err := json.Marshal(snapshots)
+	if err != nil {
+		return err
+	}
+
+	return putRecord(name, data)
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package utils
 
 import (
+	"math"
 	"sort"
 	"strconv"
 	"strings"
@@ -27,99 +28,283 @@ const (
 	DOWN_ARROW = "▼"
 )
 
-// SortData helps sort table rows. It sorts the table based on values given
-// in the sortIdx column and sorts ascending if sortAsc is true.
-// sortCase is set to identify the set of 'less' functions to use to
-// sort the selected column by.
-func SortData(data [][]string, sortIdx int, sortAsc bool, sortCase string) {
+// Comparator reports whether cell a sorts before cell b in ascending order.
+// Cells that can't be parsed by a comparator are reported as invalid and are
+// always sorted to the bottom of the table, regardless of sort order.
+type Comparator struct {
+	// Parse returns the sort key of a cell and false if the cell is invalid
+	Parse func(cell string) (float64, bool)
+	// Less compares valid cells, if nil the parsed keys are compared
+	Less func(a, b string) bool
+}
 
-	if sortIdx < 0 {
-		return
-	}
+// SortLayout maps the columns of a table to the comparator they are sorted
+// with. Columns missing from the layout are sorted by the first column.
+type SortLayout map[int]Comparator
 
-	// Define less functions
-	intSort := func(i, j int) bool {
-		x, _ := strconv.Atoi(data[i][sortIdx])
-		y, _ := strconv.Atoi(data[j][sortIdx])
-		if sortAsc {
-			return x < y
+// unitMultipliers maps units used by RoundValues to their value
+var unitMultipliers = map[string]float64{
+	"":  1,
+	"K": K,
+	"M": M,
+	"B": G,
+	"T": T,
+}
+
+// groupSeparators separate thousands of numbers written in the conventions
+// of a currency, other than commas and dots
+var groupSeparators = strings.NewReplacer("'", "", "\u202f", "")
+
+// normaliseNumber rewrites a number written in the conventions of a
+// currency with a dot as decimal separator and no separators of thousands,
+// Eg: 1.234,56 or 1,234.56 to 1234.56. A single comma followed by 3 digits
+// separates thousands, as no currency written with a decimal comma has 3
+// decimals.
+func normaliseNumber(num string) string {
+	dots, commas := strings.Count(num, "."), strings.Count(num, ",")
+	lastComma := strings.LastIndex(num, ",")
+
+	switch {
+	case dots > 0 && commas > 0:
+		if lastComma > strings.LastIndex(num, ".") {
+			return strings.Replace(strings.ReplaceAll(num, ".", ""), ",", ".", 1)
 		}
-		return x > y
+		return strings.ReplaceAll(num, ",", "")
+	case commas > 1 || (commas == 1 && len(num)-lastComma == 4):
+		return strings.ReplaceAll(num, ",", "")
+	case commas == 1:
+		return strings.Replace(num, ",", ".", 1)
+	case dots > 1:
+		return strings.ReplaceAll(num, ".", "")
 	}
+	return num
+}
 
-	strSort := func(i, j int) bool {
-		if sortAsc {
-			return data[i][sortIdx] < data[j][sortIdx]
-		}
-		return data[i][sortIdx] > data[j][sortIdx]
+// ParseValue parses a number as rendered in tables, written in the
+// conventions of any currency, Eg: 1,234.56 or 1.234,56. A trailing % and
+// units used by RoundValues (K, M, B, T), either attached or as a separate
+// word, are accepted. Anything following the unit, such as a currency label,
+// is ignored. NaN, infinities and text like "NA" are reported as invalid.
+func ParseValue(cell string) (float64, bool) {
+	fields := strings.Fields(groupSeparators.Replace(cell))
+	if len(fields) == 0 {
+		return 0, false
 	}
 
-	floatSort := func(i, j int) bool {
-		x1 := data[i][sortIdx]
-		y1 := data[j][sortIdx]
-		x, _ := strconv.ParseFloat(x1, 32)
-		y, _ := strconv.ParseFloat(y1, 32)
-		if sortAsc {
-			return x < y
+	num := strings.TrimSuffix(fields[0], "%")
+	unit := ""
+	if n := len(num); n > 0 {
+		if _, ok := unitMultipliers[num[n-1:]]; ok {
+			num, unit = num[:n-1], num[n-1:]
 		}
-		return x > y
 	}
-
-	changeSort := func(i, j int) bool {
-		x1 := strings.Split(data[i][sortIdx], " ")
-		y1 := strings.Split(data[j][sortIdx], " ")
-		x, _ := strconv.ParseFloat(x1[1], 64)
-		if string(x1[0]) == DOWN_ARROW {
-			x = -x
+	if unit == "" && len(fields) > 1 {
+		if _, ok := unitMultipliers[fields[1]]; ok {
+			unit = fields[1]
 		}
+	}
 
-		y, _ := strconv.ParseFloat(y1[1], 64)
-		if string(y1[0]) == DOWN_ARROW {
-			y = -y
-		}
+	val, err := strconv.ParseFloat(normaliseNumber(num), 64)
+	if err != nil || math.IsNaN(val) || math.IsInf(val, 0) {
+		return 0, false
+	}
 
-		if sortAsc {
+	return val * unitMultipliers[unit], true
+}
+
+// ParseChange parses a change rendered as "<arrow> <value>", where a down
+// arrow makes the value negative
+func ParseChange(cell string) (float64, bool) {
+	fields := strings.Fields(cell)
+	if len(fields) < 2 {
+		return 0, false
+	}
+
+	val, ok := ParseValue(fields[1])
+	if !ok {
+		return 0, false
+	}
+
+	if fields[0] == DOWN_ARROW {
+		val = -val
+	}
+
+	return val, true
+}
+
+// Comparators for the kinds of columns found in tables
+var (
+	// IntComparator sorts integers, Eg: ranks
+	IntComparator = Comparator{
+		Parse: func(cell string) (float64, bool) {
+			val, err := strconv.Atoi(strings.TrimSpace(cell))
+			return float64(val), err == nil
+		},
+	}
+
+	// StringComparator sorts text ignoring case, so that mixed-case symbols
+	// sort together. Text differing only by case is ordered by byte value.
+	StringComparator = Comparator{
+		Parse: func(cell string) (float64, bool) {
+			return 0, true
+		},
+		Less: func(a, b string) bool {
+			x, y := strings.ToLower(a), strings.ToLower(b)
+			if x == y {
+				return a < b
+			}
 			return x < y
-		}
-		return x > y
+		},
 	}
 
-	// Set function map
-	sortFuncs := make(map[int]func(i, j int) bool)
-	switch sortCase {
-	case "COINS":
-		sortFuncs = map[int]func(i, j int) bool{
-			0: intSort,    // Rank
-			1: strSort,    // Symbol
-			2: floatSort,  // Price
-			3: changeSort, // Change %
-		}
+	// FloatComparator sorts numbers as parsed by ParseValue
+	FloatComparator = Comparator{
+		Parse: ParseValue,
+	}
 
-	case "FAVOURITES":
-		sortFuncs = map[int]func(i, j int) bool{
-			0: strSort,   // Symbol
-			1: floatSort, // Price
-		}
+	// ChangeComparator sorts changes as parsed by ParseChange
+	ChangeComparator = Comparator{
+		Parse: ParseChange,
+	}
+)
 
-	case "PORTFOLIO":
-		sortFuncs = map[int]func(i, j int) bool{
-			0: intSort,    // Rank
-			1: strSort,    // Symbol
-			2: floatSort,  // Price
-			3: changeSort, // Change %
-			4: floatSort,  // Holding
-			5: floatSort,  // Balance
-			6: floatSort,  // Holding %
-		}
+// Layouts of tables which can be sorted
+var (
+	CoinsLayout = SortLayout{
+		0: IntComparator,    // Rank
+		1: StringComparator, // Symbol
+		2: FloatComparator,  // Price
+		3: ChangeComparator, // Change % (1h)
+		4: ChangeComparator, // Change % (24h)
+		5: ChangeComparator, // Change % (7d)
+		6: ChangeComparator, // Change % of the selected duration
+	}
+
+	FavouritesLayout = SortLayout{
+		0: StringComparator, // Symbol
+		1: FloatComparator,  // Price
+		2: ChangeComparator, // Change % (1h)
+		3: ChangeComparator, // Change % (24h)
+		4: ChangeComparator, // Change % (7d)
+		5: ChangeComparator, // Change % (30d)
+	}
+
+	PortfolioLayout = SortLayout{
+		0: IntComparator,    // Rank
+		1: StringComparator, // Symbol
+		2: FloatComparator,  // Price
+		3: ChangeComparator, // Change %
+		4: FloatComparator,  // Holding
+		5: FloatComparator,  // Balance
+		6: FloatComparator,  // Holding %
+	}
+
+	YieldsLayout = SortLayout{
+		0: StringComparator, // Project
+		1: StringComparator, // Chain
+		2: StringComparator, // Symbol
+		3: FloatComparator,  // TVL
+		4: FloatComparator,  // APY
+	}
+
+	ScreenerLayout = SortLayout{
+		0: IntComparator,    // Rank
+		1: StringComparator, // Symbol
+		2: StringComparator, // Name
+		3: FloatComparator,  // Price
+		4: ChangeComparator, // Change % (24h)
+		5: FloatComparator,  // Market Cap
+		6: FloatComparator,  // Volume / Market Cap
+		7: FloatComparator,  // From ATH %
+	}
+
+	HoldingsLayout = SortLayout{
+		0: StringComparator, // Coin
+		1: FloatComparator,  // Quantity
+		2: FloatComparator,  // Avg Buy
+		3: FloatComparator,  // Price
+		4: FloatComparator,  // Value
+		5: FloatComparator,  // P/L
+		6: ChangeComparator, // P/L %
+		7: FloatComparator,  // Allocation %
+		8: StringComparator, // Since
+	}
+
+	MarketsLayout = SortLayout{
+		0: StringComparator, // Exchange
+		1: StringComparator, // Pair
+		2: FloatComparator,  // Price
+		3: FloatComparator,  // Volume (24h)
+		4: FloatComparator,  // Volume %
+	}
+
+	ExchangesLayout = SortLayout{
+		0: IntComparator,    // Rank
+		1: StringComparator, // Exchange
+		2: IntComparator,    // Trust
+		3: FloatComparator,  // Volume (24h)
+		4: FloatComparator,  // Volume %
+		5: IntComparator,    // Pairs
+	}
+
+	ExchangeMarketsLayout = SortLayout{
+		0: StringComparator, // Pair
+		1: FloatComparator,  // Price
+		2: FloatComparator,  // Volume (24h)
+		3: FloatComparator,  // Volume %
+	}
 
-	default:
-		sortFuncs[sortIdx] = strSort
+	CurrencyLayout = SortLayout{
+		0: StringComparator, // Currency
+		1: StringComparator, // Symbol
+		2: StringComparator, // Type
+		3: FloatComparator,  // USD rate
+		4: StringComparator, // Trend
+	}
+)
+
+// SortData helps sort table rows. It sorts the table based on values given
+// in the sortIdx column and sorts ascending if sortAsc is true.
+// layout determines the comparator the selected column is sorted by.
+// Sorting is stable and rows with invalid cells are kept at the bottom.
+func SortData(data [][]string, sortIdx int, sortAsc bool, layout SortLayout) {
+
+	if sortIdx < 0 {
+		return
 	}
 
-	if _, ok := sortFuncs[sortIdx]; !ok {
+	comparator, ok := layout[sortIdx]
+	if !ok {
 		sortIdx = 0
+		comparator, ok = layout[sortIdx]
+		if !ok {
+			comparator = StringComparator
+		}
+	}
+
+	less := func(i, j int) bool {
+		a, b := data[i][sortIdx], data[j][sortIdx]
+		x, aValid := comparator.Parse(a)
+		y, bValid := comparator.Parse(b)
+
+		// Invalid cells sort last
+		if !aValid || !bValid {
+			return aValid && !bValid
+		}
+
+		if comparator.Less != nil {
+			if sortAsc {
+				return comparator.Less(a, b)
+			}
+			return comparator.Less(b, a)
+		}
+
+		if sortAsc {
+			return x < y
+		}
+		return x > y
 	}
 
 	// Sort data
-	sort.Slice(data, sortFuncs[sortIdx])
+	sort.SliceStable(data, less)
 }
@@ -0,0 +1,229 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormaliseNumber(t *testing.T) {
+	tests := []struct {
+		num  string
+		want string
+	}{
+		{"1234.56", "1234.56"},
+		{"1,234.56", "1234.56"},
+		{"1.234,56", "1234.56"},
+		{"1234,56", "1234.56"},
+		{"1,234", "1234"},
+		{"1,234,567", "1234567"},
+		{"1.234.567", "1234567"},
+		{"0,5", "0.5"},
+		{"1,234,567,890,123.45", "1234567890123.45"},
+		{"1.234.567.890.123,45", "1234567890123.45"},
+	}
+
+	for _, tt := range tests {
+		if got := normaliseNumber(tt.num); got != tt.want {
+			t.Errorf("normaliseNumber(%q) = %q, want %q", tt.num, got, tt.want)
+		}
+	}
+}
+
+func TestParseValue(t *testing.T) {
+	tests := []struct {
+		cell   string
+		want   float64
+		wantOk bool
+	}{
+		{"42", 42, true},
+		{"-3.5", -3.5, true},
+		{"12.5%", 12.5, true},
+		{"1,234.56", 1234.56, true},
+		{"1.234,56", 1234.56, true},
+		{"1'234.56", 1234.56, true},
+		{"1\u202f234,56", 1234.56, true},
+		{"1.5K", 1500, true},
+		{"2.5 M", 2.5e6, true},
+		{"3,2 B", 3.2e9, true},
+		{"1.2T", 1.2e12, true},
+		{"4.5 T EUR €", 4.5e12, true},
+		{"1,234.56 USD", 1234.56, true},
+		{"NA", 0, false},
+		{"NaN", 0, false},
+		{"Inf", 0, false},
+		{"", 0, false},
+		{"   ", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseValue(tt.cell)
+		if ok != tt.wantOk || !closeTo(got, tt.want) {
+			t.Errorf("ParseValue(%q) = %v %v, want %v %v", tt.cell, got, ok, tt.want, tt.wantOk)
+		}
+	}
+}
+
+func TestParseChange(t *testing.T) {
+	tests := []struct {
+		cell   string
+		want   float64
+		wantOk bool
+	}{
+		{UP_ARROW + " 1.25", 1.25, true},
+		{DOWN_ARROW + " 1.25", -1.25, true},
+		{DOWN_ARROW + " 0,75", -0.75, true},
+		{UP_ARROW + " 1.2K", 1200, true},
+		{"1.25", 0, false},
+		{UP_ARROW + " NA", 0, false},
+		{"", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseChange(tt.cell)
+		if ok != tt.wantOk || !closeTo(got, tt.want) {
+			t.Errorf("ParseChange(%q) = %v %v, want %v %v", tt.cell, got, ok, tt.want, tt.wantOk)
+		}
+	}
+}
+
+func TestSortData(t *testing.T) {
+	tests := []struct {
+		name    string
+		column  []string
+		sortIdx int
+		sortAsc bool
+		layout  SortLayout
+		want    []string
+	}{
+		{
+			name:    "ranks ascending",
+			column:  []string{"10", "2", "1"},
+			sortIdx: 0,
+			sortAsc: true,
+			layout:  SortLayout{0: IntComparator},
+			want:    []string{"1", "2", "10"},
+		},
+		{
+			name:    "mixed-case symbols",
+			column:  []string{"eth", "BTC", "Ada", "btc"},
+			sortIdx: 0,
+			sortAsc: true,
+			layout:  SortLayout{0: StringComparator},
+			want:    []string{"Ada", "BTC", "btc", "eth"},
+		},
+		{
+			name:    "mixed-case symbols descending",
+			column:  []string{"eth", "BTC", "Ada", "btc"},
+			sortIdx: 0,
+			sortAsc: false,
+			layout:  SortLayout{0: StringComparator},
+			want:    []string{"eth", "btc", "BTC", "Ada"},
+		},
+		{
+			name:    "units ascending",
+			column:  []string{"1.2 B", "950", "3.4 M", "1.1 T", "12K"},
+			sortIdx: 0,
+			sortAsc: true,
+			layout:  SortLayout{0: FloatComparator},
+			want:    []string{"950", "12K", "3.4 M", "1.2 B", "1.1 T"},
+		},
+		{
+			name:    "invalid last ascending",
+			column:  []string{"NA", "2", "NaN", "1"},
+			sortIdx: 0,
+			sortAsc: true,
+			layout:  SortLayout{0: FloatComparator},
+			want:    []string{"1", "2", "NA", "NaN"},
+		},
+		{
+			name:    "invalid last descending",
+			column:  []string{"NA", "2", "NaN", "1"},
+			sortIdx: 0,
+			sortAsc: false,
+			layout:  SortLayout{0: FloatComparator},
+			want:    []string{"2", "1", "NA", "NaN"},
+		},
+		{
+			name:    "comma decimals",
+			column:  []string{"1.234,56", "99,5", "1.000.000,01"},
+			sortIdx: 0,
+			sortAsc: false,
+			layout:  SortLayout{0: FloatComparator},
+			want:    []string{"1.000.000,01", "1.234,56", "99,5"},
+		},
+		{
+			name:    "changes",
+			column:  []string{UP_ARROW + " 2.5", DOWN_ARROW + " 3.0", UP_ARROW + " 0.5", "NA"},
+			sortIdx: 0,
+			sortAsc: true,
+			layout:  SortLayout{0: ChangeComparator},
+			want:    []string{DOWN_ARROW + " 3.0", UP_ARROW + " 0.5", UP_ARROW + " 2.5", "NA"},
+		},
+		{
+			name:    "negative index keeps order",
+			column:  []string{"b", "a"},
+			sortIdx: -1,
+			sortAsc: true,
+			layout:  SortLayout{0: StringComparator},
+			want:    []string{"b", "a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := [][]string{}
+			for _, cell := range tt.column {
+				data = append(data, []string{cell})
+			}
+
+			SortData(data, tt.sortIdx, tt.sortAsc, tt.layout)
+
+			got := []string{}
+			for _, row := range data {
+				got = append(got, row[0])
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SortData() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortDataUnknownColumn(t *testing.T) {
+	// Columns missing from the layout are sorted by the first column
+	data := [][]string{{"3", "x"}, {"1", "y"}, {"2", "z"}}
+	SortData(data, 5, true, SortLayout{0: IntComparator})
+
+	want := [][]string{{"1", "y"}, {"2", "z"}, {"3", "x"}}
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("SortData() = %q, want %q", data, want)
+	}
+}
+
+// closeTo reports whether two floats are equal within rounding errors
+func closeTo(a, b float64) bool {
+	diff := a - b
+	if diff < 0 {
+		diff = -diff
+	}
+	if b < 0 {
+		b = -b
+	}
+	return diff <= 1e-9*b || diff < 1e-12
+}
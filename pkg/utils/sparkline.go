@@ -0,0 +1,81 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders values as a line of unicode block characters, at most
+// width characters long. Values are averaged into buckets when there are more
+// values than width.
+func Sparkline(values []float64, width int) string {
+	if len(values) == 0 || width <= 0 {
+		return ""
+	}
+
+	// Average values into buckets
+	if len(values) > width {
+		buckets := make([]float64, width)
+		for i := range buckets {
+			start := i * len(values) / width
+			end := (i + 1) * len(values) / width
+			sum := 0.0
+			for _, val := range values[start:end] {
+				sum += val
+			}
+			buckets[i] = sum / float64(end-start)
+		}
+		values = buckets
+	}
+
+	min := MinFloat64(values...)
+	max := MaxFloat64(values...)
+
+	line := make([]rune, len(values))
+	for i, val := range values {
+		level := 0
+		if max > min {
+			level = int((val - min) / (max - min) * float64(len(sparkBlocks)-1))
+		}
+		line[i] = sparkBlocks[level]
+	}
+
+	return string(line)
+}
+
+var barBlocks = []rune("▏▎▍▌▋▊▉█")
+
+// ShareBar renders a percentage as a horizontal bar of unicode block
+// characters, width characters long at 100%
+func ShareBar(percent float64, width int) string {
+	eighths := int(percent / 100 * float64(width*8))
+	if eighths <= 0 || width <= 0 {
+		return ""
+	}
+	if eighths > width*8 {
+		eighths = width * 8
+	}
+
+	bar := []rune{}
+	for ; eighths >= 8; eighths -= 8 {
+		bar = append(bar, '█')
+	}
+	if eighths > 0 {
+		bar = append(bar, barBlocks[eighths-1])
+	}
+
+	return string(bar)
+}
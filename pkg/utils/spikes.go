@@ -0,0 +1,225 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Settings of the spike guard, set from the config file. Prices deviating
+// from the rolling median of the last SpikeWindow prices by more than
+// SpikeThreshold percent are rejected as provider glitches.
+var (
+	SpikeGuard     = true
+	SpikeThreshold = 25.0
+	SpikeWindow    = 15
+	SpikeLog       = ""
+)
+
+// spikeSettings returns settings of the spike guard, read under the
+// settings lock
+func spikeSettings() (guard bool, threshold float64, window int, log string) {
+	RLockSettings()
+	defer RUnlockSettings()
+	return SpikeGuard, SpikeThreshold, SpikeWindow, SpikeLog
+}
+
+// MinSpikeWindow is how many prices a series needs before prices are
+// checked against its median, and the smallest SpikeWindow
+const MinSpikeWindow = 5
+
+// spikeSeries holds the last accepted prices of a series and the price
+// last rejected, if any
+type spikeSeries struct {
+	prices   []float64
+	rejected float64
+}
+
+// spikes holds series of prices checked by AcceptPrice, keyed by name, and
+// points of history already logged by FilterSpikes, as history is filtered
+// again on each refresh
+var spikes = struct {
+	sync.Mutex
+	series map[string]*spikeSeries
+	logged map[string]bool
+}{series: make(map[string]*spikeSeries), logged: make(map[string]bool)}
+
+// maxLoggedPoints bounds how many logged points of history are remembered
+const maxLoggedPoints = 1000
+
+// median returns the median of values, 0 if there are none
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// deviation returns how far value is from base in percent
+func deviation(value, base float64) float64 {
+	if base == 0 {
+		return 0
+	}
+	return math.Abs(value-base) / math.Abs(base) * 100
+}
+
+// AcceptPrice returns false if price is a single tick deviating from the
+// rolling median of the named series by more than SpikeThreshold, Eg: a
+// glitch of the provider, logging it for review. A price close to one
+// just rejected is taken as a real move, and both are accepted.
+func AcceptPrice(name string, price float64) bool {
+	guard, threshold, window, _ := spikeSettings()
+	if !guard {
+		return true
+	}
+
+	spikes.Lock()
+	defer spikes.Unlock()
+
+	series, ok := spikes.series[name]
+	if !ok {
+		series = &spikeSeries{}
+		spikes.series[name] = series
+	}
+
+	if len(series.prices) >= MinSpikeWindow {
+		base := median(series.prices)
+		if deviation(price, base) > threshold {
+			if series.rejected == 0 || deviation(price, series.rejected) > threshold {
+				series.rejected = price
+				logSpike(name, price, base)
+				return false
+			}
+
+			// Prices moved, start over from the new level
+			series.prices = []float64{series.rejected}
+		}
+	}
+
+	series.rejected = 0
+	series.prices = append(series.prices, price)
+	if window > 0 && len(series.prices) > window {
+		series.prices = series.prices[len(series.prices)-window:]
+	}
+
+	return true
+}
+
+// ResetSpikes forgets prices of the named series, Eg: when a stream is
+// opened again after prices may have moved
+func ResetSpikes(name string) {
+	spikes.Lock()
+	defer spikes.Unlock()
+	delete(spikes.series, name)
+}
+
+// FilterSpikes returns indices of points of a price history which aren't
+// single points deviating by more than SpikeThreshold from the medians of
+// the points on both sides of them. Points rejected are logged once, by
+// the name of the series and their time.
+func FilterSpikes(name string, prices []float64, times []time.Time) []int {
+	indices := make([]int, 0, len(prices))
+	guard, threshold, window, _ := spikeSettings()
+
+	// Points are compared with up to half a window on each side, and those
+	// with fewer than 2 points on a side are kept
+	side := window / 2
+
+	for i, price := range prices {
+		if !guard || i < 2 || i >= len(prices)-2 {
+			indices = append(indices, i)
+			continue
+		}
+
+		from, to := i-side, i+1+side
+		if from < 0 {
+			from = 0
+		}
+		if to > len(prices) {
+			to = len(prices)
+		}
+
+		before := median(prices[from:i])
+		after := median(prices[i+1 : to])
+		if deviation(price, before) > threshold && deviation(price, after) > threshold {
+			point := name
+			if i < len(times) {
+				point = fmt.Sprintf("%s at %s", name, times[i].Format(time.RFC3339))
+			}
+
+			spikes.Lock()
+			if !spikes.logged[point] {
+				if len(spikes.logged) >= maxLoggedPoints {
+					spikes.logged = make(map[string]bool)
+				}
+				spikes.logged[point] = true
+				logSpike(point, price, before)
+			}
+			spikes.Unlock()
+			continue
+		}
+
+		indices = append(indices, i)
+	}
+
+	return indices
+}
+
+// spikeLogPath returns the file rejected prices are logged to, SpikeLog or
+// ~/.cryptgo-rejected.log
+func spikeLogPath() string {
+	if _, _, _, log := spikeSettings(); log != "" {
+		return log
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(homeDir, ".cryptgo-rejected.log")
+}
+
+// logSpike appends a rejected price to the log of rejected prices
+func logSpike(name string, price, median float64) {
+	path := spikeLogPath()
+	if path == "" {
+		return
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	fmt.Fprintf(f, "%s %s rejected %g, median %g (%.1f%% off)\n",
+		time.Now().Format(time.RFC3339), name, price, median, deviation(price, median))
+}
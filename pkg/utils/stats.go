@@ -0,0 +1,91 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// StatsOutput is the file quick stats of the open coin are written to as
+// lines of JSON, Eg: a named pipe, or - for stdout. Stats aren't written
+// if empty.
+var StatsOutput = ""
+
+// statsBacklog is how many lines of stats are held while the output is
+// behind, after which lines are dropped
+const statsBacklog = 64
+
+// stats holds lines of stats waiting to be written to the output at path,
+// so a slow or absent reader of a named pipe doesn't block the UI
+var stats = struct {
+	sync.Mutex
+	path  string
+	lines chan string
+}{}
+
+// WriteStats queues a line of stats to be written to StatsOutput. It returns
+// false if there is no output, or the line was dropped as the output is
+// behind.
+func WriteStats(line string) bool {
+	stats.Lock()
+	defer stats.Unlock()
+
+	if StatsOutput == "" {
+		return false
+	}
+
+	// Open the output again once changed, Eg: on reloading config
+	if stats.lines == nil || stats.path != StatsOutput {
+		if stats.lines != nil {
+			close(stats.lines)
+		}
+		stats.path = StatsOutput
+		stats.lines = make(chan string, statsBacklog)
+		go writeStats(stats.path, stats.lines)
+	}
+
+	select {
+	case stats.lines <- line:
+		return true
+	default:
+		return false
+	}
+}
+
+// writeStats writes lines to the output at path till lines is closed. The
+// UI is drawn to the terminal rather than stdout, so stdout can be piped.
+func writeStats(path string, lines chan string) {
+	var w io.Writer = os.Stdout
+	if path != "-" {
+		// Opened for reading too, so named pipes open without a reader
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0644)
+		if err != nil {
+			for range lines {
+			}
+			return
+		}
+		defer f.Close()
+		w = f
+	}
+
+	for line := range lines {
+		fmt.Fprintln(w, line)
+	}
+}
@@ -0,0 +1,214 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Backends local data can be kept in
+const (
+	StoreFile   = "file"   // A JSON file per record, ~/.cryptgo-<name>.json
+	StoreBolt   = "bolt"   // A bbolt database, ~/.cryptgo.db
+	StoreSQLite = "sqlite" // An SQLite database, ~/.cryptgo.sqlite, only in builds with CGO
+)
+
+// StoreBackend is the backend local data is kept in, set from the config file
+var StoreBackend = StoreFile
+
+// ErrNotStored is returned when a record was never saved
+var ErrNotStored = errors.New("record not stored")
+
+// storeTimeout is how long opening a database waits for another cryptgo
+// process holding it, Eg: the daemon
+const storeTimeout = time.Duration(1) * time.Second
+
+// Store persists records of local data by name, Eg: metadata and snapshots
+type Store interface {
+	// Get returns the record saved under name, or ErrNotStored
+	Get(name string) ([]byte, error)
+
+	// Put saves data under name, replacing the record saved before
+	Put(name string, data []byte) error
+}
+
+// NewStore returns the store of the given backend
+func NewStore(backend string) (Store, error) {
+	switch backend {
+	case StoreFile:
+		return fileStore{}, nil
+	case StoreBolt:
+		return boltStore{}, nil
+	case StoreSQLite:
+		return newSQLiteStore()
+	}
+
+	return nil, fmt.Errorf("unknown store %q, expected file, bolt or sqlite", backend)
+}
+
+// storePath returns the path of a file kept in the home directory
+func storePath(file string) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return homeDir + "/" + file, nil
+}
+
+// ConfigDir returns the user config directory, $XDG_CONFIG_HOME or ~/.config
+func ConfigDir() (string, error) {
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return xdg, nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".config"), nil
+}
+
+// getRecord returns the record saved under name in StoreBackend. Records
+// not yet in a database are read from their file, so data saved before
+// switching backends is kept, and moved over once saved again.
+func getRecord(name string) ([]byte, error) {
+	store, err := NewStore(StoreBackend)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := store.Get(name)
+	if errors.Is(err, ErrNotStored) && StoreBackend != StoreFile {
+		return fileStore{}.Get(name)
+	}
+
+	return data, err
+}
+
+// putRecord saves data under name in StoreBackend
+func putRecord(name string, data []byte) error {
+	store, err := NewStore(StoreBackend)
+	if err != nil {
+		return err
+	}
+
+	writeMutex.Lock()
+	defer writeMutex.Unlock()
+
+	return store.Put(name, data)
+}
+
+// fileStore keeps each record in its own hidden JSON file
+type fileStore struct{}
+
+// Get reads the record from ~/.cryptgo-<name>.json
+func (fileStore) Get(name string) ([]byte, error) {
+	path, err := storePath(".cryptgo-" + name + ".json")
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, ErrNotStored
+	}
+
+	return data, err
+}
+
+// Put writes the record to ~/.cryptgo-<name>.json
+func (fileStore) Put(name string, data []byte) error {
+	// Visible and hidden paths are used explicitly because we get a
+	// permission denied error on trying to write/create to a hidden file
+	filePath, err := storePath("cryptgo-" + name + ".json")
+	if err != nil {
+		return err
+	}
+	hiddenPath, err := storePath(".cryptgo-" + name + ".json")
+	if err != nil {
+		return err
+	}
+
+	return writeHidden(filePath, hiddenPath, data)
+}
+
+// boltBucket is the bucket records are kept in
+var boltBucket = []byte("cryptgo")
+
+// boltStore keeps records in a bbolt database, a pure Go key/value store.
+// The database is opened for each access, as bbolt locks it while open.
+type boltStore struct{}
+
+// open opens the database at ~/.cryptgo.db
+func (boltStore) open() (*bolt.DB, error) {
+	path, err := storePath(".cryptgo.db")
+	if err != nil {
+		return nil, err
+	}
+
+	return bolt.Open(path, 0600, &bolt.Options{Timeout: storeTimeout})
+}
+
+// Get reads the record from the database
+func (s boltStore) Get(name string) ([]byte, error) {
+	db, err := s.open()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	var data []byte
+	err = db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltBucket)
+		if bucket == nil {
+			return ErrNotStored
+		}
+
+		val := bucket.Get([]byte(name))
+		if val == nil {
+			return ErrNotStored
+		}
+
+		// Values are only valid while the transaction is open
+		data = append([]byte{}, val...)
+		return nil
+	})
+
+	return data, err
+}
+
+// Put writes the record to the database
+func (s boltStore) Put(name string, data []byte) error {
+	db, err := s.open()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(boltBucket)
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(name), data)
+	})
+}
@@ -0,0 +1,88 @@
+//go:build cgo
+// +build cgo
+
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// sqliteStore keeps records in a table of an SQLite database
+type sqliteStore struct{}
+
+// newSQLiteStore returns the SQLite store
+func newSQLiteStore() (Store, error) {
+	return sqliteStore{}, nil
+}
+
+// open opens the database at ~/.cryptgo.sqlite, creating the records table
+// if needed
+func (sqliteStore) open() (*sql.DB, error) {
+	path, err := storePath(".cryptgo.sqlite")
+	if err != nil {
+		return nil, err
+	}
+
+	dsn := fmt.Sprintf("file:%s?_busy_timeout=%d", path, storeTimeout.Milliseconds())
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = db.Exec("CREATE TABLE IF NOT EXISTS records (name TEXT PRIMARY KEY, data BLOB NOT NULL)")
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// Get reads the record from the database
+func (s sqliteStore) Get(name string) ([]byte, error) {
+	db, err := s.open()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	var data []byte
+	err = db.QueryRow("SELECT data FROM records WHERE name = ?", name).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotStored
+	}
+
+	return data, err
+}
+
+// Put writes the record to the database
+func (s sqliteStore) Put(name string, data []byte) error {
+	db, err := s.open()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	_, err = db.Exec("INSERT OR REPLACE INTO records (name, data) VALUES (?, ?)", name, data)
+	return err
+}
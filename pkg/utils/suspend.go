@@ -0,0 +1,68 @@
+//go:build !windows
+// +build !windows
+
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	ui "github.com/gizak/termui/v3"
+)
+
+var (
+	suspendChan chan os.Signal
+	suspendOnce sync.Once
+)
+
+// SuspendSignals returns a channel receiving SIGTSTP sent to cryptgo from
+// outside the UI (Eg: kill -TSTP). Pages call Suspend when it fires so that
+// the terminal is restored before stopping. The same channel is shared by
+// all pages, so only the page being viewed handles the signal.
+func SuspendSignals() chan os.Signal {
+	suspendOnce.Do(func() {
+		suspendChan = make(chan os.Signal, 1)
+		signal.Notify(suspendChan, syscall.SIGTSTP)
+	})
+	return suspendChan
+}
+
+// Suspend restores the terminal and stops cryptgo, handing control back to
+// the shell as Ctrl-Z would. It returns once cryptgo is continued (Eg: with
+// fg), after setting the terminal up for the UI again. Callers must re-render
+// the UI afterwards.
+func Suspend() error {
+	contChan := make(chan os.Signal, 1)
+	signal.Notify(contChan, syscall.SIGCONT)
+	defer signal.Stop(contChan)
+
+	ui.Close()
+
+	// SIGSTOP is used as SIGTSTP is caught by SuspendSignals
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGSTOP); err != nil {
+		return err
+	}
+
+	// Wait to be continued
+	<-contChan
+
+	return ui.Init()
+}
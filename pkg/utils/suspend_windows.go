@@ -0,0 +1,33 @@
+//go:build windows
+// +build windows
+
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import "os"
+
+// SuspendSignals returns a channel that never fires, as Windows has no
+// SIGTSTP
+func SuspendSignals() chan os.Signal {
+	return make(chan os.Signal)
+}
+
+// Suspend is a no-op, as Windows terminals do not support job control
+func Suspend() error {
+	return nil
+}
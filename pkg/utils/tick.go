@@ -22,9 +22,17 @@ import (
 )
 
 // LoopTick, runs a given action in a loop in periods of 't' duration. It exits
-// when the context is cancelled
+// when the context is cancelled. Periods are lengthened in low power mode.
 func LoopTick(ctx context.Context, t time.Duration, action func(errChan chan error)) error {
-	ticker := time.NewTicker(t)
+	return LoopTickFunc(ctx, func() time.Duration { return t }, action)
+}
+
+// LoopTickFunc runs a given action in a loop as LoopTick does, in periods
+// returned by every, which is called again every tick so pollers can be slowed
+// down or sped up while they run.
+func LoopTickFunc(ctx context.Context, every func() time.Duration, action func(errChan chan error)) error {
+	period := PollInterval(every())
+	ticker := time.NewTicker(period)
 	defer ticker.Stop()
 
 	errChan := make(chan error)
@@ -44,6 +52,10 @@ func LoopTick(ctx context.Context, t time.Duration, action func(errChan chan err
 			}
 		// Break select every tick
 		case <-ticker.C:
+			if p := PollInterval(every()); p != period {
+				period = p
+				ticker.Reset(period)
+			}
 		}
 	}
 }
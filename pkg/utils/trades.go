@@ -0,0 +1,61 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// tradesRecord is the record imported trades are kept under in the local
+// store
+const tradesRecord = "trades"
+
+// Trade is a buy or sell of a coin, imported from an exchange's export
+type Trade struct {
+	Time     time.Time `json:"time"`
+	Coin     string    `json:"coin"` // Upper case symbol, Eg: BTC
+	Side     string    `json:"side"` // buy or sell
+	Quantity float64   `json:"quantity"`
+	Price    float64   `json:"price"` // USD per coin
+	Fee      float64   `json:"fee"`   // USD
+	Exchange string    `json:"exchange,omitempty"`
+}
+
+// GetTrades returns imported trades from the local store, in the order they
+// were imported
+func GetTrades() []Trade {
+	trades := []Trade{}
+
+	data, err := getRecord(tradesRecord)
+	if err != nil || json.Unmarshal(data, &trades) != nil {
+		return []Trade{}
+	}
+
+	return trades
+}
+
+// SaveTrades saves imported trades to the local store, replacing those
+// saved before
+func SaveTrades(trades []Trade) error {
+	data, err := json.Marshal(trades)
+	if err != nil {
+		return err
+	}
+
+	return putRecord(tradesRecord, data)
+}
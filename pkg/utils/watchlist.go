@@ -0,0 +1,150 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// WatchlistDir is the directory extra watchlists are read from, set from
+// the config file. Watchlists are disabled when empty.
+var WatchlistDir = ""
+
+// FavouritesList is the name of the watchlist of favourites, along with
+// coins in watchlist files
+const FavouritesList = "Favourites"
+
+// NamedWatchlists are watchlists set in the config file, by name, Eg: DeFi
+var NamedWatchlists = map[string][]string{}
+
+// ActiveWatchlist is the watchlist shown when the main page is opened
+var ActiveWatchlist = FavouritesList
+
+// Watchlist is a named list of coins
+type Watchlist struct {
+	Name string
+	IDs  map[string]bool
+}
+
+// WatchlistNames returns the name of favourites followed by names of
+// watchlists set in the config file, sorted
+func WatchlistNames() []string {
+	RLockSettings()
+	names := []string{}
+	for name := range NamedWatchlists {
+		names = append(names, name)
+	}
+	RUnlockSettings()
+	sort.Strings(names)
+
+	return append([]string{FavouritesList}, names...)
+}
+
+// GetNamedWatchlist returns the watchlist set in the config file by name.
+// IDs are empty if no such watchlist is set.
+func GetNamedWatchlist(name string) Watchlist {
+	RLockSettings()
+	defer RUnlockSettings()
+
+	list := Watchlist{Name: name, IDs: map[string]bool{}}
+	for _, id := range NamedWatchlists[name] {
+		id = strings.ToLower(strings.TrimSpace(id))
+		if id != "" {
+			list.IDs[id] = true
+		}
+	}
+	return list
+}
+
+// watchlistFiles returns paths of watchlist files in dir, sorted by name.
+// Watchlists are .txt files with a coin ID per line or .json files with an
+// array of coin IDs.
+func watchlistFiles(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return []string{}
+	}
+
+	files := []string{}
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".txt" && ext != ".json") {
+			continue
+		}
+		files = append(files, filepath.Join(dir, entry.Name()))
+	}
+	sort.Strings(files)
+
+	return files
+}
+
+// WatchlistVersion returns a string which changes whenever a watchlist file
+// in dir is added, removed or modified
+func WatchlistVersion(dir string) string {
+	version := []string{}
+	for _, file := range watchlistFiles(dir) {
+		info, err := os.Stat(file)
+		if err != nil {
+			continue
+		}
+		version = append(version, fmt.Sprintf("%s:%d:%d", file, info.Size(), info.ModTime().UnixNano()))
+	}
+	return strings.Join(version, "|")
+}
+
+// GetWatchlists reads coin IDs listed in watchlist files in dir and returns
+// a map of them. In .txt files, blank lines and lines starting with # are
+// ignored. Files which can't be read are skipped.
+func GetWatchlists(dir string) map[string]bool {
+	watchlist := map[string]bool{}
+
+	for _, file := range watchlistFiles(dir) {
+		ids := []string{}
+
+		if strings.ToLower(filepath.Ext(file)) == ".json" {
+			data, err := os.ReadFile(file)
+			if err != nil || json.Unmarshal(data, &ids) != nil {
+				continue
+			}
+		} else {
+			f, err := os.Open(file)
+			if err != nil {
+				continue
+			}
+			scanner := bufio.NewScanner(f)
+			for scanner.Scan() {
+				ids = append(ids, scanner.Text())
+			}
+			f.Close()
+		}
+
+		for _, id := range ids {
+			id = strings.ToLower(strings.TrimSpace(id))
+			if id != "" && !strings.HasPrefix(id, "#") {
+				watchlist[id] = true
+			}
+		}
+	}
+
+	return watchlist
+}
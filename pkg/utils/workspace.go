@@ -0,0 +1,179 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// workspacesRecord is the record workspaces are kept under in the local
+// store
+const workspacesRecord = "workspaces"
+
+// WorkspacePages are pages a workspace can open on
+var WorkspacePages = []string{"main", "portfolio", "exchanges"}
+
+// Workspace bundles a page with the coins, currency and layout it is shown
+// with, Eg: "BTC trading" on the main page with BTC favourites in USD
+type Workspace struct {
+	Name     string              `json:"name"`
+	Page     string              `json:"page"`
+	Coins    []string            `json:"coins,omitempty"`    // CoinGecko IDs of favourites
+	Currency string              `json:"currency,omitempty"` // Currency ID
+	Hide     map[string][]string `json:"hide,omitempty"`     // Widgets hidden on each page, in every profile
+}
+
+// workspaces holds saved workspaces in the order they were first saved,
+// along with the workspace last opened
+type workspaces struct {
+	Workspaces []Workspace `json:"workspaces"`
+	Last       string      `json:"last"`
+}
+
+// ActiveWorkspace is the name of the workspace open, empty if none is
+var ActiveWorkspace = ""
+
+// SwitchWorkspace is returned by pages to be closed, and the workspace
+// named opened in their place
+type SwitchWorkspace struct {
+	Name string
+}
+
+func (s SwitchWorkspace) Error() string {
+	return fmt.Sprintf("switch to workspace %s", s.Name)
+}
+
+// readWorkspaces reads workspaces from the local store
+func readWorkspaces() workspaces {
+	ws := workspaces{}
+
+	data, err := getRecord(workspacesRecord)
+	if err != nil || json.Unmarshal(data, &ws) != nil {
+		return workspaces{}
+	}
+
+	return ws
+}
+
+// writeWorkspaces writes workspaces to the local store
+func writeWorkspaces(ws workspaces) error {
+	data, err := json.Marshal(ws)
+	if err != nil {
+		return err
+	}
+
+	return putRecord(workspacesRecord, data)
+}
+
+// GetWorkspaces returns saved workspaces, in the order they were first saved
+func GetWorkspaces() []Workspace {
+	return readWorkspaces().Workspaces
+}
+
+// GetWorkspace returns the workspace of a name, matched ignoring case
+func GetWorkspace(name string) (Workspace, error) {
+	ws := readWorkspaces()
+	for _, w := range ws.Workspaces {
+		if strings.EqualFold(w.Name, name) {
+			return w, nil
+		}
+	}
+
+	names := []string{}
+	for _, w := range ws.Workspaces {
+		names = append(names, w.Name)
+	}
+	if len(names) == 0 {
+		return Workspace{}, fmt.Errorf("unknown workspace %q, none are saved", name)
+	}
+	return Workspace{}, fmt.Errorf("unknown workspace %q, expected one of: %s", name, strings.Join(names, ", "))
+}
+
+// SaveWorkspace saves a workspace, replacing one of the same name
+func SaveWorkspace(w Workspace) error {
+	known := false
+	for _, page := range WorkspacePages {
+		known = known || page == w.Page
+	}
+	if !known {
+		return fmt.Errorf("unknown page %q, expected one of: %s", w.Page, strings.Join(WorkspacePages, ", "))
+	}
+
+	ws := readWorkspaces()
+	for i, saved := range ws.Workspaces {
+		if strings.EqualFold(saved.Name, w.Name) {
+			ws.Workspaces[i] = w
+			return writeWorkspaces(ws)
+		}
+	}
+	ws.Workspaces = append(ws.Workspaces, w)
+
+	return writeWorkspaces(ws)
+}
+
+// DeleteWorkspace removes the workspace of a name
+func DeleteWorkspace(name string) error {
+	w, err := GetWorkspace(name)
+	if err != nil {
+		return err
+	}
+
+	ws := readWorkspaces()
+	kept := []Workspace{}
+	for _, saved := range ws.Workspaces {
+		if saved.Name != w.Name {
+			kept = append(kept, saved)
+		}
+	}
+	ws.Workspaces = kept
+	if ws.Last == w.Name {
+		ws.Last = ""
+	}
+
+	return writeWorkspaces(ws)
+}
+
+// LastWorkspace returns the name of the workspace last opened, empty if none
+// was or it was deleted
+func LastWorkspace() string {
+	return readWorkspaces().Last
+}
+
+// SetLastWorkspace saves the name of the workspace last opened
+func SetLastWorkspace(name string) error {
+	ws := readWorkspaces()
+	ws.Last = name
+	return writeWorkspaces(ws)
+}
+
+// NextWorkspace returns the name of the workspace saved after the one named,
+// cycling back to the first, or an empty string if none are saved
+func NextWorkspace(name string) string {
+	ws := readWorkspaces().Workspaces
+	if len(ws) == 0 {
+		return ""
+	}
+
+	for i, w := range ws {
+		if w.Name == name {
+			return ws[(i+1)%len(ws)].Name
+		}
+	}
+	return ws[0].Name
+}
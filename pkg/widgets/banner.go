@@ -0,0 +1,69 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package widgets
+
+import (
+	"time"
+
+	ui "github.com/gizak/termui/v3"
+	tw "github.com/gizak/termui/v3/widgets"
+)
+
+// Banner flashes a message across the top of a page for a while, Eg: when
+// a price alert is triggered
+type Banner struct {
+	*tw.Paragraph
+	until time.Time
+}
+
+// NewBanner creates and returns a Banner instance
+func NewBanner() *Banner {
+	b := &Banner{
+		Paragraph: tw.NewParagraph(),
+	}
+	b.Title = " Alert "
+	b.TitleStyle = ui.NewStyle(ui.ColorClear)
+	return b
+}
+
+// Show displays text on the banner for duration d
+func (b *Banner) Show(text string, d time.Duration) {
+	b.Text = text
+	b.until = time.Now().Add(d)
+}
+
+// Active returns true while the banner is being shown
+func (b *Banner) Active() bool {
+	return time.Now().Before(b.until)
+}
+
+// Resize places the banner across the top of the terminal
+func (b *Banner) Resize(termWidth, termHeight int) {
+	b.SetRect(0, 0, termWidth, 3)
+}
+
+// Draw alternates the colour of the banner every second to flash it
+func (b *Banner) Draw(buf *ui.Buffer) {
+	color := ui.ColorYellow
+	if time.Now().Second()%2 == 1 {
+		color = ui.ColorRed
+	}
+	b.BorderStyle.Fg = color
+	b.TextStyle.Fg = color
+
+	b.Paragraph.Draw(buf)
+}
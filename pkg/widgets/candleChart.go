@@ -0,0 +1,135 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package widgets
+
+import (
+	"fmt"
+	"image"
+
+	ui "github.com/gizak/termui/v3"
+)
+
+// CandleChart implements a candlestick chart of open, high, low and close
+// prices. Each candle is drawn in a column with its wick spanning the high
+// and low, and its body spanning the open and close.
+type CandleChart struct {
+	*ui.Block
+
+	Candles []Candle
+
+	UpColor    ui.Color
+	DownColor  ui.Color
+	LabelStyle ui.Style
+
+	// Format formats prices labelled on the chart
+	Format func(float64) string
+
+	// EmptyText is shown when there are no candles
+	EmptyText string
+}
+
+// Candle holds the open, high, low and close price of a period
+type Candle struct {
+	Open  float64
+	High  float64
+	Low   float64
+	Close float64
+}
+
+// NewCandleChart creates and returns a CandleChart instance
+func NewCandleChart() *CandleChart {
+	return &CandleChart{
+		Block:      ui.NewBlock(),
+		UpColor:    ui.ColorGreen,
+		DownColor:  ui.ColorRed,
+		LabelStyle: ui.NewStyle(ui.ColorClear),
+		Format: func(val float64) string {
+			return fmt.Sprintf("%.2f", val)
+		},
+		EmptyText: "No candles",
+	}
+}
+
+func (c *CandleChart) Draw(buf *ui.Buffer) {
+	c.Block.Draw(buf)
+
+	width, height := c.Inner.Dx(), c.Inner.Dy()
+	if width < 1 || height < 1 {
+		return
+	}
+
+	if len(c.Candles) == 0 {
+		x := c.Inner.Min.X + (width-len(c.EmptyText))/2
+		y := c.Inner.Min.Y + height/2
+		buf.SetString(c.EmptyText, c.LabelStyle, image.Pt(x, y))
+		return
+	}
+
+	// Each candle takes a column followed by a gap, the latest candles are
+	// drawn against the right edge
+	candles := c.Candles
+	if count := (width + 1) / 2; len(candles) > count {
+		candles = candles[len(candles)-count:]
+	}
+
+	max, min := candles[0].High, candles[0].Low
+	for _, candle := range candles {
+		if candle.High > max {
+			max = candle.High
+		}
+		if candle.Low < min {
+			min = candle.Low
+		}
+	}
+
+	// row returns the row a price is drawn on
+	row := func(val float64) int {
+		if max == min {
+			return c.Inner.Min.Y + height/2
+		}
+		return c.Inner.Min.Y + int((max-val)/(max-min)*float64(height-1)+0.5)
+	}
+
+	for i, candle := range candles {
+		x := c.Inner.Max.X - 1 - 2*(len(candles)-1-i)
+
+		color := c.UpColor
+		if candle.Close < candle.Open {
+			color = c.DownColor
+		}
+		style := ui.NewStyle(color)
+
+		bodyTop, bodyBottom := row(candle.Open), row(candle.Close)
+		if bodyTop > bodyBottom {
+			bodyTop, bodyBottom = bodyBottom, bodyTop
+		}
+
+		for y := row(candle.High); y <= row(candle.Low); y++ {
+			char := '│'
+			if y >= bodyTop && y <= bodyBottom {
+				char = '┃'
+			}
+			buf.SetCell(ui.NewCell(char, style), image.Pt(x, y))
+		}
+	}
+
+	// Label high and low of the candles shown
+	buf.SetString(c.Format(max), c.LabelStyle, image.Pt(c.Inner.Min.X+1, c.Inner.Min.Y))
+	if height > 1 {
+		buf.SetString(c.Format(min), c.LabelStyle, image.Pt(c.Inner.Min.X+1, c.Inner.Max.Y-1))
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package widgets
+
+import (
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
+	ui "github.com/gizak/termui/v3"
+)
+
+// ChangeStyle configures how changes (Eg: "▲ 2.31") are coloured in tables
+type ChangeStyle struct {
+	Flat      float64 // Changes within ±Flat (%) are rendered neutral
+	Gradient  bool    // Scale colour intensity with the magnitude of change
+	FullScale float64 // Change (%) at which the gradient reaches full intensity
+}
+
+// changeColoring is the style used by all tables to colour changes. It is
+// guarded by coloringMutex as it is set again when the config file is
+// reloaded.
+var (
+	changeColoring = ChangeStyle{
+		Flat:      0,
+		Gradient:  false,
+		FullScale: 10,
+	}
+	coloringMutex sync.RWMutex
+)
+
+// ChangeColoring returns the style used by all tables to colour changes
+func ChangeColoring() ChangeStyle {
+	coloringMutex.RLock()
+	defer coloringMutex.RUnlock()
+	return changeColoring
+}
+
+// SetChangeColoring sets the style used by all tables to colour changes
+func SetChangeColoring(style ChangeStyle) {
+	coloringMutex.Lock()
+	changeColoring = style
+	coloringMutex.Unlock()
+}
+
+// Colours of rising and falling changes, set by the theme in use
+var (
+	UpColor   = ui.ColorGreen
+	DownColor = ui.ColorRed
+)
+
+// Shades of rising and falling changes from the 256 colour palette, dimmest
+// first, set by the theme in use
+var (
+	UpShades   = []ui.Color{22, 28, 34, 40, 46}
+	DownShades = []ui.Color{52, 88, 124, 160, 196}
+)
+
+// Color returns the colour a change formatted as "<arrow> <value>" is
+// rendered with, and the neutral colour for flat changes
+func (c ChangeStyle) Color(s string, neutral ui.Color) ui.Color {
+	change, ok := utils.ParseChange(s)
+	if !ok {
+		// Fall back to the arrow
+		if strings.HasPrefix(s, DOWN_ARROW) {
+			return DownColor
+		}
+		return UpColor
+	}
+
+	if math.Abs(change) <= c.Flat && c.Flat > 0 {
+		return neutral
+	}
+
+	shades := UpShades
+	if change < 0 {
+		shades = DownShades
+	}
+
+	if !c.Gradient || c.FullScale <= 0 {
+		if change < 0 {
+			return DownColor
+		}
+		return UpColor
+	}
+
+	// Pick shade by magnitude of change beyond the flat band
+	magnitude := (math.Abs(change) - c.Flat) / (c.FullScale - c.Flat)
+	if c.FullScale <= c.Flat {
+		magnitude = 1
+	}
+	idx := int(math.Ceil(magnitude*float64(len(shades)))) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(shades) {
+		idx = len(shades) - 1
+	}
+
+	return shades[idx]
+}
@@ -0,0 +1,75 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package widgets
+
+import (
+	"fmt"
+	"image"
+
+	ui "github.com/gizak/termui/v3"
+)
+
+// ChipStrip implements a horizontal strip of change "chips", Eg: [24H ▲ 1.23%],
+// each coloured by its change
+type ChipStrip struct {
+	*ui.Block
+
+	Chips []Chip
+}
+
+// Chip holds a change in percent over a timeframe
+type Chip struct {
+	Label  string
+	Change float64
+}
+
+// NewChipStrip creates and returns a ChipStrip instance
+func NewChipStrip() *ChipStrip {
+	return &ChipStrip{
+		Block: ui.NewBlock(),
+	}
+}
+
+func (c *ChipStrip) Draw(buf *ui.Buffer) {
+	c.Block.Draw(buf)
+
+	if c.Inner.Dy() < 1 {
+		return
+	}
+
+	x := c.Inner.Min.X + 1
+	y := c.Inner.Min.Y + (c.Inner.Dy()-1)/2
+	for _, chip := range c.Chips {
+		change := fmt.Sprintf("%s %.2f", UP_ARROW, chip.Change)
+		if chip.Change < 0 {
+			change = fmt.Sprintf("%s %.2f", DOWN_ARROW, -chip.Change)
+		}
+		label := fmt.Sprintf("[%s ", chip.Label)
+		value := fmt.Sprintf("%s%%]", change)
+		width := len([]rune(label)) + len([]rune(value))
+
+		// Stop at chips which don't fit
+		if x+width > c.Inner.Max.X {
+			break
+		}
+
+		buf.SetString(label, ui.NewStyle(ui.ColorClear), image.Pt(x, y))
+		style := ui.NewStyle(ChangeColoring().Color(change, ui.ColorClear), ui.ColorClear, ui.ModifierBold)
+		buf.SetString(value, style, image.Pt(x+len([]rune(label)), y))
+		x += width + 2
+	}
+}
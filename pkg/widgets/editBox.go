@@ -252,7 +252,7 @@ var edit_box EditBox
 
 const edit_box_width = 30
 
-func redraw_all(symbol string) {
+func redraw_all(title string) {
 	const coldef = termbox.ColorDefault
 	termbox.Clear(coldef, coldef)
 	w, h := termbox.Size()
@@ -284,7 +284,6 @@ func redraw_all(symbol string) {
 	edit_box.Draw(midx, midy, edit_box_width, 1)
 	termbox.SetCursor(midx+edit_box.CursorX(), midy)
 
-	title := fmt.Sprintf(" Enter Amount in %s ", symbol)
 	tbprint(midx, midy-1, coldef, coldef, title)
 	tbprint(midx, midy+2, coldef, coldef, "ESC to Close")
 	tbprint(midx, midy+3, coldef, coldef, "Enter to Save")
@@ -304,9 +303,22 @@ func init() {
 
 // DrawEdit draws an editbox and returns input passed to the box
 func DrawEdit(ev <-chan ui.Event, symbol string) string {
+	return drawInput(ev, fmt.Sprintf(" Enter Amount in %s ", symbol))
+}
+
+// DrawPrompt draws an empty editbox with the given title and returns input
+// passed to the box
+func DrawPrompt(ev <-chan ui.Event, title string) string {
+	edit_box = EditBox{}
+	return drawInput(ev, title)
+}
+
+// drawInput draws the editbox with a title and handles events till input is
+// saved or closed
+func drawInput(ev <-chan ui.Event, title string) string {
 	termbox.SetInputMode(termbox.InputEsc)
 
-	redraw_all(symbol)
+	redraw_all(title)
 	defer termbox.HideCursor()
 	for {
 		for e := range ev {
@@ -338,7 +350,7 @@ func DrawEdit(ev <-chan ui.Event, symbol string) string {
 					edit_box.InsertRune([]rune(e.ID)[0])
 				}
 			}
-			redraw_all(symbol)
+			redraw_all(title)
 		}
 	}
 }
@@ -22,6 +22,9 @@ import (
 
 var allKeybindings = [][]string{
 	{"Quit: q or <C-c>"},
+	{"Suspend: <C-z>"},
+	{"Reload config: <C-r>"},
+	{"Next workspace: W"},
 	{""},
 	{"Table Navigation"},
 	{"  - k and <Up>: up"},
@@ -35,6 +38,10 @@ var allKeybindings = [][]string{
 	{"  - f: focus favourites table"},
 	{"  - F: focus coin table"},
 	{""},
+	{"Mouse"},
+	{"  - Click a row to select it, and again to view the coin"},
+	{"  - Scroll the table under the pointer with the wheel"},
+	{""},
 	{"Sorting"},
 	{"  - Use column number to sort ascending."},
 	{"  - Use <F-column number> to sort descending."},
@@ -47,7 +54,16 @@ var allKeybindings = [][]string{
 	{"  - P: View portfolio"},
 	{"  - s: Star, save to favourites"},
 	{"  - S: UnStar,remove from favourites"},
+	{"  - a: Alert when favourite enters/leaves top N (0 to remove)"},
+	{"  - w: Cycle favourites table through watchlists"},
 	{"  - <Enter>: View Coin Information"},
+	{"  - /: Search all coins by symbol or name"},
+	{"  - m: Mark/unmark coin for comparison (up to 5)"},
+	{"  - M: Compare marked coins (at least 2)"},
+	{"  - R: Save report of raw responses backing the page"},
+	{"  - <C-l>: Clear cached responses and refresh"},
+	{"  - t: Cycle colour theme"},
+	{"  - L: Cycle power mode (auto, on, off)"},
 	{"  - %: Select Duration for Percentage Change"},
 	{""},
 	{"To close this prompt: <Esc>"},
@@ -55,9 +71,16 @@ var allKeybindings = [][]string{
 
 var coinKeybindings = [][]string{
 	{"Quit: q or <C-c>"},
+	{"Suspend: <C-z>"},
+	{"Reload config: <C-r>"},
 	{""},
 	{"Table Navigation"},
 	{"  - d Change Interval Duration"},
+	{"  - < and >: shorter and longer interval duration"},
+	{"  - D: custom range of dates, Eg: 2021-01-01 to 2021-06-30"},
+	{"  - + and -: zoom the value graph in and out"},
+	{"  - <Left> and <Right>: pan the zoomed value graph"},
+	{"  - l: toggle log scale of the value graph"},
 	{"  - k and <Up>: up"},
 	{"  - j and <Down>: down"},
 	{"  - <C-u>: half page up"},
@@ -69,17 +92,43 @@ var coinKeybindings = [][]string{
 	{"  - f: focus favourites table"},
 	{"  - F: focus interval table"},
 	{""},
+	{"Mouse"},
+	{"  - Click a row to select it, scroll tables with the wheel"},
+	{"  - Drag over the value graph to zoom in, right click to zoom out"},
+	{""},
 	{"Sorting"},
 	{"  - Use column number to sort ascending."},
 	{"  - Use <F-column number> to sort descending."},
 	{"  - Eg: 1 to sort ascending on 1st Col and F1 for descending"},
 	{""},
+	{"Actions"},
+	{"  - r: Cycle refresh priority (normal, high, low)"},
+	{"  - x: Price history in another coin, empty for fiat"},
+	{"  - H: Compare history with the same period a year ago"},
+	{"  - y: Copy text summary of coin to clipboard"},
+	{"  - Y: Write quick stats of coin as JSON, to --stats or the clipboard"},
+	{"  - E: Export details, favourites and history to a file"},
+	{"  - s: Cycle data source (applied on reopen)"},
+	{"  - a: Set price alert (>price, <price, %change or ~move/window), empty to clear"},
+	{"  - o: Toggle candlestick chart"},
+	{"  - O: Cycle candle timeframe (1m, 15m, 1h, 1d)"},
+	{"  - b: Toggle order book"},
+	{"  - m: Toggle markets, sorted with column numbers"},
+	{"  - v: Toggle volume by exchange, flagging low trust exchanges"},
+	{"  - n: Toggle news in place of details, <Enter> copies a link"},
+	{"  - R: Save report of raw responses backing the page"},
+	{"  - <C-l>: Clear cached responses and refresh"},
+	{"  - t: Cycle colour theme"},
+	{"  - L: Cycle power mode (auto, on, off)"},
 	{""},
 	{"To close this prompt: <Esc>"},
 }
 
 var portfolioKeybindings = [][]string{
 	{"Quit: q or <C-c>"},
+	{"Suspend: <C-z>"},
+	{"Reload config: <C-r>"},
+	{"Next workspace: W"},
 	{""},
 	{"Table Navigation"},
 	{"  - k and <Up>: up"},
@@ -101,6 +150,8 @@ var portfolioKeybindings = [][]string{
 	{"  - C: Select Currency (from full list)"},
 	{"  - e: Add/Edit coin to Portfolio"},
 	{"  - <Enter>: View Coin Information"},
+	{"  - <Tab>: Switch between holdings and risk tabs"},
+	{"  - < and >: shorter and longer interval of the value graph"},
 	{""},
 	{"To close this prompt: <Esc>"},
 }
@@ -156,6 +207,157 @@ func (help *HelpMenu) Draw(buf *ui.Buffer) {
 	help.Table.Draw(buf)
 }
 
+var yieldsKeybindings = [][]string{
+	{"Quit: q or <C-c>"},
+	{"Suspend: <C-z>"},
+	{"Reload config: <C-r>"},
+	{""},
+	{"Table Navigation"},
+	{"  - k and <Up>: up"},
+	{"  - j and <Down>: down"},
+	{"  - <C-u>: half page up"},
+	{"  - <C-d>: half page down"},
+	{"  - <C-b>: full page up"},
+	{"  - <C-f>: full page down"},
+	{"  - gg and <Home>: jump to top"},
+	{"  - G and <End>: jump to bottom"},
+	{""},
+	{"Sorting"},
+	{"  - Use column number to sort ascending."},
+	{"  - Use <F-column number> to sort descending."},
+	{"  - Eg: 1 to sort ascending on 1st Col and F1 for descending"},
+	{""},
+	{"To close this prompt: <Esc>"},
+}
+
+var screenerKeybindings = [][]string{
+	{"Quit: q or <C-c>"},
+	{"Suspend: <C-z>"},
+	{"Reload config: <C-r>"},
+	{""},
+	{"Table Navigation"},
+	{"  - k and <Up>: up"},
+	{"  - j and <Down>: down"},
+	{"  - <C-u>: half page up"},
+	{"  - <C-d>: half page down"},
+	{"  - <C-b>: full page up"},
+	{"  - <C-f>: full page down"},
+	{"  - gg and <Home>: jump to top"},
+	{"  - G and <End>: jump to bottom"},
+	{""},
+	{"Sorting"},
+	{"  - Use column number to sort ascending."},
+	{"  - Use <F-column number> to sort descending."},
+	{"  - Eg: 1 to sort ascending on 1st Col and F1 for descending"},
+	{""},
+	{"Actions"},
+	{"  - e: Edit criteria, Eg: mcap>100m change<-5 volmcap>0.1 ath<-70"},
+	{"  - <Tab>: Cycle saved queries"},
+	{""},
+	{"To close this prompt: <Esc>"},
+}
+
+var exchangesKeybindings = [][]string{
+	{"Quit: q or <C-c>"},
+	{"Suspend: <C-z>"},
+	{"Reload config: <C-r>"},
+	{"Next workspace: W"},
+	{""},
+	{"Table Navigation"},
+	{"  - k and <Up>: up"},
+	{"  - j and <Down>: down"},
+	{"  - <C-u>: half page up"},
+	{"  - <C-d>: half page down"},
+	{"  - <C-b>: full page up"},
+	{"  - <C-f>: full page down"},
+	{"  - gg and <Home>: jump to top"},
+	{"  - G and <End>: jump to bottom"},
+	{"  - <Enter>: list markets of the exchange"},
+	{"  - <Escape>: back to exchanges"},
+	{""},
+	{"Sorting"},
+	{"  - Use column number to sort ascending."},
+	{"  - Use <F-column number> to sort descending."},
+	{"  - Eg: 1 to sort ascending on 1st Col and F1 for descending"},
+	{""},
+	{"To close this prompt: <Esc>"},
+}
+
+var activityKeybindings = [][]string{
+	{"Quit: q or <C-c>"},
+	{"Suspend: <C-z>"},
+	{"Reload config: <C-r>"},
+	{""},
+	{"Table Navigation"},
+	{"  - k and <Up>: up"},
+	{"  - j and <Down>: down"},
+	{"  - <C-u>: half page up"},
+	{"  - <C-d>: half page down"},
+	{"  - <C-b>: full page up"},
+	{"  - <C-f>: full page down"},
+	{"  - gg and <Home>: jump to top"},
+	{"  - G and <End>: jump to bottom"},
+	{"  - <Tab>: switch between open orders and fills"},
+	{""},
+	{"Actions"},
+	{"  - i: Add fills to the ledger, once confirmed by typing yes"},
+	{""},
+	{"To close this prompt: <Esc>"},
+}
+
+var ordersKeybindings = [][]string{
+	{"Quit: q or <C-c>"},
+	{"Suspend: <C-z>"},
+	{"Reload config: <C-r>"},
+	{""},
+	{"Table Navigation"},
+	{"  - k and <Up>: up"},
+	{"  - j and <Down>: down"},
+	{"  - <C-u>: half page up"},
+	{"  - <C-d>: half page down"},
+	{"  - <C-b>: full page up"},
+	{"  - <C-f>: full page down"},
+	{"  - gg and <Home>: jump to top"},
+	{"  - G and <End>: jump to bottom"},
+	{""},
+	{"Actions (each confirmed by typing yes)"},
+	{"  - b: Place a buy order, Eg: BTCUSDT 0.01 30000"},
+	{"  - s: Place a sell order, leave out the price for a market order"},
+	{"  - c: Cancel the selected order"},
+	{""},
+	{"To close this prompt: <Esc>"},
+}
+
+var compareKeybindings = [][]string{
+	{"Quit: q or <C-c>"},
+	{"Suspend: <C-z>"},
+	{"Reload config: <C-r>"},
+	{""},
+	{"Table Navigation"},
+	{"  - < and >: shorter and longer interval duration"},
+	{"  - k and <Up>: up"},
+	{"  - j and <Down>: down"},
+	{"  - <C-u>: half page up"},
+	{"  - <C-d>: half page down"},
+	{"  - <C-b>: full page up"},
+	{"  - <C-f>: full page down"},
+	{"  - gg and <Home>: jump to top"},
+	{"  - G and <End>: jump to bottom"},
+	{""},
+	{"To close this prompt: <Esc>"},
+}
+
+var ratioKeybindings = [][]string{
+	{"Quit: q or <C-c>"},
+	{"Suspend: <C-z>"},
+	{"Reload config: <C-r>"},
+	{""},
+	{"Graph"},
+	{"  - < and >: shorter and longer interval duration"},
+	{""},
+	{"To close this prompt: <Esc>"},
+}
+
 // SelectHelpMenu selects the appropriate text
 // based on the command for which the help page
 // is needed
@@ -168,5 +370,19 @@ func (help *HelpMenu) SelectHelpMenu(page string) {
 		help.Keybindings = coinKeybindings
 	case "PORTFOLIO":
 		help.Keybindings = portfolioKeybindings
+	case "YIELDS", "HOLDINGS":
+		help.Keybindings = yieldsKeybindings
+	case "EXCHANGES":
+		help.Keybindings = exchangesKeybindings
+	case "SCREENER":
+		help.Keybindings = screenerKeybindings
+	case "ORDERS":
+		help.Keybindings = ordersKeybindings
+	case "ACTIVITY":
+		help.Keybindings = activityKeybindings
+	case "COMPARE":
+		help.Keybindings = compareKeybindings
+	case "RATIO":
+		help.Keybindings = ratioKeybindings
 	}
 }
@@ -36,6 +36,25 @@ type LineGraph struct {
 
 	LineColors       map[string]ui.Color
 	DefaultLineColor ui.Color
+
+	// Markers are drawn as vertical lines at points of MarkerSeries
+	Markers      []Marker
+	MarkerSeries string
+
+	// XLabels label points of MarkerSeries by index under the graph, on
+	// the bottom border if it has one, Eg: with dates of history
+	XLabels map[int]string
+
+	// Selection marks the columns of the terminal a range is being
+	// selected between, Eg: by dragging the mouse. It is drawn if set.
+	Selection      []int
+	SelectionColor ui.Color
+}
+
+// Marker marks the point at Index of a series with a vertical line
+type Marker struct {
+	Index int
+	Color ui.Color
 }
 
 // NewLineGraph creates and returns a lineGraph instance
@@ -49,9 +68,33 @@ func NewLineGraph() *LineGraph {
 		HorizontalScale: 5,
 
 		LineColors: make(map[string]ui.Color),
+
+		SelectionColor: ui.ColorCyan,
 	}
 }
 
+// IndexAt returns the index of the point of MarkerSeries drawn at column x
+// of the terminal, the nearest one if none is drawn there, or -1 if the
+// series is empty
+func (l *LineGraph) IndexAt(x int) int {
+	data := l.Data[l.MarkerSeries]
+	if len(data) == 0 {
+		return -1
+	}
+
+	// Inverse of the column points are drawn at, each column has 2 dots
+	dot := (x - l.Inner.Min.X + 1) * 2
+	index := len(data) - 1 - (((l.Inner.Dx()+1)*2)-1-dot)/l.HorizontalScale
+	if index < 0 {
+		index = 0
+	}
+	if index > len(data)-1 {
+		index = len(data) - 1
+	}
+
+	return index
+}
+
 func (l *LineGraph) Draw(buf *ui.Buffer) {
 	l.Block.Draw(buf)
 	// we render each data point on to the canvas then copy over the braille to the buffer at the end
@@ -78,6 +121,39 @@ func (l *LineGraph) Draw(buf *ui.Buffer) {
 	}
 	sort.Strings(seriesList)
 
+	// markerCol returns the column of the point at index of MarkerSeries
+	markerCol := func(index int) int {
+		markerData := l.Data[l.MarkerSeries]
+		x := ((l.Inner.Dx() + 1) * 2) - 1 - (((len(markerData) - 1) - index) * l.HorizontalScale)
+		return l.Inner.Min.X + x/2 - 1
+	}
+
+	// draw markers first so that lines are drawn over them
+	if markerData, ok := l.Data[l.MarkerSeries]; ok {
+		for _, marker := range l.Markers {
+			if marker.Index < 0 || marker.Index >= len(markerData) {
+				continue
+			}
+			col := markerCol(marker.Index)
+			if col < l.Inner.Min.X || col >= l.Inner.Max.X {
+				continue
+			}
+			for y := l.Inner.Min.Y; y < l.Inner.Max.Y; y++ {
+				buf.SetCell(ui.NewCell('┊', ui.NewStyle(marker.Color)), image.Pt(col, y))
+			}
+		}
+	}
+
+	// draw the edges of a range being selected, under the lines
+	for _, col := range l.Selection {
+		if col < l.Inner.Min.X || col >= l.Inner.Max.X {
+			continue
+		}
+		for y := l.Inner.Min.Y; y < l.Inner.Max.Y; y++ {
+			buf.SetCell(ui.NewCell('│', ui.NewStyle(l.SelectionColor)), image.Pt(col, y))
+		}
+	}
+
 	// draw lines in reverse order so that the first color defined in the colorscheme is on top
 	for i := len(seriesList) - 1; i >= 0; i-- {
 		seriesName := seriesList[i]
@@ -134,6 +210,42 @@ func (l *LineGraph) Draw(buf *ui.Buffer) {
 		}
 	}
 
+	// renders x axis labels centred under their points, left to right,
+	// skipping those which would overlap the previous one
+	if markerData, ok := l.Data[l.MarkerSeries]; ok {
+		indices := []int{}
+		for index := range l.XLabels {
+			if index >= 0 && index < len(markerData) {
+				indices = append(indices, index)
+			}
+		}
+		sort.Ints(indices)
+
+		y := l.Inner.Max.Y - 1
+		if l.Border {
+			y = l.Max.Y - 1
+		}
+
+		next := l.Inner.Min.X
+		for _, index := range indices {
+			label := []rune(l.XLabels[index])
+			col := markerCol(index) - len(label)/2
+			if col+len(label) > l.Inner.Max.X {
+				col = l.Inner.Max.X - len(label)
+			}
+			if col < l.Inner.Min.X {
+				col = l.Inner.Min.X
+			}
+			if col < next {
+				continue
+			}
+			for k, char := range label {
+				buf.SetCell(ui.NewCell(char, l.TitleStyle), image.Pt(col+k, y))
+			}
+			next = col + len(label) + 1
+		}
+	}
+
 	// renders key/label ontop
 	for i, seriesName := range seriesList {
 		if i+2 > l.Inner.Dy() {
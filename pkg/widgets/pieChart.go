@@ -0,0 +1,141 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package widgets
+
+import (
+	"fmt"
+	"image"
+	"math"
+
+	ui "github.com/gizak/termui/v3"
+	rw "github.com/mattn/go-runewidth"
+)
+
+// PieChart draws shares of a whole as slices of a donut, with a legend of
+// labels and percentages beside it
+type PieChart struct {
+	*ui.Block
+	Data        []float64
+	Labels      []string
+	Colors      []ui.Color
+	CenterLabel string  // Drawn in the hole of the donut, if it fits
+	InnerRadius float64 // Radius of the hole, as a fraction of the radius
+}
+
+// NewPieChart returns a donut chart coloured like termui's pie charts
+func NewPieChart() *PieChart {
+	return &PieChart{
+		Block:       ui.NewBlock(),
+		Colors:      ui.Theme.PieChart.Slices,
+		InnerRadius: 0.5,
+	}
+}
+
+// legend returns lines of the legend, as a label and percentage per slice
+func (p *PieChart) legend(total float64) []string {
+	width := 0
+	for _, label := range p.Labels {
+		width = ui.MaxInt(width, rw.StringWidth(label))
+	}
+
+	lines := make([]string, len(p.Data))
+	for i, val := range p.Data {
+		label := ""
+		if i < len(p.Labels) {
+			label = p.Labels[i]
+		}
+		lines[i] = fmt.Sprintf("%s %6.2f%%", rw.FillRight(label, width), val/total*100)
+	}
+	return lines
+}
+
+// Draw draws the donut on the left of the block and the legend on its right
+func (p *PieChart) Draw(buf *ui.Buffer) {
+	p.Block.Draw(buf)
+
+	total := 0.0
+	for _, val := range p.Data {
+		total += val
+	}
+	if total <= 0 {
+		return
+	}
+
+	lines := p.legend(total)
+	legendWidth := 0
+	for _, line := range lines {
+		legendWidth = ui.MaxInt(legendWidth, rw.StringWidth(line)+2)
+	}
+
+	// Cells are about twice as tall as they are wide, so the donut is twice
+	// as wide as it is tall to look round
+	ry := float64(p.Inner.Dy()) / 2
+	rx := math.Min(2*ry, float64(p.Inner.Dx()-legendWidth-1)/2)
+	ry = rx / 2
+	pieWidth := 0
+	if ry >= 1 {
+		pieWidth = int(2*rx) + 1
+		cx := float64(p.Inner.Min.X) + rx
+		cy := float64(p.Inner.Min.Y) + float64(p.Inner.Dy())/2
+
+		for y := p.Inner.Min.Y; y < p.Inner.Max.Y; y++ {
+			for x := p.Inner.Min.X; x < p.Inner.Min.X+pieWidth-1; x++ {
+				dx := (float64(x) + 0.5 - cx) / rx
+				dy := (float64(y) + 0.5 - cy) / ry
+				dist := math.Hypot(dx, dy)
+				if dist > 1 || dist < p.InnerRadius {
+					continue
+				}
+
+				// Slices go clockwise from the top
+				angle := math.Atan2(dx, -dy)
+				if angle < 0 {
+					angle += 2 * math.Pi
+				}
+				share := angle / (2 * math.Pi) * total
+
+				i, sum := 0, p.Data[0]
+				for sum < share && i < len(p.Data)-1 {
+					i++
+					sum += p.Data[i]
+				}
+
+				c := ui.NewCell(' ', ui.NewStyle(ui.ColorClear, ui.SelectColor(p.Colors, i)))
+				buf.SetCell(c, image.Pt(x, y))
+			}
+		}
+
+		if width := rw.StringWidth(p.CenterLabel); width > 0 && float64(width) <= 2*rx*p.InnerRadius {
+			buf.SetString(p.CenterLabel, ui.NewStyle(ui.ColorClear), image.Pt(int(cx)-width/2, int(cy)))
+		}
+	}
+
+	// Legend, centred vertically beside the donut
+	x := p.Inner.Min.X + pieWidth
+	y := p.Inner.Min.Y + ui.MaxInt(0, (p.Inner.Dy()-len(lines))/2)
+	for i, line := range lines {
+		if y+i >= p.Inner.Max.Y {
+			break
+		}
+		buf.SetCell(ui.NewCell('■', ui.NewStyle(ui.SelectColor(p.Colors, i))), image.Pt(x, y+i))
+		buf.SetString(
+			rw.Truncate(line, p.Inner.Max.X-x-2, "…"),
+			ui.NewStyle(ui.ColorClear),
+			image.Pt(x+2, y+i),
+		)
+	}
+}
@@ -36,6 +36,7 @@ type Table struct {
 
 	Header []string
 	Rows   [][]string
+	Footer string // optional line rendered below the rows
 
 	// Different Styles for Header and Rows
 	HeaderStyle ui.Style
@@ -116,8 +117,17 @@ func (t *Table) Draw(buf *ui.Buffer) {
 		log.Printf("table widget TopRow value less than 0. TopRow: %v", t.TopRow)
 		return
 	}
+	// prints footer
+	if t.Footer != "" {
+		buf.SetString(
+			ui.TrimString(t.Footer, t.Inner.Dx()-t.PadLeft),
+			t.HeaderStyle,
+			image.Pt(t.Inner.Min.X+t.PadLeft, t.Inner.Max.Y-1),
+		)
+	}
+
 	// prints each row
-	for rowNum := t.TopRow; rowNum < t.TopRow+t.Inner.Dy()-1 && rowNum < len(t.Rows); rowNum++ {
+	for rowNum := t.TopRow; rowNum < t.TopRow+t.rowCapacity() && rowNum < len(t.Rows); rowNum++ {
 		row := t.Rows[rowNum]
 		y := (rowNum + 2) - t.TopRow
 		// prints cursor
@@ -156,11 +166,7 @@ func (t *Table) Draw(buf *ui.Buffer) {
 				if rowNum == t.SelectedRow && t.ShowCursor {
 					style.Fg = t.CursorColor
 				} else {
-					rowData := strings.Split(t.Rows[rowNum][i], " ")
-					style.Fg = ui.ColorGreen
-					if string(rowData[0]) == DOWN_ARROW {
-						style.Fg = ui.ColorRed
-					}
+					style.Fg = ChangeColoring().Color(t.Rows[rowNum][i], tempFgColor)
 				}
 			} else if val, ok := t.ColColor[i]; ok {
 				if rowNum == t.SelectedRow && t.ShowCursor {
@@ -189,7 +195,7 @@ func (t *Table) Draw(buf *ui.Buffer) {
 func (t *Table) drawLocation(buf *ui.Buffer) {
 	total := len(t.Rows)
 	topRow := t.TopRow + 1
-	bottomRow := t.TopRow + t.Inner.Dy() - 1
+	bottomRow := t.TopRow + t.rowCapacity()
 	if bottomRow > total {
 		bottomRow = total
 	}
@@ -200,6 +206,15 @@ func (t *Table) drawLocation(buf *ui.Buffer) {
 	buf.SetString(loc, t.TitleStyle, image.Pt(t.Max.X-width-2, t.Min.Y))
 }
 
+// rowCapacity returns the number of rows which fit below the header (and
+// above the footer, if set)
+func (t *Table) rowCapacity() int {
+	if t.Footer != "" {
+		return t.Inner.Dy() - 2
+	}
+	return t.Inner.Dy() - 1
+}
+
 // Scrolling ///////////////////////////////////////////////////////////////////
 
 // calcPos is used to calculate the cursor position and the current view into the table.
@@ -216,8 +231,8 @@ func (t *Table) calcPos() {
 	if t.SelectedRow > len(t.Rows)-1 {
 		t.SelectedRow = len(t.Rows) - 1
 	}
-	if t.SelectedRow > t.TopRow+(t.Inner.Dy()-2) {
-		t.TopRow = t.SelectedRow - (t.Inner.Dy() - 2)
+	if t.SelectedRow > t.TopRow+(t.rowCapacity()-1) {
+		t.TopRow = t.SelectedRow - (t.rowCapacity() - 1)
 	}
 }
 
@@ -261,11 +276,30 @@ func (t *Table) ScrollPageDown() {
 	t.calcPos()
 }
 
+// RowAt returns the index of the row drawn at x, y of the terminal, or -1
+// if no row is drawn there, Eg: over the header
+func (t *Table) RowAt(x, y int) int {
+	if !image.Pt(x, y).In(t.Inner) {
+		return -1
+	}
+
+	// The header takes the first line
+	line := y - t.Inner.Min.Y - 1
+	if line < 0 || line >= t.rowCapacity() {
+		return -1
+	}
+
+	row := t.TopRow + line
+	if row >= len(t.Rows) {
+		return -1
+	}
+	return row
+}
+
+// HandleClick moves the cursor to the row clicked at x, y of the terminal
 func (t *Table) HandleClick(x, y int) {
-	x = x - t.Min.X
-	y = y - t.Min.Y
-	if (x > 0 && x <= t.Inner.Dx()) && (y > 0 && y <= t.Inner.Dy()) {
-		t.SelectedRow = (t.TopRow + y) - 2
+	if row := t.RowAt(x, y); row != -1 {
+		t.SelectedRow = row
 		t.calcPos()
 	}
 }